@@ -0,0 +1,47 @@
+package main
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"flag"
+	"fmt"
+	"log"
+)
+
+// 根据 task_change_logs 的状态变更历史回填已有任务的实际开始/完成时间，并重新计算进度
+//
+// 用法：
+//
+//	go run ./cmd/backfill_actual_dates            # 只填充为空的字段
+//	go run ./cmd/backfill_actual_dates -dry-run   # 只统计，不写入
+//	go run ./cmd/backfill_actual_dates -overwrite # 按历史记录覆盖已有值
+func main() {
+	overwrite := flag.Bool("overwrite", false, "按历史记录覆盖已有的实际开始/完成时间")
+	dryRun := flag.Bool("dry-run", false, "只统计需要更新的任务，不写入数据库")
+	flag.Parse()
+
+	// 初始化日志
+	utils.InitLogger()
+
+	// 加载配置并初始化数据库连接
+	fmt.Println("Loading config...")
+	cfg := config.LoadConfig()
+	fmt.Println("Initializing database connection...")
+	database.InitPostgres(cfg)
+
+	fmt.Println("Backfilling actual start/end dates from task_change_logs...")
+	result, err := (&services.TaskService{}).BackfillActualDates(*overwrite, *dryRun)
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+
+	fmt.Printf("Scanned: %d, updated: %d, start filled: %d, end filled: %d, unresolved: %d\n",
+		result.Scanned, result.Updated, result.StartFilled, result.EndFilled, result.Unresolved)
+	if *dryRun {
+		fmt.Println("Dry run, no changes written.")
+		return
+	}
+	fmt.Println("Backfill completed successfully!")
+}
@@ -4,16 +4,133 @@ import (
 	"RHPRo-Task/utils"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Task     TaskConfig
-	Wechat   WechatConfig
-	User     UserConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	Task      TaskConfig
+	Wechat    WechatConfig
+	User      UserConfig
+	Search    SearchConfig
+	Scheduler SchedulerConfig
+	Security  SecurityConfig
+	LDAP      LDAPConfig
+	OIDC      OIDCConfig
+}
+
+// OIDCConfig OpenID Connect 单点登录配置（可同时接入多个身份提供方）
+type OIDCConfig struct {
+	// 已启用的身份提供方
+	Providers []OIDCProviderConfig
+}
+
+// OIDCProviderConfig 单个 OpenID Connect 身份提供方配置
+// 环境变量 OIDC_PROVIDERS 列出提供方标识（如 corp,google），每个提供方的配置以 OIDC_<标识大写>_ 为前缀
+type OIDCProviderConfig struct {
+	// 提供方标识（出现在登录接口路径中）
+	Name string
+	// 登录页显示的名称
+	DisplayName string
+	// Issuer 地址，通过 /.well-known/openid-configuration 自动发现授权、令牌和 JWKS 地址
+	IssuerURL string
+	// 客户端ID
+	ClientID string
+	// 客户端密钥（公共客户端只使用 PKCE 时可为空）
+	ClientSecret string
+	// 回调地址（前端页面，接收授权码后调用回调接口完成登录），须在身份提供方登记
+	RedirectURL string
+	// 申请的 scope（openid 自动加入）
+	Scopes []string
+	// 手机号 claim
+	MobileClaim string
+	// 邮箱 claim
+	EmailClaim string
+	// 姓名 claim
+	NameClaim string
+	// 部门 claim（值为部门名称，为空表示不映射部门）
+	DepartmentClaim string
+	// 是否信任未验证的邮箱和手机号（email_verified / phone_number_verified 不为 true 时也用于关联账号）
+	TrustUnverifiedContacts bool
+}
+
+// LDAPConfig LDAP / Active Directory 登录与用户同步配置
+type LDAPConfig struct {
+	// 是否启用 LDAP 登录和同步
+	Enabled bool
+	// 服务地址（ldap://host:389 或 ldaps://host:636）
+	URL string
+	// 是否在 ldap:// 连接上使用 StartTLS
+	StartTLS bool
+	// 是否跳过服务端证书校验（仅用于测试环境）
+	InsecureSkipVerify bool
+	// 查询用的服务账号 DN（为空表示匿名查询）
+	BindDN string
+	// 服务账号密码
+	BindPassword string
+	// 用户查询的根 DN，用户所在 OU 相对根 DN 的层级映射为部门层级
+	BaseDN string
+	// 用户过滤条件
+	UserFilter string
+	// 登录名属性（OpenLDAP 一般为 uid，AD 为 sAMAccountName）
+	LoginAttr string
+	// 唯一标识属性（OpenLDAP 为 entryUUID，AD 为 objectGUID），用户移动 OU 后仍能对应到同一账号
+	UIDAttr string
+	// 姓名属性
+	NameAttr string
+	// 手机号属性（手机号为系统登录账号，目录中没有手机号的用户不同步）
+	MobileAttr string
+	// 邮箱属性
+	EmailAttr string
+	// 职位属性
+	TitleAttr string
+	// 上级属性（值为上级的 DN），用于确定部门负责人
+	ManagerAttr string
+	// 连接和查询超时（秒）
+	TimeoutSeconds int
+	// 同步间隔（分钟），0 表示不定时同步
+	SyncIntervalMinutes int
+}
+
+// SecurityConfig 登录防护与接口限流配置（次数配置为 0 表示不限制）
+type SecurityConfig struct {
+	// 同一账号在失败计数窗口内连续登录失败达到该次数后锁定
+	LoginMaxFailures int
+	// 同一IP在失败计数窗口内登录失败达到该次数后锁定
+	LoginIPMaxFailures int
+	// 登录失败计数窗口（分钟）
+	LoginFailureWindowMinutes int
+	// 首次锁定时长（秒），之后每多失败一次时长翻倍
+	LoginLockoutSeconds int
+	// 最长锁定时长（分钟）
+	LoginMaxLockoutMinutes int
+	// 注册接口每个IP每小时允许的请求次数
+	RegisterRateLimitPerHour int
+	// 微信登录接口每个IP每分钟允许的请求次数
+	WechatLoginRateLimitPerMinute int
+	// 上传接口每个用户每分钟允许的请求次数
+	UploadRateLimitPerMinute int
+	// 两步验证在验证器 App 中显示的签发方名称
+	TwoFactorIssuer string
+	// 拥有该权限的用户必须启用两步验证（为空表示不强制）
+	TwoFactorRequiredPermission string
+}
+
+// SchedulerConfig 定时任务配置
+type SchedulerConfig struct {
+	// 是否按计划执行定时任务（关闭后仍可由管理员手动触发）
+	Enabled bool
+	// 执行记录保留天数
+	JobRunRetentionDays int
+}
+
+// SearchConfig 全文检索配置
+type SearchConfig struct {
+	// PostgreSQL 全文检索配置名（如 zhparser 中文分词配置 chinese），数据库不存在该配置时自动降级为三元组模糊匹配
+	TSConfig string
 }
 
 // UserConfig 用户相关配置
@@ -38,6 +155,8 @@ type WechatConfig struct {
 type ServerConfig struct {
 	Port int
 	Mode string
+	// 受信任的反向代理（IP 或 CIDR），只有来自这些地址的请求才采用 X-Forwarded-For 等请求头中的客户端 IP；为空表示不信任任何代理
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -59,12 +178,34 @@ type RedisConfig struct {
 type JWTConfig struct {
 	Secret     string
 	ExpireTime int // 小时
+	// 访问令牌有效期（分钟）
+	AccessExpireMinutes int
+	// 刷新令牌有效期（小时），每次刷新轮换新的刷新令牌
+	RefreshExpireHours int
 }
 
 // TaskConfig 任务相关配置
 type TaskConfig struct {
 	// 执行计划提交倒计时（小时），目标方案审核通过后，执行人需在此时间内提交执行计划
 	ExecutionPlanDeadlineHours int
+	// 截止前提醒时间（小时），到期前该时间内提醒执行人，0表示不提醒
+	DeadlineReminderHours int
+	// 截止检查间隔（分钟），0表示不检查
+	DeadlineCheckIntervalMinutes int
+	// 思路方案超期后自动转换到的状态编码（为空表示不自动转换）
+	SolutionOverdueStatusCode string
+	// 执行计划超期后自动转换到的状态编码（为空表示不自动转换）
+	PlanOverdueStatusCode string
+	// SLA 违约检查间隔（分钟），0表示不检查
+	SLACheckIntervalMinutes int
+	// 陪审团响应时限（小时），发起陪审团/投票审核未指定截止时间时使用，0表示不设截止
+	JuryResponseHours int
+	// 陪审团响应截止前提醒时间（小时），0表示不提醒
+	JuryReminderHours int
+	// 陪审团响应截止后的默认处理策略：abstain/escalate/creator_finalize
+	JuryDeadlinePolicy string
+	// 陪审团响应截止检查间隔（分钟），0表示不检查
+	JuryDeadlineCheckIntervalMinutes int
 }
 
 var globalConfig *Config
@@ -86,8 +227,9 @@ func LoadConfig() *Config {
 func loadConfigFromEnv() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnvAsInt("SERVER_PORT", 8989),
-			Mode: getEnv("GIN_MODE", "debug"),
+			Port:           getEnvAsInt("SERVER_PORT", 8989),
+			Mode:           getEnv("GIN_MODE", "debug"),
+			TrustedProxies: splitEnvList(getEnv("SERVER_TRUSTED_PROXIES", "")),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -104,11 +246,22 @@ func loadConfigFromEnv() *Config {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-			ExpireTime: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+			Secret:              getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+			ExpireTime:          getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+			AccessExpireMinutes: getEnvAsInt("JWT_ACCESS_EXPIRE_MINUTES", 30),
+			RefreshExpireHours:  getEnvAsInt("JWT_REFRESH_EXPIRE_HOURS", 168),
 		},
 		Task: TaskConfig{
-			ExecutionPlanDeadlineHours: getEnvAsInt("EXECUTION_PLAN_DEADLINE_HOURS", 72),
+			ExecutionPlanDeadlineHours:       getEnvAsInt("EXECUTION_PLAN_DEADLINE_HOURS", 72),
+			DeadlineReminderHours:            getEnvAsInt("DEADLINE_REMINDER_HOURS", 24),
+			DeadlineCheckIntervalMinutes:     getEnvAsInt("DEADLINE_CHECK_INTERVAL_MINUTES", 10),
+			SolutionOverdueStatusCode:        getEnv("SOLUTION_OVERDUE_STATUS", ""),
+			PlanOverdueStatusCode:            getEnv("PLAN_OVERDUE_STATUS", ""),
+			SLACheckIntervalMinutes:          getEnvAsInt("SLA_CHECK_INTERVAL_MINUTES", 10),
+			JuryResponseHours:                getEnvAsInt("JURY_RESPONSE_HOURS", 72),
+			JuryReminderHours:                getEnvAsInt("JURY_REMINDER_HOURS", 24),
+			JuryDeadlinePolicy:               getEnv("JURY_DEADLINE_POLICY", "creator_finalize"),
+			JuryDeadlineCheckIntervalMinutes: getEnvAsInt("JURY_DEADLINE_CHECK_INTERVAL_MINUTES", 10),
 		},
 		Wechat: WechatConfig{
 			OpenAppID:     getEnv("WECHAT_OPEN_APPID", ""),
@@ -121,7 +274,91 @@ func loadConfigFromEnv() *Config {
 		User: UserConfig{
 			DefaultPassword: getEnv("USER_DEFAULT_PASSWORD", "password123"),
 		},
+		Search: SearchConfig{
+			TSConfig: getEnv("SEARCH_TS_CONFIG", "chinese"),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:             getEnv("SCHEDULER_ENABLED", "true") == "true",
+			JobRunRetentionDays: getEnvAsInt("JOB_RUN_RETENTION_DAYS", 30),
+		},
+		Security: SecurityConfig{
+			LoginMaxFailures:              getEnvAsInt("LOGIN_MAX_FAILURES", 5),
+			LoginIPMaxFailures:            getEnvAsInt("LOGIN_IP_MAX_FAILURES", 20),
+			LoginFailureWindowMinutes:     getEnvAsInt("LOGIN_FAILURE_WINDOW_MINUTES", 15),
+			LoginLockoutSeconds:           getEnvAsInt("LOGIN_LOCKOUT_SECONDS", 60),
+			LoginMaxLockoutMinutes:        getEnvAsInt("LOGIN_MAX_LOCKOUT_MINUTES", 60),
+			RegisterRateLimitPerHour:      getEnvAsInt("REGISTER_RATE_LIMIT_PER_HOUR", 10),
+			WechatLoginRateLimitPerMinute: getEnvAsInt("WECHAT_LOGIN_RATE_LIMIT_PER_MINUTE", 20),
+			UploadRateLimitPerMinute:      getEnvAsInt("UPLOAD_RATE_LIMIT_PER_MINUTE", 30),
+			TwoFactorIssuer:               getEnv("TWO_FACTOR_ISSUER", "RHPRo-Task"),
+			TwoFactorRequiredPermission:   getEnv("TWO_FACTOR_REQUIRED_PERMISSION", "permission:manage"),
+		},
+		LDAP: LDAPConfig{
+			Enabled:             getEnv("LDAP_ENABLED", "false") == "true",
+			URL:                 getEnv("LDAP_URL", "ldap://localhost:389"),
+			StartTLS:            getEnv("LDAP_START_TLS", "false") == "true",
+			InsecureSkipVerify:  getEnv("LDAP_INSECURE_SKIP_VERIFY", "false") == "true",
+			BindDN:              getEnv("LDAP_BIND_DN", ""),
+			BindPassword:        getEnv("LDAP_BIND_PASSWORD", ""),
+			BaseDN:              getEnv("LDAP_BASE_DN", "dc=example,dc=com"),
+			UserFilter:          getEnv("LDAP_USER_FILTER", "(objectClass=inetOrgPerson)"),
+			LoginAttr:           getEnv("LDAP_LOGIN_ATTR", "uid"),
+			UIDAttr:             getEnv("LDAP_UID_ATTR", "entryUUID"),
+			NameAttr:            getEnv("LDAP_NAME_ATTR", "cn"),
+			MobileAttr:          getEnv("LDAP_MOBILE_ATTR", "mobile"),
+			EmailAttr:           getEnv("LDAP_EMAIL_ATTR", "mail"),
+			TitleAttr:           getEnv("LDAP_TITLE_ATTR", "title"),
+			ManagerAttr:         getEnv("LDAP_MANAGER_ATTR", "manager"),
+			TimeoutSeconds:      getEnvAsInt("LDAP_TIMEOUT_SECONDS", 10),
+			SyncIntervalMinutes: getEnvAsInt("LDAP_SYNC_INTERVAL_MINUTES", 60),
+		},
+		OIDC: OIDCConfig{
+			Providers: loadOIDCProviders(getEnv("OIDC_PROVIDERS", "")),
+		},
+	}
+}
+
+// loadOIDCProviders 按 OIDC_PROVIDERS 列出的标识读取各身份提供方配置（缺少 Issuer 或客户端ID的跳过）
+func loadOIDCProviders(names string) []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OIDCProviderConfig{
+			Name:                    name,
+			DisplayName:             getEnv(prefix+"DISPLAY_NAME", name),
+			IssuerURL:               getEnv(prefix+"ISSUER", ""),
+			ClientID:                getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret:            getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:             getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:                  strings.Fields(strings.ReplaceAll(getEnv(prefix+"SCOPES", "profile,email,phone"), ",", " ")),
+			MobileClaim:             getEnv(prefix+"MOBILE_CLAIM", "phone_number"),
+			EmailClaim:              getEnv(prefix+"EMAIL_CLAIM", "email"),
+			NameClaim:               getEnv(prefix+"NAME_CLAIM", "name"),
+			DepartmentClaim:         getEnv(prefix+"DEPARTMENT_CLAIM", ""),
+			TrustUnverifiedContacts: getEnv(prefix+"TRUST_UNVERIFIED_CONTACTS", "false") == "true",
+		}
+		if provider.IssuerURL == "" || provider.ClientID == "" {
+			utils.Logger.Warnf("OIDC 身份提供方 %s 缺少 %sISSUER 或 %sCLIENT_ID，已跳过", name, prefix, prefix)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// splitEnvList 解析逗号分隔的配置列表（忽略空项），没有任何项时返回 nil
+func splitEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
 
 // SetConfig 设置全局配置（用于测试）
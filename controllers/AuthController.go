@@ -4,22 +4,63 @@ import (
 	"RHPRo-Task/dto"
 	"RHPRo-Task/services"
 	"RHPRo-Task/utils"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthController struct {
-	userService   *services.UserService
-	wechatService *services.WechatService
+	userService      *services.UserService
+	wechatService    *services.WechatService
+	authTokenService *services.AuthTokenService
+	twoFactorService *services.TwoFactorService
+	ldapService      *services.LdapService
 }
 
 func NewAuthController() *AuthController {
 	return &AuthController{
-		userService:   &services.UserService{},
-		wechatService: &services.WechatService{},
+		userService:      &services.UserService{},
+		wechatService:    &services.WechatService{},
+		authTokenService: &services.AuthTokenService{},
+		twoFactorService: &services.TwoFactorService{},
+		ldapService:      &services.LdapService{},
 	}
 }
 
+// loginClient 登录请求的客户端信息（IP、User-Agent），用于登记会话
+func loginClient(c *gin.Context) services.LoginClient {
+	return services.LoginClient{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// loginMessage 登录响应的提示信息（需要两步验证时提示下一步操作）
+func loginMessage(twoFactorRequired, twoFactorSetupRequired bool, success string) string {
+	if twoFactorRequired {
+		return "请输入两步验证码"
+	}
+	if twoFactorSetupRequired {
+		return "当前账号必须启用两步验证，请先绑定验证器"
+	}
+	return success
+}
+
+// respondLoginError 登录失败响应（账号或IP被锁定时返回 429 和 Retry-After）
+func respondLoginError(c *gin.Context, err error) {
+	var locked *services.LoginLockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		utils.Error(c, 429, err.Error())
+		return
+	}
+	utils.Error(c, 401, err.Error())
+}
+
 // Register 用户注册
 // @Summary 用户注册
 // @Description 新用户注册，注册后需要等待管理员审核
@@ -54,7 +95,7 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 用户使用手机号和密码登录，返回JWT令牌
+// @Description 用户使用手机号和密码登录，返回JWT令牌；已启用两步验证时返回 two_factor_required 和两步验证凭证，需调用 /auth/2fa/verify 完成登录；必须启用两步验证（如管理员）但尚未绑定时返回 two_factor_setup_required，需调用 /auth/2fa/setup 绑定后完成登录
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -62,6 +103,7 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 // @Success 200 {object} dto.LoginResponse "登录成功，返回token"
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "手机号或密码错误"
+// @Failure 429 {object} map[string]interface{} "登录失败次数过多，账号或IP被临时锁定"
 // @Router /auth/login [post]
 func (ctrl *AuthController) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -71,7 +113,64 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := ctrl.userService.Login(&req)
+	response, err := ctrl.userService.Login(&req, loginClient(c))
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, loginMessage(response.TwoFactorRequired, response.TwoFactorSetupRequired, "登录成功"), response)
+}
+
+// LdapLogin LDAP 目录账号登录
+// @Summary LDAP 目录账号登录
+// @Description 使用公司目录（LDAP）的登录名和密码登录，首次登录时自动创建账号（按目录中的手机号关联已有账号）；两步验证流程与手机号登录相同
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param credentials body dto.LdapLoginRequest true "目录登录凭证"
+// @Success 200 {object} dto.LoginResponse "登录成功，返回token"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "用户名或密码错误、未启用 LDAP 登录或目录服务不可用"
+// @Failure 429 {object} map[string]interface{} "登录失败次数过多，账号或IP被临时锁定"
+// @Router /auth/ldap/login [post]
+func (ctrl *AuthController) LdapLogin(c *gin.Context) {
+	var req dto.LdapLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	response, err := ctrl.ldapService.Login(&req, loginClient(c))
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, loginMessage(response.TwoFactorRequired, response.TwoFactorSetupRequired, "登录成功"), response)
+}
+
+// TwoFactorLogin 两步验证登录
+// @Summary 两步验证登录
+// @Description 登录第二步：使用两步验证凭证和验证器中的动态验证码（或恢复码）完成登录，返回JWT令牌；验证码连续错误 5 次后暂停验证 5 分钟
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorLoginRequest true "两步验证信息"
+// @Success 200 {object} dto.LoginResponse "登录成功，返回token"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "凭证无效或验证码错误"
+// @Router /auth/2fa/verify [post]
+func (ctrl *AuthController) TwoFactorLogin(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	response, err := ctrl.twoFactorService.VerifyLogin(&req, loginClient(c))
 	if err != nil {
 		utils.Error(c, 401, err.Error())
 		return
@@ -80,6 +179,62 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	utils.SuccessWithMessage(c, "登录成功", response)
 }
 
+// TwoFactorSetup 登录时绑定两步验证
+// @Summary 登录时绑定两步验证
+// @Description 必须启用两步验证但尚未绑定的账号，使用两步验证凭证获取密钥和二维码，在验证器 App 中扫码添加
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorSetupRequest true "两步验证凭证"
+// @Success 200 {object} dto.TwoFactorEnrollResponse "密钥和二维码"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "凭证无效或已过期"
+// @Router /auth/2fa/setup [post]
+func (ctrl *AuthController) TwoFactorSetup(c *gin.Context) {
+	var req dto.TwoFactorSetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	response, err := ctrl.twoFactorService.BeginSetup(&req)
+	if err != nil {
+		utils.Error(c, 401, err.Error())
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// TwoFactorSetupConfirm 登录时确认绑定两步验证
+// @Summary 登录时确认绑定两步验证
+// @Description 输入验证器中的动态验证码确认绑定，启用两步验证并完成登录，同时返回恢复码（仅显示一次）
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorSetupConfirmRequest true "确认绑定信息"
+// @Success 200 {object} dto.LoginResponse "登录成功，返回token和恢复码"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "凭证无效或验证码错误"
+// @Router /auth/2fa/setup/confirm [post]
+func (ctrl *AuthController) TwoFactorSetupConfirm(c *gin.Context) {
+	var req dto.TwoFactorSetupConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	response, err := ctrl.twoFactorService.ConfirmSetup(&req, loginClient(c))
+	if err != nil {
+		utils.Error(c, 401, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已启用两步验证，登录成功", response)
+}
+
 // WechatLogin 微信登录
 // @Summary 微信登录
 // @Description 使用微信授权码登录，支持扫码登录、小程序登录、公众号H5登录
@@ -98,7 +253,7 @@ func (ctrl *AuthController) WechatLogin(c *gin.Context) {
 		return
 	}
 
-	response, err := ctrl.wechatService.Login(&req)
+	response, err := ctrl.wechatService.Login(&req, loginClient(c))
 	if err != nil {
 		utils.Error(c, 400, err.Error())
 		return
@@ -107,7 +262,7 @@ func (ctrl *AuthController) WechatLogin(c *gin.Context) {
 	if response.NeedBind {
 		utils.SuccessWithMessage(c, "请补充手机号和密码完成注册", response)
 	} else {
-		utils.SuccessWithMessage(c, "登录成功", response)
+		utils.SuccessWithMessage(c, loginMessage(response.TwoFactorRequired, response.TwoFactorSetupRequired, "登录成功"), response)
 	}
 }
 
@@ -129,11 +284,79 @@ func (ctrl *AuthController) WechatBind(c *gin.Context) {
 		return
 	}
 
-	response, err := ctrl.wechatService.BindMobile(&req)
+	response, err := ctrl.wechatService.BindMobile(&req, loginClient(c))
 	if err != nil {
 		utils.Error(c, 400, err.Error())
 		return
 	}
 
-	utils.SuccessWithMessage(c, "绑定成功", response)
+	utils.SuccessWithMessage(c, loginMessage(response.TwoFactorRequired, response.TwoFactorSetupRequired, "绑定成功"), response)
+}
+
+// RefreshToken 刷新访问令牌
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，原刷新令牌随即失效；已失效的刷新令牌被再次使用时，该次登录的全部令牌都会被吊销
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} dto.TokenResponse "刷新成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "刷新令牌无效或已过期"
+// @Router /auth/refresh [post]
+func (ctrl *AuthController) RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	response, err := ctrl.authTokenService.Refresh(req.RefreshToken)
+	if err != nil {
+		utils.Error(c, 401, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "刷新成功", response)
+}
+
+// Logout 注销登录
+// @Summary 注销登录
+// @Description 注销当前登录会话，当前访问令牌和刷新令牌立即失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.LogoutRequest false "注销请求"
+// @Success 200 {object} map[string]interface{} "已注销"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /auth/logout [post]
+func (ctrl *AuthController) Logout(c *gin.Context) {
+	// 请求体可省略
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	tokenID := c.GetString("tokenID")
+	var expiresAt time.Time
+	if value, ok := c.Get("tokenExpiresAt"); ok {
+		expiresAt, _ = value.(time.Time)
+	}
+
+	if err := ctrl.authTokenService.Logout(userID.(uint), tokenID, expiresAt, req.RefreshToken); err != nil {
+		utils.Error(c, 500, "注销失败")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已注销", nil)
 }
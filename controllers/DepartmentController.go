@@ -28,6 +28,7 @@ func NewDepartmentController() *DepartmentController {
 // @Security BearerAuth
 // @Param department body dto.DepartmentRequest true "部门信息"
 // @Success 200 {object} map[string]interface{} "创建成功"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /departments [post]
 func (ctrl *DepartmentController) CreateDepartment(c *gin.Context) {
 	var req dto.DepartmentRequest
@@ -55,6 +56,7 @@ func (ctrl *DepartmentController) CreateDepartment(c *gin.Context) {
 // @Param id path int true "部门ID"
 // @Param department body dto.DepartmentRequest true "部门信息"
 // @Success 200 {object} map[string]interface{} "更新成功"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /departments/{id} [put]
 func (ctrl *DepartmentController) UpdateDepartment(c *gin.Context) {
 	idStr := c.Param("id")
@@ -87,6 +89,7 @@ func (ctrl *DepartmentController) UpdateDepartment(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "部门ID"
 // @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /departments/{id} [delete]
 func (ctrl *DepartmentController) DeleteDepartment(c *gin.Context) {
 	idStr := c.Param("id")
@@ -160,6 +163,7 @@ func (ctrl *DepartmentController) GetDepartmentDetail(c *gin.Context) {
 // @Param id path int true "部门ID"
 // @Param req body dto.AddLeaderRequest true "负责人信息"
 // @Success 200 {object} map[string]interface{} "添加成功"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /departments/{id}/leaders [post]
 func (ctrl *DepartmentController) AddLeader(c *gin.Context) {
 	idStr := c.Param("id")
@@ -193,6 +197,7 @@ func (ctrl *DepartmentController) AddLeader(c *gin.Context) {
 // @Param id path int true "部门ID"
 // @Param userId path int true "用户ID"
 // @Success 200 {object} map[string]interface{} "移除成功"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /departments/{id}/leaders/{userId} [delete]
 func (ctrl *DepartmentController) RemoveLeader(c *gin.Context) {
 	idStr := c.Param("id")
@@ -227,6 +232,7 @@ func (ctrl *DepartmentController) RemoveLeader(c *gin.Context) {
 // @Param id path int true "部门ID"
 // @Param userIds body []uint true "用户ID列表"
 // @Success 200 {object} map[string]interface{} "分配成功"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /departments/{id}/users [post]
 func (ctrl *DepartmentController) AssignUsers(c *gin.Context) {
 	idStr := c.Param("id")
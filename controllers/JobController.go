@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/scheduler"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	jobService *services.JobService
+}
+
+func NewJobController() *JobController {
+	return &JobController{
+		jobService: &services.JobService{},
+	}
+}
+
+// GetJobList 获取定时任务列表
+// @Summary 获取定时任务列表
+// @Description 获取已注册的定时任务，包含 cron 表达式、下次执行时间、最近一次执行和最近一次失败的错误信息
+// @Tags 定时任务
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.JobResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/jobs [get]
+func (ctrl *JobController) GetJobList(c *gin.Context) {
+	jobs, err := ctrl.jobService.GetJobList()
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, jobs)
+}
+
+// GetJobRuns 获取定时任务执行记录
+// @Summary 获取定时任务执行记录
+// @Description 分页查询定时任务的执行记录，按开始时间倒序
+// @Tags 定时任务
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "任务名称"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param status query string false "执行状态：running/success/failed"
+// @Success 200 {object} dto.PaginationResponse{data=[]dto.JobRunResponse} "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 404 {object} map[string]interface{} "定时任务不存在"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/jobs/{name}/runs [get]
+func (ctrl *JobController) GetJobRuns(c *gin.Context) {
+	var query dto.JobRunQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	runs, err := ctrl.jobService.GetJobRuns(c.Param("name"), &query)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrJobNotFound) {
+			utils.Error(c, 404, err.Error())
+			return
+		}
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, runs)
+}
+
+// TriggerJob 手动触发定时任务
+// @Summary 手动触发定时任务
+// @Description 立即在后台执行一次定时任务，返回本次执行记录；任务正在执行（包括其他实例）时不能触发
+// @Tags 定时任务
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "任务名称"
+// @Success 200 {object} dto.JobRunResponse "已触发"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 404 {object} map[string]interface{} "定时任务不存在"
+// @Failure 409 {object} map[string]interface{} "定时任务正在执行中"
+// @Failure 500 {object} map[string]interface{} "触发失败"
+// @Router /admin/jobs/{name}/run [post]
+func (ctrl *JobController) TriggerJob(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	run, err := ctrl.jobService.TriggerJob(c.Param("name"), userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			utils.Error(c, 404, err.Error())
+		case errors.Is(err, scheduler.ErrJobRunning):
+			utils.Error(c, 409, err.Error())
+		default:
+			utils.Error(c, 500, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已触发", run)
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OidcController struct {
+	oidcService *services.OidcService
+}
+
+func NewOidcController() *OidcController {
+	return &OidcController{
+		oidcService: &services.OidcService{},
+	}
+}
+
+// GetProviders 获取单点登录方式
+// @Summary 获取单点登录方式
+// @Description 获取已配置的 OpenID Connect 身份提供方，用于在登录页显示单点登录入口
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Success 200 {object} []dto.OidcProviderResponse "查询成功"
+// @Router /auth/oidc/providers [get]
+func (ctrl *OidcController) GetProviders(c *gin.Context) {
+	utils.Success(c, ctrl.oidcService.GetProviders())
+}
+
+// Authorize 获取单点登录授权地址
+// @Summary 获取单点登录授权地址
+// @Description 生成身份提供方的授权地址（授权码 + PKCE），前端跳转到该地址登录，10 分钟内有效；登录完成后身份提供方带授权码和 state 跳回配置的回调页面
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param provider path string true "身份提供方标识"
+// @Success 200 {object} dto.OidcAuthorizeResponse "授权地址"
+// @Failure 400 {object} map[string]interface{} "未配置该单点登录方式或身份提供方不可用"
+// @Router /auth/oidc/{provider}/authorize [get]
+func (ctrl *OidcController) Authorize(c *gin.Context) {
+	response, err := ctrl.oidcService.Authorize(c.Param("provider"))
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// Callback 单点登录回调
+// @Summary 单点登录回调
+// @Description 回调页面提交授权码和 state 完成登录：已关联的账号直接登录（两步验证流程与手机号登录相同）；未关联时按身份提供方已验证的邮箱、手机号关联已有账号；都没有匹配时返回 need_bind 和临时凭证，需调用 /auth/oidc/bind 绑定手机号
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param provider path string true "身份提供方标识"
+// @Param request body dto.OidcCallbackRequest true "授权码和 state"
+// @Success 200 {object} dto.OidcLoginResponse "登录成功或需要绑定手机号"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "登录请求无效或已过期、授权失败或账号被禁用"
+// @Router /auth/oidc/{provider}/callback [post]
+func (ctrl *OidcController) Callback(c *gin.Context) {
+	var req dto.OidcCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	response, err := ctrl.oidcService.Callback(c.Param("provider"), &req, loginClient(c))
+	if err != nil {
+		utils.Error(c, 401, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, oidcLoginMessage(response), response)
+}
+
+// Bind 单点登录绑定手机号
+// @Summary 单点登录绑定手机号
+// @Description 未能自动关联账号时绑定手机号：手机号已注册时输入该账号的登录密码完成关联并登录；未注册时创建新账号，需等待管理员审核
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.OidcBindRequest true "绑定信息"
+// @Success 200 {object} dto.OidcLoginResponse "登录成功或待审核"
+// @Failure 400 {object} map[string]interface{} "参数验证失败或绑定失败"
+// @Failure 429 {object} map[string]interface{} "密码错误次数过多，账号或IP被临时锁定"
+// @Router /auth/oidc/bind [post]
+func (ctrl *OidcController) Bind(c *gin.Context) {
+	var req dto.OidcBindRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	response, err := ctrl.oidcService.Bind(&req, loginClient(c))
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, oidcLoginMessage(response), response)
+}
+
+// oidcLoginMessage 单点登录响应的提示信息
+func oidcLoginMessage(response *dto.OidcLoginResponse) string {
+	if response.NeedBind {
+		return "请绑定手机号"
+	}
+	if response.PendingApproval {
+		return "账号待审核，请联系管理员"
+	}
+	return loginMessage(response.TwoFactorRequired, response.TwoFactorSetupRequired, "登录成功")
+}
@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewAnnotationController struct {
+	annotationService *services.ReviewAnnotationService
+}
+
+func NewReviewAnnotationController() *ReviewAnnotationController {
+	return &ReviewAnnotationController{
+		annotationService: &services.ReviewAnnotationService{},
+	}
+}
+
+// CreateAnnotation 创建审核批注
+// @Summary 创建审核批注
+// @Description 在方案/执行计划的某个版本上批注：text 锚定到字段的字符区间，step 锚定到实施步骤的 JSON 路径，goal 锚定到执行计划目标。提交新版本时未解决的批注会转移到新版本并重新定位
+// @Tags 审核批注
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param request body dto.CreateAnnotationRequest true "批注信息"
+// @Success 200 {object} dto.ReviewAnnotationResponse "批注成功"
+// @Failure 400 {object} map[string]interface{} "参数错误或锚点无效"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /tasks/{id}/annotations [post]
+func (ctrl *ReviewAnnotationController) CreateAnnotation(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	var req dto.CreateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	annotation, err := ctrl.annotationService.CreateAnnotation(uint(taskID), userID.(uint), &req)
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "批注成功", annotation)
+}
+
+// GetAnnotations 获取任务的审核批注
+// @Summary 获取任务的审核批注
+// @Description 查询任务的批注及回复，可按批注对象、当前锚定的版本和解决状态筛选
+// @Tags 审核批注
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param target_type query string false "批注对象：requirement_solutions/execution_plans"
+// @Param version query int false "当前锚定的版本号"
+// @Param status query string false "状态：open/resolved"
+// @Success 200 {array} dto.ReviewAnnotationResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /tasks/{id}/annotations [get]
+func (ctrl *ReviewAnnotationController) GetAnnotations(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	var query dto.AnnotationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	annotations, err := ctrl.annotationService.GetAnnotations(uint(taskID), &query)
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, annotations)
+}
+
+// ReplyAnnotation 回复批注
+// @Summary 回复批注
+// @Description 在批注下回复，通知批注人、执行人和此前参与回复的人
+// @Tags 审核批注
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "批注ID"
+// @Param request body dto.AnnotationReplyRequest true "回复内容"
+// @Success 200 {object} dto.AnnotationReplyResponse "回复成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /annotations/{id}/replies [post]
+func (ctrl *ReviewAnnotationController) ReplyAnnotation(c *gin.Context) {
+	annotationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的批注ID")
+		return
+	}
+
+	var req dto.AnnotationReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	reply, err := ctrl.annotationService.ReplyAnnotation(uint(annotationID), userID.(uint), &req)
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "回复成功", reply)
+}
+
+// ResolveAnnotation 解决批注
+// @Summary 解决批注
+// @Description 批注人、任务创建人或执行人将批注标记为已解决，已解决的批注不再转移到新版本
+// @Tags 审核批注
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "批注ID"
+// @Success 200 {object} map[string]interface{} "已解决"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /annotations/{id}/resolve [post]
+func (ctrl *ReviewAnnotationController) ResolveAnnotation(c *gin.Context) {
+	annotationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的批注ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.annotationService.ResolveAnnotation(uint(annotationID), userID.(uint)); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已解决", nil)
+}
+
+// UnresolveAnnotation 重新打开批注
+// @Summary 重新打开批注
+// @Description 将已解决的批注重新打开，已有更新的版本时批注转移到最新版本并重新定位
+// @Tags 审核批注
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "批注ID"
+// @Success 200 {object} map[string]interface{} "已重新打开"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /annotations/{id}/unresolve [post]
+func (ctrl *ReviewAnnotationController) UnresolveAnnotation(c *gin.Context) {
+	annotationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的批注ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.annotationService.UnresolveAnnotation(uint(annotationID), userID.(uint)); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已重新打开", nil)
+}
+
+// DeleteAnnotation 删除批注
+// @Summary 删除批注
+// @Description 批注人删除自己的批注，已有他人回复时不能删除
+// @Tags 审核批注
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "批注ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /annotations/{id} [delete]
+func (ctrl *ReviewAnnotationController) DeleteAnnotation(c *gin.Context) {
+	annotationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的批注ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.annotationService.DeleteAnnotation(uint(annotationID), userID.(uint)); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
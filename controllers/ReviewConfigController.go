@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewConfigController struct {
+	reviewConfigService *services.ReviewConfigService
+}
+
+func NewReviewConfigController() *ReviewConfigController {
+	return &ReviewConfigController{
+		reviewConfigService: &services.ReviewConfigService{},
+	}
+}
+
+// GetRoleWeights 获取审核人角色投票权重
+// @Summary 获取审核人角色投票权重
+// @Description 获取加权投票模式下各审核人角色的投票权重，未配置的角色按权重 1 计票
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ReviewRoleWeightItem "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/review/role-weights [get]
+func (ctrl *ReviewConfigController) GetRoleWeights(c *gin.Context) {
+	weights, err := ctrl.reviewConfigService.GetRoleWeights()
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, weights)
+}
+
+// SaveRoleWeights 保存审核人角色投票权重
+// @Summary 保存审核人角色投票权重
+// @Description 按角色新增或更新投票权重（leader/jury/expert），只影响之后提交的投票
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SaveReviewRoleWeightsRequest true "角色权重"
+// @Success 200 {array} dto.ReviewRoleWeightItem "保存成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /admin/review/role-weights [put]
+func (ctrl *ReviewConfigController) SaveRoleWeights(c *gin.Context) {
+	var req dto.SaveReviewRoleWeightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	weights, err := ctrl.reviewConfigService.SaveRoleWeights(&req)
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "保存成功", weights)
+}
+
+// GetRubricList 获取评分标准列表
+// @Summary 获取评分标准列表
+// @Description 获取审核评分标准及其评分维度，可按审核类型筛选
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param review_type query string false "审核类型：solution_review/plan_review"
+// @Success 200 {array} dto.ReviewRubricResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/review/rubrics [get]
+func (ctrl *ReviewConfigController) GetRubricList(c *gin.Context) {
+	rubrics, err := ctrl.reviewConfigService.GetRubricList(c.Query("review_type"))
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, rubrics)
+}
+
+// CreateRubric 创建评分标准
+// @Summary 创建评分标准
+// @Description 按审核类型创建评分标准（评分维度、权重和分值范围），每个审核类型最多一个启用的评分标准；之后发起的审核自动绑定
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rubric body dto.ReviewRubricRequest true "评分标准"
+// @Success 200 {object} dto.ReviewRubricResponse "创建成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/review/rubrics [post]
+func (ctrl *ReviewConfigController) CreateRubric(c *gin.Context) {
+	var req dto.ReviewRubricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	rubric, err := ctrl.reviewConfigService.CreateRubric(&req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", rubric)
+}
+
+// UpdateRubric 更新评分标准
+// @Summary 更新评分标准
+// @Description 更新评分标准；已被审核会话使用的评分标准不能修改分值范围、增删维度或调整权重
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评分标准ID"
+// @Param rubric body dto.ReviewRubricRequest true "评分标准"
+// @Success 200 {object} dto.ReviewRubricResponse "更新成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/review/rubrics/{id} [put]
+func (ctrl *ReviewConfigController) UpdateRubric(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的评分标准ID")
+		return
+	}
+
+	var req dto.ReviewRubricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	rubric, err := ctrl.reviewConfigService.UpdateRubric(uint(id), &req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", rubric)
+}
+
+// DeleteRubric 删除评分标准
+// @Summary 删除评分标准
+// @Description 删除评分标准；已被审核会话使用的评分标准不能删除，可改为停用
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评分标准ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 400 {object} map[string]interface{} "无效的评分标准ID"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/review/rubrics/{id} [delete]
+func (ctrl *ReviewConfigController) DeleteRubric(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的评分标准ID")
+		return
+	}
+
+	if err := ctrl.reviewConfigService.DeleteRubric(uint(id)); err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// GetApprovalChainList 获取审批链模板列表
+// @Summary 获取审批链模板列表
+// @Description 获取审批链模板及其审批阶段，可按审核类型筛选
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param review_type query string false "审核类型：solution_review/plan_review"
+// @Success 200 {array} dto.ApprovalChainTemplateResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/review/approval-chains [get]
+func (ctrl *ReviewConfigController) GetApprovalChainList(c *gin.Context) {
+	templates, err := ctrl.reviewConfigService.GetApprovalChainList(c.Query("review_type"))
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, templates)
+}
+
+// CreateApprovalChain 创建审批链模板
+// @Summary 创建审批链模板
+// @Description 按任务类型、优先级和跨部门标记配置审批链（如 执行人部门负责人 → 创建人部门负责人 → 创建人）；匹配的任务提交方案/计划后按阶段依次审批，任一阶段驳回即退回执行人
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template body dto.ApprovalChainTemplateRequest true "审批链模板"
+// @Success 200 {object} dto.ApprovalChainTemplateResponse "创建成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/review/approval-chains [post]
+func (ctrl *ReviewConfigController) CreateApprovalChain(c *gin.Context) {
+	var req dto.ApprovalChainTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	template, err := ctrl.reviewConfigService.CreateApprovalChain(&req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", template)
+}
+
+// UpdateApprovalChain 更新审批链模板
+// @Summary 更新审批链模板
+// @Description 更新审批链模板；有进行中的审批链使用该模板时不能修改审批阶段
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "审批链模板ID"
+// @Param template body dto.ApprovalChainTemplateRequest true "审批链模板"
+// @Success 200 {object} dto.ApprovalChainTemplateResponse "更新成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/review/approval-chains/{id} [put]
+func (ctrl *ReviewConfigController) UpdateApprovalChain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的审批链模板ID")
+		return
+	}
+
+	var req dto.ApprovalChainTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	template, err := ctrl.reviewConfigService.UpdateApprovalChain(uint(id), &req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", template)
+}
+
+// DeleteApprovalChain 删除审批链模板
+// @Summary 删除审批链模板
+// @Description 删除审批链模板；已被审核会话使用的模板不能删除，可改为停用
+// @Tags 审核配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "审批链模板ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 400 {object} map[string]interface{} "无效的审批链模板ID"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/review/approval-chains/{id} [delete]
+func (ctrl *ReviewConfigController) DeleteApprovalChain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的审批链模板ID")
+		return
+	}
+
+	if err := ctrl.reviewConfigService.DeleteApprovalChain(uint(id)); err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewDelegationController struct {
+	delegationService *services.ReviewDelegationService
+}
+
+func NewReviewDelegationController() *ReviewDelegationController {
+	return &ReviewDelegationController{
+		delegationService: &services.ReviewDelegationService{},
+	}
+}
+
+// CreateDelegation 创建审核委托
+// @Summary 创建审核委托
+// @Description 当前用户在指定时间段内将审核/审批委托给代理人，可限定部门或任务类型；生效期间代理人可代为提交审核意见、做出最终决策和审批状态转换，日志记录代理关系
+// @Tags 审核委托
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateDelegationRequest true "委托信息"
+// @Success 200 {object} dto.DelegationResponse "委托成功"
+// @Failure 400 {object} map[string]interface{} "参数错误或时间范围重叠"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /delegations [post]
+func (ctrl *ReviewDelegationController) CreateDelegation(c *gin.Context) {
+	var req dto.CreateDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	delegation, err := ctrl.delegationService.CreateDelegation(userID.(uint), &req)
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "委托成功", delegation)
+}
+
+// GetDelegations 获取我的审核委托
+// @Summary 获取我的审核委托
+// @Description 查询当前用户委托出去的和被委托的审核委托，可按身份和状态筛选
+// @Tags 审核委托
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "我的身份：delegator/delegate"
+// @Param status query string false "状态：upcoming/active/expired/revoked"
+// @Success 200 {array} dto.DelegationResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /delegations [get]
+func (ctrl *ReviewDelegationController) GetDelegations(c *gin.Context) {
+	var query dto.DelegationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	delegations, err := ctrl.delegationService.GetDelegations(userID.(uint), &query)
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, delegations)
+}
+
+// RevokeDelegation 撤销审核委托
+// @Summary 撤销审核委托
+// @Description 委托人撤销未过期的委托，撤销后代理人不能再代为处理审核
+// @Tags 审核委托
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "委托ID"
+// @Success 200 {object} map[string]interface{} "已撤销"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /delegations/{id}/revoke [post]
+func (ctrl *ReviewDelegationController) RevokeDelegation(c *gin.Context) {
+	delegationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的委托ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.delegationService.RevokeDelegation(uint(delegationID), userID.(uint)); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已撤销", nil)
+}
@@ -0,0 +1,366 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SLAController struct {
+	slaService *services.SLAService
+}
+
+func NewSLAController() *SLAController {
+	return &SLAController{
+		slaService: &services.SLAService{},
+	}
+}
+
+// GetTaskTimers 获取任务的 SLA 计时
+// @Summary 获取任务的 SLA 计时
+// @Description 根据任务的状态变更历史，按匹配的 SLA 策略和工作日历计算每段状态停留的已用工作时间、截止时间和是否违约（只能查看自己可见范围内的任务）
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Success 200 {array} dto.SLATimerResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "无效的任务ID"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /sla/tasks/{id} [get]
+func (ctrl *SLAController) GetTaskTimers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	timers, err := ctrl.slaService.GetTaskTimers(uint(id), userID.(uint))
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.Success(c, timers)
+}
+
+// GetBreaches 获取 SLA 违约事件列表
+// @Summary 获取 SLA 违约事件列表
+// @Description 分页查询当前用户可见范围内任务的 SLA 违约事件，可按任务、部门、执行人和是否已解除筛选
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param task_id query int false "任务ID"
+// @Param department_id query int false "部门ID"
+// @Param executor_id query int false "执行人ID"
+// @Param only_open query bool false "是否只看未解除的违约"
+// @Success 200 {object} dto.PaginationResponse{data=[]dto.SLABreachResponse} "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /sla/breaches [get]
+func (ctrl *SLAController) GetBreaches(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	var query dto.SLABreachQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	breaches, err := ctrl.slaService.GetBreaches(&query, userID.(uint))
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, breaches)
+}
+
+// GetComplianceReport 获取 SLA 达成率报表
+// @Summary 获取 SLA 达成率报表
+// @Description 按进入状态的时间范围统计当前用户可见范围内任务的 SLA 达成率，包含汇总、按任务所属部门和按执行人的统计，按达成率升序排列
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_time query string false "开始时间（YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS）"
+// @Param end_time query string false "结束时间（YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS）"
+// @Param department_id query int false "部门ID"
+// @Success 200 {object} dto.SLAReportResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /sla/report [get]
+func (ctrl *SLAController) GetComplianceReport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	var query dto.SLAReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	report, err := ctrl.slaService.GetComplianceReport(&query, userID.(uint))
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.Success(c, report)
+}
+
+// GetPolicyList 获取 SLA 策略列表
+// @Summary 获取 SLA 策略列表
+// @Description 获取全部 SLA 策略（含停用的）
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.SLAPolicyResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/sla/policies [get]
+func (ctrl *SLAController) GetPolicyList(c *gin.Context) {
+	policies, err := ctrl.slaService.GetPolicyList()
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, policies)
+}
+
+// CreatePolicy 创建 SLA 策略
+// @Summary 创建 SLA 策略
+// @Description 创建任务类型在某状态的最长停留时间策略，同一类型、状态和优先级只能有一个策略
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param policy body dto.SLAPolicyRequest true "SLA 策略"
+// @Success 200 {object} dto.SLAPolicyResponse "创建成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/sla/policies [post]
+func (ctrl *SLAController) CreatePolicy(c *gin.Context) {
+	var req dto.SLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	policy, err := ctrl.slaService.CreatePolicy(&req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", policy)
+}
+
+// UpdatePolicy 更新 SLA 策略
+// @Summary 更新 SLA 策略
+// @Description 更新 SLA 策略，修改后按新策略计算所有计时
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "策略ID"
+// @Param policy body dto.SLAPolicyRequest true "SLA 策略"
+// @Success 200 {object} dto.SLAPolicyResponse "更新成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/sla/policies/{id} [put]
+func (ctrl *SLAController) UpdatePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的策略ID")
+		return
+	}
+
+	var req dto.SLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	policy, err := ctrl.slaService.UpdatePolicy(uint(id), &req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", policy)
+}
+
+// DeletePolicy 删除 SLA 策略
+// @Summary 删除 SLA 策略
+// @Description 删除 SLA 策略，已记录的违约事件保留
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "策略ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 400 {object} map[string]interface{} "无效的策略ID"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/sla/policies/{id} [delete]
+func (ctrl *SLAController) DeletePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的策略ID")
+		return
+	}
+
+	if err := ctrl.slaService.DeletePolicy(uint(id)); err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// GetCalendarList 获取工作日历列表
+// @Summary 获取工作日历列表
+// @Description 获取全部工作日历及其节假日/调休配置
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.BusinessCalendarResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/sla/calendars [get]
+func (ctrl *SLAController) GetCalendarList(c *gin.Context) {
+	calendars, err := ctrl.slaService.GetCalendarList()
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, calendars)
+}
+
+// CreateCalendar 创建工作日历
+// @Summary 创建工作日历
+// @Description 创建工作日历，可绑定部门（每个部门最多一个）或设为默认日历
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param calendar body dto.BusinessCalendarRequest true "工作日历"
+// @Success 200 {object} dto.BusinessCalendarResponse "创建成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/sla/calendars [post]
+func (ctrl *SLAController) CreateCalendar(c *gin.Context) {
+	var req dto.BusinessCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	calendar, err := ctrl.slaService.CreateCalendar(&req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", calendar)
+}
+
+// UpdateCalendar 更新工作日历
+// @Summary 更新工作日历
+// @Description 更新工作日历，节假日/调休按请求整体替换
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "日历ID"
+// @Param calendar body dto.BusinessCalendarRequest true "工作日历"
+// @Success 200 {object} dto.BusinessCalendarResponse "更新成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/sla/calendars/{id} [put]
+func (ctrl *SLAController) UpdateCalendar(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的日历ID")
+		return
+	}
+
+	var req dto.BusinessCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	calendar, err := ctrl.slaService.UpdateCalendar(uint(id), &req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", calendar)
+}
+
+// DeleteCalendar 删除工作日历
+// @Summary 删除工作日历
+// @Description 删除工作日历及其节假日，被 SLA 策略引用时不允许删除
+// @Tags SLA管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "日历ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 400 {object} map[string]interface{} "无效的日历ID"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/sla/calendars/{id} [delete]
+func (ctrl *SLAController) DeleteCalendar(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的日历ID")
+		return
+	}
+
+	if err := ctrl.slaService.DeleteCalendar(uint(id)); err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchController struct {
+	searchService *services.SearchService
+}
+
+func NewSearchController() *SearchController {
+	return &SearchController{
+		searchService: &services.SearchService{},
+	}
+}
+
+// Search 全文检索
+// @Summary 全文检索
+// @Description 检索任务标题/描述、思路方案（内容及脑图）、执行计划（含实施步骤）和评论，结果按相关度排序并按当前用户的任务可见范围过滤，返回高亮片段
+// @Tags 全文检索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "检索关键词"
+// @Param types query string false "检索范围（逗号分隔）：task/solution/plan/comment，默认全部"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} dto.SearchResponse "检索成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "检索失败"
+// @Router /search [get]
+func (ctrl *SearchController) Search(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req dto.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	result, err := ctrl.searchService.Search(&req, userID.(uint))
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
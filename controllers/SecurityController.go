@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SecurityController struct {
+	loginGuardService *services.LoginGuardService
+	auditLogService   *services.AuditLogService
+}
+
+func NewSecurityController() *SecurityController {
+	return &SecurityController{
+		loginGuardService: &services.LoginGuardService{},
+		auditLogService:   &services.AuditLogService{},
+	}
+}
+
+// UnlockLogin 解除登录锁定（管理员）
+// @Summary 解除登录锁定
+// @Description 管理员解除账号和/或IP因登录失败次数过多产生的锁定，并清除失败计数；用户ID和IP至少传一个
+// @Tags 安全管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UnlockLoginRequest true "解除锁定信息"
+// @Success 200 {object} map[string]interface{} "已解除锁定"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
+// @Router /admin/security/unlock-login [post]
+func (ctrl *SecurityController) UnlockLogin(c *gin.Context) {
+	var req dto.UnlockLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.loginGuardService.Unlock(&req, userID.(uint), c.ClientIP()); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已解除锁定", nil)
+}
+
+// GetAuditLogs 获取审计日志（管理员）
+// @Summary 获取审计日志
+// @Description 分页查询安全审计日志（登录锁定、两步验证变更等），按时间倒序
+// @Tags 安全管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param action query string false "事件类型：login_locked/login_ip_locked/login_unlocked/login_ip_unlocked/two_factor_enabled/two_factor_disabled/recovery_code_used/recovery_codes_renewed/directory_user_disabled/identity_linked"
+// @Param user_id query int false "相关用户ID"
+// @Param target query string false "事件对象（手机号、IP）"
+// @Success 200 {object} dto.PaginationResponse{data=[]dto.AuditLogResponse} "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/audit-logs [get]
+func (ctrl *SecurityController) GetAuditLogs(c *gin.Context) {
+	var query dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	logs, err := ctrl.auditLogService.GetAuditLogs(&query)
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, logs)
+}
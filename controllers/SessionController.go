@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	sessionService *services.SessionService
+}
+
+func NewSessionController() *SessionController {
+	return &SessionController{
+		sessionService: &services.SessionService{},
+	}
+}
+
+// currentSessionID 当前请求所用的登录会话ID（由认证中间件设置）
+func currentSessionID(c *gin.Context) uint {
+	if value, ok := c.Get("sessionID"); ok {
+		if sessionID, ok := value.(uint); ok {
+			return sessionID
+		}
+	}
+	return 0
+}
+
+// GetMySessions 获取我的登录会话
+// @Summary 获取我的登录会话
+// @Description 查询当前用户的登录会话（设备、User-Agent、IP、登录方式、登录和最近访问时间），current 标记当前请求所用的会话
+// @Tags 会话管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param include_revoked query bool false "是否包含已结束的会话"
+// @Success 200 {array} dto.SessionResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /sessions [get]
+func (ctrl *SessionController) GetMySessions(c *gin.Context) {
+	var query dto.SessionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	sessions, err := ctrl.sessionService.GetSessions(userID.(uint), currentSessionID(c), &query)
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, sessions)
+}
+
+// RevokeMySession 结束我的登录会话
+// @Summary 结束我的登录会话
+// @Description 结束当前用户的某个登录会话（如遗留在他人电脑上的登录），该会话的访问令牌和刷新令牌立即失效
+// @Tags 会话管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "会话ID"
+// @Success 200 {object} map[string]interface{} "会话已结束"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /sessions/{id} [delete]
+func (ctrl *SessionController) RevokeMySession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的会话ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.sessionService.RevokeSession(uint(sessionID), userID.(uint)); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "会话已结束", nil)
+}
+
+// RevokeOtherSessions 结束我的其他登录会话
+// @Summary 结束我的其他登录会话
+// @Description 结束当前用户除当前会话外的全部登录会话
+// @Tags 会话管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.RevokeSessionsResult "会话已结束"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /sessions/revoke-others [post]
+func (ctrl *SessionController) RevokeOtherSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	result, err := ctrl.sessionService.RevokeOtherSessions(userID.(uint), currentSessionID(c))
+	if err != nil {
+		utils.Error(c, 500, "结束会话失败")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "会话已结束", result)
+}
+
+// GetUserSessions 获取用户的登录会话（管理员）
+// @Summary 获取用户的登录会话
+// @Description 管理员查询指定用户的登录会话
+// @Tags 会话管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param include_revoked query bool false "是否包含已结束的会话"
+// @Success 200 {array} dto.SessionResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
+// @Router /admin/users/{id}/sessions [get]
+func (ctrl *SessionController) GetUserSessions(c *gin.Context) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	var query dto.SessionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	sessions, err := ctrl.sessionService.GetSessions(uint(targetUserID), currentSessionID(c), &query)
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, sessions)
+}
+
+// RevokeUserSessions 结束用户的全部登录会话（管理员）
+// @Summary 结束用户的全部登录会话
+// @Description 管理员结束指定用户的全部登录会话，用户需重新登录
+// @Tags 会话管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} dto.RevokeSessionsResult "会话已结束"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
+// @Router /admin/users/{id}/sessions [delete]
+func (ctrl *SessionController) RevokeUserSessions(c *gin.Context) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	result, err := ctrl.sessionService.AdminRevokeUserSessions(uint(targetUserID), userID.(uint))
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "会话已结束", result)
+}
+
+// AdminRevokeSession 结束任意登录会话（管理员）
+// @Summary 结束任意登录会话
+// @Description 管理员结束任意用户的某个登录会话
+// @Tags 会话管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "会话ID"
+// @Success 200 {object} map[string]interface{} "会话已结束"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
+// @Router /admin/sessions/{id} [delete]
+func (ctrl *SessionController) AdminRevokeSession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的会话ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.sessionService.AdminRevokeSession(uint(sessionID), userID.(uint)); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "会话已结束", nil)
+}
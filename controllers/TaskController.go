@@ -4,6 +4,7 @@ import (
 	"RHPRo-Task/dto"
 	"RHPRo-Task/services"
 	"RHPRo-Task/utils"
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -101,7 +102,13 @@ func (ctrl *TaskController) GetTaskInfo(c *gin.Context) {
 // @Param time_range query string false "时间范围快捷筛选：week(近一周)/month(近一个月)/three_months(近三个月)"
 // @Param start_time query string false "自定义开始时间（格式：2006-01-02 或 2006-01-02T15:04:05）"
 // @Param end_time query string false "自定义结束时间（格式：2006-01-02 或 2006-01-02T15:04:05）"
-// @Success 200 {object} dto.PaginationResponse "查询成功"
+// @Param q query string false "查询语言表达式，如：status in (req_in_progress, req_blocked) and priority >= 3 and executor = me and due < +7d（语法错误时返回400及出错位置）"
+// @Param sort_by query string false "排序字段：created_at/updated_at/priority/expected_start_date/expected_end_date/task_no/title/status_code/progress"
+// @Param sort_order query string false "排序方向：asc/desc" default(desc)
+// @Param pagination query string false "分页方式：offset（页码分页）/cursor（游标分页，不返回总数）" default(offset)
+// @Param cursor query string false "游标（取自上一页的 next_cursor，传入即按游标分页；游标分页仅支持按 created_at/updated_at 排序）"
+// @Param fields query string false "返回的关联数据，逗号分隔：creator/executor/department/tags/subtasks/solution/plan/attachments（不传时返回 executor/attachments/subtasks/solution/plan）"
+// @Success 200 {object} dto.PaginationResponse "查询成功（游标分页时返回 dto.CursorPaginationResponse）"
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "查询失败"
@@ -122,8 +129,17 @@ func (ctrl *TaskController) GetTaskList(c *gin.Context) {
 		return
 	}
 
-	result, err := ctrl.taskService.GetTaskList(&req, userID)
+	var result interface{}
+	var err error
+	if req.UsesCursor() {
+		result, err = ctrl.taskService.GetTaskListByCursor(&req, userID)
+	} else {
+		result, err = ctrl.taskService.GetTaskList(&req, userID)
+	}
 	if err != nil {
+		if respondTaskQueryError(c, err) {
+			return
+		}
 		utils.Error(c, 500, err.Error())
 		return
 	}
@@ -146,6 +162,10 @@ func (ctrl *TaskController) GetTaskList(c *gin.Context) {
 // @Param status_code query string false "状态编码"
 // @Param priority query int false "优先级"
 // @Param my_role query string false "筛选角色：all/creator/executor/jury" default(all)
+// @Param q query string false "查询语言表达式，如：status in (req_in_progress, req_blocked) and priority >= 3 and executor = me and due < +7d（语法错误时返回400及出错位置）"
+// @Param sort_by query string false "排序字段：created_at/updated_at/priority/expected_start_date/expected_end_date/task_no/title/status_code/progress"
+// @Param sort_order query string false "排序方向：asc/desc" default(desc)
+// @Param fields query string false "返回的关联数据，逗号分隔：creator/executor/department/tags/subtasks/solution/plan/attachments（不传时返回 executor/attachments/subtasks/solution/plan）"
 // @Success 200 {object} dto.PaginationResponse "查询成功，返回数据中包含 my_role 字段标识用户角色"
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "未授权"
@@ -169,6 +189,9 @@ func (ctrl *TaskController) GetMyTasks(c *gin.Context) {
 
 	result, err := ctrl.taskService.GetMyTasks(&req, userID)
 	if err != nil {
+		if respondTaskQueryError(c, err) {
+			return
+		}
 		utils.Error(c, 500, "查询失败")
 		return
 	}
@@ -271,6 +294,7 @@ func (ctrl *TaskController) UpdateTask(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "无效的任务ID"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "删除失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /tasks/{id} [delete]
 func (ctrl *TaskController) DeleteTask(c *gin.Context) {
 	idStr := c.Param("id")
@@ -290,14 +314,14 @@ func (ctrl *TaskController) DeleteTask(c *gin.Context) {
 
 // TransitStatus 执行任务状态转换
 // @Summary 执行状态转换
-// @Description 执行任务状态转换，验证状态有效性并记录变更日志
+// @Description 执行任务状态转换，验证状态有效性并记录变更日志。转换规则需要审批且操作人不是任务创建人或所属部门负责人时，创建状态转换申请，审批通过后才变更状态
 // @Tags 任务管理
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "任务ID"
 // @Param transition body dto.TaskStatusTransitionRequest true "状态转换信息"
-// @Success 200 {object} map[string]interface{} "状态转换成功"
+// @Success 200 {object} dto.TransitionRequestResponse "状态转换成功；转换规则需要审批时返回已提交的申请，任务状态在审批通过后变更"
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "状态转换失败"
@@ -325,10 +349,15 @@ func (ctrl *TaskController) TransitStatus(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.taskService.TransitStatus(uint(taskID), userID.(uint), &req); err != nil {
+	request, err := ctrl.taskService.TransitStatus(uint(taskID), userID.(uint), &req)
+	if err != nil {
 		utils.Error(c, 500, err.Error())
 		return
 	}
+	if request != nil {
+		utils.SuccessWithMessage(c, "该状态转换需要审批，已提交申请", request)
+		return
+	}
 
 	utils.SuccessWithMessage(c, "状态转换成功", nil)
 }
@@ -346,6 +375,7 @@ func (ctrl *TaskController) TransitStatus(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "分配失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /tasks/{id}/assign [post]
 func (ctrl *TaskController) AssignExecutor(c *gin.Context) {
 	// 获取任务ID
@@ -370,3 +400,18 @@ func (ctrl *TaskController) AssignExecutor(c *gin.Context) {
 
 	utils.SuccessWithMessage(c, "分配成功", nil)
 }
+
+// respondTaskQueryError 查询语言存在语法错误或分页/字段参数无效时返回 400，返回值表示是否已处理
+func respondTaskQueryError(c *gin.Context, err error) bool {
+	var queryErr *services.TaskQueryError
+	if errors.As(err, &queryErr) {
+		utils.ErrorWithData(c, 400, queryErr.Error(), queryErr)
+		return true
+	}
+	var paramErr *services.TaskListParamError
+	if errors.As(err, &paramErr) {
+		utils.ErrorWithData(c, 400, paramErr.Error(), paramErr)
+		return true
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"RHPRo-Task/dto"
 	"RHPRo-Task/services"
 	"RHPRo-Task/utils"
 	"strconv"
@@ -9,12 +10,14 @@ import (
 )
 
 type TaskDetailController struct {
-	detailService *services.TaskDetailService
+	detailService  *services.TaskDetailService
+	compareService *services.VersionCompareService
 }
 
 func NewTaskDetailController() *TaskDetailController {
 	return &TaskDetailController{
-		detailService: &services.TaskDetailService{},
+		detailService:  &services.TaskDetailService{},
+		compareService: &services.VersionCompareService{},
 	}
 }
 
@@ -111,6 +114,80 @@ func (ctrl *TaskDetailController) GetTaskExecutionPlans(c *gin.Context) {
 	utils.Success(c, plans)
 }
 
+// CompareSolutions 对比任务的两个方案版本
+// @Summary 对比任务的两个方案版本
+// @Description 返回标题/内容/脑图 Markdown 的逐行差异、脑图链接等字段变更以及附件变更。不指定 from 时默认对比 to 之前最近一次被驳回的版本（没有则为上一版本），不指定 to 时为最新版本
+// @Tags 任务详情
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param from query int false "基准版本号"
+// @Param to query int false "目标版本号"
+// @Success 200 {object} dto.SolutionCompareResponse "对比成功"
+// @Failure 400 {object} map[string]interface{} "参数错误或版本不存在"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /tasks/{id}/solutions/compare [get]
+func (ctrl *TaskDetailController) CompareSolutions(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	var query dto.VersionCompareQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	result, err := ctrl.compareService.CompareSolutions(uint(taskID), &query)
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// CompareExecutionPlans 对比任务的两个执行计划版本
+// @Summary 对比任务的两个执行计划版本
+// @Description 返回文本字段的逐行差异、实施步骤 JSON 的结构化差异、目标的新增/删除/修改以及附件变更。不指定 from 时默认对比 to 之前最近一次被驳回的版本（没有则为上一版本），不指定 to 时为最新版本
+// @Tags 任务详情
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param from query int false "基准版本号"
+// @Param to query int false "目标版本号"
+// @Success 200 {object} dto.ExecutionPlanCompareResponse "对比成功"
+// @Failure 400 {object} map[string]interface{} "参数错误或版本不存在"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /tasks/{id}/execution-plans/compare [get]
+func (ctrl *TaskDetailController) CompareExecutionPlans(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	var query dto.VersionCompareQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	result, err := ctrl.compareService.CompareExecutionPlans(uint(taskID), &query)
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
 // GetTaskReviewHistory 获取任务的所有审核历史
 // @Summary 获取任务的所有审核历史
 // @Description 查询任务的所有审核会话、审核记录和陪审团信息，包括已完成和进行中的审核
@@ -237,7 +237,7 @@ func (ctrl *TaskFlowController) SubmitExecutionPlan(c *gin.Context) {
 
 // InitiateReview 发起审核
 // @Summary 发起审核
-// @Description 创建人发起方案或执行计划审核
+// @Description 创建人发起方案或执行计划审核；threshold/weighted 投票模式需指定陪审团成员或评审专家和所需批准数；陪审团/投票模式可指定响应截止时间和截止后的处理策略
 // @Tags 任务流程
 // @Accept json
 // @Produce json
@@ -277,7 +277,7 @@ func (ctrl *TaskFlowController) InitiateReview(c *gin.Context) {
 
 // SubmitReviewOpinion 提交审核意见
 // @Summary 提交审核意见
-// @Description 陪审团成员提交审核意见
+// @Description 陪审团成员提交审核意见，审核绑定评分标准时需为每个维度打分；门槛投票/加权投票模式下赞成票达到所需批准数或驳回已成定局时自动结束审核
 // @Tags 任务流程
 // @Accept json
 // @Produce json
@@ -316,7 +316,7 @@ func (ctrl *TaskFlowController) SubmitReviewOpinion(c *gin.Context) {
 
 // FinalizeReview 最终决策
 // @Summary 最终决策
-// @Description 创建人做出最终审核决策
+// @Description 创建人做出最终审核决策，评分标准配置了最低分时加权总分达标才能通过；门槛投票/加权投票模式下需设置 override 越过投票结果，并记录越权日志；响应截止后升级的会话部门负责人也可决策，仍有成员未响应时记录法定人数不足
 // @Tags 任务流程
 // @Accept json
 // @Produce json
@@ -355,7 +355,7 @@ func (ctrl *TaskFlowController) FinalizeReview(c *gin.Context) {
 
 // GetReviewSession 获取审核会话详情
 // @Summary 获取审核会话详情
-// @Description 查看审核会话的详细信息和投票情况
+// @Description 查看审核会话的详细信息、投票情况和评分汇总（各维度平均分、极差和加权总分）
 // @Tags 任务流程
 // @Accept json
 // @Produce json
@@ -381,7 +381,7 @@ func (ctrl *TaskFlowController) GetReviewSession(c *gin.Context) {
 
 // InviteJury 邀请陪审团
 // @Summary 邀请陪审团
-// @Description 创建人将单人审核转为陪审团审核
+// @Description 创建人将单人审核转为陪审团或投票审核，可同时邀请评审专家并设置响应截止时间和截止处理策略
 // @Tags 任务流程
 // @Accept json
 // @Produce json
@@ -410,7 +410,7 @@ func (ctrl *TaskFlowController) InviteJury(c *gin.Context) {
 	}
 	userID := userIDValue.(uint)
 
-	if err := ctrl.flowService.InviteJuryMembers(uint(sessionID), userID, req.JuryMemberIDs, req.RequiredApprovals); err != nil {
+	if err := ctrl.flowService.InviteJuryMembers(uint(sessionID), userID, &req); err != nil {
 		utils.Error(c, 400, err.Error())
 		return
 	}
@@ -536,8 +536,8 @@ func (ctrl *TaskFlowController) GetTaskAllowedTransitions(c *gin.Context) {
 func (ctrl *TaskFlowController) determineUserRoles(taskContext *dto.TaskContext, userID uint) []string {
 	roles := []string{}
 
-	// 检查是否为创建者
-	if taskContext.CreatorID == userID {
+	// 检查是否为创建者（含委托生效期间创建人的代理人）
+	if taskContext.CreatorID == userID || ctrl.taskService.IsCreatorDelegate(taskContext.TaskID, userID) {
 		roles = append(roles, "creator")
 	}
 
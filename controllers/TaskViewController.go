@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskViewController struct {
+	viewService *services.TaskViewService
+}
+
+func NewTaskViewController() *TaskViewController {
+	return &TaskViewController{
+		viewService: &services.TaskViewService{},
+	}
+}
+
+// CreateView 创建保存视图
+// @Summary 创建保存视图
+// @Description 保存一组命名的任务筛选条件、排序和展示列，可选择共享给自己所属或负责的部门
+// @Tags 任务视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param view body dto.TaskViewRequest true "视图信息"
+// @Success 200 {object} dto.TaskViewResponse "创建成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "只能共享给自己所属或负责的部门"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /task-views [post]
+func (ctrl *TaskViewController) CreateView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req dto.TaskViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	view, err := ctrl.viewService.CreateView(userID.(uint), &req)
+	if err != nil {
+		if respondTaskViewError(c, err) {
+			return
+		}
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", view)
+}
+
+// GetViewList 获取保存视图列表
+// @Summary 获取保存视图列表
+// @Description 获取当前用户自己的视图以及共享到所属/负责部门的视图
+// @Tags 任务视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.TaskViewResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /task-views [get]
+func (ctrl *TaskViewController) GetViewList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	views, err := ctrl.viewService.GetViewList(userID.(uint))
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.Success(c, views)
+}
+
+// GetView 获取保存视图详情
+// @Summary 获取保存视图详情
+// @Description 获取指定视图的筛选条件、排序和展示列
+// @Tags 任务视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "视图ID"
+// @Success 200 {object} dto.TaskViewResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 404 {object} map[string]interface{} "视图不存在或无权访问"
+// @Router /task-views/{id} [get]
+func (ctrl *TaskViewController) GetView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的视图ID")
+		return
+	}
+
+	view, err := ctrl.viewService.GetView(uint(viewID), userID.(uint))
+	if err != nil {
+		utils.Error(c, 404, err.Error())
+		return
+	}
+
+	utils.Success(c, view)
+}
+
+// UpdateView 更新保存视图
+// @Summary 更新保存视图
+// @Description 更新视图的名称、筛选条件、排序、展示列和共享部门（仅所有者）
+// @Tags 任务视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "视图ID"
+// @Param view body dto.TaskViewRequest true "视图信息"
+// @Success 200 {object} dto.TaskViewResponse "更新成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "不是视图所有者或共享部门无权限"
+// @Failure 404 {object} map[string]interface{} "视图不存在"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /task-views/{id} [put]
+func (ctrl *TaskViewController) UpdateView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的视图ID")
+		return
+	}
+
+	var req dto.TaskViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	view, err := ctrl.viewService.UpdateView(uint(viewID), userID.(uint), &req)
+	if err != nil {
+		if respondTaskViewError(c, err) {
+			return
+		}
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", view)
+}
+
+// DeleteView 删除保存视图
+// @Summary 删除保存视图
+// @Description 删除指定视图（仅所有者）
+// @Tags 任务视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "视图ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "不是视图所有者"
+// @Failure 404 {object} map[string]interface{} "视图不存在"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /task-views/{id} [delete]
+func (ctrl *TaskViewController) DeleteView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的视图ID")
+		return
+	}
+
+	if err := ctrl.viewService.DeleteView(uint(viewID), userID.(uint)); err != nil {
+		if respondTaskViewError(c, err) {
+			return
+		}
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// RunView 执行保存视图
+// @Summary 执行保存视图
+// @Description 按视图保存的筛选条件和排序查询任务，结果按当前用户自己的可见范围过滤（共享视图同样适用）
+// @Tags 任务视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "视图ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} dto.RunTaskViewResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "无权访问该视图"
+// @Failure 404 {object} map[string]interface{} "视图不存在"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /task-views/{id}/tasks [get]
+func (ctrl *TaskViewController) RunView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的视图ID")
+		return
+	}
+
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	result, err := ctrl.viewService.RunView(uint(viewID), userID.(uint), &pagination)
+	if err != nil {
+		if respondTaskViewError(c, err) {
+			return
+		}
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// respondTaskViewError 将视图的归属、访问权限和参数错误映射为对应的响应
+func respondTaskViewError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrTaskViewNotFound):
+		utils.Error(c, 404, err.Error())
+	case errors.Is(err, services.ErrTaskViewNotOwner),
+		errors.Is(err, services.ErrTaskViewForbidden),
+		errors.Is(err, services.ErrTaskViewShareDenied):
+		utils.Forbidden(c, err.Error())
+	default:
+		return respondTaskQueryError(c, err)
+	}
+	return true
+}
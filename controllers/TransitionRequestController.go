@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransitionRequestController struct {
+	requestService *services.TransitionRequestService
+}
+
+func NewTransitionRequestController() *TransitionRequestController {
+	return &TransitionRequestController{
+		requestService: &services.TransitionRequestService{},
+	}
+}
+
+// GetPendingRequests 获取待我审批的状态转换申请
+// @Summary 获取待我审批的状态转换申请
+// @Description 获取当前用户作为任务创建人或所属部门负责人需要审批的状态转换申请（不含自己提交的）
+// @Tags 状态转换审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.TransitionRequestResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /transition-requests/pending [get]
+func (ctrl *TransitionRequestController) GetPendingRequests(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	requests, err := ctrl.requestService.GetPendingForApprover(userID.(uint))
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, requests)
+}
+
+// GetMyRequests 获取我提交的状态转换申请
+// @Summary 获取我提交的状态转换申请
+// @Description 获取当前用户提交的状态转换申请，可按申请状态和任务筛选
+// @Tags 状态转换审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "申请状态：pending/approved/rejected/cancelled"
+// @Param task_id query int false "任务ID"
+// @Success 200 {array} dto.TransitionRequestResponse "查询成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /transition-requests/mine [get]
+func (ctrl *TransitionRequestController) GetMyRequests(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	var query dto.TransitionRequestQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	requests, err := ctrl.requestService.GetMyRequests(userID.(uint), &query)
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, requests)
+}
+
+// DecideRequest 审批状态转换申请
+// @Summary 审批状态转换申请
+// @Description 任务创建人或所属部门负责人审批状态转换申请。通过时重新校验任务状态、转换规则和守卫后变更任务状态并执行后置动作；驳回时任务状态不变
+// @Tags 状态转换审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "申请ID"
+// @Param decision body dto.FinalizeReviewRequest true "审批结果"
+// @Success 200 {object} map[string]interface{} "审批成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "审批失败"
+// @Router /transition-requests/{id}/decide [post]
+func (ctrl *TransitionRequestController) DecideRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的申请ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req dto.FinalizeReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	if err := ctrl.requestService.DecideRequest(uint(id), userID.(uint), req.Approved, req.Comment); err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "审批成功", nil)
+}
+
+// CancelRequest 撤回状态转换申请
+// @Summary 撤回状态转换申请
+// @Description 申请人撤回自己提交的待审批申请，关联的审核会话同时取消
+// @Tags 状态转换审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "申请ID"
+// @Success 200 {object} map[string]interface{} "撤回成功"
+// @Failure 400 {object} map[string]interface{} "无效的申请ID"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "撤回失败"
+// @Router /transition-requests/{id}/cancel [post]
+func (ctrl *TransitionRequestController) CancelRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的申请ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.requestService.CancelRequest(uint(id), userID.(uint)); err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "撤回成功", nil)
+}
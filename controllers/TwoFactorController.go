@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	twoFactorService *services.TwoFactorService
+}
+
+func NewTwoFactorController() *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorService: &services.TwoFactorService{},
+	}
+}
+
+// GetStatus 获取两步验证状态
+// @Summary 获取两步验证状态
+// @Description 查询当前用户是否已启用两步验证、是否必须启用以及剩余恢复码数量
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TwoFactorStatusResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /2fa [get]
+func (ctrl *TwoFactorController) GetStatus(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	status, err := ctrl.twoFactorService.GetStatus(userID.(uint))
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, status)
+}
+
+// Enroll 发起绑定两步验证
+// @Summary 发起绑定两步验证
+// @Description 生成 TOTP 密钥，返回配置链接和二维码，在验证器 App 中扫码添加后调用启用接口确认
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TwoFactorEnrollResponse "密钥和二维码"
+// @Failure 400 {object} map[string]interface{} "已启用两步验证"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /2fa/enroll [post]
+func (ctrl *TwoFactorController) Enroll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	response, err := ctrl.twoFactorService.Enroll(userID.(uint))
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// Enable 启用两步验证
+// @Summary 启用两步验证
+// @Description 输入验证器中的动态验证码确认绑定，启用后返回恢复码（仅显示一次，请妥善保存）
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeRequest true "动态验证码"
+// @Success 200 {object} dto.RecoveryCodesResponse "已启用"
+// @Failure 400 {object} map[string]interface{} "参数错误或验证码错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /2fa/enable [post]
+func (ctrl *TwoFactorController) Enable(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	response, err := ctrl.twoFactorService.Enable(userID.(uint), &req, c.ClientIP())
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已启用两步验证", response)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 验证动态验证码后重新生成恢复码，旧的恢复码全部作废
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeRequest true "动态验证码"
+// @Success 200 {object} dto.RecoveryCodesResponse "新的恢复码"
+// @Failure 400 {object} map[string]interface{} "参数错误或验证码错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /2fa/recovery-codes [post]
+func (ctrl *TwoFactorController) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	response, err := ctrl.twoFactorService.RegenerateRecoveryCodes(userID.(uint), &req, c.ClientIP())
+	if err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已重新生成恢复码", response)
+}
+
+// Disable 解除两步验证
+// @Summary 解除两步验证
+// @Description 验证登录密码和动态验证码后解除两步验证；必须启用两步验证的账号（如管理员）不能解除
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorDisableRequest true "密码和动态验证码"
+// @Success 200 {object} map[string]interface{} "已解除"
+// @Failure 400 {object} map[string]interface{} "参数错误、密码或验证码错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /2fa/disable [post]
+func (ctrl *TwoFactorController) Disable(c *gin.Context) {
+	var req dto.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.twoFactorService.Disable(userID.(uint), &req, c.ClientIP()); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已解除两步验证", nil)
+}
+
+// AdminReset 重置用户的两步验证（管理员）
+// @Summary 重置用户的两步验证
+// @Description 用户丢失验证器和恢复码时，管理员清除其两步验证配置，用户下次登录时重新绑定
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} map[string]interface{} "已重置"
+// @Failure 400 {object} map[string]interface{} "参数错误"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
+// @Router /admin/users/{id}/2fa [delete]
+func (ctrl *TwoFactorController) AdminReset(c *gin.Context) {
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := ctrl.twoFactorService.AdminReset(uint(targetUserID), userID.(uint), c.ClientIP()); err != nil {
+		utils.Error(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已重置两步验证", nil)
+}
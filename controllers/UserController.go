@@ -4,6 +4,7 @@ import (
 	"RHPRo-Task/dto"
 	"RHPRo-Task/services"
 	"RHPRo-Task/utils"
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -116,6 +117,7 @@ func (ctrl *UserController) GetUserByID(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "更新失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /users/{id} [put]
 func (ctrl *UserController) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
@@ -153,6 +155,7 @@ func (ctrl *UserController) UpdateUser(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "分配失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /users/{id}/roles [post]
 func (ctrl *UserController) AssignRoles(c *gin.Context) {
 	idStr := c.Param("id")
@@ -169,7 +172,12 @@ func (ctrl *UserController) AssignRoles(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.userService.AssignRoles(uint(id), req.RoleIDs); err != nil {
+	operatorID, _ := c.Get("userID")
+	if err := ctrl.userService.AssignRoles(operatorID.(uint), uint(id), req.RoleIDs); err != nil {
+		if errors.Is(err, services.ErrRoleNotGrantable) {
+			utils.Forbidden(c, err.Error())
+			return
+		}
 		utils.Error(c, 500, err.Error())
 		return
 	}
@@ -189,6 +197,7 @@ func (ctrl *UserController) AssignRoles(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "参数验证失败"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "创建失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /users [post]
 func (ctrl *UserController) CreateUser(c *gin.Context) {
 	var req dto.RegisterRequest
@@ -226,6 +235,7 @@ func (ctrl *UserController) CreateUser(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "无效的用户ID"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "审核失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /users/{id}/approve [post]
 func (ctrl *UserController) ApproveUser(c *gin.Context) {
 	idStr := c.Param("id")
@@ -255,6 +265,7 @@ func (ctrl *UserController) ApproveUser(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "无效的用户ID"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "删除失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /users/{id} [delete]
 func (ctrl *UserController) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
@@ -284,6 +295,7 @@ func (ctrl *UserController) DeleteUser(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "无效的用户ID"
 // @Failure 401 {object} map[string]interface{} "未授权"
 // @Failure 500 {object} map[string]interface{} "操作失败"
+// @Failure 403 {object} map[string]interface{} "没有访问权限"
 // @Router /users/{id}/disable [post]
 func (ctrl *UserController) DisableUser(c *gin.Context) {
 	idStr := c.Param("id")
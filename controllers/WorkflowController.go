@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkflowController struct {
+	workflowService *services.WorkflowService
+}
+
+func NewWorkflowController() *WorkflowController {
+	return &WorkflowController{
+		workflowService: &services.WorkflowService{},
+	}
+}
+
+// GetTaskTypeList 获取任务类型列表
+// @Summary 获取任务类型列表
+// @Description 获取所有任务类型及其入口状态配置、状态数和转换规则数
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.TaskTypeDetailResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "查询失败"
+// @Router /admin/workflow/types [get]
+func (ctrl *WorkflowController) GetTaskTypeList(c *gin.Context) {
+	types, err := ctrl.workflowService.GetTaskTypeList()
+	if err != nil {
+		utils.Error(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, types)
+}
+
+// CreateTaskType 创建任务类型
+// @Summary 创建任务类型
+// @Description 创建新的任务类型，创建后通过保存流程接口配置状态和转换规则
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskType body dto.TaskTypeRequest true "任务类型信息"
+// @Success 200 {object} dto.TaskTypeDetailResponse "创建成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/workflow/types [post]
+func (ctrl *WorkflowController) CreateTaskType(c *gin.Context) {
+	var req dto.TaskTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	taskType, err := ctrl.workflowService.CreateTaskType(&req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", taskType)
+}
+
+// UpdateTaskType 更新任务类型
+// @Summary 更新任务类型
+// @Description 更新任务类型的名称、描述和入口状态（未指派/待接受/已接受），入口状态必须属于该类型
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "任务类型编码"
+// @Param taskType body dto.TaskTypeRequest true "任务类型信息"
+// @Success 200 {object} dto.TaskTypeDetailResponse "更新成功"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/workflow/types/{code} [put]
+func (ctrl *WorkflowController) UpdateTaskType(c *gin.Context) {
+	var req dto.TaskTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	taskType, err := ctrl.workflowService.UpdateTaskType(c.Param("code"), &req)
+	if err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", taskType)
+}
+
+// DeleteTaskType 删除任务类型
+// @Summary 删除任务类型
+// @Description 删除任务类型及其状态和转换规则，已有任务使用该类型时不允许删除
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "任务类型编码"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/workflow/types/{code} [delete]
+func (ctrl *WorkflowController) DeleteTaskType(c *gin.Context) {
+	if err := ctrl.workflowService.DeleteTaskType(c.Param("code")); err != nil {
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// GetWorkflow 获取任务类型流程
+// @Summary 获取任务类型流程
+// @Description 获取任务类型的状态、转换规则以及当前流程的校验结果
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "任务类型编码"
+// @Success 200 {object} dto.WorkflowResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 404 {object} map[string]interface{} "任务类型不存在"
+// @Router /admin/workflow/types/{code}/workflow [get]
+func (ctrl *WorkflowController) GetWorkflow(c *gin.Context) {
+	workflow, err := ctrl.workflowService.GetWorkflow(c.Param("code"))
+	if err != nil {
+		utils.Error(c, 404, err.Error())
+		return
+	}
+
+	utils.Success(c, workflow)
+}
+
+// SaveWorkflow 保存任务类型流程
+// @Summary 保存任务类型流程
+// @Description 整体替换任务类型的状态（含分类：todo/in_progress/done/blocked/cancelled）和转换规则（需要的角色、是否需要审批）。
+// @Description 保存前校验：至少一个初始状态和一个已完成分类的状态、转换引用的状态存在、所有状态从初始状态可达、被删除的状态没有任务使用。校验不通过时返回 400 及校验结果
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "任务类型编码"
+// @Param workflow body dto.WorkflowRequest true "流程定义"
+// @Success 200 {object} dto.WorkflowResponse "保存成功"
+// @Failure 400 {object} dto.WorkflowValidationResult "流程校验未通过"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 500 {object} map[string]interface{} "服务器错误"
+// @Router /admin/workflow/types/{code}/workflow [put]
+func (ctrl *WorkflowController) SaveWorkflow(c *gin.Context) {
+	var req dto.WorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	workflow, validation, err := ctrl.workflowService.SaveWorkflow(c.Param("code"), &req)
+	if err != nil {
+		if validation != nil {
+			utils.ErrorWithData(c, 400, err.Error(), validation)
+			return
+		}
+		utils.Error(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "保存成功", workflow)
+}
+
+// ValidateWorkflow 校验任务类型流程
+// @Summary 校验任务类型流程
+// @Description 按保存时的规则校验流程定义（不保存），返回错误列表和不可达的状态
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "任务类型编码"
+// @Param workflow body dto.WorkflowRequest true "流程定义"
+// @Success 200 {object} dto.WorkflowValidationResult "校验完成"
+// @Failure 400 {object} map[string]interface{} "参数验证失败"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Failure 404 {object} map[string]interface{} "任务类型不存在"
+// @Router /admin/workflow/types/{code}/workflow/validate [post]
+func (ctrl *WorkflowController) ValidateWorkflow(c *gin.Context) {
+	var req dto.WorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.TranslateValidationErrors(err)
+		utils.ErrorWithData(c, 400, "参数验证失败", validationErrors)
+		return
+	}
+
+	result, err := ctrl.workflowService.ValidateWorkflow(c.Param("code"), &req)
+	if err != nil {
+		utils.Error(c, 404, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// GetTransitionHooks 获取可配置的守卫和后置动作
+// @Summary 获取可配置的守卫和后置动作
+// @Description 列出已注册的转换守卫（如 has_approved_solution/all_subtasks_done/no_open_blockers）和后置动作（如 rollup_parent/notify/create_review），用于配置转换规则的 guards 和 post_actions
+// @Tags 流程设计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TransitionHooksResponse "查询成功"
+// @Failure 401 {object} map[string]interface{} "未授权"
+// @Router /admin/workflow/hooks [get]
+func (ctrl *WorkflowController) GetTransitionHooks(c *gin.Context) {
+	utils.Success(c, ctrl.workflowService.GetTransitionHooks())
+}
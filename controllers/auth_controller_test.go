@@ -151,3 +151,45 @@ func TestLogin_EmptyCredentials(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 400, resp.Code) // 参数验证失败
 }
+
+// TestRefreshToken_EmptyToken 测试刷新令牌为空
+func TestRefreshToken_EmptyToken(t *testing.T) {
+	router := testutils.SetupTestRouter()
+	authController := NewAuthController()
+	router.POST("/api/v1/auth/refresh", authController.RefreshToken)
+
+	reqBody := dto.RefreshTokenRequest{RefreshToken: ""}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/auth/refresh", reqBody)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code) // 参数验证失败
+}
+
+// TestTwoFactorLogin_MissingToken 测试两步验证登录时缺少两步验证凭证
+func TestTwoFactorLogin_MissingToken(t *testing.T) {
+	router := testutils.SetupTestRouter()
+	authController := NewAuthController()
+	router.POST("/api/v1/auth/2fa/verify", authController.TwoFactorLogin)
+
+	reqBody := dto.TwoFactorLoginRequest{Code: "123456"}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/auth/2fa/verify", reqBody)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code) // 参数验证失败
+}
+
+// TestLdapLogin_EmptyCredentials 测试 LDAP 登录时缺少用户名或密码
+func TestLdapLogin_EmptyCredentials(t *testing.T) {
+	router := testutils.SetupTestRouter()
+	authController := NewAuthController()
+	router.POST("/api/v1/auth/ldap/login", authController.LdapLogin)
+
+	reqBody := dto.LdapLoginRequest{Username: "zhangsan"}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/auth/ldap/login", reqBody)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code) // 参数验证失败
+}
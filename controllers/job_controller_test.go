@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetJobList 测试获取定时任务列表
+func TestGetJobList(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	jobController := NewJobController()
+	router.GET("/api/v1/admin/jobs", jobController.GetJobList)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/admin/jobs", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 500,
+		"Response code should be 0 or 500, got %d", resp.Code)
+}
+
+// TestTriggerJob_NotFound 测试触发不存在的定时任务
+func TestTriggerJob_NotFound(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	jobController := NewJobController()
+	router.POST("/api/v1/admin/jobs/:name/run", jobController.TriggerJob)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/admin/jobs/unknown_job/run", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.Code)
+}
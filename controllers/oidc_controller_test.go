@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOidcCallback_MissingState 测试单点登录回调时缺少 state
+func TestOidcCallback_MissingState(t *testing.T) {
+	router := testutils.SetupTestRouter()
+	ctrl := NewOidcController()
+	router.POST("/api/v1/auth/oidc/:provider/callback", ctrl.Callback)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/auth/oidc/corp/callback", map[string]interface{}{
+		"code": "SplxlOBeZQQYbYS6WxSbIA",
+	})
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code) // 参数验证失败
+}
+
+// TestOidcAuthorize_UnknownProvider 测试获取未配置的身份提供方的授权地址
+func TestOidcAuthorize_UnknownProvider(t *testing.T) {
+	router := testutils.SetupTestRouter()
+	ctrl := NewOidcController()
+	router.GET("/api/v1/auth/oidc/:provider/authorize", ctrl.Authorize)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/auth/oidc/not-configured/authorize", nil)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestOidcBind_InvalidTempToken 测试单点登录绑定手机号时临时凭证无效
+func TestOidcBind_InvalidTempToken(t *testing.T) {
+	router := testutils.SetupTestRouter()
+	ctrl := NewOidcController()
+	router.POST("/api/v1/auth/oidc/bind", ctrl.Bind)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/auth/oidc/bind", map[string]interface{}{
+		"temp_token": "invalid",
+		"mobile":     "13800138000",
+		"username":   "张三",
+		"password":   "password123",
+	})
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 401, resp.Code)
+}
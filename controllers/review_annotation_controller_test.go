@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateAnnotation_Validation 测试创建批注参数验证
+func TestCreateAnnotation_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	annotationController := NewReviewAnnotationController()
+	router.POST("/api/v1/tasks/:id/annotations", annotationController.CreateAnnotation)
+
+	tests := []struct {
+		name string
+		path string
+		body map[string]interface{}
+	}{
+		{"缺少批注内容", "/api/v1/tasks/1/annotations", map[string]interface{}{
+			"target_type": "requirement_solutions", "anchor_type": "text",
+		}},
+		{"无效的批注对象", "/api/v1/tasks/1/annotations", map[string]interface{}{
+			"target_type": "tasks", "anchor_type": "text", "content": "请补充",
+		}},
+		{"无效的锚点类型", "/api/v1/tasks/1/annotations", map[string]interface{}{
+			"target_type": "execution_plans", "anchor_type": "page", "content": "请补充",
+		}},
+		{"偏移为负数", "/api/v1/tasks/1/annotations", map[string]interface{}{
+			"target_type": "requirement_solutions", "anchor_type": "text", "field": "content",
+			"start_offset": -1, "end_offset": 3, "content": "请补充",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutils.HTTPRequest(router, "POST", tt.path, tt.body)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			resp, err := testutils.ParseResponse(w)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.Code)
+		})
+	}
+}
+
+// TestCreateAnnotation_InvalidID 测试创建批注时任务ID无效
+func TestCreateAnnotation_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	annotationController := NewReviewAnnotationController()
+	router.POST("/api/v1/tasks/:id/annotations", annotationController.CreateAnnotation)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/tasks/abc/annotations", map[string]interface{}{
+		"target_type": "requirement_solutions", "anchor_type": "text", "content": "请补充",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestReplyAnnotation_Validation 测试回复批注参数验证
+func TestReplyAnnotation_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	annotationController := NewReviewAnnotationController()
+	router.POST("/api/v1/annotations/:id/replies", annotationController.ReplyAnnotation)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/annotations/1/replies", map[string]interface{}{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+
+	w = testutils.HTTPRequest(router, "POST", "/api/v1/annotations/abc/replies", map[string]interface{}{"content": "已修改"})
+	resp, err = testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
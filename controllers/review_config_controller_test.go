@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSaveRoleWeights_Validation 测试保存角色权重参数验证
+func TestSaveRoleWeights_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	reviewConfigController := NewReviewConfigController()
+	router.PUT("/api/v1/admin/review/role-weights", reviewConfigController.SaveRoleWeights)
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"空列表", map[string]interface{}{"weights": []interface{}{}}},
+		{"未知角色", map[string]interface{}{"weights": []interface{}{
+			map[string]interface{}{"reviewer_role": "creator", "weight": 1},
+		}}},
+		{"权重为零", map[string]interface{}{"weights": []interface{}{
+			map[string]interface{}{"reviewer_role": "jury", "weight": 0},
+		}}},
+		{"权重超出范围", map[string]interface{}{"weights": []interface{}{
+			map[string]interface{}{"reviewer_role": "leader", "weight": 12},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutils.HTTPRequest(router, "PUT", "/api/v1/admin/review/role-weights", tt.body)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			resp, err := testutils.ParseResponse(w)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.Code)
+		})
+	}
+}
+
+// TestCreateRubric_Validation 测试创建评分标准参数验证
+func TestCreateRubric_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	reviewConfigController := NewReviewConfigController()
+	router.POST("/api/v1/admin/review/rubrics", reviewConfigController.CreateRubric)
+
+	criteria := []interface{}{map[string]interface{}{"name": "可行性", "weight": 1}}
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"缺少维度", map[string]interface{}{"name": "方案评审", "review_type": "solution_review", "scale_min": 1, "scale_max": 5}},
+		{"不支持的审核类型", map[string]interface{}{"name": "状态评审", "review_type": "status_review", "scale_min": 1, "scale_max": 5, "criteria": criteria}},
+		{"分值上限不大于下限", map[string]interface{}{"name": "方案评审", "review_type": "solution_review", "scale_min": 5, "scale_max": 5, "criteria": criteria}},
+		{"维度权重为零", map[string]interface{}{"name": "方案评审", "review_type": "solution_review", "scale_min": 1, "scale_max": 5,
+			"criteria": []interface{}{map[string]interface{}{"name": "可行性", "weight": 0}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutils.HTTPRequest(router, "POST", "/api/v1/admin/review/rubrics", tt.body)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			resp, err := testutils.ParseResponse(w)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.Code)
+		})
+	}
+}
+
+// TestCreateApprovalChain_Validation 测试创建审批链模板参数验证
+func TestCreateApprovalChain_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	reviewConfigController := NewReviewConfigController()
+	router.POST("/api/v1/admin/review/approval-chains", reviewConfigController.CreateApprovalChain)
+
+	stages := []interface{}{map[string]interface{}{"name": "创建人审批", "approver_type": "creator"}}
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"缺少阶段", map[string]interface{}{"name": "重要需求", "review_type": "solution_review"}},
+		{"不支持的审核类型", map[string]interface{}{"name": "重要需求", "review_type": "status_review", "stages": stages}},
+		{"优先级超出范围", map[string]interface{}{"name": "重要需求", "review_type": "plan_review", "min_priority": 5, "stages": stages}},
+		{"未知审批人类型", map[string]interface{}{"name": "重要需求", "review_type": "plan_review",
+			"stages": []interface{}{map[string]interface{}{"name": "经理审批", "approver_type": "manager"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutils.HTTPRequest(router, "POST", "/api/v1/admin/review/approval-chains", tt.body)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			resp, err := testutils.ParseResponse(w)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.Code)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateDelegation_Validation 测试创建审核委托参数验证
+func TestCreateDelegation_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	delegationController := NewReviewDelegationController()
+	router.POST("/api/v1/delegations", delegationController.CreateDelegation)
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"缺少代理人", map[string]interface{}{"start_at": "2024-05-01", "end_at": "2024-05-07"}},
+		{"缺少开始时间", map[string]interface{}{"delegate_id": 2, "end_at": "2024-05-07"}},
+		{"缺少结束时间", map[string]interface{}{"delegate_id": 2, "start_at": "2024-05-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutils.HTTPRequest(router, "POST", "/api/v1/delegations", tt.body)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			resp, err := testutils.ParseResponse(w)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.Code)
+		})
+	}
+}
+
+// TestGetDelegations_Validation 测试审核委托查询参数验证
+func TestGetDelegations_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	delegationController := NewReviewDelegationController()
+	router.GET("/api/v1/delegations", delegationController.GetDelegations)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/delegations?status=pending", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestRevokeDelegation_InvalidID 测试撤销审核委托的无效ID
+func TestRevokeDelegation_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	delegationController := NewReviewDelegationController()
+	router.POST("/api/v1/delegations/:id/revoke", delegationController.RevokeDelegation)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/delegations/abc/revoke", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
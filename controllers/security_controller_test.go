@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnlockLogin_InvalidIP 测试解除登录锁定时IP格式无效
+func TestUnlockLogin_InvalidIP(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	ctrl := NewSecurityController()
+	router.POST("/api/v1/admin/security/unlock-login", ctrl.UnlockLogin)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/admin/security/unlock-login", map[string]interface{}{
+		"ip": "not-an-ip",
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRevokeMySession_InvalidID 测试结束会话时会话ID无效
+func TestRevokeMySession_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "testuser")
+	ctrl := NewSessionController()
+	router.DELETE("/api/v1/sessions/:id", ctrl.RevokeMySession)
+
+	w := testutils.HTTPRequest(router, "DELETE", "/api/v1/sessions/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestGetUserSessions_InvalidID 测试管理员查询会话时用户ID无效
+func TestGetUserSessions_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	ctrl := NewSessionController()
+	router.GET("/api/v1/admin/users/:id/sessions", ctrl.GetUserSessions)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/admin/users/abc/sessions", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetSLABreaches 测试获取 SLA 违约事件列表
+func TestGetSLABreaches(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	slaController := NewSLAController()
+	router.GET("/api/v1/sla/breaches", slaController.GetBreaches)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/sla/breaches?page=1&page_size=10", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 500,
+		"Response code should be 0 or 500, got %d", resp.Code)
+}
+
+// TestGetSLATaskTimers_InvalidID 测试无效的任务ID
+func TestGetSLATaskTimers_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	slaController := NewSLAController()
+	router.GET("/api/v1/sla/tasks/:id", slaController.GetTaskTimers)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/sla/tasks/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestCreateSLAPolicy_InvalidRequest 测试创建 SLA 策略参数验证
+func TestCreateSLAPolicy_InvalidRequest(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	slaController := NewSLAController()
+	router.POST("/api/v1/admin/sla/policies", slaController.CreatePolicy)
+
+	reqBody := map[string]interface{}{
+		"name":           "待接受超时",
+		"task_type_code": "requirement",
+		"status_code":    "req_pending_accept",
+		"priority":       5,
+		"max_minutes":    0,
+	}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/admin/sla/policies", reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestCreateBusinessCalendar_InvalidRequest 测试创建工作日历参数验证
+func TestCreateBusinessCalendar_InvalidRequest(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	slaController := NewSLAController()
+	router.POST("/api/v1/admin/sla/calendars", slaController.CreateCalendar)
+
+	reqBody := map[string]interface{}{
+		"name":       "研发部日历",
+		"work_start": "9点",
+		"work_days":  []int{1, 2, 8},
+		"holidays": []map[string]interface{}{
+			{"date": "2025/10/01", "name": "国庆节"},
+		},
+	}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/admin/sla/calendars", reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
@@ -115,6 +115,34 @@ func TestGetTaskList_ByStatus(t *testing.T) {
 	assert.True(t, resp.Code == 0 || resp.Code == 500)
 }
 
+// TestGetTaskList_Cursor 测试游标分页并按需加载关联数据
+func TestGetTaskList_Cursor(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	taskController := NewTaskController()
+	router.GET("/api/v1/tasks", taskController.GetTaskList)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/tasks?pagination=cursor&page_size=20&fields=creator,executor,tags", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 500)
+}
+
+// TestGetTaskList_InvalidFields 测试不支持的 fields 返回参数错误
+func TestGetTaskList_InvalidFields(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	taskController := NewTaskController()
+	router.GET("/api/v1/tasks", taskController.GetTaskList)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/tasks?fields=owner", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
 // TestGetTaskByID_Success 测试根据ID获取任务
 func TestGetTaskByID_Success(t *testing.T) {
 	router := testutils.SetupTestRouterWithAuth(1, "admin")
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompareVersions_Validation 测试版本对比参数验证
+func TestCompareVersions_Validation(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	detailController := NewTaskDetailController()
+	router.GET("/api/v1/tasks/:id/solutions/compare", detailController.CompareSolutions)
+	router.GET("/api/v1/tasks/:id/execution-plans/compare", detailController.CompareExecutionPlans)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"版本号非数字", "/api/v1/tasks/1/solutions/compare?from=v1"},
+		{"版本号为负数", "/api/v1/tasks/1/execution-plans/compare?to=-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutils.HTTPRequest(router, "GET", tt.path, nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			resp, err := testutils.ParseResponse(w)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.Code)
+		})
+	}
+}
+
+// TestCompareVersions_InvalidID 测试版本对比时任务ID无效
+func TestCompareVersions_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	detailController := NewTaskDetailController()
+	router.GET("/api/v1/tasks/:id/solutions/compare", detailController.CompareSolutions)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/tasks/abc/solutions/compare", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateTaskView_Success 测试创建保存视图
+func TestCreateTaskView_Success(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	viewController := NewTaskViewController()
+	router.POST("/api/v1/task-views", viewController.CreateView)
+
+	priority := 3
+	reqBody := dto.TaskViewRequest{
+		Name:   "高优先级需求",
+		Source: "all",
+		Filters: dto.TaskViewFilters{
+			TaskTypeCode: "requirement",
+			Priority:     &priority,
+		},
+		SortBy:    "expected_end_date",
+		SortOrder: "asc",
+		Columns:   []string{"task_no", "title", "status_code", "expected_end_date"},
+	}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/task-views", reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 500,
+		"Response code should be 0 or 500, got %d", resp.Code)
+}
+
+// TestCreateTaskView_InvalidInput 测试创建视图缺少名称
+func TestCreateTaskView_InvalidInput(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	viewController := NewTaskViewController()
+	router.POST("/api/v1/task-views", viewController.CreateView)
+
+	reqBody := dto.TaskViewRequest{
+		Source: "unknown",
+	}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/task-views", reqBody)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestCreateTaskView_InvalidSort 测试创建视图使用不支持的排序字段
+func TestCreateTaskView_InvalidSort(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	viewController := NewTaskViewController()
+	router.POST("/api/v1/task-views", viewController.CreateView)
+
+	reqBody := dto.TaskViewRequest{
+		Name:   "非法排序",
+		SortBy: "password",
+	}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/task-views", reqBody)
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestGetTaskViewList_Success 测试获取视图列表
+func TestGetTaskViewList_Success(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	viewController := NewTaskViewController()
+	router.GET("/api/v1/task-views", viewController.GetViewList)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/task-views", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 500,
+		"Response code should be 0 or 500, got %d", resp.Code)
+}
+
+// TestRunTaskView_InvalidID 测试执行视图无效ID
+func TestRunTaskView_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	viewController := NewTaskViewController()
+	router.GET("/api/v1/task-views/:id/tasks", viewController.RunView)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/task-views/abc/tasks", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestRunTaskView_NotFound 测试执行不存在的视图
+func TestRunTaskView_NotFound(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	viewController := NewTaskViewController()
+	router.GET("/api/v1/task-views/:id/tasks", viewController.RunView)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/task-views/99999/tasks?page=1&page_size=10", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.Code)
+}
+
+// TestDeleteTaskView_NotFound 测试删除不存在的视图
+func TestDeleteTaskView_NotFound(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	viewController := NewTaskViewController()
+	router.DELETE("/api/v1/task-views/:id", viewController.DeleteView)
+
+	w := testutils.HTTPRequest(router, "DELETE", "/api/v1/task-views/99999", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.Code)
+}
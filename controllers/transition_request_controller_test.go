@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetPendingTransitionRequests 测试获取待我审批的状态转换申请
+func TestGetPendingTransitionRequests(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	requestController := NewTransitionRequestController()
+	router.GET("/api/v1/transition-requests/pending", requestController.GetPendingRequests)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/transition-requests/pending", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 500,
+		"Response code should be 0 or 500, got %d", resp.Code)
+}
+
+// TestGetMyTransitionRequests_InvalidStatus 测试按不合法的申请状态筛选
+func TestGetMyTransitionRequests_InvalidStatus(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	requestController := NewTransitionRequestController()
+	router.GET("/api/v1/transition-requests/mine", requestController.GetMyRequests)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/transition-requests/mine?status=unknown", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestDecideTransitionRequest_NotFound 测试审批不存在的申请
+func TestDecideTransitionRequest_NotFound(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	requestController := NewTransitionRequestController()
+	router.POST("/api/v1/transition-requests/:id/decide", requestController.DecideRequest)
+
+	reqBody := dto.FinalizeReviewRequest{Approved: true, Comment: "同意"}
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/transition-requests/999999/decide", reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.Code)
+}
+
+// TestCancelTransitionRequest_InvalidID 测试撤回时申请ID无效
+func TestCancelTransitionRequest_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	requestController := NewTransitionRequestController()
+	router.POST("/api/v1/transition-requests/:id/cancel", requestController.CancelRequest)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/transition-requests/abc/cancel", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnableTwoFactor_InvalidCode 测试启用两步验证时验证码格式无效
+func TestEnableTwoFactor_InvalidCode(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "testuser")
+	ctrl := NewTwoFactorController()
+	router.POST("/api/v1/2fa/enable", ctrl.Enable)
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/2fa/enable", map[string]interface{}{
+		"code": "12ab",
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestAdminResetTwoFactor_InvalidID 测试管理员重置两步验证时用户ID无效
+func TestAdminResetTwoFactor_InvalidID(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	ctrl := NewTwoFactorController()
+	router.DELETE("/api/v1/admin/users/:id/2fa", ctrl.AdminReset)
+
+	w := testutils.HTTPRequest(router, "DELETE", "/api/v1/admin/users/abc/2fa", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/tests/testutils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetTaskTypeList_Success 测试获取任务类型列表
+func TestGetTaskTypeList_Success(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	workflowController := NewWorkflowController()
+	router.GET("/api/v1/admin/workflow/types", workflowController.GetTaskTypeList)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/admin/workflow/types", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 500,
+		"Response code should be 0 or 500, got %d", resp.Code)
+}
+
+// TestValidateWorkflow_Success 测试校验流程定义
+func TestValidateWorkflow_Success(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	workflowController := NewWorkflowController()
+	router.POST("/api/v1/admin/workflow/types/:code/workflow/validate", workflowController.ValidateWorkflow)
+
+	reqBody := dto.WorkflowRequest{
+		Statuses: []dto.WorkflowStatusItem{
+			{Code: "unit_draft", Name: "草稿", Category: "todo", IsInitial: true},
+			{Code: "unit_completed", Name: "已完成", Category: "done"},
+		},
+		Transitions: []dto.WorkflowTransitionItem{
+			{FromStatusCode: "unit_draft", ToStatusCode: "unit_completed", RequiredRole: "executor"},
+		},
+	}
+
+	w := testutils.HTTPRequest(router, "POST", "/api/v1/admin/workflow/types/unit_task/workflow/validate", reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.True(t, resp.Code == 0 || resp.Code == 404,
+		"Response code should be 0 or 404, got %d", resp.Code)
+}
+
+// TestSaveWorkflow_InvalidCategory 测试保存流程时状态分类不合法
+func TestSaveWorkflow_InvalidCategory(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	workflowController := NewWorkflowController()
+	router.PUT("/api/v1/admin/workflow/types/:code/workflow", workflowController.SaveWorkflow)
+
+	reqBody := dto.WorkflowRequest{
+		Statuses: []dto.WorkflowStatusItem{
+			{Code: "unit_draft", Name: "草稿", Category: "archived", IsInitial: true},
+		},
+	}
+
+	w := testutils.HTTPRequest(router, "PUT", "/api/v1/admin/workflow/types/unit_task/workflow", reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Code)
+}
+
+// TestGetTransitionHooks 测试获取可配置的守卫和后置动作
+func TestGetTransitionHooks(t *testing.T) {
+	router := testutils.SetupTestRouterWithAuth(1, "admin")
+	workflowController := NewWorkflowController()
+	router.GET("/api/v1/admin/workflow/hooks", workflowController.GetTransitionHooks)
+
+	w := testutils.HTTPRequest(router, "GET", "/api/v1/admin/workflow/hooks", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := testutils.ParseResponse(w)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Code)
+}
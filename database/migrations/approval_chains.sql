@@ -0,0 +1,111 @@
+-- ============================================
+-- 审批链迁移脚本
+-- Approval Chains Migration
+-- ============================================
+
+-- ============================================
+-- 审批链模板表 (approval_chain_templates)
+-- ============================================
+DROP TABLE IF EXISTS "public"."approval_chain_templates" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."approval_chain_templates_id_seq";
+CREATE TABLE "public"."approval_chain_templates" (
+    "id" int4 NOT NULL DEFAULT nextval('approval_chain_templates_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "review_type" varchar(50) NOT NULL,
+    "task_type_code" varchar(50),
+    "min_priority" int4,
+    "match_cross_department" bool DEFAULT false,
+    "sort_order" int4 DEFAULT 0,
+    "is_active" bool DEFAULT true,
+    "description" varchar(255),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."approval_chain_templates" IS '审批链模板表（按规则匹配任务，方案/计划按阶段依次审批）';
+COMMENT ON COLUMN "public"."approval_chain_templates"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."approval_chain_templates"."name" IS '模板名称';
+COMMENT ON COLUMN "public"."approval_chain_templates"."review_type" IS '适用的审核类型：solution_review-思路方案审核，plan_review-执行计划审核';
+COMMENT ON COLUMN "public"."approval_chain_templates"."task_type_code" IS '适用的任务类型编码（为空表示不限）';
+COMMENT ON COLUMN "public"."approval_chain_templates"."min_priority" IS '触发条件：任务优先级达到该值（为空表示不按优先级触发）';
+COMMENT ON COLUMN "public"."approval_chain_templates"."match_cross_department" IS '触发条件：跨部门任务（与优先级条件满足其一即可，均未设置时匹配全部任务）';
+COMMENT ON COLUMN "public"."approval_chain_templates"."sort_order" IS '匹配顺序（多个模板匹配时取值大的）';
+COMMENT ON COLUMN "public"."approval_chain_templates"."is_active" IS '是否启用';
+COMMENT ON COLUMN "public"."approval_chain_templates"."description" IS '描述';
+COMMENT ON COLUMN "public"."approval_chain_templates"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."approval_chain_templates"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."approval_chain_templates"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_approval_chain_templates_review_type" ON "public"."approval_chain_templates" USING btree ("review_type" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_approval_chain_templates_deleted_at" ON "public"."approval_chain_templates" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+CREATE TRIGGER "update_approval_chain_templates_updated_at"
+    BEFORE UPDATE ON "public"."approval_chain_templates"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 审批链阶段表 (approval_chain_stages)
+-- ============================================
+DROP TABLE IF EXISTS "public"."approval_chain_stages" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."approval_chain_stages_id_seq";
+CREATE TABLE "public"."approval_chain_stages" (
+    "id" int4 NOT NULL DEFAULT nextval('approval_chain_stages_id_seq'::regclass),
+    "template_id" int4 NOT NULL,
+    "stage_no" int4 NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "approver_type" varchar(50) NOT NULL,
+    "approver_department_id" int4,
+    "approver_user_id" int4,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."approval_chain_stages" IS '审批链阶段表（阶段内任一审批人通过即进入下一阶段）';
+COMMENT ON COLUMN "public"."approval_chain_stages"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."approval_chain_stages"."template_id" IS '审批链模板ID';
+COMMENT ON COLUMN "public"."approval_chain_stages"."stage_no" IS '阶段序号（从 1 开始）';
+COMMENT ON COLUMN "public"."approval_chain_stages"."name" IS '阶段名称';
+COMMENT ON COLUMN "public"."approval_chain_stages"."approver_type" IS '审批人类型：executor_leader-执行人部门负责人，creator_leader-创建人部门负责人，task_leader-任务所属部门负责人，department_leader-指定部门负责人，creator-创建人，user-指定用户';
+COMMENT ON COLUMN "public"."approval_chain_stages"."approver_department_id" IS '指定部门ID（department_leader 类型）';
+COMMENT ON COLUMN "public"."approval_chain_stages"."approver_user_id" IS '指定用户ID（user 类型）';
+COMMENT ON COLUMN "public"."approval_chain_stages"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."approval_chain_stages"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."approval_chain_stages"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_approval_chain_stages_template_id" ON "public"."approval_chain_stages" USING btree ("template_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_approval_chain_stages_deleted_at" ON "public"."approval_chain_stages" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."approval_chain_stages" ADD CONSTRAINT "approval_chain_stages_template_id_fkey"
+    FOREIGN KEY ("template_id") REFERENCES "public"."approval_chain_templates" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."approval_chain_stages" ADD CONSTRAINT "approval_chain_stages_approver_department_id_fkey"
+    FOREIGN KEY ("approver_department_id") REFERENCES "public"."departments" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+ALTER TABLE "public"."approval_chain_stages" ADD CONSTRAINT "approval_chain_stages_approver_user_id_fkey"
+    FOREIGN KEY ("approver_user_id") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_approval_chain_stages_updated_at"
+    BEFORE UPDATE ON "public"."approval_chain_stages"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 审核会话：审批链阶段
+-- ============================================
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "chain_template_id" int4;
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "chain_stage_no" int4 DEFAULT 0;
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "chain_stage_name" varchar(100);
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "stage_approver_ids" jsonb;
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "prev_stage_session_id" int4;
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "next_stage_session_id" int4;
+COMMENT ON COLUMN "public"."review_sessions"."chain_template_id" IS '审批链模板ID（按审批链逐级审批时）';
+COMMENT ON COLUMN "public"."review_sessions"."chain_stage_no" IS '审批链阶段序号（0 表示不属于审批链）';
+COMMENT ON COLUMN "public"."review_sessions"."chain_stage_name" IS '审批链阶段名称';
+COMMENT ON COLUMN "public"."review_sessions"."stage_approver_ids" IS '本阶段的审批人用户ID（创建阶段会话时确定）';
+COMMENT ON COLUMN "public"."review_sessions"."prev_stage_session_id" IS '上一阶段的审核会话ID';
+COMMENT ON COLUMN "public"."review_sessions"."next_stage_session_id" IS '下一阶段的审核会话ID（本阶段通过后创建）';
+CREATE INDEX IF NOT EXISTS "idx_review_sessions_chain_template_id" ON "public"."review_sessions" USING btree ("chain_template_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX IF NOT EXISTS "idx_review_sessions_prev_stage_session_id" ON "public"."review_sessions" USING btree ("prev_stage_session_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
@@ -0,0 +1,51 @@
+-- ============================================
+-- 安全审计日志迁移脚本
+-- Audit Logs Migration
+-- ============================================
+
+-- ============================================
+-- 安全审计日志表 (audit_logs)
+-- ============================================
+DROP TABLE IF EXISTS "public"."audit_logs" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."audit_logs_id_seq";
+CREATE TABLE "public"."audit_logs" (
+    "id" int4 NOT NULL DEFAULT nextval('audit_logs_id_seq'::regclass),
+    "action" varchar(50) NOT NULL,
+    "user_id" int4,
+    "actor_id" int4,
+    "target" varchar(100),
+    "ip" varchar(64),
+    "detail" varchar(500),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."audit_logs" IS '安全审计日志表（登录锁定、两步验证变更等安全事件）';
+COMMENT ON COLUMN "public"."audit_logs"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."audit_logs"."action" IS '事件类型：login_locked-账号锁定，login_ip_locked-IP锁定，login_unlocked-解除账号锁定，login_ip_unlocked-解除IP锁定，two_factor_enabled-启用两步验证，two_factor_disabled-解除两步验证，recovery_code_used-使用恢复码登录，recovery_codes_renewed-重新生成恢复码，directory_user_disabled-目录同步禁用用户，identity_linked-关联单点登录身份';
+COMMENT ON COLUMN "public"."audit_logs"."user_id" IS '相关用户ID（IP 锁定等不对应用户时为空）';
+COMMENT ON COLUMN "public"."audit_logs"."actor_id" IS '操作人ID（系统自动触发时为空）';
+COMMENT ON COLUMN "public"."audit_logs"."target" IS '事件对象（手机号、IP）';
+COMMENT ON COLUMN "public"."audit_logs"."ip" IS '请求来源IP';
+COMMENT ON COLUMN "public"."audit_logs"."detail" IS '事件详情';
+COMMENT ON COLUMN "public"."audit_logs"."created_at" IS '创建时间（事件发生时间）';
+COMMENT ON COLUMN "public"."audit_logs"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."audit_logs"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_audit_logs_action" ON "public"."audit_logs" USING btree ("action" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_audit_logs_user_id" ON "public"."audit_logs" USING btree ("user_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_audit_logs_target" ON "public"."audit_logs" USING btree ("target" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_audit_logs_created_at" ON "public"."audit_logs" USING btree ("created_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+CREATE INDEX "idx_audit_logs_deleted_at" ON "public"."audit_logs" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."audit_logs" ADD CONSTRAINT "audit_logs_user_id_fkey"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+ALTER TABLE "public"."audit_logs" ADD CONSTRAINT "audit_logs_actor_id_fkey"
+    FOREIGN KEY ("actor_id") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_audit_logs_updated_at"
+    BEFORE UPDATE ON "public"."audit_logs"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,51 @@
+-- ============================================
+-- 全文检索迁移脚本
+-- Full-Text Search Migration
+-- 说明：
+--   1. 优先使用 zhparser 中文分词创建 chinese 文本检索配置（需数据库已安装 zhparser 扩展）
+--   2. 同时启用 pg_trgm 作为降级方案（未安装 zhparser 时按三元组模糊匹配）
+--   3. 索引表达式需与 services/SearchService.go 中 searchSources 的 body 表达式保持一致
+--   4. 如修改了 SEARCH_TS_CONFIG，需同步替换本脚本中的 'chinese'
+-- ============================================
+
+-- ============================================
+-- 1. 三元组模糊匹配（降级方案）
+-- ============================================
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS "idx_tasks_search_trgm" ON "public"."tasks"
+    USING gin ((coalesce(title, '') || ' ' || coalesce(description, '')) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS "idx_requirement_solutions_search_trgm" ON "public"."requirement_solutions"
+    USING gin ((coalesce(title, '') || ' ' || coalesce(content, '') || ' ' || coalesce(mindmap_markdown, '')) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS "idx_execution_plans_search_trgm" ON "public"."execution_plans"
+    USING gin ((coalesce(title, '') || ' ' || coalesce(tech_stack, '') || ' ' || coalesce(implementation_steps::text, '') || ' ' || coalesce(resource_requirements, '') || ' ' || coalesce(risk_assessment, '')) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS "idx_task_comments_search_trgm" ON "public"."task_comments"
+    USING gin ((coalesce(content, '')) gin_trgm_ops);
+
+-- ============================================
+-- 2. zhparser 中文全文检索（可选）
+-- ============================================
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'zhparser') THEN
+        CREATE EXTENSION IF NOT EXISTS zhparser;
+
+        IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'chinese') THEN
+            CREATE TEXT SEARCH CONFIGURATION chinese (PARSER = zhparser);
+            -- 名词/动词/形容词/成语/叹词/习用语/简称/其他 参与检索
+            ALTER TEXT SEARCH CONFIGURATION chinese ADD MAPPING FOR n,v,a,i,e,l,j,x WITH simple;
+        END IF;
+
+        CREATE INDEX IF NOT EXISTS "idx_tasks_search_fts" ON "public"."tasks"
+            USING gin (to_tsvector('chinese', coalesce(title, '') || ' ' || coalesce(description, '')));
+        CREATE INDEX IF NOT EXISTS "idx_requirement_solutions_search_fts" ON "public"."requirement_solutions"
+            USING gin (to_tsvector('chinese', coalesce(title, '') || ' ' || coalesce(content, '') || ' ' || coalesce(mindmap_markdown, '')));
+        CREATE INDEX IF NOT EXISTS "idx_execution_plans_search_fts" ON "public"."execution_plans"
+            USING gin (to_tsvector('chinese', coalesce(title, '') || ' ' || coalesce(tech_stack, '') || ' ' || coalesce(implementation_steps::text, '') || ' ' || coalesce(resource_requirements, '') || ' ' || coalesce(risk_assessment, '')));
+        CREATE INDEX IF NOT EXISTS "idx_task_comments_search_fts" ON "public"."task_comments"
+            USING gin (to_tsvector('chinese', coalesce(content, '')));
+    ELSE
+        RAISE NOTICE 'zhparser 扩展不可用，全文检索将降级为 pg_trgm 模糊匹配';
+    END IF;
+END
+$$;
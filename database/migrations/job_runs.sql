@@ -0,0 +1,45 @@
+-- ============================================
+-- 定时任务执行记录迁移脚本
+-- Scheduled Job Runs Migration
+-- ============================================
+
+-- ============================================
+-- 定时任务执行记录表 (job_runs)
+-- ============================================
+DROP TABLE IF EXISTS "public"."job_runs";
+CREATE SEQUENCE IF NOT EXISTS "public"."job_runs_id_seq";
+CREATE TABLE "public"."job_runs" (
+    "id" int4 NOT NULL DEFAULT nextval('job_runs_id_seq'::regclass),
+    "job_name" varchar(100) NOT NULL,
+    "trigger" varchar(20) NOT NULL,
+    "triggered_by" int4,
+    "instance" varchar(100),
+    "status" varchar(20) NOT NULL,
+    "started_at" timestamptz(6) NOT NULL,
+    "finished_at" timestamptz(6),
+    "duration_ms" int8 DEFAULT 0,
+    "output" text,
+    "error" text,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."job_runs" IS '定时任务执行记录表';
+COMMENT ON COLUMN "public"."job_runs"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."job_runs"."job_name" IS '任务名称';
+COMMENT ON COLUMN "public"."job_runs"."trigger" IS '触发方式：schedule-按计划触发，manual-管理员手动触发';
+COMMENT ON COLUMN "public"."job_runs"."triggered_by" IS '手动触发的用户ID';
+COMMENT ON COLUMN "public"."job_runs"."instance" IS '执行的实例（主机名）';
+COMMENT ON COLUMN "public"."job_runs"."status" IS '执行状态：running-执行中，success-成功，failed-失败';
+COMMENT ON COLUMN "public"."job_runs"."started_at" IS '开始时间';
+COMMENT ON COLUMN "public"."job_runs"."finished_at" IS '结束时间';
+COMMENT ON COLUMN "public"."job_runs"."duration_ms" IS '耗时（毫秒）';
+COMMENT ON COLUMN "public"."job_runs"."output" IS '执行结果摘要';
+COMMENT ON COLUMN "public"."job_runs"."error" IS '错误信息';
+COMMENT ON COLUMN "public"."job_runs"."created_at" IS '创建时间';
+
+CREATE INDEX "idx_job_runs_job_name_started_at" ON "public"."job_runs" USING btree ("job_name" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST, "started_at" "pg_catalog"."timestamptz_ops" DESC NULLS LAST);
+CREATE INDEX "idx_job_runs_status" ON "public"."job_runs" USING btree ("status" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."job_runs" ADD CONSTRAINT "job_runs_triggered_by_fkey"
+    FOREIGN KEY ("triggered_by") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
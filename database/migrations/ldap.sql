@@ -0,0 +1,36 @@
+-- ============================================
+-- LDAP 目录登录与同步迁移脚本
+-- LDAP Directory Login & Sync Migration
+-- ============================================
+
+-- ============================================
+-- 用户：账号来源和目录唯一标识
+-- ============================================
+ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "auth_source" varchar(20) DEFAULT 'local';
+ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "external_id" varchar(255);
+ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "external_login" varchar(255);
+
+COMMENT ON COLUMN "public"."users"."auth_source" IS '账号来源：local-本地账号，ldap-LDAP 目录账号（密码由目录管理）';
+COMMENT ON COLUMN "public"."users"."external_id" IS '目录中的唯一标识（entryUUID / objectGUID，本地账号为空）';
+COMMENT ON COLUMN "public"."users"."external_login" IS '目录中的登录名（解除 LDAP 登录锁定时使用，本地账号为空）';
+
+CREATE INDEX IF NOT EXISTS "idx_users_external_id" ON "public"."users" USING btree ("external_id" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+
+-- ============================================
+-- 部门：对应的目录 OU
+-- ============================================
+ALTER TABLE "public"."departments" ADD COLUMN IF NOT EXISTS "external_id" varchar(500);
+
+COMMENT ON COLUMN "public"."departments"."external_id" IS '对应的 LDAP OU（规范化的 DN，手工创建的部门为空）';
+
+CREATE INDEX IF NOT EXISTS "idx_departments_external_id" ON "public"."departments" USING btree ("external_id" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+
+-- ============================================
+-- 登录会话：新增 LDAP 登录方式
+-- ============================================
+COMMENT ON COLUMN "public"."user_sessions"."login_method" IS '登录方式：password-密码，ldap-LDAP 目录，wechat_open-微信扫码，wechat_mp-微信小程序，wechat_h5-微信公众号H5';
+
+-- ============================================
+-- 审计日志：新增目录同步禁用用户事件
+-- ============================================
+COMMENT ON COLUMN "public"."audit_logs"."action" IS '事件类型：login_locked-账号锁定，login_ip_locked-IP锁定，login_unlocked-解除账号锁定，login_ip_unlocked-解除IP锁定，two_factor_enabled-启用两步验证，two_factor_disabled-解除两步验证，recovery_code_used-使用恢复码登录，recovery_codes_renewed-重新生成恢复码，directory_user_disabled-目录同步禁用用户';
@@ -0,0 +1,55 @@
+-- ============================================
+-- OIDC 单点登录迁移脚本
+-- OpenID Connect Single Sign-On Migration
+-- ============================================
+
+-- ============================================
+-- 用户外部身份表 (user_identities)
+-- ============================================
+DROP TABLE IF EXISTS "public"."user_identities" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."user_identities_id_seq";
+CREATE TABLE "public"."user_identities" (
+    "id" int4 NOT NULL DEFAULT nextval('user_identities_id_seq'::regclass),
+    "user_id" int4 NOT NULL,
+    "provider" varchar(50) NOT NULL,
+    "subject" varchar(255) NOT NULL,
+    "email" varchar(100),
+    "last_login_at" timestamptz(6),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."user_identities" IS '用户外部身份表（OIDC 单点登录关联，同一提供方的同一 subject 只能关联一个账号）';
+COMMENT ON COLUMN "public"."user_identities"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."user_identities"."user_id" IS '用户ID';
+COMMENT ON COLUMN "public"."user_identities"."provider" IS '身份提供方标识（对应 OIDC_PROVIDERS 中的名称）';
+COMMENT ON COLUMN "public"."user_identities"."subject" IS '身份提供方中的用户唯一标识（ID Token 的 sub）';
+COMMENT ON COLUMN "public"."user_identities"."email" IS '关联时身份提供方返回的邮箱';
+COMMENT ON COLUMN "public"."user_identities"."last_login_at" IS '最近一次通过该身份登录的时间';
+COMMENT ON COLUMN "public"."user_identities"."created_at" IS '创建时间（关联时间）';
+COMMENT ON COLUMN "public"."user_identities"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."user_identities"."deleted_at" IS '软删除时间';
+
+CREATE UNIQUE INDEX "idx_user_identities_provider_subject" ON "public"."user_identities" USING btree ("provider" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST, "subject" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_user_identities_user_id" ON "public"."user_identities" USING btree ("user_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_user_identities_deleted_at" ON "public"."user_identities" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."user_identities" ADD CONSTRAINT "user_identities_user_id_fkey"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_user_identities_updated_at"
+    BEFORE UPDATE ON "public"."user_identities"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 登录会话：新增 OIDC 登录方式
+-- ============================================
+COMMENT ON COLUMN "public"."user_sessions"."login_method" IS '登录方式：password-密码，ldap-LDAP 目录，oidc-OIDC 单点登录，wechat_open-微信扫码，wechat_mp-微信小程序，wechat_h5-微信公众号H5';
+
+-- ============================================
+-- 审计日志：新增外部身份关联事件
+-- ============================================
+COMMENT ON COLUMN "public"."audit_logs"."action" IS '事件类型：login_locked-账号锁定，login_ip_locked-IP锁定，login_unlocked-解除账号锁定，login_ip_unlocked-解除IP锁定，two_factor_enabled-启用两步验证，two_factor_disabled-解除两步验证，recovery_code_used-使用恢复码登录，recovery_codes_renewed-重新生成恢复码，directory_user_disabled-目录同步禁用用户，identity_linked-关联单点登录身份';
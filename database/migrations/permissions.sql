@@ -0,0 +1,62 @@
+-- ============================================
+-- 权限目录补全迁移脚本
+-- Permission Catalogue Migration
+-- ============================================
+-- 接口按以下权限校验（超级管理员 admin 不受资源范围限制）：
+--   user:*    用户管理；user:update/delete/disable 对非管理员限定为本人负责部门（含下级部门）的成员
+--   dept:*    部门管理；dept:update/manage 对非管理员限定为本人负责的部门（含下级部门）
+--   task:*    任务管理；task:delete/assign 对非管理员限定为本人创建或负责部门的任务
+--   review:*  审核流程；review:decide/manage 对非管理员限定为审核会话、状态转换申请的决策人（含代理人）或任务管理者
+-- task:review 为旧版审核权限，接口不再使用，保留以兼容已有角色配置
+
+-- ============================================
+-- 新增权限
+-- ============================================
+INSERT INTO permissions (id, name, description, created_at, updated_at) VALUES
+-- 用户管理权限
+(40, 'user:disable', '禁用用户', NOW(), NOW()),
+-- 审核权限
+(41, 'review:read', '查看审核会话和批注', NOW(), NOW()),
+(42, 'review:initiate', '发起审核', NOW(), NOW()),
+(43, 'review:submit', '提交审核意见', NOW(), NOW()),
+(44, 'review:decide', '审核决策和审批状态转换申请', NOW(), NOW()),
+(45, 'review:manage', '管理审核陪审团', NOW(), NOW()),
+(46, 'review:annotate', '审核批注', NOW(), NOW()),
+(47, 'review:delegate', '委托审核', NOW(), NOW())
+ON CONFLICT (id) DO NOTHING;
+
+-- 重置序列
+SELECT setval('permissions_id_seq', (SELECT MAX(id) FROM permissions) + 1, false);
+
+-- ============================================
+-- 角色-权限关联
+-- ============================================
+-- 管理员拥有所有新权限
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT 1, id FROM permissions WHERE id >= 40 AND id <= 47
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+-- 部门经理权限
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+(2, 16), -- task:delete
+(2, 41), -- review:read
+(2, 42), -- review:initiate
+(2, 43), -- review:submit
+(2, 44), -- review:decide
+(2, 45), -- review:manage
+(2, 46), -- review:annotate
+(2, 47)  -- review:delegate
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+-- 普通用户权限（任务创建人可以删除、分配自己的任务，并主持自己任务的审核；决策和陪审团管理由资源范围限定为会话决策人）
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+(3, 16), -- task:delete
+(3, 17), -- task:assign
+(3, 41), -- review:read
+(3, 42), -- review:initiate
+(3, 43), -- review:submit
+(3, 44), -- review:decide
+(3, 45), -- review:manage
+(3, 46), -- review:annotate
+(3, 47)  -- review:delegate
+ON CONFLICT (role_id, permission_id) DO NOTHING;
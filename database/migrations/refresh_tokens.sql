@@ -0,0 +1,93 @@
+-- ============================================
+-- 刷新令牌与令牌吊销迁移脚本
+-- Refresh Tokens & Token Revocation Migration
+-- ============================================
+
+-- ============================================
+-- 刷新令牌表 (refresh_tokens)
+-- ============================================
+DROP TABLE IF EXISTS "public"."refresh_tokens" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."refresh_tokens_id_seq";
+CREATE TABLE "public"."refresh_tokens" (
+    "id" int4 NOT NULL DEFAULT nextval('refresh_tokens_id_seq'::regclass),
+    "user_id" int4 NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "family_id" varchar(64) NOT NULL,
+    "access_token_id" varchar(64),
+    "access_expires_at" timestamptz(6),
+    "expires_at" timestamptz(6) NOT NULL,
+    "revoked_at" timestamptz(6),
+    "revoke_reason" varchar(50),
+    "replaced_by_id" int4,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."refresh_tokens" IS '刷新令牌表（只保存哈希，每次刷新轮换；已轮换的令牌再次使用时吊销整个令牌族）';
+COMMENT ON COLUMN "public"."refresh_tokens"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."refresh_tokens"."user_id" IS '用户ID';
+COMMENT ON COLUMN "public"."refresh_tokens"."token_hash" IS '令牌哈希（SHA-256）';
+COMMENT ON COLUMN "public"."refresh_tokens"."family_id" IS '令牌族ID（同一次登录轮换出的令牌属于同一族）';
+COMMENT ON COLUMN "public"."refresh_tokens"."access_token_id" IS '同时签发的访问令牌ID（jti）';
+COMMENT ON COLUMN "public"."refresh_tokens"."access_expires_at" IS '访问令牌过期时间';
+COMMENT ON COLUMN "public"."refresh_tokens"."expires_at" IS '刷新令牌过期时间';
+COMMENT ON COLUMN "public"."refresh_tokens"."revoked_at" IS '吊销时间（已轮换、注销或被吊销）';
+COMMENT ON COLUMN "public"."refresh_tokens"."revoke_reason" IS '吊销原因：rotated-已轮换，logout-注销，reuse-重复使用，password_changed-修改密码，disabled-禁用，deleted-删除';
+COMMENT ON COLUMN "public"."refresh_tokens"."replaced_by_id" IS '轮换后的新令牌ID';
+COMMENT ON COLUMN "public"."refresh_tokens"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."refresh_tokens"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."refresh_tokens"."deleted_at" IS '软删除时间';
+
+CREATE UNIQUE INDEX "idx_refresh_tokens_token_hash" ON "public"."refresh_tokens" USING btree ("token_hash" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_refresh_tokens_user_id" ON "public"."refresh_tokens" USING btree ("user_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_refresh_tokens_family_id" ON "public"."refresh_tokens" USING btree ("family_id" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_refresh_tokens_access_token_id" ON "public"."refresh_tokens" USING btree ("access_token_id" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_refresh_tokens_expires_at" ON "public"."refresh_tokens" USING btree ("expires_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+CREATE INDEX "idx_refresh_tokens_deleted_at" ON "public"."refresh_tokens" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."refresh_tokens" ADD CONSTRAINT "refresh_tokens_user_id_fkey"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_refresh_tokens_updated_at"
+    BEFORE UPDATE ON "public"."refresh_tokens"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 访问令牌吊销表 (revoked_tokens)
+-- ============================================
+DROP TABLE IF EXISTS "public"."revoked_tokens" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."revoked_tokens_id_seq";
+CREATE TABLE "public"."revoked_tokens" (
+    "id" int4 NOT NULL DEFAULT nextval('revoked_tokens_id_seq'::regclass),
+    "token_id" varchar(64) NOT NULL,
+    "user_id" int4 NOT NULL,
+    "expires_at" timestamptz(6) NOT NULL,
+    "reason" varchar(50),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."revoked_tokens" IS '访问令牌吊销表（认证中间件拒绝列表中未过期的令牌）';
+COMMENT ON COLUMN "public"."revoked_tokens"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."revoked_tokens"."token_id" IS '访问令牌ID（jti）';
+COMMENT ON COLUMN "public"."revoked_tokens"."user_id" IS '用户ID';
+COMMENT ON COLUMN "public"."revoked_tokens"."expires_at" IS '访问令牌过期时间（过期后记录可清理）';
+COMMENT ON COLUMN "public"."revoked_tokens"."reason" IS '吊销原因';
+COMMENT ON COLUMN "public"."revoked_tokens"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."revoked_tokens"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."revoked_tokens"."deleted_at" IS '软删除时间';
+
+CREATE UNIQUE INDEX "idx_revoked_tokens_token_id" ON "public"."revoked_tokens" USING btree ("token_id" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_revoked_tokens_user_id" ON "public"."revoked_tokens" USING btree ("user_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_revoked_tokens_expires_at" ON "public"."revoked_tokens" USING btree ("expires_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+CREATE INDEX "idx_revoked_tokens_deleted_at" ON "public"."revoked_tokens" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+CREATE TRIGGER "update_revoked_tokens_updated_at"
+    BEFORE UPDATE ON "public"."revoked_tokens"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,121 @@
+-- ============================================
+-- 审核批注迁移脚本
+-- Inline Review Annotations Migration
+-- ============================================
+
+-- ============================================
+-- 审核批注表 (review_annotations)
+-- ============================================
+DROP TABLE IF EXISTS "public"."review_annotations" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."review_annotations_id_seq";
+CREATE TABLE "public"."review_annotations" (
+    "id" int4 NOT NULL DEFAULT nextval('review_annotations_id_seq'::regclass),
+    "task_id" int4 NOT NULL,
+    "target_type" varchar(50) NOT NULL,
+    "target_id" int4 NOT NULL,
+    "version" int4 NOT NULL,
+    "origin_target_id" int4 NOT NULL,
+    "origin_version" int4 NOT NULL,
+    "review_session_id" int4,
+    "anchor_type" varchar(20) NOT NULL,
+    "anchor_status" varchar(20) DEFAULT 'anchored',
+    "field" varchar(50),
+    "start_offset" int4 DEFAULT 0,
+    "end_offset" int4 DEFAULT 0,
+    "quoted_text" text,
+    "step_path" varchar(255),
+    "goal_id" int4,
+    "goal_no" int4,
+    "author_id" int4 NOT NULL,
+    "content" text NOT NULL,
+    "is_resolved" bool DEFAULT false,
+    "resolved_by" int4,
+    "resolved_at" timestamptz(6),
+    "carried_at" timestamptz(6),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."review_annotations" IS '审核批注表（锚定在方案/执行计划版本的文本片段、实施步骤或目标上，未解决的批注随新版本转移）';
+COMMENT ON COLUMN "public"."review_annotations"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."review_annotations"."task_id" IS '关联任务ID';
+COMMENT ON COLUMN "public"."review_annotations"."target_type" IS '批注对象表名：requirement_solutions-思路方案，execution_plans-执行计划';
+COMMENT ON COLUMN "public"."review_annotations"."target_id" IS '当前锚定的版本记录ID';
+COMMENT ON COLUMN "public"."review_annotations"."version" IS '当前锚定的版本号';
+COMMENT ON COLUMN "public"."review_annotations"."origin_target_id" IS '创建批注时的版本记录ID';
+COMMENT ON COLUMN "public"."review_annotations"."origin_version" IS '创建批注时的版本号';
+COMMENT ON COLUMN "public"."review_annotations"."review_session_id" IS '创建批注时进行中的审核会话ID';
+COMMENT ON COLUMN "public"."review_annotations"."anchor_type" IS '锚点类型：text-文本片段，step-实施步骤，goal-目标';
+COMMENT ON COLUMN "public"."review_annotations"."anchor_status" IS '锚点状态：anchored-有效，outdated-新版本中找不到原锚点';
+COMMENT ON COLUMN "public"."review_annotations"."field" IS '文本锚点的字段名';
+COMMENT ON COLUMN "public"."review_annotations"."start_offset" IS '文本锚点的起始字符偏移（含）';
+COMMENT ON COLUMN "public"."review_annotations"."end_offset" IS '文本锚点的结束字符偏移（不含）';
+COMMENT ON COLUMN "public"."review_annotations"."quoted_text" IS '批注时选中的原文（步骤锚点为步骤内容的 JSON）';
+COMMENT ON COLUMN "public"."review_annotations"."step_path" IS '步骤锚点的 JSON 路径';
+COMMENT ON COLUMN "public"."review_annotations"."goal_id" IS '目标锚点的目标ID（当前版本）';
+COMMENT ON COLUMN "public"."review_annotations"."goal_no" IS '目标锚点的目标编号（当前版本）';
+COMMENT ON COLUMN "public"."review_annotations"."author_id" IS '批注人用户ID';
+COMMENT ON COLUMN "public"."review_annotations"."content" IS '批注内容';
+COMMENT ON COLUMN "public"."review_annotations"."is_resolved" IS '是否已解决';
+COMMENT ON COLUMN "public"."review_annotations"."resolved_by" IS '解决人用户ID';
+COMMENT ON COLUMN "public"."review_annotations"."resolved_at" IS '解决时间';
+COMMENT ON COLUMN "public"."review_annotations"."carried_at" IS '最近一次转移到新版本的时间';
+COMMENT ON COLUMN "public"."review_annotations"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."review_annotations"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."review_annotations"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_review_annotations_task_id" ON "public"."review_annotations" USING btree ("task_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_annotations_target_id" ON "public"."review_annotations" USING btree ("target_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_annotations_review_session_id" ON "public"."review_annotations" USING btree ("review_session_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_annotations_goal_id" ON "public"."review_annotations" USING btree ("goal_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_annotations_author_id" ON "public"."review_annotations" USING btree ("author_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_annotations_deleted_at" ON "public"."review_annotations" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."review_annotations" ADD CONSTRAINT "review_annotations_task_id_fkey"
+    FOREIGN KEY ("task_id") REFERENCES "public"."tasks" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."review_annotations" ADD CONSTRAINT "review_annotations_author_id_fkey"
+    FOREIGN KEY ("author_id") REFERENCES "public"."users" ("id") ON DELETE NO ACTION ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_review_annotations_updated_at"
+    BEFORE UPDATE ON "public"."review_annotations"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 批注回复表 (review_annotation_replies)
+-- ============================================
+DROP TABLE IF EXISTS "public"."review_annotation_replies";
+CREATE SEQUENCE IF NOT EXISTS "public"."review_annotation_replies_id_seq";
+CREATE TABLE "public"."review_annotation_replies" (
+    "id" int4 NOT NULL DEFAULT nextval('review_annotation_replies_id_seq'::regclass),
+    "annotation_id" int4 NOT NULL,
+    "author_id" int4 NOT NULL,
+    "content" text NOT NULL,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."review_annotation_replies" IS '批注回复表';
+COMMENT ON COLUMN "public"."review_annotation_replies"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."review_annotation_replies"."annotation_id" IS '批注ID';
+COMMENT ON COLUMN "public"."review_annotation_replies"."author_id" IS '回复人用户ID';
+COMMENT ON COLUMN "public"."review_annotation_replies"."content" IS '回复内容';
+COMMENT ON COLUMN "public"."review_annotation_replies"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."review_annotation_replies"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."review_annotation_replies"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_review_annotation_replies_annotation_id" ON "public"."review_annotation_replies" USING btree ("annotation_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_annotation_replies_author_id" ON "public"."review_annotation_replies" USING btree ("author_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_annotation_replies_deleted_at" ON "public"."review_annotation_replies" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."review_annotation_replies" ADD CONSTRAINT "review_annotation_replies_annotation_id_fkey"
+    FOREIGN KEY ("annotation_id") REFERENCES "public"."review_annotations" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_review_annotation_replies_updated_at"
+    BEFORE UPDATE ON "public"."review_annotation_replies"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
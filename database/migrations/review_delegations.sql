@@ -0,0 +1,72 @@
+-- ============================================
+-- 审核委托（代理审核）迁移脚本
+-- Reviewer Delegation Migration
+-- ============================================
+
+-- ============================================
+-- 审核委托表 (review_delegations)
+-- ============================================
+DROP TABLE IF EXISTS "public"."review_delegations";
+CREATE SEQUENCE IF NOT EXISTS "public"."review_delegations_id_seq";
+CREATE TABLE "public"."review_delegations" (
+    "id" int4 NOT NULL DEFAULT nextval('review_delegations_id_seq'::regclass),
+    "delegator_id" int4 NOT NULL,
+    "delegate_id" int4 NOT NULL,
+    "start_at" timestamptz(6) NOT NULL,
+    "end_at" timestamptz(6) NOT NULL,
+    "department_id" int4,
+    "task_type_code" varchar(50),
+    "reason" varchar(255),
+    "is_active" bool DEFAULT true,
+    "revoked_at" timestamptz(6),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."review_delegations" IS '审核委托表（委托人休假等期间由代理人代为审核、审批）';
+COMMENT ON COLUMN "public"."review_delegations"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."review_delegations"."delegator_id" IS '委托人用户ID';
+COMMENT ON COLUMN "public"."review_delegations"."delegate_id" IS '代理人用户ID';
+COMMENT ON COLUMN "public"."review_delegations"."start_at" IS '生效开始时间';
+COMMENT ON COLUMN "public"."review_delegations"."end_at" IS '生效结束时间（不含）';
+COMMENT ON COLUMN "public"."review_delegations"."department_id" IS '限定部门ID（为空表示不限）';
+COMMENT ON COLUMN "public"."review_delegations"."task_type_code" IS '限定任务类型编码（为空表示不限）';
+COMMENT ON COLUMN "public"."review_delegations"."reason" IS '委托原因';
+COMMENT ON COLUMN "public"."review_delegations"."is_active" IS '是否有效（撤销后为 false）';
+COMMENT ON COLUMN "public"."review_delegations"."revoked_at" IS '撤销时间';
+COMMENT ON COLUMN "public"."review_delegations"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."review_delegations"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."review_delegations"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_review_delegations_delegator_id" ON "public"."review_delegations" USING btree ("delegator_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_delegations_delegate_id" ON "public"."review_delegations" USING btree ("delegate_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_delegations_department_id" ON "public"."review_delegations" USING btree ("department_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_delegations_end_at" ON "public"."review_delegations" USING btree ("end_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_delegations_deleted_at" ON "public"."review_delegations" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."review_delegations" ADD CONSTRAINT "review_delegations_delegator_id_fkey"
+    FOREIGN KEY ("delegator_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."review_delegations" ADD CONSTRAINT "review_delegations_delegate_id_fkey"
+    FOREIGN KEY ("delegate_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."review_delegations" ADD CONSTRAINT "review_delegations_department_id_fkey"
+    FOREIGN KEY ("department_id") REFERENCES "public"."departments" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_review_delegations_updated_at"
+    BEFORE UPDATE ON "public"."review_delegations"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 代审记录：实际操作的代理人
+-- ============================================
+ALTER TABLE "public"."review_records" ADD COLUMN IF NOT EXISTS "delegate_id" int4;
+COMMENT ON COLUMN "public"."review_records"."delegate_id" IS '代理人用户ID（委托代审时实际提交意见的人，为空表示本人提交）';
+CREATE INDEX IF NOT EXISTS "idx_review_records_delegate_id" ON "public"."review_records" USING btree ("delegate_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "final_decision_delegate_id" int4;
+COMMENT ON COLUMN "public"."review_sessions"."final_decision_delegate_id" IS '最终决策的代理人用户ID（委托代审时实际决策的人，为空表示本人决策）';
+
+ALTER TABLE "public"."task_transition_requests" ADD COLUMN IF NOT EXISTS "decided_delegate_id" int4;
+COMMENT ON COLUMN "public"."task_transition_requests"."decided_delegate_id" IS '审批代理人用户ID（委托代审时实际审批的人，为空表示本人审批）';
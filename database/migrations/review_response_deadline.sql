@@ -0,0 +1,19 @@
+-- ============================================
+-- 陪审团响应截止迁移脚本
+-- Jury Response Deadline Migration
+-- ============================================
+
+-- ============================================
+-- 审核会话：响应截止时间、提醒和截止处理策略
+-- ============================================
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "response_due_at" timestamptz(6);
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "deadline_policy" varchar(50);
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "reminded_at" timestamptz(6);
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "deadline_handled_at" timestamptz(6);
+
+COMMENT ON COLUMN "public"."review_sessions"."response_due_at" IS '陪审团响应截止时间（单人审核为空）';
+COMMENT ON COLUMN "public"."review_sessions"."deadline_policy" IS '响应截止后的处理策略：abstain-未响应视为弃权，escalate-升级到部门负责人决策，creator_finalize-创建人直接决策并记录法定人数不足';
+COMMENT ON COLUMN "public"."review_sessions"."reminded_at" IS '截止前提醒未响应成员的时间';
+COMMENT ON COLUMN "public"."review_sessions"."deadline_handled_at" IS '执行截止处理策略的时间';
+
+CREATE INDEX IF NOT EXISTS "idx_review_sessions_response_due_at" ON "public"."review_sessions" USING btree ("response_due_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
@@ -0,0 +1,124 @@
+-- ============================================
+-- 审核评分标准迁移脚本
+-- Review Rubrics Migration
+-- ============================================
+
+-- ============================================
+-- 审核评分标准表 (review_rubrics)
+-- ============================================
+DROP TABLE IF EXISTS "public"."review_rubrics" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."review_rubrics_id_seq";
+CREATE TABLE "public"."review_rubrics" (
+    "id" int4 NOT NULL DEFAULT nextval('review_rubrics_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "review_type" varchar(50) NOT NULL,
+    "scale_min" int4 NOT NULL DEFAULT 1,
+    "scale_max" int4 NOT NULL DEFAULT 5,
+    "min_total_score" numeric(6,2),
+    "is_active" bool DEFAULT true,
+    "description" varchar(255),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."review_rubrics" IS '审核评分标准表（按审核类型配置，发起审核时绑定）';
+COMMENT ON COLUMN "public"."review_rubrics"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."review_rubrics"."name" IS '评分标准名称';
+COMMENT ON COLUMN "public"."review_rubrics"."review_type" IS '适用的审核类型：solution_review-思路方案审核，plan_review-执行计划审核';
+COMMENT ON COLUMN "public"."review_rubrics"."scale_min" IS '分值下限';
+COMMENT ON COLUMN "public"."review_rubrics"."scale_max" IS '分值上限';
+COMMENT ON COLUMN "public"."review_rubrics"."min_total_score" IS '通过所需的最低加权总分（为空表示不限制）';
+COMMENT ON COLUMN "public"."review_rubrics"."is_active" IS '是否启用（每个审核类型最多一个启用的评分标准）';
+COMMENT ON COLUMN "public"."review_rubrics"."description" IS '描述';
+COMMENT ON COLUMN "public"."review_rubrics"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."review_rubrics"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."review_rubrics"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_review_rubrics_review_type" ON "public"."review_rubrics" USING btree ("review_type" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_rubrics_deleted_at" ON "public"."review_rubrics" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+CREATE TRIGGER "update_review_rubrics_updated_at"
+    BEFORE UPDATE ON "public"."review_rubrics"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 评分维度表 (review_rubric_criteria)
+-- ============================================
+DROP TABLE IF EXISTS "public"."review_rubric_criteria" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."review_rubric_criteria_id_seq";
+CREATE TABLE "public"."review_rubric_criteria" (
+    "id" int4 NOT NULL DEFAULT nextval('review_rubric_criteria_id_seq'::regclass),
+    "rubric_id" int4 NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "description" varchar(255),
+    "weight" numeric(5,2) NOT NULL DEFAULT 1.0,
+    "sort_order" int4 DEFAULT 0,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."review_rubric_criteria" IS '评分维度表';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."rubric_id" IS '评分标准ID';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."name" IS '维度名称';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."description" IS '维度说明';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."weight" IS '权重（加权总分按权重占比计算）';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."sort_order" IS '排序';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."review_rubric_criteria"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_review_rubric_criteria_rubric_id" ON "public"."review_rubric_criteria" USING btree ("rubric_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_rubric_criteria_deleted_at" ON "public"."review_rubric_criteria" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."review_rubric_criteria" ADD CONSTRAINT "review_rubric_criteria_rubric_id_fkey"
+    FOREIGN KEY ("rubric_id") REFERENCES "public"."review_rubrics" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_review_rubric_criteria_updated_at"
+    BEFORE UPDATE ON "public"."review_rubric_criteria"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 维度评分表 (review_criterion_scores)
+-- ============================================
+DROP TABLE IF EXISTS "public"."review_criterion_scores";
+CREATE SEQUENCE IF NOT EXISTS "public"."review_criterion_scores_id_seq";
+CREATE TABLE "public"."review_criterion_scores" (
+    "id" int4 NOT NULL DEFAULT nextval('review_criterion_scores_id_seq'::regclass),
+    "review_session_id" int4 NOT NULL,
+    "review_record_id" int4 NOT NULL,
+    "criterion_id" int4 NOT NULL,
+    "score" int4 NOT NULL,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."review_criterion_scores" IS '维度评分表（审核人对评分维度的打分）';
+COMMENT ON COLUMN "public"."review_criterion_scores"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."review_criterion_scores"."review_session_id" IS '审核会话ID';
+COMMENT ON COLUMN "public"."review_criterion_scores"."review_record_id" IS '审核记录ID';
+COMMENT ON COLUMN "public"."review_criterion_scores"."criterion_id" IS '评分维度ID';
+COMMENT ON COLUMN "public"."review_criterion_scores"."score" IS '分值';
+COMMENT ON COLUMN "public"."review_criterion_scores"."created_at" IS '创建时间';
+
+CREATE INDEX "idx_review_criterion_scores_review_session_id" ON "public"."review_criterion_scores" USING btree ("review_session_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_review_criterion_scores_review_record_id" ON "public"."review_criterion_scores" USING btree ("review_record_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE UNIQUE INDEX "uk_review_criterion_scores_record_criterion" ON "public"."review_criterion_scores" USING btree ("review_record_id", "criterion_id");
+
+ALTER TABLE "public"."review_criterion_scores" ADD CONSTRAINT "review_criterion_scores_review_record_id_fkey"
+    FOREIGN KEY ("review_record_id") REFERENCES "public"."review_records" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."review_criterion_scores" ADD CONSTRAINT "review_criterion_scores_criterion_id_fkey"
+    FOREIGN KEY ("criterion_id") REFERENCES "public"."review_rubric_criteria" ("id") ON DELETE NO ACTION ON UPDATE NO ACTION;
+
+-- ============================================
+-- 审核会话：绑定评分标准
+-- ============================================
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "rubric_id" int4;
+COMMENT ON COLUMN "public"."review_sessions"."rubric_id" IS '绑定的评分标准ID（发起审核时审核类型启用的评分标准）';
+CREATE INDEX IF NOT EXISTS "idx_review_sessions_rubric_id" ON "public"."review_sessions" USING btree ("rubric_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
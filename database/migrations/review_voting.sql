@@ -0,0 +1,52 @@
+-- ============================================
+-- 陪审团加权/门槛投票迁移脚本
+-- Weighted & Threshold Jury Voting Migration
+-- ============================================
+
+-- ============================================
+-- 审核会话：投票模式与创建人越权决策标记
+-- ============================================
+ALTER TABLE "public"."review_sessions" ADD COLUMN IF NOT EXISTS "is_overridden" bool DEFAULT false;
+
+COMMENT ON COLUMN "public"."review_sessions"."review_mode" IS '审核模式：single-单人审核，jury-陪审团（创建人决策），threshold-门槛投票（自动决策），weighted-加权投票（自动决策）';
+COMMENT ON COLUMN "public"."review_sessions"."required_approvals" IS '需要的通过票数（加权投票模式下为需要的赞成权重）';
+COMMENT ON COLUMN "public"."review_sessions"."is_overridden" IS '是否由创建人越过投票结果直接决策';
+
+-- ============================================
+-- 审核人角色投票权重表 (review_role_weights)
+-- ============================================
+DROP TABLE IF EXISTS "public"."review_role_weights";
+CREATE SEQUENCE IF NOT EXISTS "public"."review_role_weights_id_seq";
+CREATE TABLE "public"."review_role_weights" (
+    "id" int4 NOT NULL DEFAULT nextval('review_role_weights_id_seq'::regclass),
+    "reviewer_role" varchar(50) NOT NULL,
+    "weight" numeric(3,2) NOT NULL DEFAULT 1.0,
+    "description" varchar(255),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."review_role_weights" IS '审核人角色投票权重表（加权投票模式使用，未配置的角色权重为 1）';
+COMMENT ON COLUMN "public"."review_role_weights"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."review_role_weights"."reviewer_role" IS '审核人角色：leader-部门负责人，jury-陪审团成员，expert-评审专家';
+COMMENT ON COLUMN "public"."review_role_weights"."weight" IS '投票权重';
+COMMENT ON COLUMN "public"."review_role_weights"."description" IS '描述';
+COMMENT ON COLUMN "public"."review_role_weights"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."review_role_weights"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."review_role_weights"."deleted_at" IS '软删除时间';
+
+CREATE UNIQUE INDEX "uk_review_role_weights_reviewer_role" ON "public"."review_role_weights" USING btree ("reviewer_role");
+CREATE INDEX "idx_review_role_weights_deleted_at" ON "public"."review_role_weights" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+CREATE TRIGGER "update_review_role_weights_updated_at"
+    BEFORE UPDATE ON "public"."review_role_weights"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- 默认权重
+INSERT INTO "public"."review_role_weights" ("reviewer_role", "weight", "description") VALUES
+    ('jury', 1.00, '陪审团成员'),
+    ('expert', 1.50, '评审专家'),
+    ('leader', 2.00, '任务所属部门负责人');
@@ -0,0 +1,198 @@
+-- ============================================
+-- SLA 策略与工作日历迁移脚本
+-- SLA Policies & Business Calendars Migration
+-- ============================================
+
+-- ============================================
+-- 工作日历表 (business_calendars)
+-- ============================================
+DROP TABLE IF EXISTS "public"."business_calendars" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."business_calendars_id_seq";
+CREATE TABLE "public"."business_calendars" (
+    "id" int4 NOT NULL DEFAULT nextval('business_calendars_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "department_id" int4,
+    "is_default" bool DEFAULT false,
+    "time_zone" varchar(50) DEFAULT 'Asia/Shanghai',
+    "work_start" varchar(5) DEFAULT '09:00',
+    "work_end" varchar(5) DEFAULT '18:00',
+    "work_days" jsonb DEFAULT '[1,2,3,4,5]'::jsonb,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."business_calendars" IS '工作日历表（SLA 计时只累计工作时间）';
+COMMENT ON COLUMN "public"."business_calendars"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."business_calendars"."name" IS '日历名称';
+COMMENT ON COLUMN "public"."business_calendars"."department_id" IS '绑定的部门ID（为空表示通用日历）';
+COMMENT ON COLUMN "public"."business_calendars"."is_default" IS '是否为默认日历（部门未配置日历时使用）';
+COMMENT ON COLUMN "public"."business_calendars"."time_zone" IS '时区';
+COMMENT ON COLUMN "public"."business_calendars"."work_start" IS '每日上班时间（HH:MM）';
+COMMENT ON COLUMN "public"."business_calendars"."work_end" IS '每日下班时间（HH:MM）';
+COMMENT ON COLUMN "public"."business_calendars"."work_days" IS '工作日JSON（1-7 表示周一至周日）';
+COMMENT ON COLUMN "public"."business_calendars"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."business_calendars"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."business_calendars"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_business_calendars_department_id" ON "public"."business_calendars" USING btree ("department_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_business_calendars_deleted_at" ON "public"."business_calendars" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."business_calendars" ADD CONSTRAINT "business_calendars_department_id_fkey"
+    FOREIGN KEY ("department_id") REFERENCES "public"."departments" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_business_calendars_updated_at"
+    BEFORE UPDATE ON "public"."business_calendars"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 日历节假日/调休表 (calendar_holidays)
+-- ============================================
+DROP TABLE IF EXISTS "public"."calendar_holidays";
+CREATE SEQUENCE IF NOT EXISTS "public"."calendar_holidays_id_seq";
+CREATE TABLE "public"."calendar_holidays" (
+    "id" int4 NOT NULL DEFAULT nextval('calendar_holidays_id_seq'::regclass),
+    "calendar_id" int4 NOT NULL,
+    "date" date NOT NULL,
+    "name" varchar(100),
+    "is_workday" bool DEFAULT false,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."calendar_holidays" IS '日历节假日/调休表';
+COMMENT ON COLUMN "public"."calendar_holidays"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."calendar_holidays"."calendar_id" IS '工作日历ID';
+COMMENT ON COLUMN "public"."calendar_holidays"."date" IS '日期';
+COMMENT ON COLUMN "public"."calendar_holidays"."name" IS '名称';
+COMMENT ON COLUMN "public"."calendar_holidays"."is_workday" IS '是否为调休上班日：true-按工作日计算，false-放假';
+COMMENT ON COLUMN "public"."calendar_holidays"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."calendar_holidays"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."calendar_holidays"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_calendar_holidays_calendar_id" ON "public"."calendar_holidays" USING btree ("calendar_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_calendar_holidays_deleted_at" ON "public"."calendar_holidays" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."calendar_holidays" ADD CONSTRAINT "calendar_holidays_calendar_id_fkey"
+    FOREIGN KEY ("calendar_id") REFERENCES "public"."business_calendars" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_calendar_holidays_updated_at"
+    BEFORE UPDATE ON "public"."calendar_holidays"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- SLA 策略表 (sla_policies)
+-- ============================================
+DROP TABLE IF EXISTS "public"."sla_policies" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."sla_policies_id_seq";
+CREATE TABLE "public"."sla_policies" (
+    "id" int4 NOT NULL DEFAULT nextval('sla_policies_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "task_type_code" varchar(50) NOT NULL,
+    "status_code" varchar(50) NOT NULL,
+    "priority" int4,
+    "max_minutes" int4 NOT NULL,
+    "calendar_id" int4,
+    "is_active" bool DEFAULT true,
+    "description" text,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."sla_policies" IS 'SLA策略表（限定任务在某状态中停留的最长工作时间）';
+COMMENT ON COLUMN "public"."sla_policies"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."sla_policies"."name" IS '策略名称';
+COMMENT ON COLUMN "public"."sla_policies"."task_type_code" IS '任务类型编码';
+COMMENT ON COLUMN "public"."sla_policies"."status_code" IS '状态编码';
+COMMENT ON COLUMN "public"."sla_policies"."priority" IS '适用的优先级（为空表示所有优先级）';
+COMMENT ON COLUMN "public"."sla_policies"."max_minutes" IS '最长停留时间（分钟，按工作日历计算）';
+COMMENT ON COLUMN "public"."sla_policies"."calendar_id" IS '使用的工作日历ID（为空时使用部门日历或默认日历）';
+COMMENT ON COLUMN "public"."sla_policies"."is_active" IS '是否启用';
+COMMENT ON COLUMN "public"."sla_policies"."description" IS '描述';
+COMMENT ON COLUMN "public"."sla_policies"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."sla_policies"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."sla_policies"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_sla_policies_task_type_code" ON "public"."sla_policies" USING btree ("task_type_code" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_sla_policies_deleted_at" ON "public"."sla_policies" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."sla_policies" ADD CONSTRAINT "sla_policies_calendar_id_fkey"
+    FOREIGN KEY ("calendar_id") REFERENCES "public"."business_calendars" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_sla_policies_updated_at"
+    BEFORE UPDATE ON "public"."sla_policies"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- SLA 违约事件表 (sla_breaches)
+-- ============================================
+DROP TABLE IF EXISTS "public"."sla_breaches";
+CREATE SEQUENCE IF NOT EXISTS "public"."sla_breaches_id_seq";
+CREATE TABLE "public"."sla_breaches" (
+    "id" int4 NOT NULL DEFAULT nextval('sla_breaches_id_seq'::regclass),
+    "task_id" int4 NOT NULL,
+    "policy_id" int4 NOT NULL,
+    "status_code" varchar(50) NOT NULL,
+    "entered_at" timestamptz(6) NOT NULL,
+    "due_at" timestamptz(6) NOT NULL,
+    "breached_at" timestamptz(6) NOT NULL,
+    "executor_id" int4,
+    "department_id" int4,
+    "resolved_at" timestamptz(6),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."sla_breaches" IS 'SLA违约事件表';
+COMMENT ON COLUMN "public"."sla_breaches"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."sla_breaches"."task_id" IS '任务ID';
+COMMENT ON COLUMN "public"."sla_breaches"."policy_id" IS 'SLA策略ID';
+COMMENT ON COLUMN "public"."sla_breaches"."status_code" IS '违约时所处的状态编码';
+COMMENT ON COLUMN "public"."sla_breaches"."entered_at" IS '进入该状态的时间';
+COMMENT ON COLUMN "public"."sla_breaches"."due_at" IS 'SLA截止时间';
+COMMENT ON COLUMN "public"."sla_breaches"."breached_at" IS '检测到违约的时间';
+COMMENT ON COLUMN "public"."sla_breaches"."executor_id" IS '违约时的执行人用户ID';
+COMMENT ON COLUMN "public"."sla_breaches"."department_id" IS '违约时任务所属部门ID';
+COMMENT ON COLUMN "public"."sla_breaches"."resolved_at" IS '解除时间（任务离开该状态的时间）';
+COMMENT ON COLUMN "public"."sla_breaches"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."sla_breaches"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."sla_breaches"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_sla_breaches_task_id" ON "public"."sla_breaches" USING btree ("task_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_sla_breaches_policy_id" ON "public"."sla_breaches" USING btree ("policy_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_sla_breaches_executor_id" ON "public"."sla_breaches" USING btree ("executor_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_sla_breaches_department_id" ON "public"."sla_breaches" USING btree ("department_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_sla_breaches_deleted_at" ON "public"."sla_breaches" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+CREATE UNIQUE INDEX "uk_sla_breaches_task_policy_entered" ON "public"."sla_breaches" USING btree ("task_id", "policy_id", "entered_at");
+
+ALTER TABLE "public"."sla_breaches" ADD CONSTRAINT "sla_breaches_task_id_fkey"
+    FOREIGN KEY ("task_id") REFERENCES "public"."tasks" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."sla_breaches" ADD CONSTRAINT "sla_breaches_policy_id_fkey"
+    FOREIGN KEY ("policy_id") REFERENCES "public"."sla_policies" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_sla_breaches_updated_at"
+    BEFORE UPDATE ON "public"."sla_breaches"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 默认工作日历与示例策略
+-- ============================================
+INSERT INTO "public"."business_calendars" ("name", "is_default", "time_zone", "work_start", "work_end", "work_days")
+VALUES ('默认工作日历', true, 'Asia/Shanghai', '09:00', '18:00', '[1,2,3,4,5]'::jsonb);
+
+-- 紧急任务（优先级4）待接受不超过 24 个工作小时
+INSERT INTO "public"."sla_policies" ("name", "task_type_code", "status_code", "priority", "max_minutes", "description")
+VALUES
+('紧急需求待接受', 'requirement', 'req_pending_accept', 4, 1440, '紧急需求指派后需在24个工作小时内接受'),
+('紧急任务待接受', 'unit_task', 'unit_pending_accept', 4, 1440, '紧急任务指派后需在24个工作小时内接受');
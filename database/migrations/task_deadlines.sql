@@ -0,0 +1,69 @@
+-- ============================================
+-- 方案/计划提交截止迁移脚本
+-- Solution & Execution Plan Deadlines Migration
+-- ============================================
+
+-- ============================================
+-- 任务截止时间表 (task_deadlines)
+-- ============================================
+DROP TABLE IF EXISTS "public"."task_deadlines";
+CREATE SEQUENCE IF NOT EXISTS "public"."task_deadlines_id_seq";
+CREATE TABLE "public"."task_deadlines" (
+    "id" int4 NOT NULL DEFAULT nextval('task_deadlines_id_seq'::regclass),
+    "task_id" int4 NOT NULL,
+    "kind" varchar(30) NOT NULL,
+    "status_code" varchar(50) NOT NULL,
+    "started_at" timestamptz(6) NOT NULL,
+    "due_at" timestamptz(6) NOT NULL,
+    "status" varchar(20) DEFAULT 'active',
+    "reminded_at" timestamptz(6),
+    "escalated_at" timestamptz(6),
+    "auto_transitioned_at" timestamptz(6),
+    "closed_at" timestamptz(6),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."task_deadlines" IS '任务截止时间表（进入待提交方案/待提交计划状态时开始计时）';
+COMMENT ON COLUMN "public"."task_deadlines"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."task_deadlines"."task_id" IS '任务ID';
+COMMENT ON COLUMN "public"."task_deadlines"."kind" IS '截止类型：solution-思路方案，execution_plan-执行计划';
+COMMENT ON COLUMN "public"."task_deadlines"."status_code" IS '开始计时时的任务状态编码';
+COMMENT ON COLUMN "public"."task_deadlines"."started_at" IS '开始计时时间';
+COMMENT ON COLUMN "public"."task_deadlines"."due_at" IS '截止时间';
+COMMENT ON COLUMN "public"."task_deadlines"."status" IS '状态：active-计时中，overdue-已超期，closed-已结束';
+COMMENT ON COLUMN "public"."task_deadlines"."reminded_at" IS '发送截止提醒的时间';
+COMMENT ON COLUMN "public"."task_deadlines"."escalated_at" IS '超期升级（通知创建人和部门负责人）的时间';
+COMMENT ON COLUMN "public"."task_deadlines"."auto_transitioned_at" IS '超期自动转换状态的时间';
+COMMENT ON COLUMN "public"."task_deadlines"."closed_at" IS '结束时间（任务离开该状态的时间）';
+COMMENT ON COLUMN "public"."task_deadlines"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."task_deadlines"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."task_deadlines"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_task_deadlines_task_id" ON "public"."task_deadlines" USING btree ("task_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_deadlines_due_at" ON "public"."task_deadlines" USING btree ("due_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_deadlines_status" ON "public"."task_deadlines" USING btree ("status" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_deadlines_deleted_at" ON "public"."task_deadlines" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."task_deadlines" ADD CONSTRAINT "task_deadlines_task_id_fkey"
+    FOREIGN KEY ("task_id") REFERENCES "public"."tasks" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_task_deadlines_updated_at"
+    BEFORE UPDATE ON "public"."task_deadlines"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- 为当前已处于待提交方案状态、设置了方案截止天数的任务补建截止时间（以最近一次进入该状态的时间开始计时）
+INSERT INTO "public"."task_deadlines" ("task_id", "kind", "status_code", "started_at", "due_at", "status")
+SELECT t."id", 'solution', t."status_code", entered."at", entered."at" + make_interval(days => t."solution_deadline"), 'active'
+FROM "public"."tasks" t
+JOIN LATERAL (
+    SELECT COALESCE(MAX(l."created_at"), t."updated_at") AS "at"
+    FROM "public"."task_change_logs" l
+    WHERE l."task_id" = t."id" AND l."field_name" = 'status_code' AND l."new_value" = t."status_code"
+) entered ON TRUE
+WHERE t."deleted_at" IS NULL
+  AND t."status_code" = 'req_pending_solution'
+  AND t."solution_deadline" > 0;
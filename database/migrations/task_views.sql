@@ -0,0 +1,55 @@
+-- ============================================
+-- 任务保存视图迁移脚本
+-- Task Saved Views Migration
+-- ============================================
+
+-- ============================================
+-- 任务保存视图表 (task_views)
+-- ============================================
+DROP TABLE IF EXISTS "public"."task_views";
+CREATE SEQUENCE IF NOT EXISTS "public"."task_views_id_seq";
+CREATE TABLE "public"."task_views" (
+    "id" int4 NOT NULL DEFAULT nextval('task_views_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    "owner_id" int4 NOT NULL,
+    "source" varchar(20) DEFAULT 'all',
+    "filters" jsonb,
+    "sort_by" varchar(50),
+    "sort_order" varchar(10),
+    "columns" jsonb,
+    "shared_department_id" int4,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."task_views" IS '任务保存视图表（命名的筛选条件+排序+展示列）';
+COMMENT ON COLUMN "public"."task_views"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."task_views"."name" IS '视图名称';
+COMMENT ON COLUMN "public"."task_views"."description" IS '视图描述';
+COMMENT ON COLUMN "public"."task_views"."owner_id" IS '视图所有者用户ID';
+COMMENT ON COLUMN "public"."task_views"."source" IS '数据来源：all-全部任务，my-我的任务';
+COMMENT ON COLUMN "public"."task_views"."filters" IS '筛选条件JSON（与任务列表查询参数一致）';
+COMMENT ON COLUMN "public"."task_views"."sort_by" IS '排序字段';
+COMMENT ON COLUMN "public"."task_views"."sort_order" IS '排序方向：asc/desc';
+COMMENT ON COLUMN "public"."task_views"."columns" IS '展示列JSON（字段名数组）';
+COMMENT ON COLUMN "public"."task_views"."shared_department_id" IS '共享的部门ID（为空表示仅所有者可见）';
+COMMENT ON COLUMN "public"."task_views"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."task_views"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."task_views"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_task_views_owner_id" ON "public"."task_views" USING btree ("owner_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_views_shared_department_id" ON "public"."task_views" USING btree ("shared_department_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_views_deleted_at" ON "public"."task_views" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."task_views" ADD CONSTRAINT "task_views_owner_id_fkey"
+    FOREIGN KEY ("owner_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."task_views" ADD CONSTRAINT "task_views_shared_department_id_fkey"
+    FOREIGN KEY ("shared_department_id") REFERENCES "public"."departments" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_task_views_updated_at"
+    BEFORE UPDATE ON "public"."task_views"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
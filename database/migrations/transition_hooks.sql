@@ -0,0 +1,32 @@
+-- ============================================
+-- 状态转换守卫与后置动作迁移脚本
+-- Transition Guards & Post Actions Migration
+-- ============================================
+
+-- ============================================
+-- 状态转换规则：守卫与后置动作配置
+-- ============================================
+ALTER TABLE "public"."task_status_transitions" ADD COLUMN IF NOT EXISTS "guards" jsonb DEFAULT '[]'::jsonb;
+ALTER TABLE "public"."task_status_transitions" ADD COLUMN IF NOT EXISTS "post_actions" jsonb DEFAULT '["rollup_parent"]'::jsonb;
+
+COMMENT ON COLUMN "public"."task_status_transitions"."guards" IS '转换前必须通过的守卫名称列表（如 has_approved_solution/all_subtasks_done/no_open_blockers）';
+COMMENT ON COLUMN "public"."task_status_transitions"."post_actions" IS '转换成功后执行的后置动作名称列表（如 rollup_parent/notify/create_review）';
+
+-- 已有转换规则：无守卫，保留原有的父任务联动更新
+UPDATE "public"."task_status_transitions" SET "guards" = '[]'::jsonb WHERE "guards" IS NULL;
+UPDATE "public"."task_status_transitions" SET "post_actions" = '["rollup_parent"]'::jsonb WHERE "post_actions" IS NULL;
+
+-- ============================================
+-- 需求类任务：原硬编码校验迁移为守卫配置
+-- ============================================
+-- 待提交方案 -> 方案审核中：需要有已通过的思路方案
+UPDATE "public"."task_status_transitions" SET "guards" = '["has_approved_solution"]'::jsonb
+WHERE "task_type_code" = 'requirement'
+  AND "from_status_code" = 'req_pending_solution'
+  AND "to_status_code" = 'req_solution_review';
+
+-- 待提交计划 -> 计划审核中：需要有已通过的执行计划
+UPDATE "public"."task_status_transitions" SET "guards" = '["has_approved_plan"]'::jsonb
+WHERE "task_type_code" = 'requirement'
+  AND "from_status_code" = 'req_pending_plan'
+  AND "to_status_code" = 'req_plan_review';
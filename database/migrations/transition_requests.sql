@@ -0,0 +1,66 @@
+-- ============================================
+-- 状态转换审批迁移脚本
+-- Transition Approval Requests Migration
+-- ============================================
+
+-- ============================================
+-- 状态转换申请表 (task_transition_requests)
+-- ============================================
+DROP TABLE IF EXISTS "public"."task_transition_requests";
+CREATE SEQUENCE IF NOT EXISTS "public"."task_transition_requests_id_seq";
+CREATE TABLE "public"."task_transition_requests" (
+    "id" int4 NOT NULL DEFAULT nextval('task_transition_requests_id_seq'::regclass),
+    "task_id" int4 NOT NULL,
+    "transition_id" int4 NOT NULL,
+    "from_status_code" varchar(50) NOT NULL,
+    "to_status_code" varchar(50) NOT NULL,
+    "requested_by" int4 NOT NULL,
+    "comment" text,
+    "status" varchar(20) DEFAULT 'pending',
+    "review_session_id" int4,
+    "decided_by" int4,
+    "decided_at" timestamptz(6),
+    "decision_comment" text,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."task_transition_requests" IS '状态转换申请表（需要审批的转换在审批通过后才变更任务状态）';
+COMMENT ON COLUMN "public"."task_transition_requests"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."task_transition_requests"."task_id" IS '任务ID';
+COMMENT ON COLUMN "public"."task_transition_requests"."transition_id" IS '命中的转换规则ID';
+COMMENT ON COLUMN "public"."task_transition_requests"."from_status_code" IS '源状态编码（申请时的任务状态）';
+COMMENT ON COLUMN "public"."task_transition_requests"."to_status_code" IS '目标状态编码';
+COMMENT ON COLUMN "public"."task_transition_requests"."requested_by" IS '申请人用户ID';
+COMMENT ON COLUMN "public"."task_transition_requests"."comment" IS '申请备注';
+COMMENT ON COLUMN "public"."task_transition_requests"."status" IS '申请状态：pending-待审批，approved-已通过，rejected-已驳回，cancelled-已撤回/已失效';
+COMMENT ON COLUMN "public"."task_transition_requests"."review_session_id" IS '关联的审核会话ID';
+COMMENT ON COLUMN "public"."task_transition_requests"."decided_by" IS '审批人用户ID';
+COMMENT ON COLUMN "public"."task_transition_requests"."decided_at" IS '审批时间';
+COMMENT ON COLUMN "public"."task_transition_requests"."decision_comment" IS '审批备注';
+COMMENT ON COLUMN "public"."task_transition_requests"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."task_transition_requests"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."task_transition_requests"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_task_transition_requests_task_id" ON "public"."task_transition_requests" USING btree ("task_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_transition_requests_requested_by" ON "public"."task_transition_requests" USING btree ("requested_by" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_transition_requests_status" ON "public"."task_transition_requests" USING btree ("status" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_task_transition_requests_review_session_id" ON "public"."task_transition_requests" USING btree ("review_session_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+-- 同一任务同时只允许一个待审批的申请
+CREATE UNIQUE INDEX "idx_task_transition_requests_pending_task" ON "public"."task_transition_requests" USING btree ("task_id")
+    WHERE "status" = 'pending' AND "deleted_at" IS NULL;
+CREATE INDEX "idx_task_transition_requests_deleted_at" ON "public"."task_transition_requests" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."task_transition_requests" ADD CONSTRAINT "task_transition_requests_task_id_fkey"
+    FOREIGN KEY ("task_id") REFERENCES "public"."tasks" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."task_transition_requests" ADD CONSTRAINT "task_transition_requests_requested_by_fkey"
+    FOREIGN KEY ("requested_by") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."task_transition_requests" ADD CONSTRAINT "task_transition_requests_review_session_id_fkey"
+    FOREIGN KEY ("review_session_id") REFERENCES "public"."review_sessions" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_task_transition_requests_updated_at"
+    BEFORE UPDATE ON "public"."task_transition_requests"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
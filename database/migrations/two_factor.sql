@@ -0,0 +1,78 @@
+-- ============================================
+-- 两步验证迁移脚本
+-- Two-Factor Authentication Migration
+-- ============================================
+
+-- ============================================
+-- 两步验证配置表 (user_two_factors)
+-- ============================================
+DROP TABLE IF EXISTS "public"."user_two_factors" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."user_two_factors_id_seq";
+CREATE TABLE "public"."user_two_factors" (
+    "id" int4 NOT NULL DEFAULT nextval('user_two_factors_id_seq'::regclass),
+    "user_id" int4 NOT NULL,
+    "secret" varchar(64) NOT NULL,
+    "enabled_at" timestamptz(6),
+    "last_used_step" int8 DEFAULT 0,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."user_two_factors" IS '两步验证配置表（TOTP，每个用户一条，解除绑定时删除）';
+COMMENT ON COLUMN "public"."user_two_factors"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."user_two_factors"."user_id" IS '用户ID';
+COMMENT ON COLUMN "public"."user_two_factors"."secret" IS 'TOTP 密钥（Base32）';
+COMMENT ON COLUMN "public"."user_two_factors"."enabled_at" IS '启用时间（为空表示已发起绑定但尚未验证）';
+COMMENT ON COLUMN "public"."user_two_factors"."last_used_step" IS '最近一次验证通过的时间步（防止动态验证码重复使用）';
+COMMENT ON COLUMN "public"."user_two_factors"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."user_two_factors"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."user_two_factors"."deleted_at" IS '软删除时间';
+
+CREATE UNIQUE INDEX "idx_user_two_factors_user_id" ON "public"."user_two_factors" USING btree ("user_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_user_two_factors_deleted_at" ON "public"."user_two_factors" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."user_two_factors" ADD CONSTRAINT "user_two_factors_user_id_fkey"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_user_two_factors_updated_at"
+    BEFORE UPDATE ON "public"."user_two_factors"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 两步验证恢复码表 (user_recovery_codes)
+-- ============================================
+DROP TABLE IF EXISTS "public"."user_recovery_codes" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."user_recovery_codes_id_seq";
+CREATE TABLE "public"."user_recovery_codes" (
+    "id" int4 NOT NULL DEFAULT nextval('user_recovery_codes_id_seq'::regclass),
+    "user_id" int4 NOT NULL,
+    "code_hash" varchar(64) NOT NULL,
+    "used_at" timestamptz(6),
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."user_recovery_codes" IS '两步验证恢复码表（只保存哈希，每个恢复码只能使用一次）';
+COMMENT ON COLUMN "public"."user_recovery_codes"."id" IS '主键ID';
+COMMENT ON COLUMN "public"."user_recovery_codes"."user_id" IS '用户ID';
+COMMENT ON COLUMN "public"."user_recovery_codes"."code_hash" IS '恢复码哈希（SHA-256）';
+COMMENT ON COLUMN "public"."user_recovery_codes"."used_at" IS '使用时间（为空表示未使用）';
+COMMENT ON COLUMN "public"."user_recovery_codes"."created_at" IS '创建时间';
+COMMENT ON COLUMN "public"."user_recovery_codes"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."user_recovery_codes"."deleted_at" IS '软删除时间';
+
+CREATE INDEX "idx_user_recovery_codes_user_id" ON "public"."user_recovery_codes" USING btree ("user_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_user_recovery_codes_deleted_at" ON "public"."user_recovery_codes" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."user_recovery_codes" ADD CONSTRAINT "user_recovery_codes_user_id_fkey"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_user_recovery_codes_updated_at"
+    BEFORE UPDATE ON "public"."user_recovery_codes"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
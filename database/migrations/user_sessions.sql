@@ -0,0 +1,65 @@
+-- ============================================
+-- 登录会话迁移脚本
+-- User Sessions Migration
+-- ============================================
+
+-- ============================================
+-- 登录会话表 (user_sessions)
+-- ============================================
+DROP TABLE IF EXISTS "public"."user_sessions" CASCADE;
+CREATE SEQUENCE IF NOT EXISTS "public"."user_sessions_id_seq";
+CREATE TABLE "public"."user_sessions" (
+    "id" int4 NOT NULL DEFAULT nextval('user_sessions_id_seq'::regclass),
+    "user_id" int4 NOT NULL,
+    "family_id" varchar(64) NOT NULL,
+    "login_method" varchar(20) NOT NULL,
+    "device" varchar(100),
+    "user_agent" varchar(500),
+    "ip" varchar(64),
+    "last_seen_at" timestamptz(6),
+    "last_seen_ip" varchar(64),
+    "expires_at" timestamptz(6) NOT NULL,
+    "revoked_at" timestamptz(6),
+    "revoke_reason" varchar(50),
+    "revoked_by" int4,
+    "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamptz(6),
+    PRIMARY KEY ("id")
+);
+
+COMMENT ON TABLE "public"."user_sessions" IS '登录会话表（每次登录一个会话，会话内的刷新令牌属于同一令牌族）';
+COMMENT ON COLUMN "public"."user_sessions"."id" IS '主键ID（写入访问令牌的 session_id）';
+COMMENT ON COLUMN "public"."user_sessions"."user_id" IS '用户ID';
+COMMENT ON COLUMN "public"."user_sessions"."family_id" IS '令牌族ID（对应 refresh_tokens.family_id）';
+COMMENT ON COLUMN "public"."user_sessions"."login_method" IS '登录方式：password-密码，ldap-LDAP 目录，oidc-OIDC 单点登录，wechat_open-微信扫码，wechat_mp-微信小程序，wechat_h5-微信公众号H5';
+COMMENT ON COLUMN "public"."user_sessions"."device" IS '设备名称（客户端上报，未上报时根据 User-Agent 识别）';
+COMMENT ON COLUMN "public"."user_sessions"."user_agent" IS 'User-Agent';
+COMMENT ON COLUMN "public"."user_sessions"."ip" IS '登录IP';
+COMMENT ON COLUMN "public"."user_sessions"."last_seen_at" IS '最近访问时间';
+COMMENT ON COLUMN "public"."user_sessions"."last_seen_ip" IS '最近访问IP';
+COMMENT ON COLUMN "public"."user_sessions"."expires_at" IS '会话过期时间（随刷新令牌轮换延长）';
+COMMENT ON COLUMN "public"."user_sessions"."revoked_at" IS '结束时间（注销、被踢出或被吊销）';
+COMMENT ON COLUMN "public"."user_sessions"."revoke_reason" IS '结束原因：logout-注销，killed-用户结束，admin_revoked-管理员结束，reuse-刷新令牌重复使用，password_changed-修改密码，disabled-禁用，deleted-删除';
+COMMENT ON COLUMN "public"."user_sessions"."revoked_by" IS '结束会话的操作人ID';
+COMMENT ON COLUMN "public"."user_sessions"."created_at" IS '创建时间（登录时间）';
+COMMENT ON COLUMN "public"."user_sessions"."updated_at" IS '更新时间';
+COMMENT ON COLUMN "public"."user_sessions"."deleted_at" IS '软删除时间';
+
+CREATE UNIQUE INDEX "idx_user_sessions_family_id" ON "public"."user_sessions" USING btree ("family_id" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST);
+CREATE INDEX "idx_user_sessions_user_id" ON "public"."user_sessions" USING btree ("user_id" "pg_catalog"."int4_ops" ASC NULLS LAST);
+CREATE INDEX "idx_user_sessions_expires_at" ON "public"."user_sessions" USING btree ("expires_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+CREATE INDEX "idx_user_sessions_deleted_at" ON "public"."user_sessions" USING btree ("deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST);
+
+ALTER TABLE "public"."user_sessions" ADD CONSTRAINT "user_sessions_user_id_fkey"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+ALTER TABLE "public"."user_sessions" ADD CONSTRAINT "user_sessions_revoked_by_fkey"
+    FOREIGN KEY ("revoked_by") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+CREATE TRIGGER "update_user_sessions_updated_at"
+    BEFORE UPDATE ON "public"."user_sessions"
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- 会话上线前签发的刷新令牌没有对应的会话，刷新时需重新登录
+UPDATE "public"."refresh_tokens" SET "revoked_at" = CURRENT_TIMESTAMP, "revoke_reason" = 'logout' WHERE "revoked_at" IS NULL;
@@ -0,0 +1,62 @@
+-- ============================================
+-- 流程设计器迁移脚本
+-- Workflow Designer Migration
+-- ============================================
+
+-- ============================================
+-- 任务类型：入口状态配置
+-- ============================================
+ALTER TABLE "public"."task_types" ADD COLUMN IF NOT EXISTS "pool_status_code" varchar(50);
+ALTER TABLE "public"."task_types" ADD COLUMN IF NOT EXISTS "assigned_status_code" varchar(50);
+ALTER TABLE "public"."task_types" ADD COLUMN IF NOT EXISTS "accepted_status_code" varchar(50);
+
+COMMENT ON COLUMN "public"."task_types"."pool_status_code" IS '未指派执行人时的状态编码';
+COMMENT ON COLUMN "public"."task_types"."assigned_status_code" IS '已指派、待执行人接受时的状态编码';
+COMMENT ON COLUMN "public"."task_types"."accepted_status_code" IS '执行人接受后的状态编码';
+
+UPDATE "public"."task_types" SET
+    "pool_status_code" = 'req_pending_assign',
+    "assigned_status_code" = 'req_pending_accept',
+    "accepted_status_code" = 'req_pending_solution'
+WHERE "code" = 'requirement';
+
+UPDATE "public"."task_types" SET
+    "pool_status_code" = 'unit_pending_assign',
+    "assigned_status_code" = 'unit_pending_accept',
+    "accepted_status_code" = 'unit_pending_start'
+WHERE "code" = 'unit_task';
+
+-- ============================================
+-- 任务状态：状态分类与初始状态
+-- ============================================
+ALTER TABLE "public"."task_statuses" ADD COLUMN IF NOT EXISTS "category" varchar(20) NOT NULL DEFAULT 'todo';
+ALTER TABLE "public"."task_statuses" ADD COLUMN IF NOT EXISTS "is_initial" bool DEFAULT false;
+ALTER TABLE "public"."task_statuses" ADD COLUMN IF NOT EXISTS "allows_subtasks" bool NOT NULL DEFAULT true;
+
+COMMENT ON COLUMN "public"."task_statuses"."category" IS '状态分类（todo-待处理, in_progress-进行中, done-已完成, blocked-受阻, cancelled-已取消）';
+COMMENT ON COLUMN "public"."task_statuses"."is_initial" IS '是否为初始状态（任务可直接以该状态创建）';
+COMMENT ON COLUMN "public"."task_statuses"."allows_subtasks" IS '是否允许在该状态的任务下创建子任务（已完成、已取消分类的状态始终不允许）';
+
+CREATE INDEX IF NOT EXISTS "idx_task_statuses_category" ON "public"."task_statuses" USING btree ("category");
+
+UPDATE "public"."task_statuses" SET "category" = 'in_progress'
+WHERE "code" IN ('req_in_progress', 'unit_in_progress');
+
+UPDATE "public"."task_statuses" SET "category" = 'done'
+WHERE "code" IN ('req_completed', 'unit_completed');
+
+UPDATE "public"."task_statuses" SET "category" = 'blocked'
+WHERE "code" IN ('req_blocked', 'unit_blocked');
+
+UPDATE "public"."task_statuses" SET "category" = 'cancelled'
+WHERE "code" IN ('req_cancelled', 'unit_cancelled');
+
+-- 需求任务在待开始（req_pending_start）之前不允许创建子任务
+UPDATE "public"."task_statuses" SET "allows_subtasks" = false
+WHERE "code" IN ('req_pending_assign', 'req_pending_accept', 'req_pending_solution',
+                 'req_solution_review', 'req_solution_rejected', 'req_pending_plan',
+                 'req_plan_review', 'req_plan_rejected');
+
+UPDATE "public"."task_statuses" SET "is_initial" = true
+WHERE "code" IN ('req_draft', 'req_pending_assign', 'req_pending_accept',
+                 'unit_draft', 'unit_pending_assign', 'unit_pending_accept');
@@ -0,0 +1,44 @@
+package dto
+
+// AuditLogQuery 审计日志查询参数
+type AuditLogQuery struct {
+	PaginationRequest
+	// 事件类型（不传表示全部）
+	Action string `form:"action" binding:"omitempty,max=50"`
+	// 相关用户ID
+	UserID uint `form:"user_id"`
+	// 事件对象（手机号、IP）
+	Target string `form:"target" binding:"omitempty,max=100"`
+}
+
+// AuditLogResponse 审计日志响应
+type AuditLogResponse struct {
+	// 日志ID
+	ID uint `json:"id"`
+	// 事件类型
+	Action string `json:"action"`
+	// 相关用户ID
+	UserID *uint `json:"user_id,omitempty"`
+	// 相关用户名
+	Username string `json:"username,omitempty"`
+	// 操作人ID
+	ActorID *uint `json:"actor_id,omitempty"`
+	// 操作人用户名
+	ActorName string `json:"actor_name,omitempty"`
+	// 事件对象
+	Target string `json:"target"`
+	// 请求来源IP
+	IP string `json:"ip"`
+	// 事件详情
+	Detail string `json:"detail"`
+	// 发生时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
+
+// UnlockLoginRequest 解除登录锁定请求（用户ID和IP至少传一个）
+type UnlockLoginRequest struct {
+	// 解除锁定的用户ID
+	UserID uint `json:"user_id" example:"1"`
+	// 解除锁定的IP
+	IP string `json:"ip" binding:"omitempty,ip" example:"192.168.1.10"`
+}
@@ -18,14 +18,54 @@ type LoginRequest struct {
 	Mobile string `json:"mobile" binding:"required,mobile" example:"13800138000"`
 	// 密码（用户登录密码）
 	Password string `json:"password" binding:"required" example:"password123"`
+	// 设备名称（选填，不传时根据 User-Agent 识别，用于会话管理）
+	Device string `json:"device" binding:"omitempty,max=100" example:"张三的笔记本"`
 }
 
 // LoginResponse 登录响应
 type LoginResponse struct {
 	// JWT 访问令牌（用于后续API请求的身份验证）
 	Token string `json:"token"`
-	// 用户信息（包含用户基本信息和权限等）
+	// 刷新令牌（访问令牌过期后用于换取新令牌）
+	RefreshToken string `json:"refresh_token"`
+	// 访问令牌有效期（秒）
+	ExpiresIn int64 `json:"expires_in"`
+	// 刷新令牌有效期（秒）
+	RefreshExpiresIn int64 `json:"refresh_expires_in"`
+	// 用户信息（包含用户基本信息和权限等，需要两步验证时不返回）
 	UserInfo interface{} `json:"user_info"`
+	// 是否需要输入两步验证码（为 true 时不返回令牌，使用 two_factor_token 调用两步验证登录接口）
+	TwoFactorRequired bool `json:"two_factor_required,omitempty"`
+	// 是否需要先绑定两步验证（账号必须启用两步验证但尚未绑定）
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+	// 两步验证凭证（短期有效，只能用于完成本次登录）
+	TwoFactorToken string `json:"two_factor_token,omitempty"`
+	// 恢复码（绑定两步验证完成登录时返回，仅显示一次）
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	// 刷新令牌（登录或上次刷新时返回，使用后即失效）
+	RefreshToken string `json:"refresh_token" binding:"required" example:"xxx"`
+}
+
+// LogoutRequest 注销请求
+type LogoutRequest struct {
+	// 刷新令牌（选填，不传时按当前访问令牌注销所属的登录会话）
+	RefreshToken string `json:"refresh_token" example:"xxx"`
+}
+
+// TokenResponse 刷新令牌响应
+type TokenResponse struct {
+	// JWT 访问令牌
+	Token string `json:"token"`
+	// 新的刷新令牌（原刷新令牌已失效）
+	RefreshToken string `json:"refresh_token"`
+	// 访问令牌有效期（秒）
+	ExpiresIn int64 `json:"expires_in"`
+	// 刷新令牌有效期（秒）
+	RefreshExpiresIn int64 `json:"refresh_expires_in"`
 }
 
 // WechatLoginRequest 微信登录请求
@@ -36,6 +76,8 @@ type WechatLoginRequest struct {
 	LoginType string `json:"login_type" binding:"required,oneof=scan mp h5" example:"scan"`
 	// 手机号授权码（小程序获取手机号时使用，可选）
 	PhoneCode string `json:"phone_code" binding:"omitempty" example:"xxx"`
+	// 设备名称（选填，不传时根据 User-Agent 识别，用于会话管理）
+	Device string `json:"device" binding:"omitempty,max=100" example:"张三的笔记本"`
 }
 
 // WechatLoginResponse 微信登录响应
@@ -48,8 +90,20 @@ type WechatLoginResponse struct {
 	WechatInfo *WechatUserInfo `json:"wechat_info,omitempty"`
 	// JWT令牌（已绑定用户直接返回）
 	Token string `json:"token,omitempty"`
+	// 刷新令牌（已绑定用户直接返回）
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// 访问令牌有效期（秒）
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+	// 刷新令牌有效期（秒）
+	RefreshExpiresIn int64 `json:"refresh_expires_in,omitempty"`
 	// 用户信息（已绑定用户返回）
 	UserInfo interface{} `json:"user_info,omitempty"`
+	// 是否需要输入两步验证码
+	TwoFactorRequired bool `json:"two_factor_required,omitempty"`
+	// 是否需要先绑定两步验证
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+	// 两步验证凭证
+	TwoFactorToken string `json:"two_factor_token,omitempty"`
 }
 
 // WechatUserInfo 微信用户信息
@@ -72,4 +126,6 @@ type WechatBindRequest struct {
 	UserName string `json:"username" binding:"required,min=2,max=50" example:"张三"`
 	// 密码
 	Password string `json:"password" binding:"required,min=6,max=20" example:"password123"`
+	// 设备名称（选填，不传时根据 User-Agent 识别，用于会话管理）
+	Device string `json:"device" binding:"omitempty,max=100" example:"张三的笔记本"`
 }
@@ -40,3 +40,15 @@ type PaginationResponse struct {
 	// 分页数据列表
 	Data interface{} `json:"data"`
 }
+
+// CursorPaginationResponse 游标分页响应
+type CursorPaginationResponse struct {
+	// 每页数量
+	PageSize int `json:"page_size"`
+	// 下一页游标（没有更多数据时为空）
+	NextCursor string `json:"next_cursor"`
+	// 是否还有更多数据
+	HasMore bool `json:"has_more"`
+	// 分页数据列表
+	Data interface{} `json:"data"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+// JobResponse 定时任务响应
+type JobResponse struct {
+	// 任务名称
+	Name string `json:"name"`
+	// cron 表达式
+	Spec string `json:"spec"`
+	// 描述
+	Description string `json:"description"`
+	// 超时时间（秒）
+	TimeoutSeconds int64 `json:"timeout_seconds"`
+	// 下次计划执行时间（调度未启用时为空）
+	NextRunAt *ResponseTime `json:"next_run_at,omitempty"`
+	// 最近一次执行
+	LastRun *JobRunResponse `json:"last_run,omitempty"`
+	// 最近一次失败的执行
+	LastFailure *JobRunResponse `json:"last_failure,omitempty"`
+}
+
+// JobRunQuery 执行记录查询参数
+type JobRunQuery struct {
+	PaginationRequest
+	// 执行状态：running/success/failed（不传表示全部）
+	Status string `form:"status" binding:"omitempty,oneof=running success failed"`
+}
+
+// JobRunResponse 执行记录响应
+type JobRunResponse struct {
+	// 执行记录ID
+	ID uint `json:"id"`
+	// 任务名称
+	JobName string `json:"job_name"`
+	// 触发方式：schedule/manual
+	Trigger string `json:"trigger"`
+	// 手动触发的用户ID
+	TriggeredBy *uint `json:"triggered_by,omitempty"`
+	// 手动触发的用户名
+	TriggeredByName string `json:"triggered_by_name,omitempty"`
+	// 执行的实例
+	Instance string `json:"instance"`
+	// 执行状态：running/success/failed
+	Status string `json:"status"`
+	// 开始时间
+	StartedAt ResponseTime `json:"started_at"`
+	// 结束时间
+	FinishedAt *ResponseTime `json:"finished_at,omitempty"`
+	// 耗时（毫秒）
+	DurationMs int64 `json:"duration_ms"`
+	// 执行结果摘要
+	Output string `json:"output"`
+	// 错误信息
+	Error string `json:"error,omitempty"`
+}
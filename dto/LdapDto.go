@@ -0,0 +1,33 @@
+package dto
+
+// LdapLoginRequest LDAP 目录账号登录请求
+type LdapLoginRequest struct {
+	// 目录登录名（如 uid 或 sAMAccountName）
+	Username string `json:"username" binding:"required,max=100" example:"zhangsan"`
+	// 目录密码
+	Password string `json:"password" binding:"required,max=128" example:"password123"`
+	// 设备名称（选填，不传时根据 User-Agent 识别，用于会话管理）
+	Device string `json:"device" binding:"omitempty,max=100" example:"张三的笔记本"`
+}
+
+// LdapSyncResult LDAP 同步结果
+type LdapSyncResult struct {
+	// 新建部门数
+	DepartmentsCreated int `json:"departments_created"`
+	// 更新部门数（名称或上级变化）
+	DepartmentsUpdated int `json:"departments_updated"`
+	// 新建用户数
+	UsersCreated int `json:"users_created"`
+	// 更新用户数
+	UsersUpdated int `json:"users_updated"`
+	// 按手机号关联到已有本地账号的用户数
+	UsersLinked int `json:"users_linked"`
+	// 已从目录移除而禁用的用户数
+	UsersDisabled int `json:"users_disabled"`
+	// 目录中缺少手机号等信息而跳过的用户数
+	UsersSkipped int `json:"users_skipped"`
+	// 新增部门负责人数
+	LeadersAdded int `json:"leaders_added"`
+	// 移除部门负责人数
+	LeadersRemoved int `json:"leaders_removed"`
+}
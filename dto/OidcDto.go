@@ -0,0 +1,84 @@
+package dto
+
+// OidcProviderResponse 单点登录身份提供方
+type OidcProviderResponse struct {
+	// 提供方标识（用于登录接口路径）
+	Name string `json:"name" example:"corp"`
+	// 显示名称
+	DisplayName string `json:"display_name" example:"企业统一身份认证"`
+}
+
+// OidcAuthorizeResponse 单点登录授权地址
+type OidcAuthorizeResponse struct {
+	// 身份提供方的授权地址（前端跳转到该地址登录）
+	AuthorizationURL string `json:"authorization_url"`
+	// state 参数（已包含在授权地址中，回调时原样提交）
+	State string `json:"state"`
+}
+
+// OidcCallbackRequest 单点登录回调请求（前端回调页面收到授权码后提交）
+type OidcCallbackRequest struct {
+	// 授权码
+	Code string `json:"code" binding:"required,max=2048" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	// 授权地址中的 state
+	State string `json:"state" binding:"required,max=2048"`
+	// 设备名称（选填，不传时根据 User-Agent 识别，用于会话管理）
+	Device string `json:"device" binding:"omitempty,max=100" example:"张三的笔记本"`
+}
+
+// OidcLoginResponse 单点登录响应
+type OidcLoginResponse struct {
+	// 是否需要绑定手机号（未能按邮箱或手机号关联到已有账号）
+	NeedBind bool `json:"need_bind"`
+	// 临时凭证（绑定手机号时使用）
+	TempToken string `json:"temp_token,omitempty"`
+	// 身份提供方返回的用户信息（需要绑定时返回，用于预填表单）
+	Profile *OidcProfile `json:"profile,omitempty"`
+	// 是否待管理员审核（绑定时新建的账号）
+	PendingApproval bool `json:"pending_approval,omitempty"`
+	// JWT令牌
+	Token string `json:"token,omitempty"`
+	// 刷新令牌
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// 访问令牌有效期（秒）
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+	// 刷新令牌有效期（秒）
+	RefreshExpiresIn int64 `json:"refresh_expires_in,omitempty"`
+	// 用户信息
+	UserInfo interface{} `json:"user_info,omitempty"`
+	// 是否需要输入两步验证码
+	TwoFactorRequired bool `json:"two_factor_required,omitempty"`
+	// 是否需要先绑定两步验证
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+	// 两步验证凭证
+	TwoFactorToken string `json:"two_factor_token,omitempty"`
+}
+
+// OidcProfile 身份提供方返回的用户信息
+type OidcProfile struct {
+	// 身份提供方标识
+	Provider string `json:"provider"`
+	// 姓名
+	Name string `json:"name,omitempty"`
+	// 邮箱
+	Email string `json:"email,omitempty"`
+	// 手机号
+	Mobile string `json:"mobile,omitempty"`
+	// 部门名称
+	Department string `json:"department,omitempty"`
+}
+
+// OidcBindRequest 单点登录绑定手机号请求
+// 手机号已注册时需输入该账号的登录密码完成关联；未注册时创建新账号，需管理员审核
+type OidcBindRequest struct {
+	// 临时凭证
+	TempToken string `json:"temp_token" binding:"required"`
+	// 手机号码
+	Mobile string `json:"mobile" binding:"required,mobile" example:"13800138000"`
+	// 用户名/真实姓名（新建账号时使用）
+	UserName string `json:"username" binding:"required,min=2,max=50" example:"张三"`
+	// 密码（关联已有账号时为该账号的登录密码，新建账号时为新密码）
+	Password string `json:"password" binding:"required,min=6,max=20" example:"password123"`
+	// 设备名称（选填，不传时根据 User-Agent 识别，用于会话管理）
+	Device string `json:"device" binding:"omitempty,max=100" example:"张三的笔记本"`
+}
@@ -0,0 +1,111 @@
+package dto
+
+// CreateAnnotationRequest 创建审核批注请求
+type CreateAnnotationRequest struct {
+	// 批注对象：requirement_solutions-思路方案，execution_plans-执行计划
+	TargetType string `json:"target_type" binding:"required,oneof=requirement_solutions execution_plans"`
+	// 版本号（不传表示最新版本）
+	Version int `json:"version" binding:"omitempty,min=1"`
+	// 锚点类型：text-文本片段，step-实施步骤，goal-目标
+	AnchorType string `json:"anchor_type" binding:"required,oneof=text step goal"`
+	// 文本锚点的字段名：方案为 title/content/mindmap_markdown，执行计划为 title/tech_stack/resource_requirements/risk_assessment
+	Field string `json:"field" binding:"omitempty,max=50"`
+	// 文本锚点的起始字符偏移（含，按 Unicode 字符计）
+	StartOffset int `json:"start_offset" binding:"min=0"`
+	// 文本锚点的结束字符偏移（不含）
+	EndOffset int `json:"end_offset" binding:"min=0"`
+	// 选中的原文（可选，传入时校验与版本内容一致）
+	QuotedText string `json:"quoted_text"`
+	// 步骤锚点的 JSON 路径（如 steps[1]）
+	StepPath string `json:"step_path" binding:"omitempty,max=255"`
+	// 目标锚点的目标ID
+	GoalID uint `json:"goal_id"`
+	// 批注内容
+	Content string `json:"content" binding:"required,max=5000"`
+}
+
+// AnnotationQuery 审核批注列表查询参数
+type AnnotationQuery struct {
+	// 批注对象（不传表示全部）
+	TargetType string `form:"target_type" binding:"omitempty,oneof=requirement_solutions execution_plans"`
+	// 当前锚定的版本号（不传表示全部）
+	Version int `form:"version" binding:"omitempty,min=1"`
+	// 状态：open-未解决，resolved-已解决（不传表示全部）
+	Status string `form:"status" binding:"omitempty,oneof=open resolved"`
+}
+
+// AnnotationReplyRequest 回复批注请求
+type AnnotationReplyRequest struct {
+	// 回复内容
+	Content string `json:"content" binding:"required,max=5000"`
+}
+
+// AnnotationReplyResponse 批注回复响应
+type AnnotationReplyResponse struct {
+	// 回复ID
+	ID uint `json:"id"`
+	// 回复人用户ID
+	AuthorID uint `json:"author_id"`
+	// 回复人用户名
+	AuthorName string `json:"author_name"`
+	// 回复内容
+	Content string `json:"content"`
+	// 回复时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
+
+// ReviewAnnotationResponse 审核批注响应
+type ReviewAnnotationResponse struct {
+	// 批注ID
+	ID uint `json:"id"`
+	// 任务ID
+	TaskID uint `json:"task_id"`
+	// 批注对象：requirement_solutions/execution_plans
+	TargetType string `json:"target_type"`
+	// 当前锚定的版本记录ID
+	TargetID uint `json:"target_id"`
+	// 当前锚定的版本号
+	Version int `json:"version"`
+	// 创建批注时的版本号
+	OriginVersion int `json:"origin_version"`
+	// 创建批注时进行中的审核会话ID
+	ReviewSessionID *uint `json:"review_session_id,omitempty"`
+	// 锚点类型：text/step/goal
+	AnchorType string `json:"anchor_type"`
+	// 锚点状态：anchored-有效，outdated-新版本中找不到原锚点
+	AnchorStatus string `json:"anchor_status"`
+	// 文本锚点的字段名
+	Field string `json:"field,omitempty"`
+	// 文本锚点的起始字符偏移
+	StartOffset int `json:"start_offset"`
+	// 文本锚点的结束字符偏移
+	EndOffset int `json:"end_offset"`
+	// 批注时选中的原文
+	QuotedText string `json:"quoted_text"`
+	// 步骤锚点的 JSON 路径
+	StepPath string `json:"step_path,omitempty"`
+	// 目标锚点的目标ID
+	GoalID *uint `json:"goal_id,omitempty"`
+	// 目标锚点的目标编号
+	GoalNo int `json:"goal_no,omitempty"`
+	// 批注人用户ID
+	AuthorID uint `json:"author_id"`
+	// 批注人用户名
+	AuthorName string `json:"author_name"`
+	// 批注内容
+	Content string `json:"content"`
+	// 是否已解决
+	IsResolved bool `json:"is_resolved"`
+	// 解决人用户ID
+	ResolvedBy *uint `json:"resolved_by,omitempty"`
+	// 解决人用户名
+	ResolverName string `json:"resolver_name,omitempty"`
+	// 解决时间
+	ResolvedAt *ResponseTime `json:"resolved_at,omitempty"`
+	// 最近一次转移到新版本的时间
+	CarriedAt *ResponseTime `json:"carried_at,omitempty"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+	// 回复列表（按时间正序）
+	Replies []AnnotationReplyResponse `json:"replies"`
+}
@@ -0,0 +1,139 @@
+package dto
+
+// ReviewRoleWeightItem 审核人角色投票权重
+type ReviewRoleWeightItem struct {
+	// 审核人角色（leader=部门负责人, jury=陪审团成员, expert=评审专家）
+	ReviewerRole string `json:"reviewer_role" binding:"required,oneof=leader jury expert"`
+	// 投票权重（加权投票模式使用）
+	Weight float64 `json:"weight" binding:"required,gt=0,lte=9.99"`
+	// 描述
+	Description string `json:"description" binding:"max=255"`
+}
+
+// SaveReviewRoleWeightsRequest 保存审核人角色投票权重请求（未包含的角色保持不变）
+type SaveReviewRoleWeightsRequest struct {
+	// 角色权重列表
+	Weights []ReviewRoleWeightItem `json:"weights" binding:"required,min=1,dive"`
+}
+
+// ReviewRubricCriterionItem 评分维度
+type ReviewRubricCriterionItem struct {
+	// 维度ID（更新时传入已有维度的ID，新增维度不传）
+	ID uint `json:"id"`
+	// 维度名称（如 可行性、完整性）
+	Name string `json:"name" binding:"required,max=100"`
+	// 维度说明
+	Description string `json:"description" binding:"max=255"`
+	// 权重（加权总分按权重占比计算）
+	Weight float64 `json:"weight" binding:"required,gt=0,lte=100"`
+	// 排序（不传时按列表顺序）
+	SortOrder int `json:"sort_order"`
+}
+
+// ReviewRubricRequest 创建/更新评分标准请求
+// 已被审核会话使用的评分标准只能修改名称、描述、启用状态和最低分
+type ReviewRubricRequest struct {
+	// 评分标准名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 适用的审核类型（solution_review=思路方案审核, plan_review=执行计划审核）
+	ReviewType string `json:"review_type" binding:"required,oneof=solution_review plan_review"`
+	// 分值下限
+	ScaleMin int `json:"scale_min" binding:"min=0"`
+	// 分值上限
+	ScaleMax int `json:"scale_max" binding:"required,gtfield=ScaleMin,max=100"`
+	// 通过所需的最低加权总分（不传表示不限制）
+	MinTotalScore *float64 `json:"min_total_score"`
+	// 是否启用（默认启用，每个审核类型最多一个启用的评分标准）
+	IsActive *bool `json:"is_active"`
+	// 描述
+	Description string `json:"description" binding:"max=255"`
+	// 评分维度
+	Criteria []ReviewRubricCriterionItem `json:"criteria" binding:"required,min=1,dive"`
+}
+
+// ReviewRubricResponse 评分标准响应
+type ReviewRubricResponse struct {
+	// 评分标准ID
+	ID uint `json:"id"`
+	// 评分标准名称
+	Name string `json:"name"`
+	// 适用的审核类型
+	ReviewType string `json:"review_type"`
+	// 分值下限
+	ScaleMin int `json:"scale_min"`
+	// 分值上限
+	ScaleMax int `json:"scale_max"`
+	// 通过所需的最低加权总分
+	MinTotalScore *float64 `json:"min_total_score,omitempty"`
+	// 是否启用
+	IsActive bool `json:"is_active"`
+	// 描述
+	Description string `json:"description"`
+	// 评分维度
+	Criteria []ReviewRubricCriterionItem `json:"criteria"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
+
+// ApprovalChainStageItem 审批链阶段
+type ApprovalChainStageItem struct {
+	// 阶段序号（响应返回；请求中按列表顺序编号）
+	StageNo int `json:"stage_no"`
+	// 阶段名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 审批人类型（executor_leader=执行人部门负责人, creator_leader=创建人部门负责人, task_leader=任务所属部门负责人, department_leader=指定部门负责人, creator=创建人, user=指定用户）
+	ApproverType string `json:"approver_type" binding:"required,oneof=executor_leader creator_leader task_leader department_leader creator user"`
+	// 指定部门ID（department_leader 类型必填）
+	ApproverDepartmentID *uint `json:"approver_department_id"`
+	// 指定用户ID（user 类型必填）
+	ApproverUserID *uint `json:"approver_user_id"`
+}
+
+// ApprovalChainTemplateRequest 创建/更新审批链模板请求
+// 有进行中的审批链使用该模板时不能修改阶段
+type ApprovalChainTemplateRequest struct {
+	// 模板名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 适用的审核类型（solution_review=思路方案审核, plan_review=执行计划审核）
+	ReviewType string `json:"review_type" binding:"required,oneof=solution_review plan_review"`
+	// 适用的任务类型编码（不传表示不限）
+	TaskTypeCode string `json:"task_type_code" binding:"max=50"`
+	// 触发条件：任务优先级达到该值（不传表示不按优先级触发）
+	MinPriority *int `json:"min_priority" binding:"omitempty,min=1,max=4"`
+	// 触发条件：跨部门任务（与优先级条件满足其一即可，均未设置时匹配全部任务）
+	MatchCrossDepartment bool `json:"match_cross_department"`
+	// 匹配顺序（多个模板匹配时取值大的）
+	SortOrder int `json:"sort_order"`
+	// 是否启用（默认启用）
+	IsActive *bool `json:"is_active"`
+	// 描述
+	Description string `json:"description" binding:"max=255"`
+	// 审批阶段（按列表顺序依次审批）
+	Stages []ApprovalChainStageItem `json:"stages" binding:"required,min=1,max=10,dive"`
+}
+
+// ApprovalChainTemplateResponse 审批链模板响应
+type ApprovalChainTemplateResponse struct {
+	// 模板ID
+	ID uint `json:"id"`
+	// 模板名称
+	Name string `json:"name"`
+	// 适用的审核类型
+	ReviewType string `json:"review_type"`
+	// 适用的任务类型编码
+	TaskTypeCode string `json:"task_type_code,omitempty"`
+	// 触发条件：任务优先级达到该值
+	MinPriority *int `json:"min_priority,omitempty"`
+	// 触发条件：跨部门任务
+	MatchCrossDepartment bool `json:"match_cross_department"`
+	// 匹配顺序
+	SortOrder int `json:"sort_order"`
+	// 是否启用
+	IsActive bool `json:"is_active"`
+	// 描述
+	Description string `json:"description"`
+	// 审批阶段
+	Stages []ApprovalChainStageItem `json:"stages"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+// CreateDelegationRequest 创建审核委托请求
+type CreateDelegationRequest struct {
+	// 代理人用户ID
+	DelegateID uint `json:"delegate_id" binding:"required"`
+	// 生效开始时间（YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS）
+	StartAt string `json:"start_at" binding:"required"`
+	// 生效结束时间（YYYY-MM-DD 表示包含当天，YYYY-MM-DD HH:MM:SS 表示到该时刻为止）
+	EndAt string `json:"end_at" binding:"required"`
+	// 限定部门ID（不传表示不限）
+	DepartmentID *uint `json:"department_id"`
+	// 限定任务类型编码（不传表示不限）
+	TaskTypeCode string `json:"task_type_code" binding:"omitempty,max=50"`
+	// 委托原因
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// DelegationQuery 审核委托列表查询参数
+type DelegationQuery struct {
+	// 我的身份：delegator-我委托的，delegate-委托给我的（不传表示全部）
+	Role string `form:"role" binding:"omitempty,oneof=delegator delegate"`
+	// 状态：upcoming-未开始，active-生效中，expired-已过期，revoked-已撤销（不传表示全部）
+	Status string `form:"status" binding:"omitempty,oneof=upcoming active expired revoked"`
+}
+
+// DelegationResponse 审核委托响应
+type DelegationResponse struct {
+	// 委托ID
+	ID uint `json:"id"`
+	// 委托人用户ID
+	DelegatorID uint `json:"delegator_id"`
+	// 委托人用户名
+	DelegatorName string `json:"delegator_name"`
+	// 代理人用户ID
+	DelegateID uint `json:"delegate_id"`
+	// 代理人用户名
+	DelegateName string `json:"delegate_name"`
+	// 生效开始时间
+	StartAt ResponseTime `json:"start_at"`
+	// 生效结束时间（不含）
+	EndAt ResponseTime `json:"end_at"`
+	// 限定部门ID
+	DepartmentID *uint `json:"department_id,omitempty"`
+	// 限定部门名称
+	DepartmentName string `json:"department_name,omitempty"`
+	// 限定任务类型编码
+	TaskTypeCode string `json:"task_type_code,omitempty"`
+	// 委托原因
+	Reason string `json:"reason"`
+	// 状态：upcoming/active/expired/revoked
+	Status string `json:"status"`
+	// 撤销时间
+	RevokedAt *ResponseTime `json:"revoked_at,omitempty"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
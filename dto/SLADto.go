@@ -0,0 +1,214 @@
+package dto
+
+// SLAPolicyRequest 创建/更新 SLA 策略请求
+type SLAPolicyRequest struct {
+	// 策略名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 任务类型编码
+	TaskTypeCode string `json:"task_type_code" binding:"required,max=50"`
+	// 状态编码（需属于该任务类型）
+	StatusCode string `json:"status_code" binding:"required,max=50"`
+	// 适用的优先级（1-4，不传表示所有优先级）
+	Priority *int `json:"priority" binding:"omitempty,min=1,max=4"`
+	// 最长停留时间（分钟，按工作日历计算）
+	MaxMinutes int `json:"max_minutes" binding:"required,min=1"`
+	// 使用的工作日历ID（不传时使用任务所属部门的日历或默认日历）
+	CalendarID *uint `json:"calendar_id"`
+	// 是否启用（默认启用）
+	IsActive *bool `json:"is_active"`
+	// 描述（可选）
+	Description string `json:"description"`
+}
+
+// SLAPolicyResponse SLA 策略响应
+type SLAPolicyResponse struct {
+	// 策略ID
+	ID uint `json:"id"`
+	// 策略名称
+	Name string `json:"name"`
+	// 任务类型编码
+	TaskTypeCode string `json:"task_type_code"`
+	// 状态编码
+	StatusCode string `json:"status_code"`
+	// 适用的优先级（为空表示所有优先级）
+	Priority *int `json:"priority,omitempty"`
+	// 最长停留时间（分钟）
+	MaxMinutes int `json:"max_minutes"`
+	// 使用的工作日历ID
+	CalendarID *uint `json:"calendar_id,omitempty"`
+	// 是否启用
+	IsActive bool `json:"is_active"`
+	// 描述
+	Description string `json:"description"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
+
+// CalendarHolidayItem 节假日/调休
+type CalendarHolidayItem struct {
+	// 日期（YYYY-MM-DD）
+	Date string `json:"date" binding:"required,datetime=2006-01-02"`
+	// 名称（如 国庆节）
+	Name string `json:"name" binding:"max=100"`
+	// 是否为调休上班日（true 表示该日按工作日计算，false 表示放假）
+	IsWorkday bool `json:"is_workday"`
+}
+
+// BusinessCalendarRequest 创建/更新工作日历请求（更新时节假日整体替换）
+type BusinessCalendarRequest struct {
+	// 日历名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 绑定的部门ID（不传表示通用日历，每个部门最多一个日历）
+	DepartmentID *uint `json:"department_id"`
+	// 是否为默认日历（设为默认时取消其他日历的默认标记）
+	IsDefault bool `json:"is_default"`
+	// 时区（默认 Asia/Shanghai）
+	TimeZone string `json:"time_zone" binding:"omitempty,max=50"`
+	// 每日上班时间（HH:MM，默认 09:00）
+	WorkStart string `json:"work_start" binding:"omitempty,datetime=15:04"`
+	// 每日下班时间（HH:MM，默认 18:00）
+	WorkEnd string `json:"work_end" binding:"omitempty,datetime=15:04"`
+	// 工作日（1-7 表示周一至周日，默认周一至周五）
+	WorkDays []int `json:"work_days" binding:"omitempty,dive,min=1,max=7"`
+	// 节假日/调休
+	Holidays []CalendarHolidayItem `json:"holidays" binding:"omitempty,dive"`
+}
+
+// BusinessCalendarResponse 工作日历响应
+type BusinessCalendarResponse struct {
+	// 日历ID
+	ID uint `json:"id"`
+	// 日历名称
+	Name string `json:"name"`
+	// 绑定的部门ID
+	DepartmentID *uint `json:"department_id,omitempty"`
+	// 绑定的部门名称
+	DepartmentName string `json:"department_name,omitempty"`
+	// 是否为默认日历
+	IsDefault bool `json:"is_default"`
+	// 时区
+	TimeZone string `json:"time_zone"`
+	// 每日上班时间
+	WorkStart string `json:"work_start"`
+	// 每日下班时间
+	WorkEnd string `json:"work_end"`
+	// 工作日（1-7 表示周一至周日）
+	WorkDays []int `json:"work_days"`
+	// 节假日/调休
+	Holidays []CalendarHolidayItem `json:"holidays"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
+
+// SLATimerResponse 任务的 SLA 计时（按状态历史计算的每一段停留）
+type SLATimerResponse struct {
+	// SLA 策略ID
+	PolicyID uint `json:"policy_id"`
+	// SLA 策略名称
+	PolicyName string `json:"policy_name"`
+	// 状态编码
+	StatusCode string `json:"status_code"`
+	// 状态名称
+	StatusName string `json:"status_name"`
+	// 进入该状态的时间
+	EnteredAt ResponseTime `json:"entered_at"`
+	// 离开该状态的时间（仍处于该状态时为空）
+	LeftAt *ResponseTime `json:"left_at,omitempty"`
+	// SLA 截止时间
+	DueAt ResponseTime `json:"due_at"`
+	// 已用工作时间（分钟）
+	ElapsedMinutes int64 `json:"elapsed_minutes"`
+	// 限定工作时间（分钟）
+	LimitMinutes int `json:"limit_minutes"`
+	// 是否违约
+	Breached bool `json:"breached"`
+	// 是否仍在计时
+	Running bool `json:"running"`
+}
+
+// SLABreachQuery SLA 违约事件查询参数
+type SLABreachQuery struct {
+	PaginationRequest
+	// 任务ID（可选）
+	TaskID *uint `form:"task_id"`
+	// 部门ID（可选）
+	DepartmentID *uint `form:"department_id"`
+	// 执行人ID（可选）
+	ExecutorID *uint `form:"executor_id"`
+	// 是否只看未解除的违约
+	OnlyOpen bool `form:"only_open"`
+}
+
+// SLABreachResponse SLA 违约事件响应
+type SLABreachResponse struct {
+	// 违约事件ID
+	ID uint `json:"id"`
+	// 任务ID
+	TaskID uint `json:"task_id"`
+	// 任务编号
+	TaskNo string `json:"task_no"`
+	// 任务标题
+	TaskTitle string `json:"task_title"`
+	// SLA 策略ID
+	PolicyID uint `json:"policy_id"`
+	// SLA 策略名称
+	PolicyName string `json:"policy_name"`
+	// 状态编码
+	StatusCode string `json:"status_code"`
+	// 进入该状态的时间
+	EnteredAt ResponseTime `json:"entered_at"`
+	// SLA 截止时间
+	DueAt ResponseTime `json:"due_at"`
+	// 检测到违约的时间
+	BreachedAt ResponseTime `json:"breached_at"`
+	// 执行人用户ID
+	ExecutorID *uint `json:"executor_id,omitempty"`
+	// 执行人用户名
+	ExecutorName string `json:"executor_name,omitempty"`
+	// 部门ID
+	DepartmentID *uint `json:"department_id,omitempty"`
+	// 部门名称
+	DepartmentName string `json:"department_name,omitempty"`
+	// 解除时间
+	ResolvedAt *ResponseTime `json:"resolved_at,omitempty"`
+}
+
+// SLAReportQuery SLA 达成率报表查询参数
+type SLAReportQuery struct {
+	// 开始时间（按进入状态的时间筛选，格式 YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS）
+	StartTime string `form:"start_time"`
+	// 结束时间
+	EndTime string `form:"end_time"`
+	// 部门ID（可选）
+	DepartmentID *uint `form:"department_id"`
+}
+
+// SLAComplianceItem SLA 达成统计
+type SLAComplianceItem struct {
+	// 部门ID或执行人ID（汇总行为 0）
+	ID uint `json:"id"`
+	// 部门名称或执行人用户名
+	Name string `json:"name"`
+	// 计时总数
+	Total int `json:"total"`
+	// 已结束且达标的数量
+	Met int `json:"met"`
+	// 违约的数量（含仍在计时但已超期的）
+	Breached int `json:"breached"`
+	// 仍在计时且未超期的数量
+	Running int `json:"running"`
+	// 达成率（百分比，met/(met+breached)，没有已结束的计时时为 100）
+	ComplianceRate float64 `json:"compliance_rate"`
+	// 平均已用工作时间（分钟）
+	AvgElapsedMinutes float64 `json:"avg_elapsed_minutes"`
+}
+
+// SLAReportResponse SLA 达成率报表
+type SLAReportResponse struct {
+	// 汇总
+	Summary SLAComplianceItem `json:"summary"`
+	// 按部门统计
+	ByDepartment []SLAComplianceItem `json:"by_department"`
+	// 按执行人统计
+	ByExecutor []SLAComplianceItem `json:"by_executor"`
+}
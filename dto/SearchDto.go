@@ -0,0 +1,43 @@
+package dto
+
+// SearchRequest 全文检索请求
+type SearchRequest struct {
+	PaginationRequest
+	// 检索关键词
+	Q string `form:"q" binding:"required,max=200"`
+	// 检索范围（逗号分隔，可选）：task/solution/plan/comment，默认全部
+	Types string `form:"types"`
+}
+
+// SearchResultItem 全文检索结果项
+type SearchResultItem struct {
+	// 命中类型：task（任务标题/描述）/solution（思路方案）/plan（执行计划）/comment（评论）
+	Type string `json:"type"`
+	// 所属任务ID
+	TaskID uint `json:"task_id"`
+	// 所属任务编号
+	TaskNo string `json:"task_no"`
+	// 所属任务标题
+	TaskTitle string `json:"task_title"`
+	// 所属任务状态编码
+	TaskStatusCode string `json:"task_status_code"`
+	// 命中对象ID（任务/方案/计划/评论的ID）
+	TargetID uint `json:"target_id"`
+	// 版本号（仅方案和计划）
+	Version int `json:"version,omitempty"`
+	// 命中对象标题
+	Title string `json:"title"`
+	// 高亮片段（命中词以 <mark></mark> 包裹，其余内容已做 HTML 转义）
+	Snippet string `json:"snippet"`
+	// 相关度得分（越大越相关）
+	Rank float64 `json:"rank"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
+
+// SearchResponse 全文检索响应
+type SearchResponse struct {
+	PaginationResponse
+	// 实际使用的检索方式：fulltext（全文检索）/trigram（三元组模糊匹配）/like（普通模糊匹配）
+	Mode string `json:"mode"`
+}
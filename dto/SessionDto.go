@@ -0,0 +1,45 @@
+package dto
+
+// SessionQuery 登录会话列表查询参数
+type SessionQuery struct {
+	// 是否包含已结束的会话（默认只返回有效会话）
+	IncludeRevoked bool `form:"include_revoked"`
+}
+
+// SessionResponse 登录会话响应
+type SessionResponse struct {
+	// 会话ID
+	ID uint `json:"id"`
+	// 用户ID
+	UserID uint `json:"user_id"`
+	// 用户名
+	Username string `json:"username,omitempty"`
+	// 登录方式：password-密码，wechat_open-微信扫码，wechat_mp-微信小程序，wechat_h5-微信公众号H5，ldap-LDAP目录账号，oidc-OIDC单点登录
+	LoginMethod string `json:"login_method"`
+	// 设备名称
+	Device string `json:"device"`
+	// User-Agent
+	UserAgent string `json:"user_agent"`
+	// 登录IP
+	IP string `json:"ip"`
+	// 最近访问IP
+	LastSeenIP string `json:"last_seen_ip"`
+	// 登录时间
+	CreatedAt ResponseTime `json:"created_at"`
+	// 最近访问时间
+	LastSeenAt ResponseTime `json:"last_seen_at"`
+	// 过期时间
+	ExpiresAt ResponseTime `json:"expires_at"`
+	// 是否为当前请求所用的会话
+	Current bool `json:"current"`
+	// 结束时间（有效会话为空）
+	RevokedAt *ResponseTime `json:"revoked_at,omitempty"`
+	// 结束原因
+	RevokeReason string `json:"revoke_reason,omitempty"`
+}
+
+// RevokeSessionsResult 批量结束会话的结果
+type RevokeSessionsResult struct {
+	// 结束的会话数量
+	Revoked int64 `json:"revoked"`
+}
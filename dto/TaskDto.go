@@ -210,6 +210,8 @@ type TaskResponse struct {
 	ExecutorID uint `json:"executor_id"`
 	// 执行人用户名
 	ExecutorUsername string `json:"executor_username,omitempty"`
+	// 创建人用户名（fields 包含 creator 时返回）
+	CreatorUsername string `json:"creator_username,omitempty"`
 	// 所属部门ID
 	DepartmentID uint `json:"department_id"`
 	// 所属部门名称
@@ -260,6 +262,8 @@ type TaskStatusResponse struct {
 	Code string `json:"code"`
 	// 任务状态名称
 	Name string `json:"name"`
+	// 状态分类：todo/in_progress/done/blocked/cancelled
+	Category string `json:"category"`
 }
 
 // TaskQueryRequest 任务查询过滤请求
@@ -300,6 +304,25 @@ type TaskQueryRequest struct {
 	StartTime string `form:"start_time"`
 	// 自定义结束时间（可选，格式：2006-01-02 或 2006-01-02T15:04:05）
 	EndTime string `form:"end_time"`
+	// 查询语言表达式（可选，与其他筛选条件同时生效）
+	// 示例：status in (req_in_progress, req_blocked) and priority >= 3 and executor = me and due < +7d
+	Q string `form:"q"`
+	// 排序字段（可选）：created_at/updated_at/priority/expected_start_date/expected_end_date/task_no/title/status_code/progress
+	SortBy string `form:"sort_by"`
+	// 排序方向（可选）：asc/desc，默认 desc
+	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	// 分页方式（可选）：offset（默认，页码分页）/cursor（游标分页，不统计总数）
+	Pagination string `form:"pagination" binding:"omitempty,oneof=offset cursor"`
+	// 游标（可选，取自上一页响应的 next_cursor，传入即按游标分页）
+	Cursor string `form:"cursor"`
+	// 返回的关联数据（可选，逗号分隔）：creator/executor/department/tags/subtasks/solution/plan/attachments
+	// 不传时返回 executor/attachments/subtasks/solution/plan，与旧版本保持一致
+	Fields string `form:"fields"`
+}
+
+// UsesCursor 是否使用游标分页
+func (r *TaskQueryRequest) UsesCursor() bool {
+	return r.Cursor != "" || r.Pagination == "cursor"
 }
 
 // TaskStatusTransitionRequest 任务状态转换请求
@@ -327,6 +350,26 @@ type TaskDetailResponse struct {
 	TaskType *TaskTypeResponse `json:"task_type,omitempty"`
 	// 任务状态信息（可选）
 	TaskStatus *TaskStatusResponse `json:"task_status,omitempty"`
+	// 当前的方案/计划提交截止（仅处于待提交方案/待提交计划状态且设置了截止时返回）
+	Deadline *TaskDeadlineResponse `json:"deadline,omitempty"`
+}
+
+// TaskDeadlineResponse 任务提交截止响应
+type TaskDeadlineResponse struct {
+	// 截止类型：solution（思路方案）/execution_plan（执行计划）
+	Kind string `json:"kind"`
+	// 开始计时时的任务状态编码
+	StatusCode string `json:"status_code"`
+	// 开始计时时间
+	StartedAt ResponseTime `json:"started_at"`
+	// 截止时间
+	DueAt ResponseTime `json:"due_at"`
+	// 剩余秒数（负数表示已超期）
+	RemainingSeconds int64 `json:"remaining_seconds"`
+	// 是否已超期
+	Overdue bool `json:"overdue"`
+	// 计时状态：active/overdue
+	Status string `json:"status"`
 }
 
 // SimpleUserResponse 简化的用户响应（避免循环依赖）
@@ -98,12 +98,18 @@ type InitiateReviewRequest struct {
 	TargetType string `json:"target_type" binding:"required"`
 	// 目标ID（被审核的目标、方案或计划的ID）
 	TargetID uint `json:"target_id" binding:"required"`
-	// 审核模式（single=单人审核, jury=陪审团审核）
-	ReviewMode string `json:"review_mode" binding:"required,oneof=single jury"`
-	// 陪审团成员ID列表（陪审团模式下需指定）
+	// 审核模式（single=单人审核, jury=陪审团审核, threshold=门槛投票, weighted=加权投票）
+	ReviewMode string `json:"review_mode" binding:"required,oneof=single jury threshold weighted"`
+	// 陪审团成员ID列表（陪审团/投票模式下需指定）
 	JuryMemberIDs []uint `json:"jury_member_ids"`
-	// 所需批准数（陪审团模式下需要的最少批准数）
+	// 评审专家ID列表（可选，投票模式下按 expert 角色计权）
+	ExpertIDs []uint `json:"expert_ids"`
+	// 所需批准数（陪审团模式下需要的最少批准数；加权投票模式下为需要的赞成权重）
 	RequiredApprovals int `json:"required_approvals"`
+	// 陪审团响应截止时间（可选，YYYY-MM-DD HH:MM:SS，不传时按系统配置的响应时限）
+	ResponseDeadline string `json:"response_deadline"`
+	// 响应截止后的处理策略（abstain=未响应视为弃权, escalate=升级到部门负责人决策, creator_finalize=创建人直接决策；不传时按系统配置）
+	DeadlinePolicy string `json:"deadline_policy" binding:"omitempty,oneof=abstain escalate creator_finalize"`
 }
 
 // SubmitReviewOpinionRequest 提交审核意见请求
@@ -114,6 +120,18 @@ type SubmitReviewOpinionRequest struct {
 	Comment string `json:"comment"`
 	// 评分（可选，用于量化评估）
 	Score *int `json:"score"`
+	// 维度评分（审核绑定评分标准时，非弃权意见需为每个维度打分）
+	CriterionScores []CriterionScoreItem `json:"criterion_scores" binding:"omitempty,dive"`
+	// 代哪位委托人提交意见（可选；本人不是审核成员且只代理了一名未投票成员时可不传）
+	OnBehalfOf *uint `json:"on_behalf_of"`
+}
+
+// CriterionScoreItem 维度评分
+type CriterionScoreItem struct {
+	// 评分维度ID
+	CriterionID uint `json:"criterion_id" binding:"required"`
+	// 分值（需在评分标准的分值范围内）
+	Score *int `json:"score" binding:"required"`
 }
 
 // FinalizeReviewRequest 最终决策请求
@@ -122,6 +140,8 @@ type FinalizeReviewRequest struct {
 	Approved bool `json:"approved"`
 	// 最终决策备注
 	Comment string `json:"comment"`
+	// 越过投票结果直接决策（门槛投票/加权投票模式下必须设置，并记录越权日志）
+	Override bool `json:"override"`
 }
 
 // ReviewSessionResponse 审核会话响应
@@ -138,7 +158,7 @@ type ReviewSessionResponse struct {
 	TargetID uint `json:"target_id"`
 	// 审核状态（pending=进行中, approved=已批准, rejected=已拒绝）
 	Status string `json:"status"`
-	// 审核模式（single=单人审核, jury=陪审团审核）
+	// 审核模式（single=单人审核, jury=陪审团审核, threshold=门槛投票, weighted=加权投票）
 	ReviewMode string `json:"review_mode"`
 	// 所需批准数
 	RequiredApprovals int `json:"required_approvals"`
@@ -146,6 +166,34 @@ type ReviewSessionResponse struct {
 	FinalDecision *string `json:"final_decision,omitempty"`
 	// 最终决议备注
 	FinalDecisionComment string `json:"final_decision_comment,omitempty"`
+	// 是否由创建人越过投票结果直接决策
+	IsOverridden bool `json:"is_overridden"`
+	// 最终决策的代理人用户ID（委托代审时实际决策的人）
+	FinalDecisionDelegateID *uint `json:"final_decision_delegate_id,omitempty"`
+	// 审批链模板ID（按审批链逐级审批时）
+	ChainTemplateID *uint `json:"chain_template_id,omitempty"`
+	// 审批链阶段序号（0 表示不属于审批链）
+	ChainStageNo int `json:"chain_stage_no"`
+	// 审批链阶段名称
+	ChainStageName string `json:"chain_stage_name,omitempty"`
+	// 本阶段的审批人用户ID
+	StageApproverIDs []uint `json:"stage_approver_ids,omitempty"`
+	// 上一阶段的审核会话ID
+	PrevStageSessionID *uint `json:"prev_stage_session_id,omitempty"`
+	// 下一阶段的审核会话ID
+	NextStageSessionID *uint `json:"next_stage_session_id,omitempty"`
+	// 投票统计（非单人审核模式）
+	VoteTally *ReviewVoteTally `json:"vote_tally,omitempty"`
+	// 评分汇总（绑定评分标准时）
+	RubricSummary *ReviewRubricSummary `json:"rubric_summary,omitempty"`
+	// 陪审团响应截止时间
+	ResponseDueAt *ResponseTime `json:"response_due_at,omitempty"`
+	// 响应截止后的处理策略（abstain/escalate/creator_finalize）
+	DeadlinePolicy string `json:"deadline_policy,omitempty"`
+	// 执行截止处理策略的时间（为空表示尚未截止）
+	DeadlineHandledAt *ResponseTime `json:"deadline_handled_at,omitempty"`
+	// 尚未提交意见的陪审团成员/评审专家ID
+	PendingReviewerIDs []uint `json:"pending_reviewer_ids,omitempty"`
 	// 审核记录列表（各个审核人的意见，可选）
 	ReviewRecords []ReviewRecordResponse `json:"review_records,omitempty"`
 }
@@ -158,7 +206,11 @@ type ReviewRecordResponse struct {
 	ReviewerID uint `json:"reviewer_id"`
 	// 审核人用户名（可选）
 	ReviewerName string `json:"reviewer_name,omitempty"`
-	// 审核人角色（reviewer=评审人, jury=陪审团成员）
+	// 代理人用户ID（委托代审时实际提交意见的人）
+	DelegateID *uint `json:"delegate_id,omitempty"`
+	// 代理人用户名
+	DelegateName string `json:"delegate_name,omitempty"`
+	// 审核人角色（creator=创建人, leader=部门负责人, jury=陪审团成员, expert=评审专家）
 	ReviewerRole string `json:"reviewer_role"`
 	// 审核意见（approve=批准, reject=拒绝, abstain=弃权）
 	Opinion string `json:"opinion"`
@@ -168,14 +220,94 @@ type ReviewRecordResponse struct {
 	Score *int `json:"score,omitempty"`
 	// 投票权重（陪审团模式下的权重）
 	VoteWeight float64 `json:"vote_weight"`
+	// 维度评分（绑定评分标准时）
+	CriterionScores []ReviewCriterionScoreResponse `json:"criterion_scores,omitempty"`
+}
+
+// ReviewCriterionScoreResponse 审核人的维度评分
+type ReviewCriterionScoreResponse struct {
+	// 评分维度ID
+	CriterionID uint `json:"criterion_id"`
+	// 维度名称
+	CriterionName string `json:"criterion_name"`
+	// 分值
+	Score int `json:"score"`
+}
+
+// ReviewRubricSummary 审核评分汇总
+type ReviewRubricSummary struct {
+	// 评分标准ID
+	RubricID uint `json:"rubric_id"`
+	// 评分标准名称
+	RubricName string `json:"rubric_name"`
+	// 分值下限
+	ScaleMin int `json:"scale_min"`
+	// 分值上限
+	ScaleMax int `json:"scale_max"`
+	// 通过所需的最低加权总分
+	MinTotalScore *float64 `json:"min_total_score,omitempty"`
+	// 已评分人数
+	ScoredReviewers int `json:"scored_reviewers"`
+	// 加权总分（按维度平均分和权重计算，尚无评分时为空）
+	WeightedTotal *float64 `json:"weighted_total,omitempty"`
+	// 各维度统计
+	Criteria []CriterionScoreSummary `json:"criteria"`
+}
+
+// CriterionScoreSummary 维度评分统计
+type CriterionScoreSummary struct {
+	// 评分维度ID
+	CriterionID uint `json:"criterion_id"`
+	// 维度名称
+	Name string `json:"name"`
+	// 权重
+	Weight float64 `json:"weight"`
+	// 评分人数
+	Count int `json:"count"`
+	// 平均分
+	Average float64 `json:"average"`
+	// 最低分
+	Min int `json:"min"`
+	// 最高分
+	Max int `json:"max"`
+	// 极差（最高分 - 最低分，反映评审分歧）
+	Spread int `json:"spread"`
+	// 标准差
+	StdDev float64 `json:"std_dev"`
+}
+
+// ReviewVoteTally 审核投票统计（均为投票权重之和）
+type ReviewVoteTally struct {
+	// 赞成权重
+	ApproveWeight float64 `json:"approve_weight"`
+	// 反对权重
+	RejectWeight float64 `json:"reject_weight"`
+	// 弃权权重
+	AbstainWeight float64 `json:"abstain_weight"`
+	// 尚未投票成员的权重
+	PendingWeight float64 `json:"pending_weight"`
+	// 尚未投票成员数
+	PendingVoters int `json:"pending_voters"`
+	// 需要的赞成权重
+	Required float64 `json:"required"`
+	// 投票结果（approved=已通过, rejected=已驳回，未决时为空）
+	Outcome string `json:"outcome,omitempty"`
 }
 
 // InviteJuryRequest 邀请陪审团请求
 type InviteJuryRequest struct {
 	// 陪审团成员ID列表（要邀请为陪审团成员的用户）
 	JuryMemberIDs []uint `json:"jury_member_ids" binding:"required,min=1"`
-	// 所需批准数（陪审团审核需要的最少批准数）
+	// 所需批准数（陪审团审核需要的最少批准数；加权投票模式下为需要的赞成权重）
 	RequiredApprovals int `json:"required_approvals" binding:"required,min=1"`
+	// 审核模式（jury=陪审团审核, threshold=门槛投票, weighted=加权投票；为空时保留当前投票模式，否则为 jury）
+	ReviewMode string `json:"review_mode" binding:"omitempty,oneof=jury threshold weighted"`
+	// 评审专家ID列表（可选）
+	ExpertIDs []uint `json:"expert_ids"`
+	// 陪审团响应截止时间（可选，YYYY-MM-DD HH:MM:SS，不传时保留当前截止时间或按系统配置的响应时限）
+	ResponseDeadline string `json:"response_deadline"`
+	// 响应截止后的处理策略（abstain=未响应视为弃权, escalate=升级到部门负责人决策, creator_finalize=创建人直接决策；不传时保留当前策略或按系统配置）
+	DeadlinePolicy string `json:"deadline_policy" binding:"omitempty,oneof=abstain escalate creator_finalize"`
 }
 
 // ========== 任务状态和转换相关DTO ==========
@@ -0,0 +1,120 @@
+package dto
+
+// TaskViewFilters 保存视图的筛选条件（字段与任务列表查询参数一致）
+type TaskViewFilters struct {
+	// 任务编号（模糊查询）
+	TaskNo string `json:"task_no,omitempty"`
+	// 任务标题（模糊查询）
+	Title string `json:"title,omitempty"`
+	// 任务类型编码
+	TaskTypeCode string `json:"task_type_code,omitempty"`
+	// 任务状态编码
+	StatusCode string `json:"status_code,omitempty"`
+	// 创建者用户ID
+	CreatorID *uint `json:"creator_id,omitempty"`
+	// 执行人用户ID
+	ExecutorID *uint `json:"executor_id,omitempty"`
+	// 所属部门ID
+	DepartmentID *uint `json:"department_id,omitempty"`
+	// 成员用户ID
+	MemberID *uint `json:"member_id,omitempty"`
+	// 优先级
+	Priority *int `json:"priority,omitempty"`
+	// 是否在待领池
+	IsInPool *bool `json:"is_in_pool,omitempty"`
+	// 我的角色：all/creator/executor/jury（仅 source=my 时生效）
+	MyRole string `json:"my_role,omitempty"`
+	// 时间范围快捷筛选：week/month/three_months
+	TimeRange string `json:"time_range,omitempty"`
+	// 自定义开始时间
+	StartTime string `json:"start_time,omitempty"`
+	// 自定义结束时间
+	EndTime string `json:"end_time,omitempty"`
+	// 查询语言表达式
+	Q string `json:"q,omitempty"`
+}
+
+// ToQueryRequest 将视图筛选条件转换为任务列表查询请求
+func (f *TaskViewFilters) ToQueryRequest() *TaskQueryRequest {
+	return &TaskQueryRequest{
+		TaskNo:       f.TaskNo,
+		Title:        f.Title,
+		TaskTypeCode: f.TaskTypeCode,
+		StatusCode:   f.StatusCode,
+		CreatorID:    f.CreatorID,
+		ExecutorID:   f.ExecutorID,
+		DepartmentID: f.DepartmentID,
+		MemberID:     f.MemberID,
+		Priority:     f.Priority,
+		IsInPool:     f.IsInPool,
+		MyRole:       f.MyRole,
+		TimeRange:    f.TimeRange,
+		StartTime:    f.StartTime,
+		EndTime:      f.EndTime,
+		Q:            f.Q,
+	}
+}
+
+// TaskViewRequest 创建/更新保存视图请求
+type TaskViewRequest struct {
+	// 视图名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 视图描述（可选）
+	Description string `json:"description"`
+	// 数据来源：all（全部任务）/my（我的任务），默认 all
+	Source string `json:"source" binding:"omitempty,oneof=all my"`
+	// 筛选条件
+	Filters TaskViewFilters `json:"filters"`
+	// 排序字段（可选，同任务列表 sort_by）
+	SortBy string `json:"sort_by"`
+	// 排序方向：asc/desc（可选）
+	SortOrder string `json:"sort_order" binding:"omitempty,oneof=asc desc"`
+	// 展示列（字段名数组，可选）
+	Columns []string `json:"columns"`
+	// 共享的部门ID（可选，为空表示仅自己可见）
+	SharedDepartmentID *uint `json:"shared_department_id"`
+}
+
+// TaskViewResponse 保存视图响应
+type TaskViewResponse struct {
+	// 视图ID
+	ID uint `json:"id"`
+	// 视图名称
+	Name string `json:"name"`
+	// 视图描述
+	Description string `json:"description"`
+	// 数据来源：all/my
+	Source string `json:"source"`
+	// 筛选条件
+	Filters TaskViewFilters `json:"filters"`
+	// 排序字段
+	SortBy string `json:"sort_by"`
+	// 排序方向
+	SortOrder string `json:"sort_order"`
+	// 展示列
+	Columns []string `json:"columns"`
+	// 所有者用户ID
+	OwnerID uint `json:"owner_id"`
+	// 所有者用户名
+	OwnerName string `json:"owner_name"`
+	// 共享的部门ID
+	SharedDepartmentID *uint `json:"shared_department_id,omitempty"`
+	// 共享的部门名称
+	SharedDepartmentName string `json:"shared_department_name,omitempty"`
+	// 当前用户是否为所有者（所有者才可编辑和删除）
+	IsOwner bool `json:"is_owner"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+	// 更新时间
+	UpdatedAt ResponseTime `json:"updated_at"`
+}
+
+// RunTaskViewResponse 执行保存视图的响应
+type RunTaskViewResponse struct {
+	// 视图信息
+	View *TaskViewResponse `json:"view"`
+	// 展示列
+	Columns []string `json:"columns"`
+	// 任务分页结果（按当前用户的可见范围过滤）
+	Tasks *PaginationResponse `json:"tasks"`
+}
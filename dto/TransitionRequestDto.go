@@ -0,0 +1,53 @@
+package dto
+
+// TransitionRequestQuery 状态转换申请列表查询参数
+type TransitionRequestQuery struct {
+	// 申请状态：pending/approved/rejected/cancelled（不传表示全部）
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected cancelled"`
+	// 任务ID（可选）
+	TaskID *uint `form:"task_id"`
+}
+
+// TransitionRequestResponse 状态转换申请响应
+type TransitionRequestResponse struct {
+	// 申请ID
+	ID uint `json:"id"`
+	// 任务ID
+	TaskID uint `json:"task_id"`
+	// 任务编号
+	TaskNo string `json:"task_no"`
+	// 任务标题
+	TaskTitle string `json:"task_title"`
+	// 源状态编码
+	FromStatusCode string `json:"from_status_code"`
+	// 源状态名称
+	FromStatusName string `json:"from_status_name"`
+	// 目标状态编码
+	ToStatusCode string `json:"to_status_code"`
+	// 目标状态名称
+	ToStatusName string `json:"to_status_name"`
+	// 申请人用户ID
+	RequestedBy uint `json:"requested_by"`
+	// 申请人用户名
+	RequesterName string `json:"requester_name"`
+	// 申请备注
+	Comment string `json:"comment"`
+	// 申请状态：pending/approved/rejected/cancelled
+	Status string `json:"status"`
+	// 关联的审核会话ID
+	ReviewSessionID *uint `json:"review_session_id,omitempty"`
+	// 审批人用户ID
+	DecidedBy *uint `json:"decided_by,omitempty"`
+	// 审批人用户名
+	DeciderName string `json:"decider_name,omitempty"`
+	// 审批代理人用户ID（委托代审时实际审批的人）
+	DecidedDelegateID *uint `json:"decided_delegate_id,omitempty"`
+	// 审批代理人用户名
+	DecidedDelegateName string `json:"decided_delegate_name,omitempty"`
+	// 审批时间
+	DecidedAt *ResponseTime `json:"decided_at,omitempty"`
+	// 审批备注
+	DecisionComment string `json:"decision_comment,omitempty"`
+	// 申请时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
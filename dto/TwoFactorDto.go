@@ -0,0 +1,67 @@
+package dto
+
+// TwoFactorStatusResponse 两步验证状态响应
+type TwoFactorStatusResponse struct {
+	// 是否已启用
+	Enabled bool `json:"enabled"`
+	// 是否必须启用（管理员等账号不能解除）
+	Required bool `json:"required"`
+	// 启用时间
+	EnabledAt *ResponseTime `json:"enabled_at,omitempty"`
+	// 剩余未使用的恢复码数量
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollResponse 发起绑定两步验证的响应
+type TwoFactorEnrollResponse struct {
+	// TOTP 密钥（Base32，无法扫码时手动输入）
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	// otpauth:// 配置链接（即二维码内容）
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/RHPRo-Task:13800138000?issuer=RHPRo-Task&secret=JBSWY3DPEHPK3PXP"`
+	// 二维码图片（PNG 的 data URL，可直接用于 img 标签）
+	QRCode string `json:"qr_code"`
+}
+
+// TwoFactorCodeRequest 动态验证码请求（启用两步验证、重新生成恢复码）
+type TwoFactorCodeRequest struct {
+	// 验证器 App 中的 6 位动态验证码
+	Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+// TwoFactorDisableRequest 解除两步验证请求
+type TwoFactorDisableRequest struct {
+	// 登录密码
+	Password string `json:"password" binding:"required" example:"password123"`
+	// 动态验证码
+	Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+// RecoveryCodesResponse 恢复码响应（仅显示一次，请妥善保存）
+type RecoveryCodesResponse struct {
+	// 恢复码列表（每个只能使用一次）
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorLoginRequest 两步验证登录请求（动态验证码和恢复码二选一）
+type TwoFactorLoginRequest struct {
+	// 密码登录返回的两步验证凭证
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	// 动态验证码
+	Code string `json:"code" binding:"omitempty,len=6,numeric" example:"123456"`
+	// 恢复码（丢失验证器时使用）
+	RecoveryCode string `json:"recovery_code" binding:"omitempty,max=20" example:"abcde-fghjk"`
+}
+
+// TwoFactorSetupRequest 登录时绑定两步验证请求
+type TwoFactorSetupRequest struct {
+	// 密码登录返回的两步验证凭证
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+}
+
+// TwoFactorSetupConfirmRequest 登录时确认绑定两步验证请求
+type TwoFactorSetupConfirmRequest struct {
+	// 密码登录返回的两步验证凭证
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	// 动态验证码
+	Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
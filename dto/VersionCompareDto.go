@@ -0,0 +1,131 @@
+package dto
+
+// VersionCompareQuery 版本对比查询参数
+type VersionCompareQuery struct {
+	// 对比的基准版本号（不传时取目标版本之前最近一次被驳回的版本，没有驳回版本时取上一版本）
+	From int `form:"from" binding:"omitempty,min=1"`
+	// 对比的目标版本号（不传时取最新版本）
+	To int `form:"to" binding:"omitempty,min=1"`
+}
+
+// VersionSummary 参与对比的版本
+type VersionSummary struct {
+	// 方案/计划ID
+	ID uint `json:"id"`
+	// 版本号
+	Version int `json:"version"`
+	// 标题
+	Title string `json:"title"`
+	// 状态（pending/approved/rejected）
+	Status string `json:"status"`
+	// 提交时间
+	SubmittedAt *ResponseTime `json:"submitted_at,omitempty"`
+}
+
+// LineDiff 行级差异
+type LineDiff struct {
+	// 差异类型（equal=未变化, added=新增, removed=删除）
+	Type string `json:"type"`
+	// 基准版本中的行号（新增行为 0）
+	OldLine int `json:"old_line,omitempty"`
+	// 目标版本中的行号（删除行为 0）
+	NewLine int `json:"new_line,omitempty"`
+	// 行内容
+	Text string `json:"text"`
+}
+
+// TextDiff 文本字段的行级差异
+type TextDiff struct {
+	// 字段名
+	Field string `json:"field"`
+	// 是否有变化
+	Changed bool `json:"changed"`
+	// 新增行数
+	Added int `json:"added"`
+	// 删除行数
+	Removed int `json:"removed"`
+	// 逐行差异（未变化时为空）
+	Lines []LineDiff `json:"lines,omitempty"`
+}
+
+// FieldChange 字段变化
+type FieldChange struct {
+	// 字段名
+	Field string `json:"field"`
+	// 基准版本的值
+	OldValue string `json:"old_value"`
+	// 目标版本的值
+	NewValue string `json:"new_value"`
+}
+
+// JSONChange JSON 结构差异
+type JSONChange struct {
+	// 变化位置（如 phases[0].name，根节点为空）
+	Path string `json:"path"`
+	// 变化类型（added=新增, removed=删除, changed=修改）
+	Type string `json:"type"`
+	// 基准版本的值
+	OldValue interface{} `json:"old_value,omitempty"`
+	// 目标版本的值
+	NewValue interface{} `json:"new_value,omitempty"`
+}
+
+// GoalChange 目标变化
+type GoalChange struct {
+	// 变化类型（added=新增, removed=删除, changed=修改）
+	Type string `json:"type"`
+	// 基准版本中的目标编号（新增为 0）
+	OldGoalNo int `json:"old_goal_no,omitempty"`
+	// 目标版本中的目标编号（删除为 0）
+	NewGoalNo int `json:"new_goal_no,omitempty"`
+	// 目标标题
+	Title string `json:"title"`
+	// 字段变化（修改时）
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// AttachmentChange 附件变化（按文件名匹配）
+type AttachmentChange struct {
+	// 变化类型（added=新增, removed=删除, changed=同名文件内容变化）
+	Type string `json:"type"`
+	// 文件名
+	FileName string `json:"file_name"`
+	// 基准版本的附件
+	Old *AttachmentDetailResult `json:"old,omitempty"`
+	// 目标版本的附件
+	New *AttachmentDetailResult `json:"new,omitempty"`
+}
+
+// SolutionCompareResponse 思路方案版本对比响应
+type SolutionCompareResponse struct {
+	// 基准版本
+	From VersionSummary `json:"from"`
+	// 目标版本
+	To VersionSummary `json:"to"`
+	// 基准版本的选择方式（specified=指定, last_rejected=最近一次被驳回的版本, previous=上一版本）
+	Baseline string `json:"baseline"`
+	// 文本字段差异（title/content/mindmap_markdown）
+	TextDiffs []TextDiff `json:"text_diffs"`
+	// 其他字段变化（mindmap_url/file_name）
+	FieldChanges []FieldChange `json:"field_changes"`
+	// 附件变化
+	Attachments []AttachmentChange `json:"attachments"`
+}
+
+// ExecutionPlanCompareResponse 执行计划版本对比响应
+type ExecutionPlanCompareResponse struct {
+	// 基准版本
+	From VersionSummary `json:"from"`
+	// 目标版本
+	To VersionSummary `json:"to"`
+	// 基准版本的选择方式（specified=指定, last_rejected=最近一次被驳回的版本, previous=上一版本）
+	Baseline string `json:"baseline"`
+	// 文本字段差异（title/tech_stack/resource_requirements/risk_assessment）
+	TextDiffs []TextDiff `json:"text_diffs"`
+	// 实施步骤的结构差异
+	StepChanges []JSONChange `json:"step_changes"`
+	// 目标变化
+	GoalChanges []GoalChange `json:"goal_changes"`
+	// 附件变化
+	Attachments []AttachmentChange `json:"attachments"`
+}
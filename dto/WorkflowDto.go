@@ -0,0 +1,127 @@
+package dto
+
+// TaskTypeRequest 创建/更新任务类型请求
+type TaskTypeRequest struct {
+	// 类型编码（创建时必填，仅允许小写字母、数字和下划线；更新时忽略）
+	Code string `json:"code" binding:"omitempty,max=50"`
+	// 类型名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 描述（可选）
+	Description string `json:"description"`
+	// 未指派执行人时的状态编码（可选，需属于该类型）
+	PoolStatusCode string `json:"pool_status_code" binding:"omitempty,max=50"`
+	// 已指派、待执行人接受时的状态编码（可选，需属于该类型）
+	AssignedStatusCode string `json:"assigned_status_code" binding:"omitempty,max=50"`
+	// 执行人接受后的状态编码（可选，需属于该类型）
+	AcceptedStatusCode string `json:"accepted_status_code" binding:"omitempty,max=50"`
+}
+
+// TaskTypeDetailResponse 任务类型详情响应（含入口状态配置和统计）
+type TaskTypeDetailResponse struct {
+	// 类型ID
+	ID uint `json:"id"`
+	// 类型编码
+	Code string `json:"code"`
+	// 类型名称
+	Name string `json:"name"`
+	// 描述
+	Description string `json:"description"`
+	// 未指派执行人时的状态编码
+	PoolStatusCode string `json:"pool_status_code"`
+	// 已指派、待执行人接受时的状态编码
+	AssignedStatusCode string `json:"assigned_status_code"`
+	// 执行人接受后的状态编码
+	AcceptedStatusCode string `json:"accepted_status_code"`
+	// 状态数量
+	StatusCount int64 `json:"status_count"`
+	// 转换规则数量
+	TransitionCount int64 `json:"transition_count"`
+	// 创建时间
+	CreatedAt ResponseTime `json:"created_at"`
+}
+
+// WorkflowStatusItem 流程中的状态定义
+type WorkflowStatusItem struct {
+	// 状态编码（全局唯一）
+	Code string `json:"code" binding:"required,max=50"`
+	// 状态名称
+	Name string `json:"name" binding:"required,max=100"`
+	// 状态分类：todo/in_progress/done/blocked/cancelled
+	Category string `json:"category" binding:"required,oneof=todo in_progress done blocked cancelled"`
+	// 是否为初始状态（可达性校验的起点）
+	IsInitial bool `json:"is_initial"`
+	// 是否允许在该状态下创建子任务（默认 true）
+	AllowsSubtasks *bool `json:"allows_subtasks"`
+	// 排序顺序
+	SortOrder int `json:"sort_order"`
+	// 描述（可选）
+	Description string `json:"description"`
+}
+
+// WorkflowTransitionItem 流程中的状态转换定义
+type WorkflowTransitionItem struct {
+	// 源状态编码
+	FromStatusCode string `json:"from_status_code" binding:"required,max=50"`
+	// 目标状态编码
+	ToStatusCode string `json:"to_status_code" binding:"required,max=50"`
+	// 需要的角色：creator/executor/jury（为空表示不限）
+	RequiredRole string `json:"required_role" binding:"omitempty,oneof=creator executor jury"`
+	// 是否需要审批
+	RequiresApproval bool `json:"requires_approval"`
+	// 是否允许此转换（默认 true）
+	IsAllowed *bool `json:"is_allowed"`
+	// 转换前必须通过的守卫名称列表（可选）
+	Guards []string `json:"guards"`
+	// 转换后执行的后置动作名称列表（不传时默认 rollup_parent，传空数组表示不执行任何动作）
+	PostActions []string `json:"post_actions"`
+	// 描述（可选）
+	Description string `json:"description"`
+}
+
+// WorkflowRequest 保存任务类型流程请求（整体替换状态和转换规则）
+type WorkflowRequest struct {
+	// 状态列表
+	Statuses []WorkflowStatusItem `json:"statuses" binding:"required,min=1,dive"`
+	// 转换规则列表
+	Transitions []WorkflowTransitionItem `json:"transitions" binding:"dive"`
+}
+
+// WorkflowResponse 任务类型流程响应
+type WorkflowResponse struct {
+	// 任务类型
+	TaskType TaskTypeDetailResponse `json:"task_type"`
+	// 状态列表
+	Statuses []WorkflowStatusItem `json:"statuses"`
+	// 转换规则列表
+	Transitions []WorkflowTransitionItem `json:"transitions"`
+	// 校验结果
+	Validation WorkflowValidationResult `json:"validation"`
+}
+
+// WorkflowValidationResult 流程校验结果
+type WorkflowValidationResult struct {
+	// 是否通过校验
+	Valid bool `json:"valid"`
+	// 错误信息列表
+	Errors []string `json:"errors"`
+	// 从初始状态不可达的状态编码
+	UnreachableStatuses []string `json:"unreachable_statuses"`
+}
+
+// TransitionHookItem 可配置的守卫/后置动作
+type TransitionHookItem struct {
+	// 名称
+	Name string `json:"name"`
+	// 说明
+	Description string `json:"description"`
+}
+
+// TransitionHooksResponse 已注册的守卫和后置动作
+type TransitionHooksResponse struct {
+	// 守卫列表
+	Guards []TransitionHookItem `json:"guards"`
+	// 后置动作列表
+	PostActions []TransitionHookItem `json:"post_actions"`
+	// 未配置后置动作时默认执行的动作
+	DefaultPostActions []string `json:"default_post_actions"`
+}
@@ -23,6 +23,8 @@ import (
 	"RHPRo-Task/config"
 	"RHPRo-Task/database"
 	"RHPRo-Task/routes"
+	"RHPRo-Task/scheduler"
+	"RHPRo-Task/scheduler/jobs"
 	"RHPRo-Task/upload/drivers"
 	"RHPRo-Task/utils"
 	"fmt"
@@ -56,6 +58,14 @@ func main() {
 		// 上传模块初始化失败不阻止服务启动，只记录警告
 	}
 
+	// 注册定时任务（截止检查、SLA 检查等），按配置启动调度
+	if err := jobs.RegisterJobs(); err != nil {
+		utils.Logger.Error(fmt.Sprintf("Failed to register scheduled jobs: %v", err))
+	}
+	if cfg.Scheduler.Enabled {
+		scheduler.GetScheduler().Start()
+	}
+
 	// 初始化路由
 	router := routes.SetupRoutes()
 
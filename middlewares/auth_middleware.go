@@ -1,12 +1,23 @@
 package middlewares
 
 import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/models"
 	"RHPRo-Task/utils"
+	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// sessionTouchInterval 会话最近访问时间的更新间隔（间隔内的请求不重复写库）
+const sessionTouchInterval = time.Minute
+
+// sessionTouchedAt 各会话最近一次写入访问时间的时刻（sessionID -> time.Time）
+var sessionTouchedAt sync.Map
+
 // AuthMiddleware JWT认证中间件
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -27,17 +38,72 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// 解析token
+		// 访问令牌必须带有令牌ID、用户ID和登录会话ID
 		claims, err := utils.ParseToken(parts[1])
-		if err != nil {
+		if err != nil || claims.ID == "" || claims.UserID == 0 || claims.SessionID == 0 {
 			utils.Unauthorized(c, "认证令牌无效或已过期")
 			c.Abort()
 			return
 		}
 
+		// 检查令牌是否已被吊销（注销、修改密码、禁用或删除用户）
+		revoked, err := isTokenRevoked(claims.ID)
+		if err != nil {
+			utils.Logger.Error(fmt.Sprintf("Token revocation check error: %v", err))
+			utils.InternalServerError(c, "认证检查失败")
+			c.Abort()
+			return
+		}
+		if revoked {
+			utils.Unauthorized(c, "认证令牌已失效，请重新登录")
+			c.Abort()
+			return
+		}
+
 		// 将用户信息存入上下文
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("tokenID", claims.ID)
+		c.Set("sessionID", claims.SessionID)
+		if claims.ExpiresAt != nil {
+			c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+		}
+
+		// 更新会话最近访问时间和IP
+		touchSession(claims.SessionID, c.ClientIP())
 
 		c.Next()
 	}
 }
+
+// isTokenRevoked 检查访问令牌是否在吊销列表中
+func isTokenRevoked(tokenID string) (bool, error) {
+	var count int64
+	if err := database.DB.Model(&models.RevokedToken{}).
+		Where("token_id = ? AND expires_at > ?", tokenID, time.Now()).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// touchSession 更新会话最近访问时间（同一会话每个更新间隔内最多写库一次，失败不影响请求）
+func touchSession(sessionID uint, ip string) {
+	if sessionID == 0 {
+		return
+	}
+	now := time.Now()
+	if last, ok := sessionTouchedAt.Load(sessionID); ok && now.Sub(last.(time.Time)) < sessionTouchInterval {
+		return
+	}
+	sessionTouchedAt.Store(sessionID, now)
+
+	if err := database.DB.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"last_seen_ip": ip,
+		}).Error; err != nil {
+		utils.Logger.Warnf("更新会话 %d 访问时间失败: %v", sessionID, err)
+	}
+}
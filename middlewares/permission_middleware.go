@@ -1,18 +1,17 @@
 package middlewares
 
 import (
-	"RHPRo-Task/database"
-	"RHPRo-Task/models"
+	"RHPRo-Task/services"
 	"RHPRo-Task/utils"
-	"encoding/json"
 	"fmt"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // PermissionMiddleware 权限验证中间件
 func PermissionMiddleware(requiredPermission string) gin.HandlerFunc {
+	authorizationService := &services.AuthorizationService{}
 	return func(c *gin.Context) {
 		// 获取用户ID
 		userID, exists := c.Get("userID")
@@ -23,7 +22,7 @@ func PermissionMiddleware(requiredPermission string) gin.HandlerFunc {
 		}
 
 		// 检查用户权限
-		hasPermission, err := checkUserPermission(userID.(uint), requiredPermission)
+		hasPermission, err := authorizationService.HasPermission(userID.(uint), requiredPermission)
 		if err != nil {
 			utils.Logger.Error(fmt.Sprintf("Permission check error: %v", err))
 			utils.InternalServerError(c, "权限检查失败")
@@ -41,60 +40,39 @@ func PermissionMiddleware(requiredPermission string) gin.HandlerFunc {
 	}
 }
 
-// checkUserPermission 检查用户是否拥有指定权限（带缓存）
-func checkUserPermission(userID uint, permissionName string) (bool, error) {
-	var permissions []string
-
-	// 尝试从Redis缓存获取权限列表（如果Redis可用）
-	cacheKey := fmt.Sprintf("user_permissions:%d", userID)
-	if database.RedisClient != nil {
-		cached, err := database.GetCache(cacheKey)
-		if err == nil && cached != "" {
-			// 从缓存中获取
-			json.Unmarshal([]byte(cached), &permissions)
-
-			// 检查是否拥有所需权限
-			for _, perm := range permissions {
-				if perm == permissionName {
-					return true, nil
-				}
-			}
-			return false, nil
+// ResourcePermissionMiddleware 资源范围权限验证中间件
+// 除功能权限外，还要求路径参数 param 指定的资源在当前用户负责范围内（超级管理员不受限制）
+func ResourcePermissionMiddleware(requiredPermission string, scope services.ResourceScope, param string) gin.HandlerFunc {
+	authorizationService := &services.AuthorizationService{}
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			utils.Unauthorized(c, "用户未认证")
+			c.Abort()
+			return
 		}
-	}
-
-	// 从数据库查询
-	var user models.User
-	if err := database.DB.Preload("Roles.Permissions").First(&user, userID).Error; err != nil {
-		return false, err
-	}
 
-	// 收集所有权限
-	permMap := make(map[string]bool)
-	for _, role := range user.Roles {
-		for _, perm := range role.Permissions {
-			permMap[perm.Name] = true
+		resourceID, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			utils.BadRequest(c, "无效的ID")
+			c.Abort()
+			return
 		}
-	}
 
-	// 转换为列表
-	for perm := range permMap {
-		permissions = append(permissions, perm)
-	}
-
-	// 缓存到Redis（5分钟）- 仅当Redis可用时
-	if database.RedisClient != nil {
-		if data, err := json.Marshal(permissions); err == nil {
-			database.SetCache(cacheKey, string(data), 5*time.Minute)
+		allowed, err := authorizationService.Authorize(userID.(uint), requiredPermission, scope, uint(resourceID))
+		if err != nil {
+			utils.Logger.Error(fmt.Sprintf("Permission check error: %v", err))
+			utils.InternalServerError(c, "权限检查失败")
+			c.Abort()
+			return
 		}
-	}
 
-	// 检查是否拥有所需权限
-	for _, perm := range permissions {
-		if perm == permissionName {
-			return true, nil
+		if !allowed {
+			utils.Forbidden(c, "没有访问权限")
+			c.Abort()
+			return
 		}
-	}
 
-	return false, nil
+		c.Next()
+	}
 }
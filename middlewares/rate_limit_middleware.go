@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"RHPRo-Task/ratelimit"
+	"RHPRo-Task/utils"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware 接口限流中间件
+// 同一客户端（已认证时按用户，否则按IP）在每个窗口内最多请求 limit 次，scope 区分不同接口的计数；limit 为 0 时不限流
+func RateLimitMiddleware(scope string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 || window <= 0 {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if userID, exists := c.Get("userID"); exists {
+			client = fmt.Sprintf("user:%v", userID)
+		}
+
+		count, ttl, err := ratelimit.GetStore().Incr("rate_limit:"+scope+":"+client, window)
+		if err != nil {
+			// 计数存储不可用时放行
+			utils.Logger.Warnf("Rate limit check error: %v", err)
+			c.Next()
+			return
+		}
+
+		remaining := int64(limit) - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+
+		if count > int64(limit) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(ttl.Seconds()))))
+			utils.TooManyRequests(c, "请求过于频繁，请稍后再试")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+// 审批阶段的审批人类型
+const (
+	ApproverTypeExecutorLeader   = "executor_leader"   // 执行人所在部门负责人
+	ApproverTypeCreatorLeader    = "creator_leader"    // 创建人所在部门负责人
+	ApproverTypeTaskLeader       = "task_leader"       // 任务所属部门负责人
+	ApproverTypeDepartmentLeader = "department_leader" // 指定部门负责人
+	ApproverTypeCreator          = "creator"           // 任务创建人
+	ApproverTypeUser             = "user"              // 指定用户
+)
+
+// ApprovalChainStage 审批链阶段（approval_chain_stages 表）
+// 阶段内任一审批人通过即进入下一阶段，任一阶段驳回即退回执行人
+type ApprovalChainStage struct {
+	BaseModel
+	// 审批链模板ID
+	TemplateID uint `gorm:"index;not null" json:"template_id"`
+	// 阶段序号（从 1 开始）
+	StageNo int `gorm:"not null" json:"stage_no"`
+	// 阶段名称
+	Name string `gorm:"size:100;not null" json:"name"`
+	// 审批人类型：executor_leader/creator_leader/task_leader/department_leader/creator/user
+	ApproverType string `gorm:"size:50;not null" json:"approver_type"`
+	// 指定部门ID（department_leader 类型）
+	ApproverDepartmentID *uint `json:"approver_department_id,omitempty"`
+	// 指定用户ID（user 类型）
+	ApproverUserID *uint `json:"approver_user_id,omitempty"`
+}
+
+// TableName 指定表名
+func (ApprovalChainStage) TableName() string {
+	return "approval_chain_stages"
+}
@@ -0,0 +1,31 @@
+package models
+
+// ApprovalChainTemplate 审批链模板（approval_chain_templates 表）
+// 按任务类型、审核类型、优先级和跨部门标记匹配任务；匹配的任务提交方案/计划后按阶段依次审批
+type ApprovalChainTemplate struct {
+	BaseModel
+	// 模板名称
+	Name string `gorm:"size:100;not null" json:"name"`
+	// 适用的审核类型：solution_review/plan_review（plan_review 同时适用于 execution_plan_review）
+	ReviewType string `gorm:"size:50;not null;index" json:"review_type"`
+	// 适用的任务类型编码（为空表示不限）
+	TaskTypeCode string `gorm:"size:50" json:"task_type_code,omitempty"`
+	// 触发条件：任务优先级达到该值（为空表示不按优先级触发）
+	MinPriority *int `json:"min_priority,omitempty"`
+	// 触发条件：跨部门任务
+	MatchCrossDepartment bool `gorm:"default:false" json:"match_cross_department"`
+	// 匹配顺序（多个模板匹配时取值大的，相同时取后创建的）
+	SortOrder int `gorm:"default:0" json:"sort_order"`
+	// 是否启用
+	IsActive bool `gorm:"default:true" json:"is_active"`
+	// 描述
+	Description string `gorm:"size:255" json:"description"`
+
+	// 关联
+	Stages []ApprovalChainStage `gorm:"foreignKey:TemplateID" json:"stages,omitempty"`
+}
+
+// TableName 指定表名
+func (ApprovalChainTemplate) TableName() string {
+	return "approval_chain_templates"
+}
@@ -0,0 +1,37 @@
+package models
+
+// 审计事件类型
+const (
+	AuditActionLoginLocked       = "login_locked"            // 账号登录失败次数过多被锁定
+	AuditActionLoginIPLocked     = "login_ip_locked"         // IP 登录失败次数过多被锁定
+	AuditActionLoginUnlocked     = "login_unlocked"          // 管理员解除账号登录锁定
+	AuditActionLoginIPUnlocked   = "login_ip_unlocked"       // 管理员解除 IP 登录锁定
+	AuditActionTwoFactorEnabled  = "two_factor_enabled"      // 启用两步验证
+	AuditActionTwoFactorDisabled = "two_factor_disabled"     // 解除两步验证（本人解除或管理员重置）
+	AuditActionRecoveryCodeUsed  = "recovery_code_used"      // 使用恢复码登录
+	AuditActionRecoveryRenewed   = "recovery_codes_renewed"  // 重新生成恢复码
+	AuditActionDirectoryDisabled = "directory_user_disabled" // 用户已从 LDAP 目录移除，同步时禁用
+	AuditActionIdentityLinked    = "identity_linked"         // 外部身份（OIDC）关联到已有账号
+)
+
+// AuditLog 安全审计日志（audit_logs 表）
+type AuditLog struct {
+	BaseModel
+	// 事件类型
+	Action string `gorm:"size:50;index;not null" json:"action"`
+	// 相关用户ID（如被锁定的账号，IP 锁定等不对应用户时为空）
+	UserID *uint `gorm:"index" json:"user_id,omitempty"`
+	// 操作人ID（管理员操作时记录，系统自动触发时为空）
+	ActorID *uint `json:"actor_id,omitempty"`
+	// 事件对象（如手机号、IP）
+	Target string `gorm:"size:100;index" json:"target"`
+	// 请求来源IP
+	IP string `gorm:"size:64" json:"ip"`
+	// 事件详情
+	Detail string `gorm:"size:500" json:"detail"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,31 @@
+package models
+
+import "gorm.io/datatypes"
+
+// BusinessCalendar 工作日历（business_calendars 表）
+// 定义工作日和每日工作时段，可绑定到部门；SLA 计时只累计工作时间
+type BusinessCalendar struct {
+	BaseModel
+	// 日历名称
+	Name string `gorm:"size:100;not null" json:"name"`
+	// 绑定的部门ID（为空表示通用日历）
+	DepartmentID *uint `gorm:"index" json:"department_id,omitempty"`
+	// 是否为默认日历（部门未配置日历时使用）
+	IsDefault bool `gorm:"default:false" json:"is_default"`
+	// 时区（如 Asia/Shanghai）
+	TimeZone string `gorm:"size:50;default:'Asia/Shanghai'" json:"time_zone"`
+	// 每日上班时间（HH:MM）
+	WorkStart string `gorm:"size:5;default:'09:00'" json:"work_start"`
+	// 每日下班时间（HH:MM）
+	WorkEnd string `gorm:"size:5;default:'18:00'" json:"work_end"`
+	// 工作日（1-7 表示周一至周日）
+	WorkDays datatypes.JSONSlice[int] `gorm:"type:jsonb" json:"work_days"`
+
+	// 关联
+	Holidays []CalendarHoliday `gorm:"foreignKey:CalendarID" json:"holidays,omitempty"`
+}
+
+// TableName 指定表名
+func (BusinessCalendar) TableName() string {
+	return "business_calendars"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// CalendarHoliday 日历节假日/调休（calendar_holidays 表）
+type CalendarHoliday struct {
+	BaseModel
+	// 工作日历ID
+	CalendarID uint `gorm:"index;not null" json:"calendar_id"`
+	// 日期
+	Date time.Time `gorm:"type:date;not null" json:"date"`
+	// 名称（如 国庆节）
+	Name string `gorm:"size:100" json:"name"`
+	// 是否为调休上班日（true 表示该日按工作日计算，false 表示放假）
+	IsWorkday bool `gorm:"default:false" json:"is_workday"`
+}
+
+// TableName 指定表名
+func (CalendarHoliday) TableName() string {
+	return "calendar_holidays"
+}
@@ -13,6 +13,8 @@ type Department struct {
 	Status int `gorm:"default:1" json:"status"`
 	// 排序序号（同级部门内排序，数值越小越靠前）
 	SortOrder int `gorm:"default:0" json:"sort_order"`
+	// 外部目录中对应的 OU（LDAP 同步的部门为 OU 的 DN，手工创建的部门为空）
+	ExternalID string `gorm:"size:500;index" json:"external_id,omitempty"`
 	// 部门负责人（多对多）
 	Leaders []*User `gorm:"many2many:department_leaders;" json:"leaders,omitempty"`
 }
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// 定时任务执行触发方式
+const (
+	JobTriggerSchedule = "schedule" // 按计划触发
+	JobTriggerManual   = "manual"   // 管理员手动触发
+)
+
+// 定时任务执行状态
+const (
+	JobRunStatusRunning = "running" // 执行中
+	JobRunStatusSuccess = "success" // 成功
+	JobRunStatusFailed  = "failed"  // 失败
+)
+
+// JobRun 定时任务执行记录（job_runs 表）
+type JobRun struct {
+	// 主键ID
+	ID uint `gorm:"primarykey" json:"id"`
+	// 创建时间
+	CreatedAt time.Time `json:"created_at"`
+
+	// 任务名称
+	JobName string `gorm:"size:100;not null;index" json:"job_name"`
+	// 触发方式：schedule/manual
+	Trigger string `gorm:"size:20;not null" json:"trigger"`
+	// 手动触发的用户ID（按计划触发时为空）
+	TriggeredBy *uint `json:"triggered_by,omitempty"`
+	// 执行的实例（主机名）
+	Instance string `gorm:"size:100" json:"instance"`
+	// 执行状态：running/success/failed
+	Status string `gorm:"size:20;not null" json:"status"`
+	// 开始时间
+	StartedAt time.Time `gorm:"not null" json:"started_at"`
+	// 结束时间（执行中为空）
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// 耗时（毫秒）
+	DurationMs int64 `json:"duration_ms"`
+	// 执行结果摘要
+	Output string `gorm:"type:text" json:"output"`
+	// 错误信息
+	Error string `gorm:"type:text" json:"error"`
+}
+
+// TableName 指定表名
+func (JobRun) TableName() string {
+	return "job_runs"
+}
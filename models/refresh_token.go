@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// RefreshToken 刷新令牌（refresh_tokens 表）
+// 只保存令牌的哈希值；每次刷新都轮换为同一令牌族中的新令牌，已轮换的令牌再次使用时吊销整个令牌族
+type RefreshToken struct {
+	BaseModel
+	// 用户ID
+	UserID uint `gorm:"index;not null" json:"user_id"`
+	// 令牌哈希（SHA-256）
+	TokenHash string `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	// 令牌族ID（同一次登录轮换出的令牌属于同一族）
+	FamilyID string `gorm:"size:64;index;not null" json:"family_id"`
+	// 同时签发的访问令牌ID（jti）
+	AccessTokenID string `gorm:"size:64;index" json:"access_token_id"`
+	// 访问令牌过期时间
+	AccessExpiresAt time.Time `json:"access_expires_at"`
+	// 刷新令牌过期时间
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	// 吊销时间（已轮换、注销或被吊销）
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// 吊销原因：rotated-已轮换，logout-注销，reuse-重复使用，password_changed-修改密码，disabled-禁用，deleted-删除
+	RevokeReason string `gorm:"size:50" json:"revoke_reason,omitempty"`
+	// 轮换后的新令牌ID
+	ReplacedByID *uint `json:"replaced_by_id,omitempty"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// 批注对象（与审核会话的 TargetType 一致）
+const (
+	AnnotationTargetSolution = "requirement_solutions" // 思路方案
+	AnnotationTargetPlan     = "execution_plans"       // 执行计划
+)
+
+// 批注锚点类型
+const (
+	AnnotationAnchorText = "text" // 文本片段：字段 + 字符区间
+	AnnotationAnchorStep = "step" // 实施步骤：JSON 路径
+	AnnotationAnchorGoal = "goal" // 执行计划目标
+)
+
+// 批注锚点状态
+const (
+	AnnotationAnchored = "anchored" // 锚点在当前版本中仍然有效
+	AnnotationOutdated = "outdated" // 新版本中找不到原锚点（原文已修改或删除）
+)
+
+// ReviewAnnotation 审核批注（review_annotations 表）
+// 批注锚定在方案/执行计划的某个版本上，提交新版本时未解决的批注会转移到新版本并重新定位
+type ReviewAnnotation struct {
+	BaseModel
+	// 关联任务ID
+	TaskID uint `gorm:"index;not null" json:"task_id"`
+	// 批注对象表名：requirement_solutions/execution_plans
+	TargetType string `gorm:"size:50;not null" json:"target_type"`
+	// 当前锚定的版本记录ID
+	TargetID uint `gorm:"index;not null" json:"target_id"`
+	// 当前锚定的版本号
+	Version int `gorm:"not null" json:"version"`
+	// 创建批注时的版本记录ID
+	OriginTargetID uint `gorm:"not null" json:"origin_target_id"`
+	// 创建批注时的版本号
+	OriginVersion int `gorm:"not null" json:"origin_version"`
+	// 创建批注时进行中的审核会话ID（可空）
+	ReviewSessionID *uint `gorm:"index" json:"review_session_id,omitempty"`
+	// 锚点类型：text/step/goal
+	AnchorType string `gorm:"size:20;not null" json:"anchor_type"`
+	// 锚点状态：anchored/outdated
+	AnchorStatus string `gorm:"size:20;default:'anchored'" json:"anchor_status"`
+	// 文本锚点的字段名（如 content/mindmap_markdown）
+	Field string `gorm:"size:50" json:"field,omitempty"`
+	// 文本锚点的起始字符偏移（含）
+	StartOffset int `json:"start_offset"`
+	// 文本锚点的结束字符偏移（不含）
+	EndOffset int `json:"end_offset"`
+	// 批注时选中的原文（步骤锚点为步骤内容的 JSON）
+	QuotedText string `gorm:"type:text" json:"quoted_text"`
+	// 步骤锚点的 JSON 路径（如 steps[1]）
+	StepPath string `gorm:"size:255" json:"step_path,omitempty"`
+	// 目标锚点的目标ID（当前版本）
+	GoalID *uint `gorm:"index" json:"goal_id,omitempty"`
+	// 目标锚点的目标编号（当前版本）
+	GoalNo int `json:"goal_no,omitempty"`
+	// 批注人用户ID
+	AuthorID uint `gorm:"index;not null" json:"author_id"`
+	// 批注内容
+	Content string `gorm:"type:text;not null" json:"content"`
+	// 是否已解决
+	IsResolved bool `gorm:"default:false" json:"is_resolved"`
+	// 解决人用户ID
+	ResolvedBy *uint `json:"resolved_by,omitempty"`
+	// 解决时间
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	// 最近一次转移到新版本的时间
+	CarriedAt *time.Time `json:"carried_at,omitempty"`
+
+	// 关联
+	Replies []ReviewAnnotationReply `gorm:"foreignKey:AnnotationID" json:"replies,omitempty"`
+}
+
+// TableName 指定表名
+func (ReviewAnnotation) TableName() string {
+	return "review_annotations"
+}
@@ -0,0 +1,17 @@
+package models
+
+// ReviewAnnotationReply 批注回复（review_annotation_replies 表）
+type ReviewAnnotationReply struct {
+	BaseModel
+	// 批注ID
+	AnnotationID uint `gorm:"index;not null" json:"annotation_id"`
+	// 回复人用户ID
+	AuthorID uint `gorm:"index;not null" json:"author_id"`
+	// 回复内容
+	Content string `gorm:"type:text;not null" json:"content"`
+}
+
+// TableName 指定表名
+func (ReviewAnnotationReply) TableName() string {
+	return "review_annotation_replies"
+}
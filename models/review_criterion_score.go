@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ReviewCriterionScore 审核人对评分维度的打分（review_criterion_scores 表）
+type ReviewCriterionScore struct {
+	// 主键ID
+	ID uint `gorm:"primarykey" json:"id"`
+	// 创建时间
+	CreatedAt time.Time `json:"created_at"`
+
+	// 审核会话ID
+	ReviewSessionID uint `gorm:"index;not null" json:"review_session_id"`
+	// 审核记录ID
+	ReviewRecordID uint `gorm:"index;not null" json:"review_record_id"`
+	// 评分维度ID
+	CriterionID uint `gorm:"not null" json:"criterion_id"`
+	// 分值
+	Score int `gorm:"not null" json:"score"`
+}
+
+// TableName 指定表名
+func (ReviewCriterionScore) TableName() string {
+	return "review_criterion_scores"
+}
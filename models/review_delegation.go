@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ReviewDelegation 审核委托（review_delegations 表）
+// 委托人在生效期间（可按部门/任务类型限定范围）的审核、审批可由代理人代为处理，代理关系不传递
+type ReviewDelegation struct {
+	BaseModel
+	// 委托人用户ID
+	DelegatorID uint `gorm:"index;not null" json:"delegator_id"`
+	// 代理人用户ID
+	DelegateID uint `gorm:"index;not null" json:"delegate_id"`
+	// 生效开始时间
+	StartAt time.Time `gorm:"not null" json:"start_at"`
+	// 生效结束时间（不含）
+	EndAt time.Time `gorm:"not null" json:"end_at"`
+	// 限定部门ID（为空表示不限）
+	DepartmentID *uint `gorm:"index" json:"department_id,omitempty"`
+	// 限定任务类型编码（为空表示不限）
+	TaskTypeCode string `gorm:"size:50" json:"task_type_code,omitempty"`
+	// 委托原因
+	Reason string `gorm:"size:255" json:"reason"`
+	// 是否有效（撤销后为 false）
+	IsActive bool `gorm:"default:true" json:"is_active"`
+	// 撤销时间
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName 指定表名
+func (ReviewDelegation) TableName() string {
+	return "review_delegations"
+}
@@ -11,6 +11,8 @@ type ReviewRecord struct {
 	ReviewSessionID uint `gorm:"index;not null" json:"review_session_id"`
 	// 审核人用户ID
 	ReviewerID uint `gorm:"index;not null" json:"reviewer_id"`
+	// 代理人用户ID（委托代审时实际提交意见的人，为空表示本人提交）
+	DelegateID *uint `gorm:"index" json:"delegate_id,omitempty"`
 	// 审核人角色：creator/jury/expert
 	ReviewerRole string `gorm:"size:50" json:"reviewer_role"`
 	// 审核意见：approve/reject/abstain
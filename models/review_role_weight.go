@@ -0,0 +1,26 @@
+package models
+
+// 审核人角色
+const (
+	ReviewerRoleCreator = "creator" // 任务创建人（最终决策）
+	ReviewerRoleLeader  = "leader"  // 任务所属部门负责人
+	ReviewerRoleJury    = "jury"    // 陪审团成员
+	ReviewerRoleExpert  = "expert"  // 评审专家
+)
+
+// ReviewRoleWeight 审核人角色投票权重（review_role_weights 表）
+// 加权投票模式下每张票的权重取投票人角色对应的权重，未配置的角色权重为 1
+type ReviewRoleWeight struct {
+	BaseModel
+	// 审核人角色：leader/jury/expert
+	ReviewerRole string `gorm:"size:50;uniqueIndex;not null" json:"reviewer_role"`
+	// 投票权重
+	Weight float64 `gorm:"type:decimal(3,2);not null;default:1.0" json:"weight"`
+	// 描述
+	Description string `gorm:"size:255" json:"description"`
+}
+
+// TableName 指定表名
+func (ReviewRoleWeight) TableName() string {
+	return "review_role_weights"
+}
@@ -0,0 +1,29 @@
+package models
+
+// ReviewRubric 审核评分标准（review_rubrics 表）
+// 按审核类型配置评分维度、权重和分值范围；发起审核时绑定当前启用的评分标准
+type ReviewRubric struct {
+	BaseModel
+	// 评分标准名称
+	Name string `gorm:"size:100;not null" json:"name"`
+	// 适用的审核类型：solution_review/plan_review（plan_review 同时适用于 execution_plan_review）
+	ReviewType string `gorm:"size:50;not null;index" json:"review_type"`
+	// 分值下限
+	ScaleMin int `gorm:"not null;default:1" json:"scale_min"`
+	// 分值上限
+	ScaleMax int `gorm:"not null;default:5" json:"scale_max"`
+	// 通过所需的最低加权总分（为空表示不限制）
+	MinTotalScore *float64 `gorm:"type:decimal(6,2)" json:"min_total_score,omitempty"`
+	// 是否启用（每个审核类型最多一个启用的评分标准）
+	IsActive bool `gorm:"default:true" json:"is_active"`
+	// 描述
+	Description string `gorm:"size:255" json:"description"`
+
+	// 关联
+	Criteria []ReviewRubricCriterion `gorm:"foreignKey:RubricID" json:"criteria,omitempty"`
+}
+
+// TableName 指定表名
+func (ReviewRubric) TableName() string {
+	return "review_rubrics"
+}
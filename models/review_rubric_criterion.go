@@ -0,0 +1,21 @@
+package models
+
+// ReviewRubricCriterion 评分维度（review_rubric_criteria 表）
+type ReviewRubricCriterion struct {
+	BaseModel
+	// 评分标准ID
+	RubricID uint `gorm:"index;not null" json:"rubric_id"`
+	// 维度名称（如 可行性、完整性）
+	Name string `gorm:"size:100;not null" json:"name"`
+	// 维度说明
+	Description string `gorm:"size:255" json:"description"`
+	// 权重（加权总分按权重占比计算）
+	Weight float64 `gorm:"type:decimal(5,2);not null;default:1.0" json:"weight"`
+	// 排序
+	SortOrder int `gorm:"default:0" json:"sort_order"`
+}
+
+// TableName 指定表名
+func (ReviewRubricCriterion) TableName() string {
+	return "review_rubric_criteria"
+}
@@ -1,15 +1,34 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// 审核模式
+const (
+	ReviewModeSingle    = "single"    // 单人审核：创建人决策
+	ReviewModeJury      = "jury"      // 陪审团审核：陪审团意见供参考，创建人决策
+	ReviewModeThreshold = "threshold" // 门槛投票：每票权重为 1，赞成票达到所需票数时自动通过
+	ReviewModeWeighted  = "weighted"  // 加权投票：按审核人角色权重计票，赞成权重达到所需值时自动通过
+)
+
+// 陪审团响应截止后的处理策略
+const (
+	ReviewDeadlinePolicyAbstain         = "abstain"          // 未响应视为弃权
+	ReviewDeadlinePolicyEscalate        = "escalate"         // 升级到任务所属部门负责人决策
+	ReviewDeadlinePolicyCreatorFinalize = "creator_finalize" // 创建人直接决策，记录法定人数不足
+)
 
 // ReviewSession 审核会话（review_sessions 表）
 type ReviewSession struct {
 	BaseModel
 	// 关联任务ID
 	TaskID uint `gorm:"index;not null" json:"task_id"`
-	// 审核类型：goal_review/solution_review/plan_review
+	// 审核类型：goal_review/solution_review/plan_review/status_review/transition_review
 	ReviewType string `gorm:"size:50;not null" json:"review_type"`
-	// 被审核对象表名：requirement_goals/requirement_solutions/execution_plans
+	// 被审核对象表名：requirement_goals/requirement_solutions/execution_plans/tasks/task_transition_requests
 	TargetType string `gorm:"size:50;not null" json:"target_type"`
 	// 被审核对象ID
 	TargetID uint `gorm:"not null" json:"target_id"`
@@ -19,18 +38,44 @@ type ReviewSession struct {
 	InitiatedAt time.Time `json:"initiated_at"`
 	// 审核状态：pending/in_review/approved/rejected/cancelled
 	Status string `gorm:"size:50;default:'pending'" json:"status"`
-	// 审核模式：single/jury
+	// 审核模式：single/jury/threshold/weighted
 	ReviewMode string `gorm:"size:50;not null" json:"review_mode"`
-	// 需要的通过票数（陪审团模式）
+	// 需要的通过票数（陪审团模式；加权投票模式下为需要的赞成权重）
 	RequiredApprovals int `gorm:"default:1" json:"required_approvals"`
 	// 最终决策
 	FinalDecision *string `gorm:"size:50" json:"final_decision,omitempty"`
 	// 最终决策人用户ID（可空）
 	FinalDecisionBy *uint `json:"final_decision_by,omitempty"`
+	// 最终决策的代理人用户ID（委托代审时实际决策的人，为空表示本人决策）
+	FinalDecisionDelegateID *uint `json:"final_decision_delegate_id,omitempty"`
 	// 最终决策时间（可空）
 	FinalDecisionAt *time.Time `json:"final_decision_at,omitempty"`
 	// 最终决策备注
 	FinalDecisionComment string `gorm:"type:text" json:"final_decision_comment"`
+	// 绑定的评分标准ID（发起审核时审核类型启用的评分标准，审批链只绑定第一个阶段；为空表示不评分）
+	RubricID *uint `gorm:"index" json:"rubric_id,omitempty"`
+	// 是否由创建人越过投票结果直接决策（投票模式）
+	IsOverridden bool `gorm:"default:false" json:"is_overridden"`
+	// 陪审团响应截止时间（单人审核为空）
+	ResponseDueAt *time.Time `json:"response_due_at,omitempty"`
+	// 响应截止后的处理策略：abstain/escalate/creator_finalize
+	DeadlinePolicy string `gorm:"size:50" json:"deadline_policy,omitempty"`
+	// 截止前提醒未响应成员的时间
+	RemindedAt *time.Time `json:"reminded_at,omitempty"`
+	// 执行截止处理策略的时间
+	DeadlineHandledAt *time.Time `json:"deadline_handled_at,omitempty"`
+	// 审批链模板ID（按审批链逐级审批时）
+	ChainTemplateID *uint `gorm:"index" json:"chain_template_id,omitempty"`
+	// 审批链阶段序号（0 表示不属于审批链）
+	ChainStageNo int `gorm:"default:0" json:"chain_stage_no"`
+	// 审批链阶段名称
+	ChainStageName string `gorm:"size:100" json:"chain_stage_name,omitempty"`
+	// 本阶段的审批人用户ID（创建阶段会话时确定）
+	StageApproverIDs datatypes.JSONSlice[uint] `gorm:"type:jsonb" json:"stage_approver_ids,omitempty"`
+	// 上一阶段的审核会话ID
+	PrevStageSessionID *uint `gorm:"index" json:"prev_stage_session_id,omitempty"`
+	// 下一阶段的审核会话ID（本阶段通过后创建）
+	NextStageSessionID *uint `json:"next_stage_session_id,omitempty"`
 	// 完成时间（可空）
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 	// 关联的审核记录
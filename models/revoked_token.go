@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// RevokedToken 已吊销的访问令牌（revoked_tokens 表）
+// 认证中间件拒绝列表中的令牌，访问令牌过期后记录可清理
+type RevokedToken struct {
+	BaseModel
+	// 访问令牌ID（jti）
+	TokenID string `gorm:"size:64;uniqueIndex;not null" json:"token_id"`
+	// 用户ID
+	UserID uint `gorm:"index;not null" json:"user_id"`
+	// 访问令牌过期时间
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	// 吊销原因
+	Reason string `gorm:"size:50" json:"reason"`
+}
+
+// TableName 指定表名
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// SLABreach SLA 违约事件（sla_breaches 表）
+// 任务在某状态停留超过策略限定的工作时间时记录，离开该状态时标记解除
+type SLABreach struct {
+	BaseModel
+	// 任务ID
+	TaskID uint `gorm:"index;not null" json:"task_id"`
+	// SLA 策略ID
+	PolicyID uint `gorm:"index;not null" json:"policy_id"`
+	// 违约时所处的状态编码
+	StatusCode string `gorm:"size:50;not null" json:"status_code"`
+	// 进入该状态的时间
+	EnteredAt time.Time `gorm:"not null" json:"entered_at"`
+	// SLA 截止时间
+	DueAt time.Time `gorm:"not null" json:"due_at"`
+	// 检测到违约的时间
+	BreachedAt time.Time `gorm:"not null" json:"breached_at"`
+	// 违约时的执行人用户ID（可空）
+	ExecutorID *uint `gorm:"index" json:"executor_id,omitempty"`
+	// 违约时任务所属部门ID（可空）
+	DepartmentID *uint `gorm:"index" json:"department_id,omitempty"`
+	// 解除时间（任务离开该状态的时间，可空）
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// TableName 指定表名
+func (SLABreach) TableName() string {
+	return "sla_breaches"
+}
@@ -0,0 +1,31 @@
+package models
+
+// SLAPolicy SLA 策略（sla_policies 表）
+// 限定某任务类型下任务在某状态中停留的最长工作时间，可按优先级区分
+type SLAPolicy struct {
+	BaseModel
+	// 策略名称
+	Name string `gorm:"size:100;not null" json:"name"`
+	// 任务类型编码
+	TaskTypeCode string `gorm:"size:50;not null;index" json:"task_type_code"`
+	// 状态编码（在该状态中停留的时间受限）
+	StatusCode string `gorm:"size:50;not null" json:"status_code"`
+	// 适用的优先级（为空表示所有优先级；同时存在时优先匹配指定优先级的策略）
+	Priority *int `json:"priority,omitempty"`
+	// 最长停留时间（分钟，按工作日历计算）
+	MaxMinutes int `gorm:"not null" json:"max_minutes"`
+	// 使用的工作日历ID（为空时使用任务所属部门的日历，部门未配置时使用默认日历）
+	CalendarID *uint `json:"calendar_id,omitempty"`
+	// 是否启用
+	IsActive bool `gorm:"default:true" json:"is_active"`
+	// 描述
+	Description string `gorm:"type:text" json:"description"`
+
+	// 关联
+	Calendar *BusinessCalendar `gorm:"foreignKey:CalendarID" json:"calendar,omitempty"`
+}
+
+// TableName 指定表名
+func (SLAPolicy) TableName() string {
+	return "sla_policies"
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+const (
+	TaskDeadlineKindSolution      = "solution"       // 思路方案提交截止
+	TaskDeadlineKindExecutionPlan = "execution_plan" // 执行计划提交截止
+)
+
+const (
+	TaskDeadlineStatusActive  = "active"  // 计时中
+	TaskDeadlineStatusOverdue = "overdue" // 已超期（已升级，任务仍未离开该状态）
+	TaskDeadlineStatusClosed  = "closed"  // 已结束（任务已离开该状态）
+)
+
+// TaskDeadline 任务截止时间（task_deadlines 表）
+// 任务进入待提交方案/待提交计划状态时创建，离开该状态时关闭
+type TaskDeadline struct {
+	BaseModel
+	// 任务ID
+	TaskID uint `gorm:"index;not null" json:"task_id"`
+	// 截止类型：solution/execution_plan
+	Kind string `gorm:"size:30;not null" json:"kind"`
+	// 开始计时时的任务状态编码
+	StatusCode string `gorm:"size:50;not null" json:"status_code"`
+	// 开始计时时间
+	StartedAt time.Time `gorm:"not null" json:"started_at"`
+	// 截止时间
+	DueAt time.Time `gorm:"index;not null" json:"due_at"`
+	// 状态：active/overdue/closed
+	Status string `gorm:"size:20;default:'active';index" json:"status"`
+	// 发送截止提醒的时间（可空）
+	RemindedAt *time.Time `json:"reminded_at,omitempty"`
+	// 超期升级的时间（可空）
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+	// 超期自动转换状态的时间（可空）
+	AutoTransitionedAt *time.Time `json:"auto_transitioned_at,omitempty"`
+	// 结束时间（任务离开该状态的时间，可空）
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+}
+
+// TableName 指定表名
+func (TaskDeadline) TableName() string {
+	return "task_deadlines"
+}
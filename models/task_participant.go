@@ -12,7 +12,7 @@ type TaskParticipant struct {
 	TaskID uint `gorm:"index;not null" json:"task_id"`
 	// 用户ID
 	UserID uint `gorm:"index;not null" json:"user_id"`
-	// 角色：creator/executor/reviewer/jury/observer
+	// 角色：creator/executor/reviewer/jury/expert/observer
 	Role string `gorm:"size:50;not null" json:"role"`
 	// 参与状态：pending/accepted/rejected
 	Status string `gorm:"size:50;default:'pending'" json:"status"`
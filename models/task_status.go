@@ -2,6 +2,15 @@ package models
 
 import "time"
 
+// 状态分类（流程引擎按分类而非具体编码判断状态语义）
+const (
+	TaskStatusCategoryTodo       = "todo"        // 待处理（未开始）
+	TaskStatusCategoryInProgress = "in_progress" // 进行中
+	TaskStatusCategoryDone       = "done"        // 已完成
+	TaskStatusCategoryBlocked    = "blocked"     // 受阻
+	TaskStatusCategoryCancelled  = "cancelled"   // 已取消
+)
+
 // TaskStatus 任务状态
 type TaskStatus struct {
 	// 主键ID
@@ -14,6 +23,12 @@ type TaskStatus struct {
 	Name string `gorm:"size:100;not null" json:"name"`
 	// 所属任务类型编码（如 requirement/unit_task）
 	TaskTypeCode string `gorm:"size:50;index" json:"task_type_code"`
+	// 状态分类：todo/in_progress/done/blocked/cancelled
+	Category string `gorm:"size:20;not null;default:'todo'" json:"category"`
+	// 是否为初始状态（任务可以直接以该状态创建，流程可达性校验的起点）
+	IsInitial bool `gorm:"default:false" json:"is_initial"`
+	// 是否允许在该状态的任务下创建子任务（已完成、已取消分类的状态始终不允许）
+	AllowsSubtasks bool `gorm:"default:true" json:"allows_subtasks"`
 	// 排序顺序
 	SortOrder int `gorm:"default:0" json:"sort_order"`
 	// 描述
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 // TaskStatusTransition 任务状态转换规则（task_status_transitions 表）
 type TaskStatusTransition struct {
@@ -18,6 +22,10 @@ type TaskStatusTransition struct {
 	RequiresApproval bool `gorm:"default:false" json:"requires_approval"`
 	// 是否允许此转换
 	IsAllowed bool `gorm:"default:true" json:"is_allowed"`
+	// 转换前必须全部通过的守卫名称列表（如 has_approved_solution/all_subtasks_done）
+	Guards datatypes.JSONSlice[string] `gorm:"type:jsonb" json:"guards"`
+	// 转换完成后依次执行的后置动作名称列表（如 rollup_parent/notify/create_review）
+	PostActions datatypes.JSONSlice[string] `gorm:"type:jsonb" json:"post_actions"`
 	// 描述/说明
 	Description string `gorm:"type:text" json:"description"`
 	// 创建时间
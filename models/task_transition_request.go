@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	TransitionRequestStatusPending   = "pending"   // 待审批
+	TransitionRequestStatusApproved  = "approved"  // 已通过（状态已变更）
+	TransitionRequestStatusRejected  = "rejected"  // 已驳回
+	TransitionRequestStatusCancelled = "cancelled" // 已撤回/已失效
+)
+
+// TaskTransitionRequest 状态转换申请（task_transition_requests 表）
+// 命中需要审批（requires_approval）的转换规则时创建，审批通过后任务状态才会变更
+type TaskTransitionRequest struct {
+	BaseModel
+	// 任务ID
+	TaskID uint `gorm:"index;not null" json:"task_id"`
+	// 命中的转换规则ID
+	TransitionID uint `gorm:"not null" json:"transition_id"`
+	// 源状态编码（申请时的任务状态）
+	FromStatusCode string `gorm:"size:50;not null" json:"from_status_code"`
+	// 目标状态编码
+	ToStatusCode string `gorm:"size:50;not null" json:"to_status_code"`
+	// 申请人用户ID
+	RequestedBy uint `gorm:"index;not null" json:"requested_by"`
+	// 申请备注（审批通过后写入状态变更日志）
+	Comment string `gorm:"type:text" json:"comment"`
+	// 申请状态：pending/approved/rejected/cancelled
+	Status string `gorm:"size:20;default:'pending';index" json:"status"`
+	// 关联的审核会话ID
+	ReviewSessionID *uint `gorm:"index" json:"review_session_id,omitempty"`
+	// 审批人用户ID（可空）
+	DecidedBy *uint `json:"decided_by,omitempty"`
+	// 审批代理人用户ID（委托代审时实际审批的人，为空表示本人审批）
+	DecidedDelegateID *uint `json:"decided_delegate_id,omitempty"`
+	// 审批时间（可空）
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	// 审批备注
+	DecisionComment string `gorm:"type:text" json:"decision_comment"`
+
+	// 关联
+	Task      *Task `gorm:"foreignKey:TaskID" json:"task,omitempty"`
+	Requester *User `gorm:"foreignKey:RequestedBy" json:"requester,omitempty"`
+}
+
+// TableName 指定表名
+func (TaskTransitionRequest) TableName() string {
+	return "task_transition_requests"
+}
@@ -14,6 +14,12 @@ type TaskType struct {
 	Name string `gorm:"size:100;not null" json:"name"`
 	// 描述
 	Description string `gorm:"type:text" json:"description"`
+	// 未指派执行人时的状态编码（如 req_pending_assign）
+	PoolStatusCode string `gorm:"size:50" json:"pool_status_code"`
+	// 已指派、待执行人接受时的状态编码（如 req_pending_accept）
+	AssignedStatusCode string `gorm:"size:50" json:"assigned_status_code"`
+	// 执行人接受后的状态编码（如 req_pending_solution）
+	AcceptedStatusCode string `gorm:"size:50" json:"accepted_status_code"`
 }
 
 // TableName 指定表名
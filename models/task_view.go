@@ -0,0 +1,41 @@
+package models
+
+import "gorm.io/datatypes"
+
+const (
+	TaskViewSourceAll = "all" // 全部任务（按可见范围，对应任务列表）
+	TaskViewSourceMy  = "my"  // 我的任务（我发布的/执行的/陪审的）
+)
+
+// TaskView 任务保存视图（task_views 表）
+// 保存一组命名的筛选条件、排序和展示列，归属某个用户，可选择共享给部门
+type TaskView struct {
+	BaseModel
+	// 视图名称
+	Name string `gorm:"size:100;not null" json:"name"`
+	// 视图描述
+	Description string `gorm:"type:text" json:"description"`
+	// 视图所有者用户ID
+	OwnerID uint `gorm:"index;not null" json:"owner_id"`
+	// 数据来源：all（全部任务）/my（我的任务）
+	Source string `gorm:"size:20;default:'all'" json:"source"`
+	// 筛选条件 JSONB（与任务列表查询参数一致）
+	Filters datatypes.JSON `gorm:"type:jsonb" json:"filters,omitempty"`
+	// 排序字段
+	SortBy string `gorm:"size:50" json:"sort_by"`
+	// 排序方向：asc/desc
+	SortOrder string `gorm:"size:10" json:"sort_order"`
+	// 展示列 JSONB（字段名数组）
+	Columns datatypes.JSON `gorm:"type:jsonb" json:"columns,omitempty"`
+	// 共享的部门ID（为空表示仅自己可见）
+	SharedDepartmentID *uint `gorm:"index" json:"shared_department_id,omitempty"`
+
+	// 关联
+	Owner            *User       `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
+	SharedDepartment *Department `gorm:"foreignKey:SharedDepartmentID" json:"shared_department,omitempty"`
+}
+
+// TableName 指定表名
+func (TaskView) TableName() string {
+	return "task_views"
+}
@@ -8,6 +8,12 @@ const (
 	UserStatusPending  = 2 // 待审核
 )
 
+// 账号来源
+const (
+	UserAuthSourceLocal = "local" // 本地注册、管理员创建或批量导入
+	UserAuthSourceLDAP  = "ldap"  // LDAP 目录（登录时自动创建或同步创建）
+)
+
 // User 用户模型 - 对应 users 表
 type User struct {
 	BaseModel
@@ -39,6 +45,12 @@ type User struct {
 	IsDepartmentLeader bool `gorm:"default:false" json:"is_department_leader"`
 	// 角色列表（多对多）
 	Roles []*Role `gorm:"many2many:user_roles;" json:"roles,omitempty"`
+	// 账号来源：local-本地，ldap-LDAP 目录
+	AuthSource string `gorm:"size:20;default:local" json:"auth_source"`
+	// 外部目录中的唯一标识（LDAP entryUUID / AD objectGUID）
+	ExternalID string `gorm:"size:255;index" json:"-"`
+	// 外部目录中的登录名（用于解除 LDAP 登录锁定）
+	ExternalLogin string `gorm:"size:255" json:"-"`
 	// 管理的部门（多对多，作为负责人）
 	ManagedDepartments []*Department `gorm:"many2many:department_leaders;" json:"managed_departments,omitempty"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserIdentity 用户关联的外部身份（user_identities 表）
+// 同一身份提供方的同一 subject 只能关联一个账号，一个账号可以关联多个身份提供方
+type UserIdentity struct {
+	BaseModel
+	// 用户ID
+	UserID uint `gorm:"index;not null" json:"user_id"`
+	// 身份提供方标识（对应 OIDC_PROVIDERS 中的名称）
+	Provider string `gorm:"size:50;not null;uniqueIndex:idx_user_identities_provider_subject" json:"provider"`
+	// 身份提供方中的用户唯一标识（ID Token 的 sub）
+	Subject string `gorm:"size:255;not null;uniqueIndex:idx_user_identities_provider_subject" json:"-"`
+	// 关联时身份提供方返回的邮箱
+	Email string `gorm:"size:100" json:"email,omitempty"`
+	// 最近一次通过该身份登录的时间
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
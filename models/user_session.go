@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// 登录方式
+const (
+	LoginMethodPassword   = "password"    // 手机号密码登录
+	LoginMethodWechatOpen = "wechat_open" // 微信开放平台扫码登录
+	LoginMethodWechatMP   = "wechat_mp"   // 微信小程序登录
+	LoginMethodWechatH5   = "wechat_h5"   // 微信公众号H5登录
+	LoginMethodLDAP       = "ldap"        // LDAP 目录账号登录
+	LoginMethodOIDC       = "oidc"        // OpenID Connect 单点登录
+)
+
+// UserSession 用户登录会话（user_sessions 表）
+// 每次登录创建一个会话，会话内的刷新令牌属于同一令牌族；踢出会话即吊销该令牌族
+type UserSession struct {
+	BaseModel
+	// 用户ID
+	UserID uint `gorm:"index;not null" json:"user_id"`
+	// 令牌族ID（对应 refresh_tokens.family_id）
+	FamilyID string `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	// 登录方式：password/wechat_open/wechat_mp/wechat_h5/ldap
+	LoginMethod string `gorm:"size:20;not null" json:"login_method"`
+	// 设备名称（客户端上报，未上报时根据 User-Agent 识别）
+	Device string `gorm:"size:100" json:"device"`
+	// User-Agent
+	UserAgent string `gorm:"size:500" json:"user_agent"`
+	// 登录IP
+	IP string `gorm:"size:64" json:"ip"`
+	// 最近访问时间
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// 最近访问IP
+	LastSeenIP string `gorm:"size:64" json:"last_seen_ip"`
+	// 会话过期时间（随刷新令牌轮换延长）
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	// 结束时间（注销、被踢出或被吊销）
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// 结束原因
+	RevokeReason string `gorm:"size:50" json:"revoke_reason,omitempty"`
+	// 踢出会话的操作人ID（管理员踢出他人会话时记录）
+	RevokedBy *uint `json:"revoked_by,omitempty"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
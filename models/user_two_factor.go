@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// UserTwoFactor 用户两步验证（TOTP）配置（user_two_factors 表）
+// 发起绑定时生成密钥，验证首个动态码后才启用；解除绑定时物理删除
+type UserTwoFactor struct {
+	BaseModel
+	// 用户ID（每个用户一条）
+	UserID uint `gorm:"uniqueIndex;not null" json:"user_id"`
+	// TOTP 密钥（Base32，响应中不返回）
+	Secret string `gorm:"size:64;not null" json:"-"`
+	// 启用时间（为空表示已发起绑定但尚未验证）
+	EnabledAt *time.Time `json:"enabled_at,omitempty"`
+	// 最近一次验证通过的时间步（防止同一动态码被重复使用）
+	LastUsedStep int64 `gorm:"default:0" json:"-"`
+}
+
+// TableName 指定表名
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// UserRecoveryCode 两步验证恢复码（user_recovery_codes 表）
+// 只保存恢复码的哈希值，每个恢复码只能使用一次
+type UserRecoveryCode struct {
+	BaseModel
+	// 用户ID
+	UserID uint `gorm:"index;not null" json:"user_id"`
+	// 恢复码哈希（SHA-256）
+	CodeHash string `gorm:"size:64;not null" json:"-"`
+	// 使用时间（为空表示未使用）
+	UsedAt *time.Time `json:"used_at,omitempty"`
+}
+
+// TableName 指定表名
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
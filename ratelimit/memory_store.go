@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// memorySweepThreshold 键数量超过该值时在写入时清理过期键
+const memorySweepThreshold = 10000
+
+type memoryEntry struct {
+	count    int64
+	expireAt time.Time
+}
+
+// MemoryStore 进程内计数器存储（单实例部署或 Redis 不可用时使用）
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryStore 创建进程内计数器存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// get 获取未过期的键（已过期的键顺便删除），调用方需持有锁
+func (s *MemoryStore) get(key string, now time.Time) *memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if !now.Before(entry.expireAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return entry
+}
+
+// sweep 清理过期键，调用方需持有锁
+func (s *MemoryStore) sweep(now time.Time) {
+	if len(s.entries) < memorySweepThreshold {
+		return
+	}
+	for key, entry := range s.entries {
+		if !now.Before(entry.expireAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// Incr 计数加一
+func (s *MemoryStore) Incr(key string, window time.Duration) (int64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry := s.get(key, now)
+	if entry == nil {
+		s.sweep(now)
+		entry = &memoryEntry{expireAt: now.Add(window)}
+		s.entries[key] = entry
+	}
+	entry.count++
+	return entry.count, entry.expireAt.Sub(now), nil
+}
+
+// Set 设置带过期时间的标记
+func (s *MemoryStore) Set(key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	s.entries[key] = &memoryEntry{count: 1, expireAt: now.Add(ttl)}
+	return nil
+}
+
+// Expire 重新设置已有键的过期时间
+func (s *MemoryStore) Expire(key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry := s.get(key, now); entry != nil {
+		entry.expireAt = now.Add(ttl)
+	}
+	return nil
+}
+
+// TTL 返回键的剩余有效时间
+func (s *MemoryStore) TTL(key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry := s.get(key, now); entry != nil {
+		return entry.expireAt.Sub(now), nil
+	}
+	return 0, nil
+}
+
+// Delete 删除键
+func (s *MemoryStore) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore 基于 Redis 的计数器存储（多实例共享计数）
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore 创建 Redis 计数器存储
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// incrScript 计数加一，首次计数时设置窗口时长（毫秒），返回计数和剩余时间
+var incrScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+// Incr 计数加一，首次计数时设置窗口时长
+func (s *RedisStore) Incr(key string, window time.Duration) (int64, time.Duration, error) {
+	result, err := incrScript.Run(context.Background(), s.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(result) != 2 {
+		return 0, 0, fmt.Errorf("unexpected incr result: %v", result)
+	}
+	return result[0], time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// Set 设置带过期时间的标记
+func (s *RedisStore) Set(key string, ttl time.Duration) error {
+	return s.client.Set(context.Background(), key, 1, ttl).Err()
+}
+
+// Expire 重新设置已有键的过期时间
+func (s *RedisStore) Expire(key string, ttl time.Duration) error {
+	return s.client.PExpire(context.Background(), key, ttl).Err()
+}
+
+// TTL 返回键的剩余有效时间
+func (s *RedisStore) TTL(key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(context.Background(), key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		// -2 表示键不存在，-1 表示未设置过期时间（不应出现）
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Delete 删除键
+func (s *RedisStore) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(context.Background(), keys...).Err()
+}
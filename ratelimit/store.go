@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"RHPRo-Task/database"
+	"sync"
+	"time"
+)
+
+// Store 计数器存储（失败计数、限流计数和锁定标记）
+// Redis 可用时使用 Redis，多实例共享计数；否则使用进程内存储
+type Store interface {
+	// Incr 计数加一并返回当前计数和计数窗口的剩余时间，键不存在时创建并设置窗口时长
+	Incr(key string, window time.Duration) (int64, time.Duration, error)
+	// Set 设置带过期时间的标记（如锁定标记）
+	Set(key string, ttl time.Duration) error
+	// Expire 重新设置已有键的过期时间（键不存在时忽略）
+	Expire(key string, ttl time.Duration) error
+	// TTL 返回键的剩余有效时间，键不存在时返回 0
+	TTL(key string) (time.Duration, error)
+	// Delete 删除键
+	Delete(keys ...string) error
+}
+
+var (
+	memoryStore     *MemoryStore
+	memoryStoreOnce sync.Once
+)
+
+// GetStore 获取计数器存储（Redis 可用时使用 Redis，否则使用进程内存储）
+func GetStore() Store {
+	if database.RedisClient != nil {
+		return NewRedisStore(database.RedisClient)
+	}
+	memoryStoreOnce.Do(func() {
+		memoryStore = NewMemoryStore()
+	})
+	return memoryStore
+}
+
+// LockoutDuration 按失败次数计算锁定时长：达到阈值时锁定 base，之后每多失败一次时长翻倍，不超过 max
+func LockoutDuration(failures, threshold int64, base, max time.Duration) time.Duration {
+	if threshold <= 0 || failures < threshold || base <= 0 {
+		return 0
+	}
+	duration := base
+	for i := threshold; i < failures; i++ {
+		duration *= 2
+		if max > 0 && duration >= max {
+			return max
+		}
+	}
+	if max > 0 && duration > max {
+		return max
+	}
+	return duration
+}
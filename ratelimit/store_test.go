@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreIncr(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	count, ttl, err := store.Incr("k", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, time.Minute, ttl)
+
+	// 窗口从首次计数开始，后续计数不延长窗口
+	now = now.Add(20 * time.Second)
+	count, ttl, _ = store.Incr("k", time.Minute)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, 40*time.Second, ttl)
+
+	// 窗口结束后重新计数
+	now = now.Add(40 * time.Second)
+	count, _, _ = store.Incr("k", time.Minute)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMemoryStoreSetTTLDelete(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	ttl, err := store.TTL("lock")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	assert.NoError(t, store.Set("lock", 5*time.Minute))
+	now = now.Add(time.Minute)
+	ttl, _ = store.TTL("lock")
+	assert.Equal(t, 4*time.Minute, ttl)
+
+	assert.NoError(t, store.Delete("lock"))
+	ttl, _ = store.TTL("lock")
+	assert.Equal(t, time.Duration(0), ttl)
+
+	// 过期后视为不存在
+	store.Set("lock", time.Minute)
+	now = now.Add(time.Minute)
+	ttl, _ = store.TTL("lock")
+	assert.Equal(t, time.Duration(0), ttl)
+}
+
+func TestLockoutDuration(t *testing.T) {
+	base, max := time.Minute, time.Hour
+
+	assert.Equal(t, time.Duration(0), LockoutDuration(4, 5, base, max))
+	assert.Equal(t, time.Minute, LockoutDuration(5, 5, base, max))
+	assert.Equal(t, 2*time.Minute, LockoutDuration(6, 5, base, max))
+	assert.Equal(t, 32*time.Minute, LockoutDuration(10, 5, base, max))
+	// 不超过最长锁定时长
+	assert.Equal(t, time.Hour, LockoutDuration(11, 5, base, max))
+	assert.Equal(t, time.Hour, LockoutDuration(100, 5, base, max))
+
+	// 阈值为 0 表示不锁定
+	assert.Equal(t, time.Duration(0), LockoutDuration(100, 0, base, max))
+}
+
+func TestMemoryStoreExpire(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	store.Incr("k", time.Minute)
+	assert.NoError(t, store.Expire("k", time.Hour))
+	now = now.Add(30 * time.Minute)
+	count, ttl, _ := store.Incr("k", time.Minute)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, 30*time.Minute, ttl)
+
+	// 不存在的键忽略
+	assert.NoError(t, store.Expire("missing", time.Hour))
+	ttl, _ = store.TTL("missing")
+	assert.Equal(t, time.Duration(0), ttl)
+}
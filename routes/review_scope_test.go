@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"RHPRo-Task/models"
+	"RHPRo-Task/tests/testutils"
+	"RHPRo-Task/utils"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFinalizeReview_UserRoleCreator 普通用户（user 角色）可以对自己创建的任务做审核决策，不能对他人的任务做决策
+func TestFinalizeReview_UserRoleCreator(t *testing.T) {
+	db, err := testutils.SetupTestDBWithDefault()
+	if err != nil {
+		t.Skipf("测试数据库不可用: %v", err)
+	}
+	defer testutils.CleanupTestDB(db)
+	require.NoError(t, testutils.AutoMigrateTest(db))
+	require.NoError(t, db.AutoMigrate(&models.ReviewDelegation{}, &models.RevokedToken{}, &models.UserSession{},
+		&models.TaskChangeLog{}, &models.Notification{}))
+
+	gin.SetMode(gin.TestMode)
+	if utils.Logger == nil {
+		utils.InitLogger()
+	}
+	utils.SetJWTSecret("route-test-secret")
+
+	// 与 permissions.sql 一致：user 角色拥有 review:decide
+	var perm models.Permission
+	require.NoError(t, db.Where(models.Permission{Name: "review:decide"}).FirstOrCreate(&perm).Error)
+	var role models.Role
+	require.NoError(t, db.Where(models.Role{Name: "user"}).FirstOrCreate(&role).Error)
+	require.NoError(t, db.Model(&role).Association("Permissions").Append(&perm))
+
+	suffix := time.Now().UnixNano() % 100000000
+	newUser := func(name string, n int64) *models.User {
+		user := &models.User{
+			Username: fmt.Sprintf("%s_%d", name, suffix),
+			Email:    fmt.Sprintf("%s_%d@test.com", name, suffix),
+			Password: "hashed_password",
+			Mobile:   fmt.Sprintf("1%d%08d", n, suffix),
+			Status:   models.UserStatusActive,
+			Roles:    []*models.Role{&role},
+		}
+		require.NoError(t, db.Create(user).Error)
+		return user
+	}
+	creator := newUser("route_creator", 7)
+	other := newUser("route_other", 8)
+
+	task := &models.Task{
+		TaskNo:       fmt.Sprintf("ROUTE-%d", suffix),
+		Title:        "审核决策路由测试",
+		TaskTypeCode: "requirement",
+		StatusCode:   "req_solution_review",
+		CreatorID:    creator.ID,
+	}
+	require.NoError(t, db.Create(task).Error)
+	session := &models.ReviewSession{
+		TaskID:      task.ID,
+		ReviewType:  "solution_review",
+		TargetType:  "requirement_solutions",
+		TargetID:    1,
+		InitiatedBy: creator.ID,
+		InitiatedAt: time.Now(),
+		Status:      "in_review",
+		ReviewMode:  models.ReviewModeSingle,
+	}
+	require.NoError(t, db.Create(session).Error)
+	defer func() {
+		db.Unscoped().Delete(session)
+		db.Unscoped().Delete(task)
+		db.Exec("DELETE FROM user_roles WHERE user_id IN ?", []uint{creator.ID, other.ID})
+		db.Unscoped().Delete(&models.User{}, []uint{creator.ID, other.ID})
+	}()
+
+	router := SetupRoutes()
+	finalize := func(user *models.User) *httptest.ResponseRecorder {
+		token, err := utils.GenerateAccessToken(user.ID, user.Username, user.Mobile, 1, fmt.Sprintf("route-test-%d", user.ID), time.Hour)
+		require.NoError(t, err)
+		return testutils.HTTPRequestWithAuth(router, http.MethodPost,
+			fmt.Sprintf("/api/v1/review-sessions/%d/finalize", session.ID),
+			map[string]interface{}{"approved": true}, token)
+	}
+
+	// 其他普通用户不是该会话的决策人
+	assert.Equal(t, http.StatusForbidden, finalize(other).Code)
+	// 任务创建人通过资源范围校验，进入审核决策
+	assert.Equal(t, http.StatusOK, finalize(creator).Code)
+}
@@ -1,8 +1,12 @@
 package routes
 
 import (
+	"RHPRo-Task/config"
 	"RHPRo-Task/controllers"
 	"RHPRo-Task/middlewares"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -12,7 +16,7 @@ import (
 )
 
 func SetupRoutes() *gin.Engine {
-	router := gin.New()
+	router := newRouter(config.GetConfig().Server.TrustedProxies)
 
 	// 全局中间件
 	router.Use(middlewares.CORSMiddleware()) // CORS 中间件 - 必须在最前面
@@ -27,18 +31,44 @@ func SetupRoutes() *gin.Engine {
 	adminController := controllers.NewAdminController()
 	taskController := controllers.NewTaskController()
 	detailController := controllers.NewTaskDetailController()
+	annotationController := controllers.NewReviewAnnotationController()
+	delegationController := controllers.NewReviewDelegationController()
+	sessionController := controllers.NewSessionController()
+	securityController := controllers.NewSecurityController()
+	twoFactorController := controllers.NewTwoFactorController()
+	oidcController := controllers.NewOidcController()
 	deptController := controllers.NewDepartmentController()
 	uploadController := controllers.NewUploadController()
 
+	// 接口限流配置
+	security := config.GetConfig().Security
+
 	// 公开路由
 	public := router.Group("/api/v1")
 	{
-		// 认证相关
-		public.POST("/auth/register", authController.Register)
+		// 认证相关（注册按IP限流）
+		public.POST("/auth/register",
+			middlewares.RateLimitMiddleware("register", security.RegisterRateLimitPerHour, time.Hour),
+			authController.Register)
 		public.POST("/auth/login", authController.Login)
-		// 微信登录
-		public.POST("/auth/wechat/login", authController.WechatLogin)
-		public.POST("/auth/wechat/bind", authController.WechatBind)
+		// LDAP 目录账号登录（未启用 LDAP 时返回错误）
+		public.POST("/auth/ldap/login", authController.LdapLogin)
+		// 刷新访问令牌（轮换刷新令牌）
+		public.POST("/auth/refresh", authController.RefreshToken)
+		// 两步验证登录：输入动态验证码或恢复码完成登录
+		public.POST("/auth/2fa/verify", authController.TwoFactorLogin)
+		// 两步验证登录：必须启用两步验证的账号首次登录时绑定
+		public.POST("/auth/2fa/setup", authController.TwoFactorSetup)
+		public.POST("/auth/2fa/setup/confirm", authController.TwoFactorSetupConfirm)
+		// 微信登录（按IP限流）
+		wechatRateLimit := middlewares.RateLimitMiddleware("wechat_login", security.WechatLoginRateLimitPerMinute, time.Minute)
+		public.POST("/auth/wechat/login", wechatRateLimit, authController.WechatLogin)
+		public.POST("/auth/wechat/bind", wechatRateLimit, authController.WechatBind)
+		// OIDC 单点登录（授权码 + PKCE，可配置多个身份提供方）
+		public.GET("/auth/oidc/providers", oidcController.GetProviders)
+		public.GET("/auth/oidc/:provider/authorize", oidcController.Authorize)
+		public.POST("/auth/oidc/:provider/callback", oidcController.Callback)
+		public.POST("/auth/oidc/bind", oidcController.Bind)
 
 		// 健康检查
 		public.GET("/health", func(c *gin.Context) {
@@ -55,6 +85,8 @@ func SetupRoutes() *gin.Engine {
 	{
 		// 用户信息
 		auth.GET("/profile", userController.GetProfile)
+		// 注销登录
+		auth.POST("/auth/logout", authController.Logout)
 	}
 	// 用户管理路由（需要user:read权限）
 	userRoutes := router.Group("/api/v1/users")
@@ -62,40 +94,40 @@ func SetupRoutes() *gin.Engine {
 	{
 		// 查看用户列表和详情（需要user:read权限）
 		userRoutes.GET("",
-			// middlewares.PermissionMiddleware("user:read"),
+			middlewares.PermissionMiddleware("user:read"),
 			userController.GetUserList)
 		userRoutes.GET("/:id",
-			// middlewares.PermissionMiddleware("user:read"),
+			middlewares.PermissionMiddleware("user:read"),
 			userController.GetUserByID)
 
-		// 更新用户（需要user:update权限）
+		// 更新用户（需要user:update权限，部门负责人只能更新本部门成员）
 		userRoutes.PUT("/:id",
-			// middlewares.PermissionMiddleware("user:update"),
+			middlewares.ResourcePermissionMiddleware("user:update", services.ScopeUser, "id"),
 			userController.UpdateUser)
 
-		// 分配角色（需要role:manage权限）
+		// 分配角色（需要role:manage权限，部门负责人只能为本部门成员分配自己拥有的角色）
 		userRoutes.POST("/:id/roles",
-			// middlewares.PermissionMiddleware("role:manage"),
+			middlewares.ResourcePermissionMiddleware("role:manage", services.ScopeUser, "id"),
 			userController.AssignRoles)
 
 		// 管理员创建用户（需要user:create权限）
 		userRoutes.POST("",
-			// middlewares.PermissionMiddleware("user:create"),
+			middlewares.PermissionMiddleware("user:create"),
 			userController.CreateUser)
 
 		// 审核用户（需要user:approve权限）
 		userRoutes.POST("/:id/approve",
-			// middlewares.PermissionMiddleware("user:approve"),
+			middlewares.PermissionMiddleware("user:approve"),
 			userController.ApproveUser)
 
-		// 删除用户（软删除）
+		// 删除用户（软删除，需要user:delete权限）
 		userRoutes.DELETE("/:id",
-			// middlewares.PermissionMiddleware("user:delete"),
+			middlewares.ResourcePermissionMiddleware("user:delete", services.ScopeUser, "id"),
 			userController.DeleteUser)
 
-		// 禁用用户
+		// 禁用用户（需要user:disable权限）
 		userRoutes.POST("/:id/disable",
-			// middlewares.PermissionMiddleware("user:disable"),
+			middlewares.ResourcePermissionMiddleware("user:disable", services.ScopeUser, "id"),
 			userController.DisableUser)
 
 		// 获取可指派的执行人列表（用于任务分配，只需要当前用户有效即可）
@@ -125,10 +157,6 @@ func SetupRoutes() *gin.Engine {
 	deptRoutes := router.Group("/api/v1/departments")
 	deptRoutes.Use(middlewares.AuthMiddleware())
 	{
-		// deptRoutes.POST("", middlewares.PermissionMiddleware("dept:create"), deptController.CreateDepartment)
-		// deptRoutes.PUT("/:id", middlewares.PermissionMiddleware("dept:update"), deptController.UpdateDepartment)
-		// deptRoutes.DELETE("/:id", middlewares.PermissionMiddleware("dept:delete"), deptController.DeleteDepartment)
-
 		// 获取当前用户负责的部门列表（必须放在 /:id 之前）
 		deptRoutes.GET("/my-departments", deptController.GetUserDepartments)
 
@@ -145,32 +173,27 @@ func SetupRoutes() *gin.Engine {
 		deptRoutes.POST("/sort", middlewares.PermissionMiddleware("permission:manage"), deptController.SortDepartments)
 
 		// 获取部门树结构（必须放在 /:id 之前）
-		deptRoutes.GET("/tree", deptController.GetDepartmentTree)
-
-		//创建部门
-		deptRoutes.POST("", deptController.CreateDepartment)
-		//更新部门
-		deptRoutes.PUT("/:id", deptController.UpdateDepartment)
-		//删除部门
-		deptRoutes.DELETE("/:id", deptController.DeleteDepartment)
+		deptRoutes.GET("/tree", middlewares.PermissionMiddleware("dept:read"), deptController.GetDepartmentTree)
+
+		// 创建部门（需要dept:create权限）
+		deptRoutes.POST("", middlewares.PermissionMiddleware("dept:create"), deptController.CreateDepartment)
+		// 更新部门（需要dept:update权限，部门负责人只能更新本部门及下级部门）
+		deptRoutes.PUT("/:id", middlewares.ResourcePermissionMiddleware("dept:update", services.ScopeDepartment, "id"), deptController.UpdateDepartment)
+		// 删除部门（需要dept:delete权限，部门负责人只能删除本部门及下级部门）
+		deptRoutes.DELETE("/:id", middlewares.ResourcePermissionMiddleware("dept:delete", services.ScopeDepartment, "id"), deptController.DeleteDepartment)
 		// 获取部门列表和详情
-		deptRoutes.GET("", deptController.GetDepartmentList)
-		deptRoutes.GET("/:id", deptController.GetDepartmentDetail)
+		deptRoutes.GET("", middlewares.PermissionMiddleware("dept:read"), deptController.GetDepartmentList)
+		deptRoutes.GET("/:id", middlewares.PermissionMiddleware("dept:read"), deptController.GetDepartmentDetail)
 
-		// 负责人管理
-		// deptRoutes.POST("/:id/leaders", middlewares.PermissionMiddleware("dept:manage"), deptController.AddLeader)
-		// deptRoutes.DELETE("/:id/leaders/:userId", middlewares.PermissionMiddleware("dept:manage"), deptController.RemoveLeader)
-		//添加负责人
-		deptRoutes.POST("/:id/leaders", deptController.AddLeader)
-		//移除负责人
-		deptRoutes.DELETE("/:id/leaders/:userId", deptController.RemoveLeader)
+		// 负责人管理（需要dept:manage权限，部门负责人只能管理本部门及下级部门）
+		deptRoutes.POST("/:id/leaders", middlewares.ResourcePermissionMiddleware("dept:manage", services.ScopeDepartment, "id"), deptController.AddLeader)
+		deptRoutes.DELETE("/:id/leaders/:userId", middlewares.ResourcePermissionMiddleware("dept:manage", services.ScopeDepartment, "id"), deptController.RemoveLeader)
 
 		// 获取部门成员列表（用于任务筛选）
-		deptRoutes.GET("/:id/members-for-filter", deptController.GetDepartmentMembersForFilter)
+		deptRoutes.GET("/:id/members-for-filter", middlewares.PermissionMiddleware("dept:read"), deptController.GetDepartmentMembersForFilter)
 
-		// 人员分配
-		// deptRoutes.POST("/:id/users", middlewares.PermissionMiddleware("dept:manage"), deptController.AssignUsers)
-		deptRoutes.POST("/:id/users", deptController.AssignUsers)
+		// 人员分配（需要dept:manage权限，部门负责人只能分配到本部门及下级部门）
+		deptRoutes.POST("/:id/users", middlewares.ResourcePermissionMiddleware("dept:manage", services.ScopeDepartment, "id"), deptController.AssignUsers)
 	}
 
 	// 任务管理路由
@@ -178,38 +201,100 @@ func SetupRoutes() *gin.Engine {
 	taskRoutes.Use(middlewares.AuthMiddleware())
 	{
 		// 任务 CRUD
-		taskRoutes.POST("", taskController.CreateTask)
+		taskRoutes.POST("", middlewares.PermissionMiddleware("task:create"), taskController.CreateTask)
 		//所有任务列表
-		taskRoutes.GET("", taskController.GetTaskList)
+		taskRoutes.GET("", middlewares.PermissionMiddleware("task:read"), taskController.GetTaskList)
 		// 我的任务列表（必须放在 /:id 之前，避免路径匹配冲突）
-		taskRoutes.GET("/my", taskController.GetMyTasks)
+		taskRoutes.GET("/my", middlewares.PermissionMiddleware("task:read"), taskController.GetMyTasks)
 		// 任务详情（包含最新版本的方案和计划）
-		taskRoutes.GET("/:id", detailController.GetTaskDetail)
-		// 更新任务
-		taskRoutes.PUT("/:id", taskController.UpdateTask)
-		//删除任务
-		taskRoutes.DELETE("/:id", taskController.DeleteTask)
+		taskRoutes.GET("/:id", middlewares.PermissionMiddleware("task:read"), detailController.GetTaskDetail)
+		// 更新任务（创建者、执行人可以更新，由服务层校验）
+		taskRoutes.PUT("/:id", middlewares.PermissionMiddleware("task:update"), taskController.UpdateTask)
+		// 删除任务（需要task:delete权限，只能删除自己创建或负责部门的任务）
+		taskRoutes.DELETE("/:id", middlewares.ResourcePermissionMiddleware("task:delete", services.ScopeTask, "id"), taskController.DeleteTask)
 
 		// 任务状态转换
-		taskRoutes.POST("/:id/transit", taskController.TransitStatus)
+		taskRoutes.POST("/:id/transit", middlewares.PermissionMiddleware("task:update"), taskController.TransitStatus)
 
-		// 任务分配
-		taskRoutes.POST("/:id/assign", taskController.AssignExecutor)
+		// 任务分配（需要task:assign权限，只能分配自己创建或负责部门的任务）
+		taskRoutes.POST("/:id/assign", middlewares.ResourcePermissionMiddleware("task:assign", services.ScopeTask, "id"), taskController.AssignExecutor)
 
 		// 任务专用访问 (Task Token)
 		taskRoutes.GET("/current", middlewares.TaskTokenMiddleware(), taskController.GetTaskInfo)
 
 		// 任务详情相关接口
 		// 获取任务的所有方案版本
-		taskRoutes.GET("/:id/solutions", detailController.GetTaskSolutions)
+		taskRoutes.GET("/:id/solutions", middlewares.PermissionMiddleware("task:read"), detailController.GetTaskSolutions)
+		// 对比两个方案版本（默认对比最近一次被驳回的版本）
+		taskRoutes.GET("/:id/solutions/compare", middlewares.PermissionMiddleware("task:read"), detailController.CompareSolutions)
 		// 获取任务的所有执行计划版本
-		taskRoutes.GET("/:id/execution-plans", detailController.GetTaskExecutionPlans)
+		taskRoutes.GET("/:id/execution-plans", middlewares.PermissionMiddleware("task:read"), detailController.GetTaskExecutionPlans)
+		// 对比两个执行计划版本（默认对比最近一次被驳回的版本）
+		taskRoutes.GET("/:id/execution-plans/compare", middlewares.PermissionMiddleware("task:read"), detailController.CompareExecutionPlans)
+		// 方案/执行计划的审核批注
+		taskRoutes.GET("/:id/annotations", middlewares.PermissionMiddleware("review:read"), annotationController.GetAnnotations)
+		taskRoutes.POST("/:id/annotations", middlewares.PermissionMiddleware("review:annotate"), annotationController.CreateAnnotation)
 		// 获取任务的审核历史
-		taskRoutes.GET("/:id/reviews", detailController.GetTaskReviewHistory)
+		taskRoutes.GET("/:id/reviews", middlewares.PermissionMiddleware("review:read"), detailController.GetTaskReviewHistory)
 		// 获取任务的变更日志
-		taskRoutes.GET("/:id/change-logs", detailController.GetTaskChangeLogs)
+		taskRoutes.GET("/:id/change-logs", middlewares.PermissionMiddleware("task:read"), detailController.GetTaskChangeLogs)
 		// 获取任务的时间轴
-		taskRoutes.GET("/:id/timeline", detailController.GetTaskTimeline)
+		taskRoutes.GET("/:id/timeline", middlewares.PermissionMiddleware("task:read"), detailController.GetTaskTimeline)
+	}
+
+	// 任务保存视图路由
+	viewController := controllers.NewTaskViewController()
+	viewRoutes := router.Group("/api/v1/task-views")
+	viewRoutes.Use(middlewares.AuthMiddleware())
+	viewRoutes.Use(middlewares.PermissionMiddleware("task:read"))
+	{
+		// 视图 CRUD
+		viewRoutes.POST("", viewController.CreateView)
+		viewRoutes.GET("", viewController.GetViewList)
+		viewRoutes.GET("/:id", viewController.GetView)
+		viewRoutes.PUT("/:id", viewController.UpdateView)
+		viewRoutes.DELETE("/:id", viewController.DeleteView)
+		// 执行视图（按当前用户可见范围查询任务）
+		viewRoutes.GET("/:id/tasks", viewController.RunView)
+	}
+
+	// 全文检索路由
+	searchController := controllers.NewSearchController()
+	searchRoutes := router.Group("/api/v1/search")
+	searchRoutes.Use(middlewares.AuthMiddleware())
+	searchRoutes.Use(middlewares.PermissionMiddleware("task:read"))
+	{
+		// 检索任务、方案、计划和评论
+		searchRoutes.GET("", searchController.Search)
+	}
+
+	// 状态转换审批路由
+	transitionRequestController := controllers.NewTransitionRequestController()
+	transitionRequestRoutes := router.Group("/api/v1/transition-requests")
+	transitionRequestRoutes.Use(middlewares.AuthMiddleware())
+	{
+		// 待我审批的申请
+		transitionRequestRoutes.GET("/pending", middlewares.PermissionMiddleware("task:read"), transitionRequestController.GetPendingRequests)
+		// 我提交的申请
+		transitionRequestRoutes.GET("/mine", middlewares.PermissionMiddleware("task:read"), transitionRequestController.GetMyRequests)
+		// 审批（通过/驳回，只有申请的审批人、其代理人或任务管理者可以审批）
+		transitionRequestRoutes.POST("/:id/decide", middlewares.ResourcePermissionMiddleware("review:decide", services.ScopeTransitionRequest, "id"), transitionRequestController.DecideRequest)
+		// 撤回
+		transitionRequestRoutes.POST("/:id/cancel", middlewares.PermissionMiddleware("task:update"), transitionRequestController.CancelRequest)
+	}
+
+	// SLA 路由
+	slaController := controllers.NewSLAController()
+	slaRoutes := router.Group("/api/v1/sla")
+	slaRoutes.Use(middlewares.AuthMiddleware())
+	slaRoutes.Use(middlewares.PermissionMiddleware("task:read"))
+	{
+		// 任务的 SLA 计时
+		slaRoutes.GET("/tasks/:id", slaController.GetTaskTimers)
+		// 违约事件
+		slaRoutes.GET("/breaches", slaController.GetBreaches)
+		// 达成率报表（按部门和执行人）
+		slaRoutes.GET("/report", slaController.GetComplianceReport)
 	}
 
 	// 任务流程路由
@@ -218,22 +303,23 @@ func SetupRoutes() *gin.Engine {
 	flowRoutes.Use(middlewares.AuthMiddleware())
 	{
 		//认领任务、接受任务、
-		flowRoutes.POST("/:id/accept", flowController.AcceptTask)
+		flowRoutes.POST("/:id/accept", middlewares.PermissionMiddleware("task:update"), flowController.AcceptTask)
 		//拒绝任务
-		flowRoutes.POST("/:id/reject", flowController.RejectTask)
+		flowRoutes.POST("/:id/reject", middlewares.PermissionMiddleware("task:update"), flowController.RejectTask)
 		//提交目标 (待用,目前目标和执行计划合并提交)
-		flowRoutes.POST("/:id/goals", flowController.SubmitGoals)
+		flowRoutes.POST("/:id/goals", middlewares.PermissionMiddleware("task:update"), flowController.SubmitGoals)
 		//发起审核(待用)
-		flowRoutes.POST("/:id/review", flowController.InitiateReview)
+		flowRoutes.POST("/:id/review", middlewares.PermissionMiddleware("review:initiate"), flowController.InitiateReview)
 		// 提交解决方案（第一步：方案审核）
-		flowRoutes.POST("/:id/solution", flowController.SubmitSolution)
+		flowRoutes.POST("/:id/solution", middlewares.PermissionMiddleware("task:update"), flowController.SubmitSolution)
 		// 提交执行计划+目标（第二步：计划审核）
-		flowRoutes.POST("/:id/execution-plan", flowController.SubmitExecutionPlanWithGoals)
+		flowRoutes.POST("/:id/execution-plan", middlewares.PermissionMiddleware("task:update"), flowController.SubmitExecutionPlanWithGoals)
 	}
 
 	// 任务状态查询路由
 	taskFlowQueryRoutes := router.Group("/api/v1/task-flow")
 	taskFlowQueryRoutes.Use(middlewares.AuthMiddleware())
+	taskFlowQueryRoutes.Use(middlewares.PermissionMiddleware("task:read"))
 	{
 		// 获取任务状态列表
 		taskFlowQueryRoutes.GET("/statuses", flowController.GetTaskStatuses)
@@ -246,29 +332,141 @@ func SetupRoutes() *gin.Engine {
 	reviewRoutes.Use(middlewares.AuthMiddleware())
 	{
 		// 获取审核会话详情
-		reviewRoutes.GET("/:sessionId", flowController.GetReviewSession)
+		reviewRoutes.GET("/:sessionId", middlewares.PermissionMiddleware("review:read"), flowController.GetReviewSession)
 		// 提交审核意见
-		reviewRoutes.POST("/:sessionId/opinion", flowController.SubmitReviewOpinion)
-		// 最终决策
-		reviewRoutes.POST("/:sessionId/finalize", flowController.FinalizeReview)
-		// 邀请陪审团成员
-		reviewRoutes.POST("/:sessionId/invite-jury", flowController.InviteJury)
+		reviewRoutes.POST("/:sessionId/opinion", middlewares.PermissionMiddleware("review:submit"), flowController.SubmitReviewOpinion)
+		// 最终决策（只有会话的审核决策人、其代理人或任务管理者可以决策）
+		reviewRoutes.POST("/:sessionId/finalize", middlewares.ResourcePermissionMiddleware("review:decide", services.ScopeReviewSession, "sessionId"), flowController.FinalizeReview)
+		// 邀请陪审团成员（会话的审核决策人或任务管理者）
+		reviewRoutes.POST("/:sessionId/invite-jury", middlewares.ResourcePermissionMiddleware("review:manage", services.ScopeReviewSession, "sessionId"), flowController.InviteJury)
 		// 移除陪审团成员
-		reviewRoutes.DELETE("/:sessionId/jury/:juryMemberId", flowController.RemoveJuryMember)
+		reviewRoutes.DELETE("/:sessionId/jury/:juryMemberId", middlewares.ResourcePermissionMiddleware("review:manage", services.ScopeReviewSession, "sessionId"), flowController.RemoveJuryMember)
+	}
+
+	// 审核批注路由
+	annotationRoutes := router.Group("/api/v1/annotations")
+	annotationRoutes.Use(middlewares.AuthMiddleware())
+	annotationRoutes.Use(middlewares.PermissionMiddleware("review:annotate"))
+	{
+		// 回复批注
+		annotationRoutes.POST("/:id/replies", annotationController.ReplyAnnotation)
+		// 解决/重新打开批注
+		annotationRoutes.POST("/:id/resolve", annotationController.ResolveAnnotation)
+		annotationRoutes.POST("/:id/unresolve", annotationController.UnresolveAnnotation)
+		// 删除批注
+		annotationRoutes.DELETE("/:id", annotationController.DeleteAnnotation)
+	}
+
+	// 审核委托路由
+	delegationRoutes := router.Group("/api/v1/delegations")
+	delegationRoutes.Use(middlewares.AuthMiddleware())
+	delegationRoutes.Use(middlewares.PermissionMiddleware("review:delegate"))
+	{
+		// 创建审核委托
+		delegationRoutes.POST("", delegationController.CreateDelegation)
+		// 获取我的审核委托
+		delegationRoutes.GET("", delegationController.GetDelegations)
+		// 撤销审核委托
+		delegationRoutes.POST("/:id/revoke", delegationController.RevokeDelegation)
+	}
+
+	// 登录会话路由
+	sessionRoutes := router.Group("/api/v1/sessions")
+	sessionRoutes.Use(middlewares.AuthMiddleware())
+	{
+		// 获取我的登录会话
+		sessionRoutes.GET("", sessionController.GetMySessions)
+		// 结束我的其他登录会话
+		sessionRoutes.POST("/revoke-others", sessionController.RevokeOtherSessions)
+		// 结束我的登录会话
+		sessionRoutes.DELETE("/:id", sessionController.RevokeMySession)
+	}
+
+	// 两步验证路由
+	twoFactorRoutes := router.Group("/api/v1/2fa")
+	twoFactorRoutes.Use(middlewares.AuthMiddleware())
+	{
+		// 获取两步验证状态
+		twoFactorRoutes.GET("", twoFactorController.GetStatus)
+		// 发起绑定（返回密钥和二维码）
+		twoFactorRoutes.POST("/enroll", twoFactorController.Enroll)
+		// 验证动态验证码后启用（返回恢复码）
+		twoFactorRoutes.POST("/enable", twoFactorController.Enable)
+		// 重新生成恢复码
+		twoFactorRoutes.POST("/recovery-codes", twoFactorController.RegenerateRecoveryCodes)
+		// 解除两步验证
+		twoFactorRoutes.POST("/disable", twoFactorController.Disable)
 	}
 
 	// 管理员路由（需要permission:manage权限）
+	workflowController := controllers.NewWorkflowController()
+	jobController := controllers.NewJobController()
+	reviewConfigController := controllers.NewReviewConfigController()
 	adminRoutes := router.Group("/api/v1/admin")
 	adminRoutes.Use(middlewares.AuthMiddleware())
 	adminRoutes.Use(middlewares.PermissionMiddleware("permission:manage"))
 	{
 		adminRoutes.GET("/roles", adminController.GetRoleList)
 		adminRoutes.GET("/permissions", adminController.GetPermissionList)
+
+		// 流程设计：任务类型
+		adminRoutes.GET("/workflow/types", workflowController.GetTaskTypeList)
+		adminRoutes.POST("/workflow/types", workflowController.CreateTaskType)
+		adminRoutes.PUT("/workflow/types/:code", workflowController.UpdateTaskType)
+		adminRoutes.DELETE("/workflow/types/:code", workflowController.DeleteTaskType)
+		// 流程设计：状态和转换规则
+		adminRoutes.GET("/workflow/types/:code/workflow", workflowController.GetWorkflow)
+		adminRoutes.PUT("/workflow/types/:code/workflow", workflowController.SaveWorkflow)
+		adminRoutes.POST("/workflow/types/:code/workflow/validate", workflowController.ValidateWorkflow)
+		// 流程设计：可配置的转换守卫和后置动作
+		adminRoutes.GET("/workflow/hooks", workflowController.GetTransitionHooks)
+
+		// SLA 策略
+		adminRoutes.GET("/sla/policies", slaController.GetPolicyList)
+		adminRoutes.POST("/sla/policies", slaController.CreatePolicy)
+		adminRoutes.PUT("/sla/policies/:id", slaController.UpdatePolicy)
+		adminRoutes.DELETE("/sla/policies/:id", slaController.DeletePolicy)
+		// 工作日历
+		adminRoutes.GET("/sla/calendars", slaController.GetCalendarList)
+		adminRoutes.POST("/sla/calendars", slaController.CreateCalendar)
+		adminRoutes.PUT("/sla/calendars/:id", slaController.UpdateCalendar)
+		adminRoutes.DELETE("/sla/calendars/:id", slaController.DeleteCalendar)
+
+		// 定时任务
+		adminRoutes.GET("/jobs", jobController.GetJobList)
+		adminRoutes.GET("/jobs/:name/runs", jobController.GetJobRuns)
+		adminRoutes.POST("/jobs/:name/run", jobController.TriggerJob)
+
+		// 审核配置：角色投票权重
+		adminRoutes.GET("/review/role-weights", reviewConfigController.GetRoleWeights)
+		adminRoutes.PUT("/review/role-weights", reviewConfigController.SaveRoleWeights)
+		// 审核配置：评分标准
+		adminRoutes.GET("/review/rubrics", reviewConfigController.GetRubricList)
+		adminRoutes.POST("/review/rubrics", reviewConfigController.CreateRubric)
+		adminRoutes.PUT("/review/rubrics/:id", reviewConfigController.UpdateRubric)
+		adminRoutes.DELETE("/review/rubrics/:id", reviewConfigController.DeleteRubric)
+		// 审核配置：审批链模板
+		adminRoutes.GET("/review/approval-chains", reviewConfigController.GetApprovalChainList)
+		adminRoutes.POST("/review/approval-chains", reviewConfigController.CreateApprovalChain)
+		adminRoutes.PUT("/review/approval-chains/:id", reviewConfigController.UpdateApprovalChain)
+		adminRoutes.DELETE("/review/approval-chains/:id", reviewConfigController.DeleteApprovalChain)
+
+		// 登录会话管理
+		adminRoutes.GET("/users/:id/sessions", sessionController.GetUserSessions)
+		adminRoutes.DELETE("/users/:id/sessions", sessionController.RevokeUserSessions)
+		adminRoutes.DELETE("/sessions/:id", sessionController.AdminRevokeSession)
+		// 重置用户的两步验证
+		adminRoutes.DELETE("/users/:id/2fa", twoFactorController.AdminReset)
+
+		// 登录安全：解除登录锁定和审计日志
+		adminRoutes.POST("/security/unlock-login", securityController.UnlockLogin)
+		adminRoutes.GET("/audit-logs", securityController.GetAuditLogs)
 	}
 
 	// 文件上传路由
 	uploadRoutes := router.Group("/api/v1/upload")
 	uploadRoutes.Use(middlewares.AuthMiddleware())
+	uploadRoutes.Use(middlewares.RateLimitMiddleware("upload", security.UploadRateLimitPerMinute, time.Minute))
 	{
 		// 通用上传（根据文件类型自动选择驱动）
 		uploadRoutes.POST("", uploadController.Upload)
@@ -291,3 +489,14 @@ func SetupRoutes() *gin.Engine {
 
 	return router
 }
+
+// newRouter 创建路由引擎，只信任配置的反向代理转发的客户端 IP
+// 未配置时不信任任何代理，X-Forwarded-For 等请求头无法伪造客户端 IP 绕过按 IP 的登录锁定和接口限流
+func newRouter(trustedProxies []string) *gin.Engine {
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		utils.Logger.Errorf("受信任代理配置无效，已不信任任何代理: %v", err)
+		router.SetTrustedProxies(nil)
+	}
+	return router
+}
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"RHPRo-Task/middlewares"
+	"RHPRo-Task/ratelimit"
+	"RHPRo-Task/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// requestFrom 从指定地址发送请求，forwardedFor 不为空时附带 X-Forwarded-For
+func requestFrom(router *gin.Engine, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNewRouter_IgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if utils.Logger == nil {
+		utils.InitLogger()
+	}
+	defer ratelimit.GetStore().Delete("rate_limit:spoof_test:ip:203.0.113.9")
+
+	router := newRouter(nil)
+	router.GET("/ping", middlewares.RateLimitMiddleware("spoof_test", 1, time.Minute), func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+
+	// 未配置受信任代理时，伪造的 X-Forwarded-For 不影响客户端 IP，也不能绕过按 IP 的限流
+	w := requestFrom(router, "203.0.113.9:40000", "198.51.100.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "203.0.113.9", w.Body.String())
+	w = requestFrom(router, "203.0.113.9:40001", "198.51.100.2")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestNewRouter_TrustedProxy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newRouter([]string{"10.0.0.0/8"})
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+
+	// 来自受信任代理的请求采用转发的客户端 IP，其他来源忽略该请求头
+	assert.Equal(t, "198.51.100.1", requestFrom(router, "10.1.2.3:40000", "198.51.100.1").Body.String())
+	assert.Equal(t, "203.0.113.9", requestFrom(router, "203.0.113.9:40000", "198.51.100.1").Body.String())
+}
@@ -0,0 +1,141 @@
+package jobs
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/scheduler"
+	"RHPRo-Task/services"
+	"context"
+	"fmt"
+	"time"
+)
+
+// 内置定时任务名称
+const (
+	JobDeadlineCheck  = "deadline_check"        // 方案/计划提交截止检查
+	JobSLACheck       = "sla_check"             // SLA 违约检查
+	JobReviewDeadline = "review_deadline_check" // 陪审团响应截止检查
+	JobRunCleanup     = "job_run_cleanup"       // 清理过期的执行记录
+	JobTokenCleanup   = "token_cleanup"         // 清理过期的刷新令牌、吊销记录和登录会话
+	JobLdapSync       = "ldap_sync"             // LDAP 目录同步
+)
+
+// RegisterJobs 注册所有内置定时任务（间隔配置为 0 的任务不注册）
+func RegisterJobs() error {
+	cfg := config.GetConfig()
+	s := scheduler.GetScheduler()
+
+	if minutes := cfg.Task.DeadlineCheckIntervalMinutes; minutes > 0 {
+		if err := s.Register(scheduler.Job{
+			Name:        JobDeadlineCheck,
+			Spec:        fmt.Sprintf("@every %dm", minutes),
+			Description: "思路方案/执行计划提交截止检查：到期前提醒，超期升级并按配置自动转换状态",
+			Run:         runDeadlineCheck,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if minutes := cfg.Task.SLACheckIntervalMinutes; minutes > 0 {
+		if err := s.Register(scheduler.Job{
+			Name:        JobSLACheck,
+			Spec:        fmt.Sprintf("@every %dm", minutes),
+			Description: "SLA 违约检查：任务在状态中的停留时间超过策略限定时记录违约并通知",
+			Run:         runSLACheck,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if minutes := cfg.Task.JuryDeadlineCheckIntervalMinutes; minutes > 0 {
+		if err := s.Register(scheduler.Job{
+			Name:        JobReviewDeadline,
+			Spec:        fmt.Sprintf("@every %dm", minutes),
+			Description: "陪审团响应截止检查：截止前提醒未响应成员，截止后按会话策略视为弃权、升级到部门负责人或由创建人决策",
+			Run:         runReviewDeadlineCheck,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if days := cfg.Scheduler.JobRunRetentionDays; days > 0 {
+		if err := s.Register(scheduler.Job{
+			Name:        JobRunCleanup,
+			Spec:        "0 3 * * *",
+			Description: fmt.Sprintf("清理 %d 天前的定时任务执行记录", days),
+			Run: func(ctx context.Context) (string, error) {
+				deleted, err := (&services.JobService{}).CleanupRuns(ctx, time.Now().AddDate(0, 0, -days))
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("删除执行记录 %d 条", deleted), nil
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Register(scheduler.Job{
+		Name:        JobTokenCleanup,
+		Spec:        "30 3 * * *",
+		Description: "清理已过期的刷新令牌、访问令牌吊销记录和登录会话",
+		Run: func(ctx context.Context) (string, error) {
+			deleted, err := (&services.AuthTokenService{}).CleanupExpiredTokens(ctx, time.Now())
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("删除令牌记录 %d 条", deleted), nil
+		},
+	}); err != nil {
+		return err
+	}
+
+	if minutes := cfg.LDAP.SyncIntervalMinutes; cfg.LDAP.Enabled && minutes > 0 {
+		if err := s.Register(scheduler.Job{
+			Name:        JobLdapSync,
+			Spec:        fmt.Sprintf("@every %dm", minutes),
+			Description: "LDAP 目录同步：OU 映射为部门、上级映射为部门负责人，禁用已从目录移除的用户",
+			Run:         runLdapSync,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runDeadlineCheck(ctx context.Context) (string, error) {
+	result, err := (&services.DeadlineService{}).ProcessDeadlines(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("提醒 %d，升级 %d，自动转换 %d",
+		result.Reminded, result.Escalated, result.AutoTransitioned), nil
+}
+
+func runReviewDeadlineCheck(ctx context.Context) (string, error) {
+	result, err := (&services.ReviewDeadlineService{}).ProcessReviewDeadlines(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("提醒 %d，截止处理 %d，自动结束 %d",
+		result.Reminded, result.Expired, result.AutoClosed), nil
+}
+
+func runSLACheck(ctx context.Context) (string, error) {
+	created, err := (&services.SLAService{}).ProcessSLABreaches(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("新增违约 %d", created), nil
+}
+
+func runLdapSync(ctx context.Context) (string, error) {
+	result, err := (&services.LdapService{}).Sync(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("部门新增 %d、更新 %d；用户新增 %d、更新 %d、关联 %d、禁用 %d、跳过 %d；负责人新增 %d、移除 %d",
+		result.DepartmentsCreated, result.DepartmentsUpdated,
+		result.UsersCreated, result.UsersUpdated, result.UsersLinked, result.UsersDisabled, result.UsersSkipped,
+		result.LeadersAdded, result.LeadersRemoved), nil
+}
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// lockKeyPrefix 分布式锁的键前缀
+const lockKeyPrefix = "scheduler:lock:"
+
+// Locker 任务锁，保证同一任务同一时间只在一个实例上执行
+type Locker interface {
+	// TryLock 尝试获取锁，成功时返回释放函数；ttl 为锁的最长持有时间（实例异常退出时自动过期）
+	TryLock(key string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// localLocker 进程内锁（未配置 Redis 时使用，只能防止本实例重复执行）
+// 与 Redis 锁一样按 ttl 过期，不释放的锁（如计划时间锁）过期后被清理
+type localLocker struct {
+	mu   sync.Mutex
+	held map[string]time.Time // 锁键 -> 过期时间
+	now  func() time.Time
+}
+
+// NewLocalLocker 创建进程内锁
+func NewLocalLocker() Locker {
+	return &localLocker{held: make(map[string]time.Time), now: time.Now}
+}
+
+func (l *localLocker) TryLock(key string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for k, expiresAt := range l.held {
+		if !now.Before(expiresAt) {
+			delete(l.held, k)
+		}
+	}
+	if _, ok := l.held[key]; ok {
+		return nil, false, nil
+	}
+
+	expiresAt := now.Add(ttl)
+	l.held[key] = expiresAt
+	return func() {
+		l.mu.Lock()
+		// 只释放自己持有的锁（已过期并被重新获取时不删除）
+		if l.held[key].Equal(expiresAt) {
+			delete(l.held, key)
+		}
+		l.mu.Unlock()
+	}, true, nil
+}
+
+// releaseScript 只释放自己持有的锁（值与令牌一致时才删除）
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// redisLocker 基于 Redis SET NX 的分布式锁（多副本部署时保证只有一个实例执行）
+type redisLocker struct {
+	client *redis.Client
+}
+
+// NewRedisLocker 创建 Redis 分布式锁
+func NewRedisLocker(client *redis.Client) Locker {
+	return &redisLocker{client: client}
+}
+
+func (l *redisLocker) TryLock(key string, ttl time.Duration) (func(), bool, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, false, err
+	}
+	ok, err := l.client.SetNX(context.Background(), key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return func() {
+		releaseScript.Run(context.Background(), l.client, []string{key}, token)
+	}, true, nil
+}
+
+// newLockToken 生成随机锁令牌
+func newLockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
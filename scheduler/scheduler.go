@@ -0,0 +1,334 @@
+package scheduler
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// defaultJobTimeout 任务默认超时时间
+const defaultJobTimeout = 10 * time.Minute
+
+// lockMargin 锁有效期在超时时间基础上的余量
+const lockMargin = time.Minute
+
+// maxTickLookback 推算本次计划执行时间时向前查找的最长时间
+const maxTickLookback = 400 * 24 * time.Hour
+
+var (
+	// ErrJobNotFound 任务不存在
+	ErrJobNotFound = errors.New("定时任务不存在")
+	// ErrJobRunning 任务正在执行（本实例或其他实例持有锁）
+	ErrJobRunning = errors.New("定时任务正在执行中，请稍后再试")
+)
+
+// specParser cron 表达式解析器：支持 5 段（分 时 日 月 周）、可选的秒字段以及 @every 10m、@daily 等描述符
+var specParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// JobFunc 任务执行函数，返回执行结果摘要
+type JobFunc func(ctx context.Context) (string, error)
+
+// Job 定时任务定义
+type Job struct {
+	// 任务名称（唯一）
+	Name string
+	// cron 表达式
+	Spec string
+	// 描述
+	Description string
+	// 超时时间（默认 10 分钟），同时决定分布式锁的有效期
+	Timeout time.Duration
+	// 执行函数
+	Run JobFunc
+}
+
+// JobInfo 已注册任务的信息
+type JobInfo struct {
+	Name        string
+	Spec        string
+	Description string
+	Timeout     time.Duration
+	// 下次计划执行时间
+	NextRun time.Time
+}
+
+type jobEntry struct {
+	job      Job
+	schedule cron.Schedule
+}
+
+// Scheduler 定时任务调度器
+// 每次执行前获取任务锁（配置 Redis 时为分布式锁），执行过程和结果写入执行记录
+type Scheduler struct {
+	cron     *cron.Cron
+	locker   Locker
+	store    RunStore
+	instance string
+
+	mu   sync.RWMutex
+	jobs map[string]*jobEntry
+	wg   sync.WaitGroup
+}
+
+var (
+	globalScheduler *Scheduler
+	schedulerOnce   sync.Once
+)
+
+// GetScheduler 获取全局调度器（配置了 Redis 时使用分布式锁，否则使用进程内锁）
+func GetScheduler() *Scheduler {
+	schedulerOnce.Do(func() {
+		var locker Locker
+		if database.RedisClient != nil {
+			locker = NewRedisLocker(database.RedisClient)
+		} else {
+			locker = NewLocalLocker()
+		}
+		globalScheduler = NewScheduler(locker, NewDBRunStore())
+	})
+	return globalScheduler
+}
+
+// NewScheduler 创建调度器
+func NewScheduler(locker Locker, store RunStore) *Scheduler {
+	instance, _ := os.Hostname()
+	return &Scheduler{
+		cron:     cron.New(cron.WithParser(specParser)),
+		locker:   locker,
+		store:    store,
+		instance: fmt.Sprintf("%s-%d", instance, os.Getpid()),
+		jobs:     make(map[string]*jobEntry),
+	}
+}
+
+// Register 注册任务
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("任务名称和执行函数不能为空")
+	}
+	schedule, err := specParser.Parse(job.Spec)
+	if err != nil {
+		return fmt.Errorf("任务 %s 的 cron 表达式无效: %v", job.Name, err)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = defaultJobTimeout
+	}
+	// @every 按固定间隔对齐到整点时刻，各副本的计划执行时间一致，才能按计划时间去重
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		schedule = alignedSchedule{interval: every.Delay}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("任务 %s 已注册", job.Name)
+	}
+	s.jobs[job.Name] = &jobEntry{job: job, schedule: schedule}
+
+	name := job.Name
+	s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.runScheduled(name)
+	}))
+	return nil
+}
+
+// Start 开始按计划执行任务
+func (s *Scheduler) Start() {
+	s.cron.Start()
+	utils.Logger.Infof("Scheduler started with %d jobs", len(s.Jobs()))
+}
+
+// Stop 停止调度并等待执行中的任务结束
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+	s.wg.Wait()
+}
+
+// Jobs 返回已注册的任务（按名称排序）
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	jobs := make([]JobInfo, 0, len(s.jobs))
+	for _, entry := range s.jobs {
+		jobs = append(jobs, JobInfo{
+			Name:        entry.job.Name,
+			Spec:        entry.job.Spec,
+			Description: entry.job.Description,
+			Timeout:     entry.job.Timeout,
+			NextRun:     entry.schedule.Next(now),
+		})
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// HasJob 判断任务是否已注册
+func (s *Scheduler) HasJob(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.jobs[name]
+	return ok
+}
+
+// Trigger 手动触发任务，获取锁并创建执行记录后在后台执行，返回执行记录
+func (s *Scheduler) Trigger(name string, userID uint) (*models.JobRun, error) {
+	run, execute, err := s.begin(name, models.JobTriggerManual, &userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		execute()
+	}()
+	return &snapshot, nil
+}
+
+// runScheduled 按计划执行任务，其他实例正在执行或已执行过本次计划时跳过
+func (s *Scheduler) runScheduled(name string) {
+	s.mu.RLock()
+	entry, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return
+	}
+	s.runScheduledAt(name, previousActivation(entry.schedule, time.Now()))
+}
+
+// runScheduledAt 执行指定计划时间的任务
+// 计划时间锁在执行结束后不释放，直到下一次计划时间之后才过期，稍晚触发的其他副本不会重复执行同一次计划
+func (s *Scheduler) runScheduledAt(name string, tick time.Time) {
+	_, execute, err := s.begin(name, models.JobTriggerSchedule, nil, &tick)
+	if err != nil {
+		if !errors.Is(err, ErrJobRunning) {
+			utils.Logger.Warnf("定时任务 %s 启动失败: %v", name, err)
+		}
+		return
+	}
+	s.wg.Add(1)
+	defer s.wg.Done()
+	execute()
+}
+
+// begin 获取任务锁并创建执行记录，返回执行函数（执行完成后记录结果并释放锁）
+// tick 不为空时为计划执行，还需获取该计划时间的锁
+func (s *Scheduler) begin(name, trigger string, userID *uint, tick *time.Time) (*models.JobRun, func(), error) {
+	s.mu.RLock()
+	entry, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrJobNotFound
+	}
+	job := entry.job
+
+	if tick != nil {
+		// 计划时间锁不释放，有效期覆盖到下一次计划时间之后
+		period := entry.schedule.Next(*tick).Sub(*tick)
+		_, locked, err := s.locker.TryLock(tickLockKey(name, *tick), period+lockMargin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取任务锁失败: %v", err)
+		}
+		if !locked {
+			return nil, nil, ErrJobRunning
+		}
+	}
+
+	release, locked, err := s.locker.TryLock(lockKeyPrefix+name, job.Timeout+lockMargin)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取任务锁失败: %v", err)
+	}
+	if !locked {
+		return nil, nil, ErrJobRunning
+	}
+
+	run := &models.JobRun{
+		JobName:     name,
+		Trigger:     trigger,
+		TriggeredBy: userID,
+		Instance:    s.instance,
+		Status:      models.JobRunStatusRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := s.store.Create(run); err != nil {
+		release()
+		return nil, nil, fmt.Errorf("创建执行记录失败: %v", err)
+	}
+
+	execute := func() {
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), job.Timeout)
+		defer cancel()
+		output, err := invokeJob(ctx, job.Run)
+
+		finishedAt := time.Now()
+		run.FinishedAt = &finishedAt
+		run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+		run.Output = output
+		if err != nil {
+			run.Status = models.JobRunStatusFailed
+			run.Error = err.Error()
+			utils.Logger.Errorf("定时任务 %s 执行失败: %v", name, err)
+		} else {
+			run.Status = models.JobRunStatusSuccess
+		}
+		if err := s.store.Finish(run); err != nil {
+			utils.Logger.Warnf("记录定时任务 %s 执行结果失败: %v", name, err)
+		}
+	}
+	return run, execute, nil
+}
+
+// tickLockKey 计划时间锁的键：任务名称加计划执行时间
+func tickLockKey(name string, tick time.Time) string {
+	return fmt.Sprintf("%s%s:%d", lockKeyPrefix, name, tick.Unix())
+}
+
+// previousActivation 推算不晚于 now 的最近一次计划执行时间
+// cron 触发存在毫秒级延迟，不能直接用当前时间作为计划时间
+func previousActivation(schedule cron.Schedule, now time.Time) time.Time {
+	for lookback := time.Second; lookback <= maxTickLookback; lookback *= 2 {
+		tick := schedule.Next(now.Add(-lookback))
+		if tick.After(now) {
+			continue
+		}
+		for next := schedule.Next(tick); !next.After(now); next = schedule.Next(tick) {
+			tick = next
+		}
+		return tick
+	}
+	return now.Truncate(time.Second)
+}
+
+// alignedSchedule 按固定间隔对齐的计划（执行时间为间隔的整数倍，与进程启动时间无关）
+type alignedSchedule struct {
+	interval time.Duration
+}
+
+// Next 返回 t 之后的下一个对齐时间
+func (a alignedSchedule) Next(t time.Time) time.Time {
+	return t.Truncate(a.interval).Add(a.interval)
+}
+
+// invokeJob 执行任务函数，panic 视为执行失败
+func invokeJob(ctx context.Context, fn JobFunc) (output string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,207 @@
+package scheduler
+
+import (
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryRunStore 内存执行记录存储（测试用）
+type memoryRunStore struct {
+	mu   sync.Mutex
+	runs []models.JobRun
+}
+
+func (s *memoryRunStore) Create(run *models.JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run.ID = uint(len(s.runs) + 1)
+	s.runs = append(s.runs, *run)
+	return nil
+}
+
+func (s *memoryRunStore) Finish(run *models.JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[run.ID-1] = *run
+	return nil
+}
+
+func (s *memoryRunStore) get(id uint) models.JobRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs[id-1]
+}
+
+func newTestScheduler() (*Scheduler, *memoryRunStore) {
+	utils.Logger = logrus.New()
+	utils.Logger.SetOutput(io.Discard)
+	store := &memoryRunStore{}
+	return NewScheduler(NewLocalLocker(), store), store
+}
+
+func TestRegister_Validation(t *testing.T) {
+	s, _ := newTestScheduler()
+	noop := func(ctx context.Context) (string, error) { return "", nil }
+
+	assert.Error(t, s.Register(Job{Name: "bad_spec", Spec: "every ten minutes", Run: noop}))
+	assert.Error(t, s.Register(Job{Name: "no_func", Spec: "@every 1m"}))
+	assert.NoError(t, s.Register(Job{Name: "cleanup", Spec: "0 3 * * *", Run: noop}))
+	assert.Error(t, s.Register(Job{Name: "cleanup", Spec: "@daily", Run: noop}))
+
+	jobs := s.Jobs()
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, defaultJobTimeout, jobs[0].Timeout)
+	assert.Equal(t, 3, jobs[0].NextRun.Hour())
+	assert.True(t, s.HasJob("cleanup"))
+	assert.False(t, s.HasJob("unknown"))
+}
+
+func TestTrigger_RecordsResult(t *testing.T) {
+	s, store := newTestScheduler()
+	assert.NoError(t, s.Register(Job{Name: "ok", Spec: "@every 1h", Run: func(ctx context.Context) (string, error) {
+		return "处理 3 条", nil
+	}}))
+	assert.NoError(t, s.Register(Job{Name: "fail", Spec: "@every 1h", Run: func(ctx context.Context) (string, error) {
+		return "", errors.New("数据库不可用")
+	}}))
+	assert.NoError(t, s.Register(Job{Name: "panic", Spec: "@every 1h", Run: func(ctx context.Context) (string, error) {
+		panic("boom")
+	}}))
+
+	okRun, err := s.Trigger("ok", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobRunStatusRunning, okRun.Status)
+	assert.Equal(t, models.JobTriggerManual, okRun.Trigger)
+	failRun, err := s.Trigger("fail", 1)
+	assert.NoError(t, err)
+	panicRun, err := s.Trigger("panic", 1)
+	assert.NoError(t, err)
+	s.Stop()
+
+	run := store.get(okRun.ID)
+	assert.Equal(t, models.JobRunStatusSuccess, run.Status)
+	assert.Equal(t, "处理 3 条", run.Output)
+	assert.NotNil(t, run.FinishedAt)
+
+	run = store.get(failRun.ID)
+	assert.Equal(t, models.JobRunStatusFailed, run.Status)
+	assert.Equal(t, "数据库不可用", run.Error)
+
+	run = store.get(panicRun.ID)
+	assert.Equal(t, models.JobRunStatusFailed, run.Status)
+	assert.Contains(t, run.Error, "boom")
+
+	_, err = s.Trigger("unknown", 1)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestTrigger_SkipsWhileRunning(t *testing.T) {
+	s, _ := newTestScheduler()
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var once sync.Once
+	assert.NoError(t, s.Register(Job{Name: "slow", Spec: "@every 1h", Run: func(ctx context.Context) (string, error) {
+		once.Do(func() { close(started) })
+		<-finish
+		return "", nil
+	}}))
+
+	_, err := s.Trigger("slow", 1)
+	assert.NoError(t, err)
+	<-started
+
+	_, err = s.Trigger("slow", 1)
+	assert.ErrorIs(t, err, ErrJobRunning)
+
+	close(finish)
+	s.Stop()
+
+	// 上一次执行结束后锁已释放
+	_, err = s.Trigger("slow", 1)
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestLocalLocker(t *testing.T) {
+	locker := NewLocalLocker()
+	release, ok, err := locker.TryLock("job", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok, _ = locker.TryLock("job", time.Minute)
+	assert.False(t, ok)
+	_, ok, _ = locker.TryLock("other", time.Minute)
+	assert.True(t, ok)
+
+	release()
+	_, ok, _ = locker.TryLock("job", time.Minute)
+	assert.True(t, ok)
+}
+
+func TestLocalLocker_Expiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	locker := &localLocker{held: make(map[string]time.Time), now: func() time.Time { return now }}
+
+	release, ok, _ := locker.TryLock("job", time.Minute)
+	assert.True(t, ok)
+	// 不释放的计划时间锁到期后被清理，不会一直占用
+	_, ok, _ = locker.TryLock("tick:1", time.Minute)
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok, _ = locker.TryLock("job", 2*time.Minute)
+	assert.True(t, ok, "锁过期后可以重新获取")
+	assert.NotContains(t, locker.held, "tick:1")
+
+	// 过期前持有者的释放函数不能释放新持有者的锁
+	release()
+	_, ok, _ = locker.TryLock("job", time.Minute)
+	assert.False(t, ok)
+}
+
+func TestRunScheduledAt_OncePerTick(t *testing.T) {
+	s, store := newTestScheduler()
+	assert.NoError(t, s.Register(Job{Name: "tick", Spec: "@every 10m", Run: func(ctx context.Context) (string, error) {
+		return "", nil
+	}}))
+
+	tick := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	s.runScheduledAt("tick", tick)
+	// 其他副本稍晚触发同一次计划时，上一次执行已结束也不能重复执行
+	s.runScheduledAt("tick", tick)
+	assert.Len(t, store.runs, 1)
+
+	s.runScheduledAt("tick", tick.Add(10*time.Minute))
+	assert.Len(t, store.runs, 2)
+	assert.Equal(t, models.JobTriggerSchedule, store.get(2).Trigger)
+
+	// 计划执行不影响随后的手动触发
+	_, err := s.Trigger("tick", 1)
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestPreviousActivation(t *testing.T) {
+	every, err := specParser.Parse("@every 10m")
+	assert.NoError(t, err)
+	aligned := alignedSchedule{interval: every.(cron.ConstantDelaySchedule).Delay}
+	tick := time.Date(2026, 1, 1, 8, 10, 0, 0, time.UTC)
+	assert.Equal(t, tick, previousActivation(aligned, tick.Add(30*time.Millisecond)))
+	assert.Equal(t, tick, previousActivation(aligned, tick.Add(3*time.Second)))
+	assert.Equal(t, tick.Add(10*time.Minute), aligned.Next(tick))
+
+	daily, err := specParser.Parse("0 3 * * *")
+	assert.NoError(t, err)
+	now := time.Date(2026, 1, 2, 3, 0, 0, 500, time.Local)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 0, 0, 0, time.Local), previousActivation(daily, now))
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/models"
+)
+
+// RunStore 任务执行记录存储
+type RunStore interface {
+	// Create 记录开始执行
+	Create(run *models.JobRun) error
+	// Finish 记录执行结果
+	Finish(run *models.JobRun) error
+}
+
+// dbRunStore 将执行记录写入 job_runs 表
+type dbRunStore struct{}
+
+// NewDBRunStore 创建数据库执行记录存储
+func NewDBRunStore() RunStore {
+	return &dbRunStore{}
+}
+
+func (s *dbRunStore) Create(run *models.JobRun) error {
+	return database.DB.Create(run).Error
+}
+
+func (s *dbRunStore) Finish(run *models.JobRun) error {
+	return database.DB.Model(&models.JobRun{}).Where("id = ?", run.ID).
+		Updates(map[string]interface{}{
+			"status":      run.Status,
+			"finished_at": run.FinishedAt,
+			"duration_ms": run.DurationMs,
+			"output":      run.Output,
+			"error":       run.Error,
+		}).Error
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// approverTypes 支持的审批阶段审批人类型
+var approverTypes = map[string]bool{
+	models.ApproverTypeExecutorLeader:   true,
+	models.ApproverTypeCreatorLeader:    true,
+	models.ApproverTypeTaskLeader:       true,
+	models.ApproverTypeDepartmentLeader: true,
+	models.ApproverTypeCreator:          true,
+	models.ApproverTypeUser:             true,
+}
+
+// approvalChainTemplateMatches 审批链模板是否适用于任务的审核
+// 任务类型/审核类型需一致；优先级和跨部门两个触发条件满足其一即可，均未设置时匹配该类型的全部任务
+func approvalChainTemplateMatches(template *models.ApprovalChainTemplate, task *models.Task, reviewType string) bool {
+	if !template.IsActive || template.ReviewType != rubricReviewType(reviewType) {
+		return false
+	}
+	if template.TaskTypeCode != "" && template.TaskTypeCode != task.TaskTypeCode {
+		return false
+	}
+	if template.MinPriority == nil && !template.MatchCrossDepartment {
+		return true
+	}
+	if template.MinPriority != nil && task.Priority >= *template.MinPriority {
+		return true
+	}
+	return template.MatchCrossDepartment && task.IsCrossDepartment
+}
+
+// selectApprovalChainTemplate 从候选模板中选择适用于任务的模板（匹配顺序值大的优先，相同时取后创建的）
+func selectApprovalChainTemplate(templates []models.ApprovalChainTemplate, task *models.Task, reviewType string) *models.ApprovalChainTemplate {
+	var selected *models.ApprovalChainTemplate
+	for i := range templates {
+		template := &templates[i]
+		if len(template.Stages) == 0 || !approvalChainTemplateMatches(template, task, reviewType) {
+			continue
+		}
+		if selected == nil || template.SortOrder > selected.SortOrder ||
+			(template.SortOrder == selected.SortOrder && template.ID > selected.ID) {
+			selected = template
+		}
+	}
+	return selected
+}
+
+// matchApprovalChain 查询任务提交审核时适用的审批链模板（含按序号排序的阶段），没有适用的模板时返回 nil
+func matchApprovalChain(db *gorm.DB, task *models.Task, reviewType string) *models.ApprovalChainTemplate {
+	chainType := rubricReviewType(reviewType)
+	if chainType == "" {
+		return nil
+	}
+	var templates []models.ApprovalChainTemplate
+	db.Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("stage_no ASC")
+	}).Where("review_type = ? AND is_active = ?", chainType, true).Find(&templates)
+	return selectApprovalChainTemplate(templates, task, reviewType)
+}
+
+// departmentLeaderIDs 获取部门负责人用户ID（按用户ID排序）
+func departmentLeaderIDs(db *gorm.DB, departmentID *uint) []uint {
+	leaders := departmentLeaderSet(db, departmentID)
+	ids := make([]uint, 0, len(leaders))
+	for id := range leaders {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// userDepartmentID 获取用户所在部门ID
+func userDepartmentID(db *gorm.DB, userID uint) *uint {
+	var user models.User
+	if err := db.Select("id, department_id").First(&user, userID).Error; err != nil {
+		return nil
+	}
+	return user.DepartmentID
+}
+
+// resolveStageApprovers 确定审批阶段的审批人（部门未设置负责人等情况下可能为空）
+func resolveStageApprovers(db *gorm.DB, stage *models.ApprovalChainStage, task *models.Task) []uint {
+	switch stage.ApproverType {
+	case models.ApproverTypeExecutorLeader:
+		if task.ExecutorID == nil {
+			return nil
+		}
+		return departmentLeaderIDs(db, userDepartmentID(db, *task.ExecutorID))
+	case models.ApproverTypeCreatorLeader:
+		return departmentLeaderIDs(db, userDepartmentID(db, task.CreatorID))
+	case models.ApproverTypeTaskLeader:
+		return departmentLeaderIDs(db, task.DepartmentID)
+	case models.ApproverTypeDepartmentLeader:
+		return departmentLeaderIDs(db, stage.ApproverDepartmentID)
+	case models.ApproverTypeCreator:
+		return []uint{task.CreatorID}
+	case models.ApproverTypeUser:
+		if stage.ApproverUserID != nil {
+			return []uint{*stage.ApproverUserID}
+		}
+	}
+	return nil
+}
+
+// openChainStage 从 fromStageNo 起创建第一个能确定审批人的阶段会话（没有审批人的阶段跳过），之后的阶段都没有审批人时返回 nil
+func openChainStage(tx *gorm.DB, template *models.ApprovalChainTemplate, task *models.Task, base *models.ReviewSession, fromStageNo int, prevSessionID *uint, now time.Time) (*models.ReviewSession, error) {
+	for i := range template.Stages {
+		stage := &template.Stages[i]
+		if stage.StageNo < fromStageNo {
+			continue
+		}
+		approvers := resolveStageApprovers(tx, stage, task)
+		if len(approvers) == 0 {
+			continue
+		}
+
+		// 评分标准只绑定到第一个阶段（评分阶段），后续阶段没有评分，不再按最低分拦截
+		var rubricID *uint
+		if prevSessionID == nil {
+			rubricID = base.RubricID
+		}
+
+		templateID := template.ID
+		session := &models.ReviewSession{
+			TaskID:             task.ID,
+			ReviewType:         base.ReviewType,
+			TargetType:         base.TargetType,
+			TargetID:           base.TargetID,
+			InitiatedBy:        base.InitiatedBy,
+			InitiatedAt:        now,
+			Status:             "in_review",
+			ReviewMode:         models.ReviewModeSingle,
+			RequiredApprovals:  1,
+			RubricID:           rubricID,
+			ChainTemplateID:    &templateID,
+			ChainStageNo:       stage.StageNo,
+			ChainStageName:     stage.Name,
+			StageApproverIDs:   approvers,
+			PrevStageSessionID: prevSessionID,
+		}
+		if err := tx.Create(session).Error; err != nil {
+			return nil, fmt.Errorf("创建审批阶段会话失败: %v", err)
+		}
+		return session, nil
+	}
+	return nil, nil
+}
+
+// createSubmissionReview 提交方案/计划后自动发起审核：匹配审批链模板时创建第一个阶段的会话，否则由创建人单人审核
+func createSubmissionReview(tx *gorm.DB, task *models.Task, reviewType, targetType string, targetID, userID uint, now time.Time) (*models.ReviewSession, error) {
+	session := &models.ReviewSession{
+		TaskID:            task.ID,
+		ReviewType:        reviewType,
+		TargetType:        targetType,
+		TargetID:          targetID,
+		InitiatedBy:       userID,
+		InitiatedAt:       now,
+		Status:            "in_review",
+		ReviewMode:        models.ReviewModeSingle,
+		RequiredApprovals: 1,
+		RubricID:          activeRubricID(tx, reviewType),
+	}
+
+	if template := matchApprovalChain(tx, task, reviewType); template != nil {
+		stageSession, err := openChainStage(tx, template, task, session, 1, nil, now)
+		if err != nil {
+			return nil, err
+		}
+		if stageSession != nil {
+			return stageSession, nil
+		}
+	}
+
+	if err := tx.Create(session).Error; err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// describeSubmissionReview 自动发起审核的说明（用于变更日志）
+func describeSubmissionReview(session *models.ReviewSession) string {
+	if session.ChainStageNo == 0 {
+		return "自动发起单人审核"
+	}
+	return fmt.Sprintf("自动发起审批链审核（第 %d 阶段：%s）", session.ChainStageNo, session.ChainStageName)
+}
+
+// advanceApprovalChain 审批链阶段通过：结束本阶段会话并创建下一阶段会话
+// 没有后续阶段（或后续阶段都没有审批人）时返回 nil，由调用方按最终通过处理
+func advanceApprovalChain(tx *gorm.DB, session *models.ReviewSession, task *models.Task, actorID, principalID uint, delegateID *uint, comment string, now time.Time) (*models.ReviewSession, error) {
+	if session.ChainTemplateID == nil {
+		return nil, nil
+	}
+	var template models.ApprovalChainTemplate
+	if err := tx.Unscoped().Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("stage_no ASC")
+	}).First(&template, *session.ChainTemplateID).Error; err != nil {
+		return nil, errors.New("审批链模板不存在")
+	}
+
+	next, err := openChainStage(tx, &template, task, session, session.ChainStageNo+1, &session.ID, now)
+	if err != nil || next == nil {
+		return nil, err
+	}
+
+	if err := completeReviewSession(tx, session, map[string]interface{}{
+		"status":                     "completed",
+		"final_decision":             "approved",
+		"final_decision_by":          principalID,
+		"final_decision_delegate_id": delegateID,
+		"final_decision_at":          now,
+		"final_decision_comment":     comment,
+		"completed_at":               now,
+		"next_stage_session_id":      next.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	record := &models.ReviewRecord{
+		ReviewSessionID: session.ID,
+		ReviewerID:      principalID,
+		DelegateID:      delegateID,
+		ReviewerRole:    chainStageReviewerRole(task, principalID),
+		Opinion:         "approve",
+		Comment:         comment,
+		VoteWeight:      1.0,
+		ReviewedAt:      now,
+	}
+	if err := tx.Create(record).Error; err != nil {
+		return nil, fmt.Errorf("记录审批意见失败: %v", err)
+	}
+
+	changeLog := &models.TaskChangeLog{
+		TaskID:     task.ID,
+		UserID:     actorID,
+		ChangeType: "review_stage_approved",
+		FieldName:  "review_session_id",
+		OldValue:   fmt.Sprintf("%d", session.ID),
+		NewValue:   fmt.Sprintf("%d", next.ID),
+		Comment: fmt.Sprintf("审批链第 %d 阶段（%s）通过，进入第 %d 阶段（%s）：%s",
+			session.ChainStageNo, session.ChainStageName, next.ChainStageNo, next.ChainStageName, comment),
+	}
+	if err := tx.Create(changeLog).Error; err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// chainStageReviewerRole 审批链阶段审批人的审核角色
+func chainStageReviewerRole(task *models.Task, reviewerID uint) string {
+	if reviewerID == task.CreatorID {
+		return models.ReviewerRoleCreator
+	}
+	return models.ReviewerRoleLeader
+}
+
+// notifyChainStage 通知审批链阶段的审批人（含其代理人）
+func notifyChainStage(db *gorm.DB, task *models.Task, session *models.ReviewSession) {
+	if session == nil || session.ChainStageNo == 0 {
+		return
+	}
+	recipients := withActiveDelegates(db, task, session.StageApproverIDs, time.Now())
+	content := fmt.Sprintf("任务「%s」的%s进入审批链第 %d 阶段（%s），请审批",
+		task.Title, session.ReviewType, session.ChainStageNo, session.ChainStageName)
+	(&NotificationService{}).Notify(recipients, &task.ID, NotificationTypeReviewRequest, "审批链审批", content)
+}
+
+// notifyChainResult 通知执行人和创建人审批链的最终结果（驳回时任务退回执行人修改）
+func notifyChainResult(task *models.Task, session *models.ReviewSession, approved bool) {
+	recipients := []uint{task.CreatorID}
+	if task.ExecutorID != nil {
+		recipients = append(recipients, *task.ExecutorID)
+	}
+	content := fmt.Sprintf("任务「%s」的%s已在审批链第 %d 阶段（%s）被驳回，请执行人修改后重新提交",
+		task.Title, session.ReviewType, session.ChainStageNo, session.ChainStageName)
+	if approved {
+		content = fmt.Sprintf("任务「%s」的%s已通过审批链全部阶段", task.Title, session.ReviewType)
+	}
+	(&NotificationService{}).Notify(uniqueUintSlice(recipients), &task.ID, NotificationTypeStatusChange, "审批链结果", content)
+}
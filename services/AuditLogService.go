@@ -0,0 +1,90 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"math"
+)
+
+type AuditLogService struct{}
+
+// recordAudit 记录安全审计日志（写入失败只记录日志，不影响业务）
+func recordAudit(entry *models.AuditLog) {
+	if err := database.DB.Create(entry).Error; err != nil {
+		utils.Logger.Warnf("记录审计日志失败（%s %s）: %v", entry.Action, entry.Target, err)
+	}
+}
+
+// GetAuditLogs 分页查询审计日志（按时间倒序）
+func (s *AuditLogService) GetAuditLogs(req *dto.AuditLogQuery) (*dto.PaginationResponse, error) {
+	query := database.DB.Model(&models.AuditLog{})
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+	if req.UserID != 0 {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	if req.Target != "" {
+		query = query.Where("target = ?", req.Target)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page := req.GetPage()
+	pageSize := req.GetPageSize()
+	var logs []models.AuditLog
+	if err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginationResponse{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+		Data:       toAuditLogResponses(logs),
+	}, nil
+}
+
+// toAuditLogResponses 转换审计日志响应（批量加载用户名）
+func toAuditLogResponses(logs []models.AuditLog) []dto.AuditLogResponse {
+	var userIDs []uint
+	for _, log := range logs {
+		if log.UserID != nil {
+			userIDs = append(userIDs, *log.UserID)
+		}
+		if log.ActorID != nil {
+			userIDs = append(userIDs, *log.ActorID)
+		}
+	}
+	names := loadUsernames(userIDs)
+
+	responses := make([]dto.AuditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		response := dto.AuditLogResponse{
+			ID:        log.ID,
+			Action:    log.Action,
+			UserID:    log.UserID,
+			ActorID:   log.ActorID,
+			Target:    log.Target,
+			IP:        log.IP,
+			Detail:    log.Detail,
+			CreatedAt: dto.ToResponseTime(log.CreatedAt),
+		}
+		if log.UserID != nil {
+			response.Username = names[*log.UserID]
+		}
+		if log.ActorID != nil {
+			response.ActorName = names[*log.ActorID]
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 刷新令牌吊销原因
+const (
+	TokenRevokeRotated         = "rotated"          // 已轮换为新令牌
+	TokenRevokeLogout          = "logout"           // 用户注销
+	TokenRevokeReuse           = "reuse"            // 已轮换的令牌被再次使用，吊销整个令牌族
+	TokenRevokePasswordChanged = "password_changed" // 修改/重置密码
+	TokenRevokeDisabled        = "disabled"         // 用户被禁用
+	TokenRevokeDeleted         = "deleted"          // 用户被删除
+)
+
+// 令牌有效期的默认值（配置为 0 或负数时使用）
+const (
+	defaultAccessTokenMinutes = 30
+	defaultRefreshTokenHours  = 168
+)
+
+var errRefreshTokenInvalid = errors.New("刷新令牌无效或已失效，请重新登录")
+
+type AuthTokenService struct{}
+
+// tokenLifetimes 访问令牌和刷新令牌的有效期
+func tokenLifetimes(cfg config.JWTConfig) (time.Duration, time.Duration) {
+	accessMinutes := cfg.AccessExpireMinutes
+	if accessMinutes <= 0 {
+		accessMinutes = defaultAccessTokenMinutes
+	}
+	refreshHours := cfg.RefreshExpireHours
+	if refreshHours <= 0 {
+		refreshHours = defaultRefreshTokenHours
+	}
+	return time.Duration(accessMinutes) * time.Minute, time.Duration(refreshHours) * time.Hour
+}
+
+// hashRefreshToken 计算刷新令牌的哈希（数据库只保存哈希值）
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRefreshTokenValue 生成随机的刷新令牌
+func newRefreshTokenValue() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// issueTokens 为登录会话签发访问令牌和刷新令牌（刷新令牌属于会话的令牌族）
+func issueTokens(db *gorm.DB, user *models.User, session *models.UserSession, now time.Time) (*dto.TokenResponse, *models.RefreshToken, error) {
+	accessTTL, refreshTTL := tokenLifetimes(config.GetConfig().JWT)
+
+	tokenID := uuid.NewString()
+	accessToken, err := utils.GenerateAccessToken(user.ID, user.Username, user.Mobile, session.ID, tokenID, accessTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+	refreshToken, err := newRefreshTokenValue()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := &models.RefreshToken{
+		UserID:          user.ID,
+		TokenHash:       hashRefreshToken(refreshToken),
+		FamilyID:        session.FamilyID,
+		AccessTokenID:   tokenID,
+		AccessExpiresAt: now.Add(accessTTL),
+		ExpiresAt:       now.Add(refreshTTL),
+	}
+	if err := db.Create(record).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &dto.TokenResponse{
+		Token:            accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(accessTTL / time.Second),
+		RefreshExpiresIn: int64(refreshTTL / time.Second),
+	}, record, nil
+}
+
+// issueLoginTokens 登录成功后登记会话、签发令牌并填充登录响应
+func issueLoginTokens(user *models.User, response *dto.LoginResponse, client LoginClient, method string) error {
+	now := time.Now()
+	_, refreshTTL := tokenLifetimes(config.GetConfig().JWT)
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	session, err := openSession(tx, user.ID, client, method, now.Add(refreshTTL), now)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tokens, _, err := issueTokens(tx, user, session, now)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	response.Token = tokens.Token
+	response.RefreshToken = tokens.RefreshToken
+	response.ExpiresIn = tokens.ExpiresIn
+	response.RefreshExpiresIn = tokens.RefreshExpiresIn
+	return nil
+}
+
+// revokeSessions 结束 column = value 的登录会话：吊销其刷新令牌，并将签发的未过期访问令牌加入吊销列表
+// column 为 user_id 或 family_id（两张表同名）；已轮换的令牌签发的访问令牌可能仍未过期，同样加入吊销列表
+func revokeSessions(db *gorm.DB, column string, value interface{}, reason string, revokedBy *uint, now time.Time) error {
+	var tokens []models.RefreshToken
+	if err := db.Where(column+" = ? AND (revoked_at IS NULL OR access_expires_at > ?)", value, now).
+		Find(&tokens).Error; err != nil {
+		return err
+	}
+
+	revoked := make([]models.RevokedToken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.AccessTokenID == "" || !token.AccessExpiresAt.After(now) {
+			continue
+		}
+		revoked = append(revoked, models.RevokedToken{
+			TokenID:   token.AccessTokenID,
+			UserID:    token.UserID,
+			ExpiresAt: token.AccessExpiresAt,
+			Reason:    reason,
+		})
+	}
+	if len(revoked) > 0 {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Model(&models.RefreshToken{}).
+		Where(column+" = ? AND revoked_at IS NULL", value).
+		Updates(map[string]interface{}{
+			"revoked_at":    now,
+			"revoke_reason": reason,
+		}).Error; err != nil {
+		return err
+	}
+
+	return db.Model(&models.UserSession{}).
+		Where(column+" = ? AND revoked_at IS NULL", value).
+		Updates(map[string]interface{}{
+			"revoked_at":    now,
+			"revoke_reason": reason,
+			"revoked_by":    revokedBy,
+		}).Error
+}
+
+// revokeUserTokens 结束用户的全部登录会话（修改密码、禁用、删除用户时调用）
+func revokeUserTokens(db *gorm.DB, userID uint, reason string) error {
+	return revokeSessions(db, "user_id", userID, reason, nil, time.Now())
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌和刷新令牌（原刷新令牌随即失效）
+// 已轮换的刷新令牌被再次使用说明令牌可能泄露，吊销其所属的整个令牌族
+func (s *AuthTokenService) Refresh(refreshToken string) (*dto.TokenResponse, error) {
+	now := time.Now()
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var current models.RefreshToken
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", hashRefreshToken(refreshToken)).
+		First(&current).Error; err != nil {
+		tx.Rollback()
+		return nil, errRefreshTokenInvalid
+	}
+
+	if current.RevokedAt != nil {
+		if current.RevokeReason != TokenRevokeRotated {
+			tx.Rollback()
+			return nil, errRefreshTokenInvalid
+		}
+		if err := revokeSessions(tx, "family_id", current.FamilyID, TokenRevokeReuse, nil, now); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return nil, err
+		}
+		utils.Logger.Warnf("用户 %d 的刷新令牌被重复使用，已吊销令牌族 %s", current.UserID, current.FamilyID)
+		return nil, errRefreshTokenInvalid
+	}
+
+	if !now.Before(current.ExpiresAt) {
+		tx.Rollback()
+		return nil, errors.New("刷新令牌已过期，请重新登录")
+	}
+
+	var session models.UserSession
+	if err := tx.Where("family_id = ? AND revoked_at IS NULL", current.FamilyID).First(&session).Error; err != nil {
+		tx.Rollback()
+		return nil, errRefreshTokenInvalid
+	}
+
+	var user models.User
+	if err := tx.First(&user, current.UserID).Error; err != nil || user.Status != models.UserStatusActive {
+		tx.Rollback()
+		return nil, errors.New("用户不可用，请联系管理员")
+	}
+
+	tokens, next, err := issueTokens(tx, &user, &session, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 刷新即会话仍在使用，顺延会话过期时间
+	if err := tx.Model(&session).Updates(map[string]interface{}{
+		"expires_at":   next.ExpiresAt,
+		"last_seen_at": now,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Model(&current).Updates(map[string]interface{}{
+		"revoked_at":     now,
+		"revoke_reason":  TokenRevokeRotated,
+		"replaced_by_id": next.ID,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Logout 注销当前登录会话：结束刷新令牌所属的会话并吊销当前访问令牌
+// 未传刷新令牌时按当前访问令牌ID查找所属的令牌族
+func (s *AuthTokenService) Logout(userID uint, accessTokenID string, accessExpiresAt time.Time, refreshToken string) error {
+	now := time.Now()
+
+	var current models.RefreshToken
+	query := database.DB.Where("user_id = ?", userID)
+	if refreshToken != "" {
+		query = query.Where("token_hash = ?", hashRefreshToken(refreshToken))
+	} else {
+		query = query.Where("access_token_id = ?", accessTokenID)
+	}
+	if err := query.First(&current).Error; err == nil {
+		if err := revokeSessions(database.DB, "family_id", current.FamilyID, TokenRevokeLogout, nil, now); err != nil {
+			return err
+		}
+	}
+
+	if accessTokenID == "" || !accessExpiresAt.After(now) {
+		return nil
+	}
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+		TokenID:   accessTokenID,
+		UserID:    userID,
+		ExpiresAt: accessExpiresAt,
+		Reason:    TokenRevokeLogout,
+	}).Error
+}
+
+// CleanupExpiredTokens 清理已过期的刷新令牌、吊销记录和登录会话，返回删除的记录数
+func (s *AuthTokenService) CleanupExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	for _, model := range []interface{}{&models.RefreshToken{}, &models.RevokedToken{}, &models.UserSession{}} {
+		result := database.DB.WithContext(ctx).Unscoped().Where("expires_at < ?", now).Delete(model)
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += result.RowsAffected
+	}
+	return deleted, nil
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/models"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RoleAdmin 超级管理员角色名称：拥有全部权限，不受资源范围限制
+const RoleAdmin = "admin"
+
+// ResourceScope 资源范围类型：在功能权限之外，限定用户只能操作自己负责范围内的资源
+type ResourceScope string
+
+const (
+	// ScopeDepartment 部门：用户是该部门或其上级部门的负责人
+	ScopeDepartment ResourceScope = "department"
+	// ScopeUser 用户：目标用户属于当前用户负责的部门（含下级部门）
+	ScopeUser ResourceScope = "user"
+	// ScopeTask 任务：当前用户是任务创建者，或负责任务所属部门（含下级部门）
+	ScopeTask ResourceScope = "task"
+	// ScopeReviewSession 审核会话：当前用户是会话的审核决策人（含代理人），或管理会话所属任务
+	ScopeReviewSession ResourceScope = "review_session"
+	// ScopeTransitionRequest 状态转换申请：当前用户是申请的审批人（含代理人），或管理申请所属任务
+	ScopeTransitionRequest ResourceScope = "transition_request"
+)
+
+// maxDepartmentDepth 向上查找上级部门的最大层数（防止部门数据成环时死循环）
+const maxDepartmentDepth = 32
+
+// permissionCacheTTL 用户权限列表的缓存时间
+const permissionCacheTTL = 5 * time.Minute
+
+// AuthorizationService 授权服务：统一判断功能权限（角色权限）和资源范围（部门负责人、任务创建者）
+type AuthorizationService struct{}
+
+// permissionCacheKey 用户权限列表的缓存键
+func permissionCacheKey(userID uint) string {
+	return fmt.Sprintf("user_permissions:%d", userID)
+}
+
+// GetUserPermissions 获取用户通过角色拥有的全部权限名称（Redis 可用时缓存）
+func (s *AuthorizationService) GetUserPermissions(userID uint) ([]string, error) {
+	var permissions []string
+
+	cacheKey := permissionCacheKey(userID)
+	if database.RedisClient != nil {
+		cached, err := database.GetCache(cacheKey)
+		if err == nil && cached != "" && json.Unmarshal([]byte(cached), &permissions) == nil {
+			return permissions, nil
+		}
+	}
+
+	var user models.User
+	if err := database.DB.Preload("Roles.Permissions").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	permissions = collectUserPermissions(&user)
+
+	if database.RedisClient != nil {
+		if data, err := json.Marshal(permissions); err == nil {
+			database.SetCache(cacheKey, string(data), permissionCacheTTL)
+		}
+	}
+
+	return permissions, nil
+}
+
+// HasPermission 判断用户是否拥有指定权限
+func (s *AuthorizationService) HasPermission(userID uint, permission string) (bool, error) {
+	permissions, err := s.GetUserPermissions(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, perm := range permissions {
+		if perm == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ClearPermissionCache 清除用户权限缓存（调整用户角色后调用）
+func (s *AuthorizationService) ClearPermissionCache(userID uint) {
+	if database.RedisClient != nil {
+		database.DeleteCache(permissionCacheKey(userID))
+	}
+}
+
+// IsAdmin 判断用户是否是超级管理员
+func (s *AuthorizationService) IsAdmin(userID uint) bool {
+	var user models.User
+	if err := database.DB.Preload("Roles").First(&user, userID).Error; err != nil {
+		return false
+	}
+	return isAdminUser(&user)
+}
+
+// Authorize 判断用户能否对指定资源执行操作：
+// 必须拥有功能权限；超级管理员不受资源范围限制，其他用户只能操作自己负责范围内的资源
+func (s *AuthorizationService) Authorize(userID uint, permission string, scope ResourceScope, resourceID uint) (bool, error) {
+	allowed, err := s.HasPermission(userID, permission)
+	if err != nil || !allowed {
+		return false, err
+	}
+	if s.IsAdmin(userID) {
+		return true, nil
+	}
+
+	switch scope {
+	case ScopeDepartment:
+		return s.LeadsDepartment(userID, resourceID), nil
+	case ScopeUser:
+		return s.ManagesUser(userID, resourceID), nil
+	case ScopeTask:
+		return s.ManagesTask(userID, resourceID), nil
+	case ScopeReviewSession:
+		return s.DecidesReviewSession(userID, resourceID), nil
+	case ScopeTransitionRequest:
+		return s.DecidesTransitionRequest(userID, resourceID), nil
+	default:
+		return false, fmt.Errorf("未知的资源范围: %s", scope)
+	}
+}
+
+// LeadsDepartment 判断用户是否负责指定部门（直接负责该部门，或负责其任一上级部门）
+func (s *AuthorizationService) LeadsDepartment(userID, departmentID uint) bool {
+	managed := s.managedDepartmentSet(userID)
+	if len(managed) == 0 {
+		return false
+	}
+	return departmentWithinScope(departmentID, managed, parentDepartment)
+}
+
+// ManagesUser 判断目标用户是否属于当前用户负责的部门
+func (s *AuthorizationService) ManagesUser(userID, targetUserID uint) bool {
+	var target models.User
+	if err := database.DB.Select("id", "department_id").First(&target, targetUserID).Error; err != nil {
+		return false
+	}
+	if target.DepartmentID == nil {
+		return false
+	}
+	return s.LeadsDepartment(userID, *target.DepartmentID)
+}
+
+// ManagesTask 判断当前用户是否是任务创建者，或负责任务所属部门
+func (s *AuthorizationService) ManagesTask(userID, taskID uint) bool {
+	var task models.Task
+	if err := database.DB.Select("id", "creator_id", "department_id").First(&task, taskID).Error; err != nil {
+		return false
+	}
+	if task.CreatorID == userID {
+		return true
+	}
+	if task.DepartmentID == nil {
+		return false
+	}
+	return s.LeadsDepartment(userID, *task.DepartmentID)
+}
+
+// DecidesReviewSession 判断当前用户能否决策审核会话：会话的审核决策人或其代理人，或管理会话所属任务
+func (s *AuthorizationService) DecidesReviewSession(userID, sessionID uint) bool {
+	var session models.ReviewSession
+	if err := database.DB.First(&session, sessionID).Error; err != nil {
+		return false
+	}
+	var task models.Task
+	if err := database.DB.First(&task, session.TaskID).Error; err != nil {
+		return false
+	}
+
+	deciders := reviewDeciderIDs(&session, &task)
+	if session.ReviewType == TransitionReviewType {
+		deciders = taskCreatorAndLeaderIDs(&task)
+	}
+	if _, ok := resolveReviewPrincipal(database.DB, deciders, userID, &task, time.Now()); ok {
+		return true
+	}
+	return s.ManagesTask(userID, task.ID)
+}
+
+// DecidesTransitionRequest 判断当前用户能否审批状态转换申请：申请的审批人或其代理人，或管理申请所属任务
+func (s *AuthorizationService) DecidesTransitionRequest(userID, requestID uint) bool {
+	var request models.TaskTransitionRequest
+	if err := database.DB.Select("id", "task_id").First(&request, requestID).Error; err != nil {
+		return false
+	}
+	var task models.Task
+	if err := database.DB.First(&task, request.TaskID).Error; err != nil {
+		return false
+	}
+
+	if (&TransitionRequestService{}).IsApprover(&task, userID) {
+		return true
+	}
+	return s.ManagesTask(userID, task.ID)
+}
+
+// managedDepartmentSet 用户直接负责的部门ID集合
+func (s *AuthorizationService) managedDepartmentSet(userID uint) map[uint]bool {
+	managed := make(map[uint]bool)
+	for _, id := range (&CommonService{}).GetUserManagedDepartmentIDs(userID) {
+		managed[id] = true
+	}
+	return managed
+}
+
+// parentDepartment 查询部门的上级部门ID，部门不存在时返回 false
+func parentDepartment(departmentID uint) (*uint, bool) {
+	var dept models.Department
+	if err := database.DB.Select("id", "parent_id").First(&dept, departmentID).Error; err != nil {
+		return nil, false
+	}
+	return dept.ParentID, true
+}
+
+// departmentWithinScope 从指定部门逐级向上查找，任一层级在负责部门集合中即属于管理范围
+func departmentWithinScope(departmentID uint, managed map[uint]bool, parentOf func(uint) (*uint, bool)) bool {
+	visited := make(map[uint]bool)
+	current := departmentID
+	for depth := 0; depth < maxDepartmentDepth; depth++ {
+		if managed[current] {
+			return true
+		}
+		visited[current] = true
+
+		parentID, ok := parentOf(current)
+		if !ok || parentID == nil || visited[*parentID] {
+			return false
+		}
+		current = *parentID
+	}
+	return false
+}
+
+// isAdminUser 判断用户是否是超级管理员（需预加载 Roles）
+func isAdminUser(user *models.User) bool {
+	for _, role := range user.Roles {
+		if role.Name == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// userHasPermission 判断用户是否拥有指定权限（需预加载 Roles.Permissions）
+func userHasPermission(user *models.User, permission string) bool {
+	for _, role := range user.Roles {
+		for _, perm := range role.Permissions {
+			if perm.Name == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectUserPermissions 汇总用户各角色的权限名称并去重（需预加载 Roles.Permissions）
+func collectUserPermissions(user *models.User) []string {
+	seen := make(map[string]bool)
+	permissions := make([]string, 0)
+	for _, role := range user.Roles {
+		for _, perm := range role.Permissions {
+			if !seen[perm.Name] {
+				seen[perm.Name] = true
+				permissions = append(permissions, perm.Name)
+			}
+		}
+	}
+	return permissions
+}
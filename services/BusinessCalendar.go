@@ -0,0 +1,163 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"fmt"
+	"time"
+)
+
+// businessCalendarMaxDays 工作时间计算最多向后扫描的天数（防止配置错误导致死循环）
+const businessCalendarMaxDays = 3660
+
+// businessCalendar 工作日历的计算形式：工作日、每日工作时段、节假日和调休
+// 为 nil 时按自然时间（7x24）计算
+type businessCalendar struct {
+	loc           *time.Location
+	workStart     int // 上班时间（距零点分钟数）
+	workEnd       int // 下班时间（距零点分钟数）
+	workdays      map[time.Weekday]bool
+	holidays      map[string]bool // 放假的日期（YYYY-MM-DD）
+	extraWorkdays map[string]bool // 调休上班的日期（YYYY-MM-DD）
+}
+
+// parseClock 解析 HH:MM 为距零点的分钟数
+func parseClock(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("无效的时间 %s，格式应为 HH:MM", value)
+	}
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("无效的时间 %s", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// newBusinessCalendar 根据日历配置构建计算用的工作日历
+func newBusinessCalendar(calendar *models.BusinessCalendar) (*businessCalendar, error) {
+	loc, err := time.LoadLocation(calendar.TimeZone)
+	if err != nil || calendar.TimeZone == "" {
+		loc = time.Local
+	}
+	workStart, err := parseClock(calendar.WorkStart)
+	if err != nil {
+		return nil, err
+	}
+	workEnd, err := parseClock(calendar.WorkEnd)
+	if err != nil {
+		return nil, err
+	}
+	if workEnd <= workStart {
+		return nil, fmt.Errorf("下班时间 %s 必须晚于上班时间 %s", calendar.WorkEnd, calendar.WorkStart)
+	}
+
+	cal := &businessCalendar{
+		loc:           loc,
+		workStart:     workStart,
+		workEnd:       workEnd,
+		workdays:      make(map[time.Weekday]bool),
+		holidays:      make(map[string]bool),
+		extraWorkdays: make(map[string]bool),
+	}
+	for _, day := range calendar.WorkDays {
+		if day < 1 || day > 7 {
+			return nil, fmt.Errorf("无效的工作日 %d，应为 1-7", day)
+		}
+		cal.workdays[time.Weekday(day%7)] = true
+	}
+	for _, holiday := range calendar.Holidays {
+		date := holiday.Date.Format(dateKeyFormat)
+		if holiday.IsWorkday {
+			cal.extraWorkdays[date] = true
+		} else {
+			cal.holidays[date] = true
+		}
+	}
+	return cal, nil
+}
+
+const dateKeyFormat = "2006-01-02"
+
+// isWorkday 判断某日是否为工作日（调休上班优先，其次节假日，最后按星期）
+func (c *businessCalendar) isWorkday(day time.Time) bool {
+	key := day.Format(dateKeyFormat)
+	if c.extraWorkdays[key] {
+		return true
+	}
+	if c.holidays[key] {
+		return false
+	}
+	return c.workdays[day.Weekday()]
+}
+
+// workWindow 返回某日的工作时段
+func (c *businessCalendar) workWindow(day time.Time) (time.Time, time.Time) {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.loc)
+	return midnight.Add(time.Duration(c.workStart) * time.Minute), midnight.Add(time.Duration(c.workEnd) * time.Minute)
+}
+
+// BusinessDuration 计算两个时间点之间的工作时长
+func (c *businessCalendar) BusinessDuration(start, end time.Time) time.Duration {
+	if !end.After(start) {
+		return 0
+	}
+	if c == nil {
+		return end.Sub(start)
+	}
+
+	var total time.Duration
+	day := start.In(c.loc)
+	for i := 0; i < businessCalendarMaxDays; i++ {
+		windowStart, windowEnd := c.workWindow(day)
+		if !windowStart.Before(end) {
+			break
+		}
+		if c.isWorkday(day) {
+			from := maxTime(windowStart, start)
+			to := minTime(windowEnd, end)
+			if to.After(from) {
+				total += to.Sub(from)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
+
+// AddBusinessDuration 计算从 start 开始经过 d 工作时长后的时间点
+func (c *businessCalendar) AddBusinessDuration(start time.Time, d time.Duration) time.Time {
+	if c == nil {
+		return start.Add(d)
+	}
+
+	remaining := d
+	day := start.In(c.loc)
+	for i := 0; i < businessCalendarMaxDays; i++ {
+		if c.isWorkday(day) {
+			windowStart, windowEnd := c.workWindow(day)
+			from := maxTime(windowStart, start)
+			if windowEnd.After(from) {
+				available := windowEnd.Sub(from)
+				if remaining <= available {
+					return from.Add(remaining)
+				}
+				remaining -= available
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return start.Add(d)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
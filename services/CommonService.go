@@ -318,15 +318,5 @@ func (s *CommonService) GetUserManagedDepartmentIDs(userID uint) []uint {
 
 // IsSuperAdmin 检查用户是否是超级管理员
 func (s *CommonService) IsSuperAdmin(userID uint) bool {
-	var user models.User
-	if err := database.DB.Preload("Roles").First(&user, userID).Error; err != nil {
-		return false
-	}
-
-	for _, role := range user.Roles {
-		if role.Name == "admin" {
-			return true
-		}
-	}
-	return false
+	return (&AuthorizationService{}).IsAdmin(userID)
 }
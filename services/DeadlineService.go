@@ -0,0 +1,293 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationTypeDeadline 截止提醒/超期通知类型
+const NotificationTypeDeadline = "deadline"
+
+// deadlineStatusKinds 进入后开始计时的状态及对应的截止类型
+var deadlineStatusKinds = map[string]string{
+	"req_pending_solution": models.TaskDeadlineKindSolution,
+	"req_pending_plan":     models.TaskDeadlineKindExecutionPlan,
+}
+
+// deadlineKindNames 截止类型名称（用于通知内容）
+var deadlineKindNames = map[string]string{
+	models.TaskDeadlineKindSolution:      "思路方案",
+	models.TaskDeadlineKindExecutionPlan: "执行计划",
+}
+
+type DeadlineService struct{}
+
+// DeadlineProcessResult 一次截止检查的处理结果
+type DeadlineProcessResult struct {
+	// 发送截止提醒的数量
+	Reminded int
+	// 超期升级的数量
+	Escalated int
+	// 超期自动转换状态的数量
+	AutoTransitioned int
+}
+
+// computeDeadlineDue 计算截止时间，返回 false 表示该任务不设截止
+// 思路方案：任务的 SolutionDeadline（天）；执行计划：配置的 ExecutionPlanDeadlineHours（小时）
+func computeDeadlineDue(kind string, task *models.Task, cfg config.TaskConfig, startedAt time.Time) (time.Time, bool) {
+	switch kind {
+	case models.TaskDeadlineKindSolution:
+		if task.SolutionDeadline == nil || *task.SolutionDeadline <= 0 {
+			return time.Time{}, false
+		}
+		return startedAt.AddDate(0, 0, *task.SolutionDeadline), true
+	case models.TaskDeadlineKindExecutionPlan:
+		if cfg.ExecutionPlanDeadlineHours <= 0 {
+			return time.Time{}, false
+		}
+		return startedAt.Add(time.Duration(cfg.ExecutionPlanDeadlineHours) * time.Hour), true
+	}
+	return time.Time{}, false
+}
+
+// overdueStatusCode 获取截止类型超期后自动转换到的状态编码（为空表示不自动转换）
+func overdueStatusCode(kind string, cfg config.TaskConfig) string {
+	switch kind {
+	case models.TaskDeadlineKindSolution:
+		return cfg.SolutionOverdueStatusCode
+	case models.TaskDeadlineKindExecutionPlan:
+		return cfg.PlanOverdueStatusCode
+	}
+	return ""
+}
+
+// OnStatusChange 任务状态变更时关闭进行中的截止计时，进入待提交方案/计划状态时开始新的计时
+func (s *DeadlineService) OnStatusChange(db *gorm.DB, task *models.Task, toStatus string, now time.Time) error {
+	if err := db.Model(&models.TaskDeadline{}).
+		Where("task_id = ? AND status IN ?", task.ID, []string{models.TaskDeadlineStatusActive, models.TaskDeadlineStatusOverdue}).
+		Updates(map[string]interface{}{
+			"status":    models.TaskDeadlineStatusClosed,
+			"closed_at": now,
+		}).Error; err != nil {
+		return fmt.Errorf("关闭截止计时失败: %v", err)
+	}
+
+	kind, ok := deadlineStatusKinds[toStatus]
+	if !ok {
+		return nil
+	}
+	dueAt, ok := computeDeadlineDue(kind, task, config.GetConfig().Task, now)
+	if !ok {
+		return nil
+	}
+
+	deadline := &models.TaskDeadline{
+		TaskID:     task.ID,
+		Kind:       kind,
+		StatusCode: toStatus,
+		StartedAt:  now,
+		DueAt:      dueAt,
+		Status:     models.TaskDeadlineStatusActive,
+	}
+	if err := db.Create(deadline).Error; err != nil {
+		return fmt.Errorf("创建截止计时失败: %v", err)
+	}
+	return nil
+}
+
+// GetTaskDeadline 获取任务当前的截止计时（没有进行中的计时时返回 nil）
+func (s *DeadlineService) GetTaskDeadline(taskID uint) *dto.TaskDeadlineResponse {
+	var deadline models.TaskDeadline
+	if err := database.DB.Where("task_id = ? AND status IN ?", taskID,
+		[]string{models.TaskDeadlineStatusActive, models.TaskDeadlineStatusOverdue}).
+		Order("id DESC").First(&deadline).Error; err != nil {
+		return nil
+	}
+	return toTaskDeadlineResponse(&deadline, time.Now())
+}
+
+// toTaskDeadlineResponse 转换截止计时响应，剩余时间为负表示已超期
+func toTaskDeadlineResponse(deadline *models.TaskDeadline, now time.Time) *dto.TaskDeadlineResponse {
+	remaining := deadline.DueAt.Sub(now)
+	return &dto.TaskDeadlineResponse{
+		Kind:             deadline.Kind,
+		StatusCode:       deadline.StatusCode,
+		StartedAt:        dto.ToResponseTime(deadline.StartedAt),
+		DueAt:            dto.ToResponseTime(deadline.DueAt),
+		RemainingSeconds: int64(remaining / time.Second),
+		Overdue:          remaining < 0,
+		Status:           deadline.Status,
+	}
+}
+
+// ProcessDeadlines 检查截止计时：到期前提醒执行人，超期后通知创建人和部门负责人，并按配置自动转换状态
+// ctx 取消或超时后停止处理剩余的计时
+func (s *DeadlineService) ProcessDeadlines(ctx context.Context, now time.Time) (*DeadlineProcessResult, error) {
+	cfg := config.GetConfig().Task
+	result := &DeadlineProcessResult{}
+	db := database.DB.WithContext(ctx)
+
+	// 1. 截止前提醒
+	if cfg.DeadlineReminderHours > 0 {
+		var upcoming []models.TaskDeadline
+		remindBefore := now.Add(time.Duration(cfg.DeadlineReminderHours) * time.Hour)
+		if err := db.Where("status = ? AND reminded_at IS NULL AND due_at > ? AND due_at <= ?",
+			models.TaskDeadlineStatusActive, now, remindBefore).
+			Find(&upcoming).Error; err != nil {
+			return result, err
+		}
+		for i := range upcoming {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			if s.remind(ctx, &upcoming[i], now) {
+				result.Reminded++
+			}
+		}
+	}
+
+	// 2. 超期升级
+	var overdue []models.TaskDeadline
+	if err := db.Where("status = ? AND due_at <= ?", models.TaskDeadlineStatusActive, now).
+		Find(&overdue).Error; err != nil {
+		return result, err
+	}
+	for i := range overdue {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if s.escalate(ctx, &overdue[i], now) {
+			result.Escalated++
+		}
+	}
+
+	// 3. 超期自动转换状态
+	var pending []models.TaskDeadline
+	if err := db.Where("status = ? AND auto_transitioned_at IS NULL", models.TaskDeadlineStatusOverdue).
+		Find(&pending).Error; err != nil {
+		return result, err
+	}
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		target := overdueStatusCode(pending[i].Kind, cfg)
+		if target == "" {
+			continue
+		}
+		transitioned, err := s.autoTransition(ctx, &pending[i], target, now)
+		if err != nil {
+			utils.Logger.Warnf("任务 %d 超期自动转换状态失败: %v", pending[i].TaskID, err)
+			continue
+		}
+		if transitioned {
+			result.AutoTransitioned++
+		}
+	}
+
+	return result, nil
+}
+
+// remind 到期前提醒执行人
+func (s *DeadlineService) remind(ctx context.Context, deadline *models.TaskDeadline, now time.Time) bool {
+	db := database.DB.WithContext(ctx)
+	var task models.Task
+	if err := db.First(&task, deadline.TaskID).Error; err != nil {
+		return false
+	}
+	if err := db.Model(deadline).Update("reminded_at", now).Error; err != nil {
+		return false
+	}
+	if task.ExecutorID != nil {
+		content := fmt.Sprintf("任务「%s」的%s将于 %s 截止，请及时提交",
+			task.Title, deadlineKindNames[deadline.Kind], deadline.DueAt.Format(dto.TimeFormatDatetime))
+		(&NotificationService{}).Notify([]uint{*task.ExecutorID}, &task.ID, NotificationTypeDeadline, "截止提醒", content)
+	}
+	return true
+}
+
+// escalate 超期后通知执行人、创建人和所属部门负责人，并标记为已超期
+func (s *DeadlineService) escalate(ctx context.Context, deadline *models.TaskDeadline, now time.Time) bool {
+	db := database.DB.WithContext(ctx)
+	var task models.Task
+	if err := db.First(&task, deadline.TaskID).Error; err != nil {
+		return false
+	}
+	if err := db.Model(deadline).Updates(map[string]interface{}{
+		"status":       models.TaskDeadlineStatusOverdue,
+		"escalated_at": now,
+	}).Error; err != nil {
+		return false
+	}
+
+	recipients := taskCreatorAndLeaderIDs(&task)
+	if task.ExecutorID != nil {
+		recipients = append(recipients, *task.ExecutorID)
+	}
+	content := fmt.Sprintf("任务「%s」的%s已于 %s 超期未提交",
+		task.Title, deadlineKindNames[deadline.Kind], deadline.DueAt.Format(dto.TimeFormatDatetime))
+	(&NotificationService{}).Notify(recipients, &task.ID, NotificationTypeDeadline, "任务超期", content)
+	return true
+}
+
+// autoTransition 超期任务自动转换到配置的状态（以创建人身份记录，跳过角色和守卫校验）
+// 任务已离开截止计时对应的状态时不做转换，只关闭该截止计时，返回 false
+func (s *DeadlineService) autoTransition(ctx context.Context, deadline *models.TaskDeadline, target string, now time.Time) (bool, error) {
+	db := database.DB.WithContext(ctx)
+	var task models.Task
+	if err := db.First(&task, deadline.TaskID).Error; err != nil {
+		return false, err
+	}
+	if task.StatusCode != deadline.StatusCode {
+		return false, db.Model(deadline).Updates(map[string]interface{}{
+			"status":    models.TaskDeadlineStatusClosed,
+			"closed_at": now,
+		}).Error
+	}
+
+	var toStatus models.TaskStatus
+	if err := db.Where("code = ? AND task_type_code = ?", target, task.TaskTypeCode).
+		First(&toStatus).Error; err != nil {
+		return false, fmt.Errorf("超期目标状态 %s 不属于任务类型 %s", target, task.TaskTypeCode)
+	}
+
+	statusTransition := &StatusTransitionService{}
+	hookCtx := &TransitionContext{
+		Task:         &task,
+		UserID:       task.CreatorID,
+		FromStatus:   task.StatusCode,
+		ToStatus:     target,
+		FromCategory: statusTransition.GetStatusCategory(task.StatusCode),
+		ToCategory:   toStatus.Category,
+		Comment:      fmt.Sprintf("系统自动：%s超期未提交", deadlineKindNames[deadline.Kind]),
+	}
+
+	tx := db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(deadline).Update("auto_transitioned_at", now).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := (&TaskService{}).applyTransition(tx, hookCtx); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	return true, runTransitionActions(hookCtx)
+}
@@ -613,13 +613,7 @@ func (s *DepartmentService) GetManagedDepartmentsForFilter(userID uint, withStat
 	}
 
 	// 判断是否为超级管理员
-	isAdmin := false
-	for _, role := range user.Roles {
-		if role.Name == "admin" {
-			isAdmin = true
-			break
-		}
-	}
+	isAdmin := isAdminUser(&user)
 
 	var result []dto.ManagedDepartmentResponse
 
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/scheduler"
+	"context"
+	"math"
+	"time"
+)
+
+type JobService struct{}
+
+// GetJobList 获取已注册的定时任务及其最近一次执行和最近一次失败
+func (s *JobService) GetJobList() ([]dto.JobResponse, error) {
+	jobs := scheduler.GetScheduler().Jobs()
+	names := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+
+	lastRuns, err := s.latestRuns(names, "")
+	if err != nil {
+		return nil, err
+	}
+	lastFailures, err := s.latestRuns(names, models.JobRunStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := config.GetConfig().Scheduler.Enabled
+	responses := make([]dto.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		resp := dto.JobResponse{
+			Name:           job.Name,
+			Spec:           job.Spec,
+			Description:    job.Description,
+			TimeoutSeconds: int64(job.Timeout / time.Second),
+			LastRun:        lastRuns[job.Name],
+			LastFailure:    lastFailures[job.Name],
+		}
+		if scheduled {
+			resp.NextRunAt = dto.PtrToResponseTime(&job.NextRun)
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// latestRuns 查询每个任务最近一次（指定状态的）执行记录
+func (s *JobService) latestRuns(names []string, status string) (map[string]*dto.JobRunResponse, error) {
+	result := make(map[string]*dto.JobRunResponse)
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	query := database.DB.Raw(`SELECT DISTINCT ON (job_name) * FROM job_runs
+		WHERE job_name IN ? ORDER BY job_name, started_at DESC, id DESC`, names)
+	if status != "" {
+		query = database.DB.Raw(`SELECT DISTINCT ON (job_name) * FROM job_runs
+			WHERE job_name IN ? AND status = ? ORDER BY job_name, started_at DESC, id DESC`, names, status)
+	}
+	var runs []models.JobRun
+	if err := query.Scan(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	responses := toJobRunResponses(runs)
+	for i := range responses {
+		result[responses[i].JobName] = &responses[i]
+	}
+	return result, nil
+}
+
+// GetJobRuns 分页查询任务的执行记录
+func (s *JobService) GetJobRuns(name string, req *dto.JobRunQuery) (*dto.PaginationResponse, error) {
+	if !scheduler.GetScheduler().HasJob(name) {
+		return nil, scheduler.ErrJobNotFound
+	}
+
+	query := database.DB.Model(&models.JobRun{}).Where("job_name = ?", name)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page := req.GetPage()
+	pageSize := req.GetPageSize()
+	var runs []models.JobRun
+	if err := query.Order("started_at DESC, id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginationResponse{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+		Data:       toJobRunResponses(runs),
+	}, nil
+}
+
+// TriggerJob 手动触发任务（后台执行），返回本次执行记录
+func (s *JobService) TriggerJob(name string, userID uint) (*dto.JobRunResponse, error) {
+	run, err := scheduler.GetScheduler().Trigger(name, userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := toJobRunResponses([]models.JobRun{*run})
+	return &responses[0], nil
+}
+
+// CleanupRuns 删除指定时间之前的执行记录，返回删除的数量
+func (s *JobService) CleanupRuns(ctx context.Context, before time.Time) (int64, error) {
+	result := database.DB.WithContext(ctx).Where("started_at < ? AND status <> ?", before, models.JobRunStatusRunning).
+		Delete(&models.JobRun{})
+	return result.RowsAffected, result.Error
+}
+
+// toJobRunResponses 转换执行记录响应（批量加载触发人用户名）
+func toJobRunResponses(runs []models.JobRun) []dto.JobRunResponse {
+	userIDs := make([]uint, 0, len(runs))
+	for _, run := range runs {
+		if run.TriggeredBy != nil {
+			userIDs = append(userIDs, *run.TriggeredBy)
+		}
+	}
+	usernames := loadUsernames(userIDs)
+
+	responses := make([]dto.JobRunResponse, 0, len(runs))
+	for _, run := range runs {
+		resp := dto.JobRunResponse{
+			ID:          run.ID,
+			JobName:     run.JobName,
+			Trigger:     run.Trigger,
+			TriggeredBy: run.TriggeredBy,
+			Instance:    run.Instance,
+			Status:      run.Status,
+			StartedAt:   dto.ToResponseTime(run.StartedAt),
+			FinishedAt:  dto.PtrToResponseTime(run.FinishedAt),
+			DurationMs:  run.DurationMs,
+			Output:      run.Output,
+			Error:       run.Error,
+		}
+		if run.TriggeredBy != nil {
+			resp.TriggeredByName = usernames[*run.TriggeredBy]
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
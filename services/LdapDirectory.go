@@ -0,0 +1,315 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	errLdapInvalidCredentials = errors.New("用户名或密码错误")
+	errLdapUnavailable        = errors.New("目录服务不可用，请稍后重试")
+)
+
+// LdapEntry 目录中的用户条目（已按配置完成属性映射）
+type LdapEntry struct {
+	// 条目 DN
+	DN string
+	// 唯一标识（entryUUID / objectGUID，目录未返回时使用规范化的 DN）
+	ExternalID string
+	// 登录名
+	Login string
+	// 姓名
+	Name string
+	// 手机号
+	Mobile string
+	// 邮箱
+	Email string
+	// 职位
+	Title string
+	// 上级 DN
+	ManagerDN string
+}
+
+// LdapDirectory 目录访问接口（LDAP 服务由 ldapDirectoryFactory 创建，测试时可替换为内存实现）
+type LdapDirectory interface {
+	// Authenticate 按登录名查找用户并以该用户身份绑定校验密码
+	Authenticate(login, password string) (*LdapEntry, error)
+	// SearchUsers 查询根 DN 下全部符合过滤条件的用户
+	SearchUsers() ([]LdapEntry, error)
+}
+
+// ldapDirectoryFactory 创建目录访问实例
+var ldapDirectoryFactory = func(cfg config.LDAPConfig) LdapDirectory {
+	return &ldapDirectory{cfg: cfg}
+}
+
+// ldapDirectory 基于 go-ldap 的目录访问实现
+type ldapDirectory struct {
+	cfg config.LDAPConfig
+}
+
+// timeout 连接和查询超时
+func (d *ldapDirectory) timeout() time.Duration {
+	if d.cfg.TimeoutSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(d.cfg.TimeoutSeconds) * time.Second
+}
+
+// connect 建立连接并以服务账号绑定（未配置服务账号时匿名查询）
+func (d *ldapDirectory) connect() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: d.cfg.InsecureSkipVerify}
+	conn, err := ldap.DialURL(d.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: d.timeout()}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("连接目录服务失败: %w", err)
+	}
+	conn.SetTimeout(d.timeout())
+
+	if d.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("目录服务 StartTLS 失败: %w", err)
+		}
+	}
+	if d.cfg.BindDN != "" {
+		if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("目录服务账号绑定失败: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// attributes 查询用户时需要返回的属性
+func (d *ldapDirectory) attributes() []string {
+	attrs := []string{d.cfg.UIDAttr, d.cfg.LoginAttr, d.cfg.NameAttr, d.cfg.MobileAttr,
+		d.cfg.EmailAttr, d.cfg.TitleAttr, d.cfg.ManagerAttr}
+	result := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr != "" {
+			result = append(result, attr)
+		}
+	}
+	return result
+}
+
+// Authenticate 先用服务账号按登录名查出用户 DN，再以用户身份绑定校验密码
+func (d *ldapDirectory) Authenticate(login, password string) (*LdapEntry, error) {
+	// 空密码会被服务端当作匿名绑定而成功，必须拒绝
+	if login == "" || password == "" {
+		return nil, errLdapInvalidCredentials
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	filter := fmt.Sprintf("(&%s(%s=%s))", d.cfg.UserFilter, d.cfg.LoginAttr, ldap.EscapeFilter(login))
+	result, err := conn.Search(ldap.NewSearchRequest(d.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(d.timeout()/time.Second), false, filter, d.attributes(), nil))
+	if err != nil {
+		return nil, fmt.Errorf("查询目录用户失败: %w", err)
+	}
+	if len(result.Entries) != 1 {
+		return nil, errLdapInvalidCredentials
+	}
+
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errLdapInvalidCredentials
+		}
+		return nil, fmt.Errorf("目录用户绑定失败: %w", err)
+	}
+
+	mapped := d.mapEntry(entry)
+	return &mapped, nil
+}
+
+// SearchUsers 分页查询根 DN 下的全部用户
+func (d *ldapDirectory) SearchUsers() ([]LdapEntry, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result, err := conn.SearchWithPaging(ldap.NewSearchRequest(d.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, d.cfg.UserFilter, d.attributes(), nil), 500)
+	if err != nil {
+		return nil, fmt.Errorf("查询目录用户失败: %w", err)
+	}
+
+	entries := make([]LdapEntry, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		entries = append(entries, d.mapEntry(entry))
+	}
+	return entries, nil
+}
+
+// mapEntry 按配置的属性映射转换目录条目
+func (d *ldapDirectory) mapEntry(entry *ldap.Entry) LdapEntry {
+	get := func(attr string) string {
+		if attr == "" {
+			return ""
+		}
+		return strings.TrimSpace(entry.GetEqualFoldAttributeValue(attr))
+	}
+
+	externalID := get(d.cfg.UIDAttr)
+	if externalID != "" && !utf8.ValidString(externalID) {
+		// objectGUID 等二进制属性转为十六进制
+		externalID = hex.EncodeToString(entry.GetRawAttributeValue(d.cfg.UIDAttr))
+	}
+	if externalID == "" {
+		externalID = normalizeDN(entry.DN)
+	}
+
+	return LdapEntry{
+		DN:         entry.DN,
+		ExternalID: externalID,
+		Login:      get(d.cfg.LoginAttr),
+		Name:       get(d.cfg.NameAttr),
+		Mobile:     get(d.cfg.MobileAttr),
+		Email:      get(d.cfg.EmailAttr),
+		Title:      get(d.cfg.TitleAttr),
+		ManagerDN:  get(d.cfg.ManagerAttr),
+	}
+}
+
+// joinRDNs 将 RDN 列表拼接为规范化的 DN（属性名和值转小写，用于比较和存储）
+func joinRDNs(rdns []*ldap.RelativeDN) string {
+	parts := make([]string, 0, len(rdns))
+	for _, rdn := range rdns {
+		attrs := make([]string, 0, len(rdn.Attributes))
+		for _, attr := range rdn.Attributes {
+			attrs = append(attrs, strings.ToLower(attr.Type)+"="+ldap.EscapeDN(strings.ToLower(attr.Value)))
+		}
+		sort.Strings(attrs)
+		parts = append(parts, strings.Join(attrs, "+"))
+	}
+	return strings.Join(parts, ",")
+}
+
+// normalizeDN 规范化 DN（无法解析时只做小写和去空格处理）
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(dn))
+	}
+	return joinRDNs(parsed.RDNs)
+}
+
+// ldapOU 用户所在的组织单位
+type ldapOU struct {
+	// 规范化的 OU DN（对应 departments.external_id）
+	DN string
+	// OU 名称（部门名称）
+	Name string
+}
+
+// ouChain 计算用户在根 DN 下所属的 OU 层级（从上级到下级），不在根 DN 下或直接位于根 DN 下时返回空
+// 非 OU 的容器（如 AD 的 CN=Users）跳过
+func ouChain(userDN, baseDN string) []ldapOU {
+	dn, err := ldap.ParseDN(userDN)
+	if err != nil {
+		return nil
+	}
+	base, err := ldap.ParseDN(baseDN)
+	if err != nil || !base.AncestorOfFold(dn) {
+		return nil
+	}
+
+	var chain []ldapOU
+	// RDNs[0] 为用户自身，RDNs[len-len(base)] 之后为根 DN
+	for i := len(dn.RDNs) - len(base.RDNs) - 1; i >= 1; i-- {
+		rdn := dn.RDNs[i]
+		if len(rdn.Attributes) != 1 || !strings.EqualFold(rdn.Attributes[0].Type, "ou") {
+			continue
+		}
+		chain = append(chain, ldapOU{
+			DN:   joinRDNs(dn.RDNs[i:]),
+			Name: rdn.Attributes[0].Value,
+		})
+	}
+	return chain
+}
+
+// userDepartmentDN 用户所属部门（最下级 OU）的 DN，不属于任何 OU 时为空
+func userDepartmentDN(userDN, baseDN string) string {
+	chain := ouChain(userDN, baseDN)
+	if len(chain) == 0 {
+		return ""
+	}
+	return chain[len(chain)-1].DN
+}
+
+// resolveDepartmentLeaders 根据上级关系计算各部门负责人，返回 部门DN -> 负责人DN 列表（均为规范化 DN）
+// 1. 有下属的用户是其所在部门的负责人；
+// 2. 部门内没有按规则 1 确定的负责人时，部门成员在部门外的上级为该部门负责人（如小组挂在上级部门经理名下）
+func resolveDepartmentLeaders(entries []LdapEntry, baseDN string) map[string][]string {
+	deptOf := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		deptOf[normalizeDN(entry.DN)] = userDepartmentDN(entry.DN, baseDN)
+	}
+
+	leaders := make(map[string]map[string]bool)
+	external := make(map[string]map[string]bool)
+	add := func(target map[string]map[string]bool, dept, manager string) {
+		if target[dept] == nil {
+			target[dept] = make(map[string]bool)
+		}
+		target[dept][manager] = true
+	}
+
+	for _, entry := range entries {
+		if entry.ManagerDN == "" {
+			continue
+		}
+		manager := normalizeDN(entry.ManagerDN)
+		managerDept, ok := deptOf[manager]
+		if !ok {
+			// 上级不在本次同步范围内
+			continue
+		}
+		if managerDept != "" {
+			add(leaders, managerDept, manager)
+		}
+		if dept := deptOf[normalizeDN(entry.DN)]; dept != "" && dept != managerDept {
+			add(external, dept, manager)
+		}
+	}
+	for dept, managers := range external {
+		if len(leaders[dept]) > 0 {
+			continue
+		}
+		for manager := range managers {
+			add(leaders, dept, manager)
+		}
+	}
+
+	result := make(map[string][]string, len(leaders))
+	for dept, managers := range leaders {
+		list := make([]string, 0, len(managers))
+		for manager := range managers {
+			list = append(list, manager)
+		}
+		sort.Strings(list)
+		result[dept] = list
+	}
+	return result
+}
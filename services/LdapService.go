@@ -0,0 +1,479 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var errLdapPasswordManaged = errors.New("目录账号的密码由公司 LDAP 目录管理，请在目录中修改")
+
+type LdapService struct{}
+
+// ldapConfig 获取已启用的 LDAP 配置
+func ldapConfig() (config.LDAPConfig, error) {
+	cfg := config.GetConfig().LDAP
+	if !cfg.Enabled {
+		return cfg, errors.New("未启用 LDAP 登录")
+	}
+	return cfg, nil
+}
+
+// Login LDAP 目录账号登录：以用户身份绑定校验密码，首次登录时自动创建系统账号
+func (s *LdapService) Login(req *dto.LdapLoginRequest, client LoginClient) (*dto.LoginResponse, error) {
+	cfg, err := ldapConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// 与手机号登录共用失败计数和锁定（账号键加前缀避免与手机号冲突）
+	account := ldapLoginAccount(req.Username)
+	if err := checkLoginLock(account, client.IP); err != nil {
+		return nil, err
+	}
+
+	entry, err := ldapDirectoryFactory(cfg).Authenticate(req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, errLdapInvalidCredentials) {
+			recordLoginFailure(nil, account, client.IP)
+			return nil, err
+		}
+		utils.Logger.Errorf("LDAP 登录失败: %v", err)
+		return nil, errLdapUnavailable
+	}
+	clearLoginFailures(account)
+
+	user, _, err := upsertLdapUser(entry, cfg.BaseDN, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status == models.UserStatusDisabled {
+		return nil, errors.New("用户已被禁用")
+	}
+	if user.Status == models.UserStatusPending {
+		return nil, errors.New("用户待审核，请联系管理员")
+	}
+
+	if err := database.DB.Preload("Roles.Permissions").Preload("Department").Preload("ManagedDepartments").
+		First(user, user.ID).Error; err != nil {
+		return nil, err
+	}
+	response := &dto.LoginResponse{UserInfo: buildLoginUserInfo(user)}
+	client.Device = req.Device
+	if err := completeLogin(user, response, client, models.LoginMethodLDAP); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// ldapLoginAccount LDAP 登录失败计数和锁定使用的账号键
+func ldapLoginAccount(login string) string {
+	return "ldap:" + strings.ToLower(login)
+}
+
+// ldapUserChange 目录用户写入系统账号的结果
+type ldapUserChange int
+
+const (
+	ldapUserUnchanged ldapUserChange = iota
+	ldapUserCreated
+	ldapUserLinked
+	ldapUserUpdated
+)
+
+// upsertLdapUser 将目录用户写入系统账号：按唯一标识匹配已同步的账号，否则按手机号关联已有的本地账号，都没有时创建新账号
+// deptIDs 为 OU DN 到部门ID 的映射（同步时预先建好），为空时按需创建用户所在的部门
+func upsertLdapUser(entry *LdapEntry, baseDN string, deptIDs map[string]uint) (*models.User, ldapUserChange, error) {
+	var deptID *uint
+	if chain := ouChain(entry.DN, baseDN); len(chain) > 0 {
+		if deptIDs == nil {
+			ids, _, _, err := ensureLdapDepartments(chain)
+			if err != nil {
+				return nil, ldapUserUnchanged, err
+			}
+			deptIDs = ids
+		}
+		if id, ok := deptIDs[chain[len(chain)-1].DN]; ok {
+			deptID = &id
+		}
+	}
+
+	var user models.User
+	err := database.DB.Where("auth_source = ? AND external_id = ?", models.UserAuthSourceLDAP, entry.ExternalID).First(&user).Error
+	if err == nil {
+		changed, err := applyLdapProfile(&user, entry, deptID)
+		if err != nil || !changed {
+			return &user, ldapUserUnchanged, err
+		}
+		return &user, ldapUserUpdated, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ldapUserUnchanged, err
+	}
+
+	// 手机号是系统登录账号，目录中没有手机号时无法创建或关联
+	if entry.Mobile == "" {
+		return nil, ldapUserUnchanged, errors.New("目录中未登记手机号，请联系管理员")
+	}
+
+	// 手机号已存在，关联到现有账号（此后由目录管理密码和基本信息）
+	if err := database.DB.Where("mobile = ?", entry.Mobile).First(&user).Error; err == nil {
+		// 已关联其他目录账号的不能重新关联，避免目录中手机号变更后接管他人账号
+		if (user.AuthSource != "" && user.AuthSource != models.UserAuthSourceLocal) || user.ExternalID != "" {
+			return nil, ldapUserUnchanged, errors.New("该手机号的账号已关联其他目录账号")
+		}
+		// 本地密码作废，此后只能通过目录登录
+		randomPassword, err := newRefreshTokenValue()
+		if err != nil {
+			return nil, ldapUserUnchanged, err
+		}
+		if err := user.SetPassword(randomPassword); err != nil {
+			return nil, ldapUserUnchanged, err
+		}
+		if err := database.DB.Model(&user).Updates(map[string]interface{}{
+			"auth_source":    models.UserAuthSourceLDAP,
+			"external_id":    entry.ExternalID,
+			"external_login": entry.Login,
+			"password":       user.Password,
+		}).Error; err != nil {
+			return nil, ldapUserUnchanged, err
+		}
+		if _, err := applyLdapProfile(&user, entry, deptID); err != nil {
+			return nil, ldapUserUnchanged, err
+		}
+		return &user, ldapUserLinked, nil
+	}
+
+	created, err := createLdapUser(entry, deptID)
+	if err != nil {
+		return nil, ldapUserUnchanged, err
+	}
+	return created, ldapUserCreated, nil
+}
+
+// createLdapUser 创建目录用户对应的系统账号（本地密码为随机值，只能通过目录登录），并分配默认角色
+func createLdapUser(entry *LdapEntry, deptID *uint) (*models.User, error) {
+	randomPassword, err := newRefreshTokenValue()
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Mobile:        entry.Mobile,
+		Username:      ldapDisplayName(entry),
+		Email:         entry.Email,
+		Status:        models.UserStatusActive,
+		JobTitle:      truncateRunes(entry.Title, 100),
+		DepartmentID:  deptID,
+		AuthSource:    models.UserAuthSourceLDAP,
+		ExternalID:    entry.ExternalID,
+		ExternalLogin: entry.Login,
+	}
+	if err := user.SetPassword(randomPassword); err != nil {
+		return nil, err
+	}
+
+	db := database.DB
+	if !ldapEmailAvailable(entry.Email, 0) {
+		// 邮箱唯一，为空或已被其他账号使用时不写入
+		user.Email = ""
+		db = db.Omit("Email")
+	}
+	if err := db.Create(user).Error; err != nil {
+		return nil, err
+	}
+
+	var userRole models.Role
+	if err := database.DB.Where("name = ?", "user").First(&userRole).Error; err == nil {
+		database.DB.Model(user).Association("Roles").Append(&userRole)
+	}
+	return user, nil
+}
+
+// ldapDisplayName 目录用户的姓名（没有姓名属性时使用登录名）
+func ldapDisplayName(entry *LdapEntry) string {
+	name := entry.Name
+	if name == "" {
+		name = entry.Login
+	}
+	return truncateRunes(name, 50)
+}
+
+// ldapEmailAvailable 邮箱是否可以写入（非空且未被其他账号使用）
+func ldapEmailAvailable(email string, userID uint) bool {
+	if email == "" {
+		return false
+	}
+	var count int64
+	database.DB.Model(&models.User{}).Where("email = ? AND id <> ?", email, userID).Count(&count)
+	return count == 0
+}
+
+// applyLdapProfile 用目录信息更新账号的姓名、邮箱、职位和部门，返回是否有变化
+func applyLdapProfile(user *models.User, entry *LdapEntry, deptID *uint) (bool, error) {
+	updates := map[string]interface{}{}
+	if name := ldapDisplayName(entry); name != "" && name != user.Username {
+		updates["username"] = name
+	}
+	if title := truncateRunes(entry.Title, 100); title != user.JobTitle {
+		updates["job_title"] = title
+	}
+	if entry.Email != "" && entry.Email != user.Email && ldapEmailAvailable(entry.Email, user.ID) {
+		updates["email"] = entry.Email
+	}
+	if entry.Login != "" && entry.Login != user.ExternalLogin {
+		updates["external_login"] = entry.Login
+	}
+	// 用户不在任何 OU 下时保留系统中的部门
+	if deptID != nil && (user.DepartmentID == nil || *user.DepartmentID != *deptID) {
+		updates["department_id"] = *deptID
+	}
+	if len(updates) == 0 {
+		return false, nil
+	}
+	if err := database.DB.Model(user).Updates(updates).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ensureLdapDepartments 确保 OU 层级对应的部门存在（按 external_id 匹配），名称或上级变化时同步更新
+// 返回 OU DN 到部门ID 的映射以及新建、更新的部门数
+func ensureLdapDepartments(chain []ldapOU) (map[string]uint, int, int, error) {
+	ids := make(map[string]uint, len(chain))
+	created, updated := 0, 0
+	var parentID *uint
+	for _, ou := range chain {
+		var dept models.Department
+		err := database.DB.Where("external_id = ?", ou.DN).First(&dept).Error
+		switch {
+		case err == nil:
+			updates := map[string]interface{}{}
+			if dept.Name != ou.Name {
+				updates["name"] = ou.Name
+			}
+			if !sameParent(dept.ParentID, parentID) {
+				updates["parent_id"] = parentID
+			}
+			if len(updates) > 0 {
+				if err := database.DB.Model(&dept).Updates(updates).Error; err != nil {
+					return nil, created, updated, err
+				}
+				updated++
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			dept = models.Department{
+				Name:        truncateRunes(ou.Name, 100),
+				Description: "LDAP 同步",
+				ParentID:    parentID,
+				Status:      1,
+				ExternalID:  ou.DN,
+			}
+			if err := database.DB.Create(&dept).Error; err != nil {
+				return nil, created, updated, err
+			}
+			created++
+		default:
+			return nil, created, updated, err
+		}
+
+		ids[ou.DN] = dept.ID
+		id := dept.ID
+		parentID = &id
+	}
+	return ids, created, updated, nil
+}
+
+// sameParent 比较两个可空的上级部门ID
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// Sync 同步目录：OU 映射为部门，上级关系映射为部门负责人，写入目录用户并禁用已从目录移除的用户
+// ctx 取消或超时后停止同步剩余的用户
+func (s *LdapService) Sync(ctx context.Context) (*dto.LdapSyncResult, error) {
+	cfg, err := ldapConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ldapDirectoryFactory(cfg).SearchUsers()
+	if err != nil {
+		return nil, err
+	}
+	// 查询结果为空多半是配置错误，此时不能把所有目录用户都禁用
+	if len(entries) == 0 {
+		return nil, errors.New("目录中没有查询到用户，已跳过同步，请检查 LDAP_BASE_DN 和 LDAP_USER_FILTER")
+	}
+
+	result := &dto.LdapSyncResult{}
+
+	// 1. 部门
+	deptIDs := make(map[string]uint)
+	for _, entry := range entries {
+		chain := ouChain(entry.DN, cfg.BaseDN)
+		if len(chain) == 0 {
+			continue
+		}
+		if _, ok := deptIDs[chain[len(chain)-1].DN]; ok {
+			continue
+		}
+		ids, created, updated, err := ensureLdapDepartments(chain)
+		if err != nil {
+			return nil, fmt.Errorf("同步部门失败: %w", err)
+		}
+		for dn, id := range ids {
+			deptIDs[dn] = id
+		}
+		result.DepartmentsCreated += created
+		result.DepartmentsUpdated += updated
+	}
+
+	// 2. 用户
+	userIDs := make(map[string]uint, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for i := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entry := &entries[i]
+		seen[entry.ExternalID] = true
+
+		user, change, err := upsertLdapUser(entry, cfg.BaseDN, deptIDs)
+		if err != nil {
+			utils.Logger.Warnf("同步目录用户 %s 失败: %v", entry.DN, err)
+			result.UsersSkipped++
+			continue
+		}
+		userIDs[normalizeDN(entry.DN)] = user.ID
+		switch change {
+		case ldapUserCreated:
+			result.UsersCreated++
+		case ldapUserLinked:
+			result.UsersLinked++
+		case ldapUserUpdated:
+			result.UsersUpdated++
+		}
+	}
+
+	// 3. 部门负责人
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.syncLeaders(entries, cfg.BaseDN, deptIDs, userIDs, result); err != nil {
+		return nil, fmt.Errorf("同步部门负责人失败: %w", err)
+	}
+
+	// 4. 禁用已从目录移除的用户
+	if err := s.disableRemovedUsers(seen, result); err != nil {
+		return nil, fmt.Errorf("禁用已移除的目录用户失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// syncLeaders 按上级关系设置目录部门的负责人，并移除目录部门中不再是负责人的目录用户
+// 手工指定的本地账号负责人不受影响
+func (s *LdapService) syncLeaders(entries []LdapEntry, baseDN string, deptIDs map[string]uint, userIDs map[string]uint, result *dto.LdapSyncResult) error {
+	deptService := &DepartmentService{}
+	expected := make(map[uint]map[uint]bool)
+	for deptDN, managers := range resolveDepartmentLeaders(entries, baseDN) {
+		deptID, ok := deptIDs[deptDN]
+		if !ok {
+			continue
+		}
+		expected[deptID] = make(map[uint]bool)
+		for _, managerDN := range managers {
+			if userID, ok := userIDs[managerDN]; ok {
+				expected[deptID][userID] = true
+			}
+		}
+	}
+
+	for _, deptID := range deptIDs {
+		var current []models.DepartmentLeader
+		if err := database.DB.Where("department_id = ?", deptID).Find(&current).Error; err != nil {
+			return err
+		}
+		existing := make(map[uint]bool, len(current))
+		for _, leader := range current {
+			existing[leader.UserID] = true
+		}
+
+		for userID := range expected[deptID] {
+			if existing[userID] {
+				continue
+			}
+			if err := deptService.AddLeader(deptID, &dto.AddLeaderRequest{UserID: userID}); err != nil {
+				return err
+			}
+			result.LeadersAdded++
+		}
+
+		var staleIDs []uint
+		for _, leader := range current {
+			if !expected[deptID][leader.UserID] {
+				staleIDs = append(staleIDs, leader.UserID)
+			}
+		}
+		if len(staleIDs) == 0 {
+			continue
+		}
+		var ldapLeaders []uint
+		if err := database.DB.Model(&models.User{}).
+			Where("id IN ? AND auth_source = ?", staleIDs, models.UserAuthSourceLDAP).
+			Pluck("id", &ldapLeaders).Error; err != nil {
+			return err
+		}
+		for _, userID := range ldapLeaders {
+			if err := deptService.RemoveLeader(deptID, userID); err != nil {
+				return err
+			}
+			result.LeadersRemoved++
+		}
+	}
+	return nil
+}
+
+// disableRemovedUsers 禁用目录中已不存在的目录用户，并结束其登录会话
+func (s *LdapService) disableRemovedUsers(seen map[string]bool, result *dto.LdapSyncResult) error {
+	var users []models.User
+	if err := database.DB.Where("auth_source = ? AND status = ?", models.UserAuthSourceLDAP, models.UserStatusActive).
+		Find(&users).Error; err != nil {
+		return err
+	}
+
+	for i := range users {
+		user := &users[i]
+		if seen[user.ExternalID] {
+			continue
+		}
+		if err := database.DB.Model(user).Update("status", models.UserStatusDisabled).Error; err != nil {
+			return err
+		}
+		if err := revokeUserTokens(database.DB, user.ID, TokenRevokeDisabled); err != nil {
+			return err
+		}
+		recordAudit(&models.AuditLog{
+			Action: models.AuditActionDirectoryDisabled,
+			UserID: &user.ID,
+			Target: user.Mobile,
+			Detail: fmt.Sprintf("用户已从 LDAP 目录移除，同步时禁用（%s）", time.Now().Format("2006-01-02 15:04")),
+		})
+		result.UsersDisabled++
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/ratelimit"
+	"RHPRo-Task/utils"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LoginLockedError 登录失败次数过多被临时锁定
+type LoginLockedError struct {
+	// 剩余锁定时长
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请%s后重试", formatRetryAfter(e.RetryAfter))
+}
+
+// formatRetryAfter 将剩余锁定时长格式化为中文描述（向上取整）
+func formatRetryAfter(d time.Duration) string {
+	if d <= time.Minute {
+		seconds := int((d + time.Second - 1) / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		return fmt.Sprintf(" %d 秒", seconds)
+	}
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	return fmt.Sprintf(" %d 分钟", minutes)
+}
+
+// 登录失败计数和锁定标记的键
+func loginFailAccountKey(mobile string) string { return "login_fail:account:" + mobile }
+func loginFailIPKey(ip string) string          { return "login_fail:ip:" + ip }
+func loginLockAccountKey(mobile string) string { return "login_lock:account:" + mobile }
+func loginLockIPKey(ip string) string          { return "login_lock:ip:" + ip }
+
+type LoginGuardService struct{}
+
+// checkLoginLock 检查账号或IP是否处于锁定期（计数存储不可用时放行，只记录日志）
+func checkLoginLock(mobile, ip string) error {
+	store := ratelimit.GetStore()
+	for _, key := range []string{loginLockAccountKey(mobile), loginLockIPKey(ip)} {
+		ttl, err := store.TTL(key)
+		if err != nil {
+			utils.Logger.Warnf("检查登录锁定失败: %v", err)
+			return nil
+		}
+		if ttl > 0 {
+			return &LoginLockedError{RetryAfter: ttl}
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure 记录一次登录失败：账号和IP分别计数，达到阈值后按失败次数指数延长锁定时长
+// user 为空表示手机号未注册，此时只按手机号计数
+func recordLoginFailure(user *models.User, mobile, ip string) {
+	cfg := config.GetConfig().Security
+	window := time.Duration(cfg.LoginFailureWindowMinutes) * time.Minute
+	if window <= 0 {
+		return
+	}
+	base := time.Duration(cfg.LoginLockoutSeconds) * time.Second
+	max := time.Duration(cfg.LoginMaxLockoutMinutes) * time.Minute
+	store := ratelimit.GetStore()
+
+	var userID *uint
+	if user != nil {
+		userID = &user.ID
+	}
+
+	if cfg.LoginMaxFailures > 0 {
+		failures, _, err := store.Incr(loginFailAccountKey(mobile), window)
+		if err != nil {
+			utils.Logger.Warnf("记录登录失败次数失败: %v", err)
+			return
+		}
+		if lockout := ratelimit.LockoutDuration(failures, int64(cfg.LoginMaxFailures), base, max); lockout > 0 {
+			// 锁定期间保留失败计数，解锁后再次失败时锁定时长继续翻倍
+			if err := store.Set(loginLockAccountKey(mobile), lockout); err != nil {
+				utils.Logger.Warnf("设置账号登录锁定失败: %v", err)
+			}
+			store.Expire(loginFailAccountKey(mobile), lockout+window)
+			recordAudit(&models.AuditLog{
+				Action: models.AuditActionLoginLocked,
+				UserID: userID,
+				Target: mobile,
+				IP:     ip,
+				Detail: fmt.Sprintf("账号连续登录失败 %d 次，锁定%s", failures, formatRetryAfter(lockout)),
+			})
+		}
+	}
+
+	if cfg.LoginIPMaxFailures > 0 && ip != "" {
+		failures, _, err := store.Incr(loginFailIPKey(ip), window)
+		if err != nil {
+			utils.Logger.Warnf("记录登录失败次数失败: %v", err)
+			return
+		}
+		if lockout := ratelimit.LockoutDuration(failures, int64(cfg.LoginIPMaxFailures), base, max); lockout > 0 {
+			if err := store.Set(loginLockIPKey(ip), lockout); err != nil {
+				utils.Logger.Warnf("设置IP登录锁定失败: %v", err)
+			}
+			store.Expire(loginFailIPKey(ip), lockout+window)
+			recordAudit(&models.AuditLog{
+				Action: models.AuditActionLoginIPLocked,
+				Target: ip,
+				IP:     ip,
+				Detail: fmt.Sprintf("IP 登录失败 %d 次（最近一次账号 %s），锁定%s", failures, mobile, formatRetryAfter(lockout)),
+			})
+		}
+	}
+}
+
+// clearLoginFailures 登录成功后清除账号的失败计数（IP 计数不清除，避免用一个有效账号重置对其他账号的尝试）
+func clearLoginFailures(mobile string) {
+	if err := ratelimit.GetStore().Delete(loginFailAccountKey(mobile)); err != nil {
+		utils.Logger.Warnf("清除登录失败次数失败: %v", err)
+	}
+}
+
+// Unlock 管理员解除账号和/或IP的登录锁定，并清除失败计数
+func (s *LoginGuardService) Unlock(req *dto.UnlockLoginRequest, adminID uint, requestIP string) error {
+	if req.UserID == 0 && req.IP == "" {
+		return errors.New("请指定要解除锁定的用户或IP")
+	}
+	store := ratelimit.GetStore()
+
+	if req.UserID != 0 {
+		var user models.User
+		if err := database.DB.First(&user, req.UserID).Error; err != nil {
+			return errors.New("用户不存在")
+		}
+		keys := []string{loginFailAccountKey(user.Mobile), loginLockAccountKey(user.Mobile)}
+		// 目录账号同时解除按目录登录名计数的 LDAP 登录锁定
+		if user.ExternalLogin != "" {
+			account := ldapLoginAccount(user.ExternalLogin)
+			keys = append(keys, loginFailAccountKey(account), loginLockAccountKey(account))
+		}
+		if err := store.Delete(keys...); err != nil {
+			return err
+		}
+		recordAudit(&models.AuditLog{
+			Action:  models.AuditActionLoginUnlocked,
+			UserID:  &user.ID,
+			ActorID: &adminID,
+			Target:  user.Mobile,
+			IP:      requestIP,
+			Detail:  "管理员解除账号登录锁定",
+		})
+	}
+
+	if req.IP != "" {
+		if err := store.Delete(loginFailIPKey(req.IP), loginLockIPKey(req.IP)); err != nil {
+			return err
+		}
+		recordAudit(&models.AuditLog{
+			Action:  models.AuditActionLoginIPUnlocked,
+			ActorID: &adminID,
+			Target:  req.IP,
+			IP:      requestIP,
+			Detail:  "管理员解除IP登录锁定",
+		})
+	}
+	return nil
+}
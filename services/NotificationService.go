@@ -0,0 +1,41 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+)
+
+// 通知消息类型
+const (
+	NotificationTypeStatusChange  = "status_change"  // 任务状态变更
+	NotificationTypeReviewRequest = "review_request" // 审核请求
+)
+
+type NotificationService struct{}
+
+// Notify 向多个用户发送站内通知，重复的用户和 0 值会被忽略
+// 通知属于附带效果，发送失败只记录日志，不影响主流程
+func (s *NotificationService) Notify(userIDs []uint, taskID *uint, notificationType, title, content string) {
+	userIDs = uniqueUintSlice(userIDs)
+	notifications := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == 0 {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:  userID,
+			TaskID:  taskID,
+			Type:    notificationType,
+			Title:   title,
+			Content: content,
+		})
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err := database.DB.Create(&notifications).Error; err != nil {
+		utils.Logger.Warnf("发送通知失败: %v", err)
+	}
+}
@@ -0,0 +1,611 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/ratelimit"
+	"RHPRo-Task/utils"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const (
+	// oidcStateTTL 授权请求（state）有效期
+	oidcStateTTL = 10 * time.Minute
+	// oidcBindTokenTTL 绑定手机号临时凭证有效期
+	oidcBindTokenTTL = 10 * time.Minute
+	// oidcStateKeyPurpose state 签名密钥的派生用途（与访问令牌使用不同的密钥）
+	oidcStateKeyPurpose = "oidc-state"
+	// oidcBindKeyPurpose 绑定手机号临时凭证签名密钥的派生用途
+	oidcBindKeyPurpose = "oidc-bind"
+	// oidcHTTPTimeout 请求身份提供方（发现、令牌、JWKS）的超时
+	oidcHTTPTimeout = 10 * time.Second
+)
+
+var (
+	errOidcProviderNotFound = errors.New("未配置该单点登录方式")
+	errOidcUnavailable      = errors.New("身份提供方暂时不可用，请稍后重试")
+	errOidcStateInvalid     = errors.New("登录请求无效或已过期，请重新登录")
+	errOidcLoginFailed      = errors.New("单点登录失败，请重新登录")
+	errOidcBindTokenInvalid = errors.New("临时凭证无效或已过期")
+)
+
+var oidcMobilePattern = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+type OidcService struct{}
+
+// oidcClient 已完成发现的身份提供方客户端
+type oidcClient struct {
+	cfg      config.OIDCProviderConfig
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+var (
+	oidcClientsMu sync.Mutex
+	// oidcClients 按提供方缓存发现结果（JWKS 由 go-oidc 按 kid 自动刷新）
+	oidcClients = map[string]*oidcClient{}
+)
+
+// oidcContext 请求身份提供方使用的上下文（带超时的 HTTP 客户端）
+func oidcContext() context.Context {
+	return oidc.ClientContext(context.Background(), &http.Client{Timeout: oidcHTTPTimeout})
+}
+
+// findOidcProvider 按标识查找已配置的身份提供方
+func findOidcProvider(name string) (config.OIDCProviderConfig, bool) {
+	for _, provider := range config.GetConfig().OIDC.Providers {
+		if provider.Name == name {
+			return provider, true
+		}
+	}
+	return config.OIDCProviderConfig{}, false
+}
+
+// getOidcClient 获取身份提供方客户端，首次使用时通过 Issuer 发现端点（失败时不缓存，下次重试）
+func getOidcClient(name string) (*oidcClient, error) {
+	cfg, ok := findOidcProvider(name)
+	if !ok {
+		return nil, errOidcProviderNotFound
+	}
+
+	key := cfg.Name + "|" + cfg.IssuerURL + "|" + cfg.ClientID + "|" + cfg.RedirectURL
+	oidcClientsMu.Lock()
+	defer oidcClientsMu.Unlock()
+	if client, ok := oidcClients[key]; ok {
+		return client, nil
+	}
+
+	ctx := oidcContext()
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		utils.Logger.Errorf("OIDC 身份提供方 %s 发现失败: %v", cfg.Name, err)
+		return nil, errOidcUnavailable
+	}
+
+	scopes := []string{oidc.ScopeOpenID}
+	for _, scope := range cfg.Scopes {
+		if scope != oidc.ScopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+	client := &oidcClient{
+		cfg: cfg,
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scopes,
+		},
+		verifier: provider.VerifierContext(ctx, &oidc.Config{ClientID: cfg.ClientID}),
+	}
+	oidcClients[key] = client
+	return client, nil
+}
+
+// GetProviders 获取已配置的身份提供方列表
+func (s *OidcService) GetProviders() []dto.OidcProviderResponse {
+	providers := config.GetConfig().OIDC.Providers
+	result := make([]dto.OidcProviderResponse, 0, len(providers))
+	for _, provider := range providers {
+		result = append(result, dto.OidcProviderResponse{
+			Name:        provider.Name,
+			DisplayName: provider.DisplayName,
+		})
+	}
+	return result
+}
+
+// OidcStateClaims 授权请求的 state（派生密钥签名防篡改，不能当作访问令牌使用；PKCE code_verifier 和 nonce 由 state ID 派生，不经过浏览器）
+type OidcStateClaims struct {
+	Provider string `json:"provider"`
+	jwt.RegisteredClaims
+}
+
+// oidcDerive 由 state ID 派生 PKCE code_verifier 和 nonce（HMAC-SHA256，base64url 编码为 43 个字符）
+func oidcDerive(purpose, stateID string) string {
+	mac := hmac.New(sha256.New, []byte(config.GetConfig().JWT.Secret))
+	mac.Write([]byte("oidc:" + purpose + ":" + stateID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Authorize 生成身份提供方的授权地址（授权码 + PKCE）
+func (s *OidcService) Authorize(providerName string) (*dto.OidcAuthorizeResponse, error) {
+	client, err := getOidcClient(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := OidcStateClaims{
+		Provider: providerName,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcStateTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "RHPRo-Task-OIDC",
+		},
+	}
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(utils.DeriveSigningKey(oidcStateKeyPurpose))
+	if err != nil {
+		return nil, err
+	}
+
+	authURL := client.oauth2.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(oidcDerive("pkce", claims.ID)),
+		oidc.Nonce(oidcDerive("nonce", claims.ID)))
+	return &dto.OidcAuthorizeResponse{AuthorizationURL: authURL, State: state}, nil
+}
+
+// consumeOidcState 校验 state 的签名、有效期和所属提供方，并作废（每个授权请求只能完成一次登录）
+func consumeOidcState(state, providerName string) (*OidcStateClaims, error) {
+	token, err := jwt.ParseWithClaims(state, &OidcStateClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return utils.DeriveSigningKey(oidcStateKeyPurpose), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer("RHPRo-Task-OIDC"))
+	if err != nil {
+		return nil, errOidcStateInvalid
+	}
+	claims, ok := token.Claims.(*OidcStateClaims)
+	if !ok || !token.Valid || claims.ID == "" || claims.Provider != providerName {
+		return nil, errOidcStateInvalid
+	}
+
+	used, _, err := ratelimit.GetStore().Incr("oidc_state_used:"+claims.ID, oidcStateTTL)
+	if err != nil {
+		utils.Logger.Warnf("记录单点登录 state 使用失败: %v", err)
+	} else if used > 1 {
+		return nil, errOidcStateInvalid
+	}
+	return claims, nil
+}
+
+// oidcIdentity 通过 ID Token 校验的外部身份
+type oidcIdentity struct {
+	Provider       string
+	Subject        string
+	Name           string
+	Email          string
+	EmailVerified  bool
+	Mobile         string
+	MobileVerified bool
+	Department     string
+}
+
+// authenticate 用授权码换取令牌，校验 ID Token（签名、issuer、audience、有效期和 nonce）并映射 claim
+func (s *OidcService) authenticate(providerName, code, state string) (*oidcIdentity, error) {
+	client, err := getOidcClient(providerName)
+	if err != nil {
+		return nil, err
+	}
+	stateClaims, err := consumeOidcState(state, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := oidcContext()
+	token, err := client.oauth2.Exchange(ctx, code, oauth2.VerifierOption(oidcDerive("pkce", stateClaims.ID)))
+	if err != nil {
+		utils.Logger.Warnf("OIDC 身份提供方 %s 授权码换取令牌失败: %v", providerName, err)
+		return nil, errOidcLoginFailed
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		utils.Logger.Warnf("OIDC 身份提供方 %s 未返回 id_token", providerName)
+		return nil, errOidcLoginFailed
+	}
+
+	idToken, err := client.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		utils.Logger.Warnf("OIDC 身份提供方 %s 的 ID Token 校验失败: %v", providerName, err)
+		return nil, errOidcLoginFailed
+	}
+	if !hmac.Equal([]byte(idToken.Nonce), []byte(oidcDerive("nonce", stateClaims.ID))) {
+		utils.Logger.Warnf("OIDC 身份提供方 %s 的 ID Token nonce 不匹配", providerName)
+		return nil, errOidcLoginFailed
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, errOidcLoginFailed
+	}
+	return mapOidcClaims(client.cfg, idToken.Subject, claims), nil
+}
+
+// mapOidcClaims 按提供方配置把 ID Token 的 claim 映射为姓名、邮箱、手机号和部门
+func mapOidcClaims(cfg config.OIDCProviderConfig, subject string, claims map[string]interface{}) *oidcIdentity {
+	identity := &oidcIdentity{
+		Provider:   cfg.Name,
+		Subject:    subject,
+		Name:       truncateRunes(oidcClaimString(claims, cfg.NameClaim), 50),
+		Email:      strings.ToLower(oidcClaimString(claims, cfg.EmailClaim)),
+		Mobile:     normalizeOidcMobile(oidcClaimString(claims, cfg.MobileClaim)),
+		Department: oidcClaimString(claims, cfg.DepartmentClaim),
+	}
+	if identity.Name == "" {
+		identity.Name = truncateRunes(oidcClaimString(claims, "preferred_username"), 50)
+	}
+	identity.EmailVerified = identity.Email != "" && (cfg.TrustUnverifiedContacts || oidcClaimBool(claims, "email_verified"))
+	identity.MobileVerified = identity.Mobile != "" && (cfg.TrustUnverifiedContacts || oidcClaimBool(claims, "phone_number_verified"))
+	return identity
+}
+
+// oidcClaimString 读取字符串 claim（数组取第一个值，支持 a.b 形式的嵌套 claim）
+func oidcClaimString(claims map[string]interface{}, name string) string {
+	if name == "" {
+		return ""
+	}
+	var value interface{} = claims
+	for _, part := range strings.Split(name, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = m[part]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		for _, item := range v {
+			if str, ok := item.(string); ok && strings.TrimSpace(str) != "" {
+				return strings.TrimSpace(str)
+			}
+		}
+	}
+	return ""
+}
+
+// oidcClaimBool 读取布尔 claim（部分身份提供方以字符串 "true" 返回）
+func oidcClaimBool(claims map[string]interface{}, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// normalizeOidcMobile 规范化手机号（去掉空格、连字符和 +86 前缀），不是中国大陆手机号时返回空
+func normalizeOidcMobile(mobile string) string {
+	mobile = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(mobile)
+	if strings.HasPrefix(mobile, "+") {
+		// E.164 格式只接受中国大陆区号
+		if !strings.HasPrefix(mobile, "+86") {
+			return ""
+		}
+		mobile = mobile[3:]
+	} else if len(mobile) == 13 && strings.HasPrefix(mobile, "86") {
+		mobile = mobile[2:]
+	}
+	if !oidcMobilePattern.MatchString(mobile) {
+		return ""
+	}
+	return mobile
+}
+
+// Callback 单点登录回调：校验授权结果后登录已关联的账号；
+// 未关联时按已验证的邮箱、手机号关联已有账号，都没有匹配时返回临时凭证，需绑定手机号
+func (s *OidcService) Callback(providerName string, req *dto.OidcCallbackRequest, client LoginClient) (*dto.OidcLoginResponse, error) {
+	client.Device = req.Device
+	identity, err := s.authenticate(providerName, req.Code, req.State)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 已关联的外部身份
+	var link models.UserIdentity
+	err = database.DB.Where("provider = ? AND subject = ?", identity.Provider, identity.Subject).First(&link).Error
+	if err == nil {
+		var user models.User
+		if err := database.DB.First(&user, link.UserID).Error; err != nil {
+			return nil, errors.New("关联的账号不存在，请联系管理员")
+		}
+		return s.loginLinkedUser(&user, &link, identity, client)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 2. 按已验证的邮箱、手机号关联已有账号
+	if user := findOidcMatchedUser(identity); user != nil {
+		link, err := linkOidcIdentity(user, identity, client.IP)
+		if err != nil {
+			return nil, err
+		}
+		return s.loginLinkedUser(user, link, identity, client)
+	}
+
+	// 3. 需要绑定手机号
+	tempToken, err := generateOidcBindToken(identity)
+	if err != nil {
+		return nil, err
+	}
+	profile := &dto.OidcProfile{
+		Provider:   identity.Provider,
+		Name:       identity.Name,
+		Email:      identity.Email,
+		Department: identity.Department,
+	}
+	if identity.MobileVerified {
+		profile.Mobile = identity.Mobile
+	}
+	return &dto.OidcLoginResponse{NeedBind: true, TempToken: tempToken, Profile: profile}, nil
+}
+
+// findOidcMatchedUser 按已验证的邮箱、手机号查找已有账号（邮箱优先）
+func findOidcMatchedUser(identity *oidcIdentity) *models.User {
+	var user models.User
+	if identity.EmailVerified {
+		if err := database.DB.Where("LOWER(email) = ?", identity.Email).First(&user).Error; err == nil {
+			return &user
+		}
+	}
+	if identity.MobileVerified {
+		if err := database.DB.Where("mobile = ?", identity.Mobile).First(&user).Error; err == nil {
+			return &user
+		}
+	}
+	return nil
+}
+
+// linkOidcIdentity 把外部身份关联到账号并记录审计日志
+func linkOidcIdentity(user *models.User, identity *oidcIdentity, ip string) (*models.UserIdentity, error) {
+	link := &models.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+	if err := database.DB.Create(link).Error; err != nil {
+		return nil, err
+	}
+	recordAudit(&models.AuditLog{
+		Action: models.AuditActionIdentityLinked,
+		UserID: &user.ID,
+		Target: user.Mobile,
+		IP:     ip,
+		Detail: truncateRunes(fmt.Sprintf("关联单点登录身份：%s（%s）", identity.Provider, identity.Email), 500),
+	})
+	return link, nil
+}
+
+// loginLinkedUser 已关联外部身份的账号登录：补充部门后登记会话或进入两步验证
+func (s *OidcService) loginLinkedUser(user *models.User, link *models.UserIdentity, identity *oidcIdentity, client LoginClient) (*dto.OidcLoginResponse, error) {
+	if user.Status == models.UserStatusDisabled {
+		return nil, errors.New("用户已被禁用")
+	}
+	if user.Status == models.UserStatusPending {
+		return &dto.OidcLoginResponse{PendingApproval: true}, nil
+	}
+
+	now := time.Now()
+	database.DB.Model(link).Update("last_login_at", now)
+	// 部门以系统中的设置为准，只在账号未设置部门时按 claim 补充
+	if user.DepartmentID == nil {
+		if deptID := findOidcDepartment(identity.Department); deptID != nil {
+			database.DB.Model(user).Update("department_id", *deptID)
+		}
+	}
+
+	if err := database.DB.Preload("Roles.Permissions").Preload("Department").Preload("ManagedDepartments").
+		First(user, user.ID).Error; err != nil {
+		return nil, err
+	}
+	loginResp := &dto.LoginResponse{UserInfo: buildLoginUserInfo(user)}
+	if err := completeLogin(user, loginResp, client, models.LoginMethodOIDC); err != nil {
+		return nil, err
+	}
+	return &dto.OidcLoginResponse{
+		Token:                  loginResp.Token,
+		RefreshToken:           loginResp.RefreshToken,
+		ExpiresIn:              loginResp.ExpiresIn,
+		RefreshExpiresIn:       loginResp.RefreshExpiresIn,
+		UserInfo:               loginResp.UserInfo,
+		TwoFactorRequired:      loginResp.TwoFactorRequired,
+		TwoFactorSetupRequired: loginResp.TwoFactorSetupRequired,
+		TwoFactorToken:         loginResp.TwoFactorToken,
+	}, nil
+}
+
+// findOidcDepartment 按部门名称查找启用的部门
+func findOidcDepartment(name string) *uint {
+	if name == "" {
+		return nil
+	}
+	var dept models.Department
+	if err := database.DB.Where("name = ? AND status = ?", name, 1).Order("id ASC").First(&dept).Error; err != nil {
+		return nil
+	}
+	return &dept.ID
+}
+
+// OidcBindClaims 绑定手机号临时凭证（派生密钥签名，不能当作访问令牌使用）
+type OidcBindClaims struct {
+	Provider       string `json:"provider"`
+	Subject        string `json:"subject"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	EmailVerified  bool   `json:"email_verified"`
+	Mobile         string `json:"mobile"`
+	MobileVerified bool   `json:"mobile_verified"`
+	Department     string `json:"department"`
+	jwt.RegisteredClaims
+}
+
+// generateOidcBindToken 签发绑定手机号临时凭证
+func generateOidcBindToken(identity *oidcIdentity) (string, error) {
+	now := time.Now()
+	claims := OidcBindClaims{
+		Provider:       identity.Provider,
+		Subject:        identity.Subject,
+		Name:           identity.Name,
+		Email:          identity.Email,
+		EmailVerified:  identity.EmailVerified,
+		Mobile:         identity.Mobile,
+		MobileVerified: identity.MobileVerified,
+		Department:     identity.Department,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcBindTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "RHPRo-Task-OIDC-Bind",
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(utils.DeriveSigningKey(oidcBindKeyPurpose))
+}
+
+// parseOidcBindToken 解析绑定手机号临时凭证
+func parseOidcBindToken(tokenString string) (*OidcBindClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &OidcBindClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return utils.DeriveSigningKey(oidcBindKeyPurpose), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer("RHPRo-Task-OIDC-Bind"))
+	if err != nil {
+		return nil, errOidcBindTokenInvalid
+	}
+	claims, ok := token.Claims.(*OidcBindClaims)
+	if !ok || !token.Valid || claims.Provider == "" || claims.Subject == "" {
+		return nil, errOidcBindTokenInvalid
+	}
+	return claims, nil
+}
+
+// Bind 单点登录绑定手机号：手机号已注册时校验该账号的密码后关联，否则创建新账号（需管理员审核）
+func (s *OidcService) Bind(req *dto.OidcBindRequest, client LoginClient) (*dto.OidcLoginResponse, error) {
+	claims, err := parseOidcBindToken(req.TempToken)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := findOidcProvider(claims.Provider); !ok {
+		return nil, errOidcProviderNotFound
+	}
+	client.Device = req.Device
+
+	// 身份提供方已验证手机号时，必须使用该手机号
+	if claims.MobileVerified && req.Mobile != claims.Mobile {
+		return nil, errors.New("请使用身份提供方登记的手机号")
+	}
+
+	identity := &oidcIdentity{
+		Provider:   claims.Provider,
+		Subject:    claims.Subject,
+		Name:       claims.Name,
+		Email:      claims.Email,
+		Mobile:     claims.Mobile,
+		Department: claims.Department,
+	}
+	var count int64
+	database.DB.Model(&models.UserIdentity{}).Where("provider = ? AND subject = ?", claims.Provider, claims.Subject).Count(&count)
+	if count > 0 {
+		return nil, errors.New("该身份已关联账号，请重新登录")
+	}
+
+	// 手机号已注册：校验密码，防止仅凭外部身份接管他人账号（与密码登录共用失败计数和锁定）
+	var existingUser models.User
+	if err := database.DB.Where("mobile = ?", req.Mobile).First(&existingUser).Error; err == nil {
+		if err := checkLoginLock(req.Mobile, client.IP); err != nil {
+			return nil, err
+		}
+		// 目录账号的本地密码一律视为错误，不提示账号来源
+		if existingUser.AuthSource == models.UserAuthSourceLDAP || !existingUser.CheckPassword(req.Password) {
+			recordLoginFailure(&existingUser, req.Mobile, client.IP)
+			return nil, errors.New("该手机号已注册，密码错误，无法关联")
+		}
+		clearLoginFailures(req.Mobile)
+		link, err := linkOidcIdentity(&existingUser, identity, client.IP)
+		if err != nil {
+			return nil, err
+		}
+		return s.loginLinkedUser(&existingUser, link, identity, client)
+	}
+
+	// 创建新账号
+	user := &models.User{
+		Mobile:       req.Mobile,
+		Username:     req.UserName,
+		Email:        claims.Email,
+		Status:       models.UserStatusPending,
+		DepartmentID: findOidcDepartment(claims.Department),
+	}
+	if err := user.SetPassword(req.Password); err != nil {
+		return nil, err
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	create := tx
+	if !claims.EmailVerified || !ldapEmailAvailable(claims.Email, 0) {
+		// 邮箱唯一，未验证或已被其他账号使用时不写入
+		user.Email = ""
+		create = create.Omit("Email")
+	}
+	if err := create.Create(user).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: claims.Provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	var userRole models.Role
+	if err := tx.Where("name = ?", "user").First(&userRole).Error; err == nil {
+		if err := tx.Model(user).Association("Roles").Append(&userRole); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.OidcLoginResponse{PendingApproval: true}, nil
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// annotationTextFields 各批注对象可按文本片段批注的字段
+var annotationTextFields = map[string][]string{
+	models.AnnotationTargetSolution: {"title", "content", "mindmap_markdown"},
+	models.AnnotationTargetPlan:     {"title", "tech_stack", "resource_requirements", "risk_assessment"},
+}
+
+// annotationTarget 批注对象的某个版本（用于定位锚点）
+type annotationTarget struct {
+	Type    string
+	ID      uint
+	Version int
+	// 可批注的文本字段内容
+	Texts map[string]string
+	// 实施步骤 JSON（仅执行计划）
+	Steps []byte
+	// 目标列表（仅执行计划）
+	Goals []models.RequirementGoal
+}
+
+// solutionAnnotationTarget 构造思路方案版本的批注对象
+func solutionAnnotationTarget(solution *models.RequirementSolution) *annotationTarget {
+	return &annotationTarget{
+		Type:    models.AnnotationTargetSolution,
+		ID:      solution.ID,
+		Version: solution.Version,
+		Texts: map[string]string{
+			"title":            solution.Title,
+			"content":          solution.Content,
+			"mindmap_markdown": solution.MindmapMarkdown,
+		},
+	}
+}
+
+// loadPlanAnnotationTarget 构造执行计划版本的批注对象（含目标）
+func loadPlanAnnotationTarget(db *gorm.DB, plan *models.ExecutionPlan) *annotationTarget {
+	var goals []models.RequirementGoal
+	db.Where("execution_plan_id = ?", plan.ID).Order("goal_no ASC").Find(&goals)
+	return &annotationTarget{
+		Type:    models.AnnotationTargetPlan,
+		ID:      plan.ID,
+		Version: plan.Version,
+		Texts: map[string]string{
+			"title":                 plan.Title,
+			"tech_stack":            plan.TechStack,
+			"resource_requirements": plan.ResourceRequirements,
+			"risk_assessment":       plan.RiskAssessment,
+		},
+		Steps: plan.ImplementationSteps,
+		Goals: goals,
+	}
+}
+
+// isAnnotationTextField 字段是否可按文本片段批注
+func isAnnotationTextField(targetType, field string) bool {
+	for _, name := range annotationTextFields[targetType] {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// runeSubstring 按字符区间 [start, end) 截取文本
+func runeSubstring(text string, start, end int) (string, bool) {
+	runes := []rune(text)
+	if start < 0 || end > len(runes) || start >= end {
+		return "", false
+	}
+	return string(runes[start:end]), true
+}
+
+// locateQuotedText 在文本中查找原文，有多处时取起始位置离 near 最近的一处，返回字符区间
+func locateQuotedText(text, quoted string, near int) (int, int, bool) {
+	if quoted == "" {
+		return 0, 0, false
+	}
+	length := utf8.RuneCountInString(quoted)
+	best, bestDistance := -1, 0
+	searchFrom, runeOffset := 0, 0
+	for {
+		index := strings.Index(text[searchFrom:], quoted)
+		if index < 0 {
+			break
+		}
+		runeOffset += utf8.RuneCountInString(text[searchFrom : searchFrom+index])
+		distance := runeOffset - near
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = runeOffset, distance
+		}
+		// 从下一个字符继续查找（允许重叠）
+		_, size := utf8.DecodeRuneInString(text[searchFrom+index:])
+		searchFrom += index + size
+		runeOffset++
+	}
+	if best < 0 {
+		return 0, 0, false
+	}
+	return best, best + length, true
+}
+
+// parseJSONPath 解析 JSON 路径（如 steps[1].name），返回键（string）和下标（int）序列
+func parseJSONPath(path string) ([]interface{}, bool) {
+	if path == "" {
+		return nil, false
+	}
+	var tokens []interface{}
+	for _, segment := range strings.Split(path, ".") {
+		name, rest := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, rest = segment[:i], segment[i:]
+		}
+		if name != "" {
+			tokens = append(tokens, name)
+		} else if rest == "" {
+			return nil, false
+		}
+		for rest != "" {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, false
+			}
+			tokens = append(tokens, index)
+			rest = rest[end+1:]
+		}
+	}
+	return tokens, len(tokens) > 0
+}
+
+// jsonPathValue 获取 JSON 文档中路径对应的值
+func jsonPathValue(raw []byte, path string) (interface{}, bool) {
+	tokens, ok := parseJSONPath(path)
+	if !ok || len(raw) == 0 {
+		return nil, false
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false
+	}
+	for _, token := range tokens {
+		switch key := token.(type) {
+		case string:
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = object[key]; !ok {
+				return nil, false
+			}
+		case int:
+			list, ok := value.([]interface{})
+			if !ok || key >= len(list) {
+				return nil, false
+			}
+			value = list[key]
+		}
+	}
+	return value, true
+}
+
+// anchorNewAnnotation 按请求在版本上定位新批注的锚点
+func anchorNewAnnotation(annotation *models.ReviewAnnotation, req *dto.CreateAnnotationRequest, target *annotationTarget) error {
+	annotation.AnchorType = req.AnchorType
+	annotation.AnchorStatus = models.AnnotationAnchored
+
+	switch req.AnchorType {
+	case models.AnnotationAnchorText:
+		if !isAnnotationTextField(target.Type, req.Field) {
+			return errors.New("不支持批注的字段")
+		}
+		quoted, ok := runeSubstring(target.Texts[req.Field], req.StartOffset, req.EndOffset)
+		if !ok {
+			return errors.New("批注的文本区间无效")
+		}
+		if req.QuotedText != "" && req.QuotedText != quoted {
+			return errors.New("选中的文本与版本内容不一致")
+		}
+		annotation.Field = req.Field
+		annotation.StartOffset = req.StartOffset
+		annotation.EndOffset = req.EndOffset
+		annotation.QuotedText = quoted
+	case models.AnnotationAnchorStep:
+		if target.Type != models.AnnotationTargetPlan {
+			return errors.New("只有执行计划可以按实施步骤批注")
+		}
+		value, ok := jsonPathValue(target.Steps, req.StepPath)
+		if !ok {
+			return errors.New("实施步骤不存在")
+		}
+		snapshot, _ := json.Marshal(value)
+		annotation.StepPath = req.StepPath
+		annotation.QuotedText = string(snapshot)
+	case models.AnnotationAnchorGoal:
+		if target.Type != models.AnnotationTargetPlan {
+			return errors.New("只有执行计划可以按目标批注")
+		}
+		var goal *models.RequirementGoal
+		for i := range target.Goals {
+			if target.Goals[i].ID == req.GoalID {
+				goal = &target.Goals[i]
+				break
+			}
+		}
+		if goal == nil {
+			return errors.New("目标不存在或不属于该版本")
+		}
+		annotation.GoalID = &goal.ID
+		annotation.GoalNo = goal.GoalNo
+		annotation.QuotedText = goal.Title
+	default:
+		return errors.New("不支持的锚点类型")
+	}
+	return nil
+}
+
+// reanchorAnnotation 将批注转移到新版本并重新定位锚点，找不到原锚点时标记为 outdated
+// oldGoal 为目标锚点当前指向的目标（其他锚点类型传 nil）
+func reanchorAnnotation(annotation *models.ReviewAnnotation, oldGoal *models.RequirementGoal, target *annotationTarget) {
+	annotation.TargetID = target.ID
+	annotation.Version = target.Version
+	annotation.AnchorStatus = models.AnnotationOutdated
+
+	switch annotation.AnchorType {
+	case models.AnnotationAnchorText:
+		if start, end, ok := locateQuotedText(target.Texts[annotation.Field], annotation.QuotedText, annotation.StartOffset); ok {
+			annotation.StartOffset = start
+			annotation.EndOffset = end
+			annotation.AnchorStatus = models.AnnotationAnchored
+		}
+	case models.AnnotationAnchorStep:
+		if _, ok := jsonPathValue(target.Steps, annotation.StepPath); ok {
+			annotation.AnchorStatus = models.AnnotationAnchored
+		}
+	case models.AnnotationAnchorGoal:
+		if oldGoal == nil {
+			return
+		}
+		for j, i := range matchGoals([]models.RequirementGoal{*oldGoal}, target.Goals) {
+			if i == 0 {
+				annotation.GoalID = &target.Goals[j].ID
+				annotation.GoalNo = target.Goals[j].GoalNo
+				annotation.AnchorStatus = models.AnnotationAnchored
+			}
+		}
+	}
+}
+
+// carryAnnotation 转移一条批注到新版本并保存
+func carryAnnotation(tx *gorm.DB, annotation *models.ReviewAnnotation, target *annotationTarget, now time.Time) error {
+	var oldGoal *models.RequirementGoal
+	if annotation.AnchorType == models.AnnotationAnchorGoal && annotation.GoalID != nil {
+		var goal models.RequirementGoal
+		if err := tx.First(&goal, *annotation.GoalID).Error; err == nil {
+			oldGoal = &goal
+		}
+	}
+	reanchorAnnotation(annotation, oldGoal, target)
+	annotation.CarriedAt = &now
+
+	return tx.Model(annotation).Updates(map[string]interface{}{
+		"target_id":     annotation.TargetID,
+		"version":       annotation.Version,
+		"anchor_status": annotation.AnchorStatus,
+		"start_offset":  annotation.StartOffset,
+		"end_offset":    annotation.EndOffset,
+		"goal_id":       annotation.GoalID,
+		"goal_no":       annotation.GoalNo,
+		"carried_at":    now,
+	}).Error
+}
+
+// carryOverAnnotations 提交新版本时将之前版本上未解决的批注转移到新版本
+func carryOverAnnotations(tx *gorm.DB, taskID uint, target *annotationTarget, now time.Time) error {
+	var annotations []models.ReviewAnnotation
+	if err := tx.Where("task_id = ? AND target_type = ? AND version < ? AND is_resolved = ?",
+		taskID, target.Type, target.Version, false).
+		Find(&annotations).Error; err != nil {
+		return err
+	}
+	for i := range annotations {
+		if err := carryAnnotation(tx, &annotations[i], target, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
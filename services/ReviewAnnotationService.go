@@ -0,0 +1,369 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationTypeReviewAnnotation 审核批注通知类型
+const NotificationTypeReviewAnnotation = "review_annotation"
+
+type ReviewAnnotationService struct{}
+
+// canAnnotateTask 是否可以在任务上批注/回复：创建人、执行人、陪审团成员/评审专家、任务所属部门负责人
+func canAnnotateTask(db *gorm.DB, task *models.Task, userID uint) bool {
+	if task.CreatorID == userID || (task.ExecutorID != nil && *task.ExecutorID == userID) {
+		return true
+	}
+	var count int64
+	db.Model(&models.TaskParticipant{}).
+		Where("task_id = ? AND user_id = ? AND role IN ?", task.ID, userID, reviewVoterRoles).
+		Count(&count)
+	if count > 0 {
+		return true
+	}
+	return departmentLeaderSet(db, task.DepartmentID)[userID]
+}
+
+// canResolveAnnotation 是否可以解决/重新打开批注：批注人、任务创建人、执行人
+func canResolveAnnotation(task *models.Task, annotation *models.ReviewAnnotation, userID uint) bool {
+	return annotation.AuthorID == userID || task.CreatorID == userID ||
+		(task.ExecutorID != nil && *task.ExecutorID == userID)
+}
+
+// loadAnnotationTarget 加载批注对象的指定版本（version 为 0 时为最新版本）
+func loadAnnotationTarget(db *gorm.DB, taskID uint, targetType string, version int) (*annotationTarget, error) {
+	switch targetType {
+	case models.AnnotationTargetSolution:
+		var solution models.RequirementSolution
+		query := db.Where("task_id = ?", taskID)
+		if version > 0 {
+			query = query.Where("version = ?", version)
+		}
+		if err := query.Order("version DESC").First(&solution).Error; err != nil {
+			return nil, errors.New("方案版本不存在")
+		}
+		return solutionAnnotationTarget(&solution), nil
+	case models.AnnotationTargetPlan:
+		var plan models.ExecutionPlan
+		query := db.Where("task_id = ?", taskID)
+		if version > 0 {
+			query = query.Where("version = ?", version)
+		}
+		if err := query.Order("version DESC").First(&plan).Error; err != nil {
+			return nil, errors.New("执行计划版本不存在")
+		}
+		return loadPlanAnnotationTarget(db, &plan), nil
+	}
+	return nil, errors.New("不支持的批注对象")
+}
+
+// CreateAnnotation 在方案/执行计划的某个版本上创建批注
+func (s *ReviewAnnotationService) CreateAnnotation(taskID uint, userID uint, req *dto.CreateAnnotationRequest) (*dto.ReviewAnnotationResponse, error) {
+	var task models.Task
+	if err := database.DB.First(&task, taskID).Error; err != nil {
+		return nil, errors.New("任务不存在")
+	}
+	if !canAnnotateTask(database.DB, &task, userID) {
+		return nil, errors.New("只有任务创建人、执行人、审核成员和部门负责人可以批注")
+	}
+
+	target, err := loadAnnotationTarget(database.DB, taskID, req.TargetType, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := &models.ReviewAnnotation{
+		TaskID:         taskID,
+		TargetType:     target.Type,
+		TargetID:       target.ID,
+		Version:        target.Version,
+		OriginTargetID: target.ID,
+		OriginVersion:  target.Version,
+		AuthorID:       userID,
+		Content:        req.Content,
+	}
+	if err := anchorNewAnnotation(annotation, req, target); err != nil {
+		return nil, err
+	}
+
+	// 关联该版本进行中的审核会话
+	var session models.ReviewSession
+	if err := database.DB.Where("target_type = ? AND target_id = ? AND status = ?", target.Type, target.ID, "in_review").
+		Order("id DESC").First(&session).Error; err == nil {
+		annotation.ReviewSessionID = &session.ID
+	}
+
+	if err := database.DB.Create(annotation).Error; err != nil {
+		return nil, err
+	}
+
+	if task.ExecutorID != nil && *task.ExecutorID != userID {
+		content := fmt.Sprintf("任务「%s」的%s（版本 v%d）收到新的批注：%s", task.Title, annotationTargetName(target.Type), target.Version, req.Content)
+		(&NotificationService{}).Notify([]uint{*task.ExecutorID}, &task.ID, NotificationTypeReviewAnnotation, "新的审核批注", content)
+	}
+
+	responses := toAnnotationResponses([]models.ReviewAnnotation{*annotation})
+	return &responses[0], nil
+}
+
+// GetAnnotations 查询任务的批注（含回复），按锚定版本和创建时间排序
+func (s *ReviewAnnotationService) GetAnnotations(taskID uint, query *dto.AnnotationQuery) ([]dto.ReviewAnnotationResponse, error) {
+	db := database.DB.Where("task_id = ?", taskID)
+	if query.TargetType != "" {
+		db = db.Where("target_type = ?", query.TargetType)
+	}
+	if query.Version > 0 {
+		db = db.Where("version = ?", query.Version)
+	}
+	switch query.Status {
+	case "open":
+		db = db.Where("is_resolved = ?", false)
+	case "resolved":
+		db = db.Where("is_resolved = ?", true)
+	}
+
+	var annotations []models.ReviewAnnotation
+	if err := db.Preload("Replies", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Order("target_type ASC, version DESC, created_at ASC").Find(&annotations).Error; err != nil {
+		return nil, err
+	}
+	return toAnnotationResponses(annotations), nil
+}
+
+// ReplyAnnotation 回复批注
+func (s *ReviewAnnotationService) ReplyAnnotation(annotationID uint, userID uint, req *dto.AnnotationReplyRequest) (*dto.AnnotationReplyResponse, error) {
+	var annotation models.ReviewAnnotation
+	if err := database.DB.First(&annotation, annotationID).Error; err != nil {
+		return nil, errors.New("批注不存在")
+	}
+	var task models.Task
+	if err := database.DB.First(&task, annotation.TaskID).Error; err != nil {
+		return nil, errors.New("任务不存在")
+	}
+	if !canAnnotateTask(database.DB, &task, userID) {
+		return nil, errors.New("只有任务创建人、执行人、审核成员和部门负责人可以回复批注")
+	}
+
+	reply := &models.ReviewAnnotationReply{
+		AnnotationID: annotation.ID,
+		AuthorID:     userID,
+		Content:      req.Content,
+	}
+	if err := database.DB.Create(reply).Error; err != nil {
+		return nil, err
+	}
+
+	// 通知批注人、执行人和此前参与回复的人
+	var replierIDs []uint
+	database.DB.Model(&models.ReviewAnnotationReply{}).Where("annotation_id = ?", annotation.ID).Pluck("author_id", &replierIDs)
+	recipients := append([]uint{annotation.AuthorID}, replierIDs...)
+	if task.ExecutorID != nil {
+		recipients = append(recipients, *task.ExecutorID)
+	}
+	filtered := make([]uint, 0, len(recipients))
+	for _, id := range recipients {
+		if id != userID {
+			filtered = append(filtered, id)
+		}
+	}
+	content := fmt.Sprintf("任务「%s」的批注「%s」有新的回复：%s", task.Title, annotation.Content, req.Content)
+	(&NotificationService{}).Notify(filtered, &task.ID, NotificationTypeReviewAnnotation, "批注回复", content)
+
+	return &dto.AnnotationReplyResponse{
+		ID:         reply.ID,
+		AuthorID:   reply.AuthorID,
+		AuthorName: loadUsernames([]uint{userID})[userID],
+		Content:    reply.Content,
+		CreatedAt:  dto.ToResponseTime(reply.CreatedAt),
+	}, nil
+}
+
+// ResolveAnnotation 将批注标记为已解决
+func (s *ReviewAnnotationService) ResolveAnnotation(annotationID uint, userID uint) error {
+	annotation, task, err := loadAnnotationWithTask(annotationID)
+	if err != nil {
+		return err
+	}
+	if !canResolveAnnotation(task, annotation, userID) {
+		return errors.New("只有批注人、任务创建人和执行人可以解决批注")
+	}
+	if annotation.IsResolved {
+		return errors.New("批注已解决")
+	}
+
+	now := time.Now()
+	return database.DB.Model(annotation).Updates(map[string]interface{}{
+		"is_resolved": true,
+		"resolved_by": userID,
+		"resolved_at": now,
+	}).Error
+}
+
+// UnresolveAnnotation 重新打开已解决的批注；已有更新的版本时转移到最新版本
+func (s *ReviewAnnotationService) UnresolveAnnotation(annotationID uint, userID uint) error {
+	annotation, task, err := loadAnnotationWithTask(annotationID)
+	if err != nil {
+		return err
+	}
+	if !canResolveAnnotation(task, annotation, userID) {
+		return errors.New("只有批注人、任务创建人和执行人可以重新打开批注")
+	}
+	if !annotation.IsResolved {
+		return errors.New("批注未解决")
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(annotation).Updates(map[string]interface{}{
+		"is_resolved": false,
+		"resolved_by": nil,
+		"resolved_at": nil,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	target, err := loadAnnotationTarget(tx, annotation.TaskID, annotation.TargetType, 0)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if target.Version > annotation.Version {
+		if err := carryAnnotation(tx, annotation, target, time.Now()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteAnnotation 删除批注（仅批注人，已有他人回复时不能删除）
+func (s *ReviewAnnotationService) DeleteAnnotation(annotationID uint, userID uint) error {
+	var annotation models.ReviewAnnotation
+	if err := database.DB.First(&annotation, annotationID).Error; err != nil {
+		return errors.New("批注不存在")
+	}
+	if annotation.AuthorID != userID {
+		return errors.New("只能删除自己的批注")
+	}
+
+	var count int64
+	database.DB.Model(&models.ReviewAnnotationReply{}).
+		Where("annotation_id = ? AND author_id <> ?", annotation.ID, userID).
+		Count(&count)
+	if count > 0 {
+		return errors.New("批注已有他人回复，不能删除")
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("annotation_id = ?", annotation.ID).Delete(&models.ReviewAnnotationReply{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&annotation).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// loadAnnotationWithTask 加载批注及其任务
+func loadAnnotationWithTask(annotationID uint) (*models.ReviewAnnotation, *models.Task, error) {
+	var annotation models.ReviewAnnotation
+	if err := database.DB.First(&annotation, annotationID).Error; err != nil {
+		return nil, nil, errors.New("批注不存在")
+	}
+	var task models.Task
+	if err := database.DB.First(&task, annotation.TaskID).Error; err != nil {
+		return nil, nil, errors.New("任务不存在")
+	}
+	return &annotation, &task, nil
+}
+
+// annotationTargetName 批注对象名称
+func annotationTargetName(targetType string) string {
+	if targetType == models.AnnotationTargetPlan {
+		return "执行计划"
+	}
+	return "思路方案"
+}
+
+// toAnnotationResponses 转换批注响应（批量加载用户名）
+func toAnnotationResponses(annotations []models.ReviewAnnotation) []dto.ReviewAnnotationResponse {
+	var userIDs []uint
+	for _, annotation := range annotations {
+		userIDs = append(userIDs, annotation.AuthorID)
+		if annotation.ResolvedBy != nil {
+			userIDs = append(userIDs, *annotation.ResolvedBy)
+		}
+		for _, reply := range annotation.Replies {
+			userIDs = append(userIDs, reply.AuthorID)
+		}
+	}
+	names := loadUsernames(userIDs)
+
+	responses := make([]dto.ReviewAnnotationResponse, 0, len(annotations))
+	for _, annotation := range annotations {
+		response := dto.ReviewAnnotationResponse{
+			ID:              annotation.ID,
+			TaskID:          annotation.TaskID,
+			TargetType:      annotation.TargetType,
+			TargetID:        annotation.TargetID,
+			Version:         annotation.Version,
+			OriginVersion:   annotation.OriginVersion,
+			ReviewSessionID: annotation.ReviewSessionID,
+			AnchorType:      annotation.AnchorType,
+			AnchorStatus:    annotation.AnchorStatus,
+			Field:           annotation.Field,
+			StartOffset:     annotation.StartOffset,
+			EndOffset:       annotation.EndOffset,
+			QuotedText:      annotation.QuotedText,
+			StepPath:        annotation.StepPath,
+			GoalID:          annotation.GoalID,
+			GoalNo:          annotation.GoalNo,
+			AuthorID:        annotation.AuthorID,
+			AuthorName:      names[annotation.AuthorID],
+			Content:         annotation.Content,
+			IsResolved:      annotation.IsResolved,
+			ResolvedBy:      annotation.ResolvedBy,
+			ResolvedAt:      dto.PtrToResponseTime(annotation.ResolvedAt),
+			CarriedAt:       dto.PtrToResponseTime(annotation.CarriedAt),
+			CreatedAt:       dto.ToResponseTime(annotation.CreatedAt),
+			Replies:         make([]dto.AnnotationReplyResponse, 0, len(annotation.Replies)),
+		}
+		if annotation.ResolvedBy != nil {
+			response.ResolverName = names[*annotation.ResolvedBy]
+		}
+		for _, reply := range annotation.Replies {
+			response.Replies = append(response.Replies, dto.AnnotationReplyResponse{
+				ID:         reply.ID,
+				AuthorID:   reply.AuthorID,
+				AuthorName: names[reply.AuthorID],
+				Content:    reply.Content,
+				CreatedAt:  dto.ToResponseTime(reply.CreatedAt),
+			})
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
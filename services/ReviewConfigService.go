@@ -0,0 +1,610 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewConfigService struct{}
+
+// GetRoleWeights 获取审核人角色投票权重（未配置的角色按权重 1 计票）
+func (s *ReviewConfigService) GetRoleWeights() ([]dto.ReviewRoleWeightItem, error) {
+	var weights []models.ReviewRoleWeight
+	if err := database.DB.Order("reviewer_role ASC").Find(&weights).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ReviewRoleWeightItem, 0, len(weights))
+	for _, weight := range weights {
+		items = append(items, dto.ReviewRoleWeightItem{
+			ReviewerRole: weight.ReviewerRole,
+			Weight:       weight.Weight,
+			Description:  weight.Description,
+		})
+	}
+	return items, nil
+}
+
+// SaveRoleWeights 保存审核人角色投票权重（按角色新增或更新）
+func (s *ReviewConfigService) SaveRoleWeights(req *dto.SaveReviewRoleWeightsRequest) ([]dto.ReviewRoleWeightItem, error) {
+	seen := make(map[string]bool)
+	weights := make([]models.ReviewRoleWeight, 0, len(req.Weights))
+	for _, item := range req.Weights {
+		if seen[item.ReviewerRole] {
+			return nil, fmt.Errorf("角色 %s 重复", item.ReviewerRole)
+		}
+		seen[item.ReviewerRole] = true
+		weights = append(weights, models.ReviewRoleWeight{
+			ReviewerRole: item.ReviewerRole,
+			Weight:       item.Weight,
+			Description:  item.Description,
+		})
+	}
+
+	if err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "reviewer_role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weight", "description", "updated_at"}),
+	}).Create(&weights).Error; err != nil {
+		return nil, fmt.Errorf("保存角色权重失败: %v", err)
+	}
+
+	return s.GetRoleWeights()
+}
+
+// GetRubricList 获取评分标准列表（含评分维度），reviewType 为空表示全部
+func (s *ReviewConfigService) GetRubricList(reviewType string) ([]dto.ReviewRubricResponse, error) {
+	query := database.DB.Preload("Criteria", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC, id ASC")
+	}).Order("review_type ASC, id ASC")
+	if reviewType != "" {
+		query = query.Where("review_type = ?", reviewType)
+	}
+
+	var rubrics []models.ReviewRubric
+	if err := query.Find(&rubrics).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ReviewRubricResponse, 0, len(rubrics))
+	for i := range rubrics {
+		responses = append(responses, toReviewRubricResponse(&rubrics[i]))
+	}
+	return responses, nil
+}
+
+// CreateRubric 创建评分标准
+func (s *ReviewConfigService) CreateRubric(req *dto.ReviewRubricRequest) (*dto.ReviewRubricResponse, error) {
+	if err := s.validateRubric(0, req); err != nil {
+		return nil, err
+	}
+
+	rubric := &models.ReviewRubric{
+		Name:          req.Name,
+		ReviewType:    req.ReviewType,
+		ScaleMin:      req.ScaleMin,
+		ScaleMax:      req.ScaleMax,
+		MinTotalScore: req.MinTotalScore,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+		Description:   req.Description,
+	}
+	for i, item := range req.Criteria {
+		rubric.Criteria = append(rubric.Criteria, buildRubricCriterion(0, i, item))
+	}
+
+	if err := database.DB.Create(rubric).Error; err != nil {
+		return nil, fmt.Errorf("创建评分标准失败: %v", err)
+	}
+	return s.getRubric(rubric.ID)
+}
+
+// UpdateRubric 更新评分标准
+// 已被审核会话使用的评分标准不能修改分值范围、增删维度或调整权重，以免已有评分失真
+func (s *ReviewConfigService) UpdateRubric(id uint, req *dto.ReviewRubricRequest) (*dto.ReviewRubricResponse, error) {
+	var rubric models.ReviewRubric
+	if err := database.DB.Preload("Criteria").First(&rubric, id).Error; err != nil {
+		return nil, errors.New("评分标准不存在")
+	}
+	if err := s.validateRubric(id, req); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uint]models.ReviewRubricCriterion, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		existing[criterion.ID] = criterion
+	}
+	for _, item := range req.Criteria {
+		if item.ID != 0 {
+			if _, ok := existing[item.ID]; !ok {
+				return nil, fmt.Errorf("评分维度 %d 不属于该评分标准", item.ID)
+			}
+		}
+	}
+
+	var sessionCount int64
+	database.DB.Model(&models.ReviewSession{}).Where("rubric_id = ?", id).Count(&sessionCount)
+	if sessionCount > 0 && !rubricScoringUnchanged(&rubric, req) {
+		return nil, errors.New("评分标准已被审核会话使用，不能修改分值范围、增删维度或调整权重，请新建评分标准")
+	}
+
+	isActive := rubric.IsActive
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(&rubric).
+		Select("name", "review_type", "scale_min", "scale_max", "min_total_score", "is_active", "description").
+		Updates(models.ReviewRubric{
+			Name:          req.Name,
+			ReviewType:    req.ReviewType,
+			ScaleMin:      req.ScaleMin,
+			ScaleMax:      req.ScaleMax,
+			MinTotalScore: req.MinTotalScore,
+			IsActive:      isActive,
+			Description:   req.Description,
+		}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("更新评分标准失败: %v", err)
+	}
+
+	// 按维度ID更新已有维度，新增未传ID的维度，删除未包含的维度
+	kept := make(map[uint]bool, len(req.Criteria))
+	for i, item := range req.Criteria {
+		criterion := buildRubricCriterion(id, i, item)
+		if item.ID == 0 {
+			if err := tx.Create(&criterion).Error; err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("保存评分维度失败: %v", err)
+			}
+			continue
+		}
+		kept[item.ID] = true
+		if err := tx.Model(&models.ReviewRubricCriterion{}).Where("id = ?", item.ID).
+			Select("name", "description", "weight", "sort_order").
+			Updates(criterion).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存评分维度失败: %v", err)
+		}
+	}
+	for criterionID := range existing {
+		if kept[criterionID] {
+			continue
+		}
+		if err := tx.Unscoped().Delete(&models.ReviewRubricCriterion{}, criterionID).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.getRubric(id)
+}
+
+// DeleteRubric 删除评分标准，已被审核会话使用时不允许删除（可停用）
+func (s *ReviewConfigService) DeleteRubric(id uint) error {
+	var rubric models.ReviewRubric
+	if err := database.DB.First(&rubric, id).Error; err != nil {
+		return errors.New("评分标准不存在")
+	}
+
+	var sessionCount int64
+	database.DB.Model(&models.ReviewSession{}).Where("rubric_id = ?", id).Count(&sessionCount)
+	if sessionCount > 0 {
+		return fmt.Errorf("该评分标准被 %d 个审核会话使用，无法删除，可改为停用", sessionCount)
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("rubric_id = ?", id).Delete(&models.ReviewRubricCriterion{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&rubric).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// validateRubric 校验评分标准配置
+func (s *ReviewConfigService) validateRubric(id uint, req *dto.ReviewRubricRequest) error {
+	if err := checkRubricRequest(req); err != nil {
+		return err
+	}
+
+	// 每个审核类型最多一个启用的评分标准
+	if req.IsActive == nil || *req.IsActive {
+		var count int64
+		database.DB.Model(&models.ReviewRubric{}).
+			Where("review_type = ? AND is_active = ? AND id <> ?", req.ReviewType, true, id).
+			Count(&count)
+		if count > 0 {
+			return errors.New("该审核类型已有启用的评分标准，请先停用")
+		}
+	}
+	return nil
+}
+
+// checkRubricRequest 校验评分标准请求本身：最低分在分值范围内，维度名称不重复
+func checkRubricRequest(req *dto.ReviewRubricRequest) error {
+	if req.MinTotalScore != nil &&
+		(*req.MinTotalScore < float64(req.ScaleMin) || *req.MinTotalScore > float64(req.ScaleMax)) {
+		return fmt.Errorf("最低分需在 %d-%d 之间", req.ScaleMin, req.ScaleMax)
+	}
+
+	names := make(map[string]bool, len(req.Criteria))
+	ids := make(map[uint]bool, len(req.Criteria))
+	for _, item := range req.Criteria {
+		if names[item.Name] {
+			return fmt.Errorf("评分维度「%s」重复", item.Name)
+		}
+		names[item.Name] = true
+		if item.ID != 0 {
+			if ids[item.ID] {
+				return fmt.Errorf("评分维度 %d 重复", item.ID)
+			}
+			ids[item.ID] = true
+		}
+	}
+	return nil
+}
+
+// rubricScoringUnchanged 判断请求是否保持评分口径不变（分值范围、维度集合和权重均未变化）
+func rubricScoringUnchanged(rubric *models.ReviewRubric, req *dto.ReviewRubricRequest) bool {
+	if rubric.ScaleMin != req.ScaleMin || rubric.ScaleMax != req.ScaleMax ||
+		len(rubric.Criteria) != len(req.Criteria) {
+		return false
+	}
+	weights := make(map[uint]float64, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		weights[criterion.ID] = criterion.Weight
+	}
+	for _, item := range req.Criteria {
+		weight, ok := weights[item.ID]
+		if !ok || weight != item.Weight {
+			return false
+		}
+	}
+	return true
+}
+
+// buildRubricCriterion 根据请求构建评分维度，未指定排序时按列表顺序
+func buildRubricCriterion(rubricID uint, index int, item dto.ReviewRubricCriterionItem) models.ReviewRubricCriterion {
+	sortOrder := item.SortOrder
+	if sortOrder == 0 {
+		sortOrder = index + 1
+	}
+	return models.ReviewRubricCriterion{
+		RubricID:    rubricID,
+		Name:        item.Name,
+		Description: item.Description,
+		Weight:      item.Weight,
+		SortOrder:   sortOrder,
+	}
+}
+
+// getRubric 获取单个评分标准响应
+func (s *ReviewConfigService) getRubric(id uint) (*dto.ReviewRubricResponse, error) {
+	var rubric models.ReviewRubric
+	if err := database.DB.Preload("Criteria", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC, id ASC")
+	}).First(&rubric, id).Error; err != nil {
+		return nil, errors.New("评分标准不存在")
+	}
+	resp := toReviewRubricResponse(&rubric)
+	return &resp, nil
+}
+
+// toReviewRubricResponse 转换评分标准响应
+func toReviewRubricResponse(rubric *models.ReviewRubric) dto.ReviewRubricResponse {
+	resp := dto.ReviewRubricResponse{
+		ID:            rubric.ID,
+		Name:          rubric.Name,
+		ReviewType:    rubric.ReviewType,
+		ScaleMin:      rubric.ScaleMin,
+		ScaleMax:      rubric.ScaleMax,
+		MinTotalScore: rubric.MinTotalScore,
+		IsActive:      rubric.IsActive,
+		Description:   rubric.Description,
+		Criteria:      make([]dto.ReviewRubricCriterionItem, 0, len(rubric.Criteria)),
+		CreatedAt:     dto.ToResponseTime(rubric.CreatedAt),
+	}
+	for _, criterion := range rubric.Criteria {
+		resp.Criteria = append(resp.Criteria, dto.ReviewRubricCriterionItem{
+			ID:          criterion.ID,
+			Name:        criterion.Name,
+			Description: criterion.Description,
+			Weight:      criterion.Weight,
+			SortOrder:   criterion.SortOrder,
+		})
+	}
+	return resp
+}
+
+// GetApprovalChainList 获取审批链模板列表（含阶段），reviewType 为空表示全部
+func (s *ReviewConfigService) GetApprovalChainList(reviewType string) ([]dto.ApprovalChainTemplateResponse, error) {
+	query := database.DB.Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("stage_no ASC")
+	}).Order("review_type ASC, sort_order DESC, id ASC")
+	if reviewType != "" {
+		query = query.Where("review_type = ?", reviewType)
+	}
+
+	var templates []models.ApprovalChainTemplate
+	if err := query.Find(&templates).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ApprovalChainTemplateResponse, 0, len(templates))
+	for i := range templates {
+		responses = append(responses, toApprovalChainResponse(&templates[i]))
+	}
+	return responses, nil
+}
+
+// CreateApprovalChain 创建审批链模板
+func (s *ReviewConfigService) CreateApprovalChain(req *dto.ApprovalChainTemplateRequest) (*dto.ApprovalChainTemplateResponse, error) {
+	if err := validateApprovalChain(req); err != nil {
+		return nil, err
+	}
+
+	template := &models.ApprovalChainTemplate{
+		Name:                 req.Name,
+		ReviewType:           req.ReviewType,
+		TaskTypeCode:         req.TaskTypeCode,
+		MinPriority:          req.MinPriority,
+		MatchCrossDepartment: req.MatchCrossDepartment,
+		SortOrder:            req.SortOrder,
+		IsActive:             req.IsActive == nil || *req.IsActive,
+		Description:          req.Description,
+		Stages:               buildApprovalChainStages(0, req.Stages),
+	}
+	if err := database.DB.Create(template).Error; err != nil {
+		return nil, fmt.Errorf("创建审批链模板失败: %v", err)
+	}
+	return s.getApprovalChain(template.ID)
+}
+
+// UpdateApprovalChain 更新审批链模板
+// 有进行中的审批链使用该模板时只能修改名称、描述、匹配规则和启用状态，不能修改阶段
+func (s *ReviewConfigService) UpdateApprovalChain(id uint, req *dto.ApprovalChainTemplateRequest) (*dto.ApprovalChainTemplateResponse, error) {
+	var template models.ApprovalChainTemplate
+	if err := database.DB.Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("stage_no ASC")
+	}).First(&template, id).Error; err != nil {
+		return nil, errors.New("审批链模板不存在")
+	}
+	if err := validateApprovalChain(req); err != nil {
+		return nil, err
+	}
+
+	stages := buildApprovalChainStages(id, req.Stages)
+	stagesChanged := !approvalChainStagesEqual(template.Stages, stages)
+	if stagesChanged {
+		var inReview int64
+		database.DB.Model(&models.ReviewSession{}).
+			Where("chain_template_id = ? AND status = ?", id, "in_review").
+			Count(&inReview)
+		if inReview > 0 {
+			return nil, errors.New("有进行中的审批链使用该模板，不能修改审批阶段，请新建模板")
+		}
+	}
+
+	isActive := template.IsActive
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(&template).
+		Select("name", "review_type", "task_type_code", "min_priority", "match_cross_department", "sort_order", "is_active", "description").
+		Updates(models.ApprovalChainTemplate{
+			Name:                 req.Name,
+			ReviewType:           req.ReviewType,
+			TaskTypeCode:         req.TaskTypeCode,
+			MinPriority:          req.MinPriority,
+			MatchCrossDepartment: req.MatchCrossDepartment,
+			SortOrder:            req.SortOrder,
+			IsActive:             isActive,
+			Description:          req.Description,
+		}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("更新审批链模板失败: %v", err)
+	}
+
+	// 阶段变化时整体替换
+	if stagesChanged {
+		if err := tx.Unscoped().Where("template_id = ?", id).Delete(&models.ApprovalChainStage{}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := tx.Create(&stages).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存审批阶段失败: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.getApprovalChain(id)
+}
+
+// DeleteApprovalChain 删除审批链模板，已被审核会话使用时不允许删除（可停用）
+func (s *ReviewConfigService) DeleteApprovalChain(id uint) error {
+	var template models.ApprovalChainTemplate
+	if err := database.DB.First(&template, id).Error; err != nil {
+		return errors.New("审批链模板不存在")
+	}
+
+	var sessionCount int64
+	database.DB.Model(&models.ReviewSession{}).Where("chain_template_id = ?", id).Count(&sessionCount)
+	if sessionCount > 0 {
+		return fmt.Errorf("该审批链模板被 %d 个审核会话使用，无法删除，可改为停用", sessionCount)
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("template_id = ?", id).Delete(&models.ApprovalChainStage{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&template).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// validateApprovalChain 校验审批链模板配置：任务类型存在，指定部门/用户类型的阶段需指定且存在
+func validateApprovalChain(req *dto.ApprovalChainTemplateRequest) error {
+	if err := checkApprovalChainStages(req.Stages); err != nil {
+		return err
+	}
+	if req.TaskTypeCode != "" {
+		var count int64
+		database.DB.Model(&models.TaskType{}).Where("code = ?", req.TaskTypeCode).Count(&count)
+		if count == 0 {
+			return errors.New("任务类型不存在")
+		}
+	}
+	for i, item := range req.Stages {
+		var count int64
+		switch item.ApproverType {
+		case models.ApproverTypeDepartmentLeader:
+			database.DB.Model(&models.Department{}).Where("id = ?", *item.ApproverDepartmentID).Count(&count)
+			if count == 0 {
+				return fmt.Errorf("第 %d 阶段指定的部门不存在", i+1)
+			}
+		case models.ApproverTypeUser:
+			database.DB.Model(&models.User{}).Where("id = ?", *item.ApproverUserID).Count(&count)
+			if count == 0 {
+				return fmt.Errorf("第 %d 阶段指定的用户不存在", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// checkApprovalChainStages 校验审批阶段本身：审批人类型有效，指定部门/用户类型需指定对应ID
+func checkApprovalChainStages(stages []dto.ApprovalChainStageItem) error {
+	for i, item := range stages {
+		if !approverTypes[item.ApproverType] {
+			return fmt.Errorf("第 %d 阶段的审批人类型无效", i+1)
+		}
+		if item.ApproverType == models.ApproverTypeDepartmentLeader && item.ApproverDepartmentID == nil {
+			return fmt.Errorf("第 %d 阶段需要指定部门", i+1)
+		}
+		if item.ApproverType == models.ApproverTypeUser && item.ApproverUserID == nil {
+			return fmt.Errorf("第 %d 阶段需要指定用户", i+1)
+		}
+	}
+	return nil
+}
+
+// buildApprovalChainStages 根据请求构建审批阶段，按列表顺序编号；只保留审批人类型对应的部门/用户ID
+func buildApprovalChainStages(templateID uint, items []dto.ApprovalChainStageItem) []models.ApprovalChainStage {
+	stages := make([]models.ApprovalChainStage, 0, len(items))
+	for i, item := range items {
+		stage := models.ApprovalChainStage{
+			TemplateID:   templateID,
+			StageNo:      i + 1,
+			Name:         item.Name,
+			ApproverType: item.ApproverType,
+		}
+		switch item.ApproverType {
+		case models.ApproverTypeDepartmentLeader:
+			stage.ApproverDepartmentID = item.ApproverDepartmentID
+		case models.ApproverTypeUser:
+			stage.ApproverUserID = item.ApproverUserID
+		}
+		stages = append(stages, stage)
+	}
+	return stages
+}
+
+// approvalChainStagesEqual 判断两组审批阶段的配置是否一致
+func approvalChainStagesEqual(a, b []models.ApprovalChainStage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sameID := func(x, y *uint) bool {
+		return (x == nil && y == nil) || (x != nil && y != nil && *x == *y)
+	}
+	for i := range a {
+		if a[i].StageNo != b[i].StageNo || a[i].Name != b[i].Name || a[i].ApproverType != b[i].ApproverType ||
+			!sameID(a[i].ApproverDepartmentID, b[i].ApproverDepartmentID) || !sameID(a[i].ApproverUserID, b[i].ApproverUserID) {
+			return false
+		}
+	}
+	return true
+}
+
+// getApprovalChain 获取单个审批链模板响应
+func (s *ReviewConfigService) getApprovalChain(id uint) (*dto.ApprovalChainTemplateResponse, error) {
+	var template models.ApprovalChainTemplate
+	if err := database.DB.Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("stage_no ASC")
+	}).First(&template, id).Error; err != nil {
+		return nil, errors.New("审批链模板不存在")
+	}
+	resp := toApprovalChainResponse(&template)
+	return &resp, nil
+}
+
+// toApprovalChainResponse 转换审批链模板响应
+func toApprovalChainResponse(template *models.ApprovalChainTemplate) dto.ApprovalChainTemplateResponse {
+	resp := dto.ApprovalChainTemplateResponse{
+		ID:                   template.ID,
+		Name:                 template.Name,
+		ReviewType:           template.ReviewType,
+		TaskTypeCode:         template.TaskTypeCode,
+		MinPriority:          template.MinPriority,
+		MatchCrossDepartment: template.MatchCrossDepartment,
+		SortOrder:            template.SortOrder,
+		IsActive:             template.IsActive,
+		Description:          template.Description,
+		Stages:               make([]dto.ApprovalChainStageItem, 0, len(template.Stages)),
+		CreatedAt:            dto.ToResponseTime(template.CreatedAt),
+	}
+	for _, stage := range template.Stages {
+		resp.Stages = append(resp.Stages, dto.ApprovalChainStageItem{
+			StageNo:              stage.StageNo,
+			Name:                 stage.Name,
+			ApproverType:         stage.ApproverType,
+			ApproverDepartmentID: stage.ApproverDepartmentID,
+			ApproverUserID:       stage.ApproverUserID,
+		})
+	}
+	return resp
+}
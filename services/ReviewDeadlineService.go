@@ -0,0 +1,267 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// NotificationTypeReviewDeadline 陪审团响应提醒/截止通知类型
+const NotificationTypeReviewDeadline = "review_deadline"
+
+// reviewDeadlinePolicies 支持的响应截止处理策略
+var reviewDeadlinePolicies = map[string]bool{
+	models.ReviewDeadlinePolicyAbstain:         true,
+	models.ReviewDeadlinePolicyEscalate:        true,
+	models.ReviewDeadlinePolicyCreatorFinalize: true,
+}
+
+type ReviewDeadlineService struct{}
+
+// ReviewDeadlineProcessResult 一次陪审团响应截止检查的处理结果
+type ReviewDeadlineProcessResult struct {
+	// 发送截止提醒的会话数量
+	Reminded int
+	// 执行截止处理策略的会话数量
+	Expired int
+	// 截止后由投票结果自动结束的会话数量
+	AutoClosed int
+}
+
+// resolveReviewDeadline 确定审核会话的响应截止时间和处理策略
+// 截止时间：请求指定 > 会话已有 > 按配置的响应时限计算（为 0 时不设截止）；策略：请求指定 > 会话已有 > 配置的默认策略
+func resolveReviewDeadline(deadline, policy string, currentDue *time.Time, currentPolicy string, cfg config.TaskConfig, now time.Time) (*time.Time, string, error) {
+	dueAt := currentDue
+	if deadline != "" {
+		parsed, err := ParseDateTime(deadline)
+		if err != nil {
+			return nil, "", errors.New("无效的响应截止时间")
+		}
+		if !parsed.After(now) {
+			return nil, "", errors.New("响应截止时间需晚于当前时间")
+		}
+		dueAt = parsed
+	} else if dueAt == nil && cfg.JuryResponseHours > 0 {
+		due := now.Add(time.Duration(cfg.JuryResponseHours) * time.Hour)
+		dueAt = &due
+	}
+
+	if policy == "" {
+		policy = currentPolicy
+	}
+	if policy == "" {
+		policy = cfg.JuryDeadlinePolicy
+	}
+	if !reviewDeadlinePolicies[policy] {
+		policy = models.ReviewDeadlinePolicyCreatorFinalize
+	}
+	return dueAt, policy, nil
+}
+
+// deadlineReleasesVoting 响应截止后是否已允许不按投票结果直接决策（升级或创建人决策策略）
+func deadlineReleasesVoting(session *models.ReviewSession) bool {
+	return session.DeadlineHandledAt != nil && session.DeadlinePolicy != models.ReviewDeadlinePolicyAbstain
+}
+
+// isEscalated 审核会话是否已在响应截止后升级到任务所属部门负责人决策
+func isEscalated(session *models.ReviewSession) bool {
+	return session.DeadlineHandledAt != nil && session.DeadlinePolicy == models.ReviewDeadlinePolicyEscalate
+}
+
+// ProcessReviewDeadlines 检查陪审团响应截止：截止前提醒未响应成员，截止后按会话的处理策略处理
+// ctx 取消或超时后停止处理剩余的会话
+func (s *ReviewDeadlineService) ProcessReviewDeadlines(ctx context.Context, now time.Time) (*ReviewDeadlineProcessResult, error) {
+	cfg := config.GetConfig().Task
+	result := &ReviewDeadlineProcessResult{}
+	db := database.DB.WithContext(ctx)
+
+	// 1. 截止前提醒
+	if cfg.JuryReminderHours > 0 {
+		var upcoming []models.ReviewSession
+		remindBefore := now.Add(time.Duration(cfg.JuryReminderHours) * time.Hour)
+		if err := db.Where("status = ? AND reminded_at IS NULL AND response_due_at > ? AND response_due_at <= ?",
+			"in_review", now, remindBefore).
+			Find(&upcoming).Error; err != nil {
+			return result, err
+		}
+		for i := range upcoming {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			if s.remind(&upcoming[i], now) {
+				result.Reminded++
+			}
+		}
+	}
+
+	// 2. 截止处理
+	var expired []models.ReviewSession
+	if err := db.Where("status = ? AND deadline_handled_at IS NULL AND response_due_at <= ?", "in_review", now).
+		Find(&expired).Error; err != nil {
+		return result, err
+	}
+	for i := range expired {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		autoClosed, err := s.expire(ctx, expired[i].ID, now)
+		if err != nil {
+			utils.Logger.Warnf("审核会话 %d 响应截止处理失败: %v", expired[i].ID, err)
+			continue
+		}
+		result.Expired++
+		if autoClosed {
+			result.AutoClosed++
+		}
+	}
+
+	return result, nil
+}
+
+// remind 提醒尚未提交意见的陪审团成员/评审专家
+func (s *ReviewDeadlineService) remind(session *models.ReviewSession, now time.Time) bool {
+	var task models.Task
+	if err := database.DB.First(&task, session.TaskID).Error; err != nil {
+		return false
+	}
+	if err := database.DB.Model(session).Update("reminded_at", now).Error; err != nil {
+		return false
+	}
+
+	pending := pendingReviewParticipants(database.DB, session)
+	if len(pending) == 0 {
+		return true
+	}
+	recipients := make([]uint, 0, len(pending))
+	for _, participant := range pending {
+		recipients = append(recipients, participant.UserID)
+	}
+	content := fmt.Sprintf("任务「%s」的%s将于 %s 截止响应，请及时提交审核意见",
+		task.Title, session.ReviewType, session.ResponseDueAt.Format(dto.TimeFormatDatetime))
+	recipients = withActiveDelegates(database.DB, &task, recipients, now)
+	(&NotificationService{}).Notify(recipients, &task.ID, NotificationTypeReviewDeadline, "审核响应提醒", content)
+	return true
+}
+
+// expire 响应截止后按会话的处理策略处理，返回会话是否因此自动结束
+func (s *ReviewDeadlineService) expire(ctx context.Context, sessionID uint, now time.Time) (bool, error) {
+	tx := database.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var session models.ReviewSession
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, sessionID).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if session.Status != "in_review" || session.DeadlineHandledAt != nil {
+		tx.Rollback()
+		return false, nil
+	}
+
+	var task models.Task
+	if err := tx.First(&task, session.TaskID).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Model(&session).Update("deadline_handled_at", now).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	pending := pendingReviewParticipants(tx, &session)
+	changeLog := &models.TaskChangeLog{
+		TaskID:    session.TaskID,
+		UserID:    task.CreatorID,
+		FieldName: "review_session_id",
+		NewValue:  fmt.Sprintf("%d", session.ID),
+	}
+
+	decided, approved := false, false
+	switch session.DeadlinePolicy {
+	case models.ReviewDeadlinePolicyAbstain:
+		// 未响应成员按弃权记录
+		weights := loadReviewRoleWeights(tx)
+		leaders := departmentLeaderSet(tx, task.DepartmentID)
+		for _, participant := range pending {
+			role := resolveReviewerRole(leaders, participant.UserID, participant.Role)
+			record := &models.ReviewRecord{
+				ReviewSessionID: session.ID,
+				ReviewerID:      participant.UserID,
+				ReviewerRole:    role,
+				Opinion:         "abstain",
+				Comment:         "超过响应截止时间未提交意见，视为弃权",
+				VoteWeight:      reviewVoteWeight(session.ReviewMode, role, weights),
+				ReviewedAt:      now,
+			}
+			if err := tx.Create(record).Error; err != nil {
+				tx.Rollback()
+				return false, fmt.Errorf("记录弃权失败: %v", err)
+			}
+		}
+		changeLog.ChangeType = "review_deadline_abstain"
+		changeLog.Comment = fmt.Sprintf("审核响应已截止，%d 名未响应成员视为弃权", len(pending))
+	case models.ReviewDeadlinePolicyEscalate:
+		changeLog.ChangeType = "review_escalated"
+		changeLog.Comment = fmt.Sprintf("审核响应已截止，%d 名成员未响应，升级到部门负责人决策", len(pending))
+	default:
+		changeLog.ChangeType = "review_deadline_passed"
+		changeLog.Comment = fmt.Sprintf("审核响应已截止，%d 名成员未响应，由创建人直接决策", len(pending))
+	}
+	if err := tx.Create(changeLog).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if session.DeadlinePolicy == models.ReviewDeadlinePolicyAbstain {
+		var err error
+		decided, approved, err = closeVotingSessionIfDecided(tx, &session, &task, task.CreatorID)
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	if decided {
+		notifyVotingDecision(&task, &session, approved)
+		return true, nil
+	}
+	s.notifyExpired(&task, &session, len(pending))
+	return false, nil
+}
+
+// notifyExpired 通知响应截止后需要做出决策的人
+func (s *ReviewDeadlineService) notifyExpired(task *models.Task, session *models.ReviewSession, pendingCount int) {
+	recipients := []uint{task.CreatorID}
+	var content string
+	switch session.DeadlinePolicy {
+	case models.ReviewDeadlinePolicyAbstain:
+		content = fmt.Sprintf("任务「%s」的%s响应已截止，%d 名未响应成员视为弃权，请做出最终决策",
+			task.Title, session.ReviewType, pendingCount)
+	case models.ReviewDeadlinePolicyEscalate:
+		recipients = taskCreatorAndLeaderIDs(task)
+		content = fmt.Sprintf("任务「%s」的%s响应已截止，%d 名成员未响应，已升级到部门负责人决策",
+			task.Title, session.ReviewType, pendingCount)
+	default:
+		content = fmt.Sprintf("任务「%s」的%s响应已截止，%d 名成员未响应，创建人可直接做出最终决策",
+			task.Title, session.ReviewType, pendingCount)
+	}
+	recipients = withActiveDelegates(database.DB, task, recipients, time.Now())
+	(&NotificationService{}).Notify(recipients, &task.ID, NotificationTypeReviewDeadline, "审核响应截止", content)
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 审核委托状态（由生效时间和撤销标记推算）
+const (
+	delegationStatusUpcoming = "upcoming"
+	delegationStatusActive   = "active"
+	delegationStatusExpired  = "expired"
+	delegationStatusRevoked  = "revoked"
+)
+
+type ReviewDelegationService struct{}
+
+// delegationStatus 推算委托在 now 时的状态
+func delegationStatus(rule *models.ReviewDelegation, now time.Time) string {
+	switch {
+	case !rule.IsActive:
+		return delegationStatusRevoked
+	case now.Before(rule.StartAt):
+		return delegationStatusUpcoming
+	case now.Before(rule.EndAt):
+		return delegationStatusActive
+	default:
+		return delegationStatusExpired
+	}
+}
+
+// delegationCoversTask 委托的范围是否覆盖任务（部门/任务类型为空表示不限）
+func delegationCoversTask(rule *models.ReviewDelegation, task *models.Task) bool {
+	if rule.DepartmentID != nil && (task.DepartmentID == nil || *task.DepartmentID != *rule.DepartmentID) {
+		return false
+	}
+	return rule.TaskTypeCode == "" || rule.TaskTypeCode == task.TaskTypeCode
+}
+
+// delegationsOverlap 同一委托人的两条委托是否冲突（时间重叠且范围有交集）
+func delegationsOverlap(a, b *models.ReviewDelegation) bool {
+	if !a.StartAt.Before(b.EndAt) || !b.StartAt.Before(a.EndAt) {
+		return false
+	}
+	if a.DepartmentID != nil && b.DepartmentID != nil && *a.DepartmentID != *b.DepartmentID {
+		return false
+	}
+	return a.TaskTypeCode == "" || b.TaskTypeCode == "" || a.TaskTypeCode == b.TaskTypeCode
+}
+
+// activeDelegations 查询在 now 时生效的委托（按委托人或代理人筛选）
+func activeDelegations(db *gorm.DB, column string, userIDs []uint, now time.Time) []models.ReviewDelegation {
+	var rules []models.ReviewDelegation
+	if len(userIDs) == 0 {
+		return rules
+	}
+	db.Where(column+" IN ? AND is_active = ? AND start_at <= ? AND end_at > ?", userIDs, true, now, now).
+		Order("id ASC").Find(&rules)
+	return rules
+}
+
+// isDelegateFor 用户当前是否为委托人在该任务上的代理人
+func isDelegateFor(db *gorm.DB, principalID, delegateID uint, task *models.Task, now time.Time) bool {
+	if principalID == delegateID {
+		return false
+	}
+	for _, rule := range activeDelegations(db, "delegate_id", []uint{delegateID}, now) {
+		if rule.DelegatorID == principalID && delegationCoversTask(&rule, task) {
+			return true
+		}
+	}
+	return false
+}
+
+// delegatorsOf 获取当前委托该用户处理此任务审核的委托人
+func delegatorsOf(db *gorm.DB, delegateID uint, task *models.Task, now time.Time) []uint {
+	var ids []uint
+	for _, rule := range activeDelegations(db, "delegate_id", []uint{delegateID}, now) {
+		if delegationCoversTask(&rule, task) {
+			ids = append(ids, rule.DelegatorID)
+		}
+	}
+	return uniqueUintSlice(ids)
+}
+
+// withActiveDelegates 在审核/审批通知的接收人中加入其当前生效的代理人
+func withActiveDelegates(db *gorm.DB, task *models.Task, userIDs []uint, now time.Time) []uint {
+	recipients := append([]uint{}, userIDs...)
+	for _, rule := range activeDelegations(db, "delegator_id", uniqueUintSlice(userIDs), now) {
+		if delegationCoversTask(&rule, task) {
+			recipients = append(recipients, rule.DelegateID)
+		}
+	}
+	return uniqueUintSlice(recipients)
+}
+
+// resolveReviewPrincipal 确定用户以谁的身份处理审核：本人在 principals 中时为本人，否则为委托其代理的 principals 之一
+func resolveReviewPrincipal(db *gorm.DB, principals []uint, userID uint, task *models.Task, now time.Time) (uint, bool) {
+	for _, id := range principals {
+		if id == userID {
+			return userID, true
+		}
+	}
+	for _, id := range principals {
+		if isDelegateFor(db, id, userID, task, now) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// logDelegatedAction 记录代理人代委托人处理审核的变更日志
+func logDelegatedAction(tx *gorm.DB, taskID, delegateID, principalID uint, action string) error {
+	names := loadUsernames([]uint{delegateID, principalID})
+	changeLog := &models.TaskChangeLog{
+		TaskID:     taskID,
+		UserID:     delegateID,
+		ChangeType: "delegated_action",
+		FieldName:  action,
+		OldValue:   strconv.FormatUint(uint64(principalID), 10),
+		NewValue:   strconv.FormatUint(uint64(delegateID), 10),
+		Comment:    fmt.Sprintf("%s 代 %s %s", names[delegateID], names[principalID], action),
+	}
+	return tx.Create(changeLog).Error
+}
+
+// parseDelegationRange 解析委托的生效时间，结束时间只有日期时包含当天
+func parseDelegationRange(startAt, endAt string) (time.Time, time.Time, error) {
+	start, err := ParseDateTime(startAt)
+	if err != nil || start == nil {
+		return time.Time{}, time.Time{}, errors.New("开始时间格式错误")
+	}
+	end, err := ParseDateTime(endAt)
+	if err != nil || end == nil {
+		return time.Time{}, time.Time{}, errors.New("结束时间格式错误")
+	}
+	if len(endAt) == len(dateKeyFormat) {
+		*end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(*start) {
+		return time.Time{}, time.Time{}, errors.New("结束时间必须晚于开始时间")
+	}
+	return *start, *end, nil
+}
+
+// CreateDelegation 创建审核委托（委托人为当前用户）
+func (s *ReviewDelegationService) CreateDelegation(userID uint, req *dto.CreateDelegationRequest) (*dto.DelegationResponse, error) {
+	if req.DelegateID == userID {
+		return nil, errors.New("不能委托给自己")
+	}
+	var delegate models.User
+	if err := database.DB.First(&delegate, req.DelegateID).Error; err != nil {
+		return nil, errors.New("代理人不存在")
+	}
+	if delegate.Status != 1 {
+		return nil, errors.New("代理人账号不可用")
+	}
+
+	start, end, err := parseDelegationRange(req.StartAt, req.EndAt)
+	if err != nil {
+		return nil, err
+	}
+	if !end.After(time.Now()) {
+		return nil, errors.New("结束时间已过")
+	}
+
+	if req.DepartmentID != nil {
+		var count int64
+		database.DB.Model(&models.Department{}).Where("id = ?", *req.DepartmentID).Count(&count)
+		if count == 0 {
+			return nil, errors.New("部门不存在")
+		}
+	}
+	if req.TaskTypeCode != "" {
+		var count int64
+		database.DB.Model(&models.TaskType{}).Where("code = ?", req.TaskTypeCode).Count(&count)
+		if count == 0 {
+			return nil, errors.New("任务类型不存在")
+		}
+	}
+
+	rule := &models.ReviewDelegation{
+		DelegatorID:  userID,
+		DelegateID:   req.DelegateID,
+		StartAt:      start,
+		EndAt:        end,
+		DepartmentID: req.DepartmentID,
+		TaskTypeCode: req.TaskTypeCode,
+		Reason:       req.Reason,
+		IsActive:     true,
+	}
+
+	// 同一委托人在重叠的时间和范围内只能有一个代理人
+	var existing []models.ReviewDelegation
+	database.DB.Where("delegator_id = ? AND is_active = ? AND end_at > ?", userID, true, start).Find(&existing)
+	for i := range existing {
+		if delegationsOverlap(rule, &existing[i]) {
+			return nil, fmt.Errorf("与已有委托（ID=%d）的时间和范围重叠", existing[i].ID)
+		}
+	}
+
+	if err := database.DB.Create(rule).Error; err != nil {
+		return nil, err
+	}
+
+	names := loadUsernames([]uint{userID})
+	content := fmt.Sprintf("%s 委托您在 %s 至 %s 期间代为处理审核", names[userID],
+		start.Format(dto.TimeFormatDatetime), end.Format(dto.TimeFormatDatetime))
+	(&NotificationService{}).Notify([]uint{req.DelegateID}, nil, NotificationTypeReviewRequest, "审核委托", content)
+
+	responses := s.toResponses([]models.ReviewDelegation{*rule}, time.Now())
+	return &responses[0], nil
+}
+
+// GetDelegations 查询当前用户相关的审核委托
+func (s *ReviewDelegationService) GetDelegations(userID uint, query *dto.DelegationQuery) ([]dto.DelegationResponse, error) {
+	db := database.DB.Model(&models.ReviewDelegation{})
+	switch query.Role {
+	case "delegator":
+		db = db.Where("delegator_id = ?", userID)
+	case "delegate":
+		db = db.Where("delegate_id = ?", userID)
+	default:
+		db = db.Where("delegator_id = ? OR delegate_id = ?", userID, userID)
+	}
+
+	now := time.Now()
+	switch query.Status {
+	case delegationStatusRevoked:
+		db = db.Where("is_active = ?", false)
+	case delegationStatusUpcoming:
+		db = db.Where("is_active = ? AND start_at > ?", true, now)
+	case delegationStatusActive:
+		db = db.Where("is_active = ? AND start_at <= ? AND end_at > ?", true, now, now)
+	case delegationStatusExpired:
+		db = db.Where("is_active = ? AND end_at <= ?", true, now)
+	}
+
+	var rules []models.ReviewDelegation
+	if err := db.Order("start_at DESC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return s.toResponses(rules, now), nil
+}
+
+// RevokeDelegation 撤销审核委托（委托人本人），撤销后代理人不能再代为处理
+func (s *ReviewDelegationService) RevokeDelegation(id uint, userID uint) error {
+	var rule models.ReviewDelegation
+	if err := database.DB.First(&rule, id).Error; err != nil {
+		return errors.New("委托不存在")
+	}
+	if rule.DelegatorID != userID {
+		return errors.New("只能撤销自己的委托")
+	}
+	if !rule.IsActive {
+		return errors.New("委托已撤销")
+	}
+	if !time.Now().Before(rule.EndAt) {
+		return errors.New("委托已过期")
+	}
+
+	return database.DB.Model(&rule).Updates(map[string]interface{}{
+		"is_active":  false,
+		"revoked_at": time.Now(),
+	}).Error
+}
+
+// toResponses 转换委托响应（批量加载用户名和部门名）
+func (s *ReviewDelegationService) toResponses(rules []models.ReviewDelegation, now time.Time) []dto.DelegationResponse {
+	var userIDs, deptIDs []uint
+	for _, rule := range rules {
+		userIDs = append(userIDs, rule.DelegatorID, rule.DelegateID)
+		if rule.DepartmentID != nil {
+			deptIDs = append(deptIDs, *rule.DepartmentID)
+		}
+	}
+	userNames := loadUsernames(userIDs)
+	deptNames := loadDepartmentNames(deptIDs)
+
+	responses := make([]dto.DelegationResponse, 0, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		response := dto.DelegationResponse{
+			ID:            rule.ID,
+			DelegatorID:   rule.DelegatorID,
+			DelegatorName: userNames[rule.DelegatorID],
+			DelegateID:    rule.DelegateID,
+			DelegateName:  userNames[rule.DelegateID],
+			StartAt:       dto.ToResponseTime(rule.StartAt),
+			EndAt:         dto.ToResponseTime(rule.EndAt),
+			DepartmentID:  rule.DepartmentID,
+			TaskTypeCode:  rule.TaskTypeCode,
+			Reason:        rule.Reason,
+			Status:        delegationStatus(rule, now),
+			RevokedAt:     dto.PtrToResponseTime(rule.RevokedAt),
+			CreatedAt:     dto.ToResponseTime(rule.CreatedAt),
+		}
+		if rule.DepartmentID != nil {
+			response.DepartmentName = deptNames[*rule.DepartmentID]
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
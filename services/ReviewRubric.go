@@ -0,0 +1,199 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// rubricReviewType 审核类型对应的评分标准类型（execution_plan_review 与 plan_review 共用评分标准）
+func rubricReviewType(reviewType string) string {
+	switch reviewType {
+	case "solution_review":
+		return "solution_review"
+	case "plan_review", "execution_plan_review":
+		return "plan_review"
+	}
+	return ""
+}
+
+// activeRubricID 获取审核类型当前启用的评分标准ID（未配置时为空）
+func activeRubricID(db *gorm.DB, reviewType string) *uint {
+	rubricType := rubricReviewType(reviewType)
+	if rubricType == "" {
+		return nil
+	}
+	var rubric models.ReviewRubric
+	if err := db.Select("id").Where("review_type = ? AND is_active = ?", rubricType, true).
+		Order("id DESC").First(&rubric).Error; err != nil {
+		return nil
+	}
+	return &rubric.ID
+}
+
+// loadSessionRubric 加载审核会话绑定的评分标准（含维度），未绑定时返回 nil
+func loadSessionRubric(db *gorm.DB, session *models.ReviewSession) (*models.ReviewRubric, error) {
+	if session.RubricID == nil {
+		return nil, nil
+	}
+	var rubric models.ReviewRubric
+	if err := db.Unscoped().Preload("Criteria", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Order("sort_order ASC, id ASC")
+	}).First(&rubric, *session.RubricID).Error; err != nil {
+		return nil, errors.New("评分标准不存在")
+	}
+	return &rubric, nil
+}
+
+// validateCriterionScores 校验维度评分：维度须属于评分标准且不重复，分值在范围内；非弃权意见需为每个维度打分
+func validateCriterionScores(rubric *models.ReviewRubric, items []dto.CriterionScoreItem, opinion string) error {
+	if rubric == nil {
+		if len(items) > 0 {
+			return errors.New("该审核未绑定评分标准，无需维度评分")
+		}
+		return nil
+	}
+
+	criteria := make(map[uint]string, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		criteria[criterion.ID] = criterion.Name
+	}
+
+	scored := make(map[uint]bool, len(items))
+	for _, item := range items {
+		name, ok := criteria[item.CriterionID]
+		if !ok {
+			return fmt.Errorf("评分维度 %d 不属于该评分标准", item.CriterionID)
+		}
+		if scored[item.CriterionID] {
+			return fmt.Errorf("评分维度「%s」重复", name)
+		}
+		scored[item.CriterionID] = true
+		if item.Score == nil || *item.Score < rubric.ScaleMin || *item.Score > rubric.ScaleMax {
+			return fmt.Errorf("评分维度「%s」的分值需在 %d-%d 之间", name, rubric.ScaleMin, rubric.ScaleMax)
+		}
+	}
+
+	if opinion == "abstain" {
+		return nil
+	}
+	for _, criterion := range rubric.Criteria {
+		if !scored[criterion.ID] {
+			return fmt.Errorf("请为评分维度「%s」打分", criterion.Name)
+		}
+	}
+	return nil
+}
+
+// round2 保留两位小数
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// summarizeRubricScores 汇总评分：各维度平均分、最低/最高分、极差和标准差，以及按维度权重计算的加权总分
+func summarizeRubricScores(rubric *models.ReviewRubric, scores []models.ReviewCriterionScore) *dto.ReviewRubricSummary {
+	byCriterion := make(map[uint][]int)
+	reviewers := make(map[uint]bool)
+	for _, score := range scores {
+		byCriterion[score.CriterionID] = append(byCriterion[score.CriterionID], score.Score)
+		reviewers[score.ReviewRecordID] = true
+	}
+
+	summary := &dto.ReviewRubricSummary{
+		RubricID:        rubric.ID,
+		RubricName:      rubric.Name,
+		ScaleMin:        rubric.ScaleMin,
+		ScaleMax:        rubric.ScaleMax,
+		MinTotalScore:   rubric.MinTotalScore,
+		ScoredReviewers: len(reviewers),
+		Criteria:        []dto.CriterionScoreSummary{},
+	}
+
+	criteria := append([]models.ReviewRubricCriterion(nil), rubric.Criteria...)
+	sort.SliceStable(criteria, func(i, j int) bool {
+		return criteria[i].SortOrder < criteria[j].SortOrder
+	})
+
+	var weightedSum, weightTotal float64
+	for _, criterion := range criteria {
+		item := dto.CriterionScoreSummary{
+			CriterionID: criterion.ID,
+			Name:        criterion.Name,
+			Weight:      criterion.Weight,
+		}
+		values := byCriterion[criterion.ID]
+		if len(values) > 0 {
+			item.Count = len(values)
+			item.Min, item.Max = values[0], values[0]
+			sum := 0
+			for _, v := range values {
+				sum += v
+				if v < item.Min {
+					item.Min = v
+				}
+				if v > item.Max {
+					item.Max = v
+				}
+			}
+			mean := float64(sum) / float64(len(values))
+			var variance float64
+			for _, v := range values {
+				variance += (float64(v) - mean) * (float64(v) - mean)
+			}
+			item.Average = round2(mean)
+			item.Spread = item.Max - item.Min
+			item.StdDev = round2(math.Sqrt(variance / float64(len(values))))
+
+			weightedSum += mean * criterion.Weight
+			weightTotal += criterion.Weight
+		}
+		summary.Criteria = append(summary.Criteria, item)
+	}
+
+	if weightTotal > 0 {
+		total := round2(weightedSum / weightTotal)
+		summary.WeightedTotal = &total
+	}
+	return summary
+}
+
+// loadRubricSummary 加载审核会话的评分汇总
+func loadRubricSummary(db *gorm.DB, session *models.ReviewSession) (*dto.ReviewRubricSummary, error) {
+	rubric, err := loadSessionRubric(db, session)
+	if err != nil || rubric == nil {
+		return nil, err
+	}
+	var scores []models.ReviewCriterionScore
+	if err := db.Where("review_session_id = ?", session.ID).Find(&scores).Error; err != nil {
+		return nil, err
+	}
+	return summarizeRubricScores(rubric, scores), nil
+}
+
+// checkSessionMinScore 校验审核会话的加权总分是否达到通过所需的最低分（所有通过路径都需校验）
+func checkSessionMinScore(db *gorm.DB, session *models.ReviewSession) error {
+	summary, err := loadRubricSummary(db, session)
+	if err != nil {
+		return err
+	}
+	return checkRubricMinScore(summary)
+}
+
+// checkRubricMinScore 校验审核通过所需的最低加权总分
+func checkRubricMinScore(summary *dto.ReviewRubricSummary) error {
+	if summary == nil || summary.MinTotalScore == nil {
+		return nil
+	}
+	if summary.WeightedTotal == nil {
+		return fmt.Errorf("尚无评审评分，未达到通过所需的最低分 %.2f", *summary.MinTotalScore)
+	}
+	if *summary.WeightedTotal < *summary.MinTotalScore {
+		return fmt.Errorf("加权总分 %.2f 低于通过所需的最低分 %.2f", *summary.WeightedTotal, *summary.MinTotalScore)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// reviewVoterRoles 可以投票的任务参与者角色
+var reviewVoterRoles = []string{models.ReviewerRoleJury, models.ReviewerRoleExpert}
+
+// isVotingReviewMode 是否为按投票结果自动决策的审核模式
+func isVotingReviewMode(mode string) bool {
+	return mode == models.ReviewModeThreshold || mode == models.ReviewModeWeighted
+}
+
+// reviewVoteTally 投票统计（均为权重之和）
+type reviewVoteTally struct {
+	Approve float64
+	Reject  float64
+	Abstain float64
+	// 尚未投票的成员权重之和
+	Pending float64
+	// 尚未投票的成员数
+	PendingVoters int
+	// 需要的赞成权重
+	Required float64
+}
+
+// decide 判断投票结果：赞成权重达到要求时通过；未投票成员全部赞成也达不到要求时驳回；否则未决
+func (t *reviewVoteTally) decide() (decided bool, approved bool) {
+	if t.Approve >= t.Required {
+		return true, true
+	}
+	if t.Approve+t.Pending < t.Required {
+		return true, false
+	}
+	return false, false
+}
+
+// summary 投票统计摘要（用于决策备注和变更日志）
+func (t *reviewVoteTally) summary() string {
+	return fmt.Sprintf("赞成 %.2f / 反对 %.2f / 弃权 %.2f / 未投 %.2f，需要赞成 %.2f",
+		t.Approve, t.Reject, t.Abstain, t.Pending, t.Required)
+}
+
+// toResponse 转换投票统计响应
+func (t *reviewVoteTally) toResponse() *dto.ReviewVoteTally {
+	decided, approved := t.decide()
+	tally := &dto.ReviewVoteTally{
+		ApproveWeight: t.Approve,
+		RejectWeight:  t.Reject,
+		AbstainWeight: t.Abstain,
+		PendingWeight: t.Pending,
+		PendingVoters: t.PendingVoters,
+		Required:      t.Required,
+	}
+	if decided {
+		outcome := "rejected"
+		if approved {
+			outcome = "approved"
+		}
+		tally.Outcome = outcome
+	}
+	return tally
+}
+
+// tallyReviewVotes 统计审核记录中的投票（创建人的决策记录不计入），pendingWeights 为尚未投票成员的权重
+func tallyReviewVotes(records []models.ReviewRecord, pendingWeights []float64, required int) reviewVoteTally {
+	tally := reviewVoteTally{Required: float64(required), PendingVoters: len(pendingWeights)}
+	for _, record := range records {
+		if record.ReviewerRole == models.ReviewerRoleCreator {
+			continue
+		}
+		switch record.Opinion {
+		case "approve":
+			tally.Approve += record.VoteWeight
+		case "reject":
+			tally.Reject += record.VoteWeight
+		default:
+			tally.Abstain += record.VoteWeight
+		}
+	}
+	for _, weight := range pendingWeights {
+		tally.Pending += weight
+	}
+	return tally
+}
+
+// loadReviewRoleWeights 加载审核人角色权重配置
+func loadReviewRoleWeights(db *gorm.DB) map[string]float64 {
+	weights := make(map[string]float64)
+	var items []models.ReviewRoleWeight
+	db.Find(&items)
+	for _, item := range items {
+		weights[item.ReviewerRole] = item.Weight
+	}
+	return weights
+}
+
+// reviewVoteWeight 计算投票权重：加权投票模式按角色权重（未配置为 1），其他模式每票为 1
+func reviewVoteWeight(mode, role string, weights map[string]float64) float64 {
+	if mode != models.ReviewModeWeighted {
+		return 1.0
+	}
+	if weight, ok := weights[role]; ok {
+		return weight
+	}
+	return 1.0
+}
+
+// departmentLeaderSet 获取部门负责人集合
+func departmentLeaderSet(db *gorm.DB, departmentID *uint) map[uint]bool {
+	leaders := make(map[uint]bool)
+	if departmentID == nil {
+		return leaders
+	}
+	var leaderIDs []uint
+	db.Model(&models.DepartmentLeader{}).
+		Where("department_id = ?", *departmentID).
+		Pluck("user_id", &leaderIDs)
+	for _, id := range leaderIDs {
+		leaders[id] = true
+	}
+	return leaders
+}
+
+// resolveReviewerRole 确定投票人的审核角色：任务所属部门负责人为 leader，否则为其参与者角色
+func resolveReviewerRole(leaders map[uint]bool, userID uint, participantRole string) string {
+	if leaders[userID] {
+		return models.ReviewerRoleLeader
+	}
+	if participantRole == "" {
+		return models.ReviewerRoleJury
+	}
+	return participantRole
+}
+
+// pendingReviewParticipants 获取尚未在审核会话中提交意见的陪审团成员/评审专家（按用户去重）
+func pendingReviewParticipants(db *gorm.DB, session *models.ReviewSession) []models.TaskParticipant {
+	var votedIDs []uint
+	db.Model(&models.ReviewRecord{}).Where("review_session_id = ?", session.ID).Pluck("reviewer_id", &votedIDs)
+	voted := make(map[uint]bool, len(votedIDs))
+	for _, id := range votedIDs {
+		voted[id] = true
+	}
+
+	var participants []models.TaskParticipant
+	db.Where("task_id = ? AND role IN ?", session.TaskID, reviewVoterRoles).Order("id ASC").Find(&participants)
+
+	pending := make([]models.TaskParticipant, 0, len(participants))
+	for _, participant := range participants {
+		if voted[participant.UserID] {
+			continue
+		}
+		voted[participant.UserID] = true
+		pending = append(pending, participant)
+	}
+	return pending
+}
+
+// computeVoteTally 统计审核会话当前的投票情况（未投票成员取自任务的陪审团/专家参与者）
+func computeVoteTally(db *gorm.DB, session *models.ReviewSession, task *models.Task) reviewVoteTally {
+	var records []models.ReviewRecord
+	db.Where("review_session_id = ?", session.ID).Find(&records)
+
+	weights := loadReviewRoleWeights(db)
+	leaders := departmentLeaderSet(db, task.DepartmentID)
+	var pendingWeights []float64
+	for _, participant := range pendingReviewParticipants(db, session) {
+		role := resolveReviewerRole(leaders, participant.UserID, participant.Role)
+		pendingWeights = append(pendingWeights, reviewVoteWeight(session.ReviewMode, role, weights))
+	}
+
+	return tallyReviewVotes(records, pendingWeights, session.RequiredApprovals)
+}
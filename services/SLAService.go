@@ -0,0 +1,1008 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// NotificationTypeSLA SLA 违约通知类型
+const NotificationTypeSLA = "sla"
+
+// slaIgnoredChangeTypes 记录了状态字段但没有实际改变任务状态的变更类型
+var slaIgnoredChangeTypes = []string{"transition_requested", "transition_rejected"}
+
+type SLAService struct{}
+
+// statusSegment 任务在某个状态中的一段停留
+type statusSegment struct {
+	StatusCode string
+	EnteredAt  time.Time
+	// 离开时间（仍处于该状态时为 nil）
+	LeftAt *time.Time
+}
+
+// slaTimer 一段状态停留按 SLA 策略计算的计时结果
+type slaTimer struct {
+	Policy   *models.SLAPolicy
+	Segment  statusSegment
+	DueAt    time.Time
+	Elapsed  time.Duration
+	Breached bool
+	Running  bool
+}
+
+// buildStatusSegments 根据状态变更日志（按时间升序）还原任务的状态停留区间
+// 第一段从任务创建时间开始，状态取第一条日志的变更前值；没有日志时整段为当前状态
+func buildStatusSegments(createdAt time.Time, currentStatus string, logs []models.TaskChangeLog) []statusSegment {
+	initial := currentStatus
+	if len(logs) > 0 && logs[0].OldValue != "" {
+		initial = logs[0].OldValue
+	}
+
+	segments := []statusSegment{{StatusCode: initial, EnteredAt: createdAt}}
+	for _, log := range logs {
+		last := &segments[len(segments)-1]
+		if log.NewValue == "" || log.NewValue == last.StatusCode {
+			continue
+		}
+		leftAt := log.CreatedAt
+		last.LeftAt = &leftAt
+		segments = append(segments, statusSegment{StatusCode: log.NewValue, EnteredAt: log.CreatedAt})
+	}
+	return segments
+}
+
+// matchSLAPolicy 匹配任务类型、状态和优先级对应的 SLA 策略，指定优先级的策略优先于不限优先级的策略
+func matchSLAPolicy(policies []models.SLAPolicy, taskTypeCode, statusCode string, priority int) *models.SLAPolicy {
+	var fallback *models.SLAPolicy
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.IsActive || policy.TaskTypeCode != taskTypeCode || policy.StatusCode != statusCode {
+			continue
+		}
+		if policy.Priority == nil {
+			if fallback == nil {
+				fallback = policy
+			}
+			continue
+		}
+		if *policy.Priority == priority {
+			return policy
+		}
+	}
+	return fallback
+}
+
+// evaluateSLASegment 按工作日历计算一段状态停留的截止时间、已用时间和是否违约
+func evaluateSLASegment(segment statusSegment, policy *models.SLAPolicy, cal *businessCalendar, now time.Time) slaTimer {
+	limit := time.Duration(policy.MaxMinutes) * time.Minute
+	end := now
+	if segment.LeftAt != nil {
+		end = *segment.LeftAt
+	}
+	elapsed := cal.BusinessDuration(segment.EnteredAt, end)
+	return slaTimer{
+		Policy:   policy,
+		Segment:  segment,
+		DueAt:    cal.AddBusinessDuration(segment.EnteredAt, limit),
+		Elapsed:  elapsed,
+		Breached: elapsed > limit,
+		Running:  segment.LeftAt == nil,
+	}
+}
+
+// slaContext 一次 SLA 计算所需的策略和工作日历
+type slaContext struct {
+	policies        []models.SLAPolicy
+	calendars       map[uint]*businessCalendar
+	deptCalendars   map[uint]*businessCalendar
+	defaultCalendar *businessCalendar
+}
+
+// loadSLAContext 加载启用的 SLA 策略和全部工作日历
+func loadSLAContext() (*slaContext, error) {
+	ctx := &slaContext{
+		calendars:     make(map[uint]*businessCalendar),
+		deptCalendars: make(map[uint]*businessCalendar),
+	}
+	if err := database.DB.Where("is_active = ?", true).Find(&ctx.policies).Error; err != nil {
+		return nil, err
+	}
+
+	var calendars []models.BusinessCalendar
+	if err := database.DB.Preload("Holidays").Find(&calendars).Error; err != nil {
+		return nil, err
+	}
+	for i := range calendars {
+		cal, err := newBusinessCalendar(&calendars[i])
+		if err != nil {
+			utils.Logger.Warnf("工作日历 %d 配置无效，已忽略: %v", calendars[i].ID, err)
+			continue
+		}
+		ctx.calendars[calendars[i].ID] = cal
+		if calendars[i].DepartmentID != nil {
+			ctx.deptCalendars[*calendars[i].DepartmentID] = cal
+		}
+		if calendars[i].IsDefault {
+			ctx.defaultCalendar = cal
+		}
+	}
+	return ctx, nil
+}
+
+// calendarFor 选择计时使用的工作日历：策略指定的日历 > 任务所属部门的日历 > 默认日历 > 自然时间
+func (c *slaContext) calendarFor(policy *models.SLAPolicy, task *models.Task) *businessCalendar {
+	if policy.CalendarID != nil {
+		if cal, ok := c.calendars[*policy.CalendarID]; ok {
+			return cal
+		}
+	}
+	if task.DepartmentID != nil {
+		if cal, ok := c.deptCalendars[*task.DepartmentID]; ok {
+			return cal
+		}
+	}
+	return c.defaultCalendar
+}
+
+// taskTimers 计算任务每段状态停留的 SLA 计时（只返回有匹配策略的状态）
+func (c *slaContext) taskTimers(task *models.Task, logs []models.TaskChangeLog, now time.Time) []slaTimer {
+	var timers []slaTimer
+	for _, segment := range buildStatusSegments(task.CreatedAt, task.StatusCode, logs) {
+		policy := matchSLAPolicy(c.policies, task.TaskTypeCode, segment.StatusCode, task.Priority)
+		if policy == nil {
+			continue
+		}
+		timers = append(timers, evaluateSLASegment(segment, policy, c.calendarFor(policy, task), now))
+	}
+	return timers
+}
+
+// policyStatusCodes 返回有 SLA 策略的状态编码
+func (c *slaContext) policyStatusCodes() []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, policy := range c.policies {
+		if !seen[policy.StatusCode] {
+			seen[policy.StatusCode] = true
+			codes = append(codes, policy.StatusCode)
+		}
+	}
+	return codes
+}
+
+// policyTaskTypeCodes 返回有 SLA 策略的任务类型编码
+func (c *slaContext) policyTaskTypeCodes() []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, policy := range c.policies {
+		if !seen[policy.TaskTypeCode] {
+			seen[policy.TaskTypeCode] = true
+			codes = append(codes, policy.TaskTypeCode)
+		}
+	}
+	return codes
+}
+
+// loadStatusLogs 批量加载任务的状态变更日志（按时间升序，忽略未实际改变状态的审批申请记录）
+func loadStatusLogs(taskIDs []uint) (map[uint][]models.TaskChangeLog, error) {
+	logsByTask := make(map[uint][]models.TaskChangeLog)
+	if len(taskIDs) == 0 {
+		return logsByTask, nil
+	}
+	var logs []models.TaskChangeLog
+	if err := database.DB.Where("task_id IN ? AND field_name = ? AND change_type NOT IN ?",
+		taskIDs, "status_code", slaIgnoredChangeTypes).
+		Order("task_id, created_at, id").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		logsByTask[log.TaskID] = append(logsByTask[log.TaskID], log)
+	}
+	return logsByTask, nil
+}
+
+// OnStatusChange 任务状态变更时解除该任务未解除的违约事件
+func (s *SLAService) OnStatusChange(db *gorm.DB, task *models.Task, now time.Time) error {
+	if err := db.Model(&models.SLABreach{}).
+		Where("task_id = ? AND resolved_at IS NULL", task.ID).
+		Update("resolved_at", now).Error; err != nil {
+		return fmt.Errorf("解除 SLA 违约失败: %v", err)
+	}
+	return nil
+}
+
+// ProcessSLABreaches 检查处于受 SLA 约束状态的任务，超时时记录违约事件并通知执行人、创建人和部门负责人
+// 同一次状态停留只记录一次违约
+// runCtx 取消或超时后停止处理剩余批次
+func (s *SLAService) ProcessSLABreaches(runCtx context.Context, now time.Time) (int, error) {
+	ctx, err := loadSLAContext()
+	if err != nil {
+		return 0, err
+	}
+	if len(ctx.policies) == 0 {
+		return 0, nil
+	}
+
+	created := 0
+	var tasks []models.Task
+	err = database.DB.WithContext(runCtx).
+		Where("task_type_code IN ? AND status_code IN ?", ctx.policyTaskTypeCodes(), ctx.policyStatusCodes()).
+		FindInBatches(&tasks, 200, func(batch *gorm.DB, _ int) error {
+			if err := runCtx.Err(); err != nil {
+				return err
+			}
+			taskIDs := make([]uint, 0, len(tasks))
+			for _, task := range tasks {
+				taskIDs = append(taskIDs, task.ID)
+			}
+			logsByTask, err := loadStatusLogs(taskIDs)
+			if err != nil {
+				return err
+			}
+
+			for i := range tasks {
+				task := &tasks[i]
+				timers := ctx.taskTimers(task, logsByTask[task.ID], now)
+				if len(timers) == 0 {
+					continue
+				}
+				timer := timers[len(timers)-1]
+				if !timer.Running || !timer.Breached || timer.Segment.StatusCode != task.StatusCode {
+					continue
+				}
+				if s.recordBreach(task, &timer, now) {
+					created++
+				}
+			}
+			return nil
+		}).Error
+	return created, err
+}
+
+// recordBreach 记录违约事件并发送通知，已记录过时返回 false
+func (s *SLAService) recordBreach(task *models.Task, timer *slaTimer, now time.Time) bool {
+	var count int64
+	database.DB.Model(&models.SLABreach{}).
+		Where("task_id = ? AND policy_id = ? AND entered_at = ?", task.ID, timer.Policy.ID, timer.Segment.EnteredAt).
+		Count(&count)
+	if count > 0 {
+		return false
+	}
+
+	breach := &models.SLABreach{
+		TaskID:       task.ID,
+		PolicyID:     timer.Policy.ID,
+		StatusCode:   timer.Segment.StatusCode,
+		EnteredAt:    timer.Segment.EnteredAt,
+		DueAt:        timer.DueAt,
+		BreachedAt:   now,
+		ExecutorID:   task.ExecutorID,
+		DepartmentID: task.DepartmentID,
+	}
+	if err := database.DB.Create(breach).Error; err != nil {
+		utils.Logger.Warnf("记录任务 %d 的 SLA 违约失败: %v", task.ID, err)
+		return false
+	}
+
+	recipients := taskCreatorAndLeaderIDs(task)
+	if task.ExecutorID != nil {
+		recipients = append(recipients, *task.ExecutorID)
+	}
+	content := fmt.Sprintf("任务「%s」在当前状态的停留时间已超过 SLA「%s」的限定（%d 分钟），截止时间 %s",
+		task.Title, timer.Policy.Name, timer.Policy.MaxMinutes, timer.DueAt.Format(dto.TimeFormatDatetime))
+	(&NotificationService{}).Notify(recipients, &task.ID, NotificationTypeSLA, "SLA 违约", content)
+	return true
+}
+
+// GetTaskTimers 获取任务的 SLA 计时（按状态历史计算，包含已结束和进行中的计时）
+// 只能查看当前用户可见范围内的任务
+func (s *SLAService) GetTaskTimers(taskID uint, userID uint) ([]dto.SLATimerResponse, error) {
+	visibility, visibilityArgs, err := (&TaskService{}).buildTaskVisibilityCondition(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	query := database.DB.Where("id = ?", taskID)
+	if visibility != "" {
+		query = query.Where(visibility, visibilityArgs...)
+	}
+	var task models.Task
+	if err := query.First(&task).Error; err != nil {
+		return nil, errors.New("任务不存在")
+	}
+
+	ctx, err := loadSLAContext()
+	if err != nil {
+		return nil, err
+	}
+	logsByTask, err := loadStatusLogs([]uint{task.ID})
+	if err != nil {
+		return nil, err
+	}
+	timers := ctx.taskTimers(&task, logsByTask[task.ID], time.Now())
+
+	statusCodes := make([]string, 0, len(timers))
+	for _, timer := range timers {
+		statusCodes = append(statusCodes, timer.Segment.StatusCode)
+	}
+	statusNames := make(map[string]string)
+	var statuses []models.TaskStatus
+	database.DB.Select("code, name").Where("code IN ?", statusCodes).Find(&statuses)
+	for _, status := range statuses {
+		statusNames[status.Code] = status.Name
+	}
+
+	responses := make([]dto.SLATimerResponse, 0, len(timers))
+	for _, timer := range timers {
+		responses = append(responses, dto.SLATimerResponse{
+			PolicyID:       timer.Policy.ID,
+			PolicyName:     timer.Policy.Name,
+			StatusCode:     timer.Segment.StatusCode,
+			StatusName:     statusNames[timer.Segment.StatusCode],
+			EnteredAt:      dto.ToResponseTime(timer.Segment.EnteredAt),
+			LeftAt:         dto.PtrToResponseTime(timer.Segment.LeftAt),
+			DueAt:          dto.ToResponseTime(timer.DueAt),
+			ElapsedMinutes: int64(timer.Elapsed / time.Minute),
+			LimitMinutes:   timer.Policy.MaxMinutes,
+			Breached:       timer.Breached,
+			Running:        timer.Running,
+		})
+	}
+	return responses, nil
+}
+
+// GetBreaches 分页查询 SLA 违约事件（只包含当前用户可见范围内的任务）
+func (s *SLAService) GetBreaches(req *dto.SLABreachQuery, userID uint) (*dto.PaginationResponse, error) {
+	visibility, visibilityArgs, err := (&TaskService{}).buildTaskVisibilityCondition(userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.DB.Model(&models.SLABreach{})
+	if visibility != "" {
+		query = query.Where("task_id IN (SELECT id FROM tasks WHERE "+visibility+")", visibilityArgs...)
+	}
+	if req.TaskID != nil {
+		query = query.Where("task_id = ?", *req.TaskID)
+	}
+	if req.DepartmentID != nil {
+		query = query.Where("department_id = ?", *req.DepartmentID)
+	}
+	if req.ExecutorID != nil {
+		query = query.Where("executor_id = ?", *req.ExecutorID)
+	}
+	if req.OnlyOpen {
+		query = query.Where("resolved_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page := req.GetPage()
+	pageSize := req.GetPageSize()
+	var breaches []models.SLABreach
+	if err := query.Order("breached_at DESC, id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&breaches).Error; err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]uint, 0, len(breaches))
+	policyIDs := make([]uint, 0, len(breaches))
+	userIDs := make([]uint, 0, len(breaches))
+	deptIDs := make([]uint, 0, len(breaches))
+	for _, breach := range breaches {
+		taskIDs = append(taskIDs, breach.TaskID)
+		policyIDs = append(policyIDs, breach.PolicyID)
+		if breach.ExecutorID != nil {
+			userIDs = append(userIDs, *breach.ExecutorID)
+		}
+		if breach.DepartmentID != nil {
+			deptIDs = append(deptIDs, *breach.DepartmentID)
+		}
+	}
+
+	tasks := make(map[uint]models.Task)
+	var taskList []models.Task
+	database.DB.Unscoped().Select("id, task_no, title").Where("id IN ?", uniqueUintSlice(taskIDs)).Find(&taskList)
+	for _, task := range taskList {
+		tasks[task.ID] = task
+	}
+	policyNames := make(map[uint]string)
+	var policies []models.SLAPolicy
+	database.DB.Unscoped().Select("id, name").Where("id IN ?", uniqueUintSlice(policyIDs)).Find(&policies)
+	for _, policy := range policies {
+		policyNames[policy.ID] = policy.Name
+	}
+	usernames := loadUsernames(userIDs)
+	deptNames := loadDepartmentNames(deptIDs)
+
+	responses := make([]dto.SLABreachResponse, 0, len(breaches))
+	for _, breach := range breaches {
+		resp := dto.SLABreachResponse{
+			ID:           breach.ID,
+			TaskID:       breach.TaskID,
+			TaskNo:       tasks[breach.TaskID].TaskNo,
+			TaskTitle:    tasks[breach.TaskID].Title,
+			PolicyID:     breach.PolicyID,
+			PolicyName:   policyNames[breach.PolicyID],
+			StatusCode:   breach.StatusCode,
+			EnteredAt:    dto.ToResponseTime(breach.EnteredAt),
+			DueAt:        dto.ToResponseTime(breach.DueAt),
+			BreachedAt:   dto.ToResponseTime(breach.BreachedAt),
+			ExecutorID:   breach.ExecutorID,
+			DepartmentID: breach.DepartmentID,
+			ResolvedAt:   dto.PtrToResponseTime(breach.ResolvedAt),
+		}
+		if breach.ExecutorID != nil {
+			resp.ExecutorName = usernames[*breach.ExecutorID]
+		}
+		if breach.DepartmentID != nil {
+			resp.DepartmentName = deptNames[*breach.DepartmentID]
+		}
+		responses = append(responses, resp)
+	}
+
+	return &dto.PaginationResponse{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+		Data:       responses,
+	}, nil
+}
+
+// slaComplianceCounter SLA 达成统计累加器
+type slaComplianceCounter struct {
+	total, met, breached, running int
+	elapsed                       time.Duration
+}
+
+// add 累加一条计时：违约（含进行中已超时）、已结束达标、进行中未超时
+func (c *slaComplianceCounter) add(timer *slaTimer) {
+	c.total++
+	c.elapsed += timer.Elapsed
+	switch {
+	case timer.Breached:
+		c.breached++
+	case timer.Running:
+		c.running++
+	default:
+		c.met++
+	}
+}
+
+// toItem 转换为统计响应，达成率 = 达标 / (达标 + 违约)
+func (c *slaComplianceCounter) toItem(id uint, name string) dto.SLAComplianceItem {
+	item := dto.SLAComplianceItem{
+		ID:             id,
+		Name:           name,
+		Total:          c.total,
+		Met:            c.met,
+		Breached:       c.breached,
+		Running:        c.running,
+		ComplianceRate: 100,
+	}
+	if finished := c.met + c.breached; finished > 0 {
+		item.ComplianceRate = math.Round(float64(c.met)/float64(finished)*10000) / 100
+	}
+	if c.total > 0 {
+		item.AvgElapsedMinutes = math.Round(c.elapsed.Minutes()/float64(c.total)*100) / 100
+	}
+	return item
+}
+
+// GetComplianceReport 统计 SLA 达成率（汇总、按任务所属部门、按执行人），按进入状态的时间筛选
+// 只统计当前用户可见范围内的任务
+func (s *SLAService) GetComplianceReport(req *dto.SLAReportQuery, userID uint) (*dto.SLAReportResponse, error) {
+	startTime, err := ParseDateTime(req.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("开始时间格式错误: %v", err)
+	}
+	endTime, err := ParseDateTime(req.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("结束时间格式错误: %v", err)
+	}
+
+	result := &dto.SLAReportResponse{
+		ByDepartment: []dto.SLAComplianceItem{},
+		ByExecutor:   []dto.SLAComplianceItem{},
+	}
+	ctx, err := loadSLAContext()
+	if err != nil {
+		return nil, err
+	}
+	summary := &slaComplianceCounter{}
+	if len(ctx.policies) == 0 {
+		result.Summary = summary.toItem(0, "汇总")
+		return result, nil
+	}
+
+	now := time.Now()
+	byDept := make(map[uint]*slaComplianceCounter)
+	byExecutor := make(map[uint]*slaComplianceCounter)
+
+	visibility, visibilityArgs, err := (&TaskService{}).buildTaskVisibilityCondition(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	query := database.DB.Where("task_type_code IN ?", ctx.policyTaskTypeCodes())
+	if visibility != "" {
+		query = query.Where(visibility, visibilityArgs...)
+	}
+	if req.DepartmentID != nil {
+		query = query.Where("department_id = ?", *req.DepartmentID)
+	}
+	if endTime != nil {
+		query = query.Where("created_at <= ?", *endTime)
+	}
+
+	var tasks []models.Task
+	err = query.FindInBatches(&tasks, 200, func(batch *gorm.DB, _ int) error {
+		taskIDs := make([]uint, 0, len(tasks))
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		logsByTask, err := loadStatusLogs(taskIDs)
+		if err != nil {
+			return err
+		}
+
+		for i := range tasks {
+			task := &tasks[i]
+			for _, timer := range ctx.taskTimers(task, logsByTask[task.ID], now) {
+				if startTime != nil && timer.Segment.EnteredAt.Before(*startTime) {
+					continue
+				}
+				if endTime != nil && timer.Segment.EnteredAt.After(*endTime) {
+					continue
+				}
+				summary.add(&timer)
+				if task.DepartmentID != nil {
+					if byDept[*task.DepartmentID] == nil {
+						byDept[*task.DepartmentID] = &slaComplianceCounter{}
+					}
+					byDept[*task.DepartmentID].add(&timer)
+				}
+				if task.ExecutorID != nil {
+					if byExecutor[*task.ExecutorID] == nil {
+						byExecutor[*task.ExecutorID] = &slaComplianceCounter{}
+					}
+					byExecutor[*task.ExecutorID].add(&timer)
+				}
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result.Summary = summary.toItem(0, "汇总")
+
+	deptIDs := make([]uint, 0, len(byDept))
+	for id := range byDept {
+		deptIDs = append(deptIDs, id)
+	}
+	deptNames := loadDepartmentNames(deptIDs)
+	for _, id := range deptIDs {
+		result.ByDepartment = append(result.ByDepartment, byDept[id].toItem(id, deptNames[id]))
+	}
+
+	userIDs := make([]uint, 0, len(byExecutor))
+	for id := range byExecutor {
+		userIDs = append(userIDs, id)
+	}
+	usernames := loadUsernames(userIDs)
+	for _, id := range userIDs {
+		result.ByExecutor = append(result.ByExecutor, byExecutor[id].toItem(id, usernames[id]))
+	}
+
+	sortComplianceItems(result.ByDepartment)
+	sortComplianceItems(result.ByExecutor)
+	return result, nil
+}
+
+// sortComplianceItems 按达成率升序排列（达成率相同时按ID）
+func sortComplianceItems(items []dto.SLAComplianceItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ComplianceRate != items[j].ComplianceRate {
+			return items[i].ComplianceRate < items[j].ComplianceRate
+		}
+		return items[i].ID < items[j].ID
+	})
+}
+
+// GetPolicyList 获取 SLA 策略列表
+func (s *SLAService) GetPolicyList() ([]dto.SLAPolicyResponse, error) {
+	var policies []models.SLAPolicy
+	if err := database.DB.Order("task_type_code, status_code, id").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	responses := make([]dto.SLAPolicyResponse, 0, len(policies))
+	for i := range policies {
+		responses = append(responses, toSLAPolicyResponse(&policies[i]))
+	}
+	return responses, nil
+}
+
+// CreatePolicy 创建 SLA 策略
+func (s *SLAService) CreatePolicy(req *dto.SLAPolicyRequest) (*dto.SLAPolicyResponse, error) {
+	if err := s.validatePolicy(0, req); err != nil {
+		return nil, err
+	}
+
+	policy := &models.SLAPolicy{
+		Name:         req.Name,
+		TaskTypeCode: req.TaskTypeCode,
+		StatusCode:   req.StatusCode,
+		Priority:     req.Priority,
+		MaxMinutes:   req.MaxMinutes,
+		CalendarID:   req.CalendarID,
+		IsActive:     req.IsActive == nil || *req.IsActive,
+		Description:  req.Description,
+	}
+	if err := database.DB.Create(policy).Error; err != nil {
+		return nil, fmt.Errorf("创建 SLA 策略失败: %v", err)
+	}
+
+	resp := toSLAPolicyResponse(policy)
+	return &resp, nil
+}
+
+// UpdatePolicy 更新 SLA 策略
+func (s *SLAService) UpdatePolicy(id uint, req *dto.SLAPolicyRequest) (*dto.SLAPolicyResponse, error) {
+	var policy models.SLAPolicy
+	if err := database.DB.First(&policy, id).Error; err != nil {
+		return nil, errors.New("SLA 策略不存在")
+	}
+	if err := s.validatePolicy(id, req); err != nil {
+		return nil, err
+	}
+
+	isActive := policy.IsActive
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	if err := database.DB.Model(&policy).
+		Select("name", "task_type_code", "status_code", "priority", "max_minutes", "calendar_id", "is_active", "description").
+		Updates(models.SLAPolicy{
+			Name:         req.Name,
+			TaskTypeCode: req.TaskTypeCode,
+			StatusCode:   req.StatusCode,
+			Priority:     req.Priority,
+			MaxMinutes:   req.MaxMinutes,
+			CalendarID:   req.CalendarID,
+			IsActive:     isActive,
+			Description:  req.Description,
+		}).Error; err != nil {
+		return nil, fmt.Errorf("更新 SLA 策略失败: %v", err)
+	}
+
+	database.DB.First(&policy, policy.ID)
+	resp := toSLAPolicyResponse(&policy)
+	return &resp, nil
+}
+
+// DeletePolicy 删除 SLA 策略（已记录的违约事件保留）
+func (s *SLAService) DeletePolicy(id uint) error {
+	var policy models.SLAPolicy
+	if err := database.DB.First(&policy, id).Error; err != nil {
+		return errors.New("SLA 策略不存在")
+	}
+	return database.DB.Delete(&policy).Error
+}
+
+// validatePolicy 校验策略的任务类型、状态和日历，并确保同一类型、状态和优先级只有一个策略
+func (s *SLAService) validatePolicy(id uint, req *dto.SLAPolicyRequest) error {
+	var count int64
+	database.DB.Model(&models.TaskStatus{}).
+		Where("code = ? AND task_type_code = ?", req.StatusCode, req.TaskTypeCode).
+		Count(&count)
+	if count == 0 {
+		return fmt.Errorf("状态 %s 不属于任务类型 %s", req.StatusCode, req.TaskTypeCode)
+	}
+
+	if req.CalendarID != nil {
+		database.DB.Model(&models.BusinessCalendar{}).Where("id = ?", *req.CalendarID).Count(&count)
+		if count == 0 {
+			return errors.New("工作日历不存在")
+		}
+	}
+
+	query := database.DB.Model(&models.SLAPolicy{}).
+		Where("task_type_code = ? AND status_code = ? AND id <> ?", req.TaskTypeCode, req.StatusCode, id)
+	if req.Priority != nil {
+		query = query.Where("priority = ?", *req.Priority)
+	} else {
+		query = query.Where("priority IS NULL")
+	}
+	query.Count(&count)
+	if count > 0 {
+		return errors.New("该任务类型、状态和优先级已存在 SLA 策略")
+	}
+	return nil
+}
+
+// toSLAPolicyResponse 转换 SLA 策略响应
+func toSLAPolicyResponse(policy *models.SLAPolicy) dto.SLAPolicyResponse {
+	return dto.SLAPolicyResponse{
+		ID:           policy.ID,
+		Name:         policy.Name,
+		TaskTypeCode: policy.TaskTypeCode,
+		StatusCode:   policy.StatusCode,
+		Priority:     policy.Priority,
+		MaxMinutes:   policy.MaxMinutes,
+		CalendarID:   policy.CalendarID,
+		IsActive:     policy.IsActive,
+		Description:  policy.Description,
+		CreatedAt:    dto.ToResponseTime(policy.CreatedAt),
+	}
+}
+
+// GetCalendarList 获取工作日历列表（含节假日）
+func (s *SLAService) GetCalendarList() ([]dto.BusinessCalendarResponse, error) {
+	var calendars []models.BusinessCalendar
+	if err := database.DB.Preload("Holidays", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date")
+	}).Order("id").Find(&calendars).Error; err != nil {
+		return nil, err
+	}
+
+	deptIDs := make([]uint, 0, len(calendars))
+	for _, calendar := range calendars {
+		if calendar.DepartmentID != nil {
+			deptIDs = append(deptIDs, *calendar.DepartmentID)
+		}
+	}
+	deptNames := loadDepartmentNames(deptIDs)
+
+	responses := make([]dto.BusinessCalendarResponse, 0, len(calendars))
+	for i := range calendars {
+		responses = append(responses, toBusinessCalendarResponse(&calendars[i], deptNames))
+	}
+	return responses, nil
+}
+
+// CreateCalendar 创建工作日历
+func (s *SLAService) CreateCalendar(req *dto.BusinessCalendarRequest) (*dto.BusinessCalendarResponse, error) {
+	calendar, err := s.buildCalendar(0, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if calendar.IsDefault {
+		if err := tx.Model(&models.BusinessCalendar{}).Where("is_default = ?", true).
+			Update("is_default", false).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Create(calendar).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("创建工作日历失败: %v", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.getCalendar(calendar.ID)
+}
+
+// UpdateCalendar 更新工作日历，节假日整体替换
+func (s *SLAService) UpdateCalendar(id uint, req *dto.BusinessCalendarRequest) (*dto.BusinessCalendarResponse, error) {
+	var existing models.BusinessCalendar
+	if err := database.DB.First(&existing, id).Error; err != nil {
+		return nil, errors.New("工作日历不存在")
+	}
+	calendar, err := s.buildCalendar(id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if calendar.IsDefault {
+		if err := tx.Model(&models.BusinessCalendar{}).Where("is_default = ? AND id <> ?", true, id).
+			Update("is_default", false).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Model(&existing).
+		Select("name", "department_id", "is_default", "time_zone", "work_start", "work_end", "work_days").
+		Updates(models.BusinessCalendar{
+			Name:         calendar.Name,
+			DepartmentID: calendar.DepartmentID,
+			IsDefault:    calendar.IsDefault,
+			TimeZone:     calendar.TimeZone,
+			WorkStart:    calendar.WorkStart,
+			WorkEnd:      calendar.WorkEnd,
+			WorkDays:     calendar.WorkDays,
+		}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("更新工作日历失败: %v", err)
+	}
+	if err := tx.Unscoped().Where("calendar_id = ?", id).Delete(&models.CalendarHoliday{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for i := range calendar.Holidays {
+		calendar.Holidays[i].CalendarID = id
+	}
+	if len(calendar.Holidays) > 0 {
+		if err := tx.Create(&calendar.Holidays).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存节假日失败: %v", err)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.getCalendar(id)
+}
+
+// DeleteCalendar 删除工作日历，被 SLA 策略引用时不允许删除
+func (s *SLAService) DeleteCalendar(id uint) error {
+	var calendar models.BusinessCalendar
+	if err := database.DB.First(&calendar, id).Error; err != nil {
+		return errors.New("工作日历不存在")
+	}
+
+	var policyCount int64
+	database.DB.Model(&models.SLAPolicy{}).Where("calendar_id = ?", id).Count(&policyCount)
+	if policyCount > 0 {
+		return fmt.Errorf("该日历被 %d 个 SLA 策略使用，无法删除", policyCount)
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("calendar_id = ?", id).Delete(&models.CalendarHoliday{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&calendar).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// buildCalendar 根据请求构建日历模型（填充默认值并校验配置）
+func (s *SLAService) buildCalendar(id uint, req *dto.BusinessCalendarRequest) (*models.BusinessCalendar, error) {
+	calendar := &models.BusinessCalendar{
+		Name:         req.Name,
+		DepartmentID: req.DepartmentID,
+		IsDefault:    req.IsDefault,
+		TimeZone:     req.TimeZone,
+		WorkStart:    req.WorkStart,
+		WorkEnd:      req.WorkEnd,
+		WorkDays:     datatypes.NewJSONSlice(req.WorkDays),
+	}
+	if calendar.TimeZone == "" {
+		calendar.TimeZone = "Asia/Shanghai"
+	}
+	if _, err := time.LoadLocation(calendar.TimeZone); err != nil {
+		return nil, fmt.Errorf("无效的时区 %s", calendar.TimeZone)
+	}
+	if calendar.WorkStart == "" {
+		calendar.WorkStart = "09:00"
+	}
+	if calendar.WorkEnd == "" {
+		calendar.WorkEnd = "18:00"
+	}
+	if len(calendar.WorkDays) == 0 {
+		calendar.WorkDays = datatypes.NewJSONSlice([]int{1, 2, 3, 4, 5})
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range req.Holidays {
+		date, err := time.Parse(dateKeyFormat, item.Date)
+		if err != nil {
+			return nil, fmt.Errorf("无效的日期 %s", item.Date)
+		}
+		if seen[item.Date] {
+			return nil, fmt.Errorf("日期 %s 重复", item.Date)
+		}
+		seen[item.Date] = true
+		calendar.Holidays = append(calendar.Holidays, models.CalendarHoliday{
+			Date:      date,
+			Name:      item.Name,
+			IsWorkday: item.IsWorkday,
+		})
+	}
+
+	if _, err := newBusinessCalendar(calendar); err != nil {
+		return nil, err
+	}
+
+	if req.DepartmentID != nil {
+		var count int64
+		database.DB.Model(&models.Department{}).Where("id = ?", *req.DepartmentID).Count(&count)
+		if count == 0 {
+			return nil, errors.New("部门不存在")
+		}
+		database.DB.Model(&models.BusinessCalendar{}).
+			Where("department_id = ? AND id <> ?", *req.DepartmentID, id).
+			Count(&count)
+		if count > 0 {
+			return nil, errors.New("该部门已配置工作日历")
+		}
+	}
+	return calendar, nil
+}
+
+// getCalendar 获取单个工作日历响应
+func (s *SLAService) getCalendar(id uint) (*dto.BusinessCalendarResponse, error) {
+	var calendar models.BusinessCalendar
+	if err := database.DB.Preload("Holidays", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date")
+	}).First(&calendar, id).Error; err != nil {
+		return nil, errors.New("工作日历不存在")
+	}
+	var deptIDs []uint
+	if calendar.DepartmentID != nil {
+		deptIDs = append(deptIDs, *calendar.DepartmentID)
+	}
+	resp := toBusinessCalendarResponse(&calendar, loadDepartmentNames(deptIDs))
+	return &resp, nil
+}
+
+// toBusinessCalendarResponse 转换工作日历响应
+func toBusinessCalendarResponse(calendar *models.BusinessCalendar, deptNames map[uint]string) dto.BusinessCalendarResponse {
+	resp := dto.BusinessCalendarResponse{
+		ID:           calendar.ID,
+		Name:         calendar.Name,
+		DepartmentID: calendar.DepartmentID,
+		IsDefault:    calendar.IsDefault,
+		TimeZone:     calendar.TimeZone,
+		WorkStart:    calendar.WorkStart,
+		WorkEnd:      calendar.WorkEnd,
+		WorkDays:     []int(calendar.WorkDays),
+		Holidays:     make([]dto.CalendarHolidayItem, 0, len(calendar.Holidays)),
+		CreatedAt:    dto.ToResponseTime(calendar.CreatedAt),
+	}
+	if calendar.DepartmentID != nil {
+		resp.DepartmentName = deptNames[*calendar.DepartmentID]
+	}
+	for _, holiday := range calendar.Holidays {
+		resp.Holidays = append(resp.Holidays, dto.CalendarHolidayItem{
+			Date:      holiday.Date.Format(dateKeyFormat),
+			Name:      holiday.Name,
+			IsWorkday: holiday.IsWorkday,
+		})
+	}
+	return resp
+}
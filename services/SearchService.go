@@ -0,0 +1,397 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/utils"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+type SearchService struct{}
+
+const (
+	SearchModeFullText = "fulltext" // PostgreSQL 全文检索（tsvector，如 zhparser 中文分词）
+	SearchModeTrigram  = "trigram"  // pg_trgm 三元组模糊匹配
+	SearchModeLike     = "like"     // 普通 ILIKE 模糊匹配（兜底）
+)
+
+// 高亮标记占位符：先用控制字符标记命中位置，HTML 转义后再替换为 <mark>
+const (
+	highlightStart = "\x01"
+	highlightStop  = "\x02"
+)
+
+var (
+	searchModeOnce sync.Once
+	searchMode     string
+	searchTSConfig string
+
+	tsConfigNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+)
+
+// searchSource 检索来源定义
+type searchSource struct {
+	// 文档类型
+	docType string
+	// FROM 子句
+	from string
+	// 输出列：task_id, target_id, version, title, created_at
+	columns string
+	// 参与检索的文本表达式（需与迁移脚本中的索引表达式保持一致）
+	body string
+	// 基础过滤条件
+	where string
+	// 基础过滤条件是否需要当前用户ID参数
+	needUserID bool
+}
+
+// searchSources 参与检索的文档来源
+var searchSources = []searchSource{
+	{
+		docType: "task",
+		from:    "tasks t",
+		columns: "t.id, t.id, 0, t.title, t.created_at",
+		body:    "coalesce(t.title, '') || ' ' || coalesce(t.description, '')",
+		where:   "t.deleted_at IS NULL",
+	},
+	{
+		docType: "solution",
+		from:    "requirement_solutions rs",
+		columns: "rs.task_id, rs.id, rs.version, rs.title, rs.created_at",
+		body:    "coalesce(rs.title, '') || ' ' || coalesce(rs.content, '') || ' ' || coalesce(rs.mindmap_markdown, '')",
+		where:   "1 = 1",
+	},
+	{
+		docType: "plan",
+		from:    "execution_plans ep",
+		columns: "ep.task_id, ep.id, ep.version, ep.title, ep.created_at",
+		body:    "coalesce(ep.title, '') || ' ' || coalesce(ep.tech_stack, '') || ' ' || coalesce(ep.implementation_steps::text, '') || ' ' || coalesce(ep.resource_requirements, '') || ' ' || coalesce(ep.risk_assessment, '')",
+		where:   "1 = 1",
+	},
+	{
+		docType:    "comment",
+		from:       "task_comments tc",
+		columns:    "tc.task_id, tc.id, 0, '', tc.created_at",
+		body:       "coalesce(tc.content, '')",
+		where:      "tc.deleted_at IS NULL AND (tc.is_private = false OR tc.user_id = ?)",
+		needUserID: true,
+	},
+}
+
+// searchRow 检索结果行
+type searchRow struct {
+	DocType        string
+	TaskID         uint
+	TargetID       uint
+	Version        int
+	Title          string
+	Body           string
+	CreatedAt      time.Time
+	Rank           float64
+	Headline       string
+	TaskNo         string
+	TaskTitle      string
+	TaskStatusCode string
+}
+
+// Search 全文检索任务标题/描述、思路方案、执行计划和评论
+// 结果按相关度排序，并按当前用户的任务可见范围过滤
+func (s *SearchService) Search(req *dto.SearchRequest, userID uint) (*dto.SearchResponse, error) {
+	keyword := strings.TrimSpace(req.Q)
+	if keyword == "" {
+		return nil, errors.New("检索关键词不能为空")
+	}
+
+	sources, err := s.selectSources(req.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, tsConfig := s.detectSearchMode()
+
+	// 1. 构建各来源的检索子查询（UNION ALL）
+	var unionParts []string
+	var args []interface{}
+	likePattern := "%" + escapeLikePattern(keyword) + "%"
+	for _, src := range sources {
+		var match, rank string
+		var matchArgs, rankArgs []interface{}
+		switch mode {
+		case SearchModeFullText:
+			vector := fmt.Sprintf("to_tsvector('%s', %s)", tsConfig, src.body)
+			query := fmt.Sprintf("plainto_tsquery('%s', ?)", tsConfig)
+			match = vector + " @@ " + query
+			rank = fmt.Sprintf("ts_rank(%s, %s)", vector, query)
+			matchArgs = []interface{}{keyword}
+			rankArgs = []interface{}{keyword}
+		case SearchModeTrigram:
+			match = fmt.Sprintf("(%s ILIKE ? OR ? <%% %s)", src.body, src.body)
+			rank = fmt.Sprintf("word_similarity(?, %s)", src.body)
+			matchArgs = []interface{}{likePattern, keyword}
+			rankArgs = []interface{}{keyword}
+		default:
+			match = fmt.Sprintf("%s ILIKE ?", src.body)
+			rank = "0"
+			matchArgs = []interface{}{likePattern}
+		}
+
+		unionParts = append(unionParts, fmt.Sprintf(
+			"SELECT '%s' AS doc_type, %s, %s AS body, %s AS rank FROM %s WHERE %s AND %s",
+			src.docType, src.columns, src.body, rank, src.from, src.where, match))
+		args = append(args, rankArgs...)
+		if src.needUserID {
+			args = append(args, userID)
+		}
+		args = append(args, matchArgs...)
+	}
+	docs := "(" + strings.Join(unionParts, " UNION ALL ") + ") AS d (doc_type, task_id, target_id, version, title, created_at, body, rank)"
+
+	// 2. 关联任务并按可见范围过滤
+	where := "t.deleted_at IS NULL"
+	visibility, visibilityArgs, err := (&TaskService{}).buildTaskVisibilityCondition(userID, "t")
+	if err != nil {
+		return nil, err
+	}
+	if visibility != "" {
+		where += " AND " + visibility
+		args = append(args, visibilityArgs...)
+	}
+	fromClause := docs + " JOIN tasks t ON t.id = d.task_id WHERE " + where
+
+	// 3. 统计总数
+	var total int64
+	if err := database.DB.Raw("SELECT COUNT(*) FROM "+fromClause, args...).Scan(&total).Error; err != nil {
+		return nil, fmt.Errorf("检索失败: %v", err)
+	}
+
+	page := req.GetPage()
+	pageSize := req.GetPageSize()
+	offset := (page - 1) * pageSize
+
+	// 4. 分页查询（全文检索模式由数据库生成高亮片段）
+	headline := "''"
+	var selectArgs []interface{}
+	if mode == SearchModeFullText {
+		headline = fmt.Sprintf("ts_headline('%s', d.body, plainto_tsquery('%s', ?), ?)", tsConfig, tsConfig)
+		selectArgs = []interface{}{keyword, fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \"", highlightStart, highlightStop)}
+	}
+
+	var rows []searchRow
+	sql := fmt.Sprintf(`SELECT d.doc_type, d.task_id, d.target_id, d.version, d.title, d.body, d.created_at, d.rank,
+		%s AS headline, t.task_no, t.title AS task_title, t.status_code AS task_status_code
+		FROM %s ORDER BY d.rank DESC, d.created_at DESC LIMIT ? OFFSET ?`, headline, fromClause)
+	queryArgs := append(append(selectArgs, args...), pageSize, offset)
+	if err := database.DB.Raw(sql, queryArgs...).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("检索失败: %v", err)
+	}
+
+	// 5. 组装结果
+	items := make([]dto.SearchResultItem, len(rows))
+	for i, row := range rows {
+		snippet := ""
+		if mode == SearchModeFullText && row.Headline != "" {
+			snippet = renderHighlight(row.Headline)
+		} else {
+			snippet = buildSearchSnippet(row.Body, keyword, 40)
+		}
+		title := row.Title
+		if title == "" {
+			title = row.TaskTitle
+		}
+		items[i] = dto.SearchResultItem{
+			Type:           row.DocType,
+			TaskID:         row.TaskID,
+			TaskNo:         row.TaskNo,
+			TaskTitle:      row.TaskTitle,
+			TaskStatusCode: row.TaskStatusCode,
+			TargetID:       row.TargetID,
+			Version:        row.Version,
+			Title:          title,
+			Snippet:        snippet,
+			Rank:           row.Rank,
+			CreatedAt:      dto.ToResponseTime(row.CreatedAt),
+		}
+	}
+
+	return &dto.SearchResponse{
+		PaginationResponse: dto.PaginationResponse{
+			Total:      total,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+			Data:       items,
+		},
+		Mode: mode,
+	}, nil
+}
+
+// selectSources 根据请求的检索范围筛选来源
+func (s *SearchService) selectSources(types string) ([]searchSource, error) {
+	if strings.TrimSpace(types) == "" {
+		return searchSources, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, t := range strings.Split(types, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			wanted[t] = true
+		}
+	}
+
+	if len(wanted) == 0 {
+		return searchSources, nil
+	}
+
+	var sources []searchSource
+	for _, src := range searchSources {
+		if wanted[src.docType] {
+			sources = append(sources, src)
+			delete(wanted, src.docType)
+		}
+	}
+	if len(wanted) > 0 {
+		var unknown []string
+		for t := range wanted {
+			unknown = append(unknown, t)
+		}
+		return nil, fmt.Errorf("不支持的检索范围: %s", strings.Join(unknown, ","))
+	}
+	return sources, nil
+}
+
+// detectSearchMode 检测数据库支持的检索方式（进程内只检测一次）
+// 优先使用配置的全文检索配置（如 zhparser），不存在时降级为 pg_trgm，再降级为 ILIKE
+func (s *SearchService) detectSearchMode() (string, string) {
+	searchModeOnce.Do(func() {
+		searchMode = SearchModeLike
+
+		tsConfig := strings.ToLower(config.GetConfig().Search.TSConfig)
+		if tsConfig != "" && tsConfigNamePattern.MatchString(tsConfig) {
+			var count int64
+			database.DB.Raw("SELECT COUNT(*) FROM pg_ts_config WHERE cfgname = ?", tsConfig).Scan(&count)
+			if count > 0 {
+				searchMode = SearchModeFullText
+				searchTSConfig = tsConfig
+			}
+		}
+
+		if searchMode != SearchModeFullText {
+			var count int64
+			database.DB.Raw("SELECT COUNT(*) FROM pg_extension WHERE extname = 'pg_trgm'").Scan(&count)
+			if count > 0 {
+				searchMode = SearchModeTrigram
+			}
+		}
+
+		utils.Logger.Info(fmt.Sprintf("全文检索模式: %s", searchMode))
+	})
+	return searchMode, searchTSConfig
+}
+
+// escapeLikePattern 转义 LIKE 模式中的通配符
+func escapeLikePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
+}
+
+// renderHighlight 将数据库生成的带占位符片段转为 HTML 安全的高亮片段
+func renderHighlight(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
+
+// buildSearchSnippet 在文本中定位关键词并截取前后 radius 个字符作为片段，命中词以 <mark> 高亮
+// 关键词按空白拆分，大小写不敏感；未直接命中时返回文本开头部分
+func buildSearchSnippet(body, keyword string, radius int) string {
+	text := []rune(body)
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var terms [][]rune
+	for _, term := range strings.Fields(keyword) {
+		t := []rune(term)
+		for i, r := range t {
+			t[i] = unicode.ToLower(r)
+		}
+		terms = append(terms, t)
+	}
+
+	// 标记所有命中位置
+	marked := make([]bool, len(text))
+	first := -1
+	for _, term := range terms {
+		for i := 0; i+len(term) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(term)], term) {
+				for j := i; j < i+len(term); j++ {
+					marked[j] = true
+				}
+				if first == -1 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+
+	start, end := 0, len(text)
+	if first == -1 {
+		if end > radius*2 {
+			end = radius * 2
+		}
+	} else {
+		if first-radius > 0 {
+			start = first - radius
+		}
+		if first+radius*2 < end {
+			end = first + radius*2
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	inMark := false
+	for i := start; i < end; i++ {
+		if marked[i] && !inMark {
+			b.WriteString(highlightStart)
+			inMark = true
+		} else if !marked[i] && inMark {
+			b.WriteString(highlightStop)
+			inMark = false
+		}
+		b.WriteRune(text[i])
+	}
+	if inMark {
+		b.WriteString(highlightStop)
+	}
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return renderHighlight(b.String())
+}
+
+// runesEqual 比较两个 rune 切片是否相等
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 会话结束原因（其余原因见刷新令牌吊销原因）
+const (
+	TokenRevokeKilled = "killed"        // 用户结束自己的会话
+	TokenRevokeAdmin  = "admin_revoked" // 管理员结束会话
+)
+
+// LoginClient 登录请求的客户端信息（用于登记会话）
+type LoginClient struct {
+	// 客户端IP
+	IP string
+	// User-Agent
+	UserAgent string
+	// 客户端上报的设备名称
+	Device string
+}
+
+type SessionService struct{}
+
+// deviceOSRules 按 User-Agent 识别操作系统（按顺序匹配，iPad/iPhone 需先于 Mac 判断）
+var deviceOSRules = []struct{ keyword, name string }{
+	{"iPhone", "iPhone"},
+	{"iPad", "iPad"},
+	{"Android", "Android"},
+	{"HarmonyOS", "HarmonyOS"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"Macintosh", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// deviceClientRules 按 User-Agent 识别客户端（微信和 Edge 的 UA 中同时包含 Chrome/Safari，需先判断）
+var deviceClientRules = []struct{ keyword, name string }{
+	{"miniProgram", "微信小程序"},
+	{"MicroMessenger", "微信"},
+	{"Edg/", "Edge"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+}
+
+// describeDevice 生成会话的设备名称：优先使用客户端上报的名称，否则根据 User-Agent 识别
+func describeDevice(device, userAgent string) string {
+	if device = strings.TrimSpace(device); device != "" {
+		return device
+	}
+
+	var parts []string
+	for _, rule := range deviceOSRules {
+		if strings.Contains(userAgent, rule.keyword) {
+			parts = append(parts, rule.name)
+			break
+		}
+	}
+	for _, rule := range deviceClientRules {
+		if strings.Contains(userAgent, rule.keyword) {
+			parts = append(parts, rule.name)
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return "未知设备"
+	}
+	return strings.Join(parts, " / ")
+}
+
+// truncateRunes 按字符截断字符串（用于限制入库字段长度）
+func truncateRunes(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
+
+// openSession 登记新的登录会话（每次登录开启新的令牌族）
+func openSession(db *gorm.DB, userID uint, client LoginClient, method string, expiresAt, now time.Time) (*models.UserSession, error) {
+	session := &models.UserSession{
+		UserID:      userID,
+		FamilyID:    uuid.NewString(),
+		LoginMethod: method,
+		Device:      truncateRunes(describeDevice(client.Device, client.UserAgent), 100),
+		UserAgent:   truncateRunes(client.UserAgent, 500),
+		IP:          client.IP,
+		LastSeenAt:  now,
+		LastSeenIP:  client.IP,
+		ExpiresAt:   expiresAt,
+	}
+	if err := db.Create(session).Error; err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSessions 查询用户的登录会话（currentSessionID 为当前请求所用的会话，用于标记）
+func (s *SessionService) GetSessions(userID, currentSessionID uint, query *dto.SessionQuery) ([]dto.SessionResponse, error) {
+	now := time.Now()
+	db := database.DB.Where("user_id = ?", userID)
+	if !query.IncludeRevoked {
+		db = db.Where("revoked_at IS NULL AND expires_at > ?", now)
+	}
+
+	var sessions []models.UserSession
+	if err := db.Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return s.toResponses(sessions, currentSessionID), nil
+}
+
+// RevokeSession 用户结束自己的某个登录会话
+func (s *SessionService) RevokeSession(sessionID, userID uint) error {
+	session, err := s.getActiveSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return errors.New("只能结束自己的会话")
+	}
+	return revokeSessions(database.DB, "family_id", session.FamilyID, TokenRevokeKilled, &userID, time.Now())
+}
+
+// RevokeOtherSessions 用户结束除当前会话外的全部登录会话
+func (s *SessionService) RevokeOtherSessions(userID, currentSessionID uint) (*dto.RevokeSessionsResult, error) {
+	var sessions []models.UserSession
+	if err := database.DB.Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, currentSessionID).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range sessions {
+		if err := revokeSessions(database.DB, "family_id", sessions[i].FamilyID, TokenRevokeKilled, &userID, now); err != nil {
+			return nil, err
+		}
+	}
+	return &dto.RevokeSessionsResult{Revoked: int64(len(sessions))}, nil
+}
+
+// AdminRevokeSession 管理员结束任意用户的某个登录会话
+func (s *SessionService) AdminRevokeSession(sessionID, adminID uint) error {
+	session, err := s.getActiveSession(sessionID)
+	if err != nil {
+		return err
+	}
+	return revokeSessions(database.DB, "family_id", session.FamilyID, TokenRevokeAdmin, &adminID, time.Now())
+}
+
+// AdminRevokeUserSessions 管理员结束用户的全部登录会话
+func (s *SessionService) AdminRevokeUserSessions(userID, adminID uint) (*dto.RevokeSessionsResult, error) {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	var count int64
+	database.DB.Model(&models.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userID).Count(&count)
+	if err := revokeSessions(database.DB, "user_id", userID, TokenRevokeAdmin, &adminID, time.Now()); err != nil {
+		return nil, err
+	}
+	return &dto.RevokeSessionsResult{Revoked: count}, nil
+}
+
+// getActiveSession 查询未结束的登录会话
+func (s *SessionService) getActiveSession(sessionID uint) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := database.DB.First(&session, sessionID).Error; err != nil {
+		return nil, errors.New("会话不存在")
+	}
+	if session.RevokedAt != nil {
+		return nil, errors.New("会话已结束")
+	}
+	return &session, nil
+}
+
+// toResponses 转换会话响应
+func (s *SessionService) toResponses(sessions []models.UserSession, currentSessionID uint) []dto.SessionResponse {
+	userIDs := make([]uint, 0, len(sessions))
+	for _, session := range sessions {
+		userIDs = append(userIDs, session.UserID)
+	}
+	names := loadUsernames(userIDs)
+
+	responses := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, dto.SessionResponse{
+			ID:           session.ID,
+			UserID:       session.UserID,
+			Username:     names[session.UserID],
+			LoginMethod:  session.LoginMethod,
+			Device:       session.Device,
+			UserAgent:    session.UserAgent,
+			IP:           session.IP,
+			LastSeenIP:   session.LastSeenIP,
+			CreatedAt:    dto.ToResponseTime(session.CreatedAt),
+			LastSeenAt:   dto.ToResponseTime(session.LastSeenAt),
+			ExpiresAt:    dto.ToResponseTime(session.ExpiresAt),
+			Current:      currentSessionID != 0 && session.ID == currentSessionID,
+			RevokedAt:    dto.PtrToResponseTime(session.RevokedAt),
+			RevokeReason: session.RevokeReason,
+		})
+	}
+	return responses
+}
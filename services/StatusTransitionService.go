@@ -4,6 +4,7 @@ import (
 	"RHPRo-Task/database"
 	"RHPRo-Task/models"
 	"errors"
+	"fmt"
 )
 
 type StatusTransitionService struct{}
@@ -29,6 +30,12 @@ type TaskContext struct {
 // ValidateTransition 验证状态转换是否允许
 // userRoles 是用户的所有角色列表，只要其中一个角色满足要求即可
 func (s *StatusTransitionService) ValidateTransition(taskTypeCode, fromStatus, toStatus string, userRoles []string) error {
+	_, err := s.MatchTransition(taskTypeCode, fromStatus, toStatus, userRoles)
+	return err
+}
+
+// MatchTransition 查找用户可用的转换规则（用于读取规则上的守卫、后置动作和审批配置）
+func (s *StatusTransitionService) MatchTransition(taskTypeCode, fromStatus, toStatus string, userRoles []string) (*models.TaskStatusTransition, error) {
 	var rule models.TaskStatusTransition
 
 	// 构建查询：required_role 为 NULL 或匹配任一用户角色
@@ -45,10 +52,10 @@ func (s *StatusTransitionService) ValidateTransition(taskTypeCode, fromStatus, t
 
 	err := query.First(&rule).Error
 	if err != nil {
-		return errors.New("不允许的状态转换")
+		return nil, errors.New("不允许的状态转换")
 	}
 
-	return nil
+	return &rule, nil
 }
 
 // GetAllowedTransitions 获取允许的状态转换选项
@@ -158,3 +165,53 @@ func (s *StatusTransitionService) GetTaskAllowedTransitions(taskTypeCode, curren
 
 	return result, nil
 }
+
+// statusCategorySubquery 按状态分类筛选任务的条件（参数为分类列表）
+const statusCategorySubquery = "status_code IN (SELECT code FROM task_statuses WHERE category IN ?)"
+
+// GetTaskType 获取任务类型配置
+func (s *StatusTransitionService) GetTaskType(taskTypeCode string) (*models.TaskType, error) {
+	var taskType models.TaskType
+	if err := database.DB.Where("code = ?", taskTypeCode).First(&taskType).Error; err != nil {
+		return nil, errors.New("任务类型不存在")
+	}
+	return &taskType, nil
+}
+
+// GetStatusCategory 获取状态所属分类，状态不存在时返回空字符串
+func (s *StatusTransitionService) GetStatusCategory(statusCode string) string {
+	var status models.TaskStatus
+	if err := database.DB.Select("category").Where("code = ?", statusCode).First(&status).Error; err != nil {
+		return ""
+	}
+	return status.Category
+}
+
+// AllowsSubtasks 判断任务处于该状态时能否创建子任务（按状态配置判断，状态不存在时不允许）
+func (s *StatusTransitionService) AllowsSubtasks(statusCode string) bool {
+	var status models.TaskStatus
+	if err := database.DB.Select("category", "allows_subtasks").Where("code = ?", statusCode).First(&status).Error; err != nil {
+		return false
+	}
+	return statusAllowsSubtasks(&status)
+}
+
+// statusAllowsSubtasks 已完成、已取消分类的状态不允许创建子任务，其他状态按 allows_subtasks 配置
+func statusAllowsSubtasks(status *models.TaskStatus) bool {
+	switch status.Category {
+	case models.TaskStatusCategoryDone, models.TaskStatusCategoryCancelled:
+		return false
+	}
+	return status.AllowsSubtasks
+}
+
+// GetCategoryStatusCode 获取任务类型下指定分类的状态编码（存在多个时取排序最靠前的）
+func (s *StatusTransitionService) GetCategoryStatusCode(taskTypeCode, category string) (string, error) {
+	var status models.TaskStatus
+	if err := database.DB.Where("task_type_code = ? AND category = ?", taskTypeCode, category).
+		Order("sort_order ASC, id ASC").
+		First(&status).Error; err != nil {
+		return "", fmt.Errorf("任务类型 %s 未配置 %s 分类的状态", taskTypeCode, category)
+	}
+	return status.Code, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/models"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TaskDateBackfillResult 实际时间回填结果
+type TaskDateBackfillResult struct {
+	// 扫描的任务数
+	Scanned int
+	// 更新的任务数
+	Updated int
+	// 回填实际开始时间的任务数
+	StartFilled int
+	// 回填实际完成时间的任务数
+	EndFilled int
+	// 已完成但没有状态变更记录、无法推断完成时间的任务数
+	Unresolved int
+}
+
+// reconstructTaskDates 根据状态变更日志（按时间升序）推断实际开始/完成时间
+// 开始时间：第一次进入进行中或已完成分类的时间
+// 完成时间：当前处于已完成分类时，最后一次从非完成分类进入已完成分类的时间
+// 状态转换申请和驳回记录没有实际改变任务状态，不参与推断
+func reconstructTaskDates(logs []models.TaskChangeLog, categories map[string]string, currentCategory string) (start, end *time.Time) {
+	for i := range logs {
+		log := logs[i]
+		if slices.Contains(slaIgnoredChangeTypes, log.ChangeType) {
+			continue
+		}
+		fromCategory := categories[log.OldValue]
+		toCategory := categories[log.NewValue]
+
+		if start == nil && (toCategory == models.TaskStatusCategoryInProgress || toCategory == models.TaskStatusCategoryDone) {
+			t := log.CreatedAt
+			start = &t
+		}
+		if toCategory == models.TaskStatusCategoryDone && (fromCategory != models.TaskStatusCategoryDone || end == nil) {
+			t := log.CreatedAt
+			end = &t
+		} else if toCategory != models.TaskStatusCategoryDone {
+			end = nil
+		}
+	}
+
+	if currentCategory != models.TaskStatusCategoryDone {
+		end = nil
+	}
+	return start, end
+}
+
+// BackfillActualDates 根据 task_change_logs 的状态变更历史回填已有任务的实际开始/完成时间，并重新计算进度
+// overwrite 为 false 时只填充为空的字段；dryRun 为 true 时只统计不写入
+func (s *TaskService) BackfillActualDates(overwrite, dryRun bool) (*TaskDateBackfillResult, error) {
+	result := &TaskDateBackfillResult{}
+
+	categories := make(map[string]string)
+	var statuses []models.TaskStatus
+	if err := database.DB.Select("code, category").Find(&statuses).Error; err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		categories[status.Code] = status.Category
+	}
+
+	var tasks []models.Task
+	err := database.DB.Model(&models.Task{}).
+		Select("id, status_code, actual_start_date, actual_end_date, total_subtasks, completed_subtasks, progress").
+		FindInBatches(&tasks, 500, func(batch *gorm.DB, _ int) error {
+			taskIDs := make([]uint, 0, len(tasks))
+			for _, task := range tasks {
+				taskIDs = append(taskIDs, task.ID)
+			}
+
+			var logs []models.TaskChangeLog
+			if err := database.DB.Where("task_id IN ? AND field_name = ? AND change_type NOT IN ?",
+				taskIDs, "status_code", slaIgnoredChangeTypes).
+				Order("task_id, created_at, id").
+				Find(&logs).Error; err != nil {
+				return err
+			}
+			logsByTask := make(map[uint][]models.TaskChangeLog)
+			for _, log := range logs {
+				logsByTask[log.TaskID] = append(logsByTask[log.TaskID], log)
+			}
+
+			for i := range tasks {
+				task := &tasks[i]
+				result.Scanned++
+				currentCategory := categories[task.StatusCode]
+				start, end := reconstructTaskDates(logsByTask[task.ID], categories, currentCategory)
+
+				updates := make(map[string]interface{})
+				if start != nil && (task.ActualStartDate == nil || overwrite) && !sameTime(task.ActualStartDate, start) {
+					updates["actual_start_date"] = *start
+					result.StartFilled++
+				}
+				if end != nil && (task.ActualEndDate == nil || overwrite) && !sameTime(task.ActualEndDate, end) {
+					updates["actual_end_date"] = *end
+					result.EndFilled++
+				} else if end == nil && currentCategory == models.TaskStatusCategoryDone && task.ActualEndDate == nil {
+					result.Unresolved++
+				} else if overwrite && currentCategory != models.TaskStatusCategoryDone && task.ActualEndDate != nil {
+					updates["actual_end_date"] = nil
+				}
+				if progress := computeTaskProgress(task.TotalSubtasks, task.CompletedSubtasks, currentCategory); progress != task.Progress {
+					updates["progress"] = progress
+				}
+
+				if len(updates) == 0 {
+					continue
+				}
+				result.Updated++
+				if dryRun {
+					continue
+				}
+				if err := database.DB.Model(&models.Task{}).Where("id = ?", task.ID).
+					UpdateColumns(updates).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// sameTime 判断可空时间是否与给定时间相同
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
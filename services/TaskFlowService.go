@@ -1,6 +1,7 @@
 package services
 
 import (
+	"RHPRo-Task/config"
 	"RHPRo-Task/database"
 	"RHPRo-Task/dto"
 	"RHPRo-Task/models"
@@ -8,7 +9,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskFlowService struct {
@@ -31,17 +36,14 @@ func (s *TaskFlowService) AcceptTask(taskID uint, userID uint) error {
 		return errors.New("只有执行人可以接受任务")
 	}
 
-	// 根据任务类型和当前状态确定目标状态
+	// 根据任务类型配置的入口状态确定目标状态：待接受 -> 已接受
+	taskType, err := s.statusTransition.GetTaskType(task.TaskTypeCode)
+	if err != nil {
+		return err
+	}
 	var newStatus string
-	switch task.TaskTypeCode {
-	case "requirement":
-		if task.StatusCode == "req_pending_accept" {
-			newStatus = "req_pending_solution"
-		}
-	case "unit_task":
-		if task.StatusCode == "unit_pending_accept" {
-			newStatus = "unit_pending_start" // 接受后进入待开始状态
-		}
+	if task.StatusCode == taskType.AssignedStatusCode {
+		newStatus = taskType.AcceptedStatusCode
 	}
 
 	if newStatus == "" {
@@ -59,9 +61,13 @@ func (s *TaskFlowService) AcceptTask(taskID uint, userID uint) error {
 	}
 
 	// 更新任务状态
+	oldStatus := task.StatusCode
 	if err := database.DB.Model(&task).Update("status_code", newStatus).Error; err != nil {
 		return err
 	}
+	if err := onTaskStatusChanged(database.DB, &task, oldStatus, newStatus); err != nil {
+		return err
+	}
 
 	// 更新 TaskParticipant 状态
 	database.DB.Model(&models.TaskParticipant{}).
@@ -74,7 +80,7 @@ func (s *TaskFlowService) AcceptTask(taskID uint, userID uint) error {
 		UserID:     userID,
 		ChangeType: "status_change",
 		FieldName:  "status_code",
-		OldValue:   task.StatusCode,
+		OldValue:   oldStatus,
 		NewValue:   newStatus,
 		Comment:    "执行人接受任务",
 	}
@@ -99,17 +105,14 @@ func (s *TaskFlowService) RejectTask(taskID uint, userID uint, req *dto.RejectTa
 		return errors.New("只有执行人可以拒绝任务")
 	}
 
-	// 根据任务类型和当前状态确定目标状态
+	// 根据任务类型配置的入口状态确定目标状态：待接受 -> 未指派
+	taskType, err := s.statusTransition.GetTaskType(task.TaskTypeCode)
+	if err != nil {
+		return err
+	}
 	var newStatus string
-	switch task.TaskTypeCode {
-	case "requirement":
-		if task.StatusCode == "req_pending_accept" {
-			newStatus = "req_pending_assign"
-		}
-	case "unit_task":
-		if task.StatusCode == "unit_pending_accept" {
-			newStatus = "unit_pending_assign"
-		}
+	if task.StatusCode == taskType.AssignedStatusCode {
+		newStatus = taskType.PoolStatusCode
 	}
 
 	if newStatus == "" {
@@ -137,9 +140,13 @@ func (s *TaskFlowService) RejectTask(taskID uint, userID uint, req *dto.RejectTa
 	}
 
 	// 更新任务
+	oldStatus := task.StatusCode
 	if err := database.DB.Model(&task).Updates(updates).Error; err != nil {
 		return err
 	}
+	if err := onTaskStatusChanged(database.DB, &task, oldStatus, newStatus); err != nil {
+		return err
+	}
 
 	// 更新 TaskParticipant 状态
 	database.DB.Model(&models.TaskParticipant{}).
@@ -152,7 +159,7 @@ func (s *TaskFlowService) RejectTask(taskID uint, userID uint, req *dto.RejectTa
 		UserID:     userID,
 		ChangeType: "task_rejected",
 		FieldName:  "status_code",
-		OldValue:   task.StatusCode,
+		OldValue:   oldStatus,
 		NewValue:   newStatus,
 		Comment:    "执行人拒绝任务：" + req.Reason,
 	}
@@ -222,26 +229,26 @@ func (s *TaskFlowService) SubmitSolution(taskID uint, userID uint, req *dto.Subm
 		}
 	}
 
+	// 之前版本上未解决的批注转移到新版本
+	if err := carryOverAnnotations(tx, taskID, solutionAnnotationTarget(solution), now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 更新任务状态为方案审核中
+	oldStatus := task.StatusCode
 	if err := tx.Model(&task).Update("status_code", "req_solution_review").Error; err != nil {
 		tx.Rollback()
 		return err
 	}
-
-	// 自动发起单人审核（创建人审核）
-	now2 := time.Now()
-	reviewSession := &models.ReviewSession{
-		TaskID:            taskID,
-		ReviewType:        "solution_review",
-		TargetType:        "requirement_solutions",
-		TargetID:          solution.ID,
-		InitiatedBy:       userID,
-		InitiatedAt:       now2,
-		Status:            "in_review",
-		ReviewMode:        "single",
-		RequiredApprovals: 1,
+	if err := onTaskStatusChanged(tx, &task, oldStatus, "req_solution_review"); err != nil {
+		tx.Rollback()
+		return err
 	}
-	if err := tx.Create(reviewSession).Error; err != nil {
+
+	// 自动发起审核（匹配审批链模板时逐级审批，否则由创建人单人审核）
+	reviewSession, err := createSubmissionReview(tx, &task, "solution_review", "requirement_solutions", solution.ID, userID, time.Now())
+	if err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -252,13 +259,17 @@ func (s *TaskFlowService) SubmitSolution(taskID uint, userID uint, req *dto.Subm
 		UserID:     userID,
 		ChangeType: "solution_submitted",
 		FieldName:  "status_code",
-		OldValue:   task.StatusCode,
+		OldValue:   oldStatus,
 		NewValue:   "req_solution_review",
-		Comment:    fmt.Sprintf("提交了解决方案（版本 v%d），自动发起单人审核", newVersion),
+		Comment:    fmt.Sprintf("提交了解决方案（版本 v%d），%s", newVersion, describeSubmissionReview(reviewSession)),
 	}
 	tx.Create(changeLog)
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	notifyChainStage(database.DB, &task, reviewSession)
+	return nil
 }
 
 // SubmitGoalsAndSolution 提交目标和方案(已废弃)
@@ -294,6 +305,28 @@ func (s *TaskFlowService) InitiateReview(taskID uint, userID uint, req *dto.Init
 		return nil, errors.New("当前状态无法发起此类审核")
 	}
 
+	// 陪审团/投票模式的响应截止时间和截止处理策略
+	now := time.Now()
+	var responseDueAt *time.Time
+	deadlinePolicy := ""
+	if req.ReviewMode != models.ReviewModeSingle {
+		var err error
+		responseDueAt, deadlinePolicy, err = resolveReviewDeadline(req.ResponseDeadline, req.DeadlinePolicy, nil, "", config.GetConfig().Task, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 投票模式需要指定投票成员和通过门槛
+	if isVotingReviewMode(req.ReviewMode) {
+		if len(req.JuryMemberIDs)+len(req.ExpertIDs) == 0 {
+			return nil, errors.New("投票模式需要指定陪审团成员或评审专家")
+		}
+		if req.RequiredApprovals < 1 {
+			return nil, errors.New("投票模式需要设置所需批准数")
+		}
+	}
+
 	// 开启事务
 	tx := database.DB.Begin()
 	defer func() {
@@ -303,7 +336,6 @@ func (s *TaskFlowService) InitiateReview(taskID uint, userID uint, req *dto.Init
 	}()
 
 	// 创建审核会话
-	now := time.Now()
 	session := &models.ReviewSession{
 		TaskID:            taskID,
 		ReviewType:        req.ReviewType,
@@ -314,27 +346,24 @@ func (s *TaskFlowService) InitiateReview(taskID uint, userID uint, req *dto.Init
 		Status:            "in_review",
 		ReviewMode:        req.ReviewMode,
 		RequiredApprovals: req.RequiredApprovals,
+		RubricID:          activeRubricID(tx, req.ReviewType),
+		ResponseDueAt:     responseDueAt,
+		DeadlinePolicy:    deadlinePolicy,
 	}
 	if err := tx.Create(session).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// 如果是陪审团模式，创建陪审团成员记录
-	if req.ReviewMode == "jury" && len(req.JuryMemberIDs) > 0 {
-		for _, juryID := range req.JuryMemberIDs {
-			participant := &models.TaskParticipant{
-				TaskID:    taskID,
-				UserID:    juryID,
-				Role:      "jury",
-				Status:    "pending",
-				InvitedBy: &userID,
-				InvitedAt: &now,
-			}
-			if err := tx.Create(participant).Error; err != nil {
-				tx.Rollback()
-				return nil, err
-			}
+	// 如果是陪审团/投票模式，创建陪审团成员和评审专家记录
+	if req.ReviewMode != models.ReviewModeSingle {
+		if err := inviteReviewParticipants(tx, taskID, req.JuryMemberIDs, models.ReviewerRoleJury, userID, now); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := inviteReviewParticipants(tx, taskID, req.ExpertIDs, models.ReviewerRoleExpert, userID, now); err != nil {
+			tx.Rollback()
+			return nil, err
 		}
 	}
 
@@ -357,69 +386,233 @@ func (s *TaskFlowService) InitiateReview(taskID uint, userID uint, req *dto.Init
 }
 
 // SubmitReviewOpinion 提交审核意见
+// 门槛投票/加权投票模式下，赞成权重达到要求或驳回已成定局时自动结束审核
 func (s *TaskFlowService) SubmitReviewOpinion(sessionID uint, userID uint, req *dto.SubmitReviewOpinionRequest) error {
+	// 开启事务，锁定审核会话，避免并发投票重复触发自动决策
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
 	var session models.ReviewSession
-	if err := database.DB.First(&session, sessionID).Error; err != nil {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, sessionID).Error; err != nil {
+		tx.Rollback()
 		return errors.New("审核会话不存在")
 	}
 
 	// 验证会话状态
 	if session.Status != "in_review" {
+		tx.Rollback()
 		return errors.New("审核会话已结束")
 	}
 
-	// 如果是陪审团模式，验证用户是否为陪审团成员
-	if session.ReviewMode == "jury" {
+	var task models.Task
+	if err := tx.First(&task, session.TaskID).Error; err != nil {
+		tx.Rollback()
+		return errors.New("任务不存在")
+	}
+
+	// 确定审核人：委托生效期间代理人可代委托人提交意见，记录为委托人的意见并记录代理人
+	now := time.Now()
+	reviewerID, err := resolveOpinionReviewer(tx, &session, &task, userID, req.OnBehalfOf, now)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	var delegateID *uint
+	if reviewerID != userID {
+		delegateID = &userID
+	}
+
+	// 非单人审核模式，验证审核人是否为陪审团成员或评审专家
+	participantRole := ""
+	if session.ReviewMode != models.ReviewModeSingle {
 		var participant models.TaskParticipant
-		if err := database.DB.Where("task_id = ? AND user_id = ? AND role = ?",
-			session.TaskID, userID, "jury").First(&participant).Error; err != nil {
+		if err := tx.Where("task_id = ? AND user_id = ? AND role IN ?",
+			session.TaskID, reviewerID, reviewVoterRoles).First(&participant).Error; err != nil {
+			tx.Rollback()
+			if delegateID != nil {
+				return errors.New("委托人不是陪审团成员")
+			}
 			return errors.New("您不是陪审团成员")
 		}
+		participantRole = participant.Role
 	}
 
 	// 检查是否已经提交过意见
 	var existingRecord models.ReviewRecord
-	if err := database.DB.Where("review_session_id = ? AND reviewer_id = ?",
-		sessionID, userID).First(&existingRecord).Error; err == nil {
+	if err := tx.Where("review_session_id = ? AND reviewer_id = ?",
+		sessionID, reviewerID).First(&existingRecord).Error; err == nil {
+		tx.Rollback()
+		if delegateID != nil {
+			return errors.New("委托人已提交过审核意见")
+		}
 		return errors.New("您已提交过审核意见")
 	}
 
+	// 校验维度评分
+	rubric, err := loadSessionRubric(tx, &session)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := validateCriterionScores(rubric, req.CriterionScores, req.Opinion); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// 单人审核模式下只有决策人可以打分，避免其他用户的评分影响最低分校验
+	if session.ReviewMode == models.ReviewModeSingle && len(req.CriterionScores) > 0 &&
+		!slices.Contains(reviewDeciderIDs(&session, &task), reviewerID) {
+		tx.Rollback()
+		return errors.New("只有审核决策人可以提交维度评分")
+	}
+
+	// 确定审核角色与投票权重（按委托人的身份计算）
+	role := resolveReviewerRole(departmentLeaderSet(tx, task.DepartmentID), reviewerID, participantRole)
+	weight := reviewVoteWeight(session.ReviewMode, role, loadReviewRoleWeights(tx))
+
 	// 创建审核记录
-	now := time.Now()
 	record := &models.ReviewRecord{
 		ReviewSessionID: sessionID,
-		ReviewerID:      userID,
-		ReviewerRole:    "jury",
+		ReviewerID:      reviewerID,
+		DelegateID:      delegateID,
+		ReviewerRole:    role,
 		Opinion:         req.Opinion,
 		Comment:         req.Comment,
 		Score:           req.Score,
-		VoteWeight:      1.0,
+		VoteWeight:      weight,
 		ReviewedAt:      now,
 	}
-	if err := database.DB.Create(record).Error; err != nil {
+	if err := tx.Create(record).Error; err != nil {
+		tx.Rollback()
 		return err
 	}
 
+	// 保存维度评分
+	if len(req.CriterionScores) > 0 {
+		scores := make([]models.ReviewCriterionScore, 0, len(req.CriterionScores))
+		for _, item := range req.CriterionScores {
+			scores = append(scores, models.ReviewCriterionScore{
+				ReviewSessionID: sessionID,
+				ReviewRecordID:  record.ID,
+				CriterionID:     item.CriterionID,
+				Score:           *item.Score,
+			})
+		}
+		if err := tx.Create(&scores).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("保存维度评分失败: %v", err)
+		}
+	}
+
+	if delegateID != nil {
+		if err := logDelegatedAction(tx, task.ID, userID, reviewerID, "提交审核意见"); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// 投票模式下检查是否已可自动决策
+	decided, approved, err := closeVotingSessionIfDecided(tx, &session, &task, userID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if decided {
+		notifyVotingDecision(&task, &session, approved)
+	}
 	return nil
 }
 
+// resolveOpinionReviewer 确定提交审核意见的审核人
+// 指定 onBehalfOf 时需为其当前代理人；未指定时本人是审核成员则为本人，否则为唯一一名尚未提交意见、委托本人代理的审核成员
+func resolveOpinionReviewer(tx *gorm.DB, session *models.ReviewSession, task *models.Task, userID uint, onBehalfOf *uint, now time.Time) (uint, error) {
+	if onBehalfOf != nil && *onBehalfOf != userID {
+		if !isDelegateFor(tx, *onBehalfOf, userID, task, now) {
+			return 0, errors.New("您不是该用户的审核代理人")
+		}
+		return *onBehalfOf, nil
+	}
+	if onBehalfOf != nil || session.ReviewMode == models.ReviewModeSingle {
+		return userID, nil
+	}
+
+	var count int64
+	tx.Model(&models.TaskParticipant{}).
+		Where("task_id = ? AND user_id = ? AND role IN ?", session.TaskID, userID, reviewVoterRoles).
+		Count(&count)
+	if count > 0 {
+		return userID, nil
+	}
+
+	delegators := make(map[uint]bool)
+	for _, id := range delegatorsOf(tx, userID, task, now) {
+		delegators[id] = true
+	}
+	var candidates []uint
+	for _, participant := range pendingReviewParticipants(tx, session) {
+		if delegators[participant.UserID] {
+			candidates = append(candidates, participant.UserID)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return userID, nil
+	case 1:
+		return candidates[0], nil
+	}
+	return 0, errors.New("您代理了多名未提交意见的审核成员，请指定 on_behalf_of")
+}
+
 // FinalizeReview 最终决策
+// 门槛投票/加权投票模式由投票结果自动决策，创建人需显式设置 override 才能越过投票直接决策，并记录越权日志
 func (s *TaskFlowService) FinalizeReview(sessionID uint, userID uint, req *dto.FinalizeReviewRequest) error {
 	var session models.ReviewSession
 	if err := database.DB.Preload("ReviewRecords").First(&session, sessionID).Error; err != nil {
 		return errors.New("审核会话不存在")
 	}
 
+	// 状态转换审批：创建人或所属部门负责人均可决策，由状态转换申请处理
+	if session.ReviewType == TransitionReviewType {
+		return (&TransitionRequestService{}).DecideRequest(session.TargetID, userID, req.Approved, req.Comment)
+	}
+
 	// 获取任务信息
 	var task models.Task
 	if err := database.DB.First(&task, session.TaskID).Error; err != nil {
 		return errors.New("任务不存在")
 	}
 
-	// 验证是否为创建人
-	if task.CreatorID != userID {
+	// 验证是否为创建人（响应截止后升级的会话，部门负责人也可决策；审批链阶段由本阶段审批人决策；委托生效期间其代理人可代为决策）
+	deciderID, ok := resolveReviewPrincipal(database.DB, reviewDeciderIDs(&session, &task), userID, &task, time.Now())
+	if !ok {
+		if session.ChainStageNo > 0 {
+			return errors.New("只有本阶段审批人可以做出决策")
+		}
 		return errors.New("只有创建人可以做出最终决策")
 	}
+	var delegateID *uint
+	if deciderID != userID {
+		delegateID = &userID
+	}
+
+	// 验证会话状态
+	if session.Status != "in_review" {
+		return errReviewSessionClosed
+	}
+
+	// 投票模式下响应截止前需显式越权；截止后按升级/创建人决策策略可直接决策
+	overridden := isVotingReviewMode(session.ReviewMode) && !deadlineReleasesVoting(&session)
+	if overridden && !req.Override {
+		return errors.New("投票模式下由投票结果自动决策，如需直接决策请设置 override")
+	}
 
 	// 开启事务
 	tx := database.DB.Begin()
@@ -429,46 +622,218 @@ func (s *TaskFlowService) FinalizeReview(sessionID uint, userID uint, req *dto.F
 		}
 	}()
 
+	// 锁定会话后重新检查状态，防止并发决策或投票自动结束后重复应用决策
+	var locked models.ReviewSession
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, session.ID).Error; err != nil {
+		tx.Rollback()
+		return errors.New("审核会话不存在")
+	}
+	if locked.Status != "in_review" {
+		tx.Rollback()
+		return errReviewSessionClosed
+	}
+
+	// 绑定评分标准且配置了最低分时，加权总分达标才能通过
+	if req.Approved {
+		if err := checkSessionMinScore(tx, &session); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	decision := "approved"
+	if !req.Approved {
+		decision = "rejected"
+	}
+	var tally reviewVoteTally
+	if session.ReviewMode != models.ReviewModeSingle {
+		tally = computeVoteTally(tx, &session, &task)
+	}
+
+	// 仍有成员未提交意见时记录法定人数不足
+	if tally.PendingVoters > 0 {
+		shortfallLog := &models.TaskChangeLog{
+			TaskID:     session.TaskID,
+			UserID:     userID,
+			ChangeType: "review_quorum_shortfall",
+			FieldName:  "review_session_id",
+			NewValue:   fmt.Sprintf("%d", session.ID),
+			Comment:    fmt.Sprintf("法定人数不足：%d 名成员未提交意见即做出决策 %s（%s）", tally.PendingVoters, decision, tally.summary()),
+		}
+		if err := tx.Create(shortfallLog).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if overridden {
+		// 记录越权决策前的投票情况
+		overrideLog := &models.TaskChangeLog{
+			TaskID:     session.TaskID,
+			UserID:     userID,
+			ChangeType: "review_override",
+			FieldName:  "review_session_id",
+			NewValue:   fmt.Sprintf("%d", session.ID),
+			Comment:    fmt.Sprintf("创建人越过投票结果直接决策：%s（%s），%s", decision, tally.summary(), req.Comment),
+		}
+		if err := tx.Create(overrideLog).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if delegateID != nil {
+		if err := logDelegatedAction(tx, task.ID, userID, deciderID, "做出最终决策"); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// 审批链：非最后阶段通过时进入下一阶段，任务状态不变
+	if session.ChainStageNo > 0 && req.Approved {
+		next, err := advanceApprovalChain(tx, &session, &task, userID, deciderID, delegateID, req.Comment, time.Now())
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if next != nil {
+			if err := tx.Commit().Error; err != nil {
+				return err
+			}
+			notifyChainStage(database.DB, &task, next)
+			return nil
+		}
+	}
+
+	if err := applyReviewDecision(tx, &session, &task, req.Approved, userID, &deciderID, delegateID, req.Comment, overridden); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	if session.ChainStageNo > 0 {
+		notifyChainResult(&task, &session, req.Approved)
+	}
+	return nil
+}
+
+// closeVotingSessionIfDecided 投票模式下根据当前投票情况自动结束审核会话
+func closeVotingSessionIfDecided(tx *gorm.DB, session *models.ReviewSession, task *models.Task, actorID uint) (bool, bool, error) {
+	if !isVotingReviewMode(session.ReviewMode) || session.Status != "in_review" {
+		return false, false, nil
+	}
+
+	tally := computeVoteTally(tx, session, task)
+	decided, approved := tally.decide()
+	if !decided {
+		return false, false, nil
+	}
+
+	// 加权总分未达最低分时不自动通过，保留会话由创建人决策
+	if approved {
+		if err := checkSessionMinScore(tx, session); err != nil {
+			return false, false, nil
+		}
+	}
+
+	comment := "投票自动驳回：" + tally.summary()
+	if approved {
+		comment = "投票自动通过：" + tally.summary()
+	}
+	if err := applyReviewDecision(tx, session, task, approved, actorID, nil, nil, comment, false); err != nil {
+		return false, false, err
+	}
+	return true, approved, nil
+}
+
+// reviewDeciderIDs 审核会话的决策人：默认为创建人，响应截止后升级的会话加上部门负责人，审批链阶段为本阶段审批人
+func reviewDeciderIDs(session *models.ReviewSession, task *models.Task) []uint {
+	if session.ChainStageNo > 0 {
+		return session.StageApproverIDs
+	}
+	if isEscalated(session) {
+		return taskCreatorAndLeaderIDs(task)
+	}
+	return []uint{task.CreatorID}
+}
+
+// notifyVotingDecision 通知创建人和执行人投票自动决策结果
+func notifyVotingDecision(task *models.Task, session *models.ReviewSession, approved bool) {
+	recipients := []uint{task.CreatorID}
+	if task.ExecutorID != nil {
+		recipients = append(recipients, *task.ExecutorID)
+	}
+	result := "驳回"
+	if approved {
+		result = "通过"
+	}
+	content := fmt.Sprintf("任务「%s」的%s已由投票自动%s", task.Title, session.ReviewType, result)
+	(&NotificationService{}).Notify(uniqueUintSlice(recipients), &task.ID, NotificationTypeStatusChange, "审核结果", content)
+}
+
+// errReviewSessionClosed 审核会话已结束（已决策或已进入下一审批阶段）
+var errReviewSessionClosed = errors.New("审核会话已结束")
+
+// completeReviewSession 结束审核会话：仅在会话仍处于审核中时更新，已被其他请求结束时返回错误
+func completeReviewSession(tx *gorm.DB, session *models.ReviewSession, updates map[string]interface{}) error {
+	result := tx.Model(session).Where("status = ?", "in_review").Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errReviewSessionClosed
+	}
+	return nil
+}
+
+// applyReviewDecision 应用审核决策：结束会话，更新方案/计划/目标及任务状态，记录变更日志
+// decidedBy 为空表示由投票结果自动决策；delegateID 为代理人代 decidedBy 决策时的实际决策人
+func applyReviewDecision(tx *gorm.DB, session *models.ReviewSession, task *models.Task, approved bool, actorID uint, decidedBy *uint, delegateID *uint, comment string, overridden bool) error {
 	// 更新审核会话
 	now := time.Now()
 	decision := "approved"
 	opinion := "approve"
-	if !req.Approved {
+	if !approved {
 		decision = "rejected"
 		opinion = "reject"
 	}
 
 	updates := map[string]interface{}{
-		"status":                 "completed",
-		"final_decision":         decision,
-		"final_decision_by":      userID,
-		"final_decision_at":      now,
-		"final_decision_comment": req.Comment,
-		"completed_at":           now,
-	}
-	if err := tx.Model(&session).Updates(updates).Error; err != nil {
-		tx.Rollback()
+		"status":                     "completed",
+		"final_decision":             decision,
+		"final_decision_by":          decidedBy,
+		"final_decision_delegate_id": delegateID,
+		"final_decision_at":          now,
+		"final_decision_comment":     comment,
+		"completed_at":               now,
+		"is_overridden":              overridden,
+	}
+	if err := completeReviewSession(tx, session, updates); err != nil {
 		return err
 	}
 
-	// 添加最终决策的审核记录到 ReviewRecords 表
-	finalReviewRecord := &models.ReviewRecord{
-		ReviewSessionID: sessionID,
-		ReviewerID:      userID,
-		ReviewerRole:    "creator",
-		Opinion:         opinion,
-		Comment:         req.Comment,
-		VoteWeight:      1.0,
-		ReviewedAt:      now,
-	}
-	if err := tx.Create(finalReviewRecord).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("记录最终审核意见失败: %v", err)
+	// 创建人或审批链阶段审批人决策时添加最终决策的审核记录到 ReviewRecords 表（升级后部门负责人的决策记录在会话和变更日志中）
+	if decidedBy != nil && (*decidedBy == task.CreatorID || session.ChainStageNo > 0) {
+		finalReviewRecord := &models.ReviewRecord{
+			ReviewSessionID: session.ID,
+			ReviewerID:      *decidedBy,
+			DelegateID:      delegateID,
+			ReviewerRole:    chainStageReviewerRole(task, *decidedBy),
+			Opinion:         opinion,
+			Comment:         comment,
+			VoteWeight:      1.0,
+			ReviewedAt:      now,
+		}
+		if err := tx.Create(finalReviewRecord).Error; err != nil {
+			return fmt.Errorf("记录最终审核意见失败: %v", err)
+		}
 	}
 
 	// 根据审核结果更新关联的思路方案/执行计划/目标的状态
 	targetStatus := "approved"
-	if !req.Approved {
+	if !approved {
 		targetStatus = "rejected"
 	}
 
@@ -478,7 +843,6 @@ func (s *TaskFlowService) FinalizeReview(sessionID uint, userID uint, req *dto.F
 		if err := tx.Model(&models.RequirementSolution{}).
 			Where("id = ?", session.TargetID).
 			Update("status", targetStatus).Error; err != nil {
-			tx.Rollback()
 			return fmt.Errorf("更新思路方案状态失败: %v", err)
 		}
 	case "plan_review", "execution_plan_review":
@@ -486,21 +850,19 @@ func (s *TaskFlowService) FinalizeReview(sessionID uint, userID uint, req *dto.F
 		if err := tx.Model(&models.ExecutionPlan{}).
 			Where("id = ?", session.TargetID).
 			Update("status", targetStatus).Error; err != nil {
-			tx.Rollback()
 			return fmt.Errorf("更新执行计划状态失败: %v", err)
 		}
 		// 更新关联目标的状态
 		if err := tx.Model(&models.RequirementGoal{}).
 			Where("execution_plan_id = ?", session.TargetID).
 			Update("status", targetStatus).Error; err != nil {
-			tx.Rollback()
 			return fmt.Errorf("更新目标状态失败: %v", err)
 		}
 	}
 
 	// 根据审核结果更新任务状态
 	var newStatus string
-	if req.Approved {
+	if approved {
 		switch session.ReviewType {
 		case "solution_review":
 			newStatus = "req_pending_plan"
@@ -516,24 +878,32 @@ func (s *TaskFlowService) FinalizeReview(sessionID uint, userID uint, req *dto.F
 		}
 	}
 
-	if err := tx.Model(&task).Update("status_code", newStatus).Error; err != nil {
-		tx.Rollback()
-		return err
+	// 其他审核类型（如状态变更审核）不改变任务状态
+	oldStatus := task.StatusCode
+	if newStatus == "" {
+		newStatus = task.StatusCode
+	} else {
+		if err := tx.Model(task).Update("status_code", newStatus).Error; err != nil {
+			return err
+		}
+		if err := onTaskStatusChanged(tx, task, oldStatus, newStatus); err != nil {
+			return err
+		}
 	}
 
 	// 记录变更日志
 	changeLog := &models.TaskChangeLog{
 		TaskID:     session.TaskID,
-		UserID:     userID,
+		UserID:     actorID,
 		ChangeType: "review_finalized",
 		FieldName:  "status_code",
-		OldValue:   task.StatusCode,
+		OldValue:   oldStatus,
 		NewValue:   newStatus,
-		Comment:    "审核决策：" + decision + "，" + req.Comment,
+		Comment:    "审核决策：" + decision + "，" + comment,
 	}
 	tx.Create(changeLog)
 
-	return tx.Commit().Error
+	return nil
 }
 
 // AddTaskParticipant 添加任务参与者
@@ -559,17 +929,63 @@ func (s *TaskFlowService) GetReviewSession(sessionID uint) (*dto.ReviewSessionRe
 
 	// 转换为响应格式
 	resp := &dto.ReviewSessionResponse{
-		ID:                   session.ID,
-		TaskID:               session.TaskID,
-		ReviewType:           session.ReviewType,
-		TargetType:           session.TargetType,
-		TargetID:             session.TargetID,
-		Status:               session.Status,
-		ReviewMode:           session.ReviewMode,
-		RequiredApprovals:    session.RequiredApprovals,
-		FinalDecision:        session.FinalDecision,
-		FinalDecisionComment: session.FinalDecisionComment,
-		ReviewRecords:        []dto.ReviewRecordResponse{},
+		ID:                      session.ID,
+		TaskID:                  session.TaskID,
+		ReviewType:              session.ReviewType,
+		TargetType:              session.TargetType,
+		TargetID:                session.TargetID,
+		Status:                  session.Status,
+		ReviewMode:              session.ReviewMode,
+		RequiredApprovals:       session.RequiredApprovals,
+		FinalDecision:           session.FinalDecision,
+		FinalDecisionComment:    session.FinalDecisionComment,
+		IsOverridden:            session.IsOverridden,
+		FinalDecisionDelegateID: session.FinalDecisionDelegateID,
+		ChainTemplateID:         session.ChainTemplateID,
+		ChainStageNo:            session.ChainStageNo,
+		ChainStageName:          session.ChainStageName,
+		StageApproverIDs:        session.StageApproverIDs,
+		PrevStageSessionID:      session.PrevStageSessionID,
+		NextStageSessionID:      session.NextStageSessionID,
+		ResponseDueAt:           dto.PtrToResponseTime(session.ResponseDueAt),
+		DeadlinePolicy:          session.DeadlinePolicy,
+		DeadlineHandledAt:       dto.PtrToResponseTime(session.DeadlineHandledAt),
+		ReviewRecords:           []dto.ReviewRecordResponse{},
+	}
+
+	// 非单人审核模式返回投票统计
+	if session.ReviewMode != models.ReviewModeSingle {
+		var task models.Task
+		if err := database.DB.First(&task, session.TaskID).Error; err == nil {
+			tally := computeVoteTally(database.DB, &session, &task)
+			resp.VoteTally = tally.toResponse()
+		}
+		if session.Status == "in_review" {
+			for _, participant := range pendingReviewParticipants(database.DB, &session) {
+				resp.PendingReviewerIDs = append(resp.PendingReviewerIDs, participant.UserID)
+			}
+		}
+	}
+
+	// 评分汇总
+	if summary, err := loadRubricSummary(database.DB, &session); err == nil && summary != nil {
+		resp.RubricSummary = summary
+	}
+	criterionNames := make(map[uint]string)
+	recordScores := make(map[uint][]dto.ReviewCriterionScoreResponse)
+	if resp.RubricSummary != nil {
+		for _, criterion := range resp.RubricSummary.Criteria {
+			criterionNames[criterion.CriterionID] = criterion.Name
+		}
+		var scores []models.ReviewCriterionScore
+		database.DB.Where("review_session_id = ?", sessionID).Order("id ASC").Find(&scores)
+		for _, score := range scores {
+			recordScores[score.ReviewRecordID] = append(recordScores[score.ReviewRecordID], dto.ReviewCriterionScoreResponse{
+				CriterionID:   score.CriterionID,
+				CriterionName: criterionNames[score.CriterionID],
+				Score:         score.Score,
+			})
+		}
 	}
 
 	// 获取审核记录
@@ -580,23 +996,56 @@ func (s *TaskFlowService) GetReviewSession(sessionID uint) (*dto.ReviewSessionRe
 		var user models.User
 		database.DB.Select("id, username").First(&user, record.ReviewerID)
 
-		resp.ReviewRecords = append(resp.ReviewRecords, dto.ReviewRecordResponse{
-			ID:           record.ID,
-			ReviewerID:   record.ReviewerID,
-			ReviewerName: user.Username,
-			ReviewerRole: record.ReviewerRole,
-			Opinion:      record.Opinion,
-			Comment:      record.Comment,
-			Score:        record.Score,
-			VoteWeight:   record.VoteWeight,
-		})
+		recordResp := dto.ReviewRecordResponse{
+			ID:              record.ID,
+			ReviewerID:      record.ReviewerID,
+			ReviewerName:    user.Username,
+			ReviewerRole:    record.ReviewerRole,
+			Opinion:         record.Opinion,
+			Comment:         record.Comment,
+			Score:           record.Score,
+			VoteWeight:      record.VoteWeight,
+			CriterionScores: recordScores[record.ID],
+		}
+		if record.DelegateID != nil {
+			var delegate models.User
+			database.DB.Select("id, username").First(&delegate, *record.DelegateID)
+			recordResp.DelegateID = record.DelegateID
+			recordResp.DelegateName = delegate.Username
+		}
+		resp.ReviewRecords = append(resp.ReviewRecords, recordResp)
 	}
 
 	return resp, nil
 }
 
-// InviteJuryMembers 邀请陪审团成员（将单人审核转为陪审团审核）
-func (s *TaskFlowService) InviteJuryMembers(sessionID uint, userID uint, juryMemberIDs []uint, requiredApprovals int) error {
+// inviteReviewParticipants 邀请审核参与者（已是同角色参与者的跳过）
+func inviteReviewParticipants(tx *gorm.DB, taskID uint, userIDs []uint, role string, invitedBy uint, now time.Time) error {
+	for _, memberID := range uniqueUintSlice(userIDs) {
+		// 检查是否已经是该角色的参与者
+		var existingParticipant models.TaskParticipant
+		if err := tx.Where("task_id = ? AND user_id = ? AND role = ?",
+			taskID, memberID, role).First(&existingParticipant).Error; err == nil {
+			continue
+		}
+
+		participant := &models.TaskParticipant{
+			TaskID:    taskID,
+			UserID:    memberID,
+			Role:      role,
+			Status:    "pending",
+			InvitedBy: &invitedBy,
+			InvitedAt: &now,
+		}
+		if err := tx.Create(participant).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InviteJuryMembers 邀请陪审团成员（将单人审核转为陪审团/投票审核）
+func (s *TaskFlowService) InviteJuryMembers(sessionID uint, userID uint, req *dto.InviteJuryRequest) error {
 	var session models.ReviewSession
 	if err := database.DB.First(&session, sessionID).Error; err != nil {
 		return errors.New("审核会话不存在")
@@ -618,6 +1067,23 @@ func (s *TaskFlowService) InviteJuryMembers(sessionID uint, userID uint, juryMem
 		return errors.New("审核会话已结束，无法邀请陪审团")
 	}
 
+	// 未指定审核模式时保留当前投票模式，否则转为陪审团模式
+	reviewMode := req.ReviewMode
+	if reviewMode == "" {
+		reviewMode = models.ReviewModeJury
+		if isVotingReviewMode(session.ReviewMode) {
+			reviewMode = session.ReviewMode
+		}
+	}
+
+	// 响应截止时间和截止处理策略（重新指定截止时间时重新提醒和处理）
+	now := time.Now()
+	responseDueAt, deadlinePolicy, err := resolveReviewDeadline(req.ResponseDeadline, req.DeadlinePolicy,
+		session.ResponseDueAt, session.DeadlinePolicy, config.GetConfig().Task, now)
+	if err != nil {
+		return err
+	}
+
 	// 开启事务
 	tx := database.DB.Begin()
 	defer func() {
@@ -626,42 +1092,39 @@ func (s *TaskFlowService) InviteJuryMembers(sessionID uint, userID uint, juryMem
 		}
 	}()
 
-	// 更新审核会话为陪审团模式
+	// 更新审核会话的审核模式、所需批准数和响应截止
+	oldMode := session.ReviewMode
 	updates := map[string]interface{}{
-		"review_mode":        "jury",
-		"required_approvals": requiredApprovals,
+		"review_mode":        reviewMode,
+		"required_approvals": req.RequiredApprovals,
+		"response_due_at":    responseDueAt,
+		"deadline_policy":    deadlinePolicy,
+	}
+	if req.ResponseDeadline != "" {
+		updates["reminded_at"] = nil
+		updates["deadline_handled_at"] = nil
 	}
 	if err := tx.Model(&session).Updates(updates).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
+	session.ReviewMode = reviewMode
+	session.RequiredApprovals = req.RequiredApprovals
+	session.ResponseDueAt = responseDueAt
+	session.DeadlinePolicy = deadlinePolicy
+	if req.ResponseDeadline != "" {
+		session.RemindedAt = nil
+		session.DeadlineHandledAt = nil
+	}
 
-	// 创建陪审团成员记录（检查是否已存在）
-	now := time.Now()
-	for _, juryID := range juryMemberIDs {
-		// 检查是否已经是陪审团成员
-		var existingParticipant models.TaskParticipant
-		err := tx.Where("task_id = ? AND user_id = ? AND role = ?",
-			session.TaskID, juryID, "jury").First(&existingParticipant).Error
-
-		if err == nil {
-			// 已存在，跳过
-			continue
-		}
-
-		// 不存在，创建新记录
-		participant := &models.TaskParticipant{
-			TaskID:    session.TaskID,
-			UserID:    juryID,
-			Role:      "jury",
-			Status:    "pending",
-			InvitedBy: &userID,
-			InvitedAt: &now,
-		}
-		if err := tx.Create(participant).Error; err != nil {
-			tx.Rollback()
-			return err
-		}
+	// 创建陪审团成员和评审专家记录（已存在的跳过）
+	if err := inviteReviewParticipants(tx, session.TaskID, req.JuryMemberIDs, models.ReviewerRoleJury, userID, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := inviteReviewParticipants(tx, session.TaskID, req.ExpertIDs, models.ReviewerRoleExpert, userID, now); err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	// 记录变更日志
@@ -670,16 +1133,30 @@ func (s *TaskFlowService) InviteJuryMembers(sessionID uint, userID uint, juryMem
 		UserID:     userID,
 		ChangeType: "jury_invited",
 		FieldName:  "review_mode",
-		OldValue:   "single",
-		NewValue:   "jury",
+		OldValue:   oldMode,
+		NewValue:   reviewMode,
 		Comment:    "邀请陪审团参与审核",
 	}
 	tx.Create(changeLog)
 
-	return tx.Commit().Error
+	// 调整所需批准数后投票结果可能已确定
+	decided, approved, err := closeVotingSessionIfDecided(tx, &session, &task, userID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if decided {
+		notifyVotingDecision(&task, &session, approved)
+	}
+	return nil
 }
 
-// RemoveJuryMember 移除陪审团成员
+// RemoveJuryMember 移除陪审团成员（包括评审专家）
 func (s *TaskFlowService) RemoveJuryMember(sessionID uint, userID uint, juryMemberID uint) error {
 	var session models.ReviewSession
 	if err := database.DB.First(&session, sessionID).Error; err != nil {
@@ -702,23 +1179,43 @@ func (s *TaskFlowService) RemoveJuryMember(sessionID uint, userID uint, juryMemb
 		return errors.New("审核会话已结束，无法移除陪审团成员")
 	}
 
-	// 删除陪审团成员记录
-	result := database.DB.Where("task_id = ? AND user_id = ? AND role = ?",
-		session.TaskID, juryMemberID, "jury").
+	// 开启事务
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 删除陪审团成员/评审专家记录
+	result := tx.Where("task_id = ? AND user_id = ? AND role IN ?",
+		session.TaskID, juryMemberID, reviewVoterRoles).
 		Delete(&models.TaskParticipant{})
 
 	if result.Error != nil {
+		tx.Rollback()
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
+		tx.Rollback()
 		return errors.New("该用户不是陪审团成员")
 	}
 
-	// 同时删除该成员的审核记录（如果已投票）
-	database.DB.Where("review_session_id = ? AND reviewer_id = ?",
+	// 同时删除该成员的维度评分和审核记录（如果已投票）
+	if err := tx.Where("review_session_id = ? AND review_record_id IN (?)", sessionID,
+		tx.Model(&models.ReviewRecord{}).Select("id").
+			Where("review_session_id = ? AND reviewer_id = ?", sessionID, juryMemberID)).
+		Delete(&models.ReviewCriterionScore{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("review_session_id = ? AND reviewer_id = ?",
 		sessionID, juryMemberID).
-		Delete(&models.ReviewRecord{})
+		Delete(&models.ReviewRecord{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
 
 	// 记录变更日志
 	changeLog := &models.TaskChangeLog{
@@ -728,8 +1225,22 @@ func (s *TaskFlowService) RemoveJuryMember(sessionID uint, userID uint, juryMemb
 		FieldName:  "jury_members",
 		Comment:    fmt.Sprintf("移除了陪审团成员 ID=%d", juryMemberID),
 	}
-	database.DB.Create(changeLog)
+	tx.Create(changeLog)
+
+	// 移除成员后剩余票数可能已不足以通过
+	decided, approved, err := closeVotingSessionIfDecided(tx, &session, &task, userID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
 
+	if decided {
+		notifyVotingDecision(&task, &session, approved)
+	}
 	return nil
 }
 
@@ -793,26 +1304,26 @@ func (s *TaskFlowService) SubmitExecutionPlan(taskID uint, userID uint, req *dto
 		return err
 	}
 
+	// 之前版本上未解决的批注转移到新版本
+	if err := carryOverAnnotations(tx, taskID, loadPlanAnnotationTarget(tx, plan), now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 更新任务状态
+	oldStatus := task.StatusCode
 	if err := tx.Model(&task).Update("status_code", "req_plan_review").Error; err != nil {
 		tx.Rollback()
 		return err
 	}
-
-	// 自动发起单人审核
-	now2 := time.Now()
-	reviewSession := &models.ReviewSession{
-		TaskID:            taskID,
-		ReviewType:        "execution_plan_review",
-		TargetType:        "execution_plans",
-		TargetID:          plan.ID,
-		InitiatedBy:       userID,
-		InitiatedAt:       now2,
-		Status:            "in_review",
-		ReviewMode:        "single",
-		RequiredApprovals: 1,
+	if err := onTaskStatusChanged(tx, &task, oldStatus, "req_plan_review"); err != nil {
+		tx.Rollback()
+		return err
 	}
-	if err := tx.Create(reviewSession).Error; err != nil {
+
+	// 自动发起审核（匹配审批链模板时逐级审批，否则由创建人单人审核）
+	reviewSession, err := createSubmissionReview(tx, &task, "execution_plan_review", "execution_plans", plan.ID, userID, time.Now())
+	if err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -823,13 +1334,17 @@ func (s *TaskFlowService) SubmitExecutionPlan(taskID uint, userID uint, req *dto
 		UserID:     userID,
 		ChangeType: "plan_submitted",
 		FieldName:  "status_code",
-		OldValue:   task.StatusCode,
+		OldValue:   oldStatus,
 		NewValue:   "req_plan_review",
-		Comment:    fmt.Sprintf("提交了执行计划（版本 v%d），自动发起单人审核", newVersion),
+		Comment:    fmt.Sprintf("提交了执行计划（版本 v%d），%s", newVersion, describeSubmissionReview(reviewSession)),
 	}
 	tx.Create(changeLog)
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	notifyChainStage(database.DB, &task, reviewSession)
+	return nil
 }
 
 // SubmitExecutionPlanWithGoals 提交执行计划和目标（合并提交）
@@ -942,26 +1457,26 @@ func (s *TaskFlowService) SubmitExecutionPlanWithGoals(taskID uint, userID uint,
 		}
 	}
 
+	// 之前版本上未解决的批注转移到新版本（在目标创建之后，以便目标批注重新定位）
+	if err := carryOverAnnotations(tx, taskID, loadPlanAnnotationTarget(tx, plan), now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 更新任务状态为计划审核中
+	oldStatus := task.StatusCode
 	if err := tx.Model(&task).Update("status_code", "req_plan_review").Error; err != nil {
 		tx.Rollback()
 		return err
 	}
-
-	// 自动发起单人审核（创建人审核）
-	now2 := time.Now()
-	reviewSession := &models.ReviewSession{
-		TaskID:            taskID,
-		ReviewType:        "execution_plan_review",
-		TargetType:        "execution_plans",
-		TargetID:          plan.ID,
-		InitiatedBy:       userID,
-		InitiatedAt:       now2,
-		Status:            "in_review",
-		ReviewMode:        "single",
-		RequiredApprovals: 1,
+	if err := onTaskStatusChanged(tx, &task, oldStatus, "req_plan_review"); err != nil {
+		tx.Rollback()
+		return err
 	}
-	if err := tx.Create(reviewSession).Error; err != nil {
+
+	// 自动发起审核（匹配审批链模板时逐级审批，否则由创建人单人审核）
+	reviewSession, err := createSubmissionReview(tx, &task, "execution_plan_review", "execution_plans", plan.ID, userID, time.Now())
+	if err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -972,11 +1487,15 @@ func (s *TaskFlowService) SubmitExecutionPlanWithGoals(taskID uint, userID uint,
 		UserID:     userID,
 		ChangeType: "plan_submitted",
 		FieldName:  "status_code",
-		OldValue:   task.StatusCode,
+		OldValue:   oldStatus,
 		NewValue:   "req_plan_review",
-		Comment:    fmt.Sprintf("提交了执行计划和目标（版本 v%d，包含 %d 个目标），自动发起单人审核", newVersion, len(req.Goals)),
+		Comment:    fmt.Sprintf("提交了执行计划和目标（版本 v%d，包含 %d 个目标），%s", newVersion, len(req.Goals), describeSubmissionReview(reviewSession)),
 	}
 	tx.Create(changeLog)
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	notifyChainStage(database.DB, &task, reviewSession)
+	return nil
 }
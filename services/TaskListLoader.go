@@ -0,0 +1,428 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskListParamError 任务列表分页/字段参数错误
+type TaskListParamError struct {
+	// 出错的参数名
+	Param string `json:"param"`
+	// 错误描述
+	Message string `json:"message"`
+}
+
+func (e *TaskListParamError) Error() string {
+	return e.Message
+}
+
+// 任务列表可按需加载的关联数据
+const (
+	TaskFieldCreator     = "creator"     // 创建人用户名
+	TaskFieldExecutor    = "executor"    // 执行人用户名
+	TaskFieldDepartment  = "department"  // 部门名称
+	TaskFieldTags        = "tags"        // 标签
+	TaskFieldSubtasks    = "subtasks"    // 子任务（递归）
+	TaskFieldSolution    = "solution"    // 最新版本思路方案
+	TaskFieldPlan        = "plan"        // 最新版本执行计划
+	TaskFieldAttachments = "attachments" // 任务、方案、计划附件
+)
+
+var allTaskFields = []string{
+	TaskFieldCreator, TaskFieldExecutor, TaskFieldDepartment, TaskFieldTags,
+	TaskFieldSubtasks, TaskFieldSolution, TaskFieldPlan, TaskFieldAttachments,
+}
+
+// defaultTaskFields 未指定 fields 时加载的关联数据，与旧版列表返回内容一致
+var defaultTaskFields = []string{
+	TaskFieldExecutor, TaskFieldAttachments, TaskFieldSubtasks, TaskFieldSolution, TaskFieldPlan,
+}
+
+// taskFieldSet 需要加载的关联数据集合
+type taskFieldSet map[string]bool
+
+// parseTaskFields 解析 fields 参数（逗号分隔），为空时使用默认字段
+func parseTaskFields(raw string) (taskFieldSet, error) {
+	fields := make(taskFieldSet)
+	if strings.TrimSpace(raw) == "" {
+		for _, f := range defaultTaskFields {
+			fields[f] = true
+		}
+		return fields, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		valid := false
+		for _, f := range allTaskFields {
+			if f == name {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, &TaskListParamError{
+				Param:   "fields",
+				Message: fmt.Sprintf("不支持的字段: %s，可选值: %s", name, strings.Join(allTaskFields, ",")),
+			}
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// taskKeyset 游标分页的排序键，固定以 id 作为第二排序键保证唯一
+type taskKeyset struct {
+	column    string
+	direction string
+}
+
+// newTaskKeyset 根据排序参数创建游标排序键
+func newTaskKeyset(req *dto.TaskQueryRequest) (*taskKeyset, error) {
+	column := req.SortBy
+	if column == "" {
+		column = "created_at"
+	}
+	if column != "created_at" && column != "updated_at" {
+		return nil, &TaskListParamError{Param: "sort_by", Message: "游标分页仅支持按 created_at 或 updated_at 排序"}
+	}
+
+	direction := "DESC"
+	if strings.EqualFold(req.SortOrder, "asc") {
+		direction = "ASC"
+	}
+	return &taskKeyset{column: column, direction: direction}, nil
+}
+
+// key 排序键标识，写入游标用于校验游标与当前排序一致
+func (k *taskKeyset) key() string {
+	return k.column + "." + strings.ToLower(k.direction)
+}
+
+// order 游标分页的排序子句
+func (k *taskKeyset) order() string {
+	return fmt.Sprintf("tasks.%s %s, tasks.id %s", k.column, k.direction, k.direction)
+}
+
+// value 取任务的排序字段值
+func (k *taskKeyset) value(task *models.Task) time.Time {
+	if k.column == "updated_at" {
+		return task.UpdatedAt
+	}
+	return task.CreatedAt
+}
+
+// encode 以最后一条任务生成下一页游标
+func (k *taskKeyset) encode(task *models.Task) string {
+	raw := fmt.Sprintf("%s|%d|%d", k.key(), k.value(task).UnixNano(), task.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decode 解析游标，返回排序字段值和任务ID
+func (k *taskKeyset) decode(cursor string) (time.Time, uint, error) {
+	invalid := &TaskListParamError{Param: "cursor", Message: "无效的游标"}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, invalid
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return time.Time{}, 0, invalid
+	}
+	if parts[0] != k.key() {
+		return time.Time{}, 0, &TaskListParamError{Param: "cursor", Message: "游标与当前排序方式不匹配"}
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, invalid
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, invalid
+	}
+	return time.Unix(0, nanos), uint(id), nil
+}
+
+// condition 生成取游标之后数据的条件
+func (k *taskKeyset) condition(cursor string) (string, []interface{}, error) {
+	value, id, err := k.decode(cursor)
+	if err != nil {
+		return "", nil, err
+	}
+	op := "<"
+	if k.direction == "ASC" {
+		op = ">"
+	}
+	return fmt.Sprintf("(tasks.%s, tasks.id) %s (?, ?)", k.column, op), []interface{}{value, id}, nil
+}
+
+// buildTaskResponses 将任务列表转换为响应，并按 fields 批量加载关联数据
+// 每类关联数据（含各级子任务）只查询一次，避免逐条查询
+func (s *TaskService) buildTaskResponses(tasks []models.Task, fields taskFieldSet) []dto.TaskResponse {
+	responses := make([]dto.TaskResponse, len(tasks))
+	nodes := make([]*dto.TaskResponse, 0, len(tasks))
+	for i := range tasks {
+		responses[i] = s.toTaskResponseBase(&tasks[i])
+		nodes = append(nodes, &responses[i])
+	}
+	if len(nodes) == 0 {
+		return responses
+	}
+
+	// 逐层加载子任务，每层一次查询
+	if fields[TaskFieldSubtasks] {
+		nodes = append(nodes, s.loadSubtasksBatch(nodes)...)
+	}
+
+	taskIDs := make([]uint, 0, len(nodes))
+	for _, node := range nodes {
+		taskIDs = append(taskIDs, node.ID)
+	}
+
+	userIDs := make([]uint, 0)
+	for _, node := range nodes {
+		if fields[TaskFieldCreator] && node.CreatorID > 0 {
+			userIDs = append(userIDs, node.CreatorID)
+		}
+		if fields[TaskFieldExecutor] && node.ExecutorID > 0 {
+			userIDs = append(userIDs, node.ExecutorID)
+		}
+	}
+
+	// 最新版本的方案和计划
+	var solutions []models.RequirementSolution
+	if fields[TaskFieldSolution] {
+		database.DB.Select("DISTINCT ON (task_id) *").
+			Where("task_id IN ?", taskIDs).
+			Order("task_id, version DESC").
+			Find(&solutions)
+		for _, sol := range solutions {
+			if sol.SubmittedBy != nil {
+				userIDs = append(userIDs, *sol.SubmittedBy)
+			}
+		}
+	}
+	var plans []models.ExecutionPlan
+	if fields[TaskFieldPlan] {
+		database.DB.Select("DISTINCT ON (task_id) *").
+			Where("task_id IN ?", taskIDs).
+			Order("task_id, version DESC").
+			Find(&plans)
+		for _, plan := range plans {
+			if plan.SubmittedBy != nil {
+				userIDs = append(userIDs, *plan.SubmittedBy)
+			}
+		}
+	}
+
+	usernames := loadUsernames(userIDs)
+	uploadService := &UploadService{}
+
+	var solutionAttachments, planAttachments, taskAttachments map[uint][]dto.AttachmentDetailResult
+	if fields[TaskFieldAttachments] {
+		taskAttachments = uploadService.GetTaskOwnAttachmentsBatch(taskIDs)
+		solutionIDs := make([]uint, 0, len(solutions))
+		for _, sol := range solutions {
+			solutionIDs = append(solutionIDs, sol.ID)
+		}
+		solutionAttachments = uploadService.GetSolutionAttachmentsBatch(solutionIDs)
+		planIDs := make([]uint, 0, len(plans))
+		for _, plan := range plans {
+			planIDs = append(planIDs, plan.ID)
+		}
+		planAttachments = uploadService.GetPlanAttachmentsBatch(planIDs)
+	}
+
+	solutionByTask := make(map[uint]*dto.SolutionListItemResponse, len(solutions))
+	for _, sol := range solutions {
+		item := &dto.SolutionListItemResponse{
+			ID:          sol.ID,
+			Version:     fmt.Sprintf("v%d", sol.Version),
+			Title:       sol.Title,
+			Status:      sol.Status,
+			SubmittedAt: dto.PtrToResponseTime(sol.SubmittedAt),
+			Attachments: solutionAttachments[sol.ID],
+		}
+		if sol.SubmittedBy != nil {
+			item.SubmittedBy = *sol.SubmittedBy
+			item.SubmittedByUsername = usernames[*sol.SubmittedBy]
+		}
+		solutionByTask[sol.TaskID] = item
+	}
+
+	planByTask := make(map[uint]*dto.ExecutionPlanListItemResponse, len(plans))
+	for _, plan := range plans {
+		item := &dto.ExecutionPlanListItemResponse{
+			ID:          plan.ID,
+			Version:     fmt.Sprintf("v%d", plan.Version),
+			Title:       plan.Title,
+			Status:      plan.Status,
+			SubmittedAt: dto.PtrToResponseTime(plan.SubmittedAt),
+			Attachments: planAttachments[plan.ID],
+		}
+		if plan.SubmittedBy != nil {
+			item.SubmittedBy = *plan.SubmittedBy
+			item.SubmittedByUsername = usernames[*plan.SubmittedBy]
+		}
+		planByTask[plan.TaskID] = item
+	}
+
+	var departmentNames map[uint]string
+	if fields[TaskFieldDepartment] {
+		deptIDs := make([]uint, 0)
+		for _, node := range nodes {
+			if node.DepartmentID > 0 {
+				deptIDs = append(deptIDs, node.DepartmentID)
+			}
+		}
+		departmentNames = loadDepartmentNames(deptIDs)
+	}
+
+	var tagsByTask map[uint][]string
+	if fields[TaskFieldTags] {
+		tagsByTask = loadTaskTagNames(taskIDs)
+	}
+
+	for _, node := range nodes {
+		if fields[TaskFieldCreator] {
+			node.CreatorUsername = usernames[node.CreatorID]
+		}
+		if fields[TaskFieldExecutor] && node.ExecutorID > 0 {
+			node.ExecutorUsername = usernames[node.ExecutorID]
+		}
+		if fields[TaskFieldDepartment] && node.DepartmentID > 0 {
+			node.DepartmentName = departmentNames[node.DepartmentID]
+		}
+		if fields[TaskFieldTags] {
+			node.Tags = tagsByTask[node.ID]
+		}
+		if fields[TaskFieldAttachments] {
+			node.TaskAttachments = taskAttachments[node.ID]
+		}
+		node.LatestSolution = solutionByTask[node.ID]
+		node.LatestExecutionPlan = planByTask[node.ID]
+	}
+
+	return responses
+}
+
+// loadSubtasksBatch 逐层批量加载子任务并挂到父任务上，返回所有新加载的子任务节点
+func (s *TaskService) loadSubtasksBatch(roots []*dto.TaskResponse) []*dto.TaskResponse {
+	var loaded []*dto.TaskResponse
+	visited := make(map[uint]bool, len(roots))
+	for _, node := range roots {
+		visited[node.ID] = true
+	}
+
+	level := roots
+	for len(level) > 0 {
+		parents := make(map[uint]*dto.TaskResponse)
+		parentIDs := make([]uint, 0)
+		for _, node := range level {
+			if node.TotalSubtasks > 0 {
+				parents[node.ID] = node
+				parentIDs = append(parentIDs, node.ID)
+			}
+		}
+		if len(parentIDs) == 0 {
+			break
+		}
+
+		var subtasks []models.Task
+		if err := database.DB.Where("parent_task_id IN ?", parentIDs).
+			Order("parent_task_id ASC, child_sequence ASC").
+			Find(&subtasks).Error; err != nil {
+			break
+		}
+
+		next := make([]*dto.TaskResponse, 0, len(subtasks))
+		for i := range subtasks {
+			st := &subtasks[i]
+			// 防止异常数据形成环导致无限加载
+			if visited[st.ID] || st.ParentTaskID == nil {
+				continue
+			}
+			visited[st.ID] = true
+			subResp := s.toTaskResponseBase(st)
+			parent := parents[*st.ParentTaskID]
+			parent.Subtasks = append(parent.Subtasks, &subResp)
+			next = append(next, &subResp)
+		}
+		loaded = append(loaded, next...)
+		level = next
+	}
+
+	return loaded
+}
+
+// loadUsernames 批量查询用户名
+func loadUsernames(userIDs []uint) map[uint]string {
+	names := make(map[uint]string)
+	userIDs = uniqueUintSlice(userIDs)
+	if len(userIDs) == 0 {
+		return names
+	}
+
+	var users []models.User
+	if err := database.DB.Select("id, username").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return names
+	}
+	for _, user := range users {
+		names[user.ID] = user.Username
+	}
+	return names
+}
+
+// loadDepartmentNames 批量查询部门名称
+func loadDepartmentNames(deptIDs []uint) map[uint]string {
+	names := make(map[uint]string)
+	deptIDs = uniqueUintSlice(deptIDs)
+	if len(deptIDs) == 0 {
+		return names
+	}
+
+	var departments []models.Department
+	if err := database.DB.Select("id, name").Where("id IN ?", deptIDs).Find(&departments).Error; err != nil {
+		return names
+	}
+	for _, dept := range departments {
+		names[dept.ID] = dept.Name
+	}
+	return names
+}
+
+// loadTaskTagNames 批量查询任务标签名称
+func loadTaskTagNames(taskIDs []uint) map[uint][]string {
+	tags := make(map[uint][]string)
+	if len(taskIDs) == 0 {
+		return tags
+	}
+
+	var rows []struct {
+		TaskID uint
+		Name   string
+	}
+	if err := database.DB.Table("task_tag_rel r").
+		Select("r.task_id, g.name").
+		Joins("JOIN task_tags g ON g.id = r.tag_id").
+		Where("r.task_id IN ? AND g.deleted_at IS NULL", taskIDs).
+		Order("g.id ASC").
+		Scan(&rows).Error; err != nil {
+		return tags
+	}
+	for _, row := range rows {
+		tags[row.TaskID] = append(tags[row.TaskID], row.Name)
+	}
+	return tags
+}
@@ -0,0 +1,872 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 任务查询语言（q 参数）
+//
+// 语法：
+//
+//	query     := or_expr
+//	or_expr   := and_expr { "or" and_expr }
+//	and_expr  := not_expr { "and" not_expr }
+//	not_expr  := "not" not_expr | "(" or_expr ")" | condition
+//	condition := field op value
+//	           | field ["not"] "in" "(" value { "," value } ")"
+//	           | field "under" value            （部门及其所有下级部门）
+//	           | field "is" ["not"] "empty"     （字段为空/不为空）
+//	op        := "=" | "!=" | ">" | ">=" | "<" | "<=" | "~"（包含） | "!~"（不包含）
+//	value     := 标识符 | 数字 | "字符串" | 日期(2006-01-02) | 相对时间(+7d/-2w/+3h) | me | today | now
+//
+// 示例：
+//
+//	status in (req_in_progress, req_blocked) and priority >= 3 and executor = me and due < +7d
+//	department under 12 and tag in (后端, 性能) and not pool = true
+//	title ~ "登录" or text ~ 支付
+
+// TaskQueryError 任务查询语法错误（Position 为出错位置，从 1 开始按字符计数）
+type TaskQueryError struct {
+	// 出错位置（从 1 开始，按字符计数）
+	Position int `json:"position"`
+	// 错误描述
+	Message string `json:"message"`
+}
+
+// Error 实现 error 接口
+func (e *TaskQueryError) Error() string {
+	return fmt.Sprintf("查询语法错误（位置 %d）: %s", e.Position, e.Message)
+}
+
+func newQueryError(pos int, format string, args ...interface{}) *TaskQueryError {
+	return &TaskQueryError{Position: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// ========================================
+// 词法分析
+// ========================================
+
+type queryTokenKind int
+
+const (
+	tokenEOF queryTokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenDate
+	tokenRelDate
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// queryToken 词法单元
+type queryToken struct {
+	kind queryTokenKind
+	text string
+	pos  int
+}
+
+// tokenizeTaskQuery 将查询字符串拆分为词法单元
+func tokenizeTaskQuery(input string) ([]queryToken, error) {
+	runes := []rune(input)
+	var tokens []queryToken
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, queryToken{kind: tokenComma, text: ",", pos: pos})
+			i++
+		case r == '"' || r == '\'':
+			quote := r
+			var b strings.Builder
+			j := i + 1
+			closed := false
+			for j < len(runes) {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					b.WriteRune(runes[j+1])
+					j += 2
+					continue
+				}
+				if runes[j] == quote {
+					closed = true
+					break
+				}
+				b.WriteRune(runes[j])
+				j++
+			}
+			if !closed {
+				return nil, newQueryError(pos, "字符串缺少结束引号")
+			}
+			tokens = append(tokens, queryToken{kind: tokenString, text: b.String(), pos: pos})
+			i = j + 1
+		case r == '=' || r == '~':
+			tokens = append(tokens, queryToken{kind: tokenOp, text: string(r), pos: pos})
+			i++
+		case r == '!' || r == '>' || r == '<':
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				tokens = append(tokens, queryToken{kind: tokenOp, text: string(runes[i : i+2]), pos: pos})
+				i += 2
+			} else if r == '!' {
+				return nil, newQueryError(pos, "无法识别的运算符 \"!\"，是否想使用 \"!=\" 或 \"!~\"")
+			} else {
+				tokens = append(tokens, queryToken{kind: tokenOp, text: string(r), pos: pos})
+				i++
+			}
+		case (r == '+' || r == '-') && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			j := i + 1
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			if j < len(runes) && strings.ContainsRune("hdwmy", runes[j]) {
+				tokens = append(tokens, queryToken{kind: tokenRelDate, text: string(runes[i : j+1]), pos: pos})
+				j++
+			} else {
+				tokens = append(tokens, queryToken{kind: tokenNumber, text: string(runes[i:j]), pos: pos})
+			}
+			if j < len(runes) && isQueryIdentRune(runes[j]) {
+				return nil, newQueryError(j+1, "无效的相对时间，格式应为 +7d/-2w/+3h 等（单位：h/d/w/m/y）")
+			}
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '-') {
+				j++
+			}
+			text := string(runes[i:j])
+			if strings.Contains(text, "-") {
+				if _, err := time.ParseInLocation("2006-01-02", text, time.Local); err != nil {
+					return nil, newQueryError(pos, "无效的日期 \"%s\"，格式应为 2006-01-02", text)
+				}
+				tokens = append(tokens, queryToken{kind: tokenDate, text: text, pos: pos})
+			} else {
+				tokens = append(tokens, queryToken{kind: tokenNumber, text: text, pos: pos})
+			}
+			if j < len(runes) && isQueryIdentRune(runes[j]) {
+				return nil, newQueryError(j+1, "数字后存在无法识别的字符")
+			}
+			i = j
+		case isQueryIdentRune(r):
+			j := i
+			for j < len(runes) && isQueryIdentRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, queryToken{kind: tokenIdent, text: string(runes[i:j]), pos: pos})
+			i = j
+		default:
+			return nil, newQueryError(pos, "无法识别的字符 \"%c\"", r)
+		}
+	}
+	tokens = append(tokens, queryToken{kind: tokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
+
+// isQueryIdentRune 判断字符是否可以出现在标识符中
+func isQueryIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+// ========================================
+// 语法分析
+// ========================================
+
+// queryNode 语法树节点
+type queryNode interface{}
+
+// queryLogicalNode 逻辑组合节点（and/or）
+type queryLogicalNode struct {
+	op    string
+	left  queryNode
+	right queryNode
+}
+
+// queryNotNode 取反节点
+type queryNotNode struct {
+	expr queryNode
+}
+
+// queryConditionNode 条件节点
+type queryConditionNode struct {
+	field  string
+	spec   *taskQueryField
+	pos    int
+	op     string
+	values []queryToken
+}
+
+type taskQueryParser struct {
+	tokens []queryToken
+	index  int
+}
+
+// parseTaskQuery 解析任务查询语言，返回语法树
+func parseTaskQuery(input string) (queryNode, error) {
+	tokens, err := tokenizeTaskQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &taskQueryParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, newQueryError(1, "查询条件不能为空")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, newQueryError(tok.pos, "多余的内容 \"%s\"，条件之间需要使用 and/or 连接", tok.text)
+	}
+	return node, nil
+}
+
+func (p *taskQueryParser) peek() queryToken {
+	return p.tokens[p.index]
+}
+
+func (p *taskQueryParser) next() queryToken {
+	tok := p.tokens[p.index]
+	if tok.kind != tokenEOF {
+		p.index++
+	}
+	return tok
+}
+
+// isKeyword 判断当前词法单元是否为指定关键字（不区分大小写）
+func (p *taskQueryParser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && strings.EqualFold(tok.text, word)
+}
+
+func (p *taskQueryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryLogicalNode{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *taskQueryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryLogicalNode{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *taskQueryParser) parseNot() (queryNode, error) {
+	if p.isKeyword("not") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNotNode{expr: expr}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		open := p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, newQueryError(p.peek().pos, "缺少右括号，对应位置 %d 的左括号", open.pos)
+		}
+		p.next()
+		return expr, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *taskQueryParser) parseCondition() (queryNode, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokenIdent {
+		if fieldTok.kind == tokenEOF {
+			return nil, newQueryError(fieldTok.pos, "查询意外结束，缺少查询条件")
+		}
+		return nil, newQueryError(fieldTok.pos, "此处应为字段名，实际为 \"%s\"", fieldTok.text)
+	}
+	fieldName := strings.ToLower(fieldTok.text)
+	spec, ok := taskQueryFields[fieldName]
+	if !ok {
+		return nil, newQueryError(fieldTok.pos, "未知字段 \"%s\"，可用字段：%s", fieldTok.text, taskQueryFieldNames())
+	}
+
+	cond := &queryConditionNode{field: fieldName, spec: spec, pos: fieldTok.pos}
+	opTok := p.peek()
+
+	switch {
+	case opTok.kind == tokenOp:
+		p.next()
+		cond.op = opTok.text
+		valueTok := p.next()
+		if err := p.checkValueToken(valueTok); err != nil {
+			return nil, err
+		}
+		cond.values = []queryToken{valueTok}
+	case p.isKeyword("in") || p.isKeyword("not"):
+		cond.op = "in"
+		if p.isKeyword("not") {
+			p.next()
+			if !p.isKeyword("in") {
+				return nil, newQueryError(p.peek().pos, "\"not\" 之后应为 \"in\"")
+			}
+			cond.op = "not in"
+		}
+		p.next()
+		if p.peek().kind != tokenLParen {
+			return nil, newQueryError(p.peek().pos, "\"in\" 之后应为左括号")
+		}
+		p.next()
+		for {
+			valueTok := p.next()
+			if err := p.checkValueToken(valueTok); err != nil {
+				return nil, err
+			}
+			cond.values = append(cond.values, valueTok)
+			if p.peek().kind == tokenComma {
+				p.next()
+				continue
+			}
+			if p.peek().kind != tokenRParen {
+				return nil, newQueryError(p.peek().pos, "值列表中应为逗号或右括号")
+			}
+			p.next()
+			break
+		}
+	case p.isKeyword("under"):
+		p.next()
+		cond.op = "under"
+		valueTok := p.next()
+		if err := p.checkValueToken(valueTok); err != nil {
+			return nil, err
+		}
+		cond.values = []queryToken{valueTok}
+	case p.isKeyword("is"):
+		p.next()
+		cond.op = "is empty"
+		if p.isKeyword("not") {
+			p.next()
+			cond.op = "is not empty"
+		}
+		if !p.isKeyword("empty") {
+			return nil, newQueryError(p.peek().pos, "\"is\" 之后应为 \"empty\" 或 \"not empty\"")
+		}
+		p.next()
+	default:
+		if opTok.kind == tokenEOF {
+			return nil, newQueryError(opTok.pos, "字段 \"%s\" 之后缺少运算符", fieldTok.text)
+		}
+		return nil, newQueryError(opTok.pos, "此处应为运算符（=、!=、>、>=、<、<=、~、!~、in、under、is），实际为 \"%s\"", opTok.text)
+	}
+
+	if !spec.supports(cond.op) {
+		return nil, newQueryError(opTok.pos, "字段 \"%s\" 不支持运算符 \"%s\"", fieldTok.text, cond.op)
+	}
+	return cond, nil
+}
+
+// checkValueToken 校验值词法单元
+func (p *taskQueryParser) checkValueToken(tok queryToken) error {
+	switch tok.kind {
+	case tokenIdent, tokenNumber, tokenString, tokenDate, tokenRelDate:
+		if tok.kind == tokenIdent {
+			lower := strings.ToLower(tok.text)
+			if lower == "and" || lower == "or" {
+				return newQueryError(tok.pos, "此处应为值，实际为关键字 \"%s\"", tok.text)
+			}
+		}
+		return nil
+	case tokenEOF:
+		return newQueryError(tok.pos, "查询意外结束，缺少值")
+	default:
+		return newQueryError(tok.pos, "此处应为值，实际为 \"%s\"", tok.text)
+	}
+}
+
+// ========================================
+// 字段定义
+// ========================================
+
+const (
+	queryFieldString = "string" // 字符串编码（精确匹配）
+	queryFieldText   = "text"   // 文本（支持包含）
+	queryFieldInt    = "int"    // 整数
+	queryFieldUser   = "user"   // 用户（支持 me/用户ID/用户名）
+	queryFieldDate   = "date"   // 日期时间
+	queryFieldBool   = "bool"   // 布尔
+	queryFieldDept   = "dept"   // 部门（支持部门ID/部门名称，under 匹配下级部门）
+	queryFieldTag    = "tag"    // 标签名称
+)
+
+// taskQueryField 可查询字段定义
+type taskQueryField struct {
+	// 数据库列（text 类型可为多列，逗号分隔）
+	column string
+	// 字段类型
+	kind string
+	// 是否可为空（支持 is empty）
+	nullable bool
+}
+
+// taskQueryFields 可查询字段（含别名）
+var taskQueryFields = map[string]*taskQueryField{
+	"status":     {column: "status_code", kind: queryFieldString},
+	"type":       {column: "task_type_code", kind: queryFieldString},
+	"no":         {column: "task_no", kind: queryFieldText},
+	"title":      {column: "title", kind: queryFieldText},
+	"text":       {column: "title,description", kind: queryFieldText},
+	"priority":   {column: "priority", kind: queryFieldInt},
+	"progress":   {column: "progress", kind: queryFieldInt},
+	"level":      {column: "task_level", kind: queryFieldInt},
+	"parent":     {column: "parent_task_id", kind: queryFieldInt, nullable: true},
+	"executor":   {column: "executor_id", kind: queryFieldUser, nullable: true},
+	"creator":    {column: "creator_id", kind: queryFieldUser},
+	"department": {column: "department_id", kind: queryFieldDept, nullable: true},
+	"dept":       {column: "department_id", kind: queryFieldDept, nullable: true},
+	"tag":        {column: "id", kind: queryFieldTag, nullable: true},
+	"tags":       {column: "id", kind: queryFieldTag, nullable: true},
+	"due":        {column: "expected_end_date", kind: queryFieldDate, nullable: true},
+	"start":      {column: "expected_start_date", kind: queryFieldDate, nullable: true},
+	"started":    {column: "actual_start_date", kind: queryFieldDate, nullable: true},
+	"completed":  {column: "actual_end_date", kind: queryFieldDate, nullable: true},
+	"created":    {column: "created_at", kind: queryFieldDate},
+	"updated":    {column: "updated_at", kind: queryFieldDate},
+	"pool":       {column: "is_in_pool", kind: queryFieldBool},
+	"cross":      {column: "is_cross_department", kind: queryFieldBool},
+}
+
+// taskQueryFieldNames 返回可用字段名（用于错误提示）
+func taskQueryFieldNames() string {
+	return "status, type, no, title, text, priority, progress, level, parent, executor, creator, department, tag, due, start, started, completed, created, updated, pool, cross"
+}
+
+// supports 判断字段是否支持指定运算符
+func (f *taskQueryField) supports(op string) bool {
+	if op == "is empty" || op == "is not empty" {
+		return f.nullable
+	}
+	switch f.kind {
+	case queryFieldString:
+		return op == "=" || op == "!=" || op == "in" || op == "not in" || op == "~" || op == "!~"
+	case queryFieldText:
+		return op == "=" || op == "!=" || op == "~" || op == "!~"
+	case queryFieldInt:
+		return op != "~" && op != "!~" && op != "under"
+	case queryFieldUser, queryFieldTag:
+		return op == "=" || op == "!=" || op == "in" || op == "not in"
+	case queryFieldDept:
+		return op == "=" || op == "!=" || op == "in" || op == "not in" || op == "under"
+	case queryFieldDate:
+		return op == "=" || op == "!=" || op == ">" || op == ">=" || op == "<" || op == "<="
+	case queryFieldBool:
+		return op == "=" || op == "!="
+	}
+	return false
+}
+
+// priorityAliases 优先级别名
+var priorityAliases = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+	"urgent": 4,
+}
+
+// ========================================
+// 编译为 SQL 条件
+// ========================================
+
+// taskQueryCompiler 将语法树编译为参数化 SQL 条件
+type taskQueryCompiler struct {
+	userID uint
+	now    time.Time
+	// 列名前缀（如 "tasks."）
+	prefix string
+	args   []interface{}
+}
+
+// CompileTaskQuery 将任务查询语言编译为参数化的 SQL 条件（可直接用于 GORM Where）
+// userID 用于解析 me，now 用于解析相对时间
+func CompileTaskQuery(input string, userID uint, now time.Time) (string, []interface{}, error) {
+	node, err := parseTaskQuery(input)
+	if err != nil {
+		return "", nil, err
+	}
+	c := &taskQueryCompiler{userID: userID, now: now, prefix: "tasks."}
+	sql, err := c.compile(node)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, c.args, nil
+}
+
+func (c *taskQueryCompiler) compile(node queryNode) (string, error) {
+	switch n := node.(type) {
+	case *queryLogicalNode:
+		left, err := c.compile(n.left)
+		if err != nil {
+			return "", err
+		}
+		right, err := c.compile(n.right)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s %s %s)", left, n.op, right), nil
+	case *queryNotNode:
+		expr, err := c.compile(n.expr)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(NOT %s)", expr), nil
+	case *queryConditionNode:
+		return c.compileCondition(n)
+	}
+	return "", newQueryError(1, "无法编译的查询节点")
+}
+
+func (c *taskQueryCompiler) compileCondition(n *queryConditionNode) (string, error) {
+	column := c.prefix + n.spec.column
+
+	if n.op == "is empty" || n.op == "is not empty" {
+		if n.spec.kind == queryFieldTag {
+			sub := "SELECT r.task_id FROM task_tag_rel r JOIN task_tags g ON g.id = r.tag_id WHERE g.deleted_at IS NULL"
+			if n.op == "is empty" {
+				return fmt.Sprintf("%s NOT IN (%s)", column, sub), nil
+			}
+			return fmt.Sprintf("%s IN (%s)", column, sub), nil
+		}
+		if n.op == "is empty" {
+			return column + " IS NULL", nil
+		}
+		return column + " IS NOT NULL", nil
+	}
+
+	switch n.spec.kind {
+	case queryFieldString:
+		return c.compileString(column, n)
+	case queryFieldText:
+		return c.compileText(n)
+	case queryFieldInt:
+		return c.compileInt(column, n)
+	case queryFieldUser:
+		return c.compileUser(column, n)
+	case queryFieldDept:
+		return c.compileDept(column, n)
+	case queryFieldTag:
+		return c.compileTag(column, n)
+	case queryFieldDate:
+		return c.compileDate(column, n)
+	case queryFieldBool:
+		return c.compileBool(column, n)
+	}
+	return "", newQueryError(n.pos, "不支持的字段类型")
+}
+
+// sqlOperator 将查询运算符转换为 SQL 运算符
+func sqlOperator(op string) string {
+	if op == "!=" {
+		return "<>"
+	}
+	return op
+}
+
+// nullableNegation 对可空字段的否定条件补充 IS NULL（如 executor != me 也应包含未指派的任务）
+func (c *taskQueryCompiler) nullableNegation(column string, n *queryConditionNode, sql string) string {
+	if n.spec.nullable && (n.op == "!=" || n.op == "not in") {
+		return fmt.Sprintf("(%s IS NULL OR %s)", column, sql)
+	}
+	return sql
+}
+
+func (c *taskQueryCompiler) compileString(column string, n *queryConditionNode) (string, error) {
+	var values []string
+	for _, v := range n.values {
+		if v.kind == tokenRelDate {
+			return "", newQueryError(v.pos, "字段 \"%s\" 的值不能为相对时间", n.field)
+		}
+		values = append(values, v.text)
+	}
+	switch n.op {
+	case "in", "not in":
+		c.args = append(c.args, values)
+		return fmt.Sprintf("%s %s ?", column, strings.ToUpper(n.op)), nil
+	case "~", "!~":
+		c.args = append(c.args, "%"+escapeLikePattern(values[0])+"%")
+		if n.op == "~" {
+			return column + " ILIKE ?", nil
+		}
+		return column + " NOT ILIKE ?", nil
+	default:
+		c.args = append(c.args, values[0])
+		return fmt.Sprintf("%s %s ?", column, sqlOperator(n.op)), nil
+	}
+}
+
+func (c *taskQueryCompiler) compileText(n *queryConditionNode) (string, error) {
+	v := n.values[0]
+	if v.kind == tokenRelDate {
+		return "", newQueryError(v.pos, "字段 \"%s\" 的值不能为相对时间", n.field)
+	}
+	var parts []string
+	for _, col := range strings.Split(n.spec.column, ",") {
+		column := c.prefix + col
+		switch n.op {
+		case "~":
+			parts = append(parts, column+" ILIKE ?")
+			c.args = append(c.args, "%"+escapeLikePattern(v.text)+"%")
+		case "!~":
+			parts = append(parts, fmt.Sprintf("coalesce(%s, '') NOT ILIKE ?", column))
+			c.args = append(c.args, "%"+escapeLikePattern(v.text)+"%")
+		default:
+			parts = append(parts, fmt.Sprintf("%s %s ?", column, sqlOperator(n.op)))
+			c.args = append(c.args, v.text)
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	joiner := " OR "
+	if n.op == "!~" || n.op == "!=" {
+		joiner = " AND "
+	}
+	return "(" + strings.Join(parts, joiner) + ")", nil
+}
+
+func (c *taskQueryCompiler) compileInt(column string, n *queryConditionNode) (string, error) {
+	var values []int
+	for _, v := range n.values {
+		if n.field == "priority" && v.kind == tokenIdent {
+			if p, ok := priorityAliases[strings.ToLower(v.text)]; ok {
+				values = append(values, p)
+				continue
+			}
+		}
+		if v.kind != tokenNumber {
+			return "", newQueryError(v.pos, "字段 \"%s\" 的值应为数字，实际为 \"%s\"", n.field, v.text)
+		}
+		num, err := strconv.Atoi(v.text)
+		if err != nil {
+			return "", newQueryError(v.pos, "无效的数字 \"%s\"", v.text)
+		}
+		values = append(values, num)
+	}
+	if n.op == "in" || n.op == "not in" {
+		c.args = append(c.args, values)
+		return c.nullableNegation(column, n, fmt.Sprintf("%s %s ?", column, strings.ToUpper(n.op))), nil
+	}
+	c.args = append(c.args, values[0])
+	return c.nullableNegation(column, n, fmt.Sprintf("%s %s ?", column, sqlOperator(n.op))), nil
+}
+
+// userValue 解析用户值：me/用户ID/用户名
+func (c *taskQueryCompiler) userValue(field string, v queryToken) (string, error) {
+	switch {
+	case v.kind == tokenIdent && strings.EqualFold(v.text, "me"):
+		c.args = append(c.args, c.userID)
+		return "?", nil
+	case v.kind == tokenNumber:
+		id, err := strconv.ParseUint(v.text, 10, 32)
+		if err != nil {
+			return "", newQueryError(v.pos, "无效的用户ID \"%s\"", v.text)
+		}
+		c.args = append(c.args, uint(id))
+		return "?", nil
+	case v.kind == tokenIdent || v.kind == tokenString:
+		c.args = append(c.args, v.text)
+		return "(SELECT u.id FROM users u WHERE u.username = ? AND u.deleted_at IS NULL LIMIT 1)", nil
+	}
+	return "", newQueryError(v.pos, "字段 \"%s\" 的值应为 me、用户ID或用户名", field)
+}
+
+func (c *taskQueryCompiler) compileUser(column string, n *queryConditionNode) (string, error) {
+	var fragments []string
+	for _, v := range n.values {
+		fragment, err := c.userValue(n.field, v)
+		if err != nil {
+			return "", err
+		}
+		fragments = append(fragments, fragment)
+	}
+	if n.op == "in" || n.op == "not in" {
+		sql := fmt.Sprintf("%s %s (%s)", column, strings.ToUpper(n.op), strings.Join(fragments, ", "))
+		return c.nullableNegation(column, n, sql), nil
+	}
+	return c.nullableNegation(column, n, fmt.Sprintf("%s %s %s", column, sqlOperator(n.op), fragments[0])), nil
+}
+
+// deptValue 解析部门值：部门ID/部门名称
+func (c *taskQueryCompiler) deptValue(v queryToken) (string, error) {
+	switch v.kind {
+	case tokenNumber:
+		id, err := strconv.ParseUint(v.text, 10, 32)
+		if err != nil {
+			return "", newQueryError(v.pos, "无效的部门ID \"%s\"", v.text)
+		}
+		c.args = append(c.args, uint(id))
+		return "?", nil
+	case tokenIdent, tokenString:
+		c.args = append(c.args, v.text)
+		return "(SELECT d.id FROM departments d WHERE d.name = ? AND d.deleted_at IS NULL LIMIT 1)", nil
+	}
+	return "", newQueryError(v.pos, "部门的值应为部门ID或部门名称")
+}
+
+func (c *taskQueryCompiler) compileDept(column string, n *queryConditionNode) (string, error) {
+	var fragments []string
+	for _, v := range n.values {
+		fragment, err := c.deptValue(v)
+		if err != nil {
+			return "", err
+		}
+		fragments = append(fragments, fragment)
+	}
+	switch n.op {
+	case "under":
+		// 递归查询部门及其所有下级部门
+		return fmt.Sprintf("%s IN (WITH RECURSIVE dept_tree AS ("+
+			"SELECT d.id FROM departments d WHERE d.id = %s AND d.deleted_at IS NULL "+
+			"UNION ALL SELECT c.id FROM departments c JOIN dept_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL"+
+			") SELECT id FROM dept_tree)", column, fragments[0]), nil
+	case "in", "not in":
+		sql := fmt.Sprintf("%s %s (%s)", column, strings.ToUpper(n.op), strings.Join(fragments, ", "))
+		return c.nullableNegation(column, n, sql), nil
+	default:
+		return c.nullableNegation(column, n, fmt.Sprintf("%s %s %s", column, sqlOperator(n.op), fragments[0])), nil
+	}
+}
+
+func (c *taskQueryCompiler) compileTag(column string, n *queryConditionNode) (string, error) {
+	var names []string
+	for _, v := range n.values {
+		if v.kind != tokenIdent && v.kind != tokenString && v.kind != tokenNumber {
+			return "", newQueryError(v.pos, "标签的值应为标签名称")
+		}
+		names = append(names, v.text)
+	}
+	c.args = append(c.args, names)
+	sub := "SELECT r.task_id FROM task_tag_rel r JOIN task_tags g ON g.id = r.tag_id WHERE g.name IN ? AND g.deleted_at IS NULL"
+	if n.op == "!=" || n.op == "not in" {
+		return fmt.Sprintf("%s NOT IN (%s)", column, sub), nil
+	}
+	return fmt.Sprintf("%s IN (%s)", column, sub), nil
+}
+
+// dateValue 解析日期值，返回时间点以及是否为整天（用于 = 比较）
+func (c *taskQueryCompiler) dateValue(field string, v queryToken) (time.Time, bool, error) {
+	switch v.kind {
+	case tokenDate:
+		t, err := time.ParseInLocation("2006-01-02", v.text, c.now.Location())
+		if err != nil {
+			return time.Time{}, false, newQueryError(v.pos, "无效的日期 \"%s\"", v.text)
+		}
+		return t, true, nil
+	case tokenRelDate:
+		sign := 1
+		if v.text[0] == '-' {
+			sign = -1
+		}
+		amount, err := strconv.Atoi(v.text[1 : len(v.text)-1])
+		if err != nil {
+			return time.Time{}, false, newQueryError(v.pos, "无效的相对时间 \"%s\"", v.text)
+		}
+		amount *= sign
+		switch v.text[len(v.text)-1] {
+		case 'h':
+			return c.now.Add(time.Duration(amount) * time.Hour), false, nil
+		case 'd':
+			return c.now.AddDate(0, 0, amount), false, nil
+		case 'w':
+			return c.now.AddDate(0, 0, amount*7), false, nil
+		case 'm':
+			return c.now.AddDate(0, amount, 0), false, nil
+		case 'y':
+			return c.now.AddDate(amount, 0, 0), false, nil
+		}
+	case tokenIdent:
+		switch strings.ToLower(v.text) {
+		case "now":
+			return c.now, false, nil
+		case "today":
+			y, m, d := c.now.Date()
+			return time.Date(y, m, d, 0, 0, 0, 0, c.now.Location()), true, nil
+		}
+	}
+	return time.Time{}, false, newQueryError(v.pos, "字段 \"%s\" 的值应为日期（2006-01-02）、相对时间（+7d）、today 或 now", field)
+}
+
+func (c *taskQueryCompiler) compileDate(column string, n *queryConditionNode) (string, error) {
+	t, wholeDay, err := c.dateValue(n.field, n.values[0])
+	if err != nil {
+		return "", err
+	}
+
+	// 整天日期的等值比较按当天范围处理
+	if wholeDay && (n.op == "=" || n.op == "!=") {
+		c.args = append(c.args, t, t.AddDate(0, 0, 1))
+		if n.op == "=" {
+			return fmt.Sprintf("(%s >= ? AND %s < ?)", column, column), nil
+		}
+		return fmt.Sprintf("(%s IS NULL OR %s < ? OR %s >= ?)", column, column, column), nil
+	}
+	// 整天日期的 <= 和 > 比较包含当天
+	if wholeDay && (n.op == "<=" || n.op == ">") {
+		t = t.AddDate(0, 0, 1)
+		if n.op == "<=" {
+			c.args = append(c.args, t)
+			return column + " < ?", nil
+		}
+		c.args = append(c.args, t)
+		return column + " >= ?", nil
+	}
+
+	c.args = append(c.args, t)
+	return c.nullableNegation(column, n, fmt.Sprintf("%s %s ?", column, sqlOperator(n.op))), nil
+}
+
+func (c *taskQueryCompiler) compileBool(column string, n *queryConditionNode) (string, error) {
+	v := n.values[0]
+	var value bool
+	switch strings.ToLower(v.text) {
+	case "true", "yes", "1":
+		value = true
+	case "false", "no", "0":
+		value = false
+	default:
+		return "", newQueryError(v.pos, "字段 \"%s\" 的值应为 true 或 false", n.field)
+	}
+	c.args = append(c.args, value)
+	return fmt.Sprintf("%s %s ?", column, sqlOperator(n.op)), nil
+}
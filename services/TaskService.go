@@ -11,6 +11,8 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type TaskService struct{}
@@ -47,22 +49,18 @@ func (s *TaskService) CreateTask(req *dto.TaskRequest, creatorID uint) (*models.
 	// 2. 设置默认状态码（如果未提供）
 	statusCode := req.StatusCode
 	if statusCode == "" {
-		// 根据任务类型设置默认状态
-		switch req.TaskTypeCode {
-		case "requirement":
-			if req.ExecutorID == nil {
-				statusCode = "req_pending_assign"
-			} else {
-				statusCode = "req_pending_accept"
-			}
-		case "unit_task":
-			if req.ExecutorID == nil {
-				statusCode = "unit_pending_assign"
-			} else {
-				statusCode = "unit_pending_accept"
-			}
-		default:
-			statusCode = "unit_pending_assign"
+		// 根据任务类型配置的入口状态设置默认状态
+		taskType, err := (&StatusTransitionService{}).GetTaskType(req.TaskTypeCode)
+		if err != nil {
+			return nil, err
+		}
+		if req.ExecutorID == nil {
+			statusCode = taskType.PoolStatusCode
+		} else {
+			statusCode = taskType.AssignedStatusCode
+		}
+		if statusCode == "" {
+			return nil, fmt.Errorf("任务类型 %s 未配置初始状态", req.TaskTypeCode)
 		}
 	}
 
@@ -122,30 +120,15 @@ func (s *TaskService) CreateTask(req *dto.TaskRequest, creatorID uint) (*models.
 			return nil, errors.New("父任务已被删除，无法创建子任务")
 		}
 
-		// 验证父任务状态允许创建子任务
-		// // 需求类任务：只有在 req_pending_start 及之后的状态才能创建子任务
-		// if parentTask.TaskTypeCode == "requirement" {
-		// 定义 req_pending_start 之前的状态（不允许创建子任务）
-		forbiddenStatuses := []string{
-			"req_pending_assign",    // 待指派
-			"req_pending_accept",    // 待接受
-			"req_pending_solution",  // 待提交方案
-			"req_solution_review",   // 方案审核中
-			"req_solution_rejected", // 方案被驳回
-			"req_pending_plan",      // 待提交计划
-			"req_plan_review",       // 计划审核中
-			"req_plan_rejected",     // 计划被驳回
-			"unit_completed",        // 已完成
-			"unit_cancelled",        // 已取消
-			"req_completed",         // 已完成
-			"req_cancelled",         // 已取消
-		}
-		for _, status := range forbiddenStatuses {
-			if parentTask.StatusCode == status {
-				return nil, errors.New("父任务状态待开始之前不允许创建子任务")
+		// 验证父任务状态允许创建子任务（按状态的 allows_subtasks 配置判断，已完成或已取消的父任务不允许创建）
+		statusTransitionService := &StatusTransitionService{}
+		if !statusTransitionService.AllowsSubtasks(parentTask.StatusCode) {
+			switch statusTransitionService.GetStatusCategory(parentTask.StatusCode) {
+			case models.TaskStatusCategoryDone, models.TaskStatusCategoryCancelled:
+				return nil, errors.New("父任务已完成或已取消，不允许创建子任务")
 			}
+			return nil, errors.New("父任务状态待开始之前不允许创建子任务")
 		}
-		// }
 
 		// 防止循环引用
 		if err := s.validateNoCircularReference(*req.ParentTaskID, 0); err != nil {
@@ -215,6 +198,117 @@ func (s *TaskService) GetTaskList(req *dto.TaskQueryRequest, userID uint) (*dto.
 	var tasks []models.Task
 	var total int64
 
+	fields, err := parseTaskFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := s.buildTaskListQuery(req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return &dto.PaginationResponse{
+			Total:      0,
+			Page:       req.GetPage(),
+			PageSize:   req.GetPageSize(),
+			TotalPages: 0,
+			Data:       []dto.TaskResponse{},
+		}, nil
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// 分页查询
+	page := req.GetPage()
+	pageSize := req.GetPageSize()
+	offset := (page - 1) * pageSize
+
+	orderBy, err := buildTaskOrder(req, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Offset(offset).Limit(pageSize).
+		Order(orderBy).
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	// 转换为响应格式，按 fields 批量加载关联数据
+	taskResponses := s.buildTaskResponses(tasks, fields)
+
+	// 计算总页数
+	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
+
+	return &dto.PaginationResponse{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		Data:       taskResponses,
+	}, nil
+}
+
+// GetTaskListByCursor 按游标分页查询任务列表
+// 过滤和权限规则与 GetTaskList 一致，按 (排序字段, id) 做 keyset 分页，不统计总数
+// 游标分页仅支持按 created_at/updated_at 排序
+func (s *TaskService) GetTaskListByCursor(req *dto.TaskQueryRequest, userID uint) (*dto.CursorPaginationResponse, error) {
+	fields, err := parseTaskFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	keyset, err := newTaskKeyset(req)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := req.GetPageSize()
+	query, err := s.buildTaskListQuery(req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return &dto.CursorPaginationResponse{
+			PageSize: pageSize,
+			Data:     []dto.TaskResponse{},
+		}, nil
+	}
+
+	if req.Cursor != "" {
+		condition, args, err := keyset.condition(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(condition, args...)
+	}
+
+	// 多取一条用于判断是否还有下一页
+	var tasks []models.Task
+	if err := query.Limit(pageSize + 1).
+		Order(keyset.order()).
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	result := &dto.CursorPaginationResponse{PageSize: pageSize}
+	if len(tasks) > pageSize {
+		tasks = tasks[:pageSize]
+		result.HasMore = true
+		result.NextCursor = keyset.encode(&tasks[len(tasks)-1])
+	}
+	result.Data = s.buildTaskResponses(tasks, fields)
+
+	return result, nil
+}
+
+// buildTaskListQuery 构建任务列表的过滤查询（包含权限范围）
+// 返回 nil 查询表示当前条件下结果必然为空
+func (s *TaskService) buildTaskListQuery(req *dto.TaskQueryRequest, userID uint) (*gorm.DB, error) {
 	// 获取用户信息，包括角色和负责的部门
 	var user models.User
 	if err := database.DB.Preload("Roles").Preload("ManagedDepartments").First(&user, userID).Error; err != nil {
@@ -222,13 +316,7 @@ func (s *TaskService) GetTaskList(req *dto.TaskQueryRequest, userID uint) (*dto.
 	}
 
 	// 判断用户角色
-	isAdmin := false
-	for _, role := range user.Roles {
-		if role.Name == "admin" {
-			isAdmin = true
-			break
-		}
-	}
+	isAdmin := isAdminUser(&user)
 
 	// 构建查询
 	query := database.DB.Model(&models.Task{})
@@ -260,13 +348,7 @@ func (s *TaskService) GetTaskList(req *dto.TaskQueryRequest, userID uint) (*dto.
 
 		if len(allowedDepartmentIDs) == 0 {
 			// 用户没有部门，返回空结果
-			return &dto.PaginationResponse{
-				Total:      0,
-				Page:       req.GetPage(),
-				PageSize:   req.GetPageSize(),
-				TotalPages: 0,
-				Data:       []dto.TaskResponse{},
-			}, nil
+			return nil, nil
 		}
 	}
 
@@ -295,13 +377,7 @@ func (s *TaskService) GetTaskList(req *dto.TaskQueryRequest, userID uint) (*dto.
 			memberIDs := s.getDepartmentMemberIDs(*req.DepartmentID)
 			if len(memberIDs) == 0 {
 				// 部门没有成员，返回空结果
-				return &dto.PaginationResponse{
-					Total:      0,
-					Page:       req.GetPage(),
-					PageSize:   req.GetPageSize(),
-					TotalPages: 0,
-					Data:       []dto.TaskResponse{},
-				}, nil
+				return nil, nil
 			}
 			query = query.Where("creator_id IN ? OR executor_id IN ?", memberIDs, memberIDs)
 		}
@@ -341,13 +417,7 @@ func (s *TaskService) GetTaskList(req *dto.TaskQueryRequest, userID uint) (*dto.
 			allMemberIDs = uniqueUintSlice(allMemberIDs)
 
 			if len(allMemberIDs) == 0 {
-				return &dto.PaginationResponse{
-					Total:      0,
-					Page:       req.GetPage(),
-					PageSize:   req.GetPageSize(),
-					TotalPages: 0,
-					Data:       []dto.TaskResponse{},
-				}, nil
+				return nil, nil
 			}
 			query = query.Where("creator_id IN ? OR executor_id IN ?", allMemberIDs, allMemberIDs)
 		}
@@ -379,6 +449,14 @@ func (s *TaskService) GetTaskList(req *dto.TaskQueryRequest, userID uint) (*dto.
 	if req.IsInPool != nil {
 		query = query.Where("is_in_pool = ?", *req.IsInPool)
 	}
+	// 查询语言表达式
+	if req.Q != "" {
+		condition, args, err := CompileTaskQuery(req.Q, userID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(condition, args...)
+	}
 
 	// 时间筛选
 	if req.TimeRange != "" {
@@ -416,41 +494,7 @@ func (s *TaskService) GetTaskList(req *dto.TaskQueryRequest, userID uint) (*dto.
 		}
 	}
 
-	// 统计总数
-	if err := query.Count(&total).Error; err != nil {
-		return nil, err
-	}
-
-	// 分页查询
-	page := req.GetPage()
-	pageSize := req.GetPageSize()
-	offset := (page - 1) * pageSize
-
-	if err := query.Offset(offset).Limit(pageSize).
-		Order("created_at DESC").
-		Find(&tasks).Error; err != nil {
-		return nil, err
-	}
-
-	// 转换为响应格式
-	taskResponses := make([]dto.TaskResponse, len(tasks))
-	for i, task := range tasks {
-		resp := s.toTaskResponse(&task)
-		// 加载子任务、方案和计划
-		s.loadTaskAssociations(&resp, task.ID)
-		taskResponses[i] = resp
-	}
-
-	// 计算总页数
-	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
-
-	return &dto.PaginationResponse{
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-		Data:       taskResponses,
-	}, nil
+	return query, nil
 }
 
 // getDepartmentMemberIDs 获取部门所有成员的用户ID（包括负责人）
@@ -492,10 +536,95 @@ func uniqueUintSlice(slice []uint) []uint {
 	return result
 }
 
+// buildTaskVisibilityCondition 构建当前用户可见任务的 SQL 条件（alias 为任务表别名，可为空）
+// 可见范围与任务列表、我的任务保持一致：
+// - 超级管理员：不限制，返回空条件
+// - 其他用户：所属/负责部门成员创建或执行的任务 + 自己发布、执行或陪审的任务
+func (s *TaskService) buildTaskVisibilityCondition(userID uint, alias string) (string, []interface{}, error) {
+	var user models.User
+	if err := database.DB.Preload("Roles").Preload("ManagedDepartments").First(&user, userID).Error; err != nil {
+		return "", nil, errors.New("用户不存在")
+	}
+	if isAdminUser(&user) {
+		return "", nil, nil
+	}
+
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+
+	var deptIDs []uint
+	for _, dept := range user.ManagedDepartments {
+		deptIDs = append(deptIDs, dept.ID)
+	}
+	if user.DepartmentID != nil {
+		deptIDs = append(deptIDs, *user.DepartmentID)
+	}
+	memberIDs := []uint{userID}
+	for _, deptID := range uniqueUintSlice(deptIDs) {
+		memberIDs = append(memberIDs, s.getDepartmentMemberIDs(deptID)...)
+	}
+	memberIDs = uniqueUintSlice(memberIDs)
+
+	var juryTaskIDs []uint
+	database.DB.Model(&models.TaskParticipant{}).
+		Where("user_id = ? AND role = ?", userID, "jury").
+		Pluck("task_id", &juryTaskIDs)
+
+	condition := fmt.Sprintf("(%screator_id IN ? OR %sexecutor_id IN ?", prefix, prefix)
+	args := []interface{}{memberIDs, memberIDs}
+	if len(juryTaskIDs) > 0 {
+		condition += fmt.Sprintf(" OR %sid IN ?", prefix)
+		args = append(args, juryTaskIDs)
+	}
+	condition += ")"
+	return condition, args, nil
+}
+
+// taskSortColumns 任务列表允许排序的字段（白名单，防止 SQL 注入）
+var taskSortColumns = map[string]string{
+	"created_at":          "created_at",
+	"updated_at":          "updated_at",
+	"priority":            "priority",
+	"expected_start_date": "expected_start_date",
+	"expected_end_date":   "expected_end_date",
+	"task_no":             "task_no",
+	"title":               "title",
+	"status_code":         "status_code",
+	"progress":            "progress",
+}
+
+// buildTaskOrder 根据查询请求生成排序子句，未指定排序字段时使用默认排序
+func buildTaskOrder(req *dto.TaskQueryRequest, defaultOrder string) (string, error) {
+	if req.SortBy == "" {
+		return defaultOrder, nil
+	}
+	column, ok := taskSortColumns[req.SortBy]
+	if !ok {
+		return "", fmt.Errorf("不支持的排序字段: %s", req.SortBy)
+	}
+	direction := "DESC"
+	if strings.EqualFold(req.SortOrder, "asc") {
+		direction = "ASC"
+	}
+	// 追加 id 保证排序稳定
+	return fmt.Sprintf("%s %s NULLS LAST, id %s", column, direction, direction), nil
+}
+
 // GetMyTasks 查询当前用户相关的任务列表（我发布的、我执行的、我陪审的）
 func (s *TaskService) GetMyTasks(req *dto.TaskQueryRequest, userID uint) (*dto.PaginationResponse, error) {
 	var total int64
 
+	// 我的任务列表按状态分组排序，不支持游标分页
+	if req.UsesCursor() {
+		return nil, &TaskListParamError{Param: "cursor", Message: "我的任务列表不支持游标分页"}
+	}
+	fields, err := parseTaskFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
 	// 根据 MyRole 参数确定查询范围
 	myRole := req.MyRole
 	if myRole == "" {
@@ -521,6 +650,14 @@ func (s *TaskService) GetMyTasks(req *dto.TaskQueryRequest, userID uint) (*dto.P
 	if req.Priority != nil {
 		baseQuery = baseQuery.Where("priority = ?", *req.Priority)
 	}
+	// 查询语言表达式
+	if req.Q != "" {
+		condition, args, err := CompileTaskQuery(req.Q, userID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		baseQuery = baseQuery.Where(condition, args...)
+	}
 
 	// 根据角色构建查询条件
 	var taskIDs []uint
@@ -576,9 +713,13 @@ func (s *TaskService) GetMyTasks(req *dto.TaskQueryRequest, userID uint) (*dto.P
 	offset := (page - 1) * pageSize
 
 	var tasks []models.Task
-	// 排序：已完成/已取消的靠后，其他按创建时间倒序
+	// 排序：已完成/已取消的靠后，其他按指定字段排序（默认创建时间倒序）
+	orderBy, err := buildTaskOrder(req, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
 	if err := baseQuery.Offset(offset).Limit(pageSize).
-		Order("CASE WHEN status_code IN ('unit_completed', 'req_completed', 'unit_cancelled', 'req_cancelled') THEN 1 ELSE 0 END ASC, created_at DESC").
+		Order("CASE WHEN status_code IN (SELECT code FROM task_statuses WHERE category IN ('done', 'cancelled')) THEN 1 ELSE 0 END ASC, " + orderBy).
 		Find(&tasks).Error; err != nil {
 		return nil, err
 	}
@@ -589,11 +730,8 @@ func (s *TaskService) GetMyTasks(req *dto.TaskQueryRequest, userID uint) (*dto.P
 		juryTaskMap[id] = true
 	}
 
-	taskResponses := make([]dto.TaskResponse, len(tasks))
+	taskResponses := s.buildTaskResponses(tasks, fields)
 	for i, task := range tasks {
-		taskResponses[i] = s.toTaskResponse(&task)
-		// 加载子任务、方案和计划
-		s.loadTaskAssociations(&taskResponses[i], task.ID)
 		// 确定当前用户在该任务中的角色
 		if task.CreatorID == userID {
 			taskResponses[i].MyRole = "creator"
@@ -674,12 +812,16 @@ func (s *TaskService) GetTaskByID(taskID uint, userID uint) (*dto.TaskDetailResp
 	var taskStatus models.TaskStatus
 	if err := database.DB.Where("code = ?", task.StatusCode).First(&taskStatus).Error; err == nil {
 		response.TaskStatus = &dto.TaskStatusResponse{
-			ID:   taskStatus.ID,
-			Code: taskStatus.Code,
-			Name: taskStatus.Name,
+			ID:       taskStatus.ID,
+			Code:     taskStatus.Code,
+			Name:     taskStatus.Name,
+			Category: taskStatus.Category,
 		}
 	}
 
+	// 查询当前的提交截止及剩余时间
+	response.Deadline = (&DeadlineService{}).GetTaskDeadline(task.ID)
+
 	return response, nil
 }
 
@@ -743,16 +885,7 @@ func (s *TaskService) UpdateTask(taskID uint, userID uint, req *dto.UpdateTaskRe
 	isExecutor := task.ExecutorID != nil && *task.ExecutorID == userID
 
 	// 检查是否为超级管理员
-	isAdmin := false
-	var user models.User
-	if err := database.DB.Preload("Roles").First(&user, userID).Error; err == nil {
-		for _, role := range user.Roles {
-			if role.Name == "admin" {
-				isAdmin = true
-				break
-			}
-		}
-	}
+	isAdmin := (&AuthorizationService{}).IsAdmin(userID)
 
 	if !isCreator && !isExecutor && !isAdmin {
 		return errors.New("只有创建者、执行人可以更新任务")
@@ -855,11 +988,9 @@ func (s *TaskService) UpdateTask(taskID uint, userID uint, req *dto.UpdateTaskRe
 	if req.ExecutorID > 0 {
 		newExecutorID := uint(req.ExecutorID)
 		if task.ExecutorID == nil || *task.ExecutorID != newExecutorID {
-			// 如果执行人发生变化，则更新任务状态
-			if task.TaskTypeCode == "requirement" {
-				updates["status_code"] = "req_pending_accept"
-			} else {
-				updates["status_code"] = "unit_pending_accept"
+			// 如果执行人发生变化，则更新任务状态为类型配置的待接受状态
+			if taskType, err := (&StatusTransitionService{}).GetTaskType(task.TaskTypeCode); err == nil && taskType.AssignedStatusCode != "" {
+				updates["status_code"] = taskType.AssignedStatusCode
 			}
 			updates["executor_id"] = newExecutorID
 			addChange("executor_id", task.ExecutorID, newExecutorID, "更新执行人")
@@ -867,10 +998,8 @@ func (s *TaskService) UpdateTask(taskID uint, userID uint, req *dto.UpdateTaskRe
 	} else if req.ExecutorID < 0 {
 		// 传负值表示清空执行人
 		if task.ExecutorID != nil {
-			if task.TaskTypeCode == "requirement" {
-				updates["status_code"] = "req_pending_assign"
-			} else {
-				updates["status_code"] = "unit_pending_assign"
+			if taskType, err := (&StatusTransitionService{}).GetTaskType(task.TaskTypeCode); err == nil && taskType.PoolStatusCode != "" {
+				updates["status_code"] = taskType.PoolStatusCode
 			}
 			updates["executor_id"] = nil
 			addChange("executor_id", task.ExecutorID, nil, "取消执行人")
@@ -915,11 +1044,20 @@ func (s *TaskService) UpdateTask(taskID uint, userID uint, req *dto.UpdateTaskRe
 	}
 
 	// 执行更新
+	oldStatusCode := task.StatusCode
 	if err := tx.Model(&task).Updates(updates).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	// 执行人变更导致状态变化时同步实际时间和进度
+	if newStatusCode, ok := updates["status_code"].(string); ok && newStatusCode != oldStatusCode {
+		if err := onTaskStatusChanged(tx, &task, oldStatusCode, newStatusCode); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// 批量插入变更日志
 	if len(changes) > 0 {
 		if err := tx.Create(&changes).Error; err != nil {
@@ -1113,13 +1251,15 @@ func (s *TaskService) DeleteTask(taskID uint) error {
 // 1. 如果所有子任务都是完成状态，父任务状态更新为已完成
 // 2. 如果子任务有阻碍状态，父任务更新为阻碍
 // 3. 如果没有阻碍状态但有未完成的任务，父任务状态应是进行中
-func (s *TaskService) TransitStatus(taskID uint, userID uint, req *dto.TaskStatusTransitionRequest) error {
+// 命中的转换规则需要审批（requires_approval）且操作人不是审批人时，不直接变更状态，
+// 而是创建并返回状态转换申请，由任务创建人或所属部门负责人审批通过后再变更
+func (s *TaskService) TransitStatus(taskID uint, userID uint, req *dto.TaskStatusTransitionRequest) (*dto.TransitionRequestResponse, error) {
 	// 创建状态转换服务
 	statusTransition := &StatusTransitionService{}
 
 	var task models.Task
 	if err := database.DB.First(&task, taskID).Error; err != nil {
-		return errors.New("任务不存在")
+		return nil, errors.New("任务不存在")
 	}
 
 	// 保存原始状态码
@@ -1129,65 +1269,103 @@ func (s *TaskService) TransitStatus(taskID uint, userID uint, req *dto.TaskStatu
 	var toStatus models.TaskStatus
 	if err := database.DB.Where("code = ? AND task_type_code = ?", req.ToStatusCode, task.TaskTypeCode).
 		First(&toStatus).Error; err != nil {
-		return errors.New("无效的目标状态")
+		return nil, errors.New("无效的目标状态")
 	}
 
 	// 获取用户的所有角色
 	userRoles := s.determineUserRoles(task, userID)
 
 	// 使用规则验证状态转换是否允许（支持多角色）
-	if err := statusTransition.ValidateTransition(
+	rule, err := statusTransition.MatchTransition(
 		task.TaskTypeCode,
 		oldStatusCode,
 		req.ToStatusCode,
 		userRoles,
-	); err != nil {
-		return fmt.Errorf("状态转换不被允许: %v", err)
+	)
+	// 委托生效期间，创建人的代理人可以创建者身份代为处理需要审批的（审核、审批）转换，不能代为取消、指派等
+	if err != nil && isDelegateFor(database.DB, task.CreatorID, userID, &task, time.Now()) {
+		delegated, delegatedErr := statusTransition.MatchTransition(
+			task.TaskTypeCode,
+			oldStatusCode,
+			req.ToStatusCode,
+			append(userRoles, "creator"),
+		)
+		if delegatedErr == nil && delegated.RequiresApproval {
+			rule, err = delegated, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("状态转换不被允许: %v", err)
 	}
 
-	// 需求类任务状态转换的额外校验
-	if task.TaskTypeCode == "requirement" {
-		if err := s.validateRequirementStatusTransition(taskID, oldStatusCode, req.ToStatusCode); err != nil {
-			return err
+	hookCtx := &TransitionContext{
+		Task:         &task,
+		UserID:       userID,
+		FromStatus:   oldStatusCode,
+		ToStatus:     req.ToStatusCode,
+		FromCategory: statusTransition.GetStatusCategory(oldStatusCode),
+		ToCategory:   toStatus.Category,
+		Comment:      req.Comment,
+		Transition:   rule,
+	}
+
+	// 执行转换规则配置的守卫
+	if err := runTransitionGuards(hookCtx); err != nil {
+		return nil, err
+	}
+
+	// 需要审批的转换：操作人本身是审批人（或审批人的代理人）时视为已审批，否则提交申请
+	requestService := &TransitionRequestService{}
+	approverID, isApprover := requestService.resolveApprover(&task, userID)
+	if rule.RequiresApproval && !isApprover {
+		return requestService.CreateRequest(&task, rule, userID, req.Comment)
+	}
+
+	if err := s.applyTransition(database.DB, hookCtx); err != nil {
+		return nil, err
+	}
+
+	// 代理人以委托人（创建人/审批人）身份完成的转换记录代理日志
+	principalID := userID
+	if rule.RequiresApproval {
+		principalID = approverID
+	} else if rule.RequiredRole != nil && *rule.RequiredRole == "creator" {
+		principalID = task.CreatorID
+	}
+	if principalID != userID {
+		if err := logDelegatedAction(database.DB, task.ID, userID, principalID, "变更任务状态"); err != nil {
+			utils.Logger.Warnf("任务 %d 记录代理日志失败: %v", task.ID, err)
 		}
 	}
 
-	// 更新任务状态
-	if err := database.DB.Model(&task).Update("status_code", req.ToStatusCode).Error; err != nil {
+	// 执行转换规则配置的后置动作（默认更新父任务统计和状态）
+	return nil, runTransitionActions(hookCtx)
+}
+
+// applyTransition 更新任务状态并记录状态变更日志（不执行后置动作）
+func (s *TaskService) applyTransition(db *gorm.DB, ctx *TransitionContext) error {
+	// 更新任务状态，并记录实际开始/完成时间、重新计算进度
+	if err := db.Model(&models.Task{}).Where("id = ?", ctx.Task.ID).
+		Update("status_code", ctx.ToStatus).Error; err != nil {
+		return err
+	}
+	if err := onTaskStatusChanged(db, ctx.Task, ctx.FromStatus, ctx.ToStatus); err != nil {
 		return err
 	}
 
 	// 记录状态变更日志
 	changeLog := &models.TaskChangeLog{
-		TaskID:     taskID,
-		UserID:     userID,
+		TaskID:     ctx.Task.ID,
+		UserID:     ctx.UserID,
 		ChangeType: "status_change",
 		FieldName:  "status_code",
-		OldValue:   oldStatusCode,
-		NewValue:   req.ToStatusCode,
-		Comment:    req.Comment,
+		OldValue:   ctx.FromStatus,
+		NewValue:   ctx.ToStatus,
+		Comment:    ctx.Comment,
 	}
-	if err := database.DB.Create(changeLog).Error; err != nil {
+	if err := db.Create(changeLog).Error; err != nil {
 		return fmt.Errorf("记录状态变更日志失败: %v", err)
 	}
-
-	// 检测状态变化是否涉及完成或阻碍状态
-	isOldCompleted := oldStatusCode == "req_completed" || oldStatusCode == "unit_completed"
-	isNewCompleted := req.ToStatusCode == "req_completed" || req.ToStatusCode == "unit_completed"
-	isOldBlocked := oldStatusCode == "req_blocked" || oldStatusCode == "unit_blocked"
-	isNewBlocked := req.ToStatusCode == "req_blocked" || req.ToStatusCode == "unit_blocked"
-
-	// 如果状态转换涉及完成或阻碍状态变化，更新父任务的统计和状态
-	if (isOldCompleted != isNewCompleted || isOldBlocked != isNewBlocked) && task.ParentTaskID != nil {
-		if err := s.recalculateTaskStats(*task.ParentTaskID); err != nil {
-			return fmt.Errorf("更新父任务统计失败: %v", err)
-		}
-		// 更新父任务状态
-		if err := s.updateParentTaskStatus(*task.ParentTaskID, userID); err != nil {
-			return fmt.Errorf("更新父任务状态失败: %v", err)
-		}
-	}
-
 	return nil
 }
 
@@ -1196,7 +1374,7 @@ func (s *TaskService) TransitStatus(taskID uint, userID uint, req *dto.TaskStatu
 func (s *TaskService) determineUserRoles(task models.Task, userID uint) []string {
 	roles := []string{}
 
-	// 检查是否为创建者
+	// 检查是否为创建者（审核代理人不具有创建者角色，只能代为处理需要审批的转换，见 TransitStatus）
 	if task.CreatorID == userID {
 		roles = append(roles, "creator")
 	}
@@ -1247,6 +1425,25 @@ func (s *TaskService) AssignExecutor(taskID uint, executorID uint) error {
 
 // 辅助方法：将 Task 模型转换为 TaskResponse
 func (s *TaskService) toTaskResponse(task *models.Task) dto.TaskResponse {
+	response := s.toTaskResponseBase(task)
+
+	// 查询执行人用户名
+	if task.ExecutorID != nil {
+		var executor models.User
+		if err := database.DB.Select("username").First(&executor, *task.ExecutorID).Error; err == nil {
+			response.ExecutorUsername = executor.Username
+		}
+	}
+
+	// 获取任务本身的附件（不含方案和计划附件）
+	uploadService := &UploadService{}
+	response.TaskAttachments = uploadService.GetTaskOwnAttachments(task.ID)
+
+	return response
+}
+
+// toTaskResponseBase 仅转换任务自身字段，不查询任何关联数据
+func (s *TaskService) toTaskResponseBase(task *models.Task) dto.TaskResponse {
 	response := dto.TaskResponse{
 		ID:                task.ID,
 		TaskNo:            task.TaskNo,
@@ -1269,11 +1466,6 @@ func (s *TaskService) toTaskResponse(task *models.Task) dto.TaskResponse {
 	// 处理指针字段
 	if task.ExecutorID != nil {
 		response.ExecutorID = *task.ExecutorID
-		// 查询执行人用户名
-		var executor models.User
-		if err := database.DB.Select("username").First(&executor, *task.ExecutorID).Error; err == nil {
-			response.ExecutorUsername = executor.Username
-		}
 	}
 	if task.DepartmentID != nil {
 		response.DepartmentID = *task.DepartmentID
@@ -1306,10 +1498,6 @@ func (s *TaskService) toTaskResponse(task *models.Task) dto.TaskResponse {
 	response.CreatedAt = dto.ToResponseTime(task.CreatedAt)
 	response.UpdatedAt = dto.ToResponseTime(task.UpdatedAt)
 
-	// 获取任务本身的附件（不含方案和计划附件）
-	uploadService := &UploadService{}
-	response.TaskAttachments = uploadService.GetTaskOwnAttachments(task.ID)
-
 	return response
 }
 
@@ -1329,85 +1517,6 @@ func (s *TaskService) toTaskResponsePtr(task *models.Task, UserID uint) *dto.Tas
 	return &resp
 }
 
-// loadTaskAssociations 加载任务的关联数据（子任务、方案、计划）
-// 支持递归加载子任务（可以多级拆分），仅获取最新版本的方案和计划
-func (s *TaskService) loadTaskAssociations(resp *dto.TaskResponse, taskID uint) {
-	// 递归加载直接子任务
-	if resp.TotalSubtasks > 0 {
-		s.loadSubtasksRecursive(resp, taskID)
-	}
-
-	uploadService := &UploadService{}
-
-	// 加载最新版本的思路方案
-	var latestSolution models.RequirementSolution
-	if err := database.DB.Where("task_id = ?", taskID).
-		Order("version DESC").
-		First(&latestSolution).Error; err == nil {
-		item := &dto.SolutionListItemResponse{
-			ID:          latestSolution.ID,
-			Version:     fmt.Sprintf("v%d", latestSolution.Version),
-			Title:       latestSolution.Title,
-			Status:      latestSolution.Status,
-			SubmittedAt: dto.PtrToResponseTime(latestSolution.SubmittedAt),
-		}
-		if latestSolution.SubmittedBy != nil {
-			item.SubmittedBy = *latestSolution.SubmittedBy
-			// 获取提交者用户名
-			var user models.User
-			if err := database.DB.Select("username").Where("id = ?", *latestSolution.SubmittedBy).First(&user).Error; err == nil {
-				item.SubmittedByUsername = user.Username
-			}
-		}
-		// 加载方案附件
-		item.Attachments = uploadService.GetSolutionAttachments(latestSolution.ID)
-		resp.LatestSolution = item
-	}
-
-	// 加载最新版本的执行计划
-	var latestPlan models.ExecutionPlan
-	if err := database.DB.Where("task_id = ?", taskID).
-		Order("version DESC").
-		First(&latestPlan).Error; err == nil {
-		item := &dto.ExecutionPlanListItemResponse{
-			ID:          latestPlan.ID,
-			Version:     fmt.Sprintf("v%d", latestPlan.Version),
-			Title:       latestPlan.Title,
-			Status:      latestPlan.Status,
-			SubmittedAt: dto.PtrToResponseTime(latestPlan.SubmittedAt),
-		}
-		if latestPlan.SubmittedBy != nil {
-			item.SubmittedBy = *latestPlan.SubmittedBy
-			// 获取提交者用户名
-			var user models.User
-			if err := database.DB.Select("username").Where("id = ?", *latestPlan.SubmittedBy).First(&user).Error; err == nil {
-				item.SubmittedByUsername = user.Username
-			}
-		}
-		// 加载执行计划附件
-		item.Attachments = uploadService.GetPlanAttachments(latestPlan.ID)
-		resp.LatestExecutionPlan = item
-	}
-}
-
-// loadSubtasksRecursive 递归加载子任务（支持多级拆分）
-func (s *TaskService) loadSubtasksRecursive(parentResp *dto.TaskResponse, parentTaskID uint) {
-	var subtasks []models.Task
-	if err := database.DB.Where("parent_task_id = ?", parentTaskID).
-		Order("child_sequence ASC").
-		Find(&subtasks).Error; err != nil || len(subtasks) == 0 {
-		return
-	}
-
-	parentResp.Subtasks = make([]*dto.TaskResponse, len(subtasks))
-	for i, st := range subtasks {
-		subResp := s.toTaskResponse(&st)
-		// 递归加载子任务的子任务、方案和计划
-		s.loadTaskAssociations(&subResp, st.ID)
-		parentResp.Subtasks[i] = &subResp
-	}
-}
-
 // ========== 辅助方法：任务层级和统计管理 ==========
 
 // validateNoCircularReference 验证是否存在循环引用
@@ -1455,15 +1564,15 @@ func (s *TaskService) recalculateTaskStats(taskID uint) error {
 	// 统计已完成的子任务数
 	var completedCount int64
 	database.DB.Model(&models.Task{}).
-		Where("parent_task_id = ? AND deleted_at IS NULL AND (status_code = ? OR status_code = ?)",
-			taskID, "req_completed", "unit_completed").
+		Where("parent_task_id = ? AND deleted_at IS NULL", taskID).
+		Where(statusCategorySubquery, []string{models.TaskStatusCategoryDone}).
 		Count(&completedCount)
 
-	// 计算进度百分比
-	var progress int
-	if totalCount > 0 {
-		progress = int(math.Round(float64(completedCount) * 100.0 / float64(totalCount)))
-	}
+	// 计算进度百分比（任务本身已完成时为100）
+	var task models.Task
+	database.DB.Select("id, status_code").First(&task, taskID)
+	category := (&StatusTransitionService{}).GetStatusCategory(task.StatusCode)
+	progress := computeTaskProgress(int(totalCount), int(completedCount), category)
 
 	// 更新任务
 	return database.DB.Model(&models.Task{}).
@@ -1475,6 +1584,69 @@ func (s *TaskService) recalculateTaskStats(taskID uint) error {
 		}).Error
 }
 
+// computeTaskProgress 计算任务进度百分比
+// 已完成分类为100；有子任务时按已完成子任务占比；否则为0
+func computeTaskProgress(totalSubtasks, completedSubtasks int, category string) int {
+	if category == models.TaskStatusCategoryDone {
+		return 100
+	}
+	if totalSubtasks > 0 {
+		return int(math.Round(float64(completedSubtasks) * 100.0 / float64(totalSubtasks)))
+	}
+	return 0
+}
+
+// taskStatusDateUpdates 根据状态分类变化计算实际开始/完成时间的更新字段
+// 1. 首次进入进行中分类时记录实际开始时间
+// 2. 进入已完成分类时记录实际完成时间（未记录过开始时间时同时记录）
+// 3. 离开已完成分类时清空实际完成时间
+func taskStatusDateUpdates(task *models.Task, fromCategory, toCategory string, now time.Time) map[string]interface{} {
+	updates := make(map[string]interface{})
+	if toCategory == models.TaskStatusCategoryInProgress && task.ActualStartDate == nil {
+		updates["actual_start_date"] = now
+	}
+	if toCategory == models.TaskStatusCategoryDone {
+		if fromCategory != models.TaskStatusCategoryDone || task.ActualEndDate == nil {
+			updates["actual_end_date"] = now
+		}
+		if task.ActualStartDate == nil {
+			updates["actual_start_date"] = now
+		}
+	} else if fromCategory == models.TaskStatusCategoryDone && task.ActualEndDate != nil {
+		updates["actual_end_date"] = nil
+	}
+	return updates
+}
+
+// onTaskStatusChanged 任务状态变更后记录实际开始/完成时间、重新计算进度，更新方案/计划截止计时并解除 SLA 违约
+// 所有变更任务状态的地方在更新 status_code 后调用，db 可传入事务
+func onTaskStatusChanged(db *gorm.DB, task *models.Task, fromStatus, toStatus string) error {
+	now := time.Now()
+	if err := (&DeadlineService{}).OnStatusChange(db, task, toStatus, now); err != nil {
+		return err
+	}
+	if err := (&SLAService{}).OnStatusChange(db, task, now); err != nil {
+		return err
+	}
+
+	statusTransition := &StatusTransitionService{}
+	fromCategory := statusTransition.GetStatusCategory(fromStatus)
+	toCategory := statusTransition.GetStatusCategory(toStatus)
+
+	updates := taskStatusDateUpdates(task, fromCategory, toCategory, now)
+	progress := computeTaskProgress(task.TotalSubtasks, task.CompletedSubtasks, toCategory)
+	if progress != task.Progress {
+		updates["progress"] = progress
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := db.Model(&models.Task{}).Where("id = ?", task.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新任务实际时间和进度失败: %v", err)
+	}
+	return nil
+}
+
 // updateParentTaskStatus 根据子任务状态更新父任务状态
 // 规则：
 // 1. 如果所有子任务都是完成状态，父任务状态更新为已完成
@@ -1501,42 +1673,43 @@ func (s *TaskService) updateParentTaskStatus(parentTaskID uint, userID uint) err
 	// 统计已完成的子任务数
 	var completedCount int64
 	database.DB.Model(&models.Task{}).
-		Where("parent_task_id = ? AND deleted_at IS NULL AND (status_code = ? OR status_code = ?)",
-			parentTaskID, "req_completed", "unit_completed").
+		Where("parent_task_id = ? AND deleted_at IS NULL", parentTaskID).
+		Where(statusCategorySubquery, []string{models.TaskStatusCategoryDone}).
 		Count(&completedCount)
 
 	// 统计阻碍状态的子任务数
 	var blockedCount int64
 	database.DB.Model(&models.Task{}).
-		Where("parent_task_id = ? AND deleted_at IS NULL AND (status_code = ? OR status_code = ?)",
-			parentTaskID, "req_blocked", "unit_blocked").
+		Where("parent_task_id = ? AND deleted_at IS NULL", parentTaskID).
+		Where(statusCategorySubquery, []string{models.TaskStatusCategoryBlocked}).
 		Count(&blockedCount)
 
-	// 确定父任务的新状态
-	var newStatusCode string
+	// 确定父任务的目标状态分类
+	var newCategory string
 	oldStatusCode := parentTask.StatusCode
 
 	if completedCount == totalCount {
 		// 所有子任务都完成，父任务状态更新为已完成
-		if parentTask.TaskTypeCode == "requirement" {
-			newStatusCode = "req_completed"
-		} else {
-			newStatusCode = "unit_completed"
-		}
+		newCategory = models.TaskStatusCategoryDone
 	} else if blockedCount > 0 {
 		// 有阻碍状态的子任务，父任务更新为阻碍
-		if parentTask.TaskTypeCode == "requirement" {
-			newStatusCode = "req_blocked"
-		} else {
-			newStatusCode = "unit_blocked"
-		}
+		newCategory = models.TaskStatusCategoryBlocked
 	} else {
 		// 没有阻碍状态但有未完成的任务，父任务状态应是进行中
-		if parentTask.TaskTypeCode == "requirement" {
-			newStatusCode = "req_in_progress"
-		} else {
-			newStatusCode = "unit_in_progress"
-		}
+		newCategory = models.TaskStatusCategoryInProgress
+	}
+
+	// 父任务已处于该分类时不切换到同分类的其他状态
+	statusTransition := &StatusTransitionService{}
+	if statusTransition.GetStatusCategory(oldStatusCode) == newCategory {
+		return nil
+	}
+
+	// 取父任务类型下该分类的状态；类型未配置该分类时保持原状态
+	newStatusCode, err := statusTransition.GetCategoryStatusCode(parentTask.TaskTypeCode, newCategory)
+	if err != nil {
+		utils.Logger.Warnf("更新父任务 %d 状态跳过: %v", parentTaskID, err)
+		return nil
 	}
 
 	// 如果状态没有变化，不更新
@@ -1548,6 +1721,9 @@ func (s *TaskService) updateParentTaskStatus(parentTaskID uint, userID uint) err
 	if err := database.DB.Model(&parentTask).Update("status_code", newStatusCode).Error; err != nil {
 		return err
 	}
+	if err := onTaskStatusChanged(database.DB, &parentTask, oldStatusCode, newStatusCode); err != nil {
+		return err
+	}
 
 	// 记录状态变更日志
 	changeLog := &models.TaskChangeLog{
@@ -1662,8 +1838,8 @@ func (s *TaskService) ValidateTaskHierarchy(taskID uint) (bool, string, error) {
 	// 验证3：检查完成统计
 	var completedActual int64
 	database.DB.Model(&models.Task{}).
-		Where("parent_task_id = ? AND deleted_at IS NULL AND (status_code = ? OR status_code = ?)",
-			task.ID, "req_completed", "unit_completed").
+		Where("parent_task_id = ? AND deleted_at IS NULL", task.ID).
+		Where(statusCategorySubquery, []string{models.TaskStatusCategoryDone}).
 		Count(&completedActual)
 
 	if int(completedActual) != task.CompletedSubtasks {
@@ -1829,36 +2005,11 @@ func (s *TaskService) GetTaskContext(taskID uint) (*dto.TaskContext, error) {
 	return context, nil
 }
 
-// ========== 需求类任务状态转换校验 ==========
-
-// validateRequirementStatusTransition 校验需求类任务状态转换的前置条件
-// 规则：
-// 1. 待提交方案 -> 方案审核中：需要有思路方案记录且状态为通过(approved)
-// 2. 待提交执行计划 -> 执行计划审核中：需要有执行计划目标记录且状态为通过(approved)
-func (s *TaskService) validateRequirementStatusTransition(taskID uint, fromStatus, toStatus string) error {
-	// 校验：待提交方案 -> 方案审核中
-	if fromStatus == "req_pending_solution" && toStatus == "req_solution_review" {
-		// 检查是否有思路方案记录且状态为通过
-		var solution models.RequirementSolution
-		err := database.DB.Where("task_id = ? AND status = ?", taskID, "approved").
-			Order("version DESC").
-			First(&solution).Error
-		if err != nil {
-			return errors.New("状态转换失败：需要先有已通过的思路方案记录才能进入方案审核")
-		}
-	}
-
-	// 校验：待提交执行计划 -> 执行计划审核中
-	if fromStatus == "req_pending_plan" && toStatus == "req_plan_review" {
-		// 检查是否有执行计划记录且状态为通过
-		var plan models.ExecutionPlan
-		err := database.DB.Where("task_id = ? AND status = ?", taskID, "approved").
-			Order("version DESC").
-			First(&plan).Error
-		if err != nil {
-			return errors.New("状态转换失败：需要先有已通过的执行计划目标记录才能进入计划审核")
-		}
+// IsCreatorDelegate 判断用户当前是否为任务创建人的审核代理人
+func (s *TaskService) IsCreatorDelegate(taskID uint, userID uint) bool {
+	var task models.Task
+	if err := database.DB.First(&task, taskID).Error; err != nil {
+		return false
 	}
-
-	return nil
+	return isDelegateFor(database.DB, task.CreatorID, userID, &task, time.Now())
 }
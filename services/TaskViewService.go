@@ -0,0 +1,266 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TaskViewService struct{}
+
+var (
+	// ErrTaskViewNotFound 视图不存在
+	ErrTaskViewNotFound = errors.New("视图不存在")
+	// ErrTaskViewNotOwner 只有所有者可以修改或删除视图
+	ErrTaskViewNotOwner = errors.New("只有视图所有者可以修改或删除视图")
+	// ErrTaskViewForbidden 视图未共享给当前用户
+	ErrTaskViewForbidden = errors.New("无权访问该视图")
+	// ErrTaskViewShareDenied 共享范围超出当前用户所属或负责的部门
+	ErrTaskViewShareDenied = errors.New("只能共享给自己所属或负责的部门")
+)
+
+// CreateView 创建保存视图
+func (s *TaskViewService) CreateView(userID uint, req *dto.TaskViewRequest) (*dto.TaskViewResponse, error) {
+	view := models.TaskView{OwnerID: userID}
+	if err := s.applyViewRequest(&view, userID, req); err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Create(&view).Error; err != nil {
+		return nil, fmt.Errorf("创建视图失败: %v", err)
+	}
+
+	return s.GetView(view.ID, userID)
+}
+
+// UpdateView 更新保存视图（仅所有者可操作）
+func (s *TaskViewService) UpdateView(viewID uint, userID uint, req *dto.TaskViewRequest) (*dto.TaskViewResponse, error) {
+	var view models.TaskView
+	if err := database.DB.First(&view, viewID).Error; err != nil {
+		return nil, ErrTaskViewNotFound
+	}
+	if view.OwnerID != userID {
+		return nil, ErrTaskViewNotOwner
+	}
+
+	if err := s.applyViewRequest(&view, userID, req); err != nil {
+		return nil, err
+	}
+
+	// 使用 Select 保证共享部门可被清空
+	if err := database.DB.Model(&view).
+		Select("name", "description", "source", "filters", "sort_by", "sort_order", "columns", "shared_department_id").
+		Updates(&view).Error; err != nil {
+		return nil, fmt.Errorf("更新视图失败: %v", err)
+	}
+
+	return s.GetView(view.ID, userID)
+}
+
+// DeleteView 删除保存视图（仅所有者可操作）
+func (s *TaskViewService) DeleteView(viewID uint, userID uint) error {
+	var view models.TaskView
+	if err := database.DB.First(&view, viewID).Error; err != nil {
+		return ErrTaskViewNotFound
+	}
+	if view.OwnerID != userID {
+		return ErrTaskViewNotOwner
+	}
+	return database.DB.Delete(&view).Error
+}
+
+// GetViewList 获取当前用户可用的视图列表（自己的 + 共享到所属/负责部门的）
+func (s *TaskViewService) GetViewList(userID uint) ([]dto.TaskViewResponse, error) {
+	deptIDs := s.getUserDepartmentIDs(userID)
+
+	query := database.DB.Preload("Owner").Preload("SharedDepartment")
+	if len(deptIDs) > 0 {
+		query = query.Where("owner_id = ? OR shared_department_id IN ?", userID, deptIDs)
+	} else {
+		query = query.Where("owner_id = ?", userID)
+	}
+
+	var views []models.TaskView
+	if err := query.Order("updated_at DESC").Find(&views).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TaskViewResponse, len(views))
+	for i := range views {
+		responses[i] = s.toViewResponse(&views[i], userID)
+	}
+	return responses, nil
+}
+
+// GetView 获取视图详情（所有者或被共享部门的成员可查看）
+func (s *TaskViewService) GetView(viewID uint, userID uint) (*dto.TaskViewResponse, error) {
+	view, err := s.loadAccessibleView(viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.toViewResponse(view, userID)
+	return &resp, nil
+}
+
+// RunView 执行保存视图
+// 视图只保存筛选条件，执行时始终以当前用户身份调用任务列表查询，
+// 因此共享视图也会按每个查看者自己的可见范围过滤结果
+func (s *TaskViewService) RunView(viewID uint, userID uint, pagination *dto.PaginationRequest) (*dto.RunTaskViewResponse, error) {
+	view, err := s.loadAccessibleView(viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters dto.TaskViewFilters
+	if len(view.Filters) > 0 {
+		if err := json.Unmarshal(view.Filters, &filters); err != nil {
+			return nil, fmt.Errorf("视图筛选条件解析失败: %v", err)
+		}
+	}
+
+	queryReq := filters.ToQueryRequest()
+	queryReq.PaginationRequest = *pagination
+	queryReq.SortBy = view.SortBy
+	queryReq.SortOrder = view.SortOrder
+
+	taskService := &TaskService{}
+	var result *dto.PaginationResponse
+	if view.Source == models.TaskViewSourceMy {
+		result, err = taskService.GetMyTasks(queryReq, userID)
+	} else {
+		result, err = taskService.GetTaskList(queryReq, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	viewResp := s.toViewResponse(view, userID)
+	return &dto.RunTaskViewResponse{
+		View:    &viewResp,
+		Columns: viewResp.Columns,
+		Tasks:   result,
+	}, nil
+}
+
+// applyViewRequest 校验请求并写入视图模型
+func (s *TaskViewService) applyViewRequest(view *models.TaskView, userID uint, req *dto.TaskViewRequest) error {
+	source := req.Source
+	if source == "" {
+		source = models.TaskViewSourceAll
+	}
+
+	// 提前校验排序字段，避免保存无法执行的视图
+	if _, err := buildTaskOrder(&dto.TaskQueryRequest{SortBy: req.SortBy, SortOrder: req.SortOrder}, ""); err != nil {
+		return &TaskListParamError{Param: "sort_by", Message: err.Error()}
+	}
+
+	// 提前校验查询语言表达式
+	if req.Filters.Q != "" {
+		if _, _, err := CompileTaskQuery(req.Filters.Q, userID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// 只能共享给自己所属或负责的部门
+	if req.SharedDepartmentID != nil {
+		allowed := false
+		for _, id := range s.getUserDepartmentIDs(userID) {
+			if id == *req.SharedDepartmentID {
+				allowed = true
+				break
+			}
+		}
+		if !allowed && !(&CommonService{}).IsSuperAdmin(userID) {
+			return ErrTaskViewShareDenied
+		}
+	}
+
+	filtersJSON, err := json.Marshal(req.Filters)
+	if err != nil {
+		return fmt.Errorf("筛选条件序列化失败: %v", err)
+	}
+	columns := req.Columns
+	if columns == nil {
+		columns = []string{}
+	}
+	columnsJSON, err := json.Marshal(columns)
+	if err != nil {
+		return fmt.Errorf("展示列序列化失败: %v", err)
+	}
+
+	view.Name = req.Name
+	view.Description = req.Description
+	view.Source = source
+	view.Filters = filtersJSON
+	view.SortBy = req.SortBy
+	view.SortOrder = req.SortOrder
+	view.Columns = columnsJSON
+	view.SharedDepartmentID = req.SharedDepartmentID
+	return nil
+}
+
+// loadAccessibleView 加载视图并校验当前用户是否可访问
+func (s *TaskViewService) loadAccessibleView(viewID uint, userID uint) (*models.TaskView, error) {
+	var view models.TaskView
+	if err := database.DB.Preload("Owner").Preload("SharedDepartment").First(&view, viewID).Error; err != nil {
+		return nil, ErrTaskViewNotFound
+	}
+
+	if view.OwnerID == userID {
+		return &view, nil
+	}
+	if view.SharedDepartmentID != nil {
+		for _, id := range s.getUserDepartmentIDs(userID) {
+			if id == *view.SharedDepartmentID {
+				return &view, nil
+			}
+		}
+	}
+	return nil, ErrTaskViewForbidden
+}
+
+// getUserDepartmentIDs 获取用户所属及负责的部门ID
+func (s *TaskViewService) getUserDepartmentIDs(userID uint) []uint {
+	deptIDs := (&CommonService{}).GetUserManagedDepartmentIDs(userID)
+
+	var user models.User
+	if err := database.DB.Select("id", "department_id").First(&user, userID).Error; err == nil && user.DepartmentID != nil {
+		deptIDs = append(deptIDs, *user.DepartmentID)
+	}
+	return uniqueUintSlice(deptIDs)
+}
+
+// toViewResponse 转换为视图响应
+func (s *TaskViewService) toViewResponse(view *models.TaskView, userID uint) dto.TaskViewResponse {
+	resp := dto.TaskViewResponse{
+		ID:                 view.ID,
+		Name:               view.Name,
+		Description:        view.Description,
+		Source:             view.Source,
+		SortBy:             view.SortBy,
+		SortOrder:          view.SortOrder,
+		Columns:            []string{},
+		OwnerID:            view.OwnerID,
+		SharedDepartmentID: view.SharedDepartmentID,
+		IsOwner:            view.OwnerID == userID,
+		CreatedAt:          dto.ToResponseTime(view.CreatedAt),
+		UpdatedAt:          dto.ToResponseTime(view.UpdatedAt),
+	}
+	if len(view.Filters) > 0 {
+		_ = json.Unmarshal(view.Filters, &resp.Filters)
+	}
+	if len(view.Columns) > 0 {
+		_ = json.Unmarshal(view.Columns, &resp.Columns)
+	}
+	if view.Owner != nil {
+		resp.OwnerName = view.Owner.Username
+	}
+	if view.SharedDepartment != nil {
+		resp.SharedDepartmentName = view.SharedDepartment.Name
+	}
+	return resp
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TransitionContext 状态转换钩子的上下文
+type TransitionContext struct {
+	// 任务（源状态以 FromStatus 为准）
+	Task *models.Task
+	// 操作人用户ID
+	UserID uint
+	// 源状态编码
+	FromStatus string
+	// 目标状态编码
+	ToStatus string
+	// 源状态分类
+	FromCategory string
+	// 目标状态分类
+	ToCategory string
+	// 转换备注
+	Comment string
+	// 命中的转换规则
+	Transition *models.TaskStatusTransition
+}
+
+// TransitionGuard 转换守卫：返回错误时阻止转换
+type TransitionGuard struct {
+	// 守卫名称（配置在转换规则 guards 中）
+	Name string `json:"name"`
+	// 说明
+	Description string `json:"description"`
+	// 校验函数
+	Check func(ctx *TransitionContext) error `json:"-"`
+}
+
+// TransitionAction 后置动作：转换成功后执行
+type TransitionAction struct {
+	// 动作名称（配置在转换规则 post_actions 中）
+	Name string `json:"name"`
+	// 说明
+	Description string `json:"description"`
+	// 执行函数
+	Run func(ctx *TransitionContext) error `json:"-"`
+}
+
+// DefaultTransitionActions 默认后置动作：未显式配置 post_actions 的转换规则使用
+var DefaultTransitionActions = []string{"rollup_parent"}
+
+var (
+	transitionGuards  = make(map[string]*TransitionGuard)
+	transitionActions = make(map[string]*TransitionAction)
+)
+
+// RegisterTransitionGuard 注册转换守卫，同名守卫会被覆盖
+func RegisterTransitionGuard(name, description string, check func(ctx *TransitionContext) error) {
+	transitionGuards[name] = &TransitionGuard{Name: name, Description: description, Check: check}
+}
+
+// RegisterTransitionAction 注册后置动作，同名动作会被覆盖
+func RegisterTransitionAction(name, description string, run func(ctx *TransitionContext) error) {
+	transitionActions[name] = &TransitionAction{Name: name, Description: description, Run: run}
+}
+
+// ListTransitionGuards 列出已注册的守卫（按名称排序）
+func ListTransitionGuards() []*TransitionGuard {
+	result := make([]*TransitionGuard, 0, len(transitionGuards))
+	for _, guard := range transitionGuards {
+		result = append(result, guard)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// ListTransitionActions 列出已注册的后置动作（按名称排序）
+func ListTransitionActions() []*TransitionAction {
+	result := make([]*TransitionAction, 0, len(transitionActions))
+	for _, action := range transitionActions {
+		result = append(result, action)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// ValidateTransitionHookNames 校验守卫和动作名称均已注册
+func ValidateTransitionHookNames(guards, actions []string) error {
+	for _, name := range guards {
+		if _, ok := transitionGuards[name]; !ok {
+			return fmt.Errorf("未知的转换守卫: %s", name)
+		}
+	}
+	for _, name := range actions {
+		if _, ok := transitionActions[name]; !ok {
+			return fmt.Errorf("未知的后置动作: %s", name)
+		}
+	}
+	return nil
+}
+
+// runTransitionGuards 依次执行转换规则配置的守卫，任一失败即返回
+func runTransitionGuards(ctx *TransitionContext) error {
+	if ctx.Transition == nil {
+		return nil
+	}
+	for _, name := range ctx.Transition.Guards {
+		guard, ok := transitionGuards[name]
+		if !ok {
+			return fmt.Errorf("转换规则配置了未知的守卫: %s", name)
+		}
+		if err := guard.Check(ctx); err != nil {
+			return fmt.Errorf("状态转换失败：%v", err)
+		}
+	}
+	return nil
+}
+
+// runTransitionActions 依次执行转换规则配置的后置动作（未配置时使用默认动作）
+func runTransitionActions(ctx *TransitionContext) error {
+	names := DefaultTransitionActions
+	if ctx.Transition != nil && ctx.Transition.PostActions != nil {
+		names = ctx.Transition.PostActions
+	}
+	for _, name := range names {
+		action, ok := transitionActions[name]
+		if !ok {
+			return fmt.Errorf("转换规则配置了未知的后置动作: %s", name)
+		}
+		if err := action.Run(ctx); err != nil {
+			return fmt.Errorf("执行后置动作 %s 失败: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// ========== 内置守卫 ==========
+
+func init() {
+	RegisterTransitionGuard("has_approved_solution", "任务存在已通过的思路方案", guardHasApprovedSolution)
+	RegisterTransitionGuard("has_approved_plan", "任务存在已通过的执行计划", guardHasApprovedPlan)
+	RegisterTransitionGuard("all_subtasks_done", "所有子任务均已完成或已取消", guardAllSubtasksDone)
+	RegisterTransitionGuard("no_open_blockers", "任务没有未解决的受阻记录", guardNoOpenBlockers)
+	RegisterTransitionGuard("has_executor", "任务已指派执行人", guardHasExecutor)
+
+	RegisterTransitionAction("rollup_parent", "子任务完成/受阻状态变化时更新父任务统计和状态", actionRollupParent)
+	RegisterTransitionAction("notify", "通知任务创建人和执行人（不含操作人）", actionNotify)
+	RegisterTransitionAction("create_review", "为本次转换创建由创建人审核的审核会话", actionCreateReview)
+}
+
+func guardHasApprovedSolution(ctx *TransitionContext) error {
+	var count int64
+	database.DB.Model(&models.RequirementSolution{}).
+		Where("task_id = ? AND status = ?", ctx.Task.ID, "approved").
+		Count(&count)
+	if count == 0 {
+		return errors.New("需要先有已通过的思路方案记录")
+	}
+	return nil
+}
+
+func guardHasApprovedPlan(ctx *TransitionContext) error {
+	var count int64
+	database.DB.Model(&models.ExecutionPlan{}).
+		Where("task_id = ? AND status = ?", ctx.Task.ID, "approved").
+		Count(&count)
+	if count == 0 {
+		return errors.New("需要先有已通过的执行计划记录")
+	}
+	return nil
+}
+
+func guardAllSubtasksDone(ctx *TransitionContext) error {
+	var count int64
+	database.DB.Model(&models.Task{}).
+		Where("parent_task_id = ?", ctx.Task.ID).
+		Where("status_code NOT IN (SELECT code FROM task_statuses WHERE category IN ?)",
+			[]string{models.TaskStatusCategoryDone, models.TaskStatusCategoryCancelled}).
+		Count(&count)
+	if count > 0 {
+		return fmt.Errorf("还有 %d 个子任务未完成", count)
+	}
+	return nil
+}
+
+func guardNoOpenBlockers(ctx *TransitionContext) error {
+	var count int64
+	database.DB.Model(&models.BlockedTask{}).
+		Where("task_id = ? AND status <> ?", ctx.Task.ID, "resolved").
+		Count(&count)
+	if count > 0 {
+		return fmt.Errorf("任务还有 %d 个未解决的受阻记录", count)
+	}
+	return nil
+}
+
+func guardHasExecutor(ctx *TransitionContext) error {
+	if ctx.Task.ExecutorID == nil {
+		return errors.New("任务尚未指派执行人")
+	}
+	return nil
+}
+
+// ========== 内置后置动作 ==========
+
+func actionRollupParent(ctx *TransitionContext) error {
+	if ctx.Task.ParentTaskID == nil {
+		return nil
+	}
+
+	// 仅当完成或受阻状态发生变化时才需要更新父任务
+	completedChanged := (ctx.FromCategory == models.TaskStatusCategoryDone) != (ctx.ToCategory == models.TaskStatusCategoryDone)
+	blockedChanged := (ctx.FromCategory == models.TaskStatusCategoryBlocked) != (ctx.ToCategory == models.TaskStatusCategoryBlocked)
+	if !completedChanged && !blockedChanged {
+		return nil
+	}
+
+	taskService := &TaskService{}
+	if err := taskService.recalculateTaskStats(*ctx.Task.ParentTaskID); err != nil {
+		return fmt.Errorf("更新父任务统计失败: %v", err)
+	}
+	if err := taskService.updateParentTaskStatus(*ctx.Task.ParentTaskID, ctx.UserID); err != nil {
+		return fmt.Errorf("更新父任务状态失败: %v", err)
+	}
+	return nil
+}
+
+func actionNotify(ctx *TransitionContext) error {
+	recipients := []uint{ctx.Task.CreatorID}
+	if ctx.Task.ExecutorID != nil {
+		recipients = append(recipients, *ctx.Task.ExecutorID)
+	}
+	filtered := make([]uint, 0, len(recipients))
+	for _, id := range recipients {
+		if id != ctx.UserID {
+			filtered = append(filtered, id)
+		}
+	}
+
+	taskID := ctx.Task.ID
+	content := fmt.Sprintf("任务「%s」状态由 %s 变更为 %s", ctx.Task.Title, ctx.FromStatus, ctx.ToStatus)
+	if ctx.Comment != "" {
+		content += "，备注：" + ctx.Comment
+	}
+	(&NotificationService{}).Notify(filtered, &taskID, NotificationTypeStatusChange, "任务状态变更", content)
+	return nil
+}
+
+func actionCreateReview(ctx *TransitionContext) error {
+	now := time.Now()
+	session := &models.ReviewSession{
+		TaskID:            ctx.Task.ID,
+		ReviewType:        "status_review",
+		TargetType:        "tasks",
+		TargetID:          ctx.Task.ID,
+		InitiatedBy:       ctx.UserID,
+		InitiatedAt:       now,
+		Status:            "in_review",
+		ReviewMode:        "single",
+		RequiredApprovals: 1,
+	}
+	if err := database.DB.Create(session).Error; err != nil {
+		return err
+	}
+
+	taskID := ctx.Task.ID
+	content := fmt.Sprintf("任务「%s」已变更为 %s，请审核", ctx.Task.Title, ctx.ToStatus)
+	recipients := withActiveDelegates(database.DB, ctx.Task, []uint{ctx.Task.CreatorID}, now)
+	(&NotificationService{}).Notify(recipients, &taskID, NotificationTypeReviewRequest, "状态变更审核", content)
+	return nil
+}
@@ -0,0 +1,513 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// TransitionReviewType 状态转换审批使用的审核类型
+const TransitionReviewType = "transition_review"
+
+type TransitionRequestService struct{}
+
+// IsApprover 判断用户是否可以审批任务的状态转换（任务创建人、所属部门负责人或其代理人）
+func (s *TransitionRequestService) IsApprover(task *models.Task, userID uint) bool {
+	_, ok := s.resolveApprover(task, userID)
+	return ok
+}
+
+// resolveApprover 确定用户以哪位审批人的身份审批：本人是审批人时为本人，否则为委托其代理的审批人
+func (s *TransitionRequestService) resolveApprover(task *models.Task, userID uint) (uint, bool) {
+	return resolveReviewPrincipal(database.DB, taskCreatorAndLeaderIDs(task), userID, task, time.Now())
+}
+
+// taskCreatorAndLeaderIDs 获取任务创建人和所属部门负责人（状态转换审批人、超期升级对象）
+func taskCreatorAndLeaderIDs(task *models.Task) []uint {
+	ids := []uint{task.CreatorID}
+	if task.DepartmentID != nil {
+		var leaderIDs []uint
+		database.DB.Model(&models.DepartmentLeader{}).
+			Where("department_id = ?", *task.DepartmentID).
+			Pluck("user_id", &leaderIDs)
+		ids = append(ids, leaderIDs...)
+	}
+	return uniqueUintSlice(ids)
+}
+
+// CreateRequest 创建状态转换申请及对应的审核会话，并通知审批人
+func (s *TransitionRequestService) CreateRequest(task *models.Task, rule *models.TaskStatusTransition, userID uint, comment string) (*dto.TransitionRequestResponse, error) {
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 同一任务同时只允许一个待审批的申请：锁定任务行，避免并发提交重复创建申请
+	var locked models.Task
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, task.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.New("任务不存在")
+	}
+	var pendingCount int64
+	tx.Model(&models.TaskTransitionRequest{}).
+		Where("task_id = ? AND status = ?", task.ID, models.TransitionRequestStatusPending).
+		Count(&pendingCount)
+	if pendingCount > 0 {
+		tx.Rollback()
+		return nil, errors.New("该任务已有待审批的状态转换申请")
+	}
+
+	request := &models.TaskTransitionRequest{
+		TaskID:         task.ID,
+		TransitionID:   rule.ID,
+		FromStatusCode: task.StatusCode,
+		ToStatusCode:   rule.ToStatusCode,
+		RequestedBy:    userID,
+		Comment:        comment,
+		Status:         models.TransitionRequestStatusPending,
+	}
+	if err := tx.Create(request).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("创建状态转换申请失败: %v", err)
+	}
+
+	session := &models.ReviewSession{
+		TaskID:            task.ID,
+		ReviewType:        TransitionReviewType,
+		TargetType:        request.TableName(),
+		TargetID:          request.ID,
+		InitiatedBy:       userID,
+		InitiatedAt:       time.Now(),
+		Status:            "in_review",
+		ReviewMode:        "single",
+		RequiredApprovals: 1,
+	}
+	if err := tx.Create(session).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("创建审核会话失败: %v", err)
+	}
+
+	if err := tx.Model(request).Update("review_session_id", session.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	request.ReviewSessionID = &session.ID
+
+	// 记录申请日志（任务状态此时未变更）
+	changeLog := &models.TaskChangeLog{
+		TaskID:     task.ID,
+		UserID:     userID,
+		ChangeType: "transition_requested",
+		FieldName:  "status_code",
+		OldValue:   task.StatusCode,
+		NewValue:   rule.ToStatusCode,
+		Comment:    comment,
+	}
+	if err := tx.Create(changeLog).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("记录申请日志失败: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	approvers := make([]uint, 0)
+	for _, id := range withActiveDelegates(database.DB, task, taskCreatorAndLeaderIDs(task), time.Now()) {
+		if id != userID {
+			approvers = append(approvers, id)
+		}
+	}
+	taskID := task.ID
+	content := fmt.Sprintf("任务「%s」申请由 %s 变更为 %s，请审批", task.Title, task.StatusCode, rule.ToStatusCode)
+	(&NotificationService{}).Notify(approvers, &taskID, NotificationTypeReviewRequest, "状态变更审批", content)
+
+	responses := s.toResponses([]models.TaskTransitionRequest{*request})
+	return &responses[0], nil
+}
+
+// GetPendingForApprover 获取待当前用户审批（含作为代理人代审批）的状态转换申请
+func (s *TransitionRequestService) GetPendingForApprover(userID uint) ([]dto.TransitionRequestResponse, error) {
+	approverIDs := []uint{userID}
+	for _, rule := range activeDelegations(database.DB, "delegate_id", []uint{userID}, time.Now()) {
+		approverIDs = append(approverIDs, rule.DelegatorID)
+	}
+	approverIDs = uniqueUintSlice(approverIDs)
+
+	var candidates []models.TaskTransitionRequest
+	err := database.DB.Model(&models.TaskTransitionRequest{}).
+		Joins("JOIN tasks ON tasks.id = task_transition_requests.task_id AND tasks.deleted_at IS NULL").
+		Where("task_transition_requests.status = ?", models.TransitionRequestStatusPending).
+		Where("task_transition_requests.requested_by <> ?", userID).
+		Where("tasks.creator_id IN ? OR tasks.department_id IN (SELECT department_id FROM department_leaders WHERE user_id IN ? AND deleted_at IS NULL)",
+			approverIDs, approverIDs).
+		Order("task_transition_requests.created_at ASC").
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(approverIDs) == 1 {
+		return s.toResponses(candidates), nil
+	}
+
+	// 代理的申请需按委托的部门/任务类型范围再次过滤
+	taskIDs := make([]uint, 0, len(candidates))
+	for _, request := range candidates {
+		taskIDs = append(taskIDs, request.TaskID)
+	}
+	var tasks []models.Task
+	database.DB.Where("id IN ?", uniqueUintSlice(taskIDs)).Find(&tasks)
+	taskMap := make(map[uint]*models.Task, len(tasks))
+	for i := range tasks {
+		taskMap[tasks[i].ID] = &tasks[i]
+	}
+	requests := make([]models.TaskTransitionRequest, 0, len(candidates))
+	for _, request := range candidates {
+		task := taskMap[request.TaskID]
+		if task == nil {
+			continue
+		}
+		if principalID, ok := s.resolveApprover(task, userID); ok && principalID != request.RequestedBy {
+			requests = append(requests, request)
+		}
+	}
+	return s.toResponses(requests), nil
+}
+
+// GetMyRequests 获取当前用户提交的状态转换申请
+func (s *TransitionRequestService) GetMyRequests(userID uint, query *dto.TransitionRequestQuery) ([]dto.TransitionRequestResponse, error) {
+	db := database.DB.Model(&models.TaskTransitionRequest{}).Where("requested_by = ?", userID)
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.TaskID != nil {
+		db = db.Where("task_id = ?", *query.TaskID)
+	}
+
+	var requests []models.TaskTransitionRequest
+	if err := db.Order("created_at DESC").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return s.toResponses(requests), nil
+}
+
+// DecideRequest 审批状态转换申请，通过时变更任务状态并执行后置动作
+func (s *TransitionRequestService) DecideRequest(requestID uint, userID uint, approved bool, comment string) error {
+	var request models.TaskTransitionRequest
+	if err := database.DB.First(&request, requestID).Error; err != nil {
+		return errors.New("状态转换申请不存在")
+	}
+	if request.Status != models.TransitionRequestStatusPending {
+		return errors.New("该申请已处理")
+	}
+
+	var task models.Task
+	if err := database.DB.First(&task, request.TaskID).Error; err != nil {
+		return errors.New("任务不存在")
+	}
+	if request.RequestedBy == userID {
+		return errors.New("不能审批自己提交的申请")
+	}
+	approverID, ok := s.resolveApprover(&task, userID)
+	if !ok {
+		return errors.New("只有任务创建人或所属部门负责人可以审批")
+	}
+	if request.RequestedBy == approverID {
+		return errors.New("不能代委托人审批其本人提交的申请")
+	}
+	var delegateID *uint
+	if approverID != userID {
+		delegateID = &userID
+	}
+
+	// 审批通过前重新校验：任务状态未变化、转换规则仍然有效、守卫仍然通过
+	var hookCtx *TransitionContext
+	if approved {
+		if task.StatusCode != request.FromStatusCode {
+			s.closeRequest(&request, models.TransitionRequestStatusCancelled, userID, "任务状态已变化，申请自动失效")
+			return errors.New("任务状态已变化，申请已失效")
+		}
+
+		var rule models.TaskStatusTransition
+		if err := database.DB.Where("id = ? AND is_allowed = ?", request.TransitionID, true).
+			First(&rule).Error; err != nil {
+			s.closeRequest(&request, models.TransitionRequestStatusCancelled, userID, "转换规则已变更，申请自动失效")
+			return errors.New("转换规则已变更，申请已失效")
+		}
+
+		statusTransition := &StatusTransitionService{}
+		hookCtx = &TransitionContext{
+			Task:         &task,
+			UserID:       request.RequestedBy,
+			FromStatus:   request.FromStatusCode,
+			ToStatus:     request.ToStatusCode,
+			FromCategory: statusTransition.GetStatusCategory(request.FromStatusCode),
+			ToCategory:   statusTransition.GetStatusCategory(request.ToStatusCode),
+			Comment:      request.Comment,
+			Transition:   &rule,
+		}
+		if err := runTransitionGuards(hookCtx); err != nil {
+			return err
+		}
+	}
+
+	status := models.TransitionRequestStatusApproved
+	opinion := "approve"
+	if !approved {
+		status = models.TransitionRequestStatusRejected
+		opinion = "reject"
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 锁定申请后重新检查状态，防止并发审批重复生效
+	var locked models.TaskTransitionRequest
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, request.ID).Error; err != nil {
+		tx.Rollback()
+		return errors.New("状态转换申请不存在")
+	}
+	if locked.Status != models.TransitionRequestStatusPending {
+		tx.Rollback()
+		return errors.New("该申请已处理")
+	}
+	if approved {
+		var current models.Task
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status_code").
+			First(&current, task.ID).Error; err != nil {
+			tx.Rollback()
+			return errors.New("任务不存在")
+		}
+		if current.StatusCode != request.FromStatusCode {
+			tx.Rollback()
+			return errors.New("任务状态已变化，申请已失效")
+		}
+	}
+
+	now := time.Now()
+	if err := tx.Model(&request).Updates(map[string]interface{}{
+		"status":              status,
+		"decided_by":          approverID,
+		"decided_delegate_id": delegateID,
+		"decided_at":          now,
+		"decision_comment":    comment,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if request.ReviewSessionID != nil {
+		if err := tx.Model(&models.ReviewSession{}).Where("id = ?", *request.ReviewSessionID).
+			Updates(map[string]interface{}{
+				"status":                     "completed",
+				"final_decision":             status,
+				"final_decision_by":          approverID,
+				"final_decision_delegate_id": delegateID,
+				"final_decision_at":          now,
+				"final_decision_comment":     comment,
+				"completed_at":               now,
+			}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		reviewerRole := "leader"
+		if task.CreatorID == approverID {
+			reviewerRole = "creator"
+		}
+		record := &models.ReviewRecord{
+			ReviewSessionID: *request.ReviewSessionID,
+			ReviewerID:      approverID,
+			DelegateID:      delegateID,
+			ReviewerRole:    reviewerRole,
+			Opinion:         opinion,
+			Comment:         comment,
+			VoteWeight:      1.0,
+			ReviewedAt:      now,
+		}
+		if err := tx.Create(record).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("记录审批意见失败: %v", err)
+		}
+	}
+
+	if delegateID != nil {
+		if err := logDelegatedAction(tx, task.ID, userID, approverID, "审批状态转换申请"); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if approved {
+		if err := (&TaskService{}).applyTransition(tx, hookCtx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	} else {
+		changeLog := &models.TaskChangeLog{
+			TaskID:     task.ID,
+			UserID:     userID,
+			ChangeType: "transition_rejected",
+			FieldName:  "status_code",
+			OldValue:   request.FromStatusCode,
+			NewValue:   request.ToStatusCode,
+			Comment:    comment,
+		}
+		if err := tx.Create(changeLog).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("记录审批日志失败: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	taskID := task.ID
+	result := "已通过"
+	if !approved {
+		result = "被驳回"
+	}
+	content := fmt.Sprintf("任务「%s」由 %s 变更为 %s 的申请%s", task.Title, request.FromStatusCode, request.ToStatusCode, result)
+	if comment != "" {
+		content += "，备注：" + comment
+	}
+	(&NotificationService{}).Notify([]uint{request.RequestedBy}, &taskID, NotificationTypeStatusChange, "状态变更审批结果", content)
+
+	if !approved {
+		return nil
+	}
+	// 执行转换规则配置的后置动作（状态已变更，失败不回滚审批结果）
+	return runTransitionActions(hookCtx)
+}
+
+// CancelRequest 申请人撤回待审批的状态转换申请
+func (s *TransitionRequestService) CancelRequest(requestID uint, userID uint) error {
+	var request models.TaskTransitionRequest
+	if err := database.DB.First(&request, requestID).Error; err != nil {
+		return errors.New("状态转换申请不存在")
+	}
+	if request.RequestedBy != userID {
+		return errors.New("只能撤回自己提交的申请")
+	}
+	if request.Status != models.TransitionRequestStatusPending {
+		return errors.New("该申请已处理")
+	}
+	return s.closeRequest(&request, models.TransitionRequestStatusCancelled, userID, "申请人撤回")
+}
+
+// closeRequest 关闭申请并取消关联的审核会话（不变更任务状态）
+func (s *TransitionRequestService) closeRequest(request *models.TaskTransitionRequest, status string, userID uint, comment string) error {
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	if err := tx.Model(request).Updates(map[string]interface{}{
+		"status":           status,
+		"decided_by":       userID,
+		"decided_at":       now,
+		"decision_comment": comment,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if request.ReviewSessionID != nil {
+		if err := tx.Model(&models.ReviewSession{}).Where("id = ?", *request.ReviewSessionID).
+			Updates(map[string]interface{}{
+				"status":                 "cancelled",
+				"final_decision_comment": comment,
+				"completed_at":           now,
+			}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// toResponses 批量转换申请响应（批量加载任务、状态名称和用户名）
+func (s *TransitionRequestService) toResponses(requests []models.TaskTransitionRequest) []dto.TransitionRequestResponse {
+	responses := make([]dto.TransitionRequestResponse, 0, len(requests))
+	if len(requests) == 0 {
+		return responses
+	}
+
+	taskIDs := make([]uint, 0, len(requests))
+	userIDs := make([]uint, 0, len(requests)*2)
+	statusCodes := make([]string, 0, len(requests)*2)
+	for _, request := range requests {
+		taskIDs = append(taskIDs, request.TaskID)
+		userIDs = append(userIDs, request.RequestedBy)
+		if request.DecidedBy != nil {
+			userIDs = append(userIDs, *request.DecidedBy)
+		}
+		if request.DecidedDelegateID != nil {
+			userIDs = append(userIDs, *request.DecidedDelegateID)
+		}
+		statusCodes = append(statusCodes, request.FromStatusCode, request.ToStatusCode)
+	}
+
+	tasks := make(map[uint]models.Task)
+	var taskList []models.Task
+	database.DB.Select("id, task_no, title").Where("id IN ?", uniqueUintSlice(taskIDs)).Find(&taskList)
+	for _, task := range taskList {
+		tasks[task.ID] = task
+	}
+
+	statusNames := make(map[string]string)
+	var statuses []models.TaskStatus
+	database.DB.Select("code, name").Where("code IN ?", statusCodes).Find(&statuses)
+	for _, status := range statuses {
+		statusNames[status.Code] = status.Name
+	}
+
+	usernames := loadUsernames(userIDs)
+
+	for _, request := range requests {
+		resp := dto.TransitionRequestResponse{
+			ID:              request.ID,
+			TaskID:          request.TaskID,
+			TaskNo:          tasks[request.TaskID].TaskNo,
+			TaskTitle:       tasks[request.TaskID].Title,
+			FromStatusCode:  request.FromStatusCode,
+			FromStatusName:  statusNames[request.FromStatusCode],
+			ToStatusCode:    request.ToStatusCode,
+			ToStatusName:    statusNames[request.ToStatusCode],
+			RequestedBy:     request.RequestedBy,
+			RequesterName:   usernames[request.RequestedBy],
+			Comment:         request.Comment,
+			Status:          request.Status,
+			ReviewSessionID: request.ReviewSessionID,
+			DecidedBy:       request.DecidedBy,
+			DecidedAt:       dto.PtrToResponseTime(request.DecidedAt),
+			DecisionComment: request.DecisionComment,
+			CreatedAt:       dto.ToResponseTime(request.CreatedAt),
+		}
+		if request.DecidedBy != nil {
+			resp.DeciderName = usernames[*request.DecidedBy]
+		}
+		if request.DecidedDelegateID != nil {
+			resp.DecidedDelegateID = request.DecidedDelegateID
+			resp.DecidedDelegateName = usernames[*request.DecidedDelegateID]
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
@@ -0,0 +1,664 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"RHPRo-Task/ratelimit"
+	"RHPRo-Task/utils"
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+)
+
+// 两步验证凭证用途
+const (
+	twoFactorPurposeVerify = "verify" // 已启用两步验证，输入动态验证码完成登录
+	twoFactorPurposeSetup  = "setup"  // 必须启用两步验证但尚未绑定，绑定后完成登录
+)
+
+const (
+	// 两步验证凭证有效期
+	twoFactorTokenTTL = 5 * time.Minute
+	// 两步验证凭证签名密钥的派生用途
+	twoFactorTokenKeyPurpose = "2fa-token"
+	// 动态验证码的时间步长
+	totpPeriod = 30
+	// 计数窗口内允许的验证码错误次数
+	twoFactorMaxAttempts = 5
+	// 验证码错误计数窗口
+	twoFactorAttemptWindow = 5 * time.Minute
+	// 每次生成的恢复码数量
+	recoveryCodeCount = 10
+)
+
+// 恢复码字符集（去掉易混淆的 0/o/1/i/l）
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+var (
+	errTwoFactorTokenInvalid = errors.New("两步验证凭证无效或已过期，请重新登录")
+	errTwoFactorCodeInvalid  = errors.New("验证码错误")
+	errTwoFactorTooMany      = errors.New("验证码错误次数过多，请稍后再试")
+)
+
+// TwoFactorTokenClaims 两步验证凭证的 claims（密码验证通过后签发，只能用于完成本次登录）
+// 使用派生密钥签名，不能被当作访问令牌使用
+type TwoFactorTokenClaims struct {
+	UID         uint   `json:"uid"`
+	Purpose     string `json:"purpose"`      // verify/setup
+	LoginMethod string `json:"login_method"` // 完成登录后登记会话的登录方式
+	Device      string `json:"device"`       // 客户端上报的设备名称
+	jwt.RegisteredClaims
+}
+
+type TwoFactorService struct{}
+
+// twoFactorRequired 判断用户是否必须启用两步验证（需预加载 Roles.Permissions）
+func twoFactorRequired(user *models.User) bool {
+	permission := config.GetConfig().Security.TwoFactorRequiredPermission
+	return permission != "" && userHasPermission(user, permission)
+}
+
+// loadTwoFactor 查询用户的两步验证配置（未发起绑定时返回 nil）
+func loadTwoFactor(userID uint) (*models.UserTwoFactor, error) {
+	var twoFactor models.UserTwoFactor
+	if err := database.DB.Where("user_id = ?", userID).First(&twoFactor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// completeLogin 第一步认证通过后完成登录：
+// 已启用两步验证或必须启用两步验证时只返回两步验证凭证，否则登记会话并签发令牌
+func completeLogin(user *models.User, response *dto.LoginResponse, client LoginClient, method string) error {
+	twoFactor, err := loadTwoFactor(user.ID)
+	if err != nil {
+		return err
+	}
+	enabled := twoFactor != nil && twoFactor.EnabledAt != nil
+	if !enabled && !twoFactorRequired(user) {
+		return issueLoginTokens(user, response, client, method)
+	}
+
+	purpose := twoFactorPurposeSetup
+	if enabled {
+		purpose = twoFactorPurposeVerify
+	}
+	token, err := generateTwoFactorToken(user.ID, purpose, method, client.Device)
+	if err != nil {
+		return err
+	}
+	response.UserInfo = nil
+	response.TwoFactorRequired = enabled
+	response.TwoFactorSetupRequired = !enabled
+	response.TwoFactorToken = token
+	return nil
+}
+
+// generateTwoFactorToken 签发两步验证凭证
+func generateTwoFactorToken(userID uint, purpose, method, device string) (string, error) {
+	now := time.Now()
+	claims := TwoFactorTokenClaims{
+		UID:         userID,
+		Purpose:     purpose,
+		LoginMethod: method,
+		Device:      device,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(twoFactorTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "RHPRo-Task-2FA",
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(utils.DeriveSigningKey(twoFactorTokenKeyPurpose))
+}
+
+// parseTwoFactorToken 解析两步验证凭证并校验用途，已完成登录的凭证不能再次使用
+func parseTwoFactorToken(tokenString, purpose string) (*TwoFactorTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &TwoFactorTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return utils.DeriveSigningKey(twoFactorTokenKeyPurpose), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer("RHPRo-Task-2FA"))
+	if err != nil {
+		return nil, errTwoFactorTokenInvalid
+	}
+	claims, ok := token.Claims.(*TwoFactorTokenClaims)
+	if !ok || !token.Valid || claims.Purpose != purpose || claims.UID == 0 || claims.ID == "" {
+		return nil, errTwoFactorTokenInvalid
+	}
+	if ttl, err := ratelimit.GetStore().TTL(twoFactorTokenUsedKey(claims.ID)); err == nil && ttl > 0 {
+		return nil, errTwoFactorTokenInvalid
+	}
+	return claims, nil
+}
+
+// markTwoFactorTokenUsed 登录完成后作废两步验证凭证
+func markTwoFactorTokenUsed(claims *TwoFactorTokenClaims) {
+	if err := ratelimit.GetStore().Set(twoFactorTokenUsedKey(claims.ID), twoFactorTokenTTL); err != nil {
+		utils.Logger.Warnf("作废两步验证凭证失败: %v", err)
+	}
+}
+
+// 两步验证凭证使用标记、验证码错误计数和锁定标记的键
+func twoFactorTokenUsedKey(tokenID string) string { return "two_factor_used:" + tokenID }
+func twoFactorFailKey(userID uint) string         { return fmt.Sprintf("two_factor_fail:%d", userID) }
+func twoFactorLockKey(userID uint) string         { return fmt.Sprintf("two_factor_lock:%d", userID) }
+
+// checkTwoFactorAttempts 验证码错误次数过多时暂停验证（计数存储不可用时放行）
+func checkTwoFactorAttempts(userID uint) error {
+	if ttl, err := ratelimit.GetStore().TTL(twoFactorLockKey(userID)); err == nil && ttl > 0 {
+		return errTwoFactorTooMany
+	}
+	return nil
+}
+
+// recordTwoFactorFailure 记录一次验证码错误，达到上限后在计数窗口内暂停验证
+func recordTwoFactorFailure(userID uint) {
+	store := ratelimit.GetStore()
+	failures, _, err := store.Incr(twoFactorFailKey(userID), twoFactorAttemptWindow)
+	if err != nil {
+		utils.Logger.Warnf("记录两步验证错误次数失败: %v", err)
+		return
+	}
+	if failures >= twoFactorMaxAttempts {
+		if err := store.Set(twoFactorLockKey(userID), twoFactorAttemptWindow); err != nil {
+			utils.Logger.Warnf("设置两步验证锁定失败: %v", err)
+		}
+		store.Delete(twoFactorFailKey(userID))
+	}
+}
+
+// resetTwoFactorAttempts 验证通过后清除错误计数
+func resetTwoFactorAttempts(userID uint) {
+	if err := ratelimit.GetStore().Delete(twoFactorFailKey(userID)); err != nil {
+		utils.Logger.Warnf("清除两步验证错误次数失败: %v", err)
+	}
+}
+
+// matchTOTP 校验动态验证码，允许前后各一个时间步的时钟偏差
+// 返回匹配的时间步；不大于 lastStep 的时间步视为已使用
+func matchTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	for _, offset := range []int64{0, -1, 1} {
+		at := now.Add(time.Duration(offset*totpPeriod) * time.Second)
+		step := at.Unix() / totpPeriod
+		if step <= lastStep {
+			continue
+		}
+		expected, err := totp.GenerateCodeCustom(secret, at, opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// verifyTwoFactorCode 校验动态验证码并记录已使用的时间步（同一验证码不能重复使用）
+func verifyTwoFactorCode(twoFactor *models.UserTwoFactor, code string) error {
+	if err := checkTwoFactorAttempts(twoFactor.UserID); err != nil {
+		return err
+	}
+	step, ok := matchTOTP(twoFactor.Secret, code, time.Now(), twoFactor.LastUsedStep)
+	if ok {
+		// 条件更新，并发提交同一验证码时只有一个成功
+		result := database.DB.Model(&models.UserTwoFactor{}).
+			Where("id = ? AND last_used_step < ?", twoFactor.ID, step).
+			Update("last_used_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		ok = result.RowsAffected > 0
+	}
+	if !ok {
+		recordTwoFactorFailure(twoFactor.UserID)
+		return errTwoFactorCodeInvalid
+	}
+	twoFactor.LastUsedStep = step
+	resetTwoFactorAttempts(twoFactor.UserID)
+	return nil
+}
+
+// normalizeRecoveryCode 统一恢复码格式（忽略大小写、空格和连字符）
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// newRecoveryCode 生成随机恢复码（格式 xxxxx-xxxxx）
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	chars := make([]byte, len(buf))
+	for i, b := range buf {
+		chars[i] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+	}
+	return string(chars[:5]) + "-" + string(chars[5:]), nil
+}
+
+// replaceRecoveryCodes 生成新的恢复码并作废旧的恢复码，返回明文（数据库只保存哈希）
+func replaceRecoveryCodes(db *gorm.DB, userID uint) ([]string, error) {
+	if err := db.Unscoped().Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.UserRecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, models.UserRecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRefreshToken(normalizeRecoveryCode(code)),
+		})
+	}
+	if err := db.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// useRecoveryCode 使用恢复码（每个恢复码只能使用一次）
+func useRecoveryCode(userID uint, code string) error {
+	if err := checkTwoFactorAttempts(userID); err != nil {
+		return err
+	}
+	result := database.DB.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRefreshToken(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		recordTwoFactorFailure(userID)
+		return errors.New("恢复码错误或已使用")
+	}
+	resetTwoFactorAttempts(userID)
+	return nil
+}
+
+// enrollTwoFactor 发起绑定：生成新的密钥（尚未启用），重复发起时覆盖未验证的密钥
+func enrollTwoFactor(user *models.User) (*dto.TwoFactorEnrollResponse, error) {
+	existing, err := loadTwoFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.EnabledAt != nil {
+		return nil, errors.New("已启用两步验证")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      config.GetConfig().Security.TwoFactorIssuer,
+		AccountName: user.Mobile,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if err := database.DB.Model(existing).Updates(map[string]interface{}{
+			"secret":         key.Secret(),
+			"last_used_step": 0,
+		}).Error; err != nil {
+			return nil, err
+		}
+	} else if err := database.DB.Create(&models.UserTwoFactor{UserID: user.ID, Secret: key.Secret()}).Error; err != nil {
+		return nil, err
+	}
+
+	qrCode, err := encodeQRCode(key)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TwoFactorEnrollResponse{
+		Secret:          key.Secret(),
+		ProvisioningURI: key.URL(),
+		QRCode:          qrCode,
+	}, nil
+}
+
+// encodeQRCode 生成配置链接的二维码（PNG data URL）
+func encodeQRCode(key *otp.Key) (string, error) {
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// enableTwoFactor 验证首个动态验证码后启用两步验证，返回恢复码
+func enableTwoFactor(user *models.User, code, ip string) ([]string, error) {
+	twoFactor, err := loadTwoFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, errors.New("请先发起绑定两步验证")
+	}
+	if twoFactor.EnabledAt != nil {
+		return nil, errors.New("已启用两步验证")
+	}
+	if err := verifyTwoFactorCode(twoFactor, code); err != nil {
+		return nil, err
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(twoFactor).Update("enabled_at", time.Now()).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	codes, err := replaceRecoveryCodes(tx, user.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(&models.AuditLog{
+		Action: models.AuditActionTwoFactorEnabled,
+		UserID: &user.ID,
+		Target: user.Mobile,
+		IP:     ip,
+		Detail: "启用两步验证",
+	})
+	return codes, nil
+}
+
+// removeTwoFactor 删除两步验证配置和恢复码
+func removeTwoFactor(userID uint) error {
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// loadLoginUser 加载完成登录所需的用户信息，并检查用户状态
+func loadLoginUser(userID uint) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Preload("Roles.Permissions").Preload("Department").Preload("ManagedDepartments").
+		First(&user, userID).Error; err != nil {
+		return nil, errTwoFactorTokenInvalid
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, errors.New("用户不可用，请联系管理员")
+	}
+	return &user, nil
+}
+
+// loadUserWithPermissions 加载用户及其角色权限（用于判断是否必须启用两步验证）
+func loadUserWithPermissions(userID uint) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Preload("Roles.Permissions").First(&user, userID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	return &user, nil
+}
+
+// VerifyLogin 登录第二步：校验动态验证码或恢复码后登记会话并签发令牌
+func (s *TwoFactorService) VerifyLogin(req *dto.TwoFactorLoginRequest, client LoginClient) (*dto.LoginResponse, error) {
+	if req.Code == "" && req.RecoveryCode == "" {
+		return nil, errors.New("请输入动态验证码或恢复码")
+	}
+	claims, err := parseTwoFactorToken(req.TwoFactorToken, twoFactorPurposeVerify)
+	if err != nil {
+		return nil, err
+	}
+	user, err := loadLoginUser(claims.UID)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := loadTwoFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || twoFactor.EnabledAt == nil {
+		return nil, errTwoFactorTokenInvalid
+	}
+
+	if req.Code != "" {
+		if err := verifyTwoFactorCode(twoFactor, req.Code); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := useRecoveryCode(user.ID, req.RecoveryCode); err != nil {
+			return nil, err
+		}
+		var remaining int64
+		database.DB.Model(&models.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", user.ID).Count(&remaining)
+		recordAudit(&models.AuditLog{
+			Action: models.AuditActionRecoveryCodeUsed,
+			UserID: &user.ID,
+			Target: user.Mobile,
+			IP:     client.IP,
+			Detail: fmt.Sprintf("使用恢复码登录，剩余 %d 个", remaining),
+		})
+	}
+	markTwoFactorTokenUsed(claims)
+
+	response := &dto.LoginResponse{UserInfo: buildLoginUserInfo(user)}
+	client.Device = claims.Device
+	if err := issueLoginTokens(user, response, client, claims.LoginMethod); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// BeginSetup 登录时发起绑定两步验证（账号必须启用两步验证但尚未绑定）
+func (s *TwoFactorService) BeginSetup(req *dto.TwoFactorSetupRequest) (*dto.TwoFactorEnrollResponse, error) {
+	claims, err := parseTwoFactorToken(req.TwoFactorToken, twoFactorPurposeSetup)
+	if err != nil {
+		return nil, err
+	}
+	user, err := loadLoginUser(claims.UID)
+	if err != nil {
+		return nil, err
+	}
+	return enrollTwoFactor(user)
+}
+
+// ConfirmSetup 登录时确认绑定两步验证，启用后完成登录并返回恢复码
+func (s *TwoFactorService) ConfirmSetup(req *dto.TwoFactorSetupConfirmRequest, client LoginClient) (*dto.LoginResponse, error) {
+	claims, err := parseTwoFactorToken(req.TwoFactorToken, twoFactorPurposeSetup)
+	if err != nil {
+		return nil, err
+	}
+	user, err := loadLoginUser(claims.UID)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := enableTwoFactor(user, req.Code, client.IP)
+	if err != nil {
+		return nil, err
+	}
+	markTwoFactorTokenUsed(claims)
+
+	response := &dto.LoginResponse{UserInfo: buildLoginUserInfo(user), RecoveryCodes: codes}
+	client.Device = claims.Device
+	if err := issueLoginTokens(user, response, client, claims.LoginMethod); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetStatus 查询当前用户的两步验证状态
+func (s *TwoFactorService) GetStatus(userID uint) (*dto.TwoFactorStatusResponse, error) {
+	user, err := loadUserWithPermissions(userID)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := loadTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dto.TwoFactorStatusResponse{Required: twoFactorRequired(user)}
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		status.Enabled = true
+		status.EnabledAt = dto.PtrToResponseTime(twoFactor.EnabledAt)
+		database.DB.Model(&models.UserRecoveryCode{}).
+			Where("user_id = ? AND used_at IS NULL", userID).Count(&status.RecoveryCodesRemaining)
+	}
+	return status, nil
+}
+
+// Enroll 发起绑定两步验证，返回密钥和二维码
+func (s *TwoFactorService) Enroll(userID uint) (*dto.TwoFactorEnrollResponse, error) {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	return enrollTwoFactor(&user)
+}
+
+// Enable 验证动态验证码后启用两步验证，返回恢复码
+func (s *TwoFactorService) Enable(userID uint, req *dto.TwoFactorCodeRequest, ip string) (*dto.RecoveryCodesResponse, error) {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	codes, err := enableTwoFactor(&user, req.Code, ip)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码（旧的恢复码全部作废）
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, req *dto.TwoFactorCodeRequest, ip string) (*dto.RecoveryCodesResponse, error) {
+	twoFactor, err := loadTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || twoFactor.EnabledAt == nil {
+		return nil, errors.New("未启用两步验证")
+	}
+	if err := verifyTwoFactorCode(twoFactor, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, err := replaceRecoveryCodes(database.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(&models.AuditLog{
+		Action: models.AuditActionRecoveryRenewed,
+		UserID: &userID,
+		IP:     ip,
+		Detail: "重新生成恢复码",
+	})
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 解除两步验证（需验证密码和动态验证码；必须启用两步验证的账号不能解除）
+func (s *TwoFactorService) Disable(userID uint, req *dto.TwoFactorDisableRequest, ip string) error {
+	user, err := loadUserWithPermissions(userID)
+	if err != nil {
+		return err
+	}
+	if twoFactorRequired(user) {
+		return errors.New("当前账号必须启用两步验证，不能解除")
+	}
+	if !user.CheckPassword(req.Password) {
+		return errors.New("密码错误")
+	}
+	twoFactor, err := loadTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || twoFactor.EnabledAt == nil {
+		return errors.New("未启用两步验证")
+	}
+	if err := verifyTwoFactorCode(twoFactor, req.Code); err != nil {
+		return err
+	}
+
+	if err := removeTwoFactor(userID); err != nil {
+		return err
+	}
+	recordAudit(&models.AuditLog{
+		Action: models.AuditActionTwoFactorDisabled,
+		UserID: &user.ID,
+		Target: user.Mobile,
+		IP:     ip,
+		Detail: "本人解除两步验证",
+	})
+	return nil
+}
+
+// AdminReset 管理员重置用户的两步验证（用户丢失验证器和恢复码时使用，下次登录需重新绑定）
+func (s *TwoFactorService) AdminReset(userID, adminID uint, ip string) error {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return errors.New("用户不存在")
+	}
+	twoFactor, err := loadTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil {
+		return errors.New("该用户未启用两步验证")
+	}
+
+	if err := removeTwoFactor(userID); err != nil {
+		return err
+	}
+	recordAudit(&models.AuditLog{
+		Action:  models.AuditActionTwoFactorDisabled,
+		UserID:  &user.ID,
+		ActorID: &adminID,
+		Target:  user.Mobile,
+		IP:      ip,
+		Detail:  "管理员重置两步验证",
+	})
+	return nil
+}
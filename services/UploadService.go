@@ -143,8 +143,62 @@ func (s *UploadService) GetPlanAttachments(planID uint) []dto.AttachmentDetailRe
 	return s.toAttachmentDetailResults(attachments)
 }
 
+// GetTaskOwnAttachmentsBatch 批量获取多个任务本身的附件，按任务ID分组
+func (s *UploadService) GetTaskOwnAttachmentsBatch(taskIDs []uint) map[uint][]dto.AttachmentDetailResult {
+	return s.getAttachmentsGrouped("task_id IN ? AND solution_id = 0 AND plan_id = 0", taskIDs,
+		func(att dto.AttachmentDetailResult) uint { return att.TaskID })
+}
+
+// GetSolutionAttachmentsBatch 批量获取多个方案的附件，按方案ID分组
+func (s *UploadService) GetSolutionAttachmentsBatch(solutionIDs []uint) map[uint][]dto.AttachmentDetailResult {
+	return s.getAttachmentsGrouped("solution_id IN ?", solutionIDs,
+		func(att dto.AttachmentDetailResult) uint { return att.SolutionID })
+}
+
+// GetPlanAttachmentsBatch 批量获取多个执行计划的附件，按计划ID分组
+func (s *UploadService) GetPlanAttachmentsBatch(planIDs []uint) map[uint][]dto.AttachmentDetailResult {
+	return s.getAttachmentsGrouped("plan_id IN ?", planIDs,
+		func(att dto.AttachmentDetailResult) uint { return att.PlanID })
+}
+
+// getAttachmentsGrouped 按条件一次性查询附件并分组
+func (s *UploadService) getAttachmentsGrouped(condition string, ids []uint, keyOf func(dto.AttachmentDetailResult) uint) map[uint][]dto.AttachmentDetailResult {
+	grouped := make(map[uint][]dto.AttachmentDetailResult)
+	if len(ids) == 0 {
+		return grouped
+	}
+
+	var attachments []models.TaskAttachment
+	if err := database.DB.Where(condition, ids).Order("id ASC").Find(&attachments).Error; err != nil {
+		return grouped
+	}
+
+	for _, result := range s.toAttachmentDetailResults(attachments) {
+		key := keyOf(result)
+		grouped[key] = append(grouped[key], result)
+	}
+	return grouped
+}
+
 // toAttachmentDetailResults 转换附件列表为响应结果
 func (s *UploadService) toAttachmentDetailResults(attachments []models.TaskAttachment) []dto.AttachmentDetailResult {
+	// 一次性查询所有上传人信息
+	uploaderIDs := make([]uint, 0, len(attachments))
+	for _, att := range attachments {
+		if att.UploadedBy > 0 {
+			uploaderIDs = append(uploaderIDs, att.UploadedBy)
+		}
+	}
+	uploaders := make(map[uint]models.User)
+	if len(uploaderIDs) > 0 {
+		var users []models.User
+		if err := database.DB.Select("id, username, nickname").Where("id IN ?", uploaderIDs).Find(&users).Error; err == nil {
+			for _, user := range users {
+				uploaders[user.ID] = user
+			}
+		}
+	}
+
 	results := make([]dto.AttachmentDetailResult, 0, len(attachments))
 	for _, att := range attachments {
 		result := dto.AttachmentDetailResult{
@@ -161,13 +215,10 @@ func (s *UploadService) toAttachmentDetailResults(attachments []models.TaskAttac
 			CreatedAt:      dto.ToResponseTime(att.CreatedAt),
 		}
 
-		// 查询上传人信息
-		if att.UploadedBy > 0 {
-			var user models.User
-			if err := database.DB.Select("username, nickname").First(&user, att.UploadedBy).Error; err == nil {
-				result.UploaderUsername = user.Username
-				result.UploaderNickname = user.Nickname
-			}
+		// 填充上传人信息
+		if user, ok := uploaders[att.UploadedBy]; ok {
+			result.UploaderUsername = user.Username
+			result.UploaderNickname = user.Nickname
 		}
 
 		results = append(results, result)
@@ -5,10 +5,10 @@ import (
 	"RHPRo-Task/database"
 	"RHPRo-Task/dto"
 	"RHPRo-Task/models"
-	"RHPRo-Task/utils"
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 
 	"gorm.io/gorm"
 )
@@ -75,20 +75,28 @@ func (s *UserService) Register(req *dto.RegisterRequest) (*models.User, error) {
 }
 
 // Login 用户登录
-func (s *UserService) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
+func (s *UserService) Login(req *dto.LoginRequest, client LoginClient) (*dto.LoginResponse, error) {
+	// 账号或IP登录失败次数过多时拒绝登录
+	if err := checkLoginLock(req.Mobile, client.IP); err != nil {
+		return nil, err
+	}
+
 	// 通过手机号查询用户
 	var user models.User
 	if err := database.DB.Preload("Roles.Permissions").Preload("Department").Preload("ManagedDepartments").Where("mobile = ?", req.Mobile).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
+			recordLoginFailure(nil, req.Mobile, client.IP)
 			return nil, errors.New("手机号或密码错误")
 		}
 		return nil, err
 	}
 
-	// 验证密码
-	if !user.CheckPassword(req.Password) {
+	// 验证密码（目录账号的密码由目录管理，本地密码一律视为错误，不提示账号来源，防止探测目录账号）
+	if user.AuthSource == models.UserAuthSourceLDAP || !user.CheckPassword(req.Password) {
+		recordLoginFailure(&user, req.Mobile, client.IP)
 		return nil, errors.New("手机号或密码错误")
 	}
+	clearLoginFailures(req.Mobile)
 
 	// 检查用户状态
 	if user.Status == models.UserStatusDisabled {
@@ -98,6 +106,17 @@ func (s *UserService) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
 		return nil, errors.New("用户待审核，请联系管理员")
 	}
 
+	// 签发访问令牌和刷新令牌（启用或必须启用两步验证时先返回两步验证凭证）
+	response := &dto.LoginResponse{UserInfo: buildLoginUserInfo(&user)}
+	client.Device = req.Device
+	if err := completeLogin(&user, response, client, models.LoginMethodPassword); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// buildLoginUserInfo 构建登录响应中的用户信息（需预加载角色权限、部门和负责的部门）
+func buildLoginUserInfo(user *models.User) dto.UserResponse {
 	// // 获取部门信息
 	// var deptID uint
 	// var deptName string
@@ -114,12 +133,6 @@ func (s *UserService) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
 	// 	}
 	// }
 
-	// 生成token
-	token, err := utils.GenerateToken(user.ID, user.Username, user.Mobile, 24)
-	if err != nil {
-		return nil, err
-	}
-
 	// 构建用户信息响应
 	userInfo := dto.UserResponse{
 		ID:       user.ID,
@@ -164,10 +177,7 @@ func (s *UserService) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
 		userInfo.Roles = append(userInfo.Roles, roleResp)
 	}
 
-	return &dto.LoginResponse{
-		Token:    token,
-		UserInfo: userInfo,
-	}, nil
+	return userInfo
 }
 
 // GetUserByID 根据ID获取用户
@@ -328,12 +338,24 @@ func (s *UserService) UpdateUser(id uint, req *dto.UpdateUserRequest) error {
 	return database.DB.Model(&user).Updates(updates).Error
 }
 
+// ErrRoleNotGrantable 非超级管理员分配或移除了自己不拥有的角色
+var ErrRoleNotGrantable = errors.New("只能分配或移除自己拥有的角色")
+
 // AssignRoles 分配角色
-func (s *UserService) AssignRoles(userID uint, roleIDs []uint) error {
+// 非超级管理员只能分配或移除自己拥有的角色，防止借分配角色提升权限
+func (s *UserService) AssignRoles(operatorID, userID uint, roleIDs []uint) error {
 	var user models.User
-	if err := database.DB.First(&user, userID).Error; err != nil {
+	if err := database.DB.Preload("Roles").First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	var operator models.User
+	if err := database.DB.Preload("Roles").First(&operator, operatorID).Error; err != nil {
 		return err
 	}
+	if !isAdminUser(&operator) && !roleChangeGrantable(roleIDsOf(operator.Roles), roleIDsOf(user.Roles), roleIDs) {
+		return ErrRoleNotGrantable
+	}
 
 	var roles []*models.Role
 	if err := database.DB.Find(&roles, roleIDs).Error; err != nil {
@@ -342,7 +364,37 @@ func (s *UserService) AssignRoles(userID uint, roleIDs []uint) error {
 
 	// 清空现有角色并分配新角色
 	database.DB.Model(&user).Association("Roles").Clear()
-	return database.DB.Model(&user).Association("Roles").Append(roles)
+	if err := database.DB.Model(&user).Association("Roles").Append(roles); err != nil {
+		return err
+	}
+
+	// 角色变化后权限立即生效
+	(&AuthorizationService{}).ClearPermissionCache(userID)
+	return nil
+}
+
+// roleIDsOf 提取角色ID列表
+func roleIDsOf(roles []*models.Role) []uint {
+	ids := make([]uint, 0, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+	}
+	return ids
+}
+
+// roleChangeGrantable 判断角色调整（新增和移除的角色）是否都在操作人自己拥有的角色范围内
+func roleChangeGrantable(held, current, requested []uint) bool {
+	for _, id := range requested {
+		if !slices.Contains(current, id) && !slices.Contains(held, id) {
+			return false
+		}
+	}
+	for _, id := range current {
+		if !slices.Contains(requested, id) && !slices.Contains(held, id) {
+			return false
+		}
+	}
+	return true
 }
 
 // CreateUser 管理员创建用户（直接激活）
@@ -424,7 +476,11 @@ func (s *UserService) DeleteUser(id uint) error {
 	if err := database.DB.First(&user, id).Error; err != nil {
 		return errors.New("用户不存在")
 	}
-	return database.DB.Delete(&user).Error
+	if err := database.DB.Delete(&user).Error; err != nil {
+		return err
+	}
+	// 吊销该用户的全部登录会话
+	return revokeUserTokens(database.DB, user.ID, TokenRevokeDeleted)
 }
 
 // DisableUser 切换用户状态（禁用/启用）
@@ -444,7 +500,14 @@ func (s *UserService) DisableUser(id uint) error {
 		newStatus = models.UserStatusDisabled
 	}
 
-	return database.DB.Model(&user).Update("status", newStatus).Error
+	if err := database.DB.Model(&user).Update("status", newStatus).Error; err != nil {
+		return err
+	}
+	// 禁用后吊销该用户的全部登录会话
+	if newStatus == models.UserStatusDisabled {
+		return revokeUserTokens(database.DB, user.ID, TokenRevokeDisabled)
+	}
+	return nil
 }
 
 // BatchImportUsers 批量导入用户
@@ -700,6 +763,9 @@ func (s *UserService) ChangePassword(userID uint, req *dto.ChangePasswordRequest
 	if err := database.DB.First(&user, userID).Error; err != nil {
 		return errors.New("用户不存在")
 	}
+	if user.AuthSource == models.UserAuthSourceLDAP {
+		return errLdapPasswordManaged
+	}
 
 	// 验证旧密码
 	if !user.CheckPassword(req.OldPassword) {
@@ -712,7 +778,11 @@ func (s *UserService) ChangePassword(userID uint, req *dto.ChangePasswordRequest
 	}
 
 	// 只更新密码字段
-	return database.DB.Model(&user).Update("password", user.Password).Error
+	if err := database.DB.Model(&user).Update("password", user.Password).Error; err != nil {
+		return err
+	}
+	// 吊销该用户的全部登录会话，需使用新密码重新登录
+	return revokeUserTokens(database.DB, user.ID, TokenRevokePasswordChanged)
 }
 
 // ResetPassword 重置用户密码为初始密码（仅超级管理员可操作）
@@ -721,6 +791,9 @@ func (s *UserService) ResetPassword(targetUserID uint) error {
 	if err := database.DB.First(&user, targetUserID).Error; err != nil {
 		return errors.New("用户不存在")
 	}
+	if user.AuthSource == models.UserAuthSourceLDAP {
+		return errLdapPasswordManaged
+	}
 
 	// 获取默认密码
 	cfg := config.GetConfig()
@@ -732,5 +805,9 @@ func (s *UserService) ResetPassword(targetUserID uint) error {
 	}
 
 	// 只更新密码字段
-	return database.DB.Model(&user).Update("password", user.Password).Error
+	if err := database.DB.Model(&user).Update("password", user.Password).Error; err != nil {
+		return err
+	}
+	// 吊销该用户的全部登录会话，需使用新密码重新登录
+	return revokeUserTokens(database.DB, user.ID, TokenRevokePasswordChanged)
 }
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+)
+
+// 对比基准版本的选择方式
+const (
+	compareBaselineSpecified    = "specified"     // 指定
+	compareBaselineLastRejected = "last_rejected" // 最近一次被驳回的版本
+	compareBaselinePrevious     = "previous"      // 上一版本
+)
+
+type VersionCompareService struct{}
+
+// versionRef 版本号和状态（用于确定对比的版本）
+type versionRef struct {
+	Version int
+	Status  string
+}
+
+// resolveCompareVersions 确定对比的基准版本和目标版本
+// 目标版本默认为最新版本；基准版本默认为目标版本之前最近一次被驳回的版本，没有时为上一版本
+func resolveCompareVersions(versions []versionRef, from, to int) (int, int, string, error) {
+	if len(versions) == 0 {
+		return 0, 0, "", errors.New("暂无可对比的版本")
+	}
+	exists := make(map[int]bool, len(versions))
+	latest := 0
+	for _, v := range versions {
+		exists[v.Version] = true
+		if v.Version > latest {
+			latest = v.Version
+		}
+	}
+
+	if to == 0 {
+		to = latest
+	} else if !exists[to] {
+		return 0, 0, "", fmt.Errorf("版本 %d 不存在", to)
+	}
+
+	if from != 0 {
+		if !exists[from] {
+			return 0, 0, "", fmt.Errorf("版本 %d 不存在", from)
+		}
+		if from == to {
+			return 0, 0, "", errors.New("对比的两个版本不能相同")
+		}
+		return from, to, compareBaselineSpecified, nil
+	}
+
+	lastRejected, previous := 0, 0
+	for _, v := range versions {
+		if v.Version >= to {
+			continue
+		}
+		if v.Version > previous {
+			previous = v.Version
+		}
+		if v.Status == "rejected" && v.Version > lastRejected {
+			lastRejected = v.Version
+		}
+	}
+	if lastRejected > 0 {
+		return lastRejected, to, compareBaselineLastRejected, nil
+	}
+	if previous > 0 {
+		return previous, to, compareBaselinePrevious, nil
+	}
+	return 0, 0, "", fmt.Errorf("版本 %d 之前没有可对比的版本", to)
+}
+
+// CompareSolutions 对比任务的两个思路方案版本
+func (s *VersionCompareService) CompareSolutions(taskID uint, query *dto.VersionCompareQuery) (*dto.SolutionCompareResponse, error) {
+	var refs []versionRef
+	if err := database.DB.Model(&models.RequirementSolution{}).
+		Where("task_id = ?", taskID).
+		Select("version, status").
+		Find(&refs).Error; err != nil {
+		return nil, err
+	}
+	fromVersion, toVersion, baseline, err := resolveCompareVersions(refs, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to models.RequirementSolution
+	if err := database.DB.Where("task_id = ? AND version = ?", taskID, fromVersion).Order("id DESC").First(&from).Error; err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", fromVersion)
+	}
+	if err := database.DB.Where("task_id = ? AND version = ?", taskID, toVersion).Order("id DESC").First(&to).Error; err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", toVersion)
+	}
+
+	uploadService := &UploadService{}
+	resp := &dto.SolutionCompareResponse{
+		From:     solutionVersionSummary(&from),
+		To:       solutionVersionSummary(&to),
+		Baseline: baseline,
+		TextDiffs: []dto.TextDiff{
+			diffText("title", from.Title, to.Title),
+			diffText("content", from.Content, to.Content),
+			diffText("mindmap_markdown", from.MindmapMarkdown, to.MindmapMarkdown),
+		},
+		FieldChanges: []dto.FieldChange{},
+		Attachments: diffAttachments(
+			uploadService.GetSolutionAttachments(from.ID),
+			uploadService.GetSolutionAttachments(to.ID),
+		),
+	}
+	if from.MindmapURL != to.MindmapURL {
+		resp.FieldChanges = append(resp.FieldChanges, dto.FieldChange{Field: "mindmap_url", OldValue: from.MindmapURL, NewValue: to.MindmapURL})
+	}
+	if from.FileName != to.FileName {
+		resp.FieldChanges = append(resp.FieldChanges, dto.FieldChange{Field: "file_name", OldValue: from.FileName, NewValue: to.FileName})
+	}
+	return resp, nil
+}
+
+// CompareExecutionPlans 对比任务的两个执行计划版本（含实施步骤和目标）
+func (s *VersionCompareService) CompareExecutionPlans(taskID uint, query *dto.VersionCompareQuery) (*dto.ExecutionPlanCompareResponse, error) {
+	var refs []versionRef
+	if err := database.DB.Model(&models.ExecutionPlan{}).
+		Where("task_id = ?", taskID).
+		Select("version, status").
+		Find(&refs).Error; err != nil {
+		return nil, err
+	}
+	fromVersion, toVersion, baseline, err := resolveCompareVersions(refs, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to models.ExecutionPlan
+	if err := database.DB.Where("task_id = ? AND version = ?", taskID, fromVersion).Order("id DESC").First(&from).Error; err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", fromVersion)
+	}
+	if err := database.DB.Where("task_id = ? AND version = ?", taskID, toVersion).Order("id DESC").First(&to).Error; err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", toVersion)
+	}
+
+	stepChanges, err := diffJSON(from.ImplementationSteps, to.ImplementationSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromGoals, toGoals []models.RequirementGoal
+	database.DB.Where("execution_plan_id = ?", from.ID).Order("goal_no ASC").Find(&fromGoals)
+	database.DB.Where("execution_plan_id = ?", to.ID).Order("goal_no ASC").Find(&toGoals)
+
+	uploadService := &UploadService{}
+	return &dto.ExecutionPlanCompareResponse{
+		From:     planVersionSummary(&from),
+		To:       planVersionSummary(&to),
+		Baseline: baseline,
+		TextDiffs: []dto.TextDiff{
+			diffText("title", from.Title, to.Title),
+			diffText("tech_stack", from.TechStack, to.TechStack),
+			diffText("resource_requirements", from.ResourceRequirements, to.ResourceRequirements),
+			diffText("risk_assessment", from.RiskAssessment, to.RiskAssessment),
+		},
+		StepChanges: stepChanges,
+		GoalChanges: diffGoals(fromGoals, toGoals),
+		Attachments: diffAttachments(
+			uploadService.GetPlanAttachments(from.ID),
+			uploadService.GetPlanAttachments(to.ID),
+		),
+	}, nil
+}
+
+// solutionVersionSummary 思路方案版本摘要
+func solutionVersionSummary(solution *models.RequirementSolution) dto.VersionSummary {
+	return dto.VersionSummary{
+		ID:          solution.ID,
+		Version:     solution.Version,
+		Title:       solution.Title,
+		Status:      solution.Status,
+		SubmittedAt: dto.PtrToResponseTime(solution.SubmittedAt),
+	}
+}
+
+// planVersionSummary 执行计划版本摘要
+func planVersionSummary(plan *models.ExecutionPlan) dto.VersionSummary {
+	return dto.VersionSummary{
+		ID:          plan.ID,
+		Version:     plan.Version,
+		Title:       plan.Title,
+		Status:      plan.Status,
+		SubmittedAt: dto.PtrToResponseTime(plan.SubmittedAt),
+	}
+}
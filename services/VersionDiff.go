@@ -0,0 +1,335 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 版本差异类型
+const (
+	diffEqual   = "equal"
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+// maxLineDiffCells 行级差异 LCS 表的最大单元数，超过时中间变化部分按整体删除/新增处理
+const maxLineDiffCells = 4000000
+
+// splitLines 按行拆分文本（统一换行符，空文本没有行）
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// diffText 计算文本字段的行级差异（去除公共前后缀后按最长公共子序列比较）
+func diffText(field, oldText, newText string) dto.TextDiff {
+	result := dto.TextDiff{Field: field}
+	if oldText == newText {
+		return result
+	}
+	result.Changed = true
+
+	oldLines, newLines := splitLines(oldText), splitLines(newText)
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	for i := 0; i < prefix; i++ {
+		result.Lines = append(result.Lines, dto.LineDiff{Type: diffEqual, OldLine: i + 1, NewLine: i + 1, Text: oldLines[i]})
+	}
+	middle := diffLineRange(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix], prefix, prefix)
+	for _, line := range middle {
+		switch line.Type {
+		case diffAdded:
+			result.Added++
+		case diffRemoved:
+			result.Removed++
+		}
+	}
+	result.Lines = append(result.Lines, middle...)
+	for i := 0; i < suffix; i++ {
+		oldIndex := len(oldLines) - suffix + i
+		newIndex := len(newLines) - suffix + i
+		result.Lines = append(result.Lines, dto.LineDiff{Type: diffEqual, OldLine: oldIndex + 1, NewLine: newIndex + 1, Text: oldLines[oldIndex]})
+	}
+	return result
+}
+
+// diffLineRange 比较两段行，oldOffset/newOffset 为这两段在原文中的起始行下标
+func diffLineRange(oldLines, newLines []string, oldOffset, newOffset int) []dto.LineDiff {
+	n, m := len(oldLines), len(newLines)
+	lines := make([]dto.LineDiff, 0, n+m)
+	if n*m > maxLineDiffCells {
+		for i, text := range oldLines {
+			lines = append(lines, dto.LineDiff{Type: diffRemoved, OldLine: oldOffset + i + 1, Text: text})
+		}
+		for j, text := range newLines {
+			lines = append(lines, dto.LineDiff{Type: diffAdded, NewLine: newOffset + j + 1, Text: text})
+		}
+		return lines
+	}
+
+	// lcs[i][j] 为 oldLines[i:] 与 newLines[j:] 的最长公共子序列长度
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldLines[i] == newLines[j]:
+			lines = append(lines, dto.LineDiff{Type: diffEqual, OldLine: oldOffset + i + 1, NewLine: newOffset + j + 1, Text: oldLines[i]})
+			i++
+			j++
+		case j >= m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, dto.LineDiff{Type: diffRemoved, OldLine: oldOffset + i + 1, Text: oldLines[i]})
+			i++
+		default:
+			lines = append(lines, dto.LineDiff{Type: diffAdded, NewLine: newOffset + j + 1, Text: newLines[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// diffJSON 计算两个 JSON 文档的结构差异（对象按键、数组按下标比较）
+func diffJSON(oldRaw, newRaw []byte) ([]dto.JSONChange, error) {
+	var oldValue, newValue interface{}
+	if len(oldRaw) > 0 {
+		if err := json.Unmarshal(oldRaw, &oldValue); err != nil {
+			return nil, fmt.Errorf("解析基准版本 JSON 失败: %v", err)
+		}
+	}
+	if len(newRaw) > 0 {
+		if err := json.Unmarshal(newRaw, &newValue); err != nil {
+			return nil, fmt.Errorf("解析目标版本 JSON 失败: %v", err)
+		}
+	}
+	changes := []dto.JSONChange{}
+	diffJSONValue("", oldValue, newValue, &changes)
+	return changes, nil
+}
+
+// diffJSONValue 递归比较 JSON 值
+func diffJSONValue(path string, oldValue, newValue interface{}, changes *[]dto.JSONChange) {
+	switch {
+	case oldValue == nil && newValue == nil:
+		return
+	case oldValue == nil:
+		*changes = append(*changes, dto.JSONChange{Path: path, Type: diffAdded, NewValue: newValue})
+		return
+	case newValue == nil:
+		*changes = append(*changes, dto.JSONChange{Path: path, Type: diffRemoved, OldValue: oldValue})
+		return
+	}
+
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make([]string, 0, len(oldMap)+len(newMap))
+		for key := range oldMap {
+			keys = append(keys, key)
+		}
+		for key := range newMap {
+			if _, ok := oldMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diffJSONValue(childPath, oldMap[key], newMap[key], changes)
+		}
+		return
+	}
+
+	oldList, oldIsList := oldValue.([]interface{})
+	newList, newIsList := newValue.([]interface{})
+	if oldIsList && newIsList {
+		length := len(oldList)
+		if len(newList) > length {
+			length = len(newList)
+		}
+		for i := 0; i < length; i++ {
+			var oldItem, newItem interface{}
+			if i < len(oldList) {
+				oldItem = oldList[i]
+			}
+			if i < len(newList) {
+				newItem = newList[i]
+			}
+			diffJSONValue(path+"["+strconv.Itoa(i)+"]", oldItem, newItem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, dto.JSONChange{Path: path, Type: diffChanged, OldValue: oldValue, NewValue: newValue})
+	}
+}
+
+// formatGoalDate 格式化目标日期（用于差异比较）
+func formatGoalDate(goal *models.RequirementGoal, start bool) string {
+	value := goal.EndDate
+	if start {
+		value = goal.StartDate
+	}
+	if value == nil {
+		return ""
+	}
+	return value.Format(dateKeyFormat)
+}
+
+// diffGoalFields 比较两个目标的字段
+func diffGoalFields(oldGoal, newGoal *models.RequirementGoal) []dto.FieldChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"title", oldGoal.Title, newGoal.Title},
+		{"description", oldGoal.Description, newGoal.Description},
+		{"success_criteria", oldGoal.SuccessCriteria, newGoal.SuccessCriteria},
+		{"priority", strconv.Itoa(oldGoal.Priority), strconv.Itoa(newGoal.Priority)},
+		{"start_date", formatGoalDate(oldGoal, true), formatGoalDate(newGoal, true)},
+		{"end_date", formatGoalDate(oldGoal, false), formatGoalDate(newGoal, false)},
+	}
+	var changes []dto.FieldChange
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, dto.FieldChange{Field: field.name, OldValue: field.old, NewValue: field.new})
+		}
+	}
+	return changes
+}
+
+// matchGoals 匹配两个版本的目标：先按标题匹配，剩余的按目标编号匹配，返回新目标下标到旧目标下标的映射
+func matchGoals(oldGoals, newGoals []models.RequirementGoal) map[int]int {
+	matched := make(map[int]int)
+	oldUsed := make(map[int]bool)
+
+	oldByTitle := make(map[string][]int)
+	for i, goal := range oldGoals {
+		oldByTitle[goal.Title] = append(oldByTitle[goal.Title], i)
+	}
+	for j, goal := range newGoals {
+		if candidates := oldByTitle[goal.Title]; len(candidates) > 0 {
+			matched[j] = candidates[0]
+			oldUsed[candidates[0]] = true
+			oldByTitle[goal.Title] = candidates[1:]
+		}
+	}
+
+	oldByNo := make(map[int]int)
+	for i, goal := range oldGoals {
+		if !oldUsed[i] {
+			oldByNo[goal.GoalNo] = i
+		}
+	}
+	for j, goal := range newGoals {
+		if _, ok := matched[j]; ok {
+			continue
+		}
+		if i, ok := oldByNo[goal.GoalNo]; ok {
+			matched[j] = i
+			oldUsed[i] = true
+			delete(oldByNo, goal.GoalNo)
+		}
+	}
+	return matched
+}
+
+// diffGoals 比较两个版本的目标：按 matchGoals 匹配，未匹配的为新增/删除
+func diffGoals(oldGoals, newGoals []models.RequirementGoal) []dto.GoalChange {
+	matched := matchGoals(oldGoals, newGoals)
+	oldUsed := make(map[int]bool, len(matched))
+	for _, i := range matched {
+		oldUsed[i] = true
+	}
+
+	changes := []dto.GoalChange{}
+	for j := range newGoals {
+		newGoal := &newGoals[j]
+		i, ok := matched[j]
+		if !ok {
+			changes = append(changes, dto.GoalChange{Type: diffAdded, NewGoalNo: newGoal.GoalNo, Title: newGoal.Title})
+			continue
+		}
+		if fieldChanges := diffGoalFields(&oldGoals[i], newGoal); len(fieldChanges) > 0 {
+			changes = append(changes, dto.GoalChange{
+				Type:      diffChanged,
+				OldGoalNo: oldGoals[i].GoalNo,
+				NewGoalNo: newGoal.GoalNo,
+				Title:     newGoal.Title,
+				Changes:   fieldChanges,
+			})
+		}
+	}
+	for i := range oldGoals {
+		if !oldUsed[i] {
+			changes = append(changes, dto.GoalChange{Type: diffRemoved, OldGoalNo: oldGoals[i].GoalNo, Title: oldGoals[i].Title})
+		}
+	}
+	return changes
+}
+
+// diffAttachments 比较两个版本的附件（按文件名匹配，同名文件地址或大小不同视为内容变化）
+func diffAttachments(oldFiles, newFiles []dto.AttachmentDetailResult) []dto.AttachmentChange {
+	oldByName := make(map[string][]int)
+	for i, file := range oldFiles {
+		oldByName[file.FileName] = append(oldByName[file.FileName], i)
+	}
+
+	changes := []dto.AttachmentChange{}
+	oldUsed := make(map[int]bool)
+	for j := range newFiles {
+		newFile := &newFiles[j]
+		candidates := oldByName[newFile.FileName]
+		if len(candidates) == 0 {
+			changes = append(changes, dto.AttachmentChange{Type: diffAdded, FileName: newFile.FileName, New: newFile})
+			continue
+		}
+		i := candidates[0]
+		oldByName[newFile.FileName] = candidates[1:]
+		oldUsed[i] = true
+		oldFile := &oldFiles[i]
+		if oldFile.FileURL != newFile.FileURL || oldFile.FileSize != newFile.FileSize {
+			changes = append(changes, dto.AttachmentChange{Type: diffChanged, FileName: newFile.FileName, Old: oldFile, New: newFile})
+		}
+	}
+	for i := range oldFiles {
+		if !oldUsed[i] {
+			changes = append(changes, dto.AttachmentChange{Type: diffRemoved, FileName: oldFiles[i].FileName, Old: &oldFiles[i]})
+		}
+	}
+	return changes
+}
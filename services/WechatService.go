@@ -5,7 +5,6 @@ import (
 	"RHPRo-Task/database"
 	"RHPRo-Task/dto"
 	"RHPRo-Task/models"
-	"RHPRo-Task/utils"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -53,7 +52,10 @@ type WechatPhoneResponse struct {
 }
 
 // Login 微信登录
-func (s *WechatService) Login(req *dto.WechatLoginRequest) (*dto.WechatLoginResponse, error) {
+func (s *WechatService) Login(req *dto.WechatLoginRequest, client LoginClient) (*dto.WechatLoginResponse, error) {
+	client.Device = req.Device
+	method := wechatLoginMethod(req.LoginType)
+
 	// 1. 根据登录类型获取对应的AppID和Secret
 	appID, appSecret := s.getWechatConfig(req.LoginType)
 	if appID == "" || appSecret == "" {
@@ -88,7 +90,7 @@ func (s *WechatService) Login(req *dto.WechatLoginRequest) (*dto.WechatLoginResp
 
 	// 5. 已绑定微信的用户，直接登录
 	if foundByWechat {
-		return s.loginExistingUser(&user)
+		return s.loginExistingUser(&user, client, method)
 	}
 
 	// 6. 未绑定微信，尝试获取微信绑定的手机号
@@ -117,12 +119,12 @@ func (s *WechatService) Login(req *dto.WechatLoginRequest) (*dto.WechatLoginResp
 			if err := database.DB.Save(&existingUser).Error; err != nil {
 				return nil, err
 			}
-			return s.loginExistingUser(&existingUser)
+			return s.loginExistingUser(&existingUser, client, method)
 		}
 	}
 
 	// 8. 未绑定用户，返回微信信息和手机号，要求补充密码
-	tempToken, err := s.generateTempToken(tokenResp.UnionID, tokenResp.OpenID, userInfo.Nickname, userInfo.HeadImgURL, wechatMobile)
+	tempToken, err := s.generateTempToken(tokenResp.UnionID, tokenResp.OpenID, userInfo.Nickname, userInfo.HeadImgURL, wechatMobile, req.LoginType)
 	if err != nil {
 		return nil, err
 	}
@@ -141,13 +143,16 @@ func (s *WechatService) Login(req *dto.WechatLoginRequest) (*dto.WechatLoginResp
 }
 
 // BindMobile 微信绑定手机号（新用户注册）
-func (s *WechatService) BindMobile(req *dto.WechatBindRequest) (*dto.LoginResponse, error) {
+func (s *WechatService) BindMobile(req *dto.WechatBindRequest, client LoginClient) (*dto.LoginResponse, error) {
 	// 1. 解析临时token
 	claims, err := s.parseTempToken(req.TempToken)
 	if err != nil {
 		return nil, errors.New("临时凭证无效或已过期")
 	}
 
+	client.Device = req.Device
+	method := wechatLoginMethod(claims.LoginType)
+
 	// 2. 如果微信已绑定手机号，必须使用该手机号注册
 	if claims.Mobile != "" && req.Mobile != claims.Mobile {
 		return nil, errors.New("请使用微信绑定的手机号进行注册")
@@ -168,7 +173,7 @@ func (s *WechatService) BindMobile(req *dto.WechatBindRequest) (*dto.LoginRespon
 		if err := database.DB.Save(&existingUser).Error; err != nil {
 			return nil, err
 		}
-		return s.generateLoginResponse(&existingUser)
+		return s.generateLoginResponse(&existingUser, client, method)
 	}
 
 	// 4. 创建新用户
@@ -194,7 +199,19 @@ func (s *WechatService) BindMobile(req *dto.WechatBindRequest) (*dto.LoginRespon
 		database.DB.Model(user).Association("Roles").Append(&userRole)
 	}
 
-	return s.generateLoginResponse(user)
+	return s.generateLoginResponse(user, client, method)
+}
+
+// wechatLoginMethod 微信登录类型对应的会话登录方式
+func wechatLoginMethod(loginType string) string {
+	switch loginType {
+	case "mp":
+		return models.LoginMethodWechatMP
+	case "h5":
+		return models.LoginMethodWechatH5
+	default:
+		return models.LoginMethodWechatOpen
+	}
 }
 
 // getWechatConfig 获取微信配置
@@ -307,7 +324,7 @@ func (s *WechatService) getPhoneNumber(appID, appSecret, phoneCode string) (*Wec
 }
 
 // loginExistingUser 已绑定用户登录
-func (s *WechatService) loginExistingUser(user *models.User) (*dto.WechatLoginResponse, error) {
+func (s *WechatService) loginExistingUser(user *models.User, client LoginClient, method string) (*dto.WechatLoginResponse, error) {
 	// 检查用户状态
 	if user.Status == models.UserStatusDisabled {
 		return nil, errors.New("用户已被禁用")
@@ -316,20 +333,27 @@ func (s *WechatService) loginExistingUser(user *models.User) (*dto.WechatLoginRe
 		return nil, errors.New("用户待审核，请联系管理员")
 	}
 
-	loginResp, err := s.generateLoginResponse(user)
+	loginResp, err := s.generateLoginResponse(user, client, method)
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.WechatLoginResponse{
-		NeedBind: false,
-		Token:    loginResp.Token,
-		UserInfo: loginResp.UserInfo,
+		NeedBind:         false,
+		Token:            loginResp.Token,
+		RefreshToken:     loginResp.RefreshToken,
+		ExpiresIn:        loginResp.ExpiresIn,
+		RefreshExpiresIn: loginResp.RefreshExpiresIn,
+		UserInfo:         loginResp.UserInfo,
+
+		TwoFactorRequired:      loginResp.TwoFactorRequired,
+		TwoFactorSetupRequired: loginResp.TwoFactorSetupRequired,
+		TwoFactorToken:         loginResp.TwoFactorToken,
 	}, nil
 }
 
 // generateLoginResponse 生成登录响应
-func (s *WechatService) generateLoginResponse(user *models.User) (*dto.LoginResponse, error) {
+func (s *WechatService) generateLoginResponse(user *models.User, client LoginClient, method string) (*dto.LoginResponse, error) {
 	// 重新加载用户关联数据
 	database.DB.Preload("Roles.Permissions").Preload("Department").Preload("ManagedDepartments").First(user, user.ID)
 
@@ -346,11 +370,6 @@ func (s *WechatService) generateLoginResponse(user *models.User) (*dto.LoginResp
 	// 	managedDeptIDs = append(managedDeptIDs, dept.ID)
 	// }
 
-	token, err := utils.GenerateToken(user.ID, user.Username, user.Mobile, 24)
-	if err != nil {
-		return nil, err
-	}
-
 	userInfo := dto.UserResponse{
 		ID:       user.ID,
 		Username: user.Username,
@@ -360,30 +379,34 @@ func (s *WechatService) generateLoginResponse(user *models.User) (*dto.LoginResp
 		Status:   user.Status,
 	}
 
-	return &dto.LoginResponse{
-		Token:    token,
-		UserInfo: userInfo,
-	}, nil
+	// 启用或必须启用两步验证时先返回两步验证凭证
+	response := &dto.LoginResponse{UserInfo: userInfo}
+	if err := completeLogin(user, response, client, method); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // TempTokenClaims 临时token的claims
 type TempTokenClaims struct {
-	UnionID  string `json:"unionid"`
-	OpenID   string `json:"openid"`
-	Nickname string `json:"nickname"`
-	Avatar   string `json:"avatar"`
-	Mobile   string `json:"mobile"` // 微信绑定的手机号
+	UnionID   string `json:"unionid"`
+	OpenID    string `json:"openid"`
+	Nickname  string `json:"nickname"`
+	Avatar    string `json:"avatar"`
+	Mobile    string `json:"mobile"`     // 微信绑定的手机号
+	LoginType string `json:"login_type"` // 登录类型：scan/mp/h5（绑定后登记会话的登录方式）
 	jwt.RegisteredClaims
 }
 
 // generateTempToken 生成临时token（用于绑定手机号）
-func (s *WechatService) generateTempToken(unionID, openID, nickname, avatar, mobile string) (string, error) {
+func (s *WechatService) generateTempToken(unionID, openID, nickname, avatar, mobile, loginType string) (string, error) {
 	claims := TempTokenClaims{
-		UnionID:  unionID,
-		OpenID:   openID,
-		Nickname: nickname,
-		Avatar:   avatar,
-		Mobile:   mobile,
+		UnionID:   unionID,
+		OpenID:    openID,
+		Nickname:  nickname,
+		Avatar:    avatar,
+		Mobile:    mobile,
+		LoginType: loginType,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(10 * time.Minute)), // 10分钟有效
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"RHPRo-Task/database"
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"gorm.io/datatypes"
+)
+
+// WorkflowService 流程设计器：管理任务类型、状态和状态转换规则
+type WorkflowService struct{}
+
+var workflowCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// GetTaskTypeList 获取任务类型列表
+func (s *WorkflowService) GetTaskTypeList() ([]dto.TaskTypeDetailResponse, error) {
+	var types []models.TaskType
+	if err := database.DB.Order("id ASC").Find(&types).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.TaskTypeDetailResponse, 0, len(types))
+	for i := range types {
+		result = append(result, s.toTaskTypeDetailResponse(&types[i]))
+	}
+	return result, nil
+}
+
+// CreateTaskType 创建任务类型（创建后再通过 SaveWorkflow 配置状态和转换）
+func (s *WorkflowService) CreateTaskType(req *dto.TaskTypeRequest) (*dto.TaskTypeDetailResponse, error) {
+	if !workflowCodePattern.MatchString(req.Code) {
+		return nil, errors.New("类型编码只能包含小写字母、数字和下划线，且以字母开头")
+	}
+
+	var count int64
+	database.DB.Model(&models.TaskType{}).Where("code = ?", req.Code).Count(&count)
+	if count > 0 {
+		return nil, errors.New("类型编码已存在")
+	}
+
+	taskType := models.TaskType{
+		Code:               req.Code,
+		Name:               req.Name,
+		Description:        req.Description,
+		PoolStatusCode:     req.PoolStatusCode,
+		AssignedStatusCode: req.AssignedStatusCode,
+		AcceptedStatusCode: req.AcceptedStatusCode,
+	}
+	if err := database.DB.Create(&taskType).Error; err != nil {
+		return nil, fmt.Errorf("创建任务类型失败: %v", err)
+	}
+
+	resp := s.toTaskTypeDetailResponse(&taskType)
+	return &resp, nil
+}
+
+// UpdateTaskType 更新任务类型的名称、描述和入口状态
+func (s *WorkflowService) UpdateTaskType(code string, req *dto.TaskTypeRequest) (*dto.TaskTypeDetailResponse, error) {
+	var taskType models.TaskType
+	if err := database.DB.Where("code = ?", code).First(&taskType).Error; err != nil {
+		return nil, errors.New("任务类型不存在")
+	}
+
+	// 入口状态必须属于该类型
+	for _, statusCode := range []string{req.PoolStatusCode, req.AssignedStatusCode, req.AcceptedStatusCode} {
+		if statusCode == "" {
+			continue
+		}
+		var count int64
+		database.DB.Model(&models.TaskStatus{}).
+			Where("code = ? AND task_type_code = ?", statusCode, code).
+			Count(&count)
+		if count == 0 {
+			return nil, fmt.Errorf("状态 %s 不属于任务类型 %s", statusCode, code)
+		}
+	}
+
+	if err := database.DB.Model(&taskType).
+		Select("name", "description", "pool_status_code", "assigned_status_code", "accepted_status_code").
+		Updates(models.TaskType{
+			Name:               req.Name,
+			Description:        req.Description,
+			PoolStatusCode:     req.PoolStatusCode,
+			AssignedStatusCode: req.AssignedStatusCode,
+			AcceptedStatusCode: req.AcceptedStatusCode,
+		}).Error; err != nil {
+		return nil, fmt.Errorf("更新任务类型失败: %v", err)
+	}
+
+	database.DB.First(&taskType, taskType.ID)
+	resp := s.toTaskTypeDetailResponse(&taskType)
+	return &resp, nil
+}
+
+// DeleteTaskType 删除任务类型及其状态和转换规则（已有任务使用时不允许删除）
+func (s *WorkflowService) DeleteTaskType(code string) error {
+	var taskType models.TaskType
+	if err := database.DB.Where("code = ?", code).First(&taskType).Error; err != nil {
+		return errors.New("任务类型不存在")
+	}
+
+	var taskCount int64
+	database.DB.Unscoped().Model(&models.Task{}).Where("task_type_code = ?", code).Count(&taskCount)
+	if taskCount > 0 {
+		return fmt.Errorf("该类型下已有 %d 个任务，无法删除", taskCount)
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("task_type_code = ?", code).Delete(&models.TaskStatusTransition{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("task_type_code = ?", code).Delete(&models.TaskStatus{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&taskType).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetWorkflow 获取任务类型的流程定义（状态、转换规则和校验结果）
+func (s *WorkflowService) GetWorkflow(code string) (*dto.WorkflowResponse, error) {
+	var taskType models.TaskType
+	if err := database.DB.Where("code = ?", code).First(&taskType).Error; err != nil {
+		return nil, errors.New("任务类型不存在")
+	}
+
+	var statuses []models.TaskStatus
+	if err := database.DB.Where("task_type_code = ?", code).Order("sort_order ASC, id ASC").Find(&statuses).Error; err != nil {
+		return nil, err
+	}
+	var transitions []models.TaskStatusTransition
+	if err := database.DB.Where("task_type_code = ?", code).Order("id ASC").Find(&transitions).Error; err != nil {
+		return nil, err
+	}
+
+	req := &dto.WorkflowRequest{
+		Statuses:    make([]dto.WorkflowStatusItem, 0, len(statuses)),
+		Transitions: make([]dto.WorkflowTransitionItem, 0, len(transitions)),
+	}
+	for _, st := range statuses {
+		allowsSubtasks := st.AllowsSubtasks
+		req.Statuses = append(req.Statuses, dto.WorkflowStatusItem{
+			Code:           st.Code,
+			Name:           st.Name,
+			Category:       st.Category,
+			IsInitial:      st.IsInitial,
+			AllowsSubtasks: &allowsSubtasks,
+			SortOrder:      st.SortOrder,
+			Description:    st.Description,
+		})
+	}
+	for _, tr := range transitions {
+		isAllowed := tr.IsAllowed
+		item := dto.WorkflowTransitionItem{
+			FromStatusCode:   tr.FromStatusCode,
+			ToStatusCode:     tr.ToStatusCode,
+			RequiresApproval: tr.RequiresApproval,
+			IsAllowed:        &isAllowed,
+			Guards:           []string(tr.Guards),
+			PostActions:      []string(tr.PostActions),
+			Description:      tr.Description,
+		}
+		if tr.RequiredRole != nil {
+			item.RequiredRole = *tr.RequiredRole
+		}
+		req.Transitions = append(req.Transitions, item)
+	}
+
+	return &dto.WorkflowResponse{
+		TaskType:    s.toTaskTypeDetailResponse(&taskType),
+		Statuses:    req.Statuses,
+		Transitions: req.Transitions,
+		Validation:  ValidateWorkflowGraph(&taskType, req),
+	}, nil
+}
+
+// ValidateWorkflow 校验流程定义（不保存）
+func (s *WorkflowService) ValidateWorkflow(code string, req *dto.WorkflowRequest) (*dto.WorkflowValidationResult, error) {
+	var taskType models.TaskType
+	if err := database.DB.Where("code = ?", code).First(&taskType).Error; err != nil {
+		return nil, errors.New("任务类型不存在")
+	}
+
+	result := ValidateWorkflowGraph(&taskType, req)
+	s.appendStorageErrors(&result, code, req)
+	return &result, nil
+}
+
+// SaveWorkflow 保存任务类型的流程定义（整体替换状态和转换规则）
+// 校验不通过时不做任何修改并返回校验结果
+func (s *WorkflowService) SaveWorkflow(code string, req *dto.WorkflowRequest) (*dto.WorkflowResponse, *dto.WorkflowValidationResult, error) {
+	var taskType models.TaskType
+	if err := database.DB.Where("code = ?", code).First(&taskType).Error; err != nil {
+		return nil, nil, errors.New("任务类型不存在")
+	}
+
+	result := ValidateWorkflowGraph(&taskType, req)
+	s.appendStorageErrors(&result, code, req)
+	if !result.Valid {
+		return nil, &result, errors.New("流程校验未通过")
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. 新增或更新状态
+	keepCodes := make([]string, 0, len(req.Statuses))
+	for _, item := range req.Statuses {
+		keepCodes = append(keepCodes, item.Code)
+		allowsSubtasks := item.AllowsSubtasks == nil || *item.AllowsSubtasks
+
+		var status models.TaskStatus
+		err := tx.Where("code = ?", item.Code).First(&status).Error
+		if err == nil {
+			if err := tx.Model(&status).
+				Select("name", "category", "is_initial", "allows_subtasks", "sort_order", "description").
+				Updates(models.TaskStatus{
+					Name:           item.Name,
+					Category:       item.Category,
+					IsInitial:      item.IsInitial,
+					AllowsSubtasks: allowsSubtasks,
+					SortOrder:      item.SortOrder,
+					Description:    item.Description,
+				}).Error; err != nil {
+				tx.Rollback()
+				return nil, nil, fmt.Errorf("更新状态 %s 失败: %v", item.Code, err)
+			}
+			continue
+		}
+
+		status = models.TaskStatus{
+			Code:           item.Code,
+			Name:           item.Name,
+			TaskTypeCode:   code,
+			Category:       item.Category,
+			IsInitial:      item.IsInitial,
+			AllowsSubtasks: allowsSubtasks,
+			SortOrder:      item.SortOrder,
+			Description:    item.Description,
+		}
+		// 使用 Select 保证 allows_subtasks=false 能写入（避免被数据库默认值覆盖）
+		if err := tx.Select("*").Omit("id").Create(&status).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, fmt.Errorf("创建状态 %s 失败: %v", item.Code, err)
+		}
+	}
+
+	// 2. 删除不再使用的状态
+	if err := tx.Where("task_type_code = ? AND code NOT IN ?", code, keepCodes).
+		Delete(&models.TaskStatus{}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, fmt.Errorf("删除状态失败: %v", err)
+	}
+
+	// 3. 替换转换规则
+	if err := tx.Where("task_type_code = ?", code).Delete(&models.TaskStatusTransition{}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, fmt.Errorf("删除转换规则失败: %v", err)
+	}
+	for _, item := range req.Transitions {
+		transition := models.TaskStatusTransition{
+			TaskTypeCode:     code,
+			FromStatusCode:   item.FromStatusCode,
+			ToStatusCode:     item.ToStatusCode,
+			RequiresApproval: item.RequiresApproval,
+			IsAllowed:        item.IsAllowed == nil || *item.IsAllowed,
+			Guards:           datatypes.NewJSONSlice(item.Guards),
+			PostActions:      datatypes.NewJSONSlice(DefaultTransitionActions),
+			Description:      item.Description,
+		}
+		if item.PostActions != nil {
+			transition.PostActions = datatypes.NewJSONSlice(item.PostActions)
+		}
+		if transition.Guards == nil {
+			transition.Guards = datatypes.JSONSlice[string]{}
+		}
+		if item.RequiredRole != "" {
+			role := item.RequiredRole
+			transition.RequiredRole = &role
+		}
+		// 使用 Select 保证 is_allowed=false 能写入（避免被数据库默认值覆盖）
+		if err := tx.Select("*").Omit("id").Create(&transition).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, fmt.Errorf("创建转换规则失败: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, nil, err
+	}
+
+	workflow, err := s.GetWorkflow(code)
+	return workflow, nil, err
+}
+
+// appendStorageErrors 追加需要查询数据库的校验：状态编码被其他类型占用、被删除的状态仍有任务使用
+func (s *WorkflowService) appendStorageErrors(result *dto.WorkflowValidationResult, code string, req *dto.WorkflowRequest) {
+	codes := make([]string, 0, len(req.Statuses))
+	for _, item := range req.Statuses {
+		codes = append(codes, item.Code)
+	}
+
+	var conflicts []models.TaskStatus
+	database.DB.Where("code IN ? AND (task_type_code IS NULL OR task_type_code <> ?)", codes, code).Find(&conflicts)
+	for _, st := range conflicts {
+		result.Errors = append(result.Errors, fmt.Sprintf("状态编码 %s 已被任务类型 %s 使用", st.Code, st.TaskTypeCode))
+	}
+
+	var inUse []string
+	database.DB.Model(&models.Task{}).
+		Where("task_type_code = ? AND status_code NOT IN ?", code, codes).
+		Distinct("status_code").
+		Pluck("status_code", &inUse)
+	for _, statusCode := range inUse {
+		result.Errors = append(result.Errors, fmt.Sprintf("状态 %s 仍有任务使用，不能删除", statusCode))
+	}
+
+	result.Valid = len(result.Errors) == 0
+}
+
+// ValidateWorkflowGraph 校验流程图（纯计算，不访问数据库）
+// 规则：状态编码合法且不重复；至少一个初始状态和一个完成分类的状态；
+// 转换规则引用的状态都存在且不重复；类型配置的入口状态都存在；
+// 所有状态都能从初始状态经允许的转换到达
+func ValidateWorkflowGraph(taskType *models.TaskType, req *dto.WorkflowRequest) dto.WorkflowValidationResult {
+	result := dto.WorkflowValidationResult{
+		Errors:              []string{},
+		UnreachableStatuses: []string{},
+	}
+
+	statusSet := make(map[string]bool, len(req.Statuses))
+	var initials []string
+	hasDone := false
+	for _, item := range req.Statuses {
+		if !workflowCodePattern.MatchString(item.Code) {
+			result.Errors = append(result.Errors, fmt.Sprintf("状态编码 %s 不合法（只能包含小写字母、数字和下划线，且以字母开头）", item.Code))
+		}
+		if statusSet[item.Code] {
+			result.Errors = append(result.Errors, fmt.Sprintf("状态编码 %s 重复", item.Code))
+		}
+		statusSet[item.Code] = true
+		if item.IsInitial {
+			initials = append(initials, item.Code)
+		}
+		if item.Category == models.TaskStatusCategoryDone {
+			hasDone = true
+		}
+	}
+	if len(initials) == 0 {
+		result.Errors = append(result.Errors, "至少需要一个初始状态")
+	}
+	if !hasDone {
+		result.Errors = append(result.Errors, "至少需要一个已完成（done）分类的状态")
+	}
+
+	entries := []struct {
+		label string
+		code  string
+	}{
+		{"未指派状态", taskType.PoolStatusCode},
+		{"待接受状态", taskType.AssignedStatusCode},
+		{"已接受状态", taskType.AcceptedStatusCode},
+	}
+	for _, entry := range entries {
+		if entry.code != "" && !statusSet[entry.code] {
+			result.Errors = append(result.Errors, fmt.Sprintf("任务类型配置的%s %s 不在状态列表中", entry.label, entry.code))
+		}
+	}
+
+	// 构建邻接表
+	edges := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, item := range req.Transitions {
+		key := item.FromStatusCode + "->" + item.ToStatusCode
+		if seen[key] {
+			result.Errors = append(result.Errors, fmt.Sprintf("转换规则 %s 重复", key))
+		}
+		seen[key] = true
+		if item.FromStatusCode == item.ToStatusCode {
+			result.Errors = append(result.Errors, fmt.Sprintf("转换规则 %s 的源状态和目标状态相同", key))
+		}
+		if !statusSet[item.FromStatusCode] {
+			result.Errors = append(result.Errors, fmt.Sprintf("转换规则 %s 的源状态不存在", key))
+		}
+		if !statusSet[item.ToStatusCode] {
+			result.Errors = append(result.Errors, fmt.Sprintf("转换规则 %s 的目标状态不存在", key))
+		}
+		if err := ValidateTransitionHookNames(item.Guards, item.PostActions); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("转换规则 %s: %v", key, err))
+		}
+		if item.IsAllowed == nil || *item.IsAllowed {
+			edges[item.FromStatusCode] = append(edges[item.FromStatusCode], item.ToStatusCode)
+		}
+	}
+
+	// 从初始状态广度优先遍历
+	reached := make(map[string]bool)
+	queue := append([]string{}, initials...)
+	for _, code := range initials {
+		reached[code] = true
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[current] {
+			if !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	for _, item := range req.Statuses {
+		if !reached[item.Code] {
+			result.UnreachableStatuses = append(result.UnreachableStatuses, item.Code)
+		}
+	}
+	if len(result.UnreachableStatuses) > 0 {
+		sort.Strings(result.UnreachableStatuses)
+		result.Errors = append(result.Errors, fmt.Sprintf("以下状态从初始状态不可达: %v", result.UnreachableStatuses))
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// GetTransitionHooks 获取已注册的守卫和后置动作（供流程设计器选择）
+func (s *WorkflowService) GetTransitionHooks() *dto.TransitionHooksResponse {
+	resp := &dto.TransitionHooksResponse{
+		Guards:             []dto.TransitionHookItem{},
+		PostActions:        []dto.TransitionHookItem{},
+		DefaultPostActions: DefaultTransitionActions,
+	}
+	for _, guard := range ListTransitionGuards() {
+		resp.Guards = append(resp.Guards, dto.TransitionHookItem{Name: guard.Name, Description: guard.Description})
+	}
+	for _, action := range ListTransitionActions() {
+		resp.PostActions = append(resp.PostActions, dto.TransitionHookItem{Name: action.Name, Description: action.Description})
+	}
+	return resp
+}
+
+// toTaskTypeDetailResponse 转换任务类型响应
+func (s *WorkflowService) toTaskTypeDetailResponse(taskType *models.TaskType) dto.TaskTypeDetailResponse {
+	resp := dto.TaskTypeDetailResponse{
+		ID:                 taskType.ID,
+		Code:               taskType.Code,
+		Name:               taskType.Name,
+		Description:        taskType.Description,
+		PoolStatusCode:     taskType.PoolStatusCode,
+		AssignedStatusCode: taskType.AssignedStatusCode,
+		AcceptedStatusCode: taskType.AcceptedStatusCode,
+		CreatedAt:          dto.ToResponseTime(taskType.CreatedAt),
+	}
+	database.DB.Model(&models.TaskStatus{}).Where("task_type_code = ?", taskType.Code).Count(&resp.StatusCount)
+	database.DB.Model(&models.TaskStatusTransition{}).Where("task_type_code = ?", taskType.Code).Count(&resp.TransitionCount)
+	return resp
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalChainTemplateMatches(t *testing.T) {
+	four := 4
+	template := &models.ApprovalChainTemplate{
+		ReviewType:           "plan_review",
+		TaskTypeCode:         "requirement",
+		MinPriority:          &four,
+		MatchCrossDepartment: true,
+		IsActive:             true,
+	}
+
+	urgent := &models.Task{TaskTypeCode: "requirement", Priority: 4}
+	crossDept := &models.Task{TaskTypeCode: "requirement", Priority: 2, IsCrossDepartment: true}
+	normal := &models.Task{TaskTypeCode: "requirement", Priority: 2}
+
+	// 优先级和跨部门满足其一即可；plan_review 同时适用于 execution_plan_review
+	assert.True(t, approvalChainTemplateMatches(template, urgent, "execution_plan_review"))
+	assert.True(t, approvalChainTemplateMatches(template, crossDept, "plan_review"))
+	assert.False(t, approvalChainTemplateMatches(template, normal, "plan_review"))
+	assert.False(t, approvalChainTemplateMatches(template, urgent, "solution_review"))
+	assert.False(t, approvalChainTemplateMatches(template, &models.Task{TaskTypeCode: "unit_task", Priority: 4}, "plan_review"))
+
+	// 未设置触发条件时匹配该类型的全部任务
+	all := &models.ApprovalChainTemplate{ReviewType: "solution_review", IsActive: true}
+	assert.True(t, approvalChainTemplateMatches(all, normal, "solution_review"))
+
+	template.IsActive = false
+	assert.False(t, approvalChainTemplateMatches(template, urgent, "plan_review"))
+}
+
+func TestSelectApprovalChainTemplate(t *testing.T) {
+	stages := []models.ApprovalChainStage{{StageNo: 1, Name: "创建人审批", ApproverType: models.ApproverTypeCreator}}
+	four := 4
+	templates := []models.ApprovalChainTemplate{
+		{BaseModel: models.BaseModel{ID: 1}, ReviewType: "solution_review", IsActive: true, Stages: stages},
+		{BaseModel: models.BaseModel{ID: 2}, ReviewType: "solution_review", MinPriority: &four, SortOrder: 10, IsActive: true, Stages: stages},
+		{BaseModel: models.BaseModel{ID: 3}, ReviewType: "solution_review", MatchCrossDepartment: true, SortOrder: 10, IsActive: true, Stages: stages},
+		// 没有阶段的模板不参与匹配
+		{BaseModel: models.BaseModel{ID: 4}, ReviewType: "solution_review", SortOrder: 99, IsActive: true},
+	}
+
+	selected := selectApprovalChainTemplate(templates, &models.Task{Priority: 4}, "solution_review")
+	assert.Equal(t, uint(2), selected.ID)
+
+	// 匹配顺序相同时取后创建的
+	selected = selectApprovalChainTemplate(templates, &models.Task{Priority: 4, IsCrossDepartment: true}, "solution_review")
+	assert.Equal(t, uint(3), selected.ID)
+
+	selected = selectApprovalChainTemplate(templates, &models.Task{Priority: 1}, "solution_review")
+	assert.Equal(t, uint(1), selected.ID)
+
+	assert.Nil(t, selectApprovalChainTemplate(templates, &models.Task{Priority: 4}, "plan_review"))
+}
+
+func TestBuildApprovalChainStages(t *testing.T) {
+	deptID, userID := uint(3), uint(7)
+	stages := buildApprovalChainStages(5, []dto.ApprovalChainStageItem{
+		{Name: "执行人部门负责人", ApproverType: models.ApproverTypeExecutorLeader, ApproverUserID: &userID},
+		{Name: "指定部门负责人", ApproverType: models.ApproverTypeDepartmentLeader, ApproverDepartmentID: &deptID},
+		{Name: "创建人", ApproverType: models.ApproverTypeCreator},
+	})
+
+	assert.Len(t, stages, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{stages[0].StageNo, stages[1].StageNo, stages[2].StageNo})
+	assert.Equal(t, uint(5), stages[0].TemplateID)
+	// 只保留审批人类型对应的部门/用户ID
+	assert.Nil(t, stages[0].ApproverUserID)
+	assert.Equal(t, &deptID, stages[1].ApproverDepartmentID)
+
+	same := buildApprovalChainStages(5, []dto.ApprovalChainStageItem{
+		{Name: "执行人部门负责人", ApproverType: models.ApproverTypeExecutorLeader},
+		{Name: "指定部门负责人", ApproverType: models.ApproverTypeDepartmentLeader, ApproverDepartmentID: &deptID},
+		{Name: "创建人", ApproverType: models.ApproverTypeCreator},
+	})
+	assert.True(t, approvalChainStagesEqual(stages, same))
+	assert.False(t, approvalChainStagesEqual(stages, same[:2]))
+
+	otherDept := uint(4)
+	same[1].ApproverDepartmentID = &otherDept
+	assert.False(t, approvalChainStagesEqual(stages, same))
+}
+
+func TestCheckApprovalChainStages(t *testing.T) {
+	deptID := uint(3)
+	assert.NoError(t, checkApprovalChainStages([]dto.ApprovalChainStageItem{
+		{Name: "部门负责人", ApproverType: models.ApproverTypeDepartmentLeader, ApproverDepartmentID: &deptID},
+		{Name: "创建人", ApproverType: models.ApproverTypeCreator},
+	}))
+	assert.Error(t, checkApprovalChainStages([]dto.ApprovalChainStageItem{
+		{Name: "部门负责人", ApproverType: models.ApproverTypeDepartmentLeader},
+	}))
+	assert.Error(t, checkApprovalChainStages([]dto.ApprovalChainStageItem{
+		{Name: "指定用户", ApproverType: models.ApproverTypeUser},
+	}))
+	assert.Error(t, checkApprovalChainStages([]dto.ApprovalChainStageItem{
+		{Name: "未知", ApproverType: "manager"},
+	}))
+}
+
+func TestDescribeSubmissionReview(t *testing.T) {
+	assert.Equal(t, "自动发起单人审核", describeSubmissionReview(&models.ReviewSession{}))
+	assert.Equal(t, "自动发起审批链审核（第 1 阶段：执行人部门负责人）",
+		describeSubmissionReview(&models.ReviewSession{ChainStageNo: 1, ChainStageName: "执行人部门负责人"}))
+}
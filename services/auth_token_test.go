@@ -0,0 +1,66 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/utils"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenLifetimes(t *testing.T) {
+	access, refresh := tokenLifetimes(config.JWTConfig{AccessExpireMinutes: 15, RefreshExpireHours: 24})
+	assert.Equal(t, 15*time.Minute, access)
+	assert.Equal(t, 24*time.Hour, refresh)
+
+	// 未配置时使用默认值
+	access, refresh = tokenLifetimes(config.JWTConfig{})
+	assert.Equal(t, defaultAccessTokenMinutes*time.Minute, access)
+	assert.Equal(t, defaultRefreshTokenHours*time.Hour, refresh)
+}
+
+func TestRefreshTokenValue(t *testing.T) {
+	a, err := newRefreshTokenValue()
+	assert.NoError(t, err)
+	b, err := newRefreshTokenValue()
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, b)
+	assert.Len(t, a, 43)
+
+	// 哈希稳定且不含原文
+	assert.Equal(t, hashRefreshToken(a), hashRefreshToken(a))
+	assert.NotEqual(t, hashRefreshToken(a), hashRefreshToken(b))
+	assert.Len(t, hashRefreshToken(a), 64)
+	assert.NotContains(t, hashRefreshToken(a), a)
+}
+
+func TestParseToken_AccessTokenOnly(t *testing.T) {
+	utils.SetJWTSecret(config.GetConfig().JWT.Secret)
+
+	access, err := utils.GenerateAccessToken(7, "张三", "13800000001", 3, "token-1", time.Minute)
+	assert.NoError(t, err)
+	claims, err := utils.ParseToken(access)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint(7), claims.UserID)
+		assert.Equal(t, uint(3), claims.SessionID)
+	}
+
+	// 其他签发方的令牌（任务专用 Token）不能当作访问令牌
+	taskToken, err := utils.GenerateTaskToken(1, 1)
+	assert.NoError(t, err)
+	_, err = utils.ParseToken(taskToken)
+	assert.Error(t, err)
+
+	// 只接受 HS256
+	other := jwt.NewWithClaims(jwt.SigningMethodHS512, utils.Claims{
+		UserID:           7,
+		SessionID:        3,
+		RegisteredClaims: jwt.RegisteredClaims{ID: "token-2", Issuer: "RHPRo-Task"},
+	})
+	signed, err := other.SignedString([]byte(config.GetConfig().JWT.Secret))
+	assert.NoError(t, err)
+	_, err = utils.ParseToken(signed)
+	assert.Error(t, err)
+}
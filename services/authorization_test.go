@@ -0,0 +1,72 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// departmentTree 测试用部门树：部门ID -> 上级部门ID（0 表示顶级部门）
+func departmentTree(parents map[uint]uint) func(uint) (*uint, bool) {
+	return func(id uint) (*uint, bool) {
+		parent, ok := parents[id]
+		if !ok {
+			return nil, false
+		}
+		if parent == 0 {
+			return nil, true
+		}
+		return &parent, true
+	}
+}
+
+func TestDepartmentWithinScope(t *testing.T) {
+	// 1 总部 -> 2 研发中心 -> 3 后端组、4 前端组；5 销售部
+	parentOf := departmentTree(map[uint]uint{1: 0, 2: 1, 3: 2, 4: 2, 5: 1})
+	managed := map[uint]bool{2: true}
+
+	assert.True(t, departmentWithinScope(2, managed, parentOf), "负责的部门")
+	assert.True(t, departmentWithinScope(3, managed, parentOf), "负责部门的下级部门")
+	assert.True(t, departmentWithinScope(4, managed, parentOf), "负责部门的下级部门")
+	assert.False(t, departmentWithinScope(1, managed, parentOf), "上级部门")
+	assert.False(t, departmentWithinScope(5, managed, parentOf), "同级的其他部门")
+	assert.False(t, departmentWithinScope(99, managed, parentOf), "不存在的部门")
+	assert.False(t, departmentWithinScope(3, map[uint]bool{}, parentOf), "不负责任何部门")
+}
+
+func TestDepartmentWithinScope_Cycle(t *testing.T) {
+	// 部门数据成环时不能死循环
+	parentOf := departmentTree(map[uint]uint{1: 2, 2: 3, 3: 1})
+	assert.False(t, departmentWithinScope(1, map[uint]bool{9: true}, parentOf))
+	assert.True(t, departmentWithinScope(1, map[uint]bool{3: true}, parentOf))
+}
+
+func TestCollectUserPermissions(t *testing.T) {
+	user := &models.User{Roles: []*models.Role{
+		{Name: "manager", Permissions: []*models.Permission{{Name: "dept:manage"}, {Name: "task:read"}}},
+		{Name: "user", Permissions: []*models.Permission{{Name: "task:read"}, {Name: "review:submit"}}},
+	}}
+
+	assert.Equal(t, []string{"dept:manage", "task:read", "review:submit"}, collectUserPermissions(user))
+	assert.True(t, userHasPermission(user, "review:submit"))
+	assert.False(t, userHasPermission(user, "dept:delete"))
+	assert.False(t, isAdminUser(user))
+
+	user.Roles = append(user.Roles, &models.Role{Name: RoleAdmin})
+	assert.True(t, isAdminUser(user))
+	assert.Empty(t, collectUserPermissions(&models.User{}))
+}
+
+func TestRoleChangeGrantable(t *testing.T) {
+	// 操作人拥有角色 2、3
+	held := []uint{2, 3}
+
+	assert.True(t, roleChangeGrantable(held, []uint{3}, []uint{2, 3}), "分配自己拥有的角色")
+	assert.True(t, roleChangeGrantable(held, []uint{1, 3}, []uint{1}), "保留目标用户已有的其他角色")
+	assert.True(t, roleChangeGrantable(held, []uint{2}, nil), "移除自己拥有的角色")
+	assert.False(t, roleChangeGrantable(held, []uint{3}, []uint{1, 3}), "分配自己没有的角色")
+	assert.False(t, roleChangeGrantable(held, []uint{1, 3}, []uint{3}), "移除自己没有的角色")
+
+	assert.Equal(t, []uint{2, 3}, roleIDsOf([]*models.Role{{BaseModel: models.BaseModel{ID: 2}}, {BaseModel: models.BaseModel{ID: 3}}}))
+}
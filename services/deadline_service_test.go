@@ -0,0 +1,57 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDeadlineDue(t *testing.T) {
+	startedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	cfg := config.TaskConfig{ExecutionPlanDeadlineHours: 48}
+
+	days := 3
+	due, ok := computeDeadlineDue(models.TaskDeadlineKindSolution, &models.Task{SolutionDeadline: &days}, cfg, startedAt)
+	assert.True(t, ok)
+	assert.Equal(t, startedAt.AddDate(0, 0, 3), due)
+
+	// 未设置或为0表示不限制
+	_, ok = computeDeadlineDue(models.TaskDeadlineKindSolution, &models.Task{}, cfg, startedAt)
+	assert.False(t, ok)
+	zero := 0
+	_, ok = computeDeadlineDue(models.TaskDeadlineKindSolution, &models.Task{SolutionDeadline: &zero}, cfg, startedAt)
+	assert.False(t, ok)
+
+	due, ok = computeDeadlineDue(models.TaskDeadlineKindExecutionPlan, &models.Task{}, cfg, startedAt)
+	assert.True(t, ok)
+	assert.Equal(t, startedAt.Add(48*time.Hour), due)
+
+	_, ok = computeDeadlineDue(models.TaskDeadlineKindExecutionPlan, &models.Task{}, config.TaskConfig{}, startedAt)
+	assert.False(t, ok)
+}
+
+func TestOverdueStatusCode(t *testing.T) {
+	cfg := config.TaskConfig{SolutionOverdueStatusCode: "req_blocked"}
+	assert.Equal(t, "req_blocked", overdueStatusCode(models.TaskDeadlineKindSolution, cfg))
+	assert.Equal(t, "", overdueStatusCode(models.TaskDeadlineKindExecutionPlan, cfg))
+}
+
+func TestToTaskDeadlineResponse(t *testing.T) {
+	now := time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)
+	deadline := &models.TaskDeadline{
+		Kind:      models.TaskDeadlineKindSolution,
+		StartedAt: now.Add(-24 * time.Hour),
+		DueAt:     now.Add(90 * time.Minute),
+		Status:    models.TaskDeadlineStatusActive,
+	}
+	resp := toTaskDeadlineResponse(deadline, now)
+	assert.Equal(t, int64(5400), resp.RemainingSeconds)
+	assert.False(t, resp.Overdue)
+
+	resp = toTaskDeadlineResponse(deadline, now.Add(2*time.Hour))
+	assert.Equal(t, int64(-1800), resp.RemainingSeconds)
+	assert.True(t, resp.Overdue)
+}
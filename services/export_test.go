@@ -0,0 +1,11 @@
+package services
+
+// 供外部测试包（services_test）使用的内部函数
+var (
+	GenerateTwoFactorToken = generateTwoFactorToken
+)
+
+// GenerateOidcBindToken 签发指定提供方和外部身份的绑定手机号临时凭证
+func GenerateOidcBindToken(provider, subject string) (string, error) {
+	return generateOidcBindToken(&oidcIdentity{Provider: provider, Subject: subject})
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubLdapEntry 内存目录中的条目
+type stubLdapEntry struct {
+	dn       string
+	password string
+	attrs    map[string]string
+}
+
+// stubLdapServer 进程内的 LDAP 服务（只实现 Bind、Search 和 Unbind，过滤条件只识别 uid 等值匹配）
+type stubLdapServer struct {
+	listener net.Listener
+	entries  []stubLdapEntry
+	wg       sync.WaitGroup
+}
+
+var stubUIDFilter = regexp.MustCompile(`\(uid=([^)]*)\)`)
+
+func newStubLdapServer(t *testing.T, entries []stubLdapEntry) *stubLdapServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &stubLdapServer{listener: listener, entries: entries}
+	server.wg.Add(1)
+	go server.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		server.wg.Wait()
+	})
+	return server
+}
+
+func (s *stubLdapServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *stubLdapServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *stubLdapServer) handle(conn net.Conn) {
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := op.Children[1].Value.(string)
+			password := op.Children[2].Data.String()
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			for _, entry := range s.entries {
+				if strings.EqualFold(entry.dn, dn) && entry.password != "" && entry.password == password {
+					code = ldap.LDAPResultSuccess
+				}
+			}
+			s.reply(conn, messageID, stubLdapResult(ldap.ApplicationBindResponse, code))
+		case ldap.ApplicationSearchRequest:
+			filter, _ := ldap.DecompileFilter(op.Children[6])
+			match := stubUIDFilter.FindStringSubmatch(filter)
+			for _, entry := range s.entries {
+				if entry.attrs == nil || (match != nil && entry.attrs["uid"] != match[1]) {
+					continue
+				}
+				s.reply(conn, messageID, stubSearchEntry(entry))
+			}
+			s.reply(conn, messageID, stubLdapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			return
+		}
+	}
+}
+
+func (s *stubLdapServer) reply(conn net.Conn, messageID int64, op *ber.Packet) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	envelope.AppendChild(op)
+	conn.Write(envelope.Bytes())
+}
+
+func stubLdapResult(tag ber.Tag, code uint16) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "ResultCode"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "MatchedDN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic"))
+	return op
+}
+
+func stubSearchEntry(entry stubLdapEntry) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.dn, "DN"))
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, value := range entry.attrs {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		attr.AppendChild(values)
+		attrs.AppendChild(attr)
+	}
+	op.AppendChild(attrs)
+	return op
+}
+
+func newStubDirectory(t *testing.T) *ldapDirectory {
+	server := newStubLdapServer(t, []stubLdapEntry{
+		{dn: "cn=admin,dc=example,dc=com", password: "admin-secret"},
+		{
+			dn:       "uid=zhangsan,ou=研发组,ou=技术部,dc=example,dc=com",
+			password: "zs-pass",
+			attrs: map[string]string{
+				"uid": "zhangsan", "entryUUID": "uuid-zhangsan", "cn": "张三",
+				"mobile": "13800000001", "mail": "zhangsan@example.com", "title": "工程师",
+				"manager": "uid=lisi,ou=技术部,dc=example,dc=com",
+			},
+		},
+		{
+			dn:       "uid=lisi,ou=技术部,dc=example,dc=com",
+			password: "ls-pass",
+			attrs: map[string]string{
+				"uid": "lisi", "entryUUID": "uuid-lisi", "cn": "李四", "mobile": "13800000002",
+			},
+		},
+	})
+
+	return &ldapDirectory{cfg: config.LDAPConfig{
+		URL:            server.url(),
+		BindDN:         "cn=admin,dc=example,dc=com",
+		BindPassword:   "admin-secret",
+		BaseDN:         "dc=example,dc=com",
+		UserFilter:     "(objectClass=inetOrgPerson)",
+		LoginAttr:      "uid",
+		UIDAttr:        "entryUUID",
+		NameAttr:       "cn",
+		MobileAttr:     "mobile",
+		EmailAttr:      "mail",
+		TitleAttr:      "title",
+		ManagerAttr:    "manager",
+		TimeoutSeconds: 5,
+	}}
+}
+
+func TestLdapDirectoryAuthenticate(t *testing.T) {
+	directory := newStubDirectory(t)
+
+	entry, err := directory.Authenticate("zhangsan", "zs-pass")
+	require.NoError(t, err)
+	assert.Equal(t, "uid=zhangsan,ou=研发组,ou=技术部,dc=example,dc=com", entry.DN)
+	assert.Equal(t, "uuid-zhangsan", entry.ExternalID)
+	assert.Equal(t, "zhangsan", entry.Login)
+	assert.Equal(t, "张三", entry.Name)
+	assert.Equal(t, "13800000001", entry.Mobile)
+	assert.Equal(t, "zhangsan@example.com", entry.Email)
+	assert.Equal(t, "工程师", entry.Title)
+	assert.Equal(t, "uid=lisi,ou=技术部,dc=example,dc=com", entry.ManagerDN)
+
+	_, err = directory.Authenticate("zhangsan", "wrong")
+	assert.ErrorIs(t, err, errLdapInvalidCredentials)
+
+	// 空密码不能以匿名绑定的方式通过
+	_, err = directory.Authenticate("zhangsan", "")
+	assert.ErrorIs(t, err, errLdapInvalidCredentials)
+
+	_, err = directory.Authenticate("wangwu", "zs-pass")
+	assert.ErrorIs(t, err, errLdapInvalidCredentials)
+}
+
+func TestLdapDirectoryServiceBindFailure(t *testing.T) {
+	directory := newStubDirectory(t)
+	directory.cfg.BindPassword = "wrong"
+
+	_, err := directory.Authenticate("zhangsan", "zs-pass")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errLdapInvalidCredentials)
+}
+
+func TestLdapDirectorySearchUsers(t *testing.T) {
+	directory := newStubDirectory(t)
+
+	entries, err := directory.SearchUsers()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	byLogin := map[string]LdapEntry{}
+	for _, entry := range entries {
+		byLogin[entry.Login] = entry
+	}
+	assert.Equal(t, "uuid-lisi", byLogin["lisi"].ExternalID)
+	assert.Equal(t, "李四", byLogin["lisi"].Name)
+	assert.Empty(t, byLogin["lisi"].ManagerDN)
+	assert.Equal(t, "13800000001", byLogin["zhangsan"].Mobile)
+}
+
+func TestOUChain(t *testing.T) {
+	chain := ouChain("uid=zhangsan,ou=研发组,ou=技术部,dc=example,dc=com", "DC=Example,DC=com")
+	require.Len(t, chain, 2)
+	assert.Equal(t, ldapOU{DN: "ou=技术部,dc=example,dc=com", Name: "技术部"}, chain[0])
+	assert.Equal(t, ldapOU{DN: "ou=研发组,ou=技术部,dc=example,dc=com", Name: "研发组"}, chain[1])
+
+	// 非 OU 容器跳过
+	chain = ouChain("CN=Zhang San,CN=Users,OU=Sales,DC=corp,DC=local", "dc=corp,dc=local")
+	require.Len(t, chain, 1)
+	assert.Equal(t, "ou=sales,dc=corp,dc=local", chain[0].DN)
+	assert.Equal(t, "Sales", chain[0].Name)
+
+	assert.Empty(t, ouChain("uid=root,dc=example,dc=com", "dc=example,dc=com"))
+	assert.Empty(t, ouChain("uid=x,ou=a,dc=other,dc=com", "dc=example,dc=com"))
+	assert.Empty(t, ouChain("not a dn", "dc=example,dc=com"))
+}
+
+func TestNormalizeDN(t *testing.T) {
+	assert.Equal(t, "uid=zhangsan,ou=tech,dc=example,dc=com", normalizeDN("UID=ZhangSan, OU=Tech, DC=example,DC=com"))
+	assert.Equal(t, normalizeDN("cn=Li\\, Si,dc=example,dc=com"), normalizeDN("CN=li\\2c si,DC=Example,DC=com"))
+}
+
+func TestResolveDepartmentLeaders(t *testing.T) {
+	base := "dc=example,dc=com"
+	entries := []LdapEntry{
+		// 技术部经理
+		{DN: "uid=cto,ou=技术部,dc=example,dc=com"},
+		// 技术部成员，上级为部门经理
+		{DN: "uid=a,ou=技术部,dc=example,dc=com", ManagerDN: "uid=cto,ou=技术部,dc=example,dc=com"},
+		// 研发组组长：在研发组内有下属
+		{DN: "uid=lead,ou=研发组,ou=技术部,dc=example,dc=com", ManagerDN: "UID=cto,OU=技术部,DC=example,DC=com"},
+		{DN: "uid=b,ou=研发组,ou=技术部,dc=example,dc=com", ManagerDN: "uid=lead,ou=研发组,ou=技术部,dc=example,dc=com"},
+		// 测试组没有组长，成员直接向技术部经理汇报
+		{DN: "uid=c,ou=测试组,ou=技术部,dc=example,dc=com", ManagerDN: "uid=cto,ou=技术部,dc=example,dc=com"},
+		// 上级不在同步范围内
+		{DN: "uid=d,ou=销售部,dc=example,dc=com", ManagerDN: "uid=ceo,dc=example,dc=com"},
+	}
+
+	leaders := resolveDepartmentLeaders(entries, base)
+	assert.Equal(t, []string{"uid=cto,ou=技术部,dc=example,dc=com"}, leaders["ou=技术部,dc=example,dc=com"])
+	assert.Equal(t, []string{"uid=lead,ou=研发组,ou=技术部,dc=example,dc=com"}, leaders["ou=研发组,ou=技术部,dc=example,dc=com"])
+	assert.Equal(t, []string{"uid=cto,ou=技术部,dc=example,dc=com"}, leaders["ou=测试组,ou=技术部,dc=example,dc=com"])
+	assert.NotContains(t, leaders, "ou=销售部,dc=example,dc=com")
+	assert.Len(t, leaders, 3)
+}
+
+func TestLdapLoginAccount(t *testing.T) {
+	// 登录名不区分大小写，且与手机号账号键不冲突
+	assert.Equal(t, "ldap:zhangsan", ldapLoginAccount("ZhangSan"))
+	assert.Equal(t, ldapLoginAccount("zhangsan"), ldapLoginAccount("ZHANGSAN"))
+	assert.NotEqual(t, loginLockAccountKey("13800000001"), loginLockAccountKey(ldapLoginAccount("13800000001")))
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"RHPRo-Task/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatRetryAfter(t *testing.T) {
+	assert.Equal(t, " 1 秒", formatRetryAfter(0))
+	assert.Equal(t, " 1 秒", formatRetryAfter(200*time.Millisecond))
+	assert.Equal(t, " 45 秒", formatRetryAfter(45*time.Second))
+	assert.Equal(t, " 60 秒", formatRetryAfter(time.Minute))
+	assert.Equal(t, " 2 分钟", formatRetryAfter(61*time.Second))
+	assert.Equal(t, " 30 分钟", formatRetryAfter(30*time.Minute))
+}
+
+func TestLoginLockedError(t *testing.T) {
+	err := &LoginLockedError{RetryAfter: 90 * time.Second}
+	assert.Equal(t, "登录失败次数过多，请 2 分钟后重试", err.Error())
+}
+
+func TestCheckLoginLock(t *testing.T) {
+	store := ratelimit.GetStore()
+	mobile, ip := "13900000046", "203.0.113.46"
+	defer store.Delete(loginLockAccountKey(mobile), loginLockIPKey(ip))
+
+	assert.NoError(t, checkLoginLock(mobile, ip))
+
+	// 账号被锁定
+	assert.NoError(t, store.Set(loginLockAccountKey(mobile), time.Minute))
+	err := checkLoginLock(mobile, ip)
+	locked, ok := err.(*LoginLockedError)
+	if assert.True(t, ok) {
+		assert.True(t, locked.RetryAfter > 0 && locked.RetryAfter <= time.Minute)
+	}
+
+	// 仅 IP 被锁定时，同一 IP 登录其他账号也被拒绝
+	assert.NoError(t, store.Delete(loginLockAccountKey(mobile)))
+	assert.NoError(t, store.Set(loginLockIPKey(ip), time.Minute))
+	assert.Error(t, checkLoginLock("13900000047", ip))
+	assert.NoError(t, checkLoginLock(mobile, "203.0.113.47"))
+}
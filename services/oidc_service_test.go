@@ -0,0 +1,287 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/utils"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIdP 本地模拟的身份提供方：发现和 JWKS 由 oidctest 提供，令牌端点校验 PKCE 后签发 ID Token
+type mockIdP struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+
+	mu    sync.Mutex
+	codes map[string]mockAuthorization
+}
+
+// mockAuthorization 用户在身份提供方完成登录后的授权记录
+type mockAuthorization struct {
+	challenge string
+	claims    map[string]interface{}
+}
+
+func newMockIdP(t *testing.T, clientID string) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, clientID: clientID, codes: map[string]mockAuthorization{}}
+	discovery := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: "test-key", Algorithm: oidc.RS256}},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", idp.serveToken)
+	mux.Handle("/", discovery)
+	idp.server = httptest.NewServer(mux)
+	discovery.SetIssuer(idp.server.URL)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// login 模拟用户在授权页面登录：记录 PKCE challenge 和 nonce，返回授权码
+func (idp *mockIdP) login(t *testing.T, authorizationURL string, claims map[string]interface{}) string {
+	parsed, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.Equal(t, idp.clientID, query.Get("client_id"))
+	require.Equal(t, "code", query.Get("response_type"))
+
+	full := map[string]interface{}{
+		"iss":   idp.server.URL,
+		"aud":   idp.clientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for k, v := range claims {
+		full[k] = v
+	}
+
+	code := uuid.NewString()
+	idp.mu.Lock()
+	idp.codes[code] = mockAuthorization{challenge: query.Get("code_challenge"), claims: full}
+	idp.mu.Unlock()
+	return code
+}
+
+func (idp *mockIdP) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "authorization_code" {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+	idp.mu.Lock()
+	auth, ok := idp.codes[r.Form.Get("code")]
+	delete(idp.codes, r.Form.Get("code"))
+	idp.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims, _ := json.Marshal(auth.claims)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(idp.key, "test-key", oidc.RS256, string(claims)),
+	})
+}
+
+// useOidcProviders 测试期间替换身份提供方配置
+func useOidcProviders(t *testing.T, providers ...config.OIDCProviderConfig) {
+	if utils.Logger == nil {
+		utils.InitLogger()
+	}
+	cfg := config.GetConfig()
+	previous := cfg.OIDC.Providers
+	cfg.OIDC.Providers = providers
+	t.Cleanup(func() { cfg.OIDC.Providers = previous })
+}
+
+func newMockOidcProvider(idp *mockIdP, name string) config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Name:            name,
+		DisplayName:     "企业统一身份认证",
+		IssuerURL:       idp.server.URL,
+		ClientID:        idp.clientID,
+		ClientSecret:    "secret",
+		RedirectURL:     "https://task.example.com/sso/callback",
+		Scopes:          []string{"profile", "email", "phone"},
+		MobileClaim:     "phone_number",
+		EmailClaim:      "email",
+		NameClaim:       "name",
+		DepartmentClaim: "department",
+	}
+}
+
+func TestOidcAuthenticate(t *testing.T) {
+	idp := newMockIdP(t, "rhpro-task")
+	useOidcProviders(t, newMockOidcProvider(idp, "corp"))
+	s := &OidcService{}
+
+	assert.Equal(t, "corp", s.GetProviders()[0].Name)
+
+	auth, err := s.Authorize("corp")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(auth.AuthorizationURL, idp.server.URL+"/auth?"))
+	assert.Contains(t, auth.AuthorizationURL, "scope=openid+profile+email+phone")
+
+	code := idp.login(t, auth.AuthorizationURL, map[string]interface{}{
+		"sub":                   "user-001",
+		"name":                  "张三",
+		"email":                 "ZhangSan@Example.com",
+		"email_verified":        true,
+		"phone_number":          "+86 138-0000-0001",
+		"phone_number_verified": true,
+		"department":            []interface{}{"研发部", "测试部"},
+	})
+	identity, err := s.authenticate("corp", code, auth.State)
+	require.NoError(t, err)
+	assert.Equal(t, &oidcIdentity{
+		Provider:       "corp",
+		Subject:        "user-001",
+		Name:           "张三",
+		Email:          "zhangsan@example.com",
+		EmailVerified:  true,
+		Mobile:         "13800000001",
+		MobileVerified: true,
+		Department:     "研发部",
+	}, identity)
+
+	// state 只能使用一次
+	code = idp.login(t, auth.AuthorizationURL, map[string]interface{}{"sub": "user-001"})
+	_, err = s.authenticate("corp", code, auth.State)
+	assert.ErrorIs(t, err, errOidcStateInvalid)
+
+	_, err = s.Authorize("unknown")
+	assert.ErrorIs(t, err, errOidcProviderNotFound)
+}
+
+func TestOidcAuthenticate_Rejected(t *testing.T) {
+	idp := newMockIdP(t, "rhpro-task")
+	other := newMockIdP(t, "other-client")
+	useOidcProviders(t, newMockOidcProvider(idp, "corp"), newMockOidcProvider(other, "partner"))
+	s := &OidcService{}
+
+	// state 属于其他身份提供方
+	auth, err := s.Authorize("partner")
+	require.NoError(t, err)
+	_, err = s.authenticate("corp", "any", auth.State)
+	assert.ErrorIs(t, err, errOidcStateInvalid)
+
+	// 篡改的 state
+	_, err = s.authenticate("corp", "any", auth.State+"x")
+	assert.ErrorIs(t, err, errOidcStateInvalid)
+
+	// nonce 与授权请求不一致
+	auth, err = s.Authorize("corp")
+	require.NoError(t, err)
+	code := idp.login(t, auth.AuthorizationURL, map[string]interface{}{"sub": "user-001", "nonce": "replayed"})
+	_, err = s.authenticate("corp", code, auth.State)
+	assert.ErrorIs(t, err, errOidcLoginFailed)
+
+	// audience 不是本系统
+	auth, err = s.Authorize("corp")
+	require.NoError(t, err)
+	code = idp.login(t, auth.AuthorizationURL, map[string]interface{}{"sub": "user-001", "aud": "other-client"})
+	_, err = s.authenticate("corp", code, auth.State)
+	assert.ErrorIs(t, err, errOidcLoginFailed)
+
+	// 授权码来自另一次授权请求（PKCE code_verifier 不匹配）
+	first, err := s.Authorize("corp")
+	require.NoError(t, err)
+	second, err := s.Authorize("corp")
+	require.NoError(t, err)
+	code = idp.login(t, first.AuthorizationURL, map[string]interface{}{"sub": "user-001"})
+	_, err = s.authenticate("corp", code, second.State)
+	assert.ErrorIs(t, err, errOidcLoginFailed)
+}
+
+func TestMapOidcClaims(t *testing.T) {
+	cfg := config.OIDCProviderConfig{
+		Name:            "corp",
+		MobileClaim:     "mobile",
+		EmailClaim:      "email",
+		NameClaim:       "name",
+		DepartmentClaim: "org.department",
+	}
+	claims := map[string]interface{}{
+		"preferred_username": "zhangsan",
+		"email":              "zhangsan@example.com",
+		"email_verified":     "true",
+		"mobile":             "13800000001",
+		"org":                map[string]interface{}{"department": "研发部"},
+	}
+
+	identity := mapOidcClaims(cfg, "sub-1", claims)
+	assert.Equal(t, "zhangsan", identity.Name)
+	assert.True(t, identity.EmailVerified)
+	// 自定义手机号 claim 没有验证标记，默认不用于关联账号
+	assert.Equal(t, "13800000001", identity.Mobile)
+	assert.False(t, identity.MobileVerified)
+	assert.Equal(t, "研发部", identity.Department)
+
+	cfg.TrustUnverifiedContacts = true
+	assert.True(t, mapOidcClaims(cfg, "sub-1", claims).MobileVerified)
+}
+
+func TestNormalizeOidcMobile(t *testing.T) {
+	assert.Equal(t, "13800000001", normalizeOidcMobile("13800000001"))
+	assert.Equal(t, "13800000001", normalizeOidcMobile("+86 138 0000 0001"))
+	assert.Equal(t, "13800000001", normalizeOidcMobile("8613800000001"))
+	assert.Empty(t, normalizeOidcMobile("+1 415 555 0100"))
+	assert.Empty(t, normalizeOidcMobile(""))
+}
+
+func TestOidcBindToken(t *testing.T) {
+	token, err := generateOidcBindToken(&oidcIdentity{Provider: "corp", Subject: "user-001", Mobile: "13800000001", MobileVerified: true})
+	require.NoError(t, err)
+
+	claims, err := parseOidcBindToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "corp", claims.Provider)
+	assert.Equal(t, "user-001", claims.Subject)
+	assert.True(t, claims.MobileVerified)
+
+	_, err = parseOidcBindToken(token + "x")
+	assert.ErrorIs(t, err, errOidcBindTokenInvalid)
+
+	// state 不能当作绑定凭证使用
+	idp := newMockIdP(t, "rhpro-task")
+	useOidcProviders(t, newMockOidcProvider(idp, "corp"))
+	auth, err := (&OidcService{}).Authorize("corp")
+	require.NoError(t, err)
+	_, err = parseOidcBindToken(auth.State)
+	assert.ErrorIs(t, err, errOidcBindTokenInvalid)
+
+	// state 和绑定凭证都不能当作访问令牌使用
+	utils.SetJWTSecret(config.GetConfig().JWT.Secret)
+	_, err = utils.ParseToken(auth.State)
+	assert.Error(t, err)
+	_, err = utils.ParseToken(token)
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocateQuotedText(t *testing.T) {
+	text := "先做需求分析，再做方案设计，最后做方案评审"
+
+	start, end, ok := locateQuotedText(text, "方案", 0)
+	assert.True(t, ok)
+	assert.Equal(t, []int{9, 11}, []int{start, end})
+
+	// 多处匹配时取离原位置最近的一处
+	start, end, ok = locateQuotedText(text, "方案", 18)
+	assert.True(t, ok)
+	assert.Equal(t, []int{17, 19}, []int{start, end})
+
+	_, _, ok = locateQuotedText(text, "测试", 0)
+	assert.False(t, ok)
+	_, _, ok = locateQuotedText(text, "", 0)
+	assert.False(t, ok)
+}
+
+func TestJSONPathValue(t *testing.T) {
+	steps := []byte(`{"steps":[{"name":"设计"},{"name":"开发","tasks":["接口","页面"]}],"owner":"张三"}`)
+
+	value, ok := jsonPathValue(steps, "steps[1].name")
+	assert.True(t, ok)
+	assert.Equal(t, "开发", value)
+
+	value, ok = jsonPathValue(steps, "steps[1].tasks[1]")
+	assert.True(t, ok)
+	assert.Equal(t, "页面", value)
+
+	_, ok = jsonPathValue(steps, "owner")
+	assert.True(t, ok)
+
+	for _, path := range []string{"", "steps[2]", "steps.name", "steps[x]", "steps[1", "a..b", "missing"} {
+		_, ok = jsonPathValue(steps, path)
+		assert.False(t, ok, path)
+	}
+}
+
+func newPlanTarget(id uint, version int, steps string, goals []models.RequirementGoal) *annotationTarget {
+	return &annotationTarget{
+		Type:    models.AnnotationTargetPlan,
+		ID:      id,
+		Version: version,
+		Texts:   map[string]string{"title": "执行计划", "risk_assessment": "主要风险：第三方接口不稳定"},
+		Steps:   []byte(steps),
+		Goals:   goals,
+	}
+}
+
+func TestAnchorNewAnnotation(t *testing.T) {
+	goal := models.RequirementGoal{GoalNo: 1, Title: "完成接口"}
+	goal.ID = 11
+	target := newPlanTarget(1, 1, `{"steps":[{"name":"设计"}]}`, []models.RequirementGoal{goal})
+
+	annotation := &models.ReviewAnnotation{}
+	err := anchorNewAnnotation(annotation, &dto.CreateAnnotationRequest{
+		AnchorType: models.AnnotationAnchorText, Field: "risk_assessment", StartOffset: 5, EndOffset: 10, QuotedText: "第三方接口",
+	}, target)
+	assert.NoError(t, err)
+	assert.Equal(t, "第三方接口", annotation.QuotedText)
+	assert.Equal(t, models.AnnotationAnchored, annotation.AnchorStatus)
+
+	annotation = &models.ReviewAnnotation{}
+	err = anchorNewAnnotation(annotation, &dto.CreateAnnotationRequest{AnchorType: models.AnnotationAnchorStep, StepPath: "steps[0]"}, target)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"设计"}`, annotation.QuotedText)
+
+	annotation = &models.ReviewAnnotation{}
+	err = anchorNewAnnotation(annotation, &dto.CreateAnnotationRequest{AnchorType: models.AnnotationAnchorGoal, GoalID: 11}, target)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(11), *annotation.GoalID)
+	assert.Equal(t, "完成接口", annotation.QuotedText)
+
+	invalid := []dto.CreateAnnotationRequest{
+		{AnchorType: models.AnnotationAnchorText, Field: "content", StartOffset: 0, EndOffset: 1},
+		{AnchorType: models.AnnotationAnchorText, Field: "risk_assessment", StartOffset: 5, EndOffset: 5},
+		{AnchorType: models.AnnotationAnchorText, Field: "risk_assessment", StartOffset: 0, EndOffset: 100},
+		{AnchorType: models.AnnotationAnchorText, Field: "risk_assessment", StartOffset: 5, EndOffset: 10, QuotedText: "数据库"},
+		{AnchorType: models.AnnotationAnchorStep, StepPath: "steps[3]"},
+		{AnchorType: models.AnnotationAnchorGoal, GoalID: 12},
+	}
+	for _, req := range invalid {
+		req := req
+		assert.Error(t, anchorNewAnnotation(&models.ReviewAnnotation{}, &req, target), req)
+	}
+
+	// 方案只能按文本片段批注
+	solution := solutionAnnotationTarget(&models.RequirementSolution{Version: 1, Content: "方案内容"})
+	assert.Error(t, anchorNewAnnotation(&models.ReviewAnnotation{}, &dto.CreateAnnotationRequest{AnchorType: models.AnnotationAnchorStep, StepPath: "steps[0]"}, solution))
+	assert.NoError(t, anchorNewAnnotation(&models.ReviewAnnotation{}, &dto.CreateAnnotationRequest{AnchorType: models.AnnotationAnchorText, Field: "content", StartOffset: 0, EndOffset: 2}, solution))
+}
+
+func TestReanchorAnnotation(t *testing.T) {
+	oldGoal := models.RequirementGoal{GoalNo: 1, Title: "完成接口"}
+	oldGoal.ID = 11
+	newGoals := []models.RequirementGoal{{GoalNo: 1, Title: "编写文档"}, {GoalNo: 2, Title: "完成接口"}}
+	newGoals[0].ID, newGoals[1].ID = 21, 22
+	target := newPlanTarget(2, 2, `{"steps":[{"name":"设计"}]}`, newGoals)
+	target.Texts["risk_assessment"] = "风险评估。主要风险：第三方接口不稳定，需要降级方案"
+
+	// 原文仍存在：重新定位
+	text := &models.ReviewAnnotation{AnchorType: models.AnnotationAnchorText, Field: "risk_assessment", StartOffset: 5, EndOffset: 10, QuotedText: "第三方接口"}
+	reanchorAnnotation(text, nil, target)
+	assert.Equal(t, models.AnnotationAnchored, text.AnchorStatus)
+	assert.Equal(t, []int{10, 15}, []int{text.StartOffset, text.EndOffset})
+	assert.Equal(t, uint(2), text.TargetID)
+	assert.Equal(t, 2, text.Version)
+
+	// 原文已修改：标记为 outdated
+	gone := &models.ReviewAnnotation{AnchorType: models.AnnotationAnchorText, Field: "title", StartOffset: 0, EndOffset: 2, QuotedText: "旧标题"}
+	reanchorAnnotation(gone, nil, target)
+	assert.Equal(t, models.AnnotationOutdated, gone.AnchorStatus)
+
+	step := &models.ReviewAnnotation{AnchorType: models.AnnotationAnchorStep, StepPath: "steps[1]"}
+	reanchorAnnotation(step, nil, target)
+	assert.Equal(t, models.AnnotationOutdated, step.AnchorStatus)
+	step.StepPath = "steps[0].name"
+	reanchorAnnotation(step, nil, target)
+	assert.Equal(t, models.AnnotationAnchored, step.AnchorStatus)
+
+	// 目标按标题匹配到新版本的目标
+	goalID := oldGoal.ID
+	goal := &models.ReviewAnnotation{AnchorType: models.AnnotationAnchorGoal, GoalID: &goalID, GoalNo: 1}
+	reanchorAnnotation(goal, &oldGoal, target)
+	assert.Equal(t, models.AnnotationAnchored, goal.AnchorStatus)
+	assert.Equal(t, uint(22), *goal.GoalID)
+	assert.Equal(t, 2, goal.GoalNo)
+
+	removed := &models.ReviewAnnotation{AnchorType: models.AnnotationAnchorGoal, GoalID: &goalID}
+	reanchorAnnotation(removed, &models.RequirementGoal{GoalNo: 5, Title: "性能优化"}, target)
+	assert.Equal(t, models.AnnotationOutdated, removed.AnchorStatus)
+	assert.Equal(t, uint(11), *removed.GoalID)
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveReviewDeadline(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	cfg := config.TaskConfig{JuryResponseHours: 48, JuryDeadlinePolicy: models.ReviewDeadlinePolicyEscalate}
+
+	// 未指定时按配置的响应时限和默认策略
+	dueAt, policy, err := resolveReviewDeadline("", "", nil, "", cfg, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(48*time.Hour), *dueAt)
+	assert.Equal(t, models.ReviewDeadlinePolicyEscalate, policy)
+
+	// 请求指定优先
+	dueAt, policy, err = resolveReviewDeadline("2026-03-05 18:00:00", models.ReviewDeadlinePolicyAbstain, nil, "", cfg, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 18, dueAt.Hour())
+	assert.Equal(t, 5, dueAt.Day())
+	assert.Equal(t, models.ReviewDeadlinePolicyAbstain, policy)
+
+	// 未指定时保留会话已有的截止时间和策略
+	existing := now.Add(time.Hour)
+	dueAt, policy, err = resolveReviewDeadline("", "", &existing, models.ReviewDeadlinePolicyAbstain, cfg, now)
+	assert.NoError(t, err)
+	assert.Equal(t, existing, *dueAt)
+	assert.Equal(t, models.ReviewDeadlinePolicyAbstain, policy)
+
+	// 截止时间需晚于当前时间
+	_, _, err = resolveReviewDeadline("2026-03-01 00:00:00", "", nil, "", cfg, now)
+	assert.Error(t, err)
+	_, _, err = resolveReviewDeadline("下周五", "", nil, "", cfg, now)
+	assert.Error(t, err)
+
+	// 响应时限为 0 不设截止；无效的默认策略回退为创建人决策
+	dueAt, policy, err = resolveReviewDeadline("", "", nil, "", config.TaskConfig{JuryDeadlinePolicy: "unknown"}, now)
+	assert.NoError(t, err)
+	assert.Nil(t, dueAt)
+	assert.Equal(t, models.ReviewDeadlinePolicyCreatorFinalize, policy)
+}
+
+func TestDeadlineReleasesVoting(t *testing.T) {
+	handledAt := time.Now()
+	session := &models.ReviewSession{DeadlinePolicy: models.ReviewDeadlinePolicyCreatorFinalize}
+	assert.False(t, deadlineReleasesVoting(session))
+
+	session.DeadlineHandledAt = &handledAt
+	assert.True(t, deadlineReleasesVoting(session))
+
+	session.DeadlinePolicy = models.ReviewDeadlinePolicyEscalate
+	assert.True(t, deadlineReleasesVoting(session))
+
+	// 视为弃权后由投票结果自动决策
+	session.DeadlinePolicy = models.ReviewDeadlinePolicyAbstain
+	assert.False(t, deadlineReleasesVoting(session))
+}
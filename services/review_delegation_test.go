@@ -0,0 +1,74 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelegationStatus(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+	rule := &models.ReviewDelegation{StartAt: start, EndAt: start.AddDate(0, 0, 7), IsActive: true}
+
+	assert.Equal(t, delegationStatusUpcoming, delegationStatus(rule, start.Add(-time.Hour)))
+	assert.Equal(t, delegationStatusActive, delegationStatus(rule, start))
+	assert.Equal(t, delegationStatusActive, delegationStatus(rule, start.AddDate(0, 0, 7).Add(-time.Second)))
+	// 结束时间不含
+	assert.Equal(t, delegationStatusExpired, delegationStatus(rule, start.AddDate(0, 0, 7)))
+
+	rule.IsActive = false
+	assert.Equal(t, delegationStatusRevoked, delegationStatus(rule, start))
+}
+
+func TestDelegationCoversTask(t *testing.T) {
+	deptA, deptB := uint(1), uint(2)
+	task := &models.Task{DepartmentID: &deptA, TaskTypeCode: "requirement"}
+
+	assert.True(t, delegationCoversTask(&models.ReviewDelegation{}, task))
+	assert.True(t, delegationCoversTask(&models.ReviewDelegation{DepartmentID: &deptA}, task))
+	assert.False(t, delegationCoversTask(&models.ReviewDelegation{DepartmentID: &deptB}, task))
+	assert.True(t, delegationCoversTask(&models.ReviewDelegation{TaskTypeCode: "requirement"}, task))
+	assert.False(t, delegationCoversTask(&models.ReviewDelegation{TaskTypeCode: "unit_task"}, task))
+
+	// 限定部门的委托不覆盖未归属部门的任务
+	assert.False(t, delegationCoversTask(&models.ReviewDelegation{DepartmentID: &deptA}, &models.Task{TaskTypeCode: "requirement"}))
+}
+
+func TestDelegationsOverlap(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.Local) }
+	deptA, deptB := uint(1), uint(2)
+
+	base := &models.ReviewDelegation{StartAt: day(1), EndAt: day(8)}
+	assert.True(t, delegationsOverlap(base, &models.ReviewDelegation{StartAt: day(5), EndAt: day(10)}))
+	// 首尾相接不算重叠
+	assert.False(t, delegationsOverlap(base, &models.ReviewDelegation{StartAt: day(8), EndAt: day(10)}))
+
+	scopedA := &models.ReviewDelegation{StartAt: day(1), EndAt: day(8), DepartmentID: &deptA}
+	scopedB := &models.ReviewDelegation{StartAt: day(1), EndAt: day(8), DepartmentID: &deptB}
+	assert.False(t, delegationsOverlap(scopedA, scopedB))
+	assert.True(t, delegationsOverlap(scopedA, base))
+
+	typed := &models.ReviewDelegation{StartAt: day(1), EndAt: day(8), DepartmentID: &deptA, TaskTypeCode: "requirement"}
+	assert.True(t, delegationsOverlap(typed, scopedA))
+	assert.False(t, delegationsOverlap(typed, &models.ReviewDelegation{StartAt: day(1), EndAt: day(8), TaskTypeCode: "unit_task"}))
+}
+
+func TestParseDelegationRange(t *testing.T) {
+	start, end, err := parseDelegationRange("2024-05-01", "2024-05-07")
+	assert.NoError(t, err)
+	// 结束日期只有日期时包含当天
+	assert.Equal(t, 7*24*time.Hour, end.Sub(start))
+
+	start, end, err = parseDelegationRange("2024-05-01 09:00:00", "2024-05-01 18:00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, 9*time.Hour, end.Sub(start))
+
+	_, _, err = parseDelegationRange("2024-05-07", "2024-05-01")
+	assert.Error(t, err)
+	_, _, err = parseDelegationRange("2024-05-01 09:00:00", "2024-05-01 09:00:00")
+	assert.Error(t, err)
+	_, _, err = parseDelegationRange("下周一", "2024-05-07")
+	assert.Error(t, err)
+}
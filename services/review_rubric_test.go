@@ -0,0 +1,168 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRubric() *models.ReviewRubric {
+	minScore := 3.5
+	rubric := &models.ReviewRubric{
+		Name:          "方案评审",
+		ReviewType:    "solution_review",
+		ScaleMin:      1,
+		ScaleMax:      5,
+		MinTotalScore: &minScore,
+		Criteria: []models.ReviewRubricCriterion{
+			{Name: "可行性", Weight: 3, SortOrder: 1},
+			{Name: "完整性", Weight: 1, SortOrder: 2},
+		},
+	}
+	rubric.ID = 1
+	rubric.Criteria[0].ID = 11
+	rubric.Criteria[1].ID = 12
+	return rubric
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestRubricReviewType(t *testing.T) {
+	assert.Equal(t, "solution_review", rubricReviewType("solution_review"))
+	assert.Equal(t, "plan_review", rubricReviewType("plan_review"))
+	assert.Equal(t, "plan_review", rubricReviewType("execution_plan_review"))
+	assert.Equal(t, "", rubricReviewType("status_review"))
+}
+
+func TestValidateCriterionScores(t *testing.T) {
+	rubric := testRubric()
+	full := []dto.CriterionScoreItem{
+		{CriterionID: 11, Score: intPtr(4)},
+		{CriterionID: 12, Score: intPtr(5)},
+	}
+	assert.NoError(t, validateCriterionScores(rubric, full, "approve"))
+
+	// 非弃权意见需为每个维度打分，弃权可不打分
+	assert.Error(t, validateCriterionScores(rubric, full[:1], "reject"))
+	assert.NoError(t, validateCriterionScores(rubric, nil, "abstain"))
+
+	// 分值越界、未知维度、重复维度
+	assert.Error(t, validateCriterionScores(rubric, []dto.CriterionScoreItem{
+		{CriterionID: 11, Score: intPtr(6)}, {CriterionID: 12, Score: intPtr(3)},
+	}, "approve"))
+	assert.Error(t, validateCriterionScores(rubric, []dto.CriterionScoreItem{
+		{CriterionID: 99, Score: intPtr(3)},
+	}, "abstain"))
+	assert.Error(t, validateCriterionScores(rubric, []dto.CriterionScoreItem{
+		{CriterionID: 11, Score: intPtr(3)}, {CriterionID: 11, Score: intPtr(3)},
+	}, "abstain"))
+
+	// 未绑定评分标准时不接受维度评分
+	assert.NoError(t, validateCriterionScores(nil, nil, "approve"))
+	assert.Error(t, validateCriterionScores(nil, full, "approve"))
+}
+
+func TestSummarizeRubricScores(t *testing.T) {
+	rubric := testRubric()
+	scores := []models.ReviewCriterionScore{
+		{ReviewRecordID: 1, CriterionID: 11, Score: 5},
+		{ReviewRecordID: 1, CriterionID: 12, Score: 2},
+		{ReviewRecordID: 2, CriterionID: 11, Score: 3},
+		{ReviewRecordID: 2, CriterionID: 12, Score: 2},
+	}
+
+	summary := summarizeRubricScores(rubric, scores)
+	assert.Equal(t, 2, summary.ScoredReviewers)
+	assert.Len(t, summary.Criteria, 2)
+
+	feasibility := summary.Criteria[0]
+	assert.Equal(t, "可行性", feasibility.Name)
+	assert.Equal(t, 2, feasibility.Count)
+	assert.Equal(t, 4.0, feasibility.Average)
+	assert.Equal(t, 3, feasibility.Min)
+	assert.Equal(t, 5, feasibility.Max)
+	assert.Equal(t, 2, feasibility.Spread)
+	assert.Equal(t, 1.0, feasibility.StdDev)
+
+	completeness := summary.Criteria[1]
+	assert.Equal(t, 2.0, completeness.Average)
+	assert.Equal(t, 0, completeness.Spread)
+
+	// (4*3 + 2*1) / 4 = 3.5
+	assert.NotNil(t, summary.WeightedTotal)
+	assert.Equal(t, 3.5, *summary.WeightedTotal)
+	assert.NoError(t, checkRubricMinScore(summary))
+
+	// (3.5*3 + 2*1) / 4 = 3.125，低于最低分 3.5
+	scores[0].Score = 4
+	summary = summarizeRubricScores(rubric, scores)
+	assert.Equal(t, 3.13, *summary.WeightedTotal)
+	assert.Error(t, checkRubricMinScore(summary))
+}
+
+func TestCheckRubricMinScore_NoScores(t *testing.T) {
+	summary := summarizeRubricScores(testRubric(), nil)
+	assert.Nil(t, summary.WeightedTotal)
+	assert.Error(t, checkRubricMinScore(summary))
+
+	// 未配置最低分或未绑定评分标准时不限制
+	summary.MinTotalScore = nil
+	assert.NoError(t, checkRubricMinScore(summary))
+	assert.NoError(t, checkRubricMinScore(nil))
+}
+
+func TestCheckRubricRequest(t *testing.T) {
+	minScore := 6.0
+	req := &dto.ReviewRubricRequest{
+		Name: "计划评审", ReviewType: "plan_review", ScaleMin: 1, ScaleMax: 5,
+		Criteria: []dto.ReviewRubricCriterionItem{{Name: "合理性", Weight: 1}, {Name: "风险", Weight: 1}},
+	}
+	assert.NoError(t, checkRubricRequest(req))
+
+	req.MinTotalScore = &minScore
+	assert.Error(t, checkRubricRequest(req))
+	req.MinTotalScore = nil
+
+	req.Criteria = append(req.Criteria, dto.ReviewRubricCriterionItem{Name: "风险", Weight: 2})
+	assert.Error(t, checkRubricRequest(req))
+}
+
+func TestRubricScoringUnchanged(t *testing.T) {
+	rubric := testRubric()
+	req := &dto.ReviewRubricRequest{
+		ScaleMin: 1, ScaleMax: 5,
+		Criteria: []dto.ReviewRubricCriterionItem{
+			{ID: 11, Name: "可行性（改名）", Weight: 3},
+			{ID: 12, Name: "完整性", Weight: 1},
+		},
+	}
+	assert.True(t, rubricScoringUnchanged(rubric, req))
+
+	req.Criteria[1].Weight = 2
+	assert.False(t, rubricScoringUnchanged(rubric, req))
+	req.Criteria[1].Weight = 1
+
+	req.ScaleMax = 10
+	assert.False(t, rubricScoringUnchanged(rubric, req))
+	req.ScaleMax = 5
+
+	req.Criteria = append(req.Criteria, dto.ReviewRubricCriterionItem{Name: "新增", Weight: 1})
+	assert.False(t, rubricScoringUnchanged(rubric, req))
+}
+
+func TestReviewDeciderIDs(t *testing.T) {
+	task := &models.Task{CreatorID: 7}
+
+	// 单人审核：只有创建人可以打分和决策
+	session := &models.ReviewSession{ReviewMode: models.ReviewModeSingle}
+	assert.Equal(t, []uint{7}, reviewDeciderIDs(session, task))
+
+	// 审批链阶段：本阶段审批人
+	session.ChainStageNo = 2
+	session.StageApproverIDs = []uint{3, 4}
+	assert.Equal(t, []uint{3, 4}, reviewDeciderIDs(session, task))
+}
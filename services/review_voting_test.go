@@ -0,0 +1,95 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func voteRecord(role, opinion string, weight float64) models.ReviewRecord {
+	return models.ReviewRecord{ReviewerRole: role, Opinion: opinion, VoteWeight: weight}
+}
+
+func TestTallyReviewVotes_Threshold(t *testing.T) {
+	records := []models.ReviewRecord{
+		voteRecord(models.ReviewerRoleJury, "approve", 1),
+		voteRecord(models.ReviewerRoleJury, "reject", 1),
+		voteRecord(models.ReviewerRoleJury, "abstain", 1),
+	}
+
+	// 3 票需要 2 票赞成，还有 1 人未投：未决
+	tally := tallyReviewVotes(records, []float64{1}, 2)
+	assert.Equal(t, 1.0, tally.Approve)
+	assert.Equal(t, 1.0, tally.Reject)
+	assert.Equal(t, 1.0, tally.Abstain)
+	assert.Equal(t, 1, tally.PendingVoters)
+	decided, _ := tally.decide()
+	assert.False(t, decided)
+
+	// 最后一票赞成：通过
+	tally = tallyReviewVotes(append(records, voteRecord(models.ReviewerRoleJury, "approve", 1)), nil, 2)
+	decided, approved := tally.decide()
+	assert.True(t, decided)
+	assert.True(t, approved)
+
+	// 最后一票弃权：赞成票已不可能达到要求，驳回
+	tally = tallyReviewVotes(append(records, voteRecord(models.ReviewerRoleJury, "abstain", 1)), nil, 2)
+	decided, approved = tally.decide()
+	assert.True(t, decided)
+	assert.False(t, approved)
+}
+
+func TestTallyReviewVotes_RejectionCertainBeforeAllVotes(t *testing.T) {
+	records := []models.ReviewRecord{
+		voteRecord(models.ReviewerRoleJury, "reject", 1),
+		voteRecord(models.ReviewerRoleJury, "reject", 1),
+	}
+	// 需要 3 票赞成，剩余 2 人全部赞成也不够
+	tally := tallyReviewVotes(records, []float64{1, 1}, 3)
+	decided, approved := tally.decide()
+	assert.True(t, decided)
+	assert.False(t, approved)
+}
+
+func TestTallyReviewVotes_Weighted(t *testing.T) {
+	weights := map[string]float64{
+		models.ReviewerRoleJury:   1,
+		models.ReviewerRoleExpert: 1.5,
+		models.ReviewerRoleLeader: 2,
+	}
+	mode := models.ReviewModeWeighted
+	records := []models.ReviewRecord{
+		voteRecord(models.ReviewerRoleLeader, "approve", reviewVoteWeight(mode, models.ReviewerRoleLeader, weights)),
+		voteRecord(models.ReviewerRoleExpert, "approve", reviewVoteWeight(mode, models.ReviewerRoleExpert, weights)),
+		// 创建人的决策记录不计票
+		voteRecord(models.ReviewerRoleCreator, "reject", 1),
+	}
+
+	tally := tallyReviewVotes(records, []float64{1}, 3)
+	assert.Equal(t, 3.5, tally.Approve)
+	assert.Equal(t, 0.0, tally.Reject)
+	decided, approved := tally.decide()
+	assert.True(t, decided)
+	assert.True(t, approved)
+
+	resp := tally.toResponse()
+	assert.Equal(t, "approved", resp.Outcome)
+	assert.Equal(t, 1.0, resp.PendingWeight)
+}
+
+func TestReviewVoteWeight(t *testing.T) {
+	weights := map[string]float64{models.ReviewerRoleExpert: 1.5}
+	assert.Equal(t, 1.5, reviewVoteWeight(models.ReviewModeWeighted, models.ReviewerRoleExpert, weights))
+	// 未配置的角色权重为 1
+	assert.Equal(t, 1.0, reviewVoteWeight(models.ReviewModeWeighted, models.ReviewerRoleLeader, weights))
+	// 门槛投票每票为 1
+	assert.Equal(t, 1.0, reviewVoteWeight(models.ReviewModeThreshold, models.ReviewerRoleExpert, weights))
+}
+
+func TestResolveReviewerRole(t *testing.T) {
+	leaders := map[uint]bool{7: true}
+	assert.Equal(t, models.ReviewerRoleLeader, resolveReviewerRole(leaders, 7, models.ReviewerRoleJury))
+	assert.Equal(t, models.ReviewerRoleExpert, resolveReviewerRole(leaders, 8, models.ReviewerRoleExpert))
+	assert.Equal(t, models.ReviewerRoleJury, resolveReviewerRole(leaders, 9, ""))
+}
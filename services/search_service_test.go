@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBuildSearchSnippet_Highlight 测试片段高亮和 HTML 转义
+func TestBuildSearchSnippet_Highlight(t *testing.T) {
+	snippet := buildSearchSnippet("优化<b>登录</b>流程，登录失败需提示", "登录", 40)
+	assert.Equal(t, "优化&lt;b&gt;<mark>登录</mark>&lt;/b&gt;流程，<mark>登录</mark>失败需提示", snippet)
+}
+
+// TestBuildSearchSnippet_CaseInsensitive 测试大小写不敏感和多关键词
+func TestBuildSearchSnippet_CaseInsensitive(t *testing.T) {
+	snippet := buildSearchSnippet("Use Redis cache for API", "redis api", 40)
+	assert.Equal(t, "Use <mark>Redis</mark> cache for <mark>API</mark>", snippet)
+}
+
+// TestBuildSearchSnippet_Truncate 测试长文本截取上下文
+func TestBuildSearchSnippet_Truncate(t *testing.T) {
+	body := "aaaaaaaaaaaaaaaaaaaa关键词bbbbbbbbbbbbbbbbbbbb"
+	snippet := buildSearchSnippet(body, "关键词", 5)
+	assert.Equal(t, "…aaaaa<mark>关键词</mark>bbbbbbb…", snippet)
+}
+
+// TestBuildSearchSnippet_NoMatch 测试未直接命中时返回开头
+func TestBuildSearchSnippet_NoMatch(t *testing.T) {
+	snippet := buildSearchSnippet("abcdefghij", "xyz", 2)
+	assert.Equal(t, "abcd…", snippet)
+}
+
+// TestEscapeLikePattern 测试 LIKE 通配符转义
+func TestEscapeLikePattern(t *testing.T) {
+	assert.Equal(t, `100\%\_done\\`, escapeLikePattern(`100%_done\`))
+}
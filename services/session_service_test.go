@@ -0,0 +1,42 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeDevice(t *testing.T) {
+	// 客户端上报的名称优先
+	assert.Equal(t, "张三的笔记本", describeDevice(" 张三的笔记本 ", "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"))
+
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36":                         "Windows / Chrome",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0":           "Windows / Edge",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15":                   "macOS / Safari",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 MicroMessenger/8.0.40":     "iPhone / 微信",
+		"Mozilla/5.0 (Linux; Android 13) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0 Mobile Safari/537.36 MicroMessenger/8.0 miniProgram": "Android / 微信小程序",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0":                                                                  "Linux / Firefox",
+		"curl/8.4.0": "未知设备",
+		"":           "未知设备",
+	}
+	for userAgent, expected := range cases {
+		assert.Equal(t, expected, describeDevice("", userAgent), userAgent)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "abc", truncateRunes("abc", 5))
+	assert.Equal(t, "张三的", truncateRunes("张三的笔记本", 3))
+	assert.Len(t, []rune(truncateRunes(strings.Repeat("设", 600), 500)), 500)
+}
+
+func TestWechatLoginMethod(t *testing.T) {
+	assert.Equal(t, models.LoginMethodWechatOpen, wechatLoginMethod("scan"))
+	assert.Equal(t, models.LoginMethodWechatMP, wechatLoginMethod("mp"))
+	assert.Equal(t, models.LoginMethodWechatH5, wechatLoginMethod("h5"))
+	// 旧的临时凭证没有登录类型，按扫码登录处理
+	assert.Equal(t, models.LoginMethodWechatOpen, wechatLoginMethod(""))
+}
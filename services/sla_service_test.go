@@ -0,0 +1,151 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func newTestCalendar(t *testing.T, holidays ...models.CalendarHoliday) *businessCalendar {
+	cal, err := newBusinessCalendar(&models.BusinessCalendar{
+		TimeZone:  "UTC",
+		WorkStart: "09:00",
+		WorkEnd:   "18:00",
+		WorkDays:  datatypes.NewJSONSlice([]int{1, 2, 3, 4, 5}),
+		Holidays:  holidays,
+	})
+	assert.NoError(t, err)
+	return cal
+}
+
+func TestNewBusinessCalendar_Invalid(t *testing.T) {
+	_, err := newBusinessCalendar(&models.BusinessCalendar{WorkStart: "18:00", WorkEnd: "09:00"})
+	assert.Error(t, err)
+	_, err = newBusinessCalendar(&models.BusinessCalendar{WorkStart: "9点", WorkEnd: "18:00"})
+	assert.Error(t, err)
+	_, err = newBusinessCalendar(&models.BusinessCalendar{WorkStart: "09:00", WorkEnd: "18:00",
+		WorkDays: datatypes.NewJSONSlice([]int{0})})
+	assert.Error(t, err)
+}
+
+func TestBusinessCalendar_Duration(t *testing.T) {
+	cal := newTestCalendar(t)
+	// 2025-03-07 为周五
+	friday := time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 2*time.Hour, cal.BusinessDuration(friday, monday))
+	assert.Equal(t, time.Duration(0), cal.BusinessDuration(monday, friday))
+	// 下班后到次日上班前不计时
+	assert.Equal(t, time.Duration(0), cal.BusinessDuration(
+		time.Date(2025, 3, 10, 19, 0, 0, 0, time.UTC), time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, monday, cal.AddBusinessDuration(friday, 2*time.Hour))
+	// 从非工作时间开始，从下一个上班时间起算
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC),
+		cal.AddBusinessDuration(time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC), 30*time.Minute))
+	// 24 小时工作时间跨越多个工作日
+	assert.Equal(t, time.Date(2025, 3, 12, 15, 0, 0, 0, time.UTC),
+		cal.AddBusinessDuration(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), 24*time.Hour))
+}
+
+func TestBusinessCalendar_Holidays(t *testing.T) {
+	cal := newTestCalendar(t,
+		models.CalendarHoliday{Date: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		models.CalendarHoliday{Date: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), IsWorkday: true},
+	)
+	friday := time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC)
+
+	// 周一放假，顺延到周二
+	assert.Equal(t, time.Date(2025, 3, 11, 10, 0, 0, 0, time.UTC), cal.AddBusinessDuration(friday, 2*time.Hour))
+	// 周六调休上班
+	assert.Equal(t, 9*time.Hour, cal.BusinessDuration(
+		time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestBusinessCalendar_Nil(t *testing.T) {
+	var cal *businessCalendar
+	start := time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 36*time.Hour, cal.BusinessDuration(start, start.Add(36*time.Hour)))
+	assert.Equal(t, start.Add(time.Hour), cal.AddBusinessDuration(start, time.Hour))
+}
+
+func TestBuildStatusSegments(t *testing.T) {
+	created := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	t1 := created.Add(time.Hour)
+	t2 := created.Add(3 * time.Hour)
+	logs := []models.TaskChangeLog{
+		{CreatedAt: t1, OldValue: "req_pending_accept", NewValue: "req_pending_solution"},
+		// 状态未变化的记录被忽略
+		{CreatedAt: t1.Add(time.Minute), OldValue: "req_pending_solution", NewValue: "req_pending_solution"},
+		{CreatedAt: t2, OldValue: "req_pending_solution", NewValue: "req_solution_review"},
+	}
+
+	segments := buildStatusSegments(created, "req_solution_review", logs)
+	assert.Len(t, segments, 3)
+	assert.Equal(t, "req_pending_accept", segments[0].StatusCode)
+	assert.Equal(t, created, segments[0].EnteredAt)
+	assert.Equal(t, t1, *segments[0].LeftAt)
+	assert.Equal(t, "req_pending_solution", segments[1].StatusCode)
+	assert.Equal(t, t2, *segments[1].LeftAt)
+	assert.Equal(t, "req_solution_review", segments[2].StatusCode)
+	assert.Nil(t, segments[2].LeftAt)
+
+	segments = buildStatusSegments(created, "req_pending_accept", nil)
+	assert.Len(t, segments, 1)
+	assert.Equal(t, "req_pending_accept", segments[0].StatusCode)
+}
+
+func TestMatchSLAPolicy(t *testing.T) {
+	four := 4
+	policies := []models.SLAPolicy{
+		{Name: "all", TaskTypeCode: "requirement", StatusCode: "req_pending_accept", IsActive: true, MaxMinutes: 2880},
+		{Name: "p4", TaskTypeCode: "requirement", StatusCode: "req_pending_accept", Priority: &four, IsActive: true, MaxMinutes: 1440},
+		{Name: "off", TaskTypeCode: "requirement", StatusCode: "req_pending_solution", IsActive: false, MaxMinutes: 60},
+	}
+
+	assert.Equal(t, "p4", matchSLAPolicy(policies, "requirement", "req_pending_accept", 4).Name)
+	assert.Equal(t, "all", matchSLAPolicy(policies, "requirement", "req_pending_accept", 2).Name)
+	assert.Nil(t, matchSLAPolicy(policies, "requirement", "req_pending_solution", 4))
+	assert.Nil(t, matchSLAPolicy(policies, "unit_task", "req_pending_accept", 4))
+}
+
+func TestEvaluateSLASegment(t *testing.T) {
+	cal := newTestCalendar(t)
+	policy := &models.SLAPolicy{MaxMinutes: 120}
+	entered := time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC)
+
+	// 周末不计时，周一 09:30 时仍未超时
+	timer := evaluateSLASegment(statusSegment{EnteredAt: entered}, policy, cal, time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC))
+	assert.True(t, timer.Running)
+	assert.False(t, timer.Breached)
+	assert.Equal(t, 90*time.Minute, timer.Elapsed)
+	assert.Equal(t, time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC), timer.DueAt)
+
+	left := time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)
+	timer = evaluateSLASegment(statusSegment{EnteredAt: entered, LeftAt: &left}, policy, cal, time.Now())
+	assert.False(t, timer.Running)
+	assert.True(t, timer.Breached)
+}
+
+func TestSLAComplianceCounter(t *testing.T) {
+	counter := &slaComplianceCounter{}
+	item := counter.toItem(0, "汇总")
+	assert.Equal(t, float64(100), item.ComplianceRate)
+
+	counter.add(&slaTimer{Elapsed: time.Hour})
+	counter.add(&slaTimer{Elapsed: 3 * time.Hour, Breached: true})
+	counter.add(&slaTimer{Elapsed: 2 * time.Hour, Running: true})
+	counter.add(&slaTimer{Elapsed: 2 * time.Hour})
+
+	item = counter.toItem(1, "研发部")
+	assert.Equal(t, 4, item.Total)
+	assert.Equal(t, 2, item.Met)
+	assert.Equal(t, 1, item.Breached)
+	assert.Equal(t, 1, item.Running)
+	assert.Equal(t, 66.67, item.ComplianceRate)
+	assert.Equal(t, float64(120), item.AvgElapsedMinutes)
+}
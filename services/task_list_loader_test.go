@@ -0,0 +1,76 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseTaskFields 测试 fields 参数解析
+func TestParseTaskFields(t *testing.T) {
+	fields, err := parseTaskFields("")
+	assert.NoError(t, err)
+	for _, f := range defaultTaskFields {
+		assert.True(t, fields[f])
+	}
+	assert.False(t, fields[TaskFieldCreator])
+
+	fields, err = parseTaskFields(" Creator, tags ,")
+	assert.NoError(t, err)
+	assert.Equal(t, taskFieldSet{TaskFieldCreator: true, TaskFieldTags: true}, fields)
+
+	_, err = parseTaskFields("creator,owner")
+	var paramErr *TaskListParamError
+	if assert.True(t, errors.As(err, &paramErr)) {
+		assert.Equal(t, "fields", paramErr.Param)
+	}
+}
+
+// TestTaskKeyset_RoundTrip 测试游标生成与解析
+func TestTaskKeyset_RoundTrip(t *testing.T) {
+	keyset, err := newTaskKeyset(&dto.TaskQueryRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "tasks.created_at DESC, tasks.id DESC", keyset.order())
+
+	task := &models.Task{}
+	task.ID = 42
+	task.CreatedAt = time.Date(2026, 3, 10, 15, 30, 0, 123456000, time.UTC)
+
+	condition, args, err := keyset.condition(keyset.encode(task))
+	assert.NoError(t, err)
+	assert.Equal(t, "(tasks.created_at, tasks.id) < (?, ?)", condition)
+	assert.True(t, task.CreatedAt.Equal(args[0].(time.Time)))
+	assert.Equal(t, uint(42), args[1])
+
+	// 升序
+	ascKeyset, err := newTaskKeyset(&dto.TaskQueryRequest{SortBy: "updated_at", SortOrder: "asc"})
+	assert.NoError(t, err)
+	task.UpdatedAt = task.CreatedAt
+	condition, _, err = ascKeyset.condition(ascKeyset.encode(task))
+	assert.NoError(t, err)
+	assert.Equal(t, "(tasks.updated_at, tasks.id) > (?, ?)", condition)
+}
+
+// TestTaskKeyset_Invalid 测试无效游标和不支持的排序
+func TestTaskKeyset_Invalid(t *testing.T) {
+	_, err := newTaskKeyset(&dto.TaskQueryRequest{SortBy: "priority"})
+	assert.Error(t, err)
+
+	keyset, _ := newTaskKeyset(&dto.TaskQueryRequest{})
+	_, _, err = keyset.condition("not-a-cursor!")
+	assert.Error(t, err)
+
+	// 游标与排序方式不匹配
+	ascKeyset, _ := newTaskKeyset(&dto.TaskQueryRequest{SortOrder: "asc"})
+	task := &models.Task{}
+	task.ID = 1
+	_, _, err = keyset.condition(ascKeyset.encode(task))
+	var paramErr *TaskListParamError
+	if assert.True(t, errors.As(err, &paramErr)) {
+		assert.Equal(t, "游标与当前排序方式不匹配", paramErr.Message)
+	}
+}
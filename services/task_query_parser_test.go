@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var queryTestNow = time.Date(2026, 3, 10, 15, 30, 0, 0, time.Local)
+
+// TestCompileTaskQuery_Example 测试典型查询表达式
+func TestCompileTaskQuery_Example(t *testing.T) {
+	sql, args, err := CompileTaskQuery(
+		"status in (req_in_progress, req_blocked) and priority >= 3 and executor = me and due < +7d",
+		42, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"(((tasks.status_code IN ? AND tasks.priority >= ?) AND tasks.executor_id = ?) AND tasks.expected_end_date < ?)",
+		sql)
+	assert.Equal(t, []interface{}{
+		[]string{"req_in_progress", "req_blocked"},
+		3,
+		uint(42),
+		queryTestNow.AddDate(0, 0, 7),
+	}, args)
+}
+
+// TestCompileTaskQuery_Precedence 测试 and 优先于 or 以及括号和 not
+func TestCompileTaskQuery_Precedence(t *testing.T) {
+	sql, _, err := CompileTaskQuery("type = requirement or priority = urgent and not (pool = true)", 1, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, "(tasks.task_type_code = ? OR (tasks.priority = ? AND (NOT tasks.is_in_pool = ?)))", sql)
+}
+
+// TestCompileTaskQuery_NullableNegation 测试可空字段的否定条件包含空值
+func TestCompileTaskQuery_NullableNegation(t *testing.T) {
+	sql, args, err := CompileTaskQuery("executor != me", 7, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, "(tasks.executor_id IS NULL OR tasks.executor_id <> ?)", sql)
+	assert.Equal(t, []interface{}{uint(7)}, args)
+}
+
+// TestCompileTaskQuery_UserByName 测试按用户名匹配
+func TestCompileTaskQuery_UserByName(t *testing.T) {
+	sql, args, err := CompileTaskQuery(`creator in (me, "张三", 5)`, 7, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, "tasks.creator_id IN (?, (SELECT u.id FROM users u WHERE u.username = ? AND u.deleted_at IS NULL LIMIT 1), ?)", sql)
+	assert.Equal(t, []interface{}{uint(7), "张三", uint(5)}, args)
+}
+
+// TestCompileTaskQuery_DepartmentSubtree 测试部门子树查询
+func TestCompileTaskQuery_DepartmentSubtree(t *testing.T) {
+	sql, args, err := CompileTaskQuery("department under 12", 1, queryTestNow)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "WITH RECURSIVE dept_tree")
+	assert.Equal(t, []interface{}{uint(12)}, args)
+}
+
+// TestCompileTaskQuery_Tags 测试标签查询
+func TestCompileTaskQuery_Tags(t *testing.T) {
+	sql, args, err := CompileTaskQuery("tag not in (后端, 性能)", 1, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, "tasks.id NOT IN (SELECT r.task_id FROM task_tag_rel r JOIN task_tags g ON g.id = r.tag_id WHERE g.name IN ? AND g.deleted_at IS NULL)", sql)
+	assert.Equal(t, []interface{}{[]string{"后端", "性能"}}, args)
+}
+
+// TestCompileTaskQuery_WholeDay 测试日期等值比较按整天处理
+func TestCompileTaskQuery_WholeDay(t *testing.T) {
+	sql, args, err := CompileTaskQuery("due = today", 1, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, "(tasks.expected_end_date >= ? AND tasks.expected_end_date < ?)", sql)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, []interface{}{today, today.AddDate(0, 0, 1)}, args)
+
+	sql, args, err = CompileTaskQuery("created <= 2026-03-01", 1, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, "tasks.created_at < ?", sql)
+	assert.Equal(t, []interface{}{time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)}, args)
+}
+
+// TestCompileTaskQuery_TextContains 测试文本包含查询并转义通配符
+func TestCompileTaskQuery_TextContains(t *testing.T) {
+	sql, args, err := CompileTaskQuery(`text ~ "100%"`, 1, queryTestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, "(tasks.title ILIKE ? OR tasks.description ILIKE ?)", sql)
+	assert.Equal(t, []interface{}{`%100\%%`, `%100\%%`}, args)
+}
+
+// TestCompileTaskQuery_Errors 测试语法错误及出错位置
+func TestCompileTaskQuery_Errors(t *testing.T) {
+	cases := []struct {
+		query    string
+		position int
+	}{
+		{"", 1},
+		{"owner = me", 1},
+		{"priority >", 11},
+		{"status in (a, b", 16},
+		{"priority ~ 3", 10},
+		{"status = a status = b", 12},
+		{"due < +7x", 9},
+		{"(status = a", 12},
+		{`title ~ "abc`, 9},
+		{"priority = high and priority = abc", 32},
+		{"pool is empty", 6},
+	}
+
+	for _, tc := range cases {
+		_, _, err := CompileTaskQuery(tc.query, 1, queryTestNow)
+		var queryErr *TaskQueryError
+		if assert.True(t, errors.As(err, &queryErr), "query %q should fail", tc.query) {
+			assert.Equal(t, tc.position, queryErr.Position, "query %q: %s", tc.query, queryErr.Message)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTaskProgress(t *testing.T) {
+	assert.Equal(t, 100, computeTaskProgress(0, 0, models.TaskStatusCategoryDone))
+	assert.Equal(t, 100, computeTaskProgress(4, 1, models.TaskStatusCategoryDone))
+	assert.Equal(t, 33, computeTaskProgress(3, 1, models.TaskStatusCategoryInProgress))
+	assert.Equal(t, 0, computeTaskProgress(0, 0, models.TaskStatusCategoryInProgress))
+}
+
+func TestTaskStatusDateUpdates(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	earlier := now.Add(-24 * time.Hour)
+
+	// 首次进入进行中：记录开始时间
+	updates := taskStatusDateUpdates(&models.Task{}, models.TaskStatusCategoryTodo, models.TaskStatusCategoryInProgress, now)
+	assert.Equal(t, now, updates["actual_start_date"])
+	assert.NotContains(t, updates, "actual_end_date")
+
+	// 再次进入进行中：不覆盖开始时间
+	updates = taskStatusDateUpdates(&models.Task{ActualStartDate: &earlier}, models.TaskStatusCategoryBlocked, models.TaskStatusCategoryInProgress, now)
+	assert.Empty(t, updates)
+
+	// 直接完成：同时记录开始和完成时间
+	updates = taskStatusDateUpdates(&models.Task{}, models.TaskStatusCategoryTodo, models.TaskStatusCategoryDone, now)
+	assert.Equal(t, now, updates["actual_start_date"])
+	assert.Equal(t, now, updates["actual_end_date"])
+
+	// 离开已完成：清空完成时间
+	updates = taskStatusDateUpdates(&models.Task{ActualStartDate: &earlier, ActualEndDate: &earlier}, models.TaskStatusCategoryDone, models.TaskStatusCategoryInProgress, now)
+	assert.Contains(t, updates, "actual_end_date")
+	assert.Nil(t, updates["actual_end_date"])
+}
+
+func TestReconstructTaskDates(t *testing.T) {
+	categories := map[string]string{
+		"todo":    models.TaskStatusCategoryTodo,
+		"doing":   models.TaskStatusCategoryInProgress,
+		"blocked": models.TaskStatusCategoryBlocked,
+		"done":    models.TaskStatusCategoryDone,
+	}
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	logAt := func(hours int, from, to string) models.TaskChangeLog {
+		log := models.TaskChangeLog{OldValue: from, NewValue: to}
+		log.CreatedAt = base.Add(time.Duration(hours) * time.Hour)
+		return log
+	}
+
+	logs := []models.TaskChangeLog{
+		logAt(1, "todo", "doing"),
+		logAt(2, "doing", "blocked"),
+		logAt(3, "blocked", "doing"),
+		logAt(4, "doing", "done"),
+		logAt(5, "done", "doing"),
+		logAt(6, "doing", "done"),
+	}
+	start, end := reconstructTaskDates(logs, categories, models.TaskStatusCategoryDone)
+	assert.Equal(t, base.Add(1*time.Hour), *start)
+	assert.Equal(t, base.Add(6*time.Hour), *end)
+
+	// 当前未完成：没有完成时间
+	start, end = reconstructTaskDates(logs[:5], categories, models.TaskStatusCategoryInProgress)
+	assert.Equal(t, base.Add(1*time.Hour), *start)
+	assert.Nil(t, end)
+
+	// 没有历史记录
+	start, end = reconstructTaskDates(nil, categories, models.TaskStatusCategoryDone)
+	assert.Nil(t, start)
+	assert.Nil(t, end)
+
+	// 被驳回的状态转换申请没有改变任务状态，不能当作开始或完成
+	requested := func(hours int, changeType, from, to string) models.TaskChangeLog {
+		log := logAt(hours, from, to)
+		log.ChangeType = changeType
+		return log
+	}
+	logs = []models.TaskChangeLog{
+		requested(1, "transition_requested", "todo", "doing"),
+		requested(2, "transition_rejected", "todo", "doing"),
+		logAt(3, "todo", "doing"),
+		requested(4, "transition_requested", "doing", "done"),
+		requested(5, "transition_rejected", "doing", "done"),
+	}
+	start, end = reconstructTaskDates(logs, categories, models.TaskStatusCategoryInProgress)
+	assert.Equal(t, base.Add(3*time.Hour), *start)
+	assert.Nil(t, end)
+
+	// 完成后被驳回的重新打开申请不清空完成时间
+	logs = []models.TaskChangeLog{
+		logAt(1, "todo", "doing"),
+		logAt(2, "doing", "done"),
+		requested(3, "transition_requested", "done", "doing"),
+		requested(4, "transition_rejected", "done", "doing"),
+	}
+	start, end = reconstructTaskDates(logs, categories, models.TaskStatusCategoryDone)
+	assert.Equal(t, base.Add(1*time.Hour), *start)
+	assert.Equal(t, base.Add(2*time.Hour), *end)
+}
+
+func TestStatusAllowsSubtasks(t *testing.T) {
+	// 与 workflow_designer.sql 中的默认配置一致
+	planReview := &models.TaskStatus{Code: "req_plan_review", Category: models.TaskStatusCategoryTodo, AllowsSubtasks: false}
+	unitDraft := &models.TaskStatus{Code: "unit_draft", Category: models.TaskStatusCategoryTodo, AllowsSubtasks: true}
+	pendingStart := &models.TaskStatus{Code: "req_pending_start", Category: models.TaskStatusCategoryTodo, AllowsSubtasks: true}
+
+	assert.False(t, statusAllowsSubtasks(planReview), "计划审核中不允许创建子任务")
+	assert.True(t, statusAllowsSubtasks(unitDraft), "最小单元草稿允许创建子任务")
+	assert.True(t, statusAllowsSubtasks(pendingStart))
+
+	// 已完成、已取消分类始终不允许，与配置无关
+	assert.False(t, statusAllowsSubtasks(&models.TaskStatus{Category: models.TaskStatusCategoryDone, AllowsSubtasks: true}))
+	assert.False(t, statusAllowsSubtasks(&models.TaskStatus{Category: models.TaskStatusCategoryCancelled, AllowsSubtasks: true}))
+}
@@ -0,0 +1,52 @@
+package services_test
+
+import (
+	"RHPRo-Task/config"
+	"RHPRo-Task/middlewares"
+	"RHPRo-Task/models"
+	"RHPRo-Task/services"
+	"RHPRo-Task/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// protectedRouter 只挂载认证中间件的受保护路由
+func protectedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	utils.SetJWTSecret(config.GetConfig().JWT.Secret)
+	router := gin.New()
+	router.GET("/protected", middlewares.AuthMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// requestWithToken 携带令牌访问受保护路由
+func requestWithToken(router *gin.Engine, token string) int {
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestTwoFactorTokenRejectedByAuthMiddleware(t *testing.T) {
+	router := protectedRouter()
+
+	token, err := services.GenerateTwoFactorToken(7, "verify", models.LoginMethodPassword, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, requestWithToken(router, token))
+}
+
+func TestOidcBindTokenRejectedByAuthMiddleware(t *testing.T) {
+	router := protectedRouter()
+
+	token, err := services.GenerateOidcBindToken("corp", "user-001")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, requestWithToken(router, token))
+}
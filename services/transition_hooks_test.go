@@ -0,0 +1,95 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestBuiltinTransitionHooksRegistered(t *testing.T) {
+	guardNames := make([]string, 0)
+	for _, guard := range ListTransitionGuards() {
+		guardNames = append(guardNames, guard.Name)
+	}
+	for _, name := range []string{"has_approved_solution", "has_approved_plan", "all_subtasks_done", "no_open_blockers", "has_executor"} {
+		assert.Contains(t, guardNames, name)
+	}
+
+	actionNames := make([]string, 0)
+	for _, action := range ListTransitionActions() {
+		actionNames = append(actionNames, action.Name)
+	}
+	for _, name := range []string{"rollup_parent", "notify", "create_review"} {
+		assert.Contains(t, actionNames, name)
+	}
+}
+
+func TestValidateTransitionHookNames(t *testing.T) {
+	assert.NoError(t, ValidateTransitionHookNames([]string{"has_executor"}, []string{"notify"}))
+	assert.NoError(t, ValidateTransitionHookNames(nil, nil))
+	assert.Error(t, ValidateTransitionHookNames([]string{"unknown_guard"}, nil))
+	assert.Error(t, ValidateTransitionHookNames(nil, []string{"unknown_action"}))
+}
+
+func TestValidateWorkflowGraph_UnknownHook(t *testing.T) {
+	taskType, req := newTestWorkflow()
+	req.Transitions[0].Guards = []string{"unknown_guard"}
+
+	result := ValidateWorkflowGraph(taskType, req)
+	assert.False(t, result.Valid)
+}
+
+func TestRunTransitionGuards(t *testing.T) {
+	ctx := &TransitionContext{
+		Task:       &models.Task{},
+		Transition: &models.TaskStatusTransition{Guards: datatypes.JSONSlice[string]{"has_executor"}},
+	}
+	assert.Error(t, runTransitionGuards(ctx))
+
+	executorID := uint(2)
+	ctx.Task.ExecutorID = &executorID
+	assert.NoError(t, runTransitionGuards(ctx))
+
+	ctx.Transition.Guards = datatypes.JSONSlice[string]{"unknown_guard"}
+	assert.Error(t, runTransitionGuards(ctx))
+}
+
+func TestRunTransitionActions(t *testing.T) {
+	var calls []string
+	RegisterTransitionAction("test_record", "测试动作", func(ctx *TransitionContext) error {
+		calls = append(calls, ctx.ToStatus)
+		return nil
+	})
+	RegisterTransitionAction("test_fail", "测试失败动作", func(ctx *TransitionContext) error {
+		return errors.New("boom")
+	})
+	defer delete(transitionActions, "test_record")
+	defer delete(transitionActions, "test_fail")
+
+	ctx := &TransitionContext{
+		Task:       &models.Task{},
+		ToStatus:   "done",
+		Transition: &models.TaskStatusTransition{PostActions: datatypes.JSONSlice[string]{"test_record"}},
+	}
+	assert.NoError(t, runTransitionActions(ctx))
+	assert.Equal(t, []string{"done"}, calls)
+
+	// 显式配置空列表时不执行任何动作
+	ctx.Transition.PostActions = datatypes.JSONSlice[string]{}
+	assert.NoError(t, runTransitionActions(ctx))
+	assert.Len(t, calls, 1)
+
+	// 未配置（NULL）时执行默认动作
+	defaults := DefaultTransitionActions
+	DefaultTransitionActions = []string{"test_record"}
+	defer func() { DefaultTransitionActions = defaults }()
+	ctx.Transition.PostActions = nil
+	assert.NoError(t, runTransitionActions(ctx))
+	assert.Len(t, calls, 2)
+
+	ctx.Transition.PostActions = datatypes.JSONSlice[string]{"test_fail"}
+	assert.Error(t, runTransitionActions(ctx))
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"RHPRo-Task/models"
+	"RHPRo-Task/ratelimit"
+	"RHPRo-Task/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTOTP(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	now := time.Unix(1700000000, 0)
+	code, err := totp.GenerateCode(secret, now)
+	assert.NoError(t, err)
+
+	step, ok := matchTOTP(secret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/totpPeriod, step)
+
+	// 允许前后各一个时间步的时钟偏差
+	_, ok = matchTOTP(secret, code, now.Add(totpPeriod*time.Second), 0)
+	assert.True(t, ok)
+	_, ok = matchTOTP(secret, code, now.Add(3*totpPeriod*time.Second), 0)
+	assert.False(t, ok)
+
+	// 已使用的时间步不能再次通过
+	_, ok = matchTOTP(secret, code, now, step)
+	assert.False(t, ok)
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	_, ok = matchTOTP(secret, wrong, now, 0)
+	assert.False(t, ok)
+}
+
+func TestRecoveryCode(t *testing.T) {
+	code, err := newRecoveryCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, 11)
+	assert.Equal(t, "-", code[5:6])
+	for _, ch := range normalizeRecoveryCode(code) {
+		assert.True(t, strings.ContainsRune(recoveryCodeAlphabet, ch))
+	}
+
+	// 忽略大小写、空格和连字符
+	assert.Equal(t, "abcdefghjk", normalizeRecoveryCode(" ABCDE-fghjk "))
+}
+
+func TestTwoFactorToken(t *testing.T) {
+	token, err := generateTwoFactorToken(7, twoFactorPurposeVerify, models.LoginMethodPassword, "张三的笔记本")
+	assert.NoError(t, err)
+
+	claims, err := parseTwoFactorToken(token, twoFactorPurposeVerify)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint(7), claims.UID)
+		assert.Equal(t, models.LoginMethodPassword, claims.LoginMethod)
+		assert.Equal(t, "张三的笔记本", claims.Device)
+	}
+
+	// 用途不符
+	_, err = parseTwoFactorToken(token, twoFactorPurposeSetup)
+	assert.Equal(t, errTwoFactorTokenInvalid, err)
+
+	// 完成登录后不能再次使用
+	markTwoFactorTokenUsed(claims)
+	_, err = parseTwoFactorToken(token, twoFactorPurposeVerify)
+	assert.Equal(t, errTwoFactorTokenInvalid, err)
+
+	// 不能当作访问令牌使用（派生密钥签名，访问令牌的密钥无法通过签名校验）
+	_, err = utils.ParseToken(token)
+	assert.Error(t, err)
+}
+
+func TestTwoFactorAttempts(t *testing.T) {
+	userID := uint(4700)
+	defer ratelimit.GetStore().Delete(twoFactorFailKey(userID), twoFactorLockKey(userID))
+
+	for i := 0; i < twoFactorMaxAttempts-1; i++ {
+		recordTwoFactorFailure(userID)
+	}
+	assert.NoError(t, checkTwoFactorAttempts(userID))
+
+	recordTwoFactorFailure(userID)
+	assert.Equal(t, errTwoFactorTooMany, checkTwoFactorAttempts(userID))
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffText(t *testing.T) {
+	unchanged := diffText("content", "a\nb", "a\nb")
+	assert.False(t, unchanged.Changed)
+	assert.Empty(t, unchanged.Lines)
+
+	result := diffText("content", "标题\n第一步\n第二步\n结尾", "标题\n第一步（修改）\n第二步\n补充\n结尾")
+	assert.True(t, result.Changed)
+	assert.Equal(t, 2, result.Added)
+	assert.Equal(t, 1, result.Removed)
+
+	var types []string
+	for _, line := range result.Lines {
+		types = append(types, line.Type)
+	}
+	assert.Equal(t, []string{diffEqual, diffRemoved, diffAdded, diffEqual, diffAdded, diffEqual}, types)
+	assert.Equal(t, dto.LineDiff{Type: diffRemoved, OldLine: 2, Text: "第一步"}, result.Lines[1])
+	assert.Equal(t, dto.LineDiff{Type: diffAdded, NewLine: 4, Text: "补充"}, result.Lines[4])
+	assert.Equal(t, dto.LineDiff{Type: diffEqual, OldLine: 4, NewLine: 5, Text: "结尾"}, result.Lines[5])
+
+	// 从无到有
+	created := diffText("mindmap_markdown", "", "# 根\n## 子")
+	assert.Equal(t, 2, created.Added)
+	assert.Equal(t, 0, created.Removed)
+}
+
+func TestDiffJSON(t *testing.T) {
+	oldSteps := []byte(`{"steps":[{"name":"设计","days":2},{"name":"开发","days":5}],"owner":"张三"}`)
+	newSteps := []byte(`{"steps":[{"name":"设计","days":3},{"name":"开发","days":5},{"name":"测试","days":1}],"note":"新增"}`)
+
+	changes, err := diffJSON(oldSteps, newSteps)
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.JSONChange{
+		{Path: "note", Type: diffAdded, NewValue: "新增"},
+		{Path: "owner", Type: diffRemoved, OldValue: "张三"},
+		{Path: "steps[0].days", Type: diffChanged, OldValue: float64(2), NewValue: float64(3)},
+		{Path: "steps[2]", Type: diffAdded, NewValue: map[string]interface{}{"name": "测试", "days": float64(1)}},
+	}, changes)
+
+	changes, err = diffJSON(nil, oldSteps)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, diffAdded, changes[0].Type)
+
+	_, err = diffJSON([]byte(`{`), newSteps)
+	assert.Error(t, err)
+}
+
+func TestDiffGoals(t *testing.T) {
+	end := time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)
+	newEnd := end.AddDate(0, 0, 7)
+	oldGoals := []models.RequirementGoal{
+		{GoalNo: 1, Title: "完成接口", Priority: 2, EndDate: &end},
+		{GoalNo: 2, Title: "编写文档", Priority: 1},
+		{GoalNo: 3, Title: "性能优化", Priority: 1},
+	}
+	newGoals := []models.RequirementGoal{
+		// 编号变化但标题相同，仍视为同一目标
+		{GoalNo: 2, Title: "完成接口", Priority: 3, EndDate: &newEnd},
+		// 标题修改，按编号匹配
+		{GoalNo: 3, Title: "性能压测", Priority: 1},
+		{GoalNo: 4, Title: "上线发布", Priority: 2},
+	}
+
+	changes := diffGoals(oldGoals, newGoals)
+	assert.Len(t, changes, 4)
+
+	assert.Equal(t, diffChanged, changes[0].Type)
+	assert.Equal(t, 1, changes[0].OldGoalNo)
+	assert.Equal(t, 2, changes[0].NewGoalNo)
+	assert.Equal(t, []dto.FieldChange{
+		{Field: "priority", OldValue: "2", NewValue: "3"},
+		{Field: "end_date", OldValue: "2026-05-01", NewValue: "2026-05-08"},
+	}, changes[0].Changes)
+
+	assert.Equal(t, diffChanged, changes[1].Type)
+	assert.Equal(t, []dto.FieldChange{{Field: "title", OldValue: "性能优化", NewValue: "性能压测"}}, changes[1].Changes)
+
+	assert.Equal(t, dto.GoalChange{Type: diffAdded, NewGoalNo: 4, Title: "上线发布"}, changes[2])
+	assert.Equal(t, dto.GoalChange{Type: diffRemoved, OldGoalNo: 2, Title: "编写文档"}, changes[3])
+
+	assert.Empty(t, diffGoals(oldGoals, oldGoals))
+}
+
+func TestDiffAttachments(t *testing.T) {
+	oldFiles := []dto.AttachmentDetailResult{
+		{ID: 1, FileName: "设计.pdf", FileURL: "/a/1.pdf", FileSize: 100},
+		{ID: 2, FileName: "草图.png", FileURL: "/a/2.png", FileSize: 50},
+		{ID: 3, FileName: "说明.docx", FileURL: "/a/3.docx", FileSize: 10},
+	}
+	newFiles := []dto.AttachmentDetailResult{
+		{ID: 1, FileName: "设计.pdf", FileURL: "/a/1.pdf", FileSize: 100},
+		{ID: 4, FileName: "草图.png", FileURL: "/a/4.png", FileSize: 80},
+		{ID: 5, FileName: "测试报告.xlsx", FileURL: "/a/5.xlsx", FileSize: 20},
+	}
+
+	changes := diffAttachments(oldFiles, newFiles)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, diffChanged, changes[0].Type)
+	assert.Equal(t, uint(2), changes[0].Old.ID)
+	assert.Equal(t, uint(4), changes[0].New.ID)
+	assert.Equal(t, diffAdded, changes[1].Type)
+	assert.Equal(t, "测试报告.xlsx", changes[1].FileName)
+	assert.Equal(t, diffRemoved, changes[2].Type)
+	assert.Equal(t, "说明.docx", changes[2].FileName)
+}
+
+func TestResolveCompareVersions(t *testing.T) {
+	versions := []versionRef{
+		{Version: 1, Status: "rejected"},
+		{Version: 2, Status: "rejected"},
+		{Version: 3, Status: "approved"},
+		{Version: 4, Status: "pending"},
+	}
+
+	// 默认：最新版本对比最近一次被驳回的版本
+	from, to, baseline, err := resolveCompareVersions(versions, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, []int{from, to})
+	assert.Equal(t, compareBaselineLastRejected, baseline)
+
+	// 指定目标版本时在其之前查找
+	from, to, baseline, err = resolveCompareVersions(versions, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, []int{from, to})
+	assert.Equal(t, compareBaselineLastRejected, baseline)
+
+	// 之前没有被驳回的版本时对比上一版本
+	from, _, baseline, err = resolveCompareVersions([]versionRef{{Version: 1, Status: "approved"}, {Version: 2, Status: "pending"}}, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, from)
+	assert.Equal(t, compareBaselinePrevious, baseline)
+
+	from, to, baseline, err = resolveCompareVersions(versions, 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1}, []int{from, to})
+	assert.Equal(t, compareBaselineSpecified, baseline)
+
+	_, _, _, err = resolveCompareVersions(versions, 2, 2)
+	assert.Error(t, err)
+	_, _, _, err = resolveCompareVersions(versions, 5, 0)
+	assert.Error(t, err)
+	_, _, _, err = resolveCompareVersions(versions, 0, 1)
+	assert.Error(t, err)
+	_, _, _, err = resolveCompareVersions(nil, 0, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"RHPRo-Task/dto"
+	"RHPRo-Task/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWorkflow() (*models.TaskType, *dto.WorkflowRequest) {
+	taskType := &models.TaskType{
+		Code:               "bug",
+		PoolStatusCode:     "bug_open",
+		AssignedStatusCode: "bug_assigned",
+		AcceptedStatusCode: "bug_fixing",
+	}
+	req := &dto.WorkflowRequest{
+		Statuses: []dto.WorkflowStatusItem{
+			{Code: "bug_open", Name: "待处理", Category: models.TaskStatusCategoryTodo, IsInitial: true},
+			{Code: "bug_assigned", Name: "已指派", Category: models.TaskStatusCategoryTodo, IsInitial: true},
+			{Code: "bug_fixing", Name: "修复中", Category: models.TaskStatusCategoryInProgress},
+			{Code: "bug_blocked", Name: "受阻", Category: models.TaskStatusCategoryBlocked},
+			{Code: "bug_fixed", Name: "已修复", Category: models.TaskStatusCategoryDone},
+		},
+		Transitions: []dto.WorkflowTransitionItem{
+			{FromStatusCode: "bug_open", ToStatusCode: "bug_assigned", RequiredRole: "creator"},
+			{FromStatusCode: "bug_assigned", ToStatusCode: "bug_fixing", RequiredRole: "executor"},
+			{FromStatusCode: "bug_fixing", ToStatusCode: "bug_blocked", RequiredRole: "executor"},
+			{FromStatusCode: "bug_blocked", ToStatusCode: "bug_fixing", RequiredRole: "executor"},
+			{FromStatusCode: "bug_fixing", ToStatusCode: "bug_fixed", RequiredRole: "executor", RequiresApproval: true},
+		},
+	}
+	return taskType, req
+}
+
+// TestValidateWorkflowGraph_Valid 测试合法流程
+func TestValidateWorkflowGraph_Valid(t *testing.T) {
+	taskType, req := newTestWorkflow()
+	result := ValidateWorkflowGraph(taskType, req)
+	assert.True(t, result.Valid, "errors: %v", result.Errors)
+	assert.Empty(t, result.UnreachableStatuses)
+}
+
+// TestValidateWorkflowGraph_Unreachable 测试不可达状态（含被禁用的转换）
+func TestValidateWorkflowGraph_Unreachable(t *testing.T) {
+	taskType, req := newTestWorkflow()
+	disallowed := false
+	req.Transitions[2].IsAllowed = &disallowed
+
+	result := ValidateWorkflowGraph(taskType, req)
+	assert.False(t, result.Valid)
+	assert.Equal(t, []string{"bug_blocked"}, result.UnreachableStatuses)
+}
+
+// TestValidateWorkflowGraph_Errors 测试结构性错误
+func TestValidateWorkflowGraph_Errors(t *testing.T) {
+	taskType, req := newTestWorkflow()
+	taskType.AcceptedStatusCode = "bug_missing"
+	req.Statuses[4].Category = models.TaskStatusCategoryCancelled
+	req.Statuses[0].IsInitial = false
+	req.Statuses[1].IsInitial = false
+	req.Transitions = append(req.Transitions,
+		dto.WorkflowTransitionItem{FromStatusCode: "bug_open", ToStatusCode: "bug_assigned"},
+		dto.WorkflowTransitionItem{FromStatusCode: "bug_fixed", ToStatusCode: "bug_closed"},
+	)
+
+	result := ValidateWorkflowGraph(taskType, req)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "至少需要一个初始状态")
+	assert.Contains(t, result.Errors, "至少需要一个已完成（done）分类的状态")
+	assert.Contains(t, result.Errors, "任务类型配置的已接受状态 bug_missing 不在状态列表中")
+	assert.Contains(t, result.Errors, "转换规则 bug_open->bug_assigned 重复")
+	assert.Contains(t, result.Errors, "转换规则 bug_fixed->bug_closed 的目标状态不存在")
+	assert.Len(t, result.UnreachableStatuses, 5)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"time"
 
@@ -9,6 +11,9 @@ import (
 
 var jwtSecret = []byte("your-secret-key-change-in-production")
 
+// accessTokenIssuer 访问令牌的签发方（任务专用 Token、两步验证凭证等使用其他签发方）
+const accessTokenIssuer = "RHPRo-Task"
+
 type Claims struct {
 	UserID         uint   `json:"user_id"`
 	Username       string `json:"username"`
@@ -17,22 +22,30 @@ type Claims struct {
 	DepartmentName string `json:"department_name"`
 	IsLeader       bool   `json:"is_leader"`        // 是否为部门负责人
 	ManagedDeptIDs []uint `json:"managed_dept_ids"` // 负责的部门ID列表
+	SessionID      uint   `json:"session_id"`       // 服务端登录会话ID
 	jwt.RegisteredClaims
 }
 
 // GenerateToken 生成JWT Token
 func GenerateToken(userID uint, username string, mobile string, expireHours int) (string, error) {
+	return GenerateAccessToken(userID, username, mobile, 0, "", time.Duration(expireHours)*time.Hour)
+}
+
+// GenerateAccessToken 生成带会话ID和令牌ID（jti）的访问令牌，令牌ID用于注销和吊销检查
+func GenerateAccessToken(userID uint, username string, mobile string, sessionID uint, tokenID string, expire time.Duration) (string, error) {
 	nowTime := time.Now()
-	expireTime := nowTime.Add(time.Duration(expireHours) * time.Hour)
+	expireTime := nowTime.Add(expire)
 
 	claims := Claims{
 		UserID:     userID,
 		Username:   username,
 		UserMobile: mobile,
+		SessionID:  sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(expireTime),
 			IssuedAt:  jwt.NewNumericDate(nowTime),
-			Issuer:    "RHPRo-Task",
+			Issuer:    accessTokenIssuer,
 		},
 	}
 
@@ -40,11 +53,11 @@ func GenerateToken(userID uint, username string, mobile string, expireHours int)
 	return token.SignedString(jwtSecret)
 }
 
-// ParseToken 解析JWT Token
+// ParseToken 解析JWT Token（只接受本系统签发的 HS256 访问令牌）
 func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return jwtSecret, nil
-	})
+	}, jwt.WithIssuer(accessTokenIssuer), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -98,6 +111,14 @@ func ParseTaskToken(tokenString string) (*TaskClaims, error) {
 	return nil, errors.New("invalid task token")
 }
 
+// DeriveSigningKey 由 JWT 密钥派生指定用途的签名密钥
+// 两步验证凭证等临时凭证使用派生密钥签名，即使被当作访问令牌提交也无法通过签名校验
+func DeriveSigningKey(purpose string) []byte {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("RHPRo-Task:" + purpose))
+	return mac.Sum(nil)
+}
+
 // SetJWTSecret 设置JWT密钥
 func SetJWTSecret(secret string) {
 	jwtSecret = []byte(secret)
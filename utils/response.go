@@ -78,3 +78,11 @@ func InternalServerError(c *gin.Context, message string) {
 		Message: message,
 	})
 }
+
+// TooManyRequests 429请求过于频繁
+func TooManyRequests(c *gin.Context, message string) {
+	c.JSON(http.StatusTooManyRequests, Response{
+		Code:    429,
+		Message: message,
+	})
+}